@@ -45,6 +45,7 @@ require (
 	golang.design/x/hotkey v0.4.1
 	golang.org/x/image v0.21.0
 	golang.org/x/sys v0.26.0
+	golang.org/x/text v0.19.0
 	google.golang.org/api v0.204.0
 	howett.net/plist v1.0.1
 )
@@ -90,7 +91,6 @@ require (
 	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/oauth2 v0.23.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
-	golang.org/x/text v0.19.0 // indirect
 	golang.org/x/time v0.7.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20241104194629-dd2ea8efbc28 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241104194629-dd2ea8efbc28 // indirect
@@ -20,13 +20,14 @@ type Manager struct {
 	currentLangCode   LangCode
 	enUsLangJson      string
 	currentLangJson   string
-	pluginLangJsonMap util.HashMap[string, string]
+	pluginLangJsonMap *util.HashMap[string, string]
 }
 
 func GetI18nManager() *Manager {
 	managerOnce.Do(func() {
 		managerInstance = &Manager{
-			currentLangCode: LangCodeEnUs,
+			currentLangCode:   LangCodeEnUs,
+			pluginLangJsonMap: util.NewHashMap[string, string](),
 		}
 		json, _ := resource.GetLangJson(util.NewTraceContext(), string(LangCodeEnUs))
 		managerInstance.enUsLangJson = string(json)
@@ -94,24 +95,62 @@ func (m *Manager) TranslateWoxEnUs(ctx context.Context, key string) string {
 }
 
 func (m *Manager) TranslatePlugin(ctx context.Context, key string, pluginDirectory string) string {
-	cacheKey := fmt.Sprintf("%s:%s", pluginDirectory, m.currentLangCode)
+	langJson, found := m.getPluginLangJson(ctx, pluginDirectory, m.currentLangCode)
+	if !found {
+		return key
+	}
+
+	return m.translatePluginFromJson(ctx, key, langJson)
+}
+
+// TranslatePluginIn resolves key (with or without "i18n:" prefix) of a plugin in the given language, without falling back to other languages.
+// Returns false if the key is missing in that language.
+func (m *Manager) TranslatePluginIn(ctx context.Context, key string, pluginDirectory string, langCode LangCode) (string, bool) {
+	langJson, found := m.getPluginLangJson(ctx, pluginDirectory, langCode)
+	if !found {
+		return key, false
+	}
+
+	result := gjson.Get(langJson, strings.TrimPrefix(key, "i18n:"))
+	if !result.Exists() {
+		return key, false
+	}
+	return result.String(), true
+}
+
+// TranslateWoxIn is like TranslatePluginIn but for Wox (and system plugins) translations
+func (m *Manager) TranslateWoxIn(ctx context.Context, key string, langCode LangCode) (string, bool) {
+	langJson, err := m.GetLangJson(ctx, langCode)
+	if err != nil {
+		return key, false
+	}
+
+	result := gjson.Get(langJson, strings.TrimPrefix(key, "i18n:"))
+	if !result.Exists() {
+		return key, false
+	}
+	return result.String(), true
+}
+
+func (m *Manager) getPluginLangJson(ctx context.Context, pluginDirectory string, langCode LangCode) (string, bool) {
+	cacheKey := fmt.Sprintf("%s:%s", pluginDirectory, langCode)
 	if v, ok := m.pluginLangJsonMap.Load(cacheKey); ok {
-		return m.translatePluginFromJson(ctx, key, v)
+		return v, true
 	}
 
-	jsonPath := path.Join(pluginDirectory, "lang", fmt.Sprintf("%s.json", m.currentLangCode))
+	jsonPath := path.Join(pluginDirectory, "lang", fmt.Sprintf("%s.json", langCode))
 	if _, err := os.Stat(jsonPath); os.IsNotExist(err) {
-		return key
+		return "", false
 	}
 
 	json, err := os.ReadFile(jsonPath)
 	if err != nil {
 		util.GetLogger().Error(ctx, fmt.Sprintf("error reading lang file(%s): %s", jsonPath, err.Error()))
-		return key
+		return "", false
 	}
 
 	m.pluginLangJsonMap.Store(cacheKey, string(json))
-	return m.translatePluginFromJson(ctx, key, string(json))
+	return string(json), true
 }
 
 func (m *Manager) translatePluginFromJson(ctx context.Context, key string, langJson string) string {
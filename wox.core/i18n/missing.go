@@ -0,0 +1,45 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+)
+
+// FindMissingPluginKeys reports which keys can't be resolved in each supported language of the plugin in pluginDirectory.
+// Keys may have "i18n:" prefix, keys without it are not translated by Wox and are ignored.
+// Languages without missing keys are not in the result, so an empty result means all keys resolve.
+//
+// It's meant to be used in plugin test suites, E.g.
+//
+//	missing := i18n.FindMissingPluginKeys(ctx, pluginDirectory, []string{"i18n:title", "i18n:subtitle"})
+//	assert.Empty(t, missing)
+func FindMissingPluginKeys(ctx context.Context, pluginDirectory string, keys []string) map[LangCode][]string {
+	return findMissingKeys(keys, func(key string, langCode LangCode) bool {
+		_, found := GetI18nManager().TranslatePluginIn(ctx, key, pluginDirectory, langCode)
+		return found
+	})
+}
+
+// FindMissingWoxKeys is like FindMissingPluginKeys but for Wox (and system plugins) translations
+func FindMissingWoxKeys(ctx context.Context, keys []string) map[LangCode][]string {
+	return findMissingKeys(keys, func(key string, langCode LangCode) bool {
+		_, found := GetI18nManager().TranslateWoxIn(ctx, key, langCode)
+		return found
+	})
+}
+
+func findMissingKeys(keys []string, resolve func(key string, langCode LangCode) bool) map[LangCode][]string {
+	missing := map[LangCode][]string{}
+	for _, lang := range GetSupportedLanguages() {
+		for _, key := range keys {
+			if !strings.HasPrefix(key, "i18n:") {
+				continue
+			}
+			if !resolve(key, lang.Code) {
+				missing[lang.Code] = append(missing[lang.Code], key)
+			}
+		}
+	}
+
+	return missing
+}
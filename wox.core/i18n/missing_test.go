@@ -0,0 +1,34 @@
+package i18n
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMissingPluginKeys(t *testing.T) {
+	pluginDirectory := t.TempDir()
+	assert.Nil(t, os.MkdirAll(path.Join(pluginDirectory, "lang"), os.ModePerm))
+	writeLang := func(langCode LangCode, json string) {
+		assert.Nil(t, os.WriteFile(path.Join(pluginDirectory, "lang", string(langCode)+".json"), []byte(json), 0644))
+	}
+	writeLang(LangCodeEnUs, `{"title": "Title", "subtitle": "Subtitle"}`)
+	writeLang(LangCodeZhCn, `{"title": "标题"}`)
+	writeLang(LangCodeRuRu, `{"title": "Заголовок", "subtitle": "Подзаголовок"}`)
+
+	ctx := context.Background()
+	translated, found := GetI18nManager().TranslatePluginIn(ctx, "i18n:title", pluginDirectory, LangCodeZhCn)
+	assert.True(t, found)
+	assert.Equal(t, "标题", translated)
+	_, found = GetI18nManager().TranslatePluginIn(ctx, "i18n:subtitle", pluginDirectory, LangCodeZhCn)
+	assert.False(t, found)
+
+	missing := FindMissingPluginKeys(ctx, pluginDirectory, []string{"i18n:title", "i18n:subtitle", "not translated"})
+	assert.Equal(t, map[LangCode][]string{
+		LangCodeZhCn: {"i18n:subtitle"},
+		LangCodePtBr: {"i18n:title", "i18n:subtitle"},
+	}, missing)
+}
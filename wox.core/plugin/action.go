@@ -0,0 +1,322 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"wox/i18n"
+	"wox/setting"
+	"wox/share"
+	"wox/util"
+	"wox/util/clipboard"
+	"wox/util/keyboard"
+	"wox/util/openwith"
+	"wox/util/permission"
+	"wox/util/quicklook"
+	"wox/util/sharesheet"
+	"wox/util/window"
+
+	"github.com/samber/lo"
+)
+
+var allowedUrlSchemes = []string{"http", "https", "ftp", "mailto"}
+
+// NewOpenAction returns an action that opens the given path with the default application of the OS
+func NewOpenAction(path string) QueryResultAction {
+	return QueryResultAction{
+		Name: "i18n:plugin_action_open",
+		Icon: OpenIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if !checkActionPathExist(ctx, path) {
+				return
+			}
+
+			if err := util.ShellOpen(path); err != nil {
+				notifyActionError(ctx, "plugin_action_open_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewRevealAction returns an action that opens the OS file manager (Finder/Explorer/etc.) with the given path selected
+func NewRevealAction(path string) QueryResultAction {
+	return QueryResultAction{
+		Name: "i18n:plugin_action_reveal",
+		Icon: OpenContainingFolderIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if !checkActionPathExist(ctx, path) {
+				return
+			}
+
+			if err := util.ShellOpenFileInFolder(path); err != nil {
+				notifyActionError(ctx, "plugin_action_reveal_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewOpenWithAction returns an action that opens the given path with the given application instead of the default one.
+// appPath is App.Path of an application returned by openwith.GetApps. User is notified if the application can't open the path
+func NewOpenWithAction(path string, appPath string) QueryResultAction {
+	return newOpenWithAction(path, openwith.App{
+		Name: strings.TrimSuffix(filepath.Base(appPath), filepath.Ext(appPath)),
+		Path: appPath,
+	})
+}
+
+// NewOpenWithActions returns one "Open with <app>" action per application, E.g. to offer an "open with" list in action panel.
+// Get apps by openwith.GetApps, it may be slow (E.g. spawns processes on linux), so plugins should cache apps by file type instead of calling it for every result
+func NewOpenWithActions(path string, apps []openwith.App) []QueryResultAction {
+	return lo.Map(apps, func(app openwith.App, _ int) QueryResultAction {
+		return newOpenWithAction(path, app)
+	})
+}
+
+func newOpenWithAction(path string, app openwith.App) QueryResultAction {
+	return QueryResultAction{
+		Name: fmt.Sprintf(i18n.GetI18nManager().TranslateWox(util.NewTraceContext(), "plugin_action_open_with"), app.Name),
+		Icon: OpenIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if !checkActionPathExist(ctx, path) {
+				return
+			}
+
+			if err := openwith.Open(app.Path, path); err != nil {
+				notifyActionError(ctx, "plugin_action_open_with_failed", fmt.Sprintf("%s: %s", app.Name, err.Error()))
+			}
+		},
+	}
+}
+
+// NewQuickLookAction returns an action that previews the given path with the file previewer of the OS (Quick Look on macOS, GNOME Sushi on linux),
+// triggered by cmd+y like in Finder. Wox is kept visible so user can go on browsing results.
+// It does nothing on platforms without a previewer (see quicklook.IsSupported), plugins can check it to omit the action
+func NewQuickLookAction(path string) QueryResultAction {
+	return QueryResultAction{
+		Name:                   "i18n:plugin_action_quick_look",
+		Icon:                   PreviewIcon,
+		Hotkey:                 "cmd+y",
+		PreventHideAfterAction: true,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if !quicklook.IsSupported() {
+				logger.Debug(ctx, "quick look is not supported on this platform")
+				return
+			}
+			if !checkActionPathExist(ctx, path) {
+				return
+			}
+
+			if err := quicklook.Preview(path); err != nil {
+				notifyActionError(ctx, "plugin_action_quick_look_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewFocusOrOpenAction returns an action that focuses the existing window of a running application instead of launching a new instance.
+// pid and bundleId (macOS only) identify the running application, either can be empty (0 or "").
+// If no existing window is found, path is opened with the default application of the OS.
+func NewFocusOrOpenAction(pid int, bundleId string, path string) QueryResultAction {
+	return QueryResultAction{
+		Name: "i18n:plugin_action_open",
+		Icon: OpenIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if pid > 0 && window.ActivateWindowByPid(pid) {
+				return
+			}
+			if bundleId != "" && window.ActivateWindowByBundleId(bundleId) {
+				return
+			}
+
+			if !checkActionPathExist(ctx, path) {
+				return
+			}
+			if err := util.ShellOpen(path); err != nil {
+				notifyActionError(ctx, "plugin_action_open_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewOpenURLAction returns an action that opens the given url in browser.
+// If user has set CustomBrowserPath in wox setting, url will be opened with that browser, otherwise the system default browser is used.
+func NewOpenURLAction(name string, rawUrl string) QueryResultAction {
+	return QueryResultAction{
+		Name: name,
+		Icon: OpenIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if err := validateOpenUrl(rawUrl); err != nil {
+				notifyActionError(ctx, "plugin_action_invalid_url", err.Error())
+				return
+			}
+
+			var openErr error
+			browserPath := setting.GetSettingManager().GetWoxSetting(ctx).CustomBrowserPath.Get()
+			if browserPath != "" {
+				openErr = util.ShellOpenWithApp(browserPath, rawUrl)
+			} else {
+				openErr = util.ShellOpen(rawUrl)
+			}
+			if openErr != nil {
+				notifyActionError(ctx, "plugin_action_open_failed", openErr.Error())
+			}
+		},
+	}
+}
+
+// NewCopyLinkAction returns an action that copies the given url to clipboard
+func NewCopyLinkAction(rawUrl string) QueryResultAction {
+	return QueryResultAction{
+		Name: "i18n:plugin_action_copy_link",
+		Icon: CopyIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if err := clipboard.WriteText(rawUrl); err != nil {
+				notifyActionError(ctx, "plugin_action_copy_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewRunInTerminalAction returns an action that runs the given shell command in a new terminal window.
+// If user has set TerminalCommand in wox setting, that terminal is used, otherwise the default terminal of the OS is used.
+// command is passed to the terminal as one argument, plugins don't need to quote or escape it.
+func NewRunInTerminalAction(name string, command string) QueryResultAction {
+	return QueryResultAction{
+		Name: name,
+		Icon: OpenIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			terminalCommand := setting.GetSettingManager().GetWoxSetting(ctx).TerminalCommand.Get()
+			if err := util.RunInTerminal(terminalCommand, command); err != nil {
+				notifyActionError(ctx, "plugin_action_run_in_terminal_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewPasteAction returns an action that copies text to clipboard and pastes it into the app which was active before Wox was shown.
+// Pasting simulates Cmd/Ctrl+V, so it requires accessibility permission on macOS and is not supported on linux yet.
+// If pasting is not possible, text is still kept in clipboard and user is notified to paste it manually.
+func NewPasteAction(text string) QueryResultAction {
+	return QueryResultAction{
+		Name: "i18n:plugin_action_paste",
+		Icon: CopyIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			if err := clipboard.WriteText(text); err != nil {
+				notifyActionError(ctx, "plugin_action_copy_failed", err.Error())
+				return
+			}
+			if !permission.HasAccessibilityPermission(ctx) {
+				notifyActionError(ctx, "plugin_action_paste_failed", i18n.GetI18nManager().TranslateWox(ctx, "plugin_action_paste_no_accessibility_permission"))
+				return
+			}
+
+			// active window is recorded when Wox is shown
+			var activeWindowPid int
+			if ui := GetPluginManager().GetUI(); ui != nil {
+				activeWindowPid = ui.GetActiveWindowPid()
+			}
+			util.Go(ctx, "paste action", func() {
+				// wait for Wox to hide, otherwise focus goes back to Wox
+				time.Sleep(time.Millisecond * 150)
+				if activeWindowPid > 0 && window.ActivateWindowByPid(activeWindowPid) {
+					time.Sleep(time.Millisecond * 50)
+				}
+				if err := keyboard.SimulatePaste(); err != nil {
+					notifyActionError(ctx, "plugin_action_paste_failed", err.Error())
+				}
+			})
+		},
+	}
+}
+
+// NewShareAction returns an action that shows the share sheet of the OS (E.g. macOS share menu) with payload, see sharesheet.Payload.
+// The share sheet is shown at mouse position after Wox hides, dismissing it shares nothing. Platforms without a share sheet notify user instead.
+func NewShareAction(payload sharesheet.Payload) QueryResultAction {
+	return QueryResultAction{
+		Name: "i18n:plugin_action_share",
+		Icon: ShareIcon,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			for _, filePath := range payload.FilePaths {
+				if !checkActionPathExist(ctx, filePath) {
+					return
+				}
+			}
+
+			if err := sharesheet.Share(payload); err != nil {
+				notifyActionError(ctx, "plugin_action_share_failed", err.Error())
+			}
+		},
+	}
+}
+
+// NewCopyFormatActions returns one "Copy as <format>" action per entry of formats, key is the display name of the format (e.g. "HEX")
+// and value is the text to copy. Actions are sorted by format name so that the order is stable between queries.
+func NewCopyFormatActions(formats map[string]string) []QueryResultAction {
+	copyAsTemplate := i18n.GetI18nManager().TranslateWox(util.NewTraceContext(), "plugin_action_copy_as")
+
+	names := lo.Keys(formats)
+	slices.Sort(names)
+
+	var actions []QueryResultAction
+	for _, format := range names {
+		text := formats[format]
+		actions = append(actions, QueryResultAction{
+			Name: fmt.Sprintf(copyAsTemplate, format),
+			Icon: CopyIcon,
+			Action: func(ctx context.Context, actionContext ActionContext) {
+				clipboard.WriteText(text)
+			},
+		})
+	}
+	return actions
+}
+
+func validateOpenUrl(rawUrl string) error {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return fmt.Errorf("%s: %w", rawUrl, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if !lo.Contains(allowedUrlSchemes, scheme) {
+		return fmt.Errorf("%s: unsupported scheme %q", rawUrl, u.Scheme)
+	}
+	if scheme != "mailto" && u.Host == "" {
+		return fmt.Errorf("%s: missing host", rawUrl)
+	}
+
+	return nil
+}
+
+func checkActionPathExist(ctx context.Context, path string) bool {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			notifyActionError(ctx, "plugin_action_path_not_exist", path)
+		} else {
+			notifyActionError(ctx, "plugin_action_open_failed", err.Error())
+		}
+		return false
+	}
+
+	return true
+}
+
+// notifyActionError shows a user-facing error for a failed action, key is a wox i18n key with one %s placeholder
+func notifyActionError(ctx context.Context, key string, detail string) {
+	msg := fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, key), detail)
+	logger.Error(ctx, fmt.Sprintf("action failed: %s", msg))
+
+	ui := GetPluginManager().GetUI()
+	if ui == nil {
+		return
+	}
+	ui.Notify(ctx, share.NotifyMsg{
+		Icon:           ErrorIcon.String(),
+		Text:           msg,
+		DisplaySeconds: 5,
+	})
+}
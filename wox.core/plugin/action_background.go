@@ -0,0 +1,55 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"wox/setting"
+	"wox/share"
+	"wox/util"
+)
+
+// actions usually open something in another app (E.g. a browser tab), which activates that app a moment after the action returns.
+// Wox waits for this long before taking focus back, otherwise the other app would steal it again
+const backgroundActionRefocusDelay = 300 * time.Millisecond
+
+// getBackgroundActionModifier returns the platform specific background action modifier, empty if it's disabled
+func getBackgroundActionModifier(ctx context.Context) string {
+	modifier := strings.ToLower(strings.TrimSpace(setting.GetSettingManager().GetWoxSetting(ctx).BackgroundActionModifier))
+	if modifier == "" || modifier == setting.BackgroundActionModifierNone {
+		return ""
+	}
+	return toPlatformModifier(modifier)
+}
+
+// ExecuteActionInBackground executes an action without leaving Wox, UI invokes it with the default action of the focused result when user
+// presses Enter with the background action modifier (see WoxSetting.BackgroundActionModifier).
+//
+// Compared to ExecuteAction:
+//   - UI doesn't hide after the action, as if PreventHideAfterAction is set, and keeps the query, results and focused result
+//     regardless of LastQueryMode, because the window is never hidden
+//   - Wox takes focus back after the action, so user can move to the next result and open it the same way
+//
+// Actions which require input or are system actions (E.g. add to favorite) run the same as ExecuteAction, they don't open anything
+func (m *Manager) ExecuteActionInBackground(ctx context.Context, resultId string, actionId string) error {
+	resultCache, action, err := m.loadResultAction(resultId, actionId)
+	if err != nil {
+		return err
+	}
+	if action.Input != nil {
+		return fmt.Errorf("action requires input, it can't run in background: %s", action.Name)
+	}
+
+	if executeErr := m.executeAction(ctx, resultCache, action, resultCache.getActionContext()); executeErr != nil {
+		return executeErr
+	}
+
+	if m.ui != nil && !action.IsSystemAction {
+		util.Go(ctx, "refocus after background action", func() {
+			time.Sleep(backgroundActionRefocusDelay)
+			m.ui.ShowApp(ctx, share.ShowContext{SelectAll: false})
+		})
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wox/i18n"
+	"wox/setting"
+	"wox/share"
+	"wox/util"
+)
+
+var errBatchActionNotAvailable = errors.New("action is not available for this result")
+
+// ExecuteBatchAction runs an action on all results selected by user at once (E.g. delete 5 files), see QueryResultAction.BatchAction.
+// actionId is the id of the action in the first result (the one user opened the action panel for), the same action of other results
+// is found by the id declared by plugin (see hasBatchAction), so all selected results must come from the same plugin.
+//
+// Results which don't have the action and results the action failed on are reported to user by one notification,
+// it only returns an error if the action can't run at all (E.g. it's not batch capable)
+func (m *Manager) ExecuteBatchAction(ctx context.Context, resultIds []string, actionId string) error {
+	if len(resultIds) == 0 {
+		return fmt.Errorf("no result selected")
+	}
+
+	firstCache, action, err := m.loadResultAction(resultIds[0], actionId)
+	if err != nil {
+		return err
+	}
+	if action.BatchAction == nil {
+		return fmt.Errorf("action doesn't support multiple results: %s", action.Name)
+	}
+	if action.Input != nil {
+		return fmt.Errorf("action requires input, it can't run on multiple results: %s", action.Name)
+	}
+	if !action.AllowRepeat && m.actionDedup.isDuplicate(getActionInvocationKey(strings.Join(resultIds, ","), actionId, ""), time.Now()) {
+		logger.Info(ctx, fmt.Sprintf("<%s> drop duplicate invocation of batch action %s", firstCache.PluginInstance.Metadata.Name, action.Name))
+		return nil
+	}
+
+	var resultCaches []*QueryResultCache
+	var actionContexts []ActionContext
+	var failures []error
+	for _, resultId := range resultIds {
+		resultCache, found := m.loadResultCache(resultId)
+		if !found || resultCache.PluginInstance.Metadata.Id != firstCache.PluginInstance.Metadata.Id || !hasBatchAction(resultCache, action) {
+			failures = append(failures, errBatchActionNotAvailable)
+			continue
+		}
+		resultCaches = append(resultCaches, resultCache)
+		actionContexts = append(actionContexts, resultCache.getActionContext())
+	}
+	if len(actionContexts) == 0 {
+		m.notifyBatchActionFailures(ctx, failures, len(resultIds))
+		return fmt.Errorf("action is not available on selected results: %s", action.Name)
+	}
+
+	logger.Info(ctx, fmt.Sprintf("<%s> execute batch action %s on %d results", firstCache.PluginInstance.Metadata.Name, action.Name, len(actionContexts)))
+	errs := action.BatchAction(ctx, actionContexts)
+	if errs != nil && len(errs) != len(actionContexts) {
+		logger.Error(ctx, fmt.Sprintf("<%s> batch action %s returned %d errors for %d results", firstCache.PluginInstance.Metadata.Name, action.Name, len(errs), len(actionContexts)))
+		errs = nil
+	}
+
+	var succeeded []*QueryResultCache
+	for i, resultCache := range resultCaches {
+		if errs != nil && errs[i] != nil {
+			logger.Error(ctx, fmt.Sprintf("<%s> batch action %s failed on %s: %s", firstCache.PluginInstance.Metadata.Name, action.Name, resultCache.ResultTitle, errs[i].Error()))
+			failures = append(failures, errs[i])
+			continue
+		}
+		succeeded = append(succeeded, resultCache)
+	}
+	if len(failures) > 0 {
+		m.notifyBatchActionFailures(ctx, failures, len(resultIds))
+	}
+
+	var actionedResults []actionedResult
+	for _, resultCache := range succeeded {
+		actionedResults = append(actionedResults, newActionedResult(ctx, resultCache))
+	}
+	firstActioned := newActionedResult(ctx, firstCache)
+	util.Go(ctx, fmt.Sprintf("[%s] add actioned results", firstCache.PluginInstance.Metadata.Name), func() {
+		for _, actioned := range actionedResults {
+			setting.GetSettingManager().AddActionedResult(ctx, actioned.pluginInstance.Metadata.Id, actioned.resultTitle, actioned.resultSubTitle)
+			m.addRecentResult(ctx, actioned, action)
+		}
+		m.trackActionInvoked(ctx, firstActioned, action)
+	})
+
+	return nil
+}
+
+// hasBatchAction checks whether result has the same batch action. Actions are matched by the id declared by plugin,
+// different actions may share a name (E.g. translated "Open"), actions without declared id only match the result they belong to
+func hasBatchAction(resultCache *QueryResultCache, action QueryResultAction) bool {
+	item, found := resultCache.Actions.Load(action.Id)
+	return found && item.BatchAction != nil
+}
+
+// notifyBatchActionFailures tells user how many of the selected results failed with the first error, details are logged
+func (m *Manager) notifyBatchActionFailures(ctx context.Context, failures []error, total int) {
+	if m.ui == nil {
+		return
+	}
+
+	msg := fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_action_batch_failed"), len(failures), total, failures[0].Error())
+	m.ui.Notify(ctx, share.NotifyMsg{
+		Icon:           ErrorIcon.String(),
+		Text:           msg,
+		DisplaySeconds: 5,
+	})
+}
+
+// getSingleAction runs a batch action on one result, for actions which only declare BatchAction
+func getSingleAction(batchAction func(ctx context.Context, actionContexts []ActionContext) []error) func(ctx context.Context, actionContext ActionContext) {
+	return func(ctx context.Context, actionContext ActionContext) {
+		errs := batchAction(ctx, []ActionContext{actionContext})
+		if len(errs) > 0 && errs[0] != nil {
+			notifyActionError(ctx, "plugin_action_failed", errs[0].Error())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExecuteBatchAction(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "batch-action-test", Name: "batch action test"})
+
+	var received [][]string
+	deleteAction := func() QueryResultAction {
+		return QueryResultAction{
+			Id:   "delete",
+			Name: "Delete",
+			BatchAction: func(ctx context.Context, actionContexts []ActionContext) []error {
+				var contextData []string
+				errs := make([]error, len(actionContexts))
+				for i, actionContext := range actionContexts {
+					contextData = append(contextData, actionContext.ContextData)
+					if actionContext.ContextData == "locked" {
+						errs[i] = errors.New("file is locked")
+					}
+				}
+				received = append(received, contextData)
+				return errs
+			},
+		}
+	}
+	newResult := func(title string, actions ...QueryResultAction) QueryResultUI {
+		result := m.PolishResult(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "batch"}, QueryResult{
+			Title:       title,
+			ContextData: title,
+			Actions:     actions,
+		})
+		return result.ToUI()
+	}
+
+	a := newResult("a", deleteAction())
+	b := newResult("b", deleteAction())
+	locked := newResult("locked", deleteAction())
+	// same name but no declared id, it's a different action
+	unnamed := newResult("unnamed", QueryResultAction{Name: "Delete", BatchAction: deleteAction().BatchAction})
+	assert.True(t, a.Actions[0].IsBatch)
+	assert.False(t, unnamed.Actions[0].IsBatch)
+
+	assert.Nil(t, m.ExecuteBatchAction(ctx, []string{a.Id, b.Id, locked.Id, unnamed.Id, "missing"}, "delete"))
+	// results without the action are skipped, failures don't stop the others
+	assert.Equal(t, [][]string{{"a", "b", "locked"}}, received)
+
+	// action of a result without declared id only runs on its own result
+	received = nil
+	assert.Nil(t, m.ExecuteBatchAction(ctx, []string{unnamed.Id, a.Id}, unnamed.Actions[0].Id))
+	assert.Equal(t, [][]string{{"unnamed"}}, received)
+
+	plain := newResult("plain", QueryResultAction{Id: "open", Name: "Open", Action: func(ctx context.Context, actionContext ActionContext) {}})
+	assert.ErrorContains(t, m.ExecuteBatchAction(ctx, []string{plain.Id, a.Id}, "open"), "doesn't support multiple results")
+	assert.ErrorContains(t, m.ExecuteBatchAction(ctx, nil, "delete"), "no result selected")
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"wox/util"
+)
+
+type runningAction struct {
+	pluginInstance *Instance
+	actionName     string
+	cancel         context.CancelFunc
+}
+
+func getRunningActionKey(resultId string, actionId string) string {
+	return fmt.Sprintf("%s|%s", resultId, actionId)
+}
+
+// startCancellableAction runs action in background with a cancellable context, see QueryResultAction.Cancellable.
+// Action is registered until it returns (even if it panics), so the same action can't be started twice
+// and a cancelled action is not considered finished before it has cleaned up
+func (m *Manager) startCancellableAction(ctx context.Context, resultCache *QueryResultCache, action QueryResultAction, actionContext ActionContext) error {
+	key := getRunningActionKey(resultCache.ResultId, action.Id)
+	if m.runningActions.Exist(key) {
+		return fmt.Errorf("action is already running: %s", action.Name)
+	}
+
+	// action outlives the websocket request which started it
+	actionCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.runningActions.Store(key, &runningAction{
+		pluginInstance: resultCache.PluginInstance,
+		actionName:     action.Name,
+		cancel:         cancel,
+	})
+
+	util.Go(actionCtx, fmt.Sprintf("[%s] cancellable action", resultCache.PluginInstance.Metadata.Name), func() {
+		defer func() {
+			m.runningActions.Delete(key)
+			cancel()
+		}()
+
+		start := util.GetSystemTimestamp()
+		action.Action(actionCtx, actionContext)
+		if actionCtx.Err() != nil {
+			logger.Info(actionCtx, fmt.Sprintf("[%s] action %s cancelled, cost %d ms", resultCache.PluginInstance.Metadata.Name, action.Name, util.GetSystemTimestamp()-start))
+		}
+	})
+
+	return nil
+}
+
+// CancelAction cancels a running cancellable action. It returns once the action is signalled,
+// the action itself may still be cleaning up until its function returns
+func (m *Manager) CancelAction(ctx context.Context, resultId string, actionId string) error {
+	action, exist := m.runningActions.Load(getRunningActionKey(resultId, actionId))
+	if !exist {
+		return fmt.Errorf("action is not running, result id: %s, action id: %s", resultId, actionId)
+	}
+
+	logger.Info(ctx, fmt.Sprintf("[%s] cancel action: %s", action.pluginInstance.Metadata.Name, action.actionName))
+	action.cancel()
+	return nil
+}
+
+// IsActionRunning returns true if given cancellable action is still running
+func (m *Manager) IsActionRunning(resultId string, actionId string) bool {
+	return m.runningActions.Exist(getRunningActionKey(resultId, actionId))
+}
+
+// cancelPluginActions cancels all running actions of given plugin instance, E.g. when plugin is unloaded
+func (m *Manager) cancelPluginActions(ctx context.Context, pluginInstance *Instance) {
+	actions := m.runningActions.FilterList(func(_ string, action *runningAction) bool {
+		return action.pluginInstance == pluginInstance
+	})
+	for _, action := range actions {
+		logger.Info(ctx, fmt.Sprintf("[%s] plugin unloaded, cancel action: %s", pluginInstance.Metadata.Name, action.actionName))
+		action.cancel()
+	}
+}
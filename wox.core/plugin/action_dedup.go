@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"sync"
+	"time"
+)
+
+// actionDedupWindow is how long an identical action invocation is ignored after the previous one, it's long enough for a double
+// pressed Enter or a double click, but short enough not to block user who really runs the action again
+const actionDedupWindow = 500 * time.Millisecond
+
+// actionDeduplicator drops identical action invocations within actionDedupWindow (E.g. user double pressed Enter and a message
+// would be sent twice), unless the action allows repeats, see QueryResultAction.AllowRepeat. The zero value is ready to use
+type actionDeduplicator struct {
+	lock        sync.Mutex
+	lastInvoked map[string]time.Time // by invocation key, see getActionInvocationKey
+}
+
+// isDuplicate records the invocation and tells whether the same invocation happened within actionDedupWindow before now
+func (d *actionDeduplicator) isDuplicate(key string, now time.Time) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.lastInvoked == nil {
+		d.lastInvoked = map[string]time.Time{}
+	}
+	for invocationKey, invokedAt := range d.lastInvoked {
+		if now.Sub(invokedAt) >= actionDedupWindow {
+			delete(d.lastInvoked, invocationKey)
+		}
+	}
+
+	if _, exist := d.lastInvoked[key]; exist {
+		return true
+	}
+	d.lastInvoked[key] = now
+	return false
+}
+
+// getActionInvocationKey identifies an invocation, the same action of the same result with a different input is not a duplicate
+func getActionInvocationKey(resultId string, actionId string, input string) string {
+	return resultId + "\x00" + actionId + "\x00" + input
+}
@@ -0,0 +1,25 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionDeduplicator(t *testing.T) {
+	var d actionDeduplicator
+	now := time.Now()
+	key := getActionInvocationKey("result", "send", "")
+
+	assert.False(t, d.isDuplicate(key, now))
+	// double pressed Enter
+	assert.True(t, d.isDuplicate(key, now.Add(100*time.Millisecond)))
+	// the window starts from the first invocation, repeated presses don't extend it
+	assert.False(t, d.isDuplicate(key, now.Add(actionDedupWindow)))
+
+	// other results, actions and inputs are not duplicates
+	assert.False(t, d.isDuplicate(getActionInvocationKey("other", "send", ""), now))
+	assert.False(t, d.isDuplicate(getActionInvocationKey("result", "copy", ""), now))
+	assert.False(t, d.isDuplicate(getActionInvocationKey("result", "send", "hello"), now))
+}
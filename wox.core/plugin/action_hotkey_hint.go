@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"wox/i18n"
+	"wox/setting"
+	"wox/util"
+)
+
+var macHotkeyModifierSymbols = map[string]string{
+	"cmd":     "⌘",
+	"command": "⌘",
+	"win":     "⌘",
+	"windows": "⌘",
+	"ctrl":    "⌃",
+	"control": "⌃",
+	"option":  "⌥",
+	"alt":     "⌥",
+	"shift":   "⇧",
+}
+
+var hotkeyModifierNames = map[string]string{
+	"cmd":     "Win",
+	"command": "Win",
+	"win":     "Win",
+	"windows": "Win",
+	"ctrl":    "Ctrl",
+	"control": "Ctrl",
+	"option":  "Alt",
+	"alt":     "Alt",
+	"shift":   "Shift",
+}
+
+// keys with a localized display name, others are shown upper cased (E.g. "k" => "K", "f5" => "F5")
+var localizedHotkeyKeys = map[string]string{
+	"enter":     "hotkey_key_enter",
+	"return":    "hotkey_key_enter",
+	"space":     "hotkey_key_space",
+	"tab":       "hotkey_key_tab",
+	"esc":       "hotkey_key_escape",
+	"escape":    "hotkey_key_escape",
+	"backspace": "hotkey_key_backspace",
+	"delete":    "hotkey_key_delete",
+}
+
+var arrowHotkeyKeys = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// getActionHotkeyHint returns the text UI shows for hotkey of an action, empty if user hides hints (see WoxSetting.HideActionHotkeyHints).
+// Hiding hints doesn't disable hotkeys, UI still matches pressed keys against QueryResultActionUI.Hotkey
+func (m *Manager) getActionHotkeyHint(ctx context.Context, hotkey string) string {
+	if hotkey == "" || setting.GetSettingManager().GetWoxSetting(ctx).HideActionHotkeyHints {
+		return ""
+	}
+
+	return formatActionHotkeyHint(hotkey, util.IsMacOS(), func(key string) string {
+		return i18n.GetI18nManager().TranslateWox(ctx, key)
+	})
+}
+
+// formatActionHotkeyHint normalizes hotkey (E.g. "cmd+shift+c") to the platform convention, modifiers are symbols on macOS ("⌘⇧C")
+// and names on other platforms ("Win+Shift+C"). Named keys like Enter are translated
+func formatActionHotkeyHint(hotkey string, isMacOS bool, translate func(key string) string) string {
+	var parts []string
+	for _, key := range strings.Split(hotkey, "+") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+
+		if isMacOS {
+			if symbol, ok := macHotkeyModifierSymbols[key]; ok {
+				parts = append(parts, symbol)
+				continue
+			}
+		} else if name, ok := hotkeyModifierNames[key]; ok {
+			parts = append(parts, name)
+			continue
+		}
+
+		if i18nKey, ok := localizedHotkeyKeys[key]; ok {
+			parts = append(parts, translate(i18nKey))
+		} else if arrow, ok := arrowHotkeyKeys[key]; ok {
+			parts = append(parts, arrow)
+		} else {
+			parts = append(parts, strings.ToUpper(key))
+		}
+	}
+
+	if isMacOS {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts, "+")
+}
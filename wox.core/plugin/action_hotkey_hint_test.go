@@ -0,0 +1,24 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func translateHotkeyKeyForTest(key string) string {
+	return map[string]string{"hotkey_key_enter": "回车", "hotkey_key_space": "空格"}[key]
+}
+
+func TestFormatActionHotkeyHint_MacOS(t *testing.T) {
+	assert.Equal(t, "⌘⇧C", formatActionHotkeyHint("cmd+shift+c", true, translateHotkeyKeyForTest))
+	assert.Equal(t, "⌘⌥K", formatActionHotkeyHint("win+alt+k", true, translateHotkeyKeyForTest))
+	assert.Equal(t, "⌃回车", formatActionHotkeyHint("ctrl+Enter", true, translateHotkeyKeyForTest))
+}
+
+func TestFormatActionHotkeyHint_OtherPlatforms(t *testing.T) {
+	assert.Equal(t, "Win+Shift+C", formatActionHotkeyHint("cmd+shift+c", false, translateHotkeyKeyForTest))
+	assert.Equal(t, "Ctrl+Alt+F5", formatActionHotkeyHint("ctrl + option + f5", false, translateHotkeyKeyForTest))
+	assert.Equal(t, "Alt+↑", formatActionHotkeyHint("alt+up", false, translateHotkeyKeyForTest))
+	assert.Equal(t, "空格", formatActionHotkeyHint("space", false, translateHotkeyKeyForTest))
+}
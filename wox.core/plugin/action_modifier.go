@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+var activationModifiers = []string{"cmd", "win", "ctrl", "alt", "option", "shift"}
+
+// normalizeActivationModifiers converts activation modifiers to the platform specific name (same as hotkeys) and resolves conflicts:
+//   - the default action is activated by plain Enter, its modifier is dropped
+//   - if several actions claim the same modifier, the first one in action order wins, modifiers of the others are dropped
+//   - the background action modifier (see WoxSetting.BackgroundActionModifier) is reserved, actions claiming it lose their modifier
+//
+// Unknown modifiers are dropped as well.
+func (m *Manager) normalizeActivationModifiers(ctx context.Context, pluginInstance *Instance, actions []QueryResultAction) {
+	var claimed []string
+	if backgroundModifier := getBackgroundActionModifier(ctx); backgroundModifier != "" {
+		claimed = append(claimed, backgroundModifier)
+	}
+	for i := range actions {
+		modifier := strings.ToLower(strings.TrimSpace(actions[i].ActivationModifier))
+		if modifier == "" {
+			continue
+		}
+		actions[i].ActivationModifier = ""
+
+		if !lo.Contains(activationModifiers, modifier) {
+			logger.Warn(ctx, fmt.Sprintf("<%s> action(%s) has unknown activation modifier: %s", pluginInstance.Metadata.Name, actions[i].Name, modifier))
+			continue
+		}
+		if actions[i].IsDefault {
+			logger.Warn(ctx, fmt.Sprintf("<%s> default action(%s) can't have activation modifier", pluginInstance.Metadata.Name, actions[i].Name))
+			continue
+		}
+
+		modifier = toPlatformModifier(modifier)
+		if lo.Contains(claimed, modifier) {
+			logger.Warn(ctx, fmt.Sprintf("<%s> activation modifier %s of action(%s) is already used by another action", pluginInstance.Metadata.Name, modifier, actions[i].Name))
+			continue
+		}
+
+		claimed = append(claimed, modifier)
+		actions[i].ActivationModifier = modifier
+	}
+}
+
+// toPlatformModifier converts a modifier to the platform specific name, E.g. "cmd" to "win" on Windows
+func toPlatformModifier(modifier string) string {
+	if util.IsMacOS() {
+		return strings.NewReplacer("win", "cmd", "alt", "option").Replace(modifier)
+	}
+	return strings.NewReplacer("cmd", "win", "option", "alt").Replace(modifier)
+}
+
+// normalizeInvocationModifiers converts modifiers held when an action was invoked (sent by UI) to platform specific names, see ActionContext.Modifiers.
+// Unknown and duplicate modifiers are dropped, the result is sorted so actions can compare it directly
+func normalizeInvocationModifiers(modifiers []string) []string {
+	var normalized []string
+	for _, modifier := range modifiers {
+		modifier = strings.ToLower(strings.TrimSpace(modifier))
+		if !lo.Contains(activationModifiers, modifier) {
+			continue
+		}
+		modifier = toPlatformModifier(modifier)
+		if !lo.Contains(normalized, modifier) {
+			normalized = append(normalized, modifier)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized
+}
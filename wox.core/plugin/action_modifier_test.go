@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeInvocationModifiers(t *testing.T) {
+	assert.Empty(t, normalizeInvocationModifiers(nil))
+	assert.Equal(t, []string{"ctrl", "shift"}, normalizeInvocationModifiers([]string{"shift", " Ctrl ", "hyper", "shift"}))
+
+	metaModifier, altModifier := "win", "alt"
+	if util.IsMacOS() {
+		metaModifier, altModifier = "cmd", "option"
+	}
+	modifiers := normalizeInvocationModifiers([]string{"cmd", "alt"})
+	assert.ElementsMatch(t, []string{metaModifier, altModifier}, modifiers)
+
+	// modifiers can be checked by any platform name
+	actionContext := ActionContext{Modifiers: modifiers}
+	assert.True(t, actionContext.HasModifier("cmd"))
+	assert.True(t, actionContext.HasModifier("win"))
+	assert.True(t, actionContext.HasModifier("Option"))
+	assert.False(t, actionContext.HasModifier("shift"))
+	assert.False(t, ActionContext{}.HasModifier("shift"))
+}
+
+func TestNormalizeActivationModifiers_BackgroundModifier(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := &Instance{Metadata: Metadata{Id: "activation-modifier-test", Name: "activation modifier test"}}
+	newActions := func() []QueryResultAction {
+		return []QueryResultAction{
+			{Name: "Open", IsDefault: true},
+			{Name: "Open in new window", ActivationModifier: "shift"},
+		}
+	}
+
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	original := woxSetting.BackgroundActionModifier
+	defer func() { woxSetting.BackgroundActionModifier = original }()
+
+	// background action is disabled by default, so plugin actions keep their modifiers
+	woxSetting.BackgroundActionModifier = setting.BackgroundActionModifierNone
+	actions := newActions()
+	m.normalizeActivationModifiers(ctx, instance, actions)
+	assert.Equal(t, "shift", actions[1].ActivationModifier)
+
+	woxSetting.BackgroundActionModifier = "shift"
+	actions = newActions()
+	m.normalizeActivationModifiers(ctx, instance, actions)
+	assert.Empty(t, actions[1].ActivationModifier)
+}
@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"wox/setting"
+
+	"github.com/samber/lo"
+)
+
+// assignStableActionIds gives every action without id an id derived from its declared name, so the same action keeps its id
+// in every result and across refreshes, and user preferences can refer to it (see setting.ActionPreference).
+// It must be called before names are translated
+func assignStableActionIds(actions []QueryResultAction) {
+	usedIds := map[string]bool{}
+	for i := range actions {
+		actions[i].declaredId = actions[i].Id
+		if actions[i].Id != "" {
+			usedIds[actions[i].Id] = true
+		}
+	}
+
+	for i := range actions {
+		if actions[i].Id != "" {
+			continue
+		}
+		baseId := getStableActionId(actions[i].Name)
+		id := baseId
+		for suffix := 2; usedIds[id]; suffix++ {
+			id = fmt.Sprintf("%s-%d", baseId, suffix)
+		}
+		usedIds[id] = true
+		actions[i].Id = id
+	}
+}
+
+// E.g. "i18n:plugin_action_open" => "plugin_action_open"
+func getStableActionId(name string) string {
+	id := strings.TrimPrefix(name, "i18n:")
+	if id == "" {
+		return "action"
+	}
+	return id
+}
+
+// getActionPreference returns the most specific preference of user matching the result, false if there is none
+func getActionPreference(ctx context.Context, pluginId string, kind QueryResultKind) (setting.ActionPreference, bool) {
+	preferences := setting.GetSettingManager().GetWoxSetting(ctx).ActionPreferences
+	for _, candidate := range [][2]string{{pluginId, kind}, {pluginId, ""}, {"", kind}} {
+		if candidate[0] == "" && candidate[1] == "" {
+			continue
+		}
+		if preference, found := lo.Find(preferences, func(item setting.ActionPreference) bool {
+			return item.PluginId == candidate[0] && item.ResultKind == candidate[1]
+		}); found {
+			return preference, true
+		}
+	}
+	return setting.ActionPreference{}, false
+}
+
+// applyActionPreference applies the preference of user to actions of a result, it's applied after plugin declared its default action
+// (IsDefault or QueryResult.DefaultActionId) and before the default action is moved to the first place:
+//   - DefaultAction of user overrides the default of plugin, Enter is moved to it. It's ignored if no action matches
+//   - actions in Order are moved to the front in that order, other actions keep plugin order after them
+//
+// Activation modifiers are resolved afterwards (see normalizeActivationModifiers), so an action picked as default loses its modifier,
+// and if several actions claim the same modifier, the first one in user order wins
+func (m *Manager) applyActionPreference(ctx context.Context, pluginInstance *Instance, kind QueryResultKind, actions []QueryResultAction) {
+	preference, found := getActionPreference(ctx, pluginInstance.Metadata.Id, kind)
+	if !found {
+		return
+	}
+
+	if preference.DefaultAction != "" {
+		if lo.ContainsBy(actions, func(item QueryResultAction) bool { return item.Id == preference.DefaultAction }) {
+			for i := range actions {
+				isDefault := actions[i].Id == preference.DefaultAction
+				if !isDefault && actions[i].IsDefault && strings.EqualFold(actions[i].Hotkey, "Enter") {
+					actions[i].Hotkey = ""
+				}
+				actions[i].IsDefault = isDefault
+			}
+		} else {
+			logger.Debug(ctx, fmt.Sprintf("<%s> preferred default action not found: %s", pluginInstance.Metadata.Name, preference.DefaultAction))
+		}
+	}
+
+	if len(preference.Order) > 0 {
+		slices.SortStableFunc(actions, func(a, b QueryResultAction) int {
+			return getPreferredActionRank(preference.Order, a) - getPreferredActionRank(preference.Order, b)
+		})
+	}
+}
+
+// actions not in order are ranked after all ordered ones
+func getPreferredActionRank(order []string, action QueryResultAction) int {
+	if index := slices.Index(order, action.Id); index >= 0 {
+		return index
+	}
+	return len(order)
+}
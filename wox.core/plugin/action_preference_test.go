@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AssignStableActionIds(t *testing.T) {
+	newActions := func() []QueryResultAction {
+		return []QueryResultAction{
+			{Name: "i18n:plugin_action_open"},
+			{Id: "reveal", Name: "Reveal"},
+			{Name: "i18n:plugin_action_open"},
+			{Name: "Copy path"},
+		}
+	}
+
+	actions := newActions()
+	assignStableActionIds(actions)
+	ids := lo.Map(actions, func(item QueryResultAction, _ int) string { return item.Id })
+	assert.Equal(t, []string{"plugin_action_open", "reveal", "plugin_action_open-2", "Copy path"}, ids)
+	assert.False(t, actions[0].hasDeclaredId())
+	assert.True(t, actions[1].hasDeclaredId())
+
+	// ids must not change between results or refreshes
+	again := newActions()
+	assignStableActionIds(again)
+	assert.Equal(t, ids, lo.Map(again, func(item QueryResultAction, _ int) string { return item.Id }))
+
+	// a derived id never takes an id declared by plugin
+	actions = []QueryResultAction{{Name: "i18n:copy"}, {Id: "copy", Name: "Copy"}}
+	assignStableActionIds(actions)
+	assert.Equal(t, "copy-2", actions[0].Id)
+	assert.Equal(t, "copy", actions[1].Id)
+}
+
+func Test_ApplyActionPreference(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	oldPreferences := woxSetting.ActionPreferences
+	defer func() { woxSetting.ActionPreferences = oldPreferences }()
+	woxSetting.ActionPreferences = []setting.ActionPreference{{
+		PluginId:      "action-preference-test",
+		DefaultAction: "copy_path",
+		Order:         []string{"reveal", "plugin_action_open"},
+	}}
+
+	actions := []QueryResultAction{
+		{Name: "i18n:plugin_action_open", IsDefault: true, Hotkey: "Enter"},
+		{Id: "copy_path", Name: "Copy path"},
+		{Id: "reveal", Name: "Reveal"},
+	}
+	assignStableActionIds(actions)
+
+	instance := &Instance{Metadata: Metadata{Id: "action-preference-test", Name: "action preference test"}}
+	GetPluginManager().applyActionPreference(ctx, instance, "", actions)
+
+	assert.Equal(t, []string{"reveal", "plugin_action_open", "copy_path"}, lo.Map(actions, func(item QueryResultAction, _ int) string { return item.Id }))
+	assert.Equal(t, []bool{false, false, true}, lo.Map(actions, func(item QueryResultAction, _ int) bool { return item.IsDefault }))
+	assert.Equal(t, "", actions[1].Hotkey)
+}
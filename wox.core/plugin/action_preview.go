@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"wox/util"
+)
+
+const actionPreviewTimeout = 5 * time.Second
+
+// actionPreviewRunner makes sure only the preview of the focused action is being computed,
+// starting a new one cancels the previous one
+type actionPreviewRunner struct {
+	lock   sync.Mutex
+	cancel context.CancelFunc
+}
+
+func (r *actionPreviewRunner) start(ctx context.Context) (context.Context, context.CancelFunc) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+	previewCtx, cancel := context.WithTimeout(ctx, actionPreviewTimeout)
+	r.cancel = cancel
+	return previewCtx, cancel
+}
+
+// GetActionPreview computes the preview of an action (see QueryResultAction.Preview), UI calls it when the action is focused.
+// Computed previews are cached in result cache, so focusing the same action again won't compute it again.
+// ctx should be cancelled by caller when UI doesn't need the preview anymore (E.g. http request is aborted).
+func (m *Manager) GetActionPreview(ctx context.Context, resultId string, actionId string) (WoxPreview, error) {
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return WoxPreview{}, fmt.Errorf("result cache not found for result id (get action preview): %s", resultId)
+	}
+	action, exist := resultCache.Actions.Load(actionId)
+	if !exist {
+		return WoxPreview{}, fmt.Errorf("action not found for result id: %s, action id: %s", resultId, actionId)
+	}
+	if action.Preview == nil {
+		return WoxPreview{}, fmt.Errorf("action has no preview: %s", action.Name)
+	}
+
+	actionPreviews := resultCache.ActionPreviews
+	if preview, cached := actionPreviews.Load(actionId); cached {
+		return preview, nil
+	}
+
+	previewCtx, cancel := m.actionPreview.start(ctx)
+	defer cancel()
+
+	previewChan := make(chan WoxPreview, 1)
+	util.Go(previewCtx, fmt.Sprintf("[%s] action(%s) preview", resultCache.PluginInstance.Metadata.Name, action.Name), func() {
+		previewChan <- action.Preview(previewCtx, resultCache.getActionContext())
+	})
+
+	select {
+	case preview := <-previewChan:
+		preview = m.polishPreviewForUI(ctx, resultCache.PluginInstance, preview)
+		actionPreviews.Store(actionId, preview)
+		return preview, nil
+	case <-previewCtx.Done():
+		return WoxPreview{}, fmt.Errorf("preview of action(%s) is cancelled: %w", action.Name, previewCtx.Err())
+	}
+}
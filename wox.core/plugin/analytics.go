@@ -0,0 +1,178 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"wox/setting"
+	"wox/util"
+)
+
+// AnalyticsEventType is the type of AnalyticsEvent, see docs/analytics.md for the schema
+type AnalyticsEventType string
+
+const (
+	AnalyticsEventQueryIssued   AnalyticsEventType = "query_issued"   // user issued a query
+	AnalyticsEventResultShown   AnalyticsEventType = "result_shown"   // results of a plugin are sent to UI, one event per plugin per batch
+	AnalyticsEventActionInvoked AnalyticsEventType = "action_invoked" // user executed an action of a result
+	AnalyticsEventQueryTimeout  AnalyticsEventType = "query_timeout"  // plugin hadn't returned when query timed out, one event per plugin
+)
+
+// AnalyticsEvent is a usage event, it never contains query text or result content
+type AnalyticsEvent struct {
+	Type        AnalyticsEventType
+	Timestamp   int64  // in ms
+	QueryId     string // id of the query assigned by UI, events of the same query share it
+	QueryType   string // input or selection, only for query_issued
+	PluginId    string // plugin of the results or action, for query_issued it's the plugin triggered by keyword (empty for global query)
+	ResultCount int    // only for result_shown
+	ActionName  string // only for action_invoked
+}
+
+// AnalyticsSink receives analytics events, see Manager.SetAnalyticsSink
+type AnalyticsSink interface {
+	Write(ctx context.Context, event AnalyticsEvent) error
+}
+
+// fileAnalyticsSink appends events as json lines to local files, events never leave user's machine
+type fileAnalyticsSink struct {
+	writer *util.Lumberjack
+}
+
+func newFileAnalyticsSink() *fileAnalyticsSink {
+	return &fileAnalyticsSink{
+		writer: &util.Lumberjack{
+			Filename:   path.Join(util.GetLocation().GetAnalyticsDirectory(), "events.jsonl"),
+			LocalTime:  true,
+			MaxSize:    10, // megabytes
+			MaxBackups: 5,
+		},
+	}
+}
+
+func (s *fileAnalyticsSink) Write(ctx context.Context, event AnalyticsEvent) error {
+	data, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	_, writeErr := s.writer.Write(append(data, '\n'))
+	return writeErr
+}
+
+type analytics struct {
+	lock sync.Mutex
+	sink AnalyticsSink
+}
+
+// SetAnalyticsSink replaces the default local file sink, events are only emitted if user enabled analytics in settings
+func (m *Manager) SetAnalyticsSink(sink AnalyticsSink) {
+	m.analytics.lock.Lock()
+	defer m.analytics.lock.Unlock()
+
+	m.analytics.sink = sink
+}
+
+func (m *Manager) emitAnalyticsEvent(ctx context.Context, event AnalyticsEvent) {
+	if !setting.GetSettingManager().GetWoxSetting(ctx).EnableAnalytics {
+		return
+	}
+
+	m.writeAnalyticsEvent(ctx, event)
+}
+
+// writeAnalyticsEvent writes event without checking whether analytics is enabled, callers check it themselves
+func (m *Manager) writeAnalyticsEvent(ctx context.Context, event AnalyticsEvent) {
+	m.analytics.lock.Lock()
+	defer m.analytics.lock.Unlock()
+
+	if m.analytics.sink == nil {
+		m.analytics.sink = newFileAnalyticsSink()
+	}
+
+	event.Timestamp = util.GetSystemTimestamp()
+	if writeErr := m.analytics.sink.Write(ctx, event); writeErr != nil {
+		logger.Warn(ctx, fmt.Sprintf("failed to write analytics event: %s", writeErr.Error()))
+	}
+}
+
+// TrackQueryIssued emits query_issued event, queryPlugin is the plugin triggered by keyword, nil for global query
+func (m *Manager) TrackQueryIssued(ctx context.Context, queryId string, query Query, queryPlugin *Instance) {
+	event := AnalyticsEvent{
+		Type:      AnalyticsEventQueryIssued,
+		QueryId:   queryId,
+		QueryType: query.Type,
+	}
+	if queryPlugin != nil {
+		event.PluginId = queryPlugin.Metadata.Id
+	}
+	m.emitAnalyticsEvent(ctx, event)
+}
+
+func (m *Manager) trackResultsShown(ctx context.Context, queryId string, results []QueryResultUI) {
+	if !setting.GetSettingManager().GetWoxSetting(ctx).EnableAnalytics {
+		return
+	}
+
+	var pluginIds []string
+	counts := map[string]int{}
+	for _, result := range results {
+		resultCache, found := m.resultCache.Load(result.Id)
+		if !found {
+			continue
+		}
+		pluginId := resultCache.PluginInstance.Metadata.Id
+		if _, exist := counts[pluginId]; !exist {
+			pluginIds = append(pluginIds, pluginId)
+		}
+		counts[pluginId]++
+	}
+
+	for _, pluginId := range pluginIds {
+		m.emitAnalyticsEvent(ctx, AnalyticsEvent{
+			Type:        AnalyticsEventResultShown,
+			QueryId:     queryId,
+			PluginId:    pluginId,
+			ResultCount: counts[pluginId],
+		})
+	}
+}
+
+// notifyResultsShown calls QueryResult.OnShown of results which are shown for the first time in current query.
+// Like other analytics, callbacks are only called if user enabled analytics in settings
+func (m *Manager) notifyResultsShown(ctx context.Context, results []QueryResultUI) {
+	if !setting.GetSettingManager().GetWoxSetting(ctx).EnableAnalytics {
+		return
+	}
+
+	for _, result := range results {
+		resultCache, found := m.resultCache.Load(result.Id)
+		if !found || resultCache.OnShown == nil {
+			continue
+		}
+
+		onShown := resultCache.OnShown
+		util.Go(ctx, fmt.Sprintf("[%s] result shown callback", resultCache.PluginInstance.Metadata.Name), func() {
+			onShown(ctx)
+		})
+	}
+}
+
+func (m *Manager) trackActionInvoked(ctx context.Context, actioned actionedResult, action QueryResultAction) {
+	if !actioned.enableAnalytics {
+		return
+	}
+
+	m.shownResults.lock.Lock()
+	queryId := m.shownResults.queryId
+	m.shownResults.lock.Unlock()
+
+	m.writeAnalyticsEvent(ctx, AnalyticsEvent{
+		Type:       AnalyticsEventActionInvoked,
+		QueryId:    queryId,
+		PluginId:   actioned.pluginInstance.Metadata.Id,
+		ActionName: action.Name,
+	})
+}
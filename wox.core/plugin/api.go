@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"time"
 	"wox/ai"
 	"wox/i18n"
 	"wox/setting"
 	"wox/share"
 	"wox/util"
+	"wox/util/selection"
 
 	"github.com/disintegration/imaging"
 	"github.com/samber/lo"
@@ -30,15 +32,63 @@ type API interface {
 	HideApp(ctx context.Context)
 	ShowApp(ctx context.Context)
 	Notify(ctx context.Context, description string)
+	// SetStatus shows a status line at the bottom of Wox window until ClearStatus is called, text support i18n. See share.StatusMsg
+	SetStatus(ctx context.Context, text string)
+	ClearStatus(ctx context.Context)
 	Log(ctx context.Context, level LogLevel, msg string)
 	GetTranslation(ctx context.Context, key string) string
+	// FormatRelativeTime formats t relative to now in the language of Wox (E.g. "5m ago", "in 2h", "just now"), useful for subtitles.
+	// Empty string is returned for zero t, see util.FormatRelativeTime
+	FormatRelativeTime(ctx context.Context, t time.Time) string
 	GetSetting(ctx context.Context, key string) string
 	SaveSetting(ctx context.Context, key string, value string, isPlatformSpecific bool)
 	OnSettingChanged(ctx context.Context, callback func(key string, value string))
 	OnGetDynamicSetting(ctx context.Context, callback func(key string) string)
 	OnDeepLink(ctx context.Context, callback func(arguments map[string]string))
 	OnUnload(ctx context.Context, callback func())
+	OnQuerySessionStart(ctx context.Context, callback func())
+	OnQuerySessionEnd(ctx context.Context, callback func())
+	// OnStartup registers callback to warm up the plugin (E.g. build an index, authenticate) before the first query.
+	// It's called in background after plugin is initialized with a timeout, errors are logged, see Instance.GetWarmUpStatus
+	OnStartup(ctx context.Context, callback func(ctx context.Context) error)
 	RegisterQueryCommands(ctx context.Context, commands []MetadataCommand)
+	RegisterSelectionHandler(ctx context.Context, handler SelectionHandler)
+	RegisterStaticResults(ctx context.Context, results []StaticResult)
+	RegisterSuggestionProvider(ctx context.Context, provider SuggestionProvider)
+	RegisterPreviewEnricher(ctx context.Context, enricher PreviewEnricher)
+	// RegisterQueryVariable registers a custom variable (E.g. "myplugin:project" for "{myplugin:project}") which is replaced by the value
+	// returned by resolver in query hotkeys, see QueryVariableResolver. If several plugins register the same variable, the first one wins
+	RegisterQueryVariable(ctx context.Context, name string, resolver QueryVariableResolver)
+	// ReportInitError tells Wox plugin can't work (E.g. bad config, missing dependency), call it in Init or later. Plugin is skipped by global queries
+	// and queries triggered by its keyword show the error with an action to open plugin settings. nil clears the error once plugin recovered,
+	// it's also cleared when plugin is initialized again
+	ReportInitError(ctx context.Context, err error)
+	// RegisterHeadlessAction registers a named action which runs without a query (E.g. "toggle-dark-mode"), invoked by a query hotkey
+	// or /action/headless endpoint as "<plugin id or name>:<action name>", see HeadlessAction
+	RegisterHeadlessAction(ctx context.Context, action HeadlessAction)
+	UpdateResult(ctx context.Context, resultId string, result RefreshableResult) bool
+	// UpdateResultScore changes score of a result returned in current query without rebuilding it, UI re-sorts the results.
+	// Returns false if the update is dropped because query has changed, see Manager.UpdateResultScore
+	UpdateResultScore(ctx context.Context, resultId string, score int64) bool
+	ExecuteUIBatch(ctx context.Context, commands []share.UICommand) error
+	GetStore(ctx context.Context) *KVStore
+	GetSecrets(ctx context.Context) *SecretStore
+	GetHTTPClient(ctx context.Context) *util.RateLimitedHTTPClient
+	RequestPermission(ctx context.Context, permission MetadataPermission) error
+	SendToPlugin(ctx context.Context, targetPluginId string, payload selection.Selection, contextData string) error
+	CarryContext(ctx context.Context, contextData string)
+	// ReportQueryProgress shows progress of the running query in UI before results arrive (E.g. "Searching… 40%"), text support i18n.
+	// Progress is 0-100, negative means indeterminate. Updates are rate limited, see QueryProgress
+	ReportQueryProgress(ctx context.Context, progress int, text string)
+	// PickFile opens the native file picker and returns paths of chosen files, it returns empty paths and no error if user cancelled.
+	// Error is returned if the picker can't be opened (E.g. UI is not connected)
+	PickFile(ctx context.Context, options PickFileOptions) ([]string, error)
+	// PickFolder opens the native folder picker and returns path of the chosen folder, empty if user cancelled
+	PickFolder(ctx context.Context, options PickFolderOptions) (string, error)
+	// ShowDialog shows a modal form (E.g. OAuth consent, multi-field setup) and waits until user submits it, texts support i18n.
+	// Responses are values of fields by key plus the pressed button by share.DialogButtonResponseKey.
+	// share.ErrDialogCancelled is returned if user cancelled, share.ErrDialogTimeout if user didn't respond in time
+	ShowDialog(ctx context.Context, spec share.DialogSpec) (map[string]string, error)
 	AIChatStream(ctx context.Context, model ai.Model, conversations []ai.Conversation, callback ai.ChatStreamFunc) error
 }
 
@@ -69,6 +119,17 @@ func (a *APIImpl) Notify(ctx context.Context, message string) {
 	})
 }
 
+func (a *APIImpl) SetStatus(ctx context.Context, text string) {
+	GetPluginManager().GetUI().SetStatus(ctx, share.StatusMsg{
+		PluginId: a.pluginInstance.Metadata.Id,
+		Text:     a.GetTranslation(ctx, text),
+	})
+}
+
+func (a *APIImpl) ClearStatus(ctx context.Context) {
+	GetPluginManager().GetUI().ClearStatus(ctx, a.pluginInstance.Metadata.Id)
+}
+
 func (a *APIImpl) Log(ctx context.Context, level LogLevel, msg string) {
 	logCtx := util.NewComponentContext(ctx, a.pluginInstance.Metadata.Name)
 	if level == LogLevelError {
@@ -104,6 +165,12 @@ func (a *APIImpl) GetTranslation(ctx context.Context, key string) string {
 	}
 }
 
+func (a *APIImpl) FormatRelativeTime(ctx context.Context, t time.Time) string {
+	return util.FormatRelativeTime(t, util.GetSystemTime(), func(key string) string {
+		return i18n.GetI18nManager().TranslateWox(ctx, key)
+	})
+}
+
 func (a *APIImpl) GetSetting(ctx context.Context, key string) string {
 	// try to get platform specific setting first
 	platformSpecificKey := key + "@" + util.GetCurrentPlatform()
@@ -164,6 +231,18 @@ func (a *APIImpl) OnUnload(ctx context.Context, callback func()) {
 	a.pluginInstance.UnloadCallbacks = append(a.pluginInstance.UnloadCallbacks, callback)
 }
 
+func (a *APIImpl) OnStartup(ctx context.Context, callback func(ctx context.Context) error) {
+	a.pluginInstance.StartupCallbacks = append(a.pluginInstance.StartupCallbacks, callback)
+}
+
+func (a *APIImpl) OnQuerySessionStart(ctx context.Context, callback func()) {
+	a.pluginInstance.QuerySessionStartCallbacks = append(a.pluginInstance.QuerySessionStartCallbacks, callback)
+}
+
+func (a *APIImpl) OnQuerySessionEnd(ctx context.Context, callback func()) {
+	a.pluginInstance.QuerySessionEndCallbacks = append(a.pluginInstance.QuerySessionEndCallbacks, callback)
+}
+
 func (a *APIImpl) RegisterQueryCommands(ctx context.Context, commands []MetadataCommand) {
 	a.pluginInstance.Setting.QueryCommands = lo.Map(commands, func(command MetadataCommand, _ int) setting.PluginQueryCommand {
 		return setting.PluginQueryCommand{
@@ -174,6 +253,184 @@ func (a *APIImpl) RegisterQueryCommands(ctx context.Context, commands []Metadata
 	a.pluginInstance.SaveSetting(ctx)
 }
 
+// RegisterStaticResults replaces the static results of the plugin, changes take effect from the next query. See StaticResult
+func (a *APIImpl) RegisterStaticResults(ctx context.Context, results []StaticResult) {
+	staticResults := append([]StaticResult(nil), results...)
+	a.pluginInstance.staticResults.Store(&staticResults)
+}
+
+func (a *APIImpl) RegisterSelectionHandler(ctx context.Context, handler SelectionHandler) {
+	if handler.Handler == nil {
+		a.Log(ctx, LogLevelError, "selection handler must have a handler function")
+		return
+	}
+
+	a.pluginInstance.SelectionHandlers = append(a.pluginInstance.SelectionHandlers, handler)
+}
+
+// RegisterSuggestionProvider adds a provider of query suggestions, see SuggestionProvider
+func (a *APIImpl) RegisterSuggestionProvider(ctx context.Context, provider SuggestionProvider) {
+	if provider == nil {
+		a.Log(ctx, LogLevelError, "suggestion provider must not be nil")
+		return
+	}
+
+	a.pluginInstance.SuggestionProviders = append(a.pluginInstance.SuggestionProviders, provider)
+}
+
+// RegisterPreviewEnricher adds an enricher which appends content to the preview of focused results, see PreviewEnricher
+func (a *APIImpl) RegisterPreviewEnricher(ctx context.Context, enricher PreviewEnricher) {
+	if enricher.Enrich == nil {
+		a.Log(ctx, LogLevelError, "preview enricher must have an enrich function")
+		return
+	}
+
+	a.pluginInstance.PreviewEnrichers = append(a.pluginInstance.PreviewEnrichers, enricher)
+}
+
+// RegisterQueryVariable adds a custom query variable, see QueryVariableResolver
+func (a *APIImpl) RegisterQueryVariable(ctx context.Context, name string, resolver QueryVariableResolver) {
+	if resolver == nil {
+		a.Log(ctx, LogLevelError, "query variable resolver must not be nil")
+		return
+	}
+	variable, parseErr := parseCustomQueryVariable(name)
+	if parseErr != nil {
+		a.Log(ctx, LogLevelError, parseErr.Error())
+		return
+	}
+	if _, found := GetPluginManager().getRegisteredQueryVariable(variable); found {
+		a.Log(ctx, LogLevelError, fmt.Sprintf("query variable %s is already registered", variable))
+		return
+	}
+
+	a.pluginInstance.QueryVariables = append(a.pluginInstance.QueryVariables, registeredQueryVariable{Variable: variable, Resolver: resolver})
+}
+
+// ReportInitError marks plugin as failed to initialize, see Manager.setPluginInitError
+func (a *APIImpl) ReportInitError(ctx context.Context, err error) {
+	GetPluginManager().setPluginInitError(ctx, a.pluginInstance, err)
+}
+
+// RegisterHeadlessAction adds a headless action, see HeadlessAction
+func (a *APIImpl) RegisterHeadlessAction(ctx context.Context, action HeadlessAction) {
+	if action.Action == nil {
+		a.Log(ctx, LogLevelError, fmt.Sprintf("headless action %s must have an action", action.Name))
+		return
+	}
+	if !headlessActionNamePattern.MatchString(action.Name) {
+		a.Log(ctx, LogLevelError, fmt.Sprintf("invalid headless action name: %s, only letters, digits, - and _ are allowed", action.Name))
+		return
+	}
+	a.pluginInstance.headlessActionsLock.Lock()
+	defer a.pluginInstance.headlessActionsLock.Unlock()
+	if lo.ContainsBy(a.pluginInstance.HeadlessActions, func(item HeadlessAction) bool { return item.Name == action.Name }) {
+		a.Log(ctx, LogLevelError, fmt.Sprintf("headless action %s is already registered", action.Name))
+		return
+	}
+
+	a.pluginInstance.HeadlessActions = append(a.pluginInstance.HeadlessActions, action)
+}
+
+// UpdateResult pushes a new state of a result returned in current query to UI, E.g. when a subscription receives new data.
+// Result must have an explicit id set by plugin. Returns false if the update is dropped because query has changed.
+func (a *APIImpl) UpdateResult(ctx context.Context, resultId string, result RefreshableResult) bool {
+	return GetPluginManager().UpdateResult(ctx, a.pluginInstance, resultId, result)
+}
+
+// UpdateResultScore changes score of a result returned in current query, E.g. when a background lookup finds out how relevant it is.
+// Result must have an explicit id set by plugin. Returns false if the update is dropped because query has changed.
+func (a *APIImpl) UpdateResultScore(ctx context.Context, resultId string, score int64) bool {
+	return GetPluginManager().UpdateResultScore(ctx, a.pluginInstance, resultId, score)
+}
+
+// ExecuteUIBatch applies multiple UI commands in order as one request, E.g. show a message, then change query and hide app.
+// See share.UICommand for supported commands and error handling.
+func (a *APIImpl) ExecuteUIBatch(ctx context.Context, commands []share.UICommand) error {
+	for i := range commands {
+		if commands[i].Type == share.UICommandTypeShowToolbarMsg {
+			commands[i].Msg.PluginId = a.pluginInstance.Metadata.Id
+			commands[i].Msg.Text = a.GetTranslation(ctx, commands[i].Msg.Text)
+		}
+	}
+
+	return GetPluginManager().GetUI().ExecuteBatch(ctx, commands)
+}
+
+func (a *APIImpl) GetStore(ctx context.Context) *KVStore {
+	return a.pluginInstance.Store()
+}
+
+func (a *APIImpl) GetSecrets(ctx context.Context) *SecretStore {
+	return a.pluginInstance.Secrets()
+}
+
+// GetHTTPClient returns the http client of plugin, requests need network permission, see Instance.HTTPClient
+func (a *APIImpl) GetHTTPClient(ctx context.Context) *util.RateLimitedHTTPClient {
+	return a.pluginInstance.HTTPClient()
+}
+
+// RequestPermission must be called before a sensitive operation (E.g. reading clipboard), the operation should be skipped if error is returned.
+// See Manager.CheckPermission
+func (a *APIImpl) RequestPermission(ctx context.Context, permission MetadataPermission) error {
+	return GetPluginManager().CheckPermission(ctx, a.pluginInstance, permission)
+}
+
+// SendToPlugin hands payload to another plugin (empty targetPluginId means any plugin supports selection query), see Handoff
+func (a *APIImpl) SendToPlugin(ctx context.Context, targetPluginId string, payload selection.Selection, contextData string) error {
+	return GetPluginManager().SendToPlugin(ctx, a.pluginInstance, targetPluginId, payload, contextData)
+}
+
+// CarryContext makes contextData available to the next input queries as Query.CarriedContext, see CarriedContext
+func (a *APIImpl) CarryContext(ctx context.Context, contextData string) {
+	GetPluginManager().CarryContext(ctx, a.pluginInstance, contextData)
+}
+
+func (a *APIImpl) ReportQueryProgress(ctx context.Context, progress int, text string) {
+	GetPluginManager().ReportQueryProgress(ctx, a.pluginInstance, progress, a.GetTranslation(ctx, text))
+}
+
+func (a *APIImpl) PickFile(ctx context.Context, options PickFileOptions) ([]string, error) {
+	return GetPluginManager().GetUI().PickFiles(ctx, share.PickFilesParams{
+		Title:            a.GetTranslation(ctx, options.Title),
+		DefaultDirectory: options.DefaultDirectory,
+		Extensions:       options.Extensions,
+		AllowMultiple:    options.AllowMultiple,
+	})
+}
+
+func (a *APIImpl) PickFolder(ctx context.Context, options PickFolderOptions) (string, error) {
+	folders, err := GetPluginManager().GetUI().PickFiles(ctx, share.PickFilesParams{
+		IsDirectory:      true,
+		Title:            a.GetTranslation(ctx, options.Title),
+		DefaultDirectory: options.DefaultDirectory,
+	})
+	if err != nil || len(folders) == 0 {
+		return "", err
+	}
+	return folders[0], nil
+}
+
+func (a *APIImpl) ShowDialog(ctx context.Context, spec share.DialogSpec) (map[string]string, error) {
+	// spec is owned by plugin, don't translate it in place
+	spec.Title = a.GetTranslation(ctx, spec.Title)
+	spec.Description = a.GetTranslation(ctx, spec.Description)
+	spec.Fields = lo.Map(spec.Fields, func(field share.DialogField, _ int) share.DialogField {
+		field.Label = a.GetTranslation(ctx, field.Label)
+		field.Placeholder = a.GetTranslation(ctx, field.Placeholder)
+		field.Options = lo.Map(field.Options, func(option share.DialogFieldOption, _ int) share.DialogFieldOption {
+			option.Label = a.GetTranslation(ctx, option.Label)
+			return option
+		})
+		return field
+	})
+	spec.Buttons = lo.Map(spec.Buttons, func(button share.DialogButton, _ int) share.DialogButton {
+		button.Label = a.GetTranslation(ctx, button.Label)
+		return button
+	})
+	return GetPluginManager().GetUI().ShowDialog(ctx, spec)
+}
+
 func (a *APIImpl) AIChatStream(ctx context.Context, model ai.Model, conversations []ai.Conversation, callback ai.ChatStreamFunc) error {
 	//check if plugin has the feature permission
 	if !a.pluginInstance.Metadata.IsSupportFeature(MetadataFeatureAI) {
@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"wox/util"
+)
+
+// carriedContextExpiry is how long a carried context waits for user to continue the workflow
+const carriedContextExpiry = 5 * time.Minute
+
+// CarriedContext is context data stashed by an action for the queries user types next, see Manager.CarryContext.
+// It enables multi step workflows, E.g. pick a repository, then search issues within it.
+//
+// The context is attached to following input queries as Query.CarriedContext until one of these happens:
+//   - user clears the query box
+//   - query session ends (Wox is hidden)
+//   - another context is carried, by any plugin
+//   - no query is made within 5 minutes
+type CarriedContext struct {
+	SourcePluginId string
+	ContextData    string
+}
+
+type pendingCarriedContext struct {
+	carried  CarriedContext
+	lastUsed atomic.Int64 // refreshed by each query which gets the context
+}
+
+// CarryContext stashes contextData for the next input queries, any plugin can read it from Query.CarriedContext, see CarriedContext
+func (m *Manager) CarryContext(ctx context.Context, sourceInstance *Instance, contextData string) {
+	pending := &pendingCarriedContext{
+		carried: CarriedContext{
+			SourcePluginId: sourceInstance.Metadata.Id,
+			ContextData:    contextData,
+		},
+	}
+	pending.lastUsed.Store(util.GetSystemTimestamp())
+	m.pendingCarry.Store(pending)
+	logger.Info(ctx, fmt.Sprintf("<%s> carry context to next query", sourceInstance.Metadata.Name))
+}
+
+// getCarriedContext returns the carried context for an input query, or drops it if the query box is cleared or it has expired.
+// Selection queries neither get nor drop it
+func (m *Manager) getCarriedContext(query Query) *CarriedContext {
+	pending := m.pendingCarry.Load()
+	if pending == nil {
+		return nil
+	}
+
+	now := util.GetSystemTimestamp()
+	if query.RawQuery == "" || now-pending.lastUsed.Load() > carriedContextExpiry.Milliseconds() {
+		m.pendingCarry.CompareAndSwap(pending, nil)
+		return nil
+	}
+
+	pending.lastUsed.Store(now)
+	carried := pending.carried
+	return &carried
+}
@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCarriedContext(t *testing.T) {
+	ctx := util.NewTraceContext()
+	logger = util.GetLogger()
+	m := &Manager{}
+	repoPicker := &Instance{Metadata: Metadata{Id: "repo-picker", Name: "repo picker"}}
+
+	assert.Nil(t, m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: "issues"}))
+
+	m.CarryContext(ctx, repoPicker, "Wox-launcher/Wox")
+	carried := m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: "issues"})
+	assert.Equal(t, &CarriedContext{SourcePluginId: "repo-picker", ContextData: "Wox-launcher/Wox"}, carried)
+	assert.NotNil(t, m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: "issues bug"}))
+
+	// another carried context replaces it
+	m.CarryContext(ctx, repoPicker, "Wox-launcher/Wox.Plugin")
+	assert.Equal(t, "Wox-launcher/Wox.Plugin", m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: "issues"}).ContextData)
+
+	// clearing the query box drops it
+	assert.Nil(t, m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: ""}))
+	assert.Nil(t, m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: "issues"}))
+
+	// expires if user doesn't continue the workflow
+	m.CarryContext(ctx, repoPicker, "Wox-launcher/Wox")
+	m.pendingCarry.Load().lastUsed.Store(util.GetSystemTimestamp() - carriedContextExpiry.Milliseconds() - 1)
+	assert.Nil(t, m.getCarriedContext(Query{Type: QueryTypeInput, RawQuery: "issues"}))
+	assert.Nil(t, m.pendingCarry.Load())
+}
+
+func TestGetInflightQueryKey_CarriedContext(t *testing.T) {
+	query := Query{Type: QueryTypeInput, RawQuery: "issues"}
+	withRepo := query
+	withRepo.CarriedContext = &CarriedContext{ContextData: "Wox-launcher/Wox"}
+	withOtherRepo := query
+	withOtherRepo.CarriedContext = &CarriedContext{ContextData: "Wox-launcher/Wox.Plugin"}
+
+	assert.NotEqual(t, getInflightQueryKey(query), getInflightQueryKey(withRepo))
+	assert.NotEqual(t, getInflightQueryKey(withRepo), getInflightQueryKey(withOtherRepo))
+}
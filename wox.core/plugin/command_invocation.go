@@ -0,0 +1,164 @@
+package plugin
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type MetadataCommandArgumentType = string
+
+const (
+	MetadataCommandArgumentTypeString MetadataCommandArgumentType = "string"
+	MetadataCommandArgumentTypeNumber MetadataCommandArgumentType = "number"
+	MetadataCommandArgumentTypeBool   MetadataCommandArgumentType = "bool" // true/false, yes/no, on/off or 1/0
+)
+
+// MetadataCommandArgument declares a positional argument of a command, see MetadataCommand.Arguments
+type MetadataCommandArgument struct {
+	Name        string
+	Description string
+	// Type of the value, empty means MetadataCommandArgumentTypeString. Values of wrong type are reported in CommandInvocation.Invalid
+	Type     MetadataCommandArgumentType
+	Required bool
+	// If true, argument takes the rest of the search as is (E.g. a commit message), it must be the last argument
+	Rest bool
+}
+
+// CommandInvocation is the search of a query parsed by the arguments of the matched command, see MetadataCommand.Arguments.
+// Search is split by spaces, double quotes group words into one value (E.g. `"hello world"`).
+// Parsing never fails, plugin decides how to handle missing or invalid arguments (E.g. show usage of the command)
+type CommandInvocation struct {
+	Command   string            // canonical name of the command, aliases are resolved
+	Arguments map[string]string // values of provided arguments by argument name
+	Extra     []string          // values after the last argument
+	Missing   []string          // names of required arguments which are not provided
+	Invalid   []string          // names of arguments whose value doesn't match their type
+}
+
+// UnmarshalJSON accepts simple string commands (E.g. "install") besides command objects in plugin.json
+func (c *MetadataCommand) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*c = MetadataCommand{Command: command}
+		return nil
+	}
+
+	type metadataCommand MetadataCommand
+	var full metadataCommand
+	if err := json.Unmarshal(data, &full); err != nil {
+		return err
+	}
+	*c = MetadataCommand(full)
+	return nil
+}
+
+// GetString returns the value of argument name, empty if it's not provided
+func (c *CommandInvocation) GetString(name string) string {
+	return c.Arguments[name]
+}
+
+// GetNumber returns the value of number argument name, false if it's not provided or invalid
+func (c *CommandInvocation) GetNumber(name string) (float64, bool) {
+	value, exist := c.Arguments[name]
+	if !exist {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return number, err == nil
+}
+
+// GetBool returns the value of bool argument name, false if it's not provided or invalid
+func (c *CommandInvocation) GetBool(name string) bool {
+	value, _ := parseCommandArgumentBool(c.Arguments[name])
+	return value
+}
+
+// parseCommandInvocation parses search by arguments of command, nil if command declares no arguments
+func parseCommandInvocation(command MetadataCommand, search string) *CommandInvocation {
+	if len(command.Arguments) == 0 {
+		return nil
+	}
+
+	invocation := &CommandInvocation{
+		Command:   command.Command,
+		Arguments: map[string]string{},
+	}
+	rest := search
+	for _, argument := range command.Arguments {
+		var value string
+		var found bool
+		if argument.Rest {
+			value = strings.TrimSpace(rest)
+			rest = ""
+			found = value != ""
+		} else {
+			value, rest, found = nextCommandArgument(rest)
+		}
+		if !found {
+			if argument.Required {
+				invocation.Missing = append(invocation.Missing, argument.Name)
+			}
+			continue
+		}
+
+		invocation.Arguments[argument.Name] = value
+		if !isCommandArgumentValid(argument, value) {
+			invocation.Invalid = append(invocation.Invalid, argument.Name)
+		}
+	}
+	for {
+		value, remaining, found := nextCommandArgument(rest)
+		if !found {
+			break
+		}
+		invocation.Extra = append(invocation.Extra, value)
+		rest = remaining
+	}
+
+	return invocation
+}
+
+// nextCommandArgument returns the first value of text and the text after it, a value is a word or a double-quoted text.
+// An unclosed quote takes the rest of text
+func nextCommandArgument(text string) (value string, rest string, found bool) {
+	text = strings.TrimLeftFunc(text, unicode.IsSpace)
+	if text == "" {
+		return "", "", false
+	}
+
+	if strings.HasPrefix(text, `"`) {
+		if end := strings.Index(text[1:], `"`); end >= 0 {
+			return text[1 : end+1], text[end+2:], true
+		}
+		return text[1:], "", true
+	}
+
+	if end := strings.IndexFunc(text, unicode.IsSpace); end >= 0 {
+		return text[:end], text[end:], true
+	}
+	return text, "", true
+}
+
+func isCommandArgumentValid(argument MetadataCommandArgument, value string) bool {
+	switch argument.Type {
+	case MetadataCommandArgumentTypeNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case MetadataCommandArgumentTypeBool:
+		_, ok := parseCommandArgumentBool(value)
+		return ok
+	}
+	return true
+}
+
+func parseCommandArgumentBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"wox/i18n"
+	"wox/share"
+
+	"github.com/samber/lo"
+)
+
+// getUnknownCommandSuggestion returns a result suggesting the closest command of the plugin for Query.UnknownCommand,
+// selecting it changes the query to use that command. Returns false if no command is close enough
+func (m *Manager) getUnknownCommandSuggestion(ctx context.Context, pluginInstance *Instance, query Query, results []QueryResult) (QueryResult, bool) {
+	closestCommand, found := findClosestCommand(query.UnknownCommand, pluginInstance.GetQueryCommands())
+	if !found {
+		return QueryResult{}, false
+	}
+
+	logger.Debug(ctx, fmt.Sprintf("<%s> unknown command: %s, suggest: %s", pluginInstance.Metadata.Name, query.UnknownCommand, closestCommand.Command))
+	rest := strings.TrimPrefix(query.Search, query.UnknownCommand)
+	newQueryText := fmt.Sprintf("%s %s%s", query.TriggerKeyword, closestCommand.Command, rest)
+	if rest == "" {
+		newQueryText += " "
+	}
+
+	// show suggestion above results of the plugin, because user most likely mistyped the command
+	var score int64
+	if len(results) > 0 {
+		score = addScore(lo.MaxBy(results, func(a, b QueryResult) bool { return a.Score > b.Score }).Score, 1)
+	}
+
+	suggestion := QueryResult{
+		Title:                fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_manager_unknown_command_suggestion"), closestCommand.Command),
+		SubTitle:             closestCommand.Description,
+		Icon:                 ParseWoxImageOrDefault(pluginInstance.Metadata.Icon, SearchIcon),
+		Score:                score,
+		DisableGlobalActions: true,
+		Actions: []QueryResultAction{
+			{
+				Name:                   "i18n:plugin_manager_unknown_command_use",
+				PreventHideAfterAction: true,
+				Action: func(ctx context.Context, actionContext ActionContext) {
+					m.ui.ChangeQuery(ctx, share.PlainQuery{
+						QueryType: QueryTypeInput,
+						QueryText: newQueryText,
+					})
+				},
+			},
+		},
+	}
+	return m.PolishResult(ctx, pluginInstance, query, suggestion), true
+}
+
+// findClosestCommand finds the command with minimal edit distance (case-insensitive) to given text,
+// a command is considered close enough if at most a third of the text needs to be changed
+func findClosestCommand(text string, commands []MetadataCommand) (MetadataCommand, bool) {
+	maxDistance := max(1, utf8.RuneCountInString(text)/3)
+
+	var closest MetadataCommand
+	closestDistance := maxDistance + 1
+	for _, command := range commands {
+		distance := editDistance(strings.ToLower(text), strings.ToLower(command.Command))
+		if distance < closestDistance {
+			closest = command
+			closestDistance = distance
+		}
+	}
+
+	return closest, closestDistance <= maxDistance
+}
+
+// editDistance returns the levenshtein distance between a and b in runes
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(br)]
+}
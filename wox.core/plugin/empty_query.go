@@ -0,0 +1,17 @@
+package plugin
+
+import (
+	"github.com/samber/lo"
+)
+
+// emptyQueryDebounceMs is the debounce of empty queries, long enough to skip the empty query in between when user deletes the query
+// and types another one right away
+const emptyQueryDebounceMs = 150
+
+// IsEmptyQuerySupported returns true if any enabled plugin wants empty queries, see MetadataFeatureEmptyQuery.
+// Empty queries sent by UI when query box is cleared are answered without querying plugins if it returns false
+func (m *Manager) IsEmptyQuerySupported() bool {
+	return lo.ContainsBy(m.getInstances(), func(instance *Instance) bool {
+		return !instance.Setting.Disabled && instance.Metadata.IsSupportFeature(MetadataFeatureEmptyQuery)
+	})
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"wox/setting"
+	"wox/util"
+	"wox/util/clipboard"
+
+	"github.com/samber/lo"
+)
+
+// getGlobalActions returns the actions user enabled in GlobalActions setting, they are appended to every result
+// unless the result opts out (see QueryResult.DisableGlobalActions).
+// Global actions are system actions and never become the default action of a result.
+// A global action hotkey is dropped if one of the plugin's own actions already uses it.
+func (m *Manager) getGlobalActions(ctx context.Context, pluginInstance *Instance, title string, actions []QueryResultAction) (globalActions []QueryResultAction) {
+	usedHotkeys := lo.FilterMap(actions, func(action QueryResultAction, _ int) (string, bool) {
+		return normalizeActionHotkey(action.Hotkey), action.Hotkey != ""
+	})
+	hotkey := func(key string) string {
+		modifier := "ctrl"
+		if util.IsMacOS() {
+			modifier = "cmd"
+		}
+		hotkey := modifier + "+shift+" + key
+		if lo.Contains(usedHotkeys, normalizeActionHotkey(hotkey)) {
+			return ""
+		}
+		return hotkey
+	}
+
+	for _, globalAction := range setting.GetSettingManager().GetWoxSetting(ctx).GlobalActions {
+		switch globalAction {
+		case setting.GlobalActionCopyTitle:
+			globalActions = append(globalActions, QueryResultAction{
+				Name:           "i18n:plugin_manager_global_action_copy_title",
+				Icon:           CopyIcon,
+				Hotkey:         hotkey("c"),
+				IsSystemAction: true,
+				Action: func(ctx context.Context, actionContext ActionContext) {
+					clipboard.WriteText(m.translatePlugin(ctx, pluginInstance, title))
+				},
+			})
+		case setting.GlobalActionOpenPluginSetting:
+			globalActions = append(globalActions, QueryResultAction{
+				Name:                   "i18n:plugin_manager_global_action_open_plugin_setting",
+				Icon:                   SettingIcon,
+				Hotkey:                 hotkey("s"),
+				IsSystemAction:         true,
+				PreventHideAfterAction: true,
+				Action: func(ctx context.Context, actionContext ActionContext) {
+					m.openPluginSetting(ctx, pluginInstance)
+				},
+			})
+		case setting.GlobalActionReportIssue:
+			// plugins without website have nowhere to report
+			if pluginInstance.Metadata.Website == "" {
+				continue
+			}
+			globalActions = append(globalActions, NewOpenURLAction("i18n:plugin_manager_global_action_report_issue", pluginInstance.Metadata.Website))
+			globalActions[len(globalActions)-1].Hotkey = hotkey("r")
+			globalActions[len(globalActions)-1].IsSystemAction = true
+		}
+	}
+
+	return globalActions
+}
+
+func normalizeActionHotkey(hotkey string) string {
+	return strings.ToLower(strings.ReplaceAll(hotkey, " ", ""))
+}
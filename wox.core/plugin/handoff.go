@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"wox/share"
+	"wox/util/selection"
+)
+
+// Handoff is the context of a payload sent from one plugin to another (E.g. pipe a file result into a compression plugin).
+//
+// The payload is delivered to target plugin as a selection query (so target plugin must support selection query),
+// and this handoff is attached to the query as Query.Handoff, so target plugin knows where the payload comes from.
+// The handoff lives as long as the selection is not changed, so user can still type to search in target plugin.
+type Handoff struct {
+	SourcePluginId string
+	TargetPluginId string // empty means all plugins that support selection query
+	ContextData    string // additional data from source plugin, E.g. result's ContextData
+}
+
+type pendingHandoff struct {
+	handoff   Handoff
+	selection selection.Selection
+}
+
+// SendToPlugin hands payload to target plugin and switches the query accordingly, see Handoff
+func (m *Manager) SendToPlugin(ctx context.Context, sourceInstance *Instance, targetPluginId string, payload selection.Selection, contextData string) error {
+	if payload.IsEmpty() {
+		return fmt.Errorf("payload is empty")
+	}
+
+	if targetPluginId != "" {
+		targetInstance, found := m.getPluginInstanceById(targetPluginId)
+		if !found {
+			return fmt.Errorf("target plugin not found: %s", targetPluginId)
+		}
+		if !targetInstance.IsSupportSelection() {
+			return fmt.Errorf("target plugin %s doesn't support selection query", targetInstance.Metadata.Name)
+		}
+	}
+
+	m.pendingHandoff.Store(&pendingHandoff{
+		handoff: Handoff{
+			SourcePluginId: sourceInstance.Metadata.Id,
+			TargetPluginId: targetPluginId,
+			ContextData:    contextData,
+		},
+		selection: payload,
+	})
+	logger.Info(ctx, fmt.Sprintf("<%s> send %s to plugin: %s", sourceInstance.Metadata.Name, payload.String(), targetPluginId))
+
+	m.ui.ChangeQuery(ctx, share.PlainQuery{
+		QueryType:      QueryTypeSelection,
+		QuerySelection: payload,
+	})
+	return nil
+}
+
+// getHandoff returns the pending handoff if it's for the given selection, otherwise the handoff is dropped because query has changed
+func (m *Manager) getHandoff(query Query) *Handoff {
+	pending := m.pendingHandoff.Load()
+	if pending == nil {
+		return nil
+	}
+
+	if query.Type != QueryTypeSelection || query.Selection.String() != pending.selection.String() {
+		m.pendingHandoff.CompareAndSwap(pending, nil)
+		return nil
+	}
+
+	handoff := pending.handoff
+	return &handoff
+}
+
+func (m *Manager) getPluginInstanceById(pluginId string) (*Instance, bool) {
+	for _, instance := range m.getInstances() {
+		if instance.Metadata.Id == pluginId {
+			return instance, true
+		}
+	}
+	return nil, false
+}
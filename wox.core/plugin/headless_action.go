@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+// headless action names are like "toggle-dark-mode", so they can be referenced as "<plugin>:<action>", see parseHeadlessActionReference
+var headlessActionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// HeadlessAction is a named action which runs without a query or result list, E.g. "toggle-dark-mode". It's invoked by a query hotkey
+// with HeadlessAction set (see setting.QueryHotkey) or by /action/headless endpoint. Register it by API.RegisterHeadlessAction
+type HeadlessAction struct {
+	Name        string // unique within plugin, letters, digits, "-" and "_"
+	Description string
+	// Returned error is logged and reported to caller, hotkeys show it as a notification
+	Action func(ctx context.Context, actionContext HeadlessActionContext) error
+}
+
+// HeadlessActionContext is supplied by the caller of a headless action
+type HeadlessActionContext struct {
+	// Data passed as is by caller: the query of a query hotkey (query variables are replaced) or "ContextData" of /action/headless request.
+	// Empty if caller passed nothing
+	ContextData string
+}
+
+// HeadlessActionInfo describes a registered headless action, see Manager.GetHeadlessActions
+type HeadlessActionInfo struct {
+	PluginId    string
+	PluginName  string
+	Name        string
+	Description string
+}
+
+// parseHeadlessActionReference splits "<plugin id or name>:<action name>" (E.g. "Theme:toggle-dark-mode"), plugin name may contain ":"
+func parseHeadlessActionReference(reference string) (plugin string, actionName string, err error) {
+	index := strings.LastIndex(reference, ":")
+	if index <= 0 || index == len(reference)-1 {
+		return "", "", fmt.Errorf("invalid headless action: %s, it should be like <plugin>:<action>", reference)
+	}
+	return strings.TrimSpace(reference[:index]), strings.TrimSpace(reference[index+1:]), nil
+}
+
+// GetHeadlessActions returns headless actions registered by enabled plugins
+func (m *Manager) GetHeadlessActions() (actions []HeadlessActionInfo) {
+	for _, instance := range m.getInstances() {
+		if instance.Setting.Disabled {
+			continue
+		}
+		for _, action := range instance.getHeadlessActions() {
+			actions = append(actions, HeadlessActionInfo{
+				PluginId:    instance.Metadata.Id,
+				PluginName:  instance.Metadata.Name,
+				Name:        action.Name,
+				Description: action.Description,
+			})
+		}
+	}
+	return actions
+}
+
+// ExecuteHeadlessActionByReference executes a headless action referenced as "<plugin id or name>:<action name>", see ExecuteHeadlessAction
+func (m *Manager) ExecuteHeadlessActionByReference(ctx context.Context, reference string, contextData string) error {
+	pluginIdOrName, actionName, parseErr := parseHeadlessActionReference(reference)
+	if parseErr != nil {
+		return parseErr
+	}
+	return m.ExecuteHeadlessAction(ctx, pluginIdOrName, actionName, contextData)
+}
+
+// ExecuteHeadlessAction executes a headless action of an enabled plugin (by plugin id or case-insensitive name) directly, there is no query
+// and nothing is shown. It waits for the action, errors and panics of the action are returned, see HeadlessAction
+func (m *Manager) ExecuteHeadlessAction(ctx context.Context, pluginIdOrName string, actionName string, contextData string) (err error) {
+	instance, found := lo.Find(m.getInstances(), func(item *Instance) bool {
+		return item.Metadata.Id == pluginIdOrName || strings.EqualFold(item.Metadata.Name, pluginIdOrName)
+	})
+	if !found {
+		return fmt.Errorf("plugin not found for headless action: %s", pluginIdOrName)
+	}
+	if instance.Setting.Disabled {
+		return fmt.Errorf("plugin %s is disabled", instance.Metadata.Name)
+	}
+	headlessActions := instance.getHeadlessActions()
+	action, found := lo.Find(headlessActions, func(item HeadlessAction) bool { return item.Name == actionName })
+	if !found {
+		available := lo.Map(headlessActions, func(item HeadlessAction, _ int) string { return item.Name })
+		return fmt.Errorf("headless action %s not found in plugin %s, available: %s", actionName, instance.Metadata.Name, strings.Join(available, ", "))
+	}
+
+	defer util.GoRecover(ctx, fmt.Sprintf("<%s> headless action %s panic", instance.Metadata.Name, actionName), func(panicErr error) {
+		err = fmt.Errorf("headless action %s panic: %w", actionName, panicErr)
+	})
+
+	logger.Info(ctx, fmt.Sprintf("<%s> execute headless action: %s", instance.Metadata.Name, actionName))
+	if actionErr := action.Action(ctx, HeadlessActionContext{ContextData: contextData}); actionErr != nil {
+		return fmt.Errorf("headless action %s failed: %w", actionName, actionErr)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeadlessActionReference(t *testing.T) {
+	pluginIdOrName, actionName, err := parseHeadlessActionReference("Theme:toggle-dark-mode")
+	assert.Nil(t, err)
+	assert.Equal(t, "Theme", pluginIdOrName)
+	assert.Equal(t, "toggle-dark-mode", actionName)
+
+	// plugin name may contain ":"
+	pluginIdOrName, actionName, err = parseHeadlessActionReference("Foo: Bar:run")
+	assert.Nil(t, err)
+	assert.Equal(t, "Foo: Bar", pluginIdOrName)
+	assert.Equal(t, "run", actionName)
+
+	for _, invalid := range []string{"", "toggle", ":toggle", "Theme:"} {
+		_, _, err = parseHeadlessActionReference(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestExecuteHeadlessAction(t *testing.T) {
+	logger = util.GetLogger()
+	ctx := context.Background()
+
+	var received []string
+	instance := &Instance{
+		Metadata: Metadata{Id: "theme-plugin-id", Name: "Theme"},
+		Setting:  &setting.PluginSetting{},
+		HeadlessActions: []HeadlessAction{
+			{Name: "toggle-dark-mode", Action: func(ctx context.Context, actionContext HeadlessActionContext) error {
+				received = append(received, actionContext.ContextData)
+				return nil
+			}},
+			{Name: "fail", Action: func(ctx context.Context, actionContext HeadlessActionContext) error {
+				return errors.New("boom")
+			}},
+			{Name: "panic", Action: func(ctx context.Context, actionContext HeadlessActionContext) error {
+				panic("boom")
+			}},
+		},
+	}
+	m := &Manager{instances: []*Instance{instance}}
+
+	assert.Nil(t, m.ExecuteHeadlessActionByReference(ctx, "theme:toggle-dark-mode", "dark"))
+	assert.Nil(t, m.ExecuteHeadlessAction(ctx, "theme-plugin-id", "toggle-dark-mode", ""))
+	assert.Equal(t, []string{"dark", ""}, received)
+	assert.Len(t, m.GetHeadlessActions(), 3)
+
+	assert.ErrorContains(t, m.ExecuteHeadlessAction(ctx, "Theme", "fail", ""), "boom")
+	assert.ErrorContains(t, m.ExecuteHeadlessAction(ctx, "Theme", "panic", ""), "panic")
+	assert.ErrorContains(t, m.ExecuteHeadlessAction(ctx, "Theme", "unknown", ""), "available: toggle-dark-mode, fail, panic")
+	assert.ErrorContains(t, m.ExecuteHeadlessAction(ctx, "Unknown", "toggle-dark-mode", ""), "plugin not found")
+
+	instance.Setting.Disabled = true
+	assert.ErrorContains(t, m.ExecuteHeadlessAction(ctx, "Theme", "toggle-dark-mode", ""), "disabled")
+	assert.Empty(t, m.GetHeadlessActions())
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+	"wox/i18n"
+)
+
+// max matched parts quoted in a generated match reason, see getMatchReason
+const maxMatchReasonParts = 5
+
+// normalizeHighlights clamps highlight ranges to text, drops empty ones, and sorts and merges overlapping ones,
+// so UI can render them in one pass
+func normalizeHighlights(highlights [][2]int, text string) [][2]int {
+	if len(highlights) == 0 {
+		return nil
+	}
+
+	length := utf8.RuneCountInString(text)
+	var normalized [][2]int
+	for _, highlight := range highlights {
+		start, end := max(highlight[0], 0), min(highlight[1], length)
+		if start < end {
+			normalized = append(normalized, [2]int{start, end})
+		}
+	}
+	slices.SortFunc(normalized, func(a, b [2]int) int {
+		return a[0] - b[0]
+	})
+
+	var merged [][2]int
+	for _, highlight := range normalized {
+		if last := len(merged) - 1; last >= 0 && highlight[0] <= merged[last][1] {
+			merged[last][1] = max(merged[last][1], highlight[1])
+			continue
+		}
+		merged = append(merged, highlight)
+	}
+	return merged
+}
+
+// getMatchReason explains which parts of title and subtitle matched the query by their highlights (E.g. `matched "vs", "c" in title`),
+// see QueryResult.MatchReason. Highlights must be normalized, empty if there is no highlight
+func getMatchReason(ctx context.Context, title string, titleHighlights [][2]int, subTitle string, subTitleHighlights [][2]int) string {
+	var reasons []string
+	if parts := quoteHighlights(title, titleHighlights); parts != "" {
+		reasons = append(reasons, fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_match_reason_title"), parts))
+	}
+	if parts := quoteHighlights(subTitle, subTitleHighlights); parts != "" {
+		reasons = append(reasons, fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_match_reason_subtitle"), parts))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// quoteHighlights returns highlighted parts of text quoted and joined (E.g. `"vs", "c"`), parts beyond maxMatchReasonParts are elided
+func quoteHighlights(text string, highlights [][2]int) string {
+	runes := []rune(text)
+	var parts []string
+	for _, highlight := range highlights {
+		if len(parts) == maxMatchReasonParts {
+			parts = append(parts, "…")
+			break
+		}
+		parts = append(parts, fmt.Sprintf("\"%s\"", string(runes[highlight[0]:highlight[1]])))
+	}
+	return strings.Join(parts, ", ")
+}
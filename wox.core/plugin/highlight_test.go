@@ -0,0 +1,16 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteHighlights(t *testing.T) {
+	assert.Equal(t, "", quoteHighlights("Visual Studio Code", nil))
+	assert.Equal(t, `"Vis", "C"`, quoteHighlights("Visual Studio Code", [][2]int{{0, 3}, {14, 15}}))
+	// highlights are in runes
+	assert.Equal(t, `"微信"`, quoteHighlights("打开微信", [][2]int{{2, 4}}))
+	// parts beyond the limit are elided
+	assert.Equal(t, `"a", "b", "c", "d", "e", …`, quoteHighlights("abcdefg", [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}}))
+}
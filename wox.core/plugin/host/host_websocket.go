@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 	"wox/ai"
@@ -16,6 +17,7 @@ import (
 	"wox/util/selection"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/tidwall/gjson"
 )
 
@@ -270,6 +272,17 @@ func (w *WebsocketHost) handleRequestFromPlugin(ctx context.Context, request Jso
 		}
 		pluginInstance.API.Notify(ctx, message)
 		w.sendResponseToHost(ctx, request, "")
+	case "SetStatus":
+		text, exist := request.Params["text"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] SetStatus method must have a text parameter", request.PluginName))
+			return
+		}
+		pluginInstance.API.SetStatus(ctx, text)
+		w.sendResponseToHost(ctx, request, "")
+	case "ClearStatus":
+		pluginInstance.API.ClearStatus(ctx)
+		w.sendResponseToHost(ctx, request, "")
 	case "Log":
 		msg, exist := request.Params["msg"]
 		if !exist {
@@ -292,6 +305,26 @@ func (w *WebsocketHost) handleRequestFromPlugin(ctx context.Context, request Jso
 		}
 		result := pluginInstance.API.GetTranslation(ctx, key)
 		w.sendResponseToHost(ctx, request, result)
+	case "FormatRelativeTime":
+		timestampStr, exist := request.Params["timestamp"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] FormatRelativeTime method must have a timestamp parameter", request.PluginName))
+			return
+		}
+		// unix timestamp in milliseconds
+		timestamp, parseErr := strconv.ParseInt(timestampStr, 10, 64)
+		if parseErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] FormatRelativeTime timestamp parameter is not a valid number: %s", request.PluginName, timestampStr))
+			return
+		}
+
+		// 0 means no time, same as zero time.Time of Go plugins
+		var t time.Time
+		if timestamp != 0 {
+			t = util.ConvertTimeFromTimestamp(timestamp)
+		}
+		result := pluginInstance.API.FormatRelativeTime(ctx, t)
+		w.sendResponseToHost(ctx, request, result)
 	case "GetSetting":
 		key, exist := request.Params["key"]
 		if !exist {
@@ -420,6 +453,49 @@ func (w *WebsocketHost) handleRequestFromPlugin(ctx context.Context, request Jso
 			})
 		})
 		w.sendResponseToHost(ctx, request, "")
+	case "OnQuerySessionStart":
+		callbackId, exist := request.Params["callbackId"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] OnQuerySessionStart method must have a callbackId parameter", request.PluginName))
+			return
+		}
+
+		metadata := pluginInstance.Metadata
+		pluginInstance.API.OnQuerySessionStart(ctx, func() {
+			w.invokeMethod(ctx, metadata, "onQuerySessionStart", map[string]string{
+				"CallbackId": callbackId,
+			})
+		})
+		w.sendResponseToHost(ctx, request, "")
+	case "OnQuerySessionEnd":
+		callbackId, exist := request.Params["callbackId"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] OnQuerySessionEnd method must have a callbackId parameter", request.PluginName))
+			return
+		}
+
+		metadata := pluginInstance.Metadata
+		pluginInstance.API.OnQuerySessionEnd(ctx, func() {
+			w.invokeMethod(ctx, metadata, "onQuerySessionEnd", map[string]string{
+				"CallbackId": callbackId,
+			})
+		})
+		w.sendResponseToHost(ctx, request, "")
+	case "OnStartup":
+		callbackId, exist := request.Params["callbackId"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] OnStartup method must have a callbackId parameter", request.PluginName))
+			return
+		}
+
+		metadata := pluginInstance.Metadata
+		pluginInstance.API.OnStartup(ctx, func(ctx context.Context) error {
+			_, err := w.invokeMethod(ctx, metadata, "onStartup", map[string]string{
+				"CallbackId": callbackId,
+			})
+			return err
+		})
+		w.sendResponseToHost(ctx, request, "")
 	case "RegisterQueryCommands":
 		var commands []plugin.MetadataCommand
 		unmarshalErr := json.Unmarshal([]byte(request.Params["commands"]), &commands)
@@ -430,6 +506,320 @@ func (w *WebsocketHost) handleRequestFromPlugin(ctx context.Context, request Jso
 
 		pluginInstance.API.RegisterQueryCommands(ctx, commands)
 		w.sendResponseToHost(ctx, request, "")
+	case "ReportInitError":
+		// empty error clears the init error
+		var initErr error
+		if errMsg := request.Params["error"]; errMsg != "" {
+			initErr = errors.New(errMsg)
+		}
+		pluginInstance.API.ReportInitError(ctx, initErr)
+		w.sendResponseToHost(ctx, request, "")
+	case "RegisterHeadlessAction":
+		callbackId, exist := request.Params["callbackId"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] RegisterHeadlessAction method must have a callbackId parameter", request.PluginName))
+			return
+		}
+
+		metadata := pluginInstance.Metadata
+		pluginInstance.API.RegisterHeadlessAction(ctx, plugin.HeadlessAction{
+			Name:        request.Params["name"],
+			Description: request.Params["description"],
+			Action: func(ctx context.Context, actionContext plugin.HeadlessActionContext) error {
+				_, err := w.invokeMethod(ctx, metadata, "onHeadlessAction", map[string]string{
+					"CallbackId":  callbackId,
+					"ContextData": actionContext.ContextData,
+				})
+				return err
+			},
+		})
+		w.sendResponseToHost(ctx, request, "")
+	case "UpdateResult":
+		var result plugin.RefreshableResultWithResultId
+		unmarshalErr := json.Unmarshal([]byte(request.Params["result"]), &result)
+		if unmarshalErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal result: %s", request.PluginName, unmarshalErr))
+			return
+		}
+
+		metadata := pluginInstance.Metadata
+		updated := pluginInstance.API.UpdateResult(ctx, result.ResultId, plugin.RefreshableResult{
+			Title:              result.Title,
+			SubTitle:           result.SubTitle,
+			AccessibilityLabel: result.AccessibilityLabel,
+			Icon:               result.Icon,
+			Preview:            result.Preview,
+			Tails:              result.Tails,
+			ContextData:        result.ContextData,
+			RefreshInterval:    result.RefreshInterval,
+			Actions: lo.Map(result.Actions, func(action plugin.QueryResultActionUI, _ int) plugin.QueryResultAction {
+				return plugin.QueryResultAction{
+					Id:                     action.Id,
+					Name:                   action.Name,
+					Icon:                   action.Icon,
+					IsDefault:              action.IsDefault,
+					PreventHideAfterAction: action.PreventHideAfterAction,
+					Hotkey:                 action.Hotkey,
+					ActivationModifier:     action.ActivationModifier,
+					AllowRepeat:            action.AllowRepeat,
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						_, actionErr := w.invokeMethod(ctx, metadata, "action", map[string]string{
+							"ActionId":       action.Id,
+							"ContextData":    actionContext.ContextData,
+							"TriggerKeyword": actionContext.TriggerKeyword,
+						})
+						if actionErr != nil {
+							util.GetLogger().Error(ctx, fmt.Sprintf("[%s] action failed: %s", metadata.Name, actionErr.Error()))
+						}
+					},
+					IsSystemAction: action.IsSystemAction,
+				}
+			}),
+		})
+		w.sendResponseToHost(ctx, request, strconv.FormatBool(updated))
+	case "UpdateResultScore":
+		resultId, exist := request.Params["resultId"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] UpdateResultScore method must have a resultId parameter", request.PluginName))
+			return
+		}
+		score, parseErr := strconv.ParseInt(request.Params["score"], 10, 64)
+		if parseErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] UpdateResultScore score parameter is not a valid number: %s", request.PluginName, request.Params["score"]))
+			return
+		}
+
+		updated := pluginInstance.API.UpdateResultScore(ctx, resultId, score)
+		w.sendResponseToHost(ctx, request, strconv.FormatBool(updated))
+	case "RegisterStaticResults":
+		var staticResults []plugin.StaticResult
+		unmarshalErr := json.Unmarshal([]byte(request.Params["results"]), &staticResults)
+		if unmarshalErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal static results: %s", request.PluginName, unmarshalErr))
+			return
+		}
+
+		metadata := pluginInstance.Metadata
+		for i := range staticResults {
+			for j := range staticResults[i].Result.Actions {
+				actionId := staticResults[i].Result.Actions[j].Id
+				staticResults[i].Result.Actions[j].Action = func(ctx context.Context, actionContext plugin.ActionContext) {
+					_, actionErr := w.invokeMethod(ctx, metadata, "action", map[string]string{
+						"ActionId":       actionId,
+						"ContextData":    actionContext.ContextData,
+						"TriggerKeyword": actionContext.TriggerKeyword,
+					})
+					if actionErr != nil {
+						util.GetLogger().Error(ctx, fmt.Sprintf("[%s] action failed: %s", metadata.Name, actionErr.Error()))
+					}
+				}
+			}
+		}
+
+		pluginInstance.API.RegisterStaticResults(ctx, staticResults)
+		w.sendResponseToHost(ctx, request, "")
+	case "ExecuteUIBatch":
+		var commands []share.UICommand
+		unmarshalErr := json.Unmarshal([]byte(request.Params["commands"]), &commands)
+		if unmarshalErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal ui commands: %s", request.PluginName, unmarshalErr))
+			return
+		}
+
+		batchErr := pluginInstance.API.ExecuteUIBatch(ctx, commands)
+		if batchErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to execute ui batch: %s", request.PluginName, batchErr))
+			w.sendResponseToHost(ctx, request, batchErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "StoreGet":
+		key, exist := request.Params["key"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] StoreGet method must have a key parameter", request.PluginName))
+			return
+		}
+
+		// value is returned as json string, empty string means key doesn't exist
+		var value json.RawMessage
+		found, getErr := pluginInstance.Store().Get(ctx, key, &value)
+		if getErr != nil || !found {
+			w.sendResponseToHost(ctx, request, "")
+			return
+		}
+		w.sendResponseToHost(ctx, request, string(value))
+	case "StoreSet":
+		key, exist := request.Params["key"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] StoreSet method must have a key parameter", request.PluginName))
+			return
+		}
+		value, exist := request.Params["value"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] StoreSet method must have a value parameter", request.PluginName))
+			return
+		}
+
+		setErr := pluginInstance.Store().Set(ctx, key, json.RawMessage(value))
+		if setErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to set store value: %s", request.PluginName, setErr))
+			w.sendResponseToHost(ctx, request, setErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "StoreDelete":
+		key, exist := request.Params["key"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] StoreDelete method must have a key parameter", request.PluginName))
+			return
+		}
+
+		deleteErr := pluginInstance.Store().Delete(ctx, key)
+		if deleteErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to delete store value: %s", request.PluginName, deleteErr))
+			w.sendResponseToHost(ctx, request, deleteErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "SecretGet":
+		key, exist := request.Params["key"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] SecretGet method must have a key parameter", request.PluginName))
+			return
+		}
+
+		secret, _, getErr := pluginInstance.Secrets().Get(ctx, key)
+		if getErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to get secret: %s", request.PluginName, getErr))
+		}
+		w.sendResponseToHost(ctx, request, secret)
+	case "SecretSet":
+		key, exist := request.Params["key"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] SecretSet method must have a key parameter", request.PluginName))
+			return
+		}
+
+		setErr := pluginInstance.Secrets().Set(ctx, key, request.Params["value"])
+		if setErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to set secret: %s", request.PluginName, setErr))
+			w.sendResponseToHost(ctx, request, setErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "SecretDelete":
+		key, exist := request.Params["key"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] SecretDelete method must have a key parameter", request.PluginName))
+			return
+		}
+
+		deleteErr := pluginInstance.Secrets().Delete(ctx, key)
+		if deleteErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to delete secret: %s", request.PluginName, deleteErr))
+			w.sendResponseToHost(ctx, request, deleteErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "RequestPermission":
+		permission, exist := request.Params["permission"]
+		if !exist {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] RequestPermission method must have a permission parameter", request.PluginName))
+			return
+		}
+
+		// empty result means permission is granted, otherwise it's the reason
+		permissionErr := pluginInstance.API.RequestPermission(ctx, permission)
+		if permissionErr != nil {
+			w.sendResponseToHost(ctx, request, permissionErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "SendToPlugin":
+		var payload selection.Selection
+		unmarshalErr := json.Unmarshal([]byte(request.Params["payload"]), &payload)
+		if unmarshalErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal payload: %s", request.PluginName, unmarshalErr))
+			return
+		}
+
+		sendErr := pluginInstance.API.SendToPlugin(ctx, request.Params["targetPluginId"], payload, request.Params["contextData"])
+		if sendErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to send to plugin: %s", request.PluginName, sendErr))
+			w.sendResponseToHost(ctx, request, sendErr.Error())
+			return
+		}
+		w.sendResponseToHost(ctx, request, "")
+	case "CarryContext":
+		pluginInstance.API.CarryContext(ctx, request.Params["contextData"])
+		w.sendResponseToHost(ctx, request, "")
+	case "ReportQueryProgress":
+		progress, convErr := strconv.Atoi(request.Params["progress"])
+		if convErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] ReportQueryProgress method must have a numeric progress parameter", request.PluginName))
+			return
+		}
+		pluginInstance.API.ReportQueryProgress(ctx, progress, request.Params["text"])
+		w.sendResponseToHost(ctx, request, "")
+	case "PickFile", "PickFolder":
+		// paths are returned as json array, empty if user cancelled or the picker can't be opened
+		var paths []string
+		var pickErr error
+		if request.Method == "PickFile" {
+			var options plugin.PickFileOptions
+			if unmarshalErr := json.Unmarshal([]byte(request.Params["options"]), &options); unmarshalErr != nil {
+				util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal pick file options: %s", request.PluginName, unmarshalErr))
+				return
+			}
+			paths, pickErr = pluginInstance.API.PickFile(ctx, options)
+		} else {
+			var options plugin.PickFolderOptions
+			if unmarshalErr := json.Unmarshal([]byte(request.Params["options"]), &options); unmarshalErr != nil {
+				util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal pick folder options: %s", request.PluginName, unmarshalErr))
+				return
+			}
+			var folder string
+			folder, pickErr = pluginInstance.API.PickFolder(ctx, options)
+			if folder != "" {
+				paths = []string{folder}
+			}
+		}
+		if pickErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to %s: %s", request.PluginName, request.Method, pickErr))
+		}
+		pathsJson, _ := json.Marshal(lo.Ternary(paths == nil, []string{}, paths))
+		w.sendResponseToHost(ctx, request, string(pathsJson))
+	case "ShowDialog":
+		// Error is "cancelled" if user cancelled, "timeout" if user didn't respond in time, or the error message
+		type dialogResult struct {
+			Responses map[string]string
+			Error     string
+		}
+		var result dialogResult
+		var spec share.DialogSpec
+		if unmarshalErr := json.Unmarshal([]byte(request.Params["spec"]), &spec); unmarshalErr != nil {
+			// respond anyway, otherwise plugin waits for the dialog until request times out
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to unmarshal dialog spec: %s", request.PluginName, unmarshalErr))
+			result.Error = fmt.Sprintf("invalid dialog spec: %s", unmarshalErr)
+			resultJson, _ := json.Marshal(result)
+			w.sendResponseToHost(ctx, request, string(resultJson))
+			return
+		}
+
+		responses, dialogErr := pluginInstance.API.ShowDialog(ctx, spec)
+		switch {
+		case dialogErr == nil:
+			result.Responses = responses
+		case errors.Is(dialogErr, share.ErrDialogCancelled):
+			result.Error = "cancelled"
+		case errors.Is(dialogErr, share.ErrDialogTimeout):
+			result.Error = "timeout"
+		default:
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to show dialog: %s", request.PluginName, dialogErr))
+			result.Error = dialogErr.Error()
+		}
+		resultJson, _ := json.Marshal(result)
+		w.sendResponseToHost(ctx, request, string(resultJson))
 	case "AIChatStream":
 		callbackId, exist := request.Params["callbackId"]
 		if !exist {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"wox/plugin"
 	"wox/util"
 
@@ -23,9 +24,12 @@ func NewWebsocketPlugin(metadata plugin.Metadata, websocketHost *WebsocketHost)
 }
 
 func (w *WebsocketPlugin) Init(ctx context.Context, initParams plugin.InitParams) {
-	w.websocketHost.invokeMethod(ctx, w.metadata, "init", map[string]string{
+	_, initErr := w.websocketHost.invokeMethod(ctx, w.metadata, "init", map[string]string{
 		"PluginDirectory": initParams.PluginDirectory,
 	})
+	if initErr != nil {
+		initParams.API.ReportInitError(ctx, initErr)
+	}
 }
 
 func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugin.QueryResult {
@@ -41,14 +45,30 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 		return []plugin.QueryResult{}
 	}
 
+	commandInvocationJson, marshalInvocationErr := json.Marshal(query.CommandInvocation)
+	if marshalInvocationErr != nil {
+		util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to marshal plugin query command invocation: %s", w.metadata.Name, marshalInvocationErr.Error()))
+		return []plugin.QueryResult{}
+	}
+
+	carriedContextJson, marshalCarriedErr := json.Marshal(query.CarriedContext)
+	if marshalCarriedErr != nil {
+		util.GetLogger().Error(ctx, fmt.Sprintf("[%s] failed to marshal plugin query carried context: %s", w.metadata.Name, marshalCarriedErr.Error()))
+		return []plugin.QueryResult{}
+	}
+
 	rawResults, queryErr := w.websocketHost.invokeMethod(ctx, w.metadata, "query", map[string]string{
 		"Type":           query.Type,
 		"RawQuery":       query.RawQuery,
 		"TriggerKeyword": query.TriggerKeyword,
 		"Command":        query.Command,
 		"Search":         query.Search,
+		"IsLiteralMatch": strconv.FormatBool(query.IsLiteralMatch),
 		"Selection":      string(selectionJson),
 		"Env":            string(envJson),
+		"CarriedContext": string(carriedContextJson),
+		// null if the command declares no arguments
+		"CommandInvocation": string(commandInvocationJson),
 	})
 	if queryErr != nil {
 		util.GetLogger().Error(ctx, fmt.Sprintf("[%s] query failed: %s", w.metadata.Name, queryErr.Error()))
@@ -72,27 +92,24 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 	for i, r := range results {
 		result := r
 		for j, action := range result.Actions {
-			result.Actions[j].Action = func(ctx context.Context, actionContext plugin.ActionContext) {
-				_, actionErr := w.websocketHost.invokeMethod(ctx, w.metadata, "action", map[string]string{
-					"ActionId":    action.Id,
-					"ContextData": actionContext.ContextData,
-				})
-				if actionErr != nil {
-					util.GetLogger().Error(ctx, fmt.Sprintf("[%s] action failed: %s", w.metadata.Name, actionErr.Error()))
-				}
-			}
+			result.Actions[j].Action = w.newHostAction(action.Id)
+		}
+		// context actions are dispatched to host by id the same way as actions
+		for j, action := range result.ContextActions {
+			result.ContextActions[j].Action = w.newHostAction(action.Id)
 		}
 
 		results[i].OnRefresh = func(ctx context.Context, refreshableResult plugin.RefreshableResult) plugin.RefreshableResult {
 			refreshableResultWithResultId := plugin.RefreshableResultWithResultId{
-				ResultId:        result.Id,
-				Title:           refreshableResult.Title,
-				SubTitle:        refreshableResult.SubTitle,
-				Icon:            refreshableResult.Icon,
-				Preview:         refreshableResult.Preview,
-				Tails:           refreshableResult.Tails,
-				ContextData:     refreshableResult.ContextData,
-				RefreshInterval: refreshableResult.RefreshInterval,
+				ResultId:           result.Id,
+				Title:              refreshableResult.Title,
+				SubTitle:           refreshableResult.SubTitle,
+				AccessibilityLabel: refreshableResult.AccessibilityLabel,
+				Icon:               refreshableResult.Icon,
+				Preview:            refreshableResult.Preview,
+				Tails:              refreshableResult.Tails,
+				ContextData:        refreshableResult.ContextData,
+				RefreshInterval:    refreshableResult.RefreshInterval,
 				Actions: lo.Map(refreshableResult.Actions, func(action plugin.QueryResultAction, _ int) plugin.QueryResultActionUI {
 					return plugin.QueryResultActionUI{
 						Id:                     action.Id,
@@ -101,6 +118,8 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 						IsDefault:              action.IsDefault,
 						PreventHideAfterAction: action.PreventHideAfterAction,
 						Hotkey:                 action.Hotkey,
+						ActivationModifier:     action.ActivationModifier,
+						AllowRepeat:            action.AllowRepeat,
 						IsSystemAction:         action.IsSystemAction,
 					}
 				}),
@@ -115,6 +134,7 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 			rawResult, refreshErr := w.websocketHost.invokeMethod(ctx, w.metadata, "refresh", map[string]string{
 				"ResultId":          result.Id,
 				"RefreshableResult": string(refreshableJson),
+				"TriggerKeyword":    refreshableResult.TriggerKeyword,
 			})
 			if refreshErr != nil {
 				util.GetLogger().Error(ctx, fmt.Sprintf("[%s] refresh failed: %s", w.metadata.Name, refreshErr.Error()))
@@ -134,13 +154,15 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 			}
 
 			return plugin.RefreshableResult{
-				Title:           newResult.Title,
-				SubTitle:        newResult.SubTitle,
-				Icon:            newResult.Icon,
-				Preview:         newResult.Preview,
-				Tails:           newResult.Tails,
-				ContextData:     newResult.ContextData,
-				RefreshInterval: newResult.RefreshInterval,
+				Title:              newResult.Title,
+				SubTitle:           newResult.SubTitle,
+				AccessibilityLabel: newResult.AccessibilityLabel,
+				Icon:               newResult.Icon,
+				Preview:            newResult.Preview,
+				Tails:              newResult.Tails,
+				ContextData:        newResult.ContextData,
+				RefreshInterval:    newResult.RefreshInterval,
+				LastUpdated:        refreshableResult.LastUpdated,
 				Actions: lo.Map(newResult.Actions, func(action plugin.QueryResultActionUI, _ int) plugin.QueryResultAction {
 					return plugin.QueryResultAction{
 						Id:                     action.Id,
@@ -149,10 +171,13 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 						IsDefault:              action.IsDefault,
 						PreventHideAfterAction: action.PreventHideAfterAction,
 						Hotkey:                 action.Hotkey,
+						ActivationModifier:     action.ActivationModifier,
+						AllowRepeat:            action.AllowRepeat,
 						Action: func(ctx context.Context, actionContext plugin.ActionContext) {
 							_, actionErr := w.websocketHost.invokeMethod(ctx, w.metadata, "action", map[string]string{
-								"ActionId":    action.Id,
-								"ContextData": actionContext.ContextData,
+								"ActionId":       action.Id,
+								"ContextData":    actionContext.ContextData,
+								"TriggerKeyword": actionContext.TriggerKeyword,
 							})
 							if actionErr != nil {
 								util.GetLogger().Error(ctx, fmt.Sprintf("[%s] action failed: %s", w.metadata.Name, actionErr.Error()))
@@ -167,3 +192,17 @@ func (w *WebsocketPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 
 	return results
 }
+
+// newHostAction returns an action function which invokes the action with given id in plugin host
+func (w *WebsocketPlugin) newHostAction(actionId string) func(ctx context.Context, actionContext plugin.ActionContext) {
+	return func(ctx context.Context, actionContext plugin.ActionContext) {
+		_, actionErr := w.websocketHost.invokeMethod(ctx, w.metadata, "action", map[string]string{
+			"ActionId":       actionId,
+			"ContextData":    actionContext.ContextData,
+			"TriggerKeyword": actionContext.TriggerKeyword,
+		})
+		if actionErr != nil {
+			util.GetLogger().Error(ctx, fmt.Sprintf("[%s] action failed: %s", w.metadata.Name, actionErr.Error()))
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"image/png"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"wox/share"
 	"wox/util"
@@ -23,6 +24,14 @@ import (
 
 var localImageMap = util.NewHashMap[string, string]()
 
+// WoxImageResourceScheme references a resource bundled with plugin relative to its install directory (E.g. "res://icons/foo.png"),
+// so plugins keep working when they are moved. It's accepted anywhere a WoxImage is: as image string (see ParseWoxImage), as data of
+// an absolute path, relative path or url image, and as data of file previews. Missing resources are logged and replaced by missingResourceImage
+const WoxImageResourceScheme = "res://"
+
+// shown instead of a plugin resource which doesn't exist, see ConvertRelativePathToAbsolutePath
+var missingResourceImage = NewWoxImageEmoji("❓")
+
 type WoxImageType = string
 
 var notPngErr = errors.New("image is not png")
@@ -241,6 +250,15 @@ func NewWoxImage(image image.Image) (WoxImage, error) {
 	return NewWoxImageBase64(fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(buf.Bytes()))), nil
 }
 
+// NewWoxImageResource returns an image bundled with plugin, resourcePath is relative to plugin directory with or without
+// WoxImageResourceScheme, E.g. "res://icons/foo.png" or "icons/foo.png"
+func NewWoxImageResource(resourcePath string) WoxImage {
+	return WoxImage{
+		ImageType: WoxImageTypeRelativePath,
+		ImageData: strings.TrimPrefix(resourcePath, WoxImageResourceScheme),
+	}
+}
+
 func NewWoxImageUrl(url string) WoxImage {
 	return WoxImage{
 		ImageType: WoxImageTypeUrl,
@@ -288,6 +306,10 @@ func ParseWoxImageOrDefault(image string, defaultImage WoxImage) WoxImage {
 }
 
 func ParseWoxImage(image string) (WoxImage, error) {
+	if strings.HasPrefix(image, WoxImageResourceScheme) {
+		return NewWoxImageResource(image), nil
+	}
+
 	n := strings.SplitN(image, ":", 2)
 	if len(n) != 2 {
 		return WoxImage{}, fmt.Errorf("invalid image format: %s", image)
@@ -443,17 +465,67 @@ func cropTransparentPaddings(pngImg image.Image) image.Image {
 	return imaging.Crop(pngImg, image.Rect(minX, minY, maxX, maxY))
 }
 
+// ConvertRelativePathToAbsolutePath resolves an image bundled with plugin (relative path or WoxImageResourceScheme) against plugin directory.
+// If the resource doesn't exist or is outside of plugin directory, it's logged and missingResourceImage is returned instead
 func ConvertRelativePathToAbsolutePath(ctx context.Context, image WoxImage, pluginDirectory string) (newImage WoxImage) {
-	newImage = image
+	newImage = normalizeResourceImage(image)
 
-	if image.ImageType == WoxImageTypeRelativePath {
-		newImage.ImageType = WoxImageTypeAbsolutePath
-		newImage.ImageData = path.Join(pluginDirectory, image.ImageData)
+	if newImage.ImageType == WoxImageTypeRelativePath {
+		absolutePath, resolveErr := resolvePluginResource(pluginDirectory, newImage.ImageData)
+		if resolveErr != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to resolve image of plugin in %s, use fallback image: %s", pluginDirectory, resolveErr.Error()))
+			return missingResourceImage
+		}
+		newImage = NewWoxImageAbsolutePath(absolutePath)
 	}
 
 	return newImage
 }
 
+// normalizeResourceImage converts an image whose data uses WoxImageResourceScheme (E.g. a url image "res://icons/foo.png") to a relative path image
+func normalizeResourceImage(image WoxImage) WoxImage {
+	if image.ImageType != WoxImageTypeAbsolutePath && image.ImageType != WoxImageTypeRelativePath && image.ImageType != WoxImageTypeUrl {
+		return image
+	}
+	if !strings.HasPrefix(image.ImageData, WoxImageResourceScheme) {
+		return image
+	}
+	return NewWoxImageResource(image.ImageData)
+}
+
+// existing resources by plugin directory, resources are resolved for every result and bundled resources don't change while plugin is loaded.
+// Missing resources are not cached, so a resource added later is found. The cache of a plugin is dropped when it's unloaded, see clearPluginResourceCache
+var pluginResourceCache = util.NewHashMap[string, *util.HashMap[string, string]]()
+
+func clearPluginResourceCache(pluginDirectory string) {
+	pluginResourceCache.Delete(pluginDirectory)
+}
+
+// resolvePluginResource returns absolute path of a resource bundled with plugin, resourcePath is relative to plugin directory with or
+// without WoxImageResourceScheme. Resource must exist and must not escape plugin directory (E.g. "res://../other/foo.png")
+func resolvePluginResource(pluginDirectory string, resourcePath string) (string, error) {
+	resources, found := pluginResourceCache.Load(pluginDirectory)
+	if !found {
+		resources = util.NewHashMap[string, string]()
+		pluginResourceCache.Store(pluginDirectory, resources)
+	}
+	if absolutePath, exist := resources.Load(resourcePath); exist {
+		return absolutePath, nil
+	}
+
+	relativePath := filepath.FromSlash(strings.TrimPrefix(resourcePath, WoxImageResourceScheme))
+	absolutePath := filepath.Join(pluginDirectory, relativePath)
+	rel, relErr := filepath.Rel(pluginDirectory, absolutePath)
+	if relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("resource is outside of plugin directory: %s", resourcePath)
+	}
+	if _, statErr := os.Stat(absolutePath); statErr != nil {
+		return "", fmt.Errorf("resource not found: %s (%s)", resourcePath, absolutePath)
+	}
+	resources.Store(resourcePath, absolutePath)
+	return absolutePath, nil
+}
+
 func convertLocalImageToUrl(ctx context.Context, image WoxImage) (newImage WoxImage) {
 	newImage = image
 
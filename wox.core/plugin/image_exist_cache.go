@@ -0,0 +1,37 @@
+package plugin
+
+import (
+	"os"
+	"sync/atomic"
+	"wox/util"
+)
+
+const (
+	// image files rarely change, but results are validated on every keystroke, see validateWoxImage
+	imageExistCacheTTLMs = 5000
+	// the cache is dropped as a whole when it grows over this size, E.g. file search results with absolute path icons
+	imageExistCacheMaxEntries = 2000
+)
+
+type imageExistCacheItem struct {
+	exist     bool
+	timestamp int64
+}
+
+var imageExistCache = util.NewHashMap[string, imageExistCacheItem]()
+var imageExistCacheSize atomic.Int64
+
+// isImageFileExist returns true if the image file exists, the result is cached for imageExistCacheTTLMs
+func isImageFileExist(filePath string) bool {
+	now := util.GetSystemTimestamp()
+	if cached, found := imageExistCache.Load(filePath); found && now-cached.timestamp < imageExistCacheTTLMs {
+		return cached.exist
+	} else if !found && imageExistCacheSize.Add(1) > imageExistCacheMaxEntries {
+		imageExistCache.Clear()
+		imageExistCacheSize.Store(1)
+	}
+
+	_, statErr := os.Stat(filePath)
+	imageExistCache.Store(filePath, imageExistCacheItem{exist: statErr == nil, timestamp: now})
+	return statErr == nil
+}
@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"wox/util"
+	"wox/util/fileicon"
+
+	"github.com/disintegration/imaging"
+	"github.com/samber/lo"
+)
+
+// icons of files with these extensions differ by file (E.g. each app has its own icon), other files share the icon of their type
+var fileIconPerPathExtensions = []string{"", ".app", ".prefpane", ".exe", ".lnk", ".ico", ".url", ".appimage"}
+
+// resolved file icons by cache key, see fileIconCacheKey
+var fileIconCache = util.NewHashMap[string, WoxImage]()
+
+// NewWoxImageFromFilePath returns the native icon OS shows for the file at path, E.g. icon of an app bundle or icon of the file type.
+// Icons are cached, files of the same type share one icon unless their icons differ by file (E.g. apps and executables).
+// If file doesn't exist or OS can't provide an icon, a generic file icon is returned
+func NewWoxImageFromFilePath(filePath string) WoxImage {
+	if _, statErr := os.Stat(filePath); statErr != nil {
+		return PluginFileIcon
+	}
+
+	cacheKey := fileIconCacheKey(filePath)
+	if icon, found := fileIconCache.Load(cacheKey); found {
+		return icon
+	}
+
+	icon := resolveFileIcon(filePath, cacheKey)
+	fileIconCache.Store(cacheKey, icon)
+	return icon
+}
+
+func fileIconCacheKey(filePath string) string {
+	extension := strings.ToLower(filepath.Ext(filePath))
+	if lo.Contains(fileIconPerPathExtensions, extension) {
+		return "path:" + filePath
+	}
+	return "extension:" + extension
+}
+
+func resolveFileIcon(filePath string, cacheKey string) WoxImage {
+	iconPath := path.Join(util.GetLocation().GetImageCacheDirectory(), fmt.Sprintf("file_icon_%s.png", util.Md5([]byte(cacheKey))))
+	if _, statErr := os.Stat(iconPath); statErr == nil {
+		return NewWoxImageAbsolutePath(iconPath)
+	}
+
+	img, iconErr := fileicon.GetFileIcon(filePath)
+	if iconErr != nil && strings.HasPrefix(cacheKey, "extension:") {
+		img, iconErr = fileicon.GetFileTypeIcon(filepath.Ext(filePath))
+	}
+	if iconErr != nil {
+		logger.Debug(util.NewTraceContext(), fmt.Sprintf("failed to get file icon of %s: %s", filePath, iconErr.Error()))
+		return PluginFileIcon
+	}
+
+	if saveErr := imaging.Save(img, iconPath); saveErr != nil {
+		logger.Error(util.NewTraceContext(), fmt.Sprintf("failed to save file icon of %s: %s", filePath, saveErr.Error()))
+		if woxImage, err := NewWoxImage(img); err == nil {
+			return woxImage
+		}
+		return PluginFileIcon
+	}
+	return NewWoxImageAbsolutePath(iconPath)
+}
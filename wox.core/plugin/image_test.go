@@ -1,9 +1,13 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 	"wox/util"
 )
@@ -19,14 +23,12 @@ func TestWoxImage_ToImage(t *testing.T) {
 		return
 	}
 
-	util.GetLocation().Init()
 	path := fmt.Sprintf("%s/%s.png", util.GetLocation().GetImageCacheDirectory(), uuid.NewString())
 	imaging.Save(img, path)
 	t.Log(path)
 }
 
 func TestWoxImage_Emoji(t *testing.T) {
-	util.GetLocation().Init()
 	emojiImg := NewWoxImageEmoji("😀")
 	img, err := emojiImg.ToImage()
 	if err != nil {
@@ -38,3 +40,72 @@ func TestWoxImage_Emoji(t *testing.T) {
 	imaging.Save(img, path)
 	t.Log(path)
 }
+
+func TestParseWoxImage_Resource(t *testing.T) {
+	image, err := ParseWoxImage("res://icons/foo.png")
+	assert.Nil(t, err)
+	assert.Equal(t, WoxImage{ImageType: WoxImageTypeRelativePath, ImageData: "icons/foo.png"}, image)
+
+	// resource scheme in data of path and url images
+	assert.Equal(t, image, normalizeResourceImage(NewWoxImageUrl("res://icons/foo.png")))
+	assert.Equal(t, image, normalizeResourceImage(NewWoxImageAbsolutePath("res://icons/foo.png")))
+	assert.Equal(t, NewWoxImageUrl("https://example.com/foo.png"), normalizeResourceImage(NewWoxImageUrl("https://example.com/foo.png")))
+}
+
+func TestConvertRelativePathToAbsolutePath_Resource(t *testing.T) {
+	logger = util.GetLogger()
+	pluginDirectory := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(pluginDirectory, "icons"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(pluginDirectory, "icons", "foo.png"), []byte("png"), 0644))
+
+	ctx := context.Background()
+	expected := NewWoxImageAbsolutePath(filepath.Join(pluginDirectory, "icons", "foo.png"))
+	assert.Equal(t, expected, ConvertRelativePathToAbsolutePath(ctx, NewWoxImageResource("res://icons/foo.png"), pluginDirectory))
+	assert.Equal(t, expected, ConvertRelativePathToAbsolutePath(ctx, NewWoxImageUrl("res://icons/foo.png"), pluginDirectory))
+
+	// missing resources and resources outside of plugin directory fall back
+	assert.Equal(t, missingResourceImage, ConvertRelativePathToAbsolutePath(ctx, NewWoxImageResource("res://icons/bar.png"), pluginDirectory))
+	assert.Equal(t, missingResourceImage, ConvertRelativePathToAbsolutePath(ctx, NewWoxImageResource("res://../foo.png"), pluginDirectory))
+
+	// other images are untouched
+	assert.Equal(t, NewWoxImageEmoji("😀"), ConvertRelativePathToAbsolutePath(ctx, NewWoxImageEmoji("😀"), pluginDirectory))
+}
+
+func TestResolvePluginResource_Cache(t *testing.T) {
+	pluginDirectory := t.TempDir()
+	iconPath := filepath.Join(pluginDirectory, "icon.png")
+
+	// missing resources are not cached, a resource added later is found
+	_, err := resolvePluginResource(pluginDirectory, "res://icon.png")
+	assert.NotNil(t, err)
+	assert.Nil(t, os.WriteFile(iconPath, []byte("png"), 0644))
+	absolutePath, err := resolvePluginResource(pluginDirectory, "res://icon.png")
+	assert.Nil(t, err)
+	assert.Equal(t, iconPath, absolutePath)
+
+	// existing resources are not checked again until plugin is unloaded
+	assert.Nil(t, os.Remove(iconPath))
+	_, err = resolvePluginResource(pluginDirectory, "res://icon.png")
+	assert.Nil(t, err)
+	clearPluginResourceCache(pluginDirectory)
+	_, err = resolvePluginResource(pluginDirectory, "res://icon.png")
+	assert.NotNil(t, err)
+}
+
+func TestMetadata_GetIconOrDefault(t *testing.T) {
+	ctx := context.Background()
+	pluginDirectory := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(pluginDirectory, "icon.png"), []byte("png"), 0644))
+	defaultIcon := NewWoxImageEmoji("🧩")
+
+	for icon, expected := range map[string]WoxImage{
+		"":                   defaultIcon,
+		"relative:icon.png":  NewWoxImageAbsolutePath(filepath.Join(pluginDirectory, "icon.png")),
+		"relative:other.png": defaultIcon,
+		"unknown:icon.png":   defaultIcon,
+		"emoji:🚀":            NewWoxImageEmoji("🚀"),
+	} {
+		metadata := Metadata{Name: "icon test", Icon: icon}
+		assert.Equal(t, expected, metadata.GetIconOrDefault(ctx, pluginDirectory, defaultIcon), icon)
+	}
+}
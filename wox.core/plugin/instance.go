@@ -2,7 +2,15 @@ package plugin
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"wox/setting"
+	"wox/util"
+
+	"github.com/samber/lo"
 )
 
 type Instance struct {
@@ -21,6 +29,38 @@ type Instance struct {
 	DeepLinkCallbacks       []func(arguments map[string]string)
 	UnloadCallbacks         []func()
 
+	QuerySessionStartCallbacks []func() // invoked when Wox is shown, see Manager.StartQuerySession
+	QuerySessionEndCallbacks   []func() // invoked when Wox is hidden, see Manager.EndQuerySession
+
+	StartupCallbacks []func(ctx context.Context) error // invoked in background after plugin is initialized, see Manager.warmUpPlugin
+
+	SelectionHandlers   []SelectionHandler             // registered by API.RegisterSelectionHandler
+	SuggestionProviders []SuggestionProvider           // registered by API.RegisterSuggestionProvider
+	PreviewEnrichers    []PreviewEnricher              // registered by API.RegisterPreviewEnricher
+	QueryVariables      []registeredQueryVariable      // registered by API.RegisterQueryVariable
+	HeadlessActions     []HeadlessAction               // registered by API.RegisterHeadlessAction, guarded by headlessActionsLock
+	staticResults       atomic.Pointer[[]StaticResult] // registered by API.RegisterStaticResults
+
+	kvStore         *KVStore
+	kvStoreOnce     sync.Once
+	secretStore     *SecretStore
+	secretStoreOnce sync.Once
+	httpClient      *util.RateLimitedHTTPClient
+	httpClientOnce  sync.Once
+	icon            WoxImage
+	iconOnce        sync.Once
+
+	globalCommandPattern     *regexp.Regexp // nil if globalCommand feature is not enabled or invalid
+	globalCommandPatternOnce sync.Once
+
+	unloaded atomic.Bool // set when instance is unloaded, E.g. plugin is reloaded, see Manager.ReloadPlugin
+
+	warmUpStatus atomic.Pointer[PluginWarmUpStatus] // nil if plugin has no startup callbacks
+
+	initError atomic.Pointer[string] // nil if plugin is initialized, see Manager.setPluginInitError
+
+	headlessActionsLock sync.RWMutex // plugins may register headless actions while they are listed or executed
+
 	// for measure performance
 	LoadStartTimestamp    int64
 	LoadFinishedTimestamp int64
@@ -36,6 +76,73 @@ func (i *Instance) GetTriggerKeywords() []string {
 	return i.Metadata.TriggerKeywords
 }
 
+// IsSupportSelection returns true if plugin can handle selection queries
+func (i *Instance) IsSupportSelection() bool {
+	return i.Metadata.IsSupportFeature(MetadataFeatureQuerySelection) || len(i.SelectionHandlers) > 0
+}
+
+// MatchTriggerKeyword returns the trigger keyword of this plugin that matches given keyword.
+// Keyword is compared case-insensitively if user enabled it in plugin setting, the returned keyword is always the one defined by plugin or user.
+// A keyword with the same case wins over other case-insensitive matches (E.g. "Git" over "git")
+func (i *Instance) MatchTriggerKeyword(keyword string) (string, bool) {
+	triggerKeywords := i.GetTriggerKeywords()
+	if lo.Contains(triggerKeywords, keyword) {
+		return keyword, true
+	}
+	return lo.Find(triggerKeywords, func(triggerKeyword string) bool {
+		return i.equalsKeyword(triggerKeyword, keyword)
+	})
+}
+
+// MatchQueryCommand returns the query command of this plugin that matches given command, see MatchTriggerKeyword.
+// If user enabled prefix matching, a prefix of exactly one query command also matches
+func (i *Instance) MatchQueryCommand(command string) (MetadataCommand, bool) {
+	queryCommands := i.GetQueryCommands()
+	queryCommand, found := lo.Find(queryCommands, func(item MetadataCommand) bool {
+		return i.equalsKeyword(item.Command, command)
+	})
+	if found {
+		return queryCommand, true
+	}
+	// aliases are only matched exactly, after commands
+	queryCommand, found = lo.Find(queryCommands, func(item MetadataCommand) bool {
+		return lo.ContainsBy(item.Aliases, func(alias string) bool { return i.equalsKeyword(alias, command) })
+	})
+	if found {
+		return queryCommand, true
+	}
+
+	if i.Setting == nil || !i.Setting.PrefixQueryCommands || command == "" {
+		return MetadataCommand{}, false
+	}
+
+	prefixMatched := lo.UniqBy(lo.Filter(queryCommands, func(item MetadataCommand, _ int) bool {
+		return len(item.Command) >= len(command) && i.equalsKeyword(item.Command[:len(command)], command)
+	}), func(item MetadataCommand) string {
+		return item.Command
+	})
+	if len(prefixMatched) != 1 {
+		// no match or ambiguous prefix
+		return MetadataCommand{}, false
+	}
+	return prefixMatched[0], true
+}
+
+func (i *Instance) equalsKeyword(expected string, actual string) bool {
+	if i.Setting != nil && i.Setting.CaseInsensitiveTriggerKeywords {
+		return strings.EqualFold(expected, actual)
+	}
+	return expected == actual
+}
+
+// multiplier applied to scores of this plugin's results, 1.0 means no change
+func (i *Instance) GetScoreMultiplier() float64 {
+	if i.Setting.ScoreMultiplier <= 0 {
+		return 1.0
+	}
+	return i.Setting.ScoreMultiplier
+}
+
 // query commands to query this plugin. Maybe plugin author dynamical registered or pre-defined in plugin.json
 func (i *Instance) GetQueryCommands() []MetadataCommand {
 	commands := i.Metadata.Commands
@@ -48,10 +155,110 @@ func (i *Instance) GetQueryCommands() []MetadataCommand {
 	return commands
 }
 
+// Store returns the persistent key-value store of this plugin, see KVStore
+func (i *Instance) Store() *KVStore {
+	i.kvStoreOnce.Do(func() {
+		i.kvStore = newKVStore(i.Metadata.Id, i.Metadata.Name)
+	})
+	return i.kvStore
+}
+
+// Secrets returns the secret store of this plugin, see SecretStore
+func (i *Instance) Secrets() *SecretStore {
+	i.secretStoreOnce.Do(func() {
+		i.secretStore = newSecretStore(i.Metadata.Id, i.Metadata.Name)
+	})
+	return i.secretStore
+}
+
+func (i *Instance) GetStaticResults() []StaticResult {
+	staticResults := i.staticResults.Load()
+	if staticResults == nil {
+		return nil
+	}
+	return *staticResults
+}
+
+// HTTPClient returns the rate limited http client of this plugin, all requests sent by it share one limiter with default rate.
+// Plugins that call several services with different limits should create their own clients, see util.NewRateLimitedHTTPClient.
+// Requests fail unless plugin declared network permission and user granted it, see Manager.CheckPermission
+func (i *Instance) HTTPClient() *util.RateLimitedHTTPClient {
+	i.httpClientOnce.Do(func() {
+		i.httpClient = util.NewRateLimitedHTTPClient(nil).WithGuard(func(ctx context.Context) error {
+			return GetPluginManager().CheckPermission(ctx, i, MetadataPermissionNetwork)
+		})
+	})
+	return i.httpClient
+}
+
+// GetIcon returns the icon of this plugin with relative path resolved, it's resolved once and cached.
+// It's not converted through UI (see ConvertIcon), so it can be used before UI is ready
+func (i *Instance) GetIcon(ctx context.Context) WoxImage {
+	i.iconOnce.Do(func() {
+		i.icon = i.Metadata.GetIconOrDefault(ctx, i.PluginDirectory, DefaultActionIcon)
+	})
+	return i.icon
+}
+
 func (i *Instance) String() string {
 	return i.Metadata.Name
 }
 
+// GetWarmUpStatus returns state of startup callbacks of the plugin, nil if plugin has no startup callbacks or warm up is not started yet
+func (i *Instance) GetWarmUpStatus() *PluginWarmUpStatus {
+	return i.warmUpStatus.Load()
+}
+
 func (i *Instance) SaveSetting(ctx context.Context) error {
 	return setting.GetSettingManager().SavePluginSetting(ctx, i.Metadata.Id, i.Setting)
 }
+
+// parseGlobalCommand parses Command and Search of a global query by the pattern of globalCommand feature, see MetadataFeatureParamsGlobalCommand.
+// Query is returned unchanged if plugin doesn't enable the feature or query doesn't match
+func (i *Instance) parseGlobalCommand(ctx context.Context, query Query) Query {
+	i.globalCommandPatternOnce.Do(func() {
+		if !i.Metadata.IsSupportFeature(MetadataFeatureGlobalCommand) {
+			return
+		}
+		params, err := i.Metadata.GetFeatureParamsForGlobalCommand()
+		if err != nil {
+			logger.Error(ctx, fmt.Sprintf("<%s> invalid global command config: %s", i.Metadata.Name, err))
+			return
+		}
+		i.globalCommandPattern = params.Pattern
+	})
+	if i.globalCommandPattern == nil {
+		return query
+	}
+
+	match := i.globalCommandPattern.FindStringSubmatchIndex(query.Search)
+	if match == nil {
+		return query
+	}
+	commandIndex := i.globalCommandPattern.SubexpIndex("command")
+	if match[2*commandIndex] < 0 {
+		return query
+	}
+
+	command := query.Search[match[2*commandIndex]:match[2*commandIndex+1]]
+	search := strings.TrimSpace(query.Search[match[1]:])
+	if searchIndex := i.globalCommandPattern.SubexpIndex("search"); searchIndex >= 0 && match[2*searchIndex] >= 0 {
+		search = query.Search[match[2*searchIndex]:match[2*searchIndex+1]]
+	}
+
+	query.Command = command
+	query.Search = search
+	if matchedCommand, found := i.MatchQueryCommand(command); found {
+		query.Command = matchedCommand.Command
+		query.MatchedCommand = &matchedCommand
+		query.CommandInvocation = parseCommandInvocation(matchedCommand, search)
+	}
+	return query
+}
+
+// getHeadlessActions returns a copy of registered headless actions, see API.RegisterHeadlessAction
+func (i *Instance) getHeadlessActions() []HeadlessAction {
+	i.headlessActionsLock.RLock()
+	defer i.headlessActionsLock.RUnlock()
+	return append([]HeadlessAction(nil), i.HeadlessActions...)
+}
@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"wox/util"
+)
+
+// MaxKVStoreSize is the max size in bytes of the encoded storage of a plugin
+const MaxKVStoreSize = 1024 * 1024
+
+// KVStore is a persistent key-value store of a plugin, values are json encoded and saved to disk on every change.
+// Each plugin has its own store file named by plugin id, so keys of different plugins won't collide.
+//
+// If the store file is corrupted, it will be reset to empty (the corrupted file is kept with .corrupted suffix for inspection).
+// A change which makes the encoded store larger than MaxKVStoreSize is rejected.
+type KVStore struct {
+	pluginName string
+	path       string
+	lock       sync.Mutex
+	data       map[string]json.RawMessage // nil means not loaded yet
+}
+
+func newKVStore(pluginId string, pluginName string) *KVStore {
+	return &KVStore{
+		pluginName: pluginName,
+		path:       path.Join(util.GetLocation().GetPluginStorageDirectory(), fmt.Sprintf("%s.json", pluginId)),
+	}
+}
+
+// Get decodes value of key into v, returns false if key doesn't exist
+func (s *KVStore) Get(ctx context.Context, key string, v any) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.load(ctx)
+	raw, exist := s.data[key]
+	if !exist {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("failed to decode value of key %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set json encodes v and saves it to key
+func (s *KVStore) Set(ctx context.Context, key string, v any) error {
+	raw, marshalErr := json.Marshal(v)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to encode value of key %s: %w", key, marshalErr)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.load(ctx)
+	old, exist := s.data[key]
+	s.data[key] = raw
+	if saveErr := s.save(); saveErr != nil {
+		// rollback so memory is consistent with disk
+		if exist {
+			s.data[key] = old
+		} else {
+			delete(s.data, key)
+		}
+		return saveErr
+	}
+	return nil
+}
+
+func (s *KVStore) Delete(ctx context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.load(ctx)
+	if _, exist := s.data[key]; !exist {
+		return nil
+	}
+	delete(s.data, key)
+	return s.save()
+}
+
+func (s *KVStore) Keys(ctx context.Context) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.load(ctx)
+	var keys []string
+	for key := range s.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// export returns a copy of all json encoded values, used by plugin settings export
+func (s *KVStore) export(ctx context.Context) map[string]json.RawMessage {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.load(ctx)
+	data := make(map[string]json.RawMessage, len(s.data))
+	for key, value := range s.data {
+		data[key] = value
+	}
+	return data
+}
+
+func (s *KVStore) GetString(ctx context.Context, key string) string {
+	var v string
+	s.Get(ctx, key, &v)
+	return v
+}
+
+func (s *KVStore) SetString(ctx context.Context, key string, value string) error {
+	return s.Set(ctx, key, value)
+}
+
+func (s *KVStore) GetInt64(ctx context.Context, key string) int64 {
+	var v int64
+	s.Get(ctx, key, &v)
+	return v
+}
+
+func (s *KVStore) SetInt64(ctx context.Context, key string, value int64) error {
+	return s.Set(ctx, key, value)
+}
+
+func (s *KVStore) GetBool(ctx context.Context, key string) bool {
+	var v bool
+	s.Get(ctx, key, &v)
+	return v
+}
+
+func (s *KVStore) SetBool(ctx context.Context, key string, value bool) error {
+	return s.Set(ctx, key, value)
+}
+
+// load must be called with lock held
+func (s *KVStore) load(ctx context.Context) {
+	if s.data != nil {
+		return
+	}
+
+	s.data = map[string]json.RawMessage{}
+	content, readErr := os.ReadFile(s.path)
+	if readErr != nil {
+		if !os.IsNotExist(readErr) {
+			logger.Error(ctx, fmt.Sprintf("<%s> failed to read kv store: %s", s.pluginName, readErr.Error()))
+		}
+		return
+	}
+
+	if unmarshalErr := json.Unmarshal(content, &s.data); unmarshalErr != nil {
+		logger.Error(ctx, fmt.Sprintf("<%s> kv store is corrupted, reset to empty: %s", s.pluginName, unmarshalErr.Error()))
+		s.data = map[string]json.RawMessage{}
+		if renameErr := os.Rename(s.path, s.path+".corrupted"); renameErr != nil {
+			logger.Error(ctx, fmt.Sprintf("<%s> failed to backup corrupted kv store: %s", s.pluginName, renameErr.Error()))
+		}
+	}
+}
+
+// save must be called with lock held
+func (s *KVStore) save() error {
+	content, marshalErr := json.Marshal(s.data)
+	if marshalErr != nil {
+		return marshalErr
+	}
+	if len(content) > MaxKVStoreSize {
+		return fmt.Errorf("kv store size %d exceeds limit %d", len(content), MaxKVStoreSize)
+	}
+
+	// write to temp file first, so a crash during writing won't corrupt the store
+	tempPath := s.path + ".tmp"
+	if writeErr := os.WriteFile(tempPath, content, 0644); writeErr != nil {
+		return writeErr
+	}
+	return os.Rename(tempPath, s.path)
+}
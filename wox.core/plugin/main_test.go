@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"wox/setting"
+	"wox/util"
+)
+
+// TestMain runs tests with a temp home directory, so they never read or write ~/.wox of whoever runs them.
+// Location and setting manager are process wide singletons and are initialized only once here, re-running Init in
+// each test races with background work left by previous tests (E.g. recording actioned results)
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	home, err := os.MkdirTemp("", "wox-plugin-test")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create test home directory: %s\n", err)
+		return 1
+	}
+	defer os.RemoveAll(home)
+
+	// os.UserHomeDir reads USERPROFILE on windows
+	os.Setenv("HOME", home)
+	os.Setenv("USERPROFILE", home)
+	if err := util.GetLocation().Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to init location: %s\n", err)
+		return 1
+	}
+	if err := setting.GetSettingManager().Init(util.NewTraceContext()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to init setting manager: %s\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// newTestPluginInstance returns a system plugin instance with empty settings, so tests can polish and act on its results
+func newTestPluginInstance(metadata Metadata) *Instance {
+	return &Instance{
+		Metadata:       metadata,
+		Setting:        &setting.PluginSetting{Settings: util.NewHashMap[string, string]()},
+		IsSystemPlugin: true,
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"wox/util/selection"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
 	"github.com/samber/lo"
 	"github.com/wissance/stringFormatter"
@@ -39,11 +38,45 @@ type debounceTimer struct {
 }
 
 type Manager struct {
-	instances          []*Instance
+	instances          []*Instance // guarded by instancesLock when plugins are loaded or unloaded, see getInstances
+	instancesLock      sync.RWMutex
 	ui                 share.UI
 	resultCache        *util.HashMap[string, *QueryResultCache]
+	resultCacheSize    atomic.Int64 // estimated size in bytes of result cache, see storeResultCache
+	resultCacheEvict   resultCacheEvictor
 	debounceQueryTimer *util.HashMap[string, *debounceTimer]
 	aiProviders        *util.HashMap[ai.ProviderName, ai.Provider]
+	lastQueryStat      atomic.Pointer[queryStatCollector] // only available when query debug is enabled
+	querySessionActive atomic.Bool
+	refreshLimiter     *refreshLimiter
+	queryLimiter       *queryConcurrencyLimiter
+	previewStreamReads *queryConcurrencyLimiter // bounds concurrent reads of stream previews, see ReadResultPreviewStream
+	inflightQueries    map[string]*inflightQuery
+	inflightLock       sync.Mutex
+	resultUpdater      *resultUpdater
+	shownResults       shownResults
+	permissionRequests *util.HashMap[string, PermissionRequest]
+	pendingHandoff     atomic.Pointer[pendingHandoff]
+	pendingCarry       atomic.Pointer[pendingCarriedContext]
+	actionPreview      actionPreviewRunner
+	previewEnrich      actionPreviewRunner // only enrichers of the focused result are running, see GetEnrichedResultPreview
+	previewUpdater     previewUpdater
+	queryMiddlewares   []QueryMiddleware
+	resultMiddlewares  []ResultMiddleware
+	analytics          analytics
+	pendingPlugins     *util.HashMap[string, *util.HashMap[string, string]] // plugins not finished yet by inflight query key, plugin id => name
+	expandedResults    *util.HashMap[string, []string]                      // child result ids by expanded result id
+	runningActions     *util.HashMap[string, *runningAction]                // running cancellable actions by result id and action id
+	collapsedGroups    *util.HashMap[string, bool]                          // plugin ids of collapsed result groups, see SetResultGroupCollapsed
+	queryProgress      *util.HashMap[string, *queryProgressReporter]        // progress reporter of running query by plugin id, see ReportQueryProgress
+	suggestionSeq      atomic.Uint64                                        // used to debounce QuerySuggestions
+	middlewareLock     sync.RWMutex
+	reloadLock         sync.Mutex                       // only one reload at a time, see Reload
+	pluginErrors       pluginErrorTracker               // recent errors of each plugin, see DumpState
+	actionDedup        actionDeduplicator               // drops accidental repeated action invocations, see QueryResultAction.AllowRepeat
+	preloaded          atomic.Pointer[preloadedResults] // results for empty query of current query session, see preloadResults
+	keywordGrace       triggerKeywordGrace              // keeps the triggered plugin after its trigger keyword is deleted, see applyTriggerKeywordGrace
+	alternativeGroups  collapseGroupState               // collapse groups user expanded in current query session, see collapseResults
 
 	activeBrowserUrl string //active browser url before wox is activated
 }
@@ -52,8 +85,19 @@ func GetPluginManager() *Manager {
 	managerOnce.Do(func() {
 		managerInstance = &Manager{
 			resultCache:        util.NewHashMap[string, *QueryResultCache](),
+			expandedResults:    util.NewHashMap[string, []string](),
+			runningActions:     util.NewHashMap[string, *runningAction](),
+			collapsedGroups:    util.NewHashMap[string, bool](),
+			queryProgress:      util.NewHashMap[string, *queryProgressReporter](),
 			debounceQueryTimer: util.NewHashMap[string, *debounceTimer](),
 			aiProviders:        util.NewHashMap[ai.ProviderName, ai.Provider](),
+			refreshLimiter:     newRefreshLimiter(),
+			queryLimiter:       newQueryConcurrencyLimiter(),
+			previewStreamReads: newQueryConcurrencyLimiter(),
+			inflightQueries:    map[string]*inflightQuery{},
+			resultUpdater:      newResultUpdater(),
+			permissionRequests: util.NewHashMap[string, PermissionRequest](),
+			pendingPlugins:     util.NewHashMap[string, *util.HashMap[string, string]](),
 		}
 		logger = util.GetLogger()
 	})
@@ -71,6 +115,7 @@ func (m *Manager) Start(ctx context.Context, ui share.UI) error {
 	util.Go(ctx, "start store manager", func() {
 		GetStoreManager().Start(util.NewTraceContext())
 	})
+	m.startResultUpdater(ctx)
 
 	return nil
 }
@@ -91,11 +136,45 @@ func (m *Manager) loadPlugins(ctx context.Context) error {
 	// load system plugin first
 	m.loadSystemPlugins(ctx)
 
+	metaDataList, scanErr := m.scanUserPluginMetadata(ctx)
+	if scanErr != nil {
+		return scanErr
+	}
+	logger.Info(ctx, fmt.Sprintf("start loading user plugins, found %d user plugins", len(metaDataList)))
+
+	for _, host := range AllHosts {
+		util.Go(ctx, fmt.Sprintf("[%s] start host", host.GetRuntime(ctx)), func() {
+			newCtx := util.NewTraceContext()
+			hostErr := host.Start(newCtx)
+			if hostErr != nil {
+				logger.Error(newCtx, fmt.Errorf("[%s HOST] %w", host.GetRuntime(newCtx), hostErr).Error())
+				return
+			}
+
+			for _, metadata := range metaDataList {
+				if strings.ToUpper(metadata.Metadata.Runtime) != strings.ToUpper(string(host.GetRuntime(newCtx))) {
+					continue
+				}
+
+				loadErr := m.loadHostPlugin(newCtx, host, metadata)
+				if loadErr != nil {
+					logger.Error(newCtx, fmt.Errorf("[%s HOST] %w", host.GetRuntime(newCtx), loadErr).Error())
+					continue
+				}
+			}
+		})
+	}
+
+	return nil
+}
+
+// scanUserPluginMetadata parses metadata of all user plugins in plugin directory, only newest version is kept if a plugin is installed multiple times
+func (m *Manager) scanUserPluginMetadata(ctx context.Context) ([]MetadataWithDirectory, error) {
 	logger.Debug(ctx, "start loading user plugin metadata")
 	basePluginDirectory := util.GetLocation().GetPluginDirectory()
 	pluginDirectories, readErr := os.ReadDir(basePluginDirectory)
 	if readErr != nil {
-		return fmt.Errorf("failed to read plugin directory: %w", readErr)
+		return nil, fmt.Errorf("failed to read plugin directory: %w", readErr)
 	}
 
 	var metaDataList []MetadataWithDirectory
@@ -140,36 +219,62 @@ func (m *Manager) loadPlugins(ctx context.Context) error {
 		}
 		metaDataList = append(metaDataList, MetadataWithDirectory{Metadata: metadata, Directory: pluginDirectory})
 	}
-	logger.Info(ctx, fmt.Sprintf("start loading user plugins, found %d user plugins", len(metaDataList)))
 
-	for _, host := range AllHosts {
-		util.Go(ctx, fmt.Sprintf("[%s] start host", host.GetRuntime(ctx)), func() {
-			newCtx := util.NewTraceContext()
-			hostErr := host.Start(newCtx)
-			if hostErr != nil {
-				logger.Error(newCtx, fmt.Errorf("[%s HOST] %w", host.GetRuntime(newCtx), hostErr).Error())
-				return
-			}
+	return metaDataList, nil
+}
 
-			for _, metadata := range metaDataList {
-				if strings.ToUpper(metadata.Metadata.Runtime) != strings.ToUpper(string(host.GetRuntime(newCtx))) {
-					continue
-				}
+// ReloadPlugin tears down the loaded instance of given user plugin and loads it again from its directory,
+// other plugins are not touched. See ReloadPluginWithMetadata for what happens to in-flight queries and cached results
+func (m *Manager) ReloadPlugin(ctx context.Context, pluginId string) error {
+	pluginInstance, exist := lo.Find(m.getInstances(), func(item *Instance) bool {
+		return item.Metadata.Id == pluginId
+	})
+	if !exist {
+		return fmt.Errorf("plugin is not loaded: %s", pluginId)
+	}
+	if pluginInstance.IsSystemPlugin {
+		return fmt.Errorf("system plugin can't be reloaded: %s", pluginInstance.Metadata.Name)
+	}
 
-				loadErr := m.loadHostPlugin(newCtx, host, metadata)
-				if loadErr != nil {
-					logger.Error(newCtx, fmt.Errorf("[%s HOST] %w", host.GetRuntime(newCtx), loadErr).Error())
-					continue
-				}
-			}
+	metadata, parseErr := m.ParseMetadata(ctx, pluginInstance.PluginDirectory)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	return m.ReloadPluginWithMetadata(ctx, MetadataWithDirectory{
+		Metadata:           metadata,
+		Directory:          pluginInstance.PluginDirectory,
+		IsDev:              pluginInstance.IsDevPlugin,
+		DevPluginDirectory: pluginInstance.DevPluginDirectory,
+	})
+}
+
+// EnablePlugin clears disabled flag of the plugin and saves it. Host plugins which were disabled when Wox started are loaded
+// without init, so they are initialized here, otherwise they would never answer queries until Wox restarts
+func (m *Manager) EnablePlugin(ctx context.Context, pluginInstance *Instance) error {
+	if !pluginInstance.Setting.Disabled {
+		return nil
+	}
+
+	pluginInstance.Setting.Disabled = false
+	if err := pluginInstance.SaveSetting(ctx); err != nil {
+		return err
+	}
+
+	if pluginInstance.InitStartTimestamp == 0 {
+		util.Go(ctx, fmt.Sprintf("[%s] init enabled plugin", pluginInstance.Metadata.Name), func() {
+			m.initPlugin(ctx, pluginInstance)
 		})
 	}
 
 	return nil
 }
 
-func (m *Manager) ReloadPlugin(ctx context.Context, metadata MetadataWithDirectory) error {
-	logger.Info(ctx, fmt.Sprintf("start reloading dev plugin: %s", metadata.Metadata.Name))
+// ReloadPluginWithMetadata unloads the plugin if it's loaded, then loads it with given metadata.
+// Queries still waiting for the old instance are not cancelled, but their results are dropped once returned,
+// and cached results of the old instance are removed, so their actions can't be executed anymore
+func (m *Manager) ReloadPluginWithMetadata(ctx context.Context, metadata MetadataWithDirectory) error {
+	logger.Info(ctx, fmt.Sprintf("start reloading plugin: %s", metadata.Metadata.Name))
 
 	pluginHost, exist := lo.Find(AllHosts, func(item Host) bool {
 		return strings.ToLower(string(item.GetRuntime(ctx))) == strings.ToLower(metadata.Metadata.Runtime)
@@ -178,12 +283,13 @@ func (m *Manager) ReloadPlugin(ctx context.Context, metadata MetadataWithDirecto
 		return fmt.Errorf("unsupported runtime: %s", metadata.Metadata.Runtime)
 	}
 
-	pluginInstance, pluginInstanceExist := lo.Find(m.instances, func(item *Instance) bool {
+	pluginInstance, pluginInstanceExist := lo.Find(m.getInstances(), func(item *Instance) bool {
 		return item.Metadata.Id == metadata.Metadata.Id
 	})
 	if pluginInstanceExist {
 		logger.Info(ctx, fmt.Sprintf("plugin(%s) is loaded, unload first", metadata.Metadata.Name))
 		m.UnloadPlugin(ctx, pluginInstance)
+		m.removePluginResultCache(pluginInstance)
 	} else {
 		logger.Info(ctx, fmt.Sprintf("plugin(%s) is not loaded, skip unload", metadata.Metadata.Name))
 	}
@@ -201,6 +307,7 @@ func (m *Manager) loadHostPlugin(ctx context.Context, host Host, metadata Metada
 	plugin, loadErr := host.LoadPlugin(ctx, metadata.Metadata, metadata.Directory)
 	if loadErr != nil {
 		logger.Error(ctx, fmt.Errorf("[%s HOST] failed to load plugin: %w", host.GetRuntime(ctx), loadErr).Error())
+		m.pluginErrors.add(metadata.Metadata.Id, metadata.Metadata.Name, PluginErrorKindLoad, fmt.Sprintf("load failed: %s", loadErr.Error()))
 		return loadErr
 	}
 	loadFinishTimestamp := util.GetSystemTimestamp()
@@ -223,7 +330,7 @@ func (m *Manager) loadHostPlugin(ctx context.Context, host Host, metadata Metada
 	}
 	instance.Setting = pluginSetting
 
-	m.instances = append(m.instances, instance)
+	m.addInstance(instance)
 
 	if pluginSetting.Disabled {
 		logger.Info(ctx, fmt.Errorf("[%s HOST] plugin is disabled by user, skip init: %s", host.GetRuntime(ctx), metadata.Metadata.Name).Error())
@@ -260,11 +367,22 @@ func (m *Manager) LoadPlugin(ctx context.Context, pluginDirectory string) error
 }
 
 func (m *Manager) UnloadPlugin(ctx context.Context, pluginInstance *Instance) {
+	pluginInstance.unloaded.Store(true)
+	m.cancelPluginActions(ctx, pluginInstance)
+	if v, ok := m.debounceQueryTimer.Load(pluginInstance.Metadata.Id); ok {
+		if v.timer.Stop() {
+			v.onStop()
+		}
+	}
+
 	for _, callback := range pluginInstance.UnloadCallbacks {
 		callback()
 	}
 	pluginInstance.Host.UnloadPlugin(ctx, pluginInstance.Metadata)
+	clearPluginResourceCache(pluginInstance.PluginDirectory)
 
+	m.instancesLock.Lock()
+	defer m.instancesLock.Unlock()
 	var newInstances []*Instance
 	for _, instance := range m.instances {
 		if instance.Metadata.Id != pluginInstance.Metadata.Id {
@@ -274,6 +392,19 @@ func (m *Manager) UnloadPlugin(ctx context.Context, pluginInstance *Instance) {
 	m.instances = newInstances
 }
 
+func (m *Manager) addInstance(instance *Instance) {
+	m.instancesLock.Lock()
+	defer m.instancesLock.Unlock()
+	m.instances = append(m.instances, instance)
+}
+
+// getInstances returns a snapshot of loaded plugin instances, it's safe to use while plugins are loaded or unloaded
+func (m *Manager) getInstances() []*Instance {
+	m.instancesLock.RLock()
+	defer m.instancesLock.RUnlock()
+	return append([]*Instance(nil), m.instances...)
+}
+
 func (m *Manager) loadSystemPlugins(ctx context.Context) {
 	start := util.GetSystemTimestamp()
 	logger.Info(ctx, fmt.Sprintf("start loading system plugins, found %d system plugins", len(AllSystemPlugin)))
@@ -306,7 +437,7 @@ func (m *Manager) loadSystemPlugins(ctx context.Context) {
 				logger.Warn(ctx, fmt.Sprintf("load system plugin[%s] setting too slow, cost %d ms", metadata.Name, util.GetSystemTimestamp()-startTimestamp))
 			}
 
-			m.instances = append(m.instances, instance)
+			m.addInstance(instance)
 
 			m.initPlugin(util.NewTraceContext(), instance)
 		})
@@ -318,12 +449,25 @@ func (m *Manager) loadSystemPlugins(ctx context.Context) {
 func (m *Manager) initPlugin(ctx context.Context, instance *Instance) {
 	logger.Info(ctx, fmt.Sprintf("start init plugin: %s", instance.Metadata.Name))
 	instance.InitStartTimestamp = util.GetSystemTimestamp()
+	// errors of previous init are cleared, plugin reports them again if it still can't initialize, see API.ReportInitError
+	m.setPluginInitError(ctx, instance, nil)
+	m.invokePluginInit(ctx, instance)
+	instance.InitFinishedTimestamp = util.GetSystemTimestamp()
+	logger.Info(ctx, fmt.Sprintf("init plugin %s finished, cost %d ms", instance.Metadata.Name, instance.InitFinishedTimestamp-instance.InitStartTimestamp))
+
+	// warm up in background, a slow plugin won't block Wox from being usable
+	m.warmUpPlugin(ctx, instance)
+}
+
+func (m *Manager) invokePluginInit(ctx context.Context, instance *Instance) {
+	defer util.GoRecover(ctx, fmt.Sprintf("<%s> init panic", instance.Metadata.Name), func(err error) {
+		m.setPluginInitError(ctx, instance, fmt.Errorf("init panic: %w", err))
+	})
+
 	instance.Plugin.Init(ctx, InitParams{
 		API:             instance.API,
 		PluginDirectory: instance.PluginDirectory,
 	})
-	instance.InitFinishedTimestamp = util.GetSystemTimestamp()
-	logger.Info(ctx, fmt.Sprintf("init plugin %s finished, cost %d ms", instance.Metadata.Name, instance.InitFinishedTimestamp-instance.InitStartTimestamp))
 }
 
 func (m *Manager) ParseMetadata(ctx context.Context, pluginDirectory string) (Metadata, error) {
@@ -357,7 +501,7 @@ func (m *Manager) ParseMetadata(ctx context.Context, pluginDirectory string) (Me
 }
 
 func (m *Manager) GetPluginInstances() []*Instance {
-	return m.instances
+	return m.getInstances()
 }
 
 func (m *Manager) canOperateQuery(ctx context.Context, pluginInstance *Instance, query Query) bool {
@@ -366,8 +510,29 @@ func (m *Manager) canOperateQuery(ctx context.Context, pluginInstance *Instance,
 	}
 
 	if query.Type == QueryTypeSelection {
-		isPluginSupportSelection := pluginInstance.Metadata.IsSupportFeature(MetadataFeatureQuerySelection)
-		return isPluginSupportSelection
+		if !pluginInstance.IsSupportSelection() {
+			return false
+		}
+		// selection query scoped by trigger keyword, see newQuerySelectionWithPlugins
+		if query.TriggerKeyword != "" && !lo.Contains(pluginInstance.GetTriggerKeywords(), query.TriggerKeyword) {
+			return false
+		}
+		if query.Handoff != nil && query.Handoff.TargetPluginId != "" && query.Handoff.TargetPluginId != pluginInstance.Metadata.Id {
+			return false
+		}
+		// plugins declared selection permission need user's grant, others are allowed for backward compatibility
+		if pluginInstance.Metadata.IsDeclarePermission(MetadataPermissionSelection) {
+			if permissionErr := m.CheckPermission(ctx, pluginInstance, MetadataPermissionSelection); permissionErr != nil {
+				logger.Info(ctx, fmt.Sprintf("<%s> skip selection query: %s", pluginInstance.Metadata.Name, permissionErr.Error()))
+				return false
+			}
+		}
+		return true
+	}
+
+	// only plugins opted in get empty queries, regardless of their trigger keywords
+	if query.IsEmptyInput() {
+		return pluginInstance.Metadata.IsSupportFeature(MetadataFeatureEmptyQuery)
 	}
 
 	var validGlobalQuery = lo.Contains(pluginInstance.GetTriggerKeywords(), "*") && query.TriggerKeyword == ""
@@ -376,10 +541,27 @@ func (m *Manager) canOperateQuery(ctx context.Context, pluginInstance *Instance,
 		return false
 	}
 
+	// plugins failed to initialize only answer queries triggered by their keyword, with an error result, see getResultForInitError
+	if query.TriggerKeyword == "" && pluginInstance.GetInitError() != "" {
+		logger.Debug(ctx, fmt.Sprintf("<%s> skip global query, plugin failed to initialize", pluginInstance.Metadata.Name))
+		return false
+	}
+
+	if isSearchTooShort(ctx, pluginInstance, query) {
+		logger.Debug(ctx, fmt.Sprintf("<%s> skip query, search is shorter than min search length: %s", pluginInstance.Metadata.Name, query.Search))
+		return false
+	}
+
 	return true
 }
 
 func (m *Manager) queryForPlugin(ctx context.Context, pluginInstance *Instance, query Query) (results []QueryResult) {
+	return m.queryForPluginStreaming(ctx, pluginInstance, query, nil)
+}
+
+// queryForPluginStreaming queries the plugin, onBatch receives processed results flushed by a ResultSinkQuerier while it's still querying,
+// the rest of results is returned. All results are returned if onBatch is nil
+func (m *Manager) queryForPluginStreaming(ctx context.Context, pluginInstance *Instance, query Query, onBatch func(results []QueryResult)) (results []QueryResult) {
 	defer util.GoRecover(ctx, fmt.Sprintf("<%s> query panic", pluginInstance.Metadata.Name), func(err error) {
 		// if plugin query panic, return error result
 		failedResult := m.GetResultForFailedQuery(ctx, pluginInstance.Metadata, query, err)
@@ -391,6 +573,17 @@ func (m *Manager) queryForPlugin(ctx context.Context, pluginInstance *Instance,
 	logger.Info(ctx, fmt.Sprintf("<%s> start query: %s", pluginInstance.Metadata.Name, query.RawQuery))
 	start := util.GetSystemTimestamp()
 
+	if initError := pluginInstance.GetInitError(); initError != "" {
+		if query.TriggerKeyword == "" {
+			return nil
+		}
+		return []QueryResult{m.PolishResult(ctx, pluginInstance, query, m.getResultForInitError(ctx, pluginInstance, initError))}
+	}
+
+	if query.IsGlobalQuery() && query.Command == "" {
+		query = pluginInstance.parseGlobalCommand(ctx, query)
+	}
+
 	// set query env base on plugin's feature
 	currentEnv := query.Env
 	newEnv := QueryEnv{}
@@ -412,37 +605,109 @@ func (m *Manager) queryForPlugin(ctx context.Context, pluginInstance *Instance,
 	}
 	query.Env = newEnv
 
-	results = pluginInstance.Plugin.Query(ctx, query)
+	if query.Type == QueryTypeSelection && query.Selection.Type == selection.SelectionTypeText && pluginInstance.Metadata.IsSupportFeature(MetadataFeatureDetectSelectionLanguage) {
+		query.SelectionLanguage = util.DetectLanguage(query.Selection.Text)
+	}
+
+	if query.IsLiteralMatch {
+		ctx = util.NewLiteralMatchContext(ctx)
+	}
+	if pluginInstance.Metadata.IsSupportFeature(MetadataFeatureMatchSensitivity) {
+		if params, err := pluginInstance.Metadata.GetFeatureParamsForMatchSensitivity(); err == nil {
+			ctx = util.NewMatchOptionsContext(ctx, util.MatchOptions{Sensitivity: params.Sensitivity, MinScore: params.MinScore})
+		} else {
+			logger.Error(ctx, fmt.Sprintf("<%s> invalid matchSensitivity feature params: %s", pluginInstance.Metadata.Name, err.Error()))
+		}
+	}
+	queryCtx, releaseQuerySlot, acquireErr := m.queryLimiter.acquire(ctx, pluginInstance.Metadata.Id, setting.GetSettingManager().GetWoxSetting(ctx).MaxConcurrentQueriesPerPlugin)
+	if acquireErr != nil {
+		logger.Debug(ctx, fmt.Sprintf("<%s> skip query: %s", pluginInstance.Metadata.Name, acquireErr.Error()))
+		return nil
+	}
+	defer releaseQuerySlot()
+	var onPluginBatch func(batch []QueryResult)
+	if onBatch != nil {
+		onPluginBatch = func(batch []QueryResult) {
+			if processed := m.processQueryResults(ctx, pluginInstance, query, batch, false); len(processed) > 0 {
+				onBatch(processed)
+			}
+		}
+	}
+	finishProgress := m.startQueryProgress(ctx, pluginInstance)
+	results = m.invokePluginQuery(queryCtx, pluginInstance, query, onPluginBatch)
+	finishProgress()
 	logger.Debug(ctx, fmt.Sprintf("<%s> finish query, result count: %d, cost: %dms", pluginInstance.Metadata.Name, len(results), util.GetSystemTimestamp()-start))
 
+	return m.processQueryResults(ctx, pluginInstance, query, results, true)
+}
+
+// processQueryResults validates and polishes results returned by plugin. Streamed results are processed batch by batch (see ResultSinkQuerier),
+// isLastBatch is false for batches flushed while plugin is still querying
+func (m *Manager) processQueryResults(ctx context.Context, pluginInstance *Instance, query Query, results []QueryResult, isLastBatch bool) []QueryResult {
+	// user asked to hide results of this plugin for this query, see setting.SuppressedQuery
+	if query.Type == QueryTypeInput && len(results) > 0 && setting.GetSettingManager().IsQuerySuppressed(ctx, pluginInstance.Metadata.Id, query.RawQuery) {
+		logger.Debug(ctx, fmt.Sprintf("<%s> results are suppressed for query: %s", pluginInstance.Metadata.Name, query.RawQuery))
+		return nil
+	}
+
+	results = m.validateQueryResults(ctx, pluginInstance, query, results)
+	results = m.collapseResults(ctx, pluginInstance, query, results)
+
 	for i := range results {
-		if results[i].Group == "" {
-			defaultActions := m.getDefaultActions(ctx, pluginInstance, query, results[i].Title, results[i].SubTitle)
-			results[i].Actions = append(results[i].Actions, defaultActions...)
-		}
-		results[i] = m.PolishResult(ctx, pluginInstance, query, results[i])
+		results[i] = m.prepareQueryResult(ctx, pluginInstance, query, results[i])
 	}
 
 	if query.Type == QueryTypeSelection && query.Search != "" {
 		woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
 		results = lo.Filter(results, func(item QueryResult, _ int) bool {
-			match, _ := util.IsStringMatchScore(item.Title, query.Search, woxSetting.UsePinYin)
+			if query.IsLiteralMatch {
+				match, _ := util.IsStringMatchScoreLiteral(item.Title, query.Search)
+				return match
+			}
+			match, _ := util.IsStringMatchScoreWithOptions(item.Title, query.Search, woxSetting.UsePinYin, util.GetMatchOptionsContext(ctx))
 			return match
 		})
 	}
 
+	if sorter, ok := pluginInstance.Plugin.(ResultSorter); ok {
+		sortPluginResults(ctx, pluginInstance, sorter, results)
+	} else if pluginInstance.Metadata.IsSupportFeature(MetadataFeaturePreserveOrder) {
+		preserveResultOrder(results)
+	}
+
+	if isLastBatch && query.UnknownCommand != "" {
+		if suggestion, found := m.getUnknownCommandSuggestion(ctx, pluginInstance, query, results); found {
+			results = append(results, suggestion)
+		}
+	}
+
+	if query.mixedWithGlobal {
+		m.promoteTriggeredResults(ctx, pluginInstance, results)
+	}
+
 	return results
 }
 
+// prepareQueryResult adds system actions to a result returned by plugin and polishes it
+func (m *Manager) prepareQueryResult(ctx context.Context, pluginInstance *Instance, query Query, result QueryResult) QueryResult {
+	if result.Group == "" {
+		defaultActions := m.getDefaultActions(ctx, pluginInstance, query, result.Id, result.Title, result.SubTitle, result.ContextData)
+		result.Actions = append(result.Actions, defaultActions...)
+	}
+	if !result.DisableGlobalActions {
+		globalActions := m.getGlobalActions(ctx, pluginInstance, result.Title, result.Actions)
+		result.Actions = append(result.Actions, globalActions...)
+	}
+	return m.PolishResult(ctx, pluginInstance, query, result)
+}
+
 func (m *Manager) GetResultForFailedQuery(ctx context.Context, pluginMetadata Metadata, query Query, err error) QueryResult {
-	overlayIcon := NewWoxImageEmoji("🚫")
-	pluginIcon := ParseWoxImageOrDefault(pluginMetadata.Icon, overlayIcon)
-	icon := pluginIcon.OverlayFullPercentage(overlayIcon, 0.6)
+	m.pluginErrors.add(pluginMetadata.Id, pluginMetadata.Name, PluginErrorKindQuery, fmt.Sprintf("query failed: %s", err.Error()))
 
 	return QueryResult{
 		Title:    fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_manager_query_failed"), pluginMetadata.Name),
 		SubTitle: util.EllipsisEnd(err.Error(), 20),
-		Icon:     icon,
+		Icon:     getFailedResultIcon(pluginMetadata),
 		Preview: WoxPreview{
 			PreviewType: WoxPreviewTypeText,
 			PreviewData: err.Error(),
@@ -450,14 +715,35 @@ func (m *Manager) GetResultForFailedQuery(ctx context.Context, pluginMetadata Me
 	}
 }
 
-func (m *Manager) getDefaultActions(ctx context.Context, pluginInstance *Instance, query Query, title, subTitle string) (defaultActions []QueryResultAction) {
-	if setting.GetSettingManager().IsFavoriteResult(ctx, pluginInstance.Metadata.Id, title, subTitle) {
+// getFailedResultIcon returns plugin icon with an overlay telling something went wrong
+func getFailedResultIcon(pluginMetadata Metadata) WoxImage {
+	overlayIcon := NewWoxImageEmoji("🚫")
+	pluginIcon := ParseWoxImageOrDefault(pluginMetadata.Icon, overlayIcon)
+	return pluginIcon.OverlayFullPercentage(overlayIcon, 0.6)
+}
+
+func (m *Manager) getDefaultActions(ctx context.Context, pluginInstance *Instance, query Query, resultId, title, subTitle, contextData string) (defaultActions []QueryResultAction) {
+	favoriteResult := setting.FavoriteResult{
+		PluginId:       pluginInstance.Metadata.Id,
+		ResultId:       resultId,
+		ResultTitle:    title,
+		ResultSubTitle: subTitle,
+		ContextData:    contextData,
+	}
+	if query.Type == QueryTypeInput {
+		favoriteResult.Query = share.PlainQuery{
+			QueryType: QueryTypeInput,
+			QueryText: query.RawQuery,
+		}
+	}
+
+	if setting.GetSettingManager().MatchFavoriteResult(ctx, favoriteResult) {
 		defaultActions = append(defaultActions, QueryResultAction{
 			Name:           "i18n:plugin_manager_remove_from_favorite",
 			Icon:           RemoveFromFavIcon,
 			IsSystemAction: true,
 			Action: func(ctx context.Context, actionContext ActionContext) {
-				setting.GetSettingManager().RemoveFavoriteResult(ctx, pluginInstance.Metadata.Id, title, subTitle)
+				setting.GetSettingManager().RemoveFavoriteResult(ctx, favoriteResult)
 			},
 		})
 	} else {
@@ -466,7 +752,23 @@ func (m *Manager) getDefaultActions(ctx context.Context, pluginInstance *Instanc
 			Icon:           AddToFavIcon,
 			IsSystemAction: true,
 			Action: func(ctx context.Context, actionContext ActionContext) {
-				setting.GetSettingManager().AddFavoriteResult(ctx, pluginInstance.Metadata.Id, title, subTitle)
+				setting.GetSettingManager().AddFavoriteResult(ctx, favoriteResult)
+			},
+		})
+	}
+
+	// only offered in global queries, where results of plugins the user didn't ask for can get in the way.
+	// Selection queries can't be suppressed because there is no query text to match
+	if query.IsGlobalQuery() && strings.TrimSpace(query.RawQuery) != "" {
+		defaultActions = append(defaultActions, QueryResultAction{
+			Name:           "i18n:plugin_manager_suppress_plugin_results",
+			Icon:           HideIcon,
+			IsSystemAction: true,
+			Action: func(ctx context.Context, actionContext ActionContext) {
+				suppressErr := setting.GetSettingManager().AddSuppressedQuery(ctx, pluginInstance.Metadata.Id, query.RawQuery, setting.SuppressedQueryScopeExact)
+				if suppressErr != nil {
+					logger.Error(ctx, fmt.Sprintf("<%s> failed to suppress results for query %s: %s", pluginInstance.Metadata.Name, query.RawQuery, suppressErr.Error()))
+				}
 			},
 		})
 	}
@@ -474,18 +776,35 @@ func (m *Manager) getDefaultActions(ctx context.Context, pluginInstance *Instanc
 	return defaultActions
 }
 
+func (m *Manager) translateAction(ctx context.Context, pluginInstance *Instance, action QueryResultAction) QueryResultAction {
+	action.Name = m.translatePlugin(ctx, pluginInstance, action.Name)
+	if action.Input != nil {
+		// input may be shared by results, don't translate it in place
+		input := *action.Input
+		input.Prompt = m.translatePlugin(ctx, pluginInstance, input.Prompt)
+		input.Placeholder = m.translatePlugin(ctx, pluginInstance, input.Placeholder)
+		action.Input = &input
+	}
+	return action
+}
+
 func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, query Query, result QueryResult) QueryResult {
-	// set default id
+	// set default id, derived from result content so the same result keeps its id across queries, see newStableResultId
 	if result.Id == "" {
-		result.Id = uuid.NewString()
+		result.Id = m.newStableResultId(pluginInstance.Metadata.Id, result.Title, result.ContextData)
+	}
+	result.Actions = m.applyResultURL(ctx, pluginInstance, result.URL, result.DefaultActionId != "", result.Actions)
+	if result.SetQueryOnSelect != "" {
+		result.Actions = applySetQueryOnSelect(result.SetQueryOnSelect, result.Actions)
+		result.DefaultActionId = ""
 	}
+	assignStableActionIds(result.Actions)
 	for actionIndex := range result.Actions {
-		if result.Actions[actionIndex].Id == "" {
-			result.Actions[actionIndex].Id = uuid.NewString()
-		}
 		if result.Actions[actionIndex].Icon.IsEmpty() {
 			// set default action icon if not present
 			result.Actions[actionIndex].Icon = DefaultActionIcon
+		} else {
+			result.Actions[actionIndex].Icon = ConvertRelativePathToAbsolutePath(ctx, result.Actions[actionIndex].Icon, pluginInstance.PluginDirectory)
 		}
 	}
 
@@ -513,10 +832,30 @@ func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, qu
 		}
 	}
 
-	// translate title
+	// translate title and subtitle, highlights are computed against the original text so they are meaningless after translation
+	if strings.HasPrefix(result.Title, "i18n:") {
+		result.TitleHighlights = nil
+	}
 	result.Title = m.translatePlugin(ctx, pluginInstance, result.Title)
-	// translate subtitle
+	result.TitleHighlights = normalizeHighlights(result.TitleHighlights, result.Title)
+	if strings.HasPrefix(result.SubTitle, "i18n:") {
+		result.SubTitleHighlights = nil
+	}
 	result.SubTitle = m.translatePlugin(ctx, pluginInstance, result.SubTitle)
+	result.SubTitleHighlights = normalizeHighlights(result.SubTitleHighlights, result.SubTitle)
+	if result.MatchReason != "" {
+		result.MatchReason = m.translatePlugin(ctx, pluginInstance, result.MatchReason)
+	} else {
+		result.MatchReason = getMatchReason(ctx, result.Title, result.TitleHighlights, result.SubTitle, result.SubTitleHighlights)
+	}
+	result.TitleTruncation = m.normalizeTruncation(ctx, pluginInstance, result.TitleTruncation)
+	result.SubTitleTruncation = m.normalizeTruncation(ctx, pluginInstance, result.SubTitleTruncation)
+	result.IconSize = normalizeIconSize(result.IconSize, result.Kind)
+	// translate accessibility label, fallback to title and subtitle
+	result.AccessibilityLabel = m.translatePlugin(ctx, pluginInstance, result.AccessibilityLabel)
+	if result.AccessibilityLabel == "" {
+		result.AccessibilityLabel = defaultAccessibilityLabel(result.Title, result.SubTitle)
+	}
 	// translate preview properties
 	var previewProperties = make(map[string]string)
 	for key, value := range result.Preview.PreviewProperties {
@@ -526,24 +865,44 @@ func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, qu
 	result.Preview.PreviewProperties = previewProperties
 	// translate action names
 	for actionIndex := range result.Actions {
-		result.Actions[actionIndex].Name = m.translatePlugin(ctx, pluginInstance, result.Actions[actionIndex].Name)
+		result.Actions[actionIndex] = m.translateAction(ctx, pluginInstance, result.Actions[actionIndex])
 	}
 	// translate preview data if preview type is text
 	if result.Preview.PreviewType == WoxPreviewTypeText || result.Preview.PreviewType == WoxPreviewTypeMarkdown {
 		result.Preview.PreviewData = m.translatePlugin(ctx, pluginInstance, result.Preview.PreviewData)
 	}
 
+	// dynamic default action overrides static IsDefault flags
+	if result.DefaultActionId != "" {
+		if lo.ContainsBy(result.Actions, func(item QueryResultAction) bool { return item.Id == result.DefaultActionId }) {
+			for actionIndex := range result.Actions {
+				isDefault := result.Actions[actionIndex].Id == result.DefaultActionId
+				if !isDefault && result.Actions[actionIndex].IsDefault && strings.EqualFold(result.Actions[actionIndex].Hotkey, "Enter") {
+					result.Actions[actionIndex].Hotkey = ""
+				}
+				result.Actions[actionIndex].IsDefault = isDefault
+			}
+		} else {
+			logger.Warn(ctx, fmt.Sprintf("<%s> result(%s) default action id not found: %s, use IsDefault of actions", pluginInstance.Metadata.Name, result.Title, result.DefaultActionId))
+		}
+	}
+	m.applyActionPreference(ctx, pluginInstance, result.Kind, result.Actions)
+
 	// set first action as default if no default action is set
 	defaultActionCount := lo.CountBy(result.Actions, func(item QueryResultAction) bool {
 		return item.IsDefault
 	})
 	if defaultActionCount == 0 && len(result.Actions) > 0 {
-		result.Actions[0].IsDefault = true
-		result.Actions[0].Hotkey = "Enter"
+		// prefer plugin's own action, system actions (E.g. global actions) should not be triggered by Enter
+		defaultIndex := max(lo.IndexOf(lo.Map(result.Actions, func(item QueryResultAction, _ int) bool {
+			return item.IsSystemAction
+		}), false), 0)
+		result.Actions[defaultIndex].IsDefault = true
+		result.Actions[defaultIndex].Hotkey = "Enter"
 	}
 
-	//move default action to first one of the actions
-	sort.Slice(result.Actions, func(i, j int) bool {
+	//move default action to first one of the actions, keep order of others
+	sort.SliceStable(result.Actions, func(i, j int) bool {
 		return result.Actions[i].IsDefault
 	})
 
@@ -551,12 +910,31 @@ func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, qu
 		ResultId:       result.Id,
 		ResultTitle:    result.Title,
 		ResultSubTitle: result.SubTitle,
+		ResultKind:     result.Kind,
 		ContextData:    result.ContextData,
 		PluginInstance: pluginInstance,
 		Query:          query,
-		Actions:        util.NewHashMap[string, func(ctx context.Context, actionContext ActionContext)](),
+		Actions:        util.NewHashMap[string, QueryResultAction](),
+		ActionPreviews: util.NewHashMap[string, WoxPreview](),
+		TabPreviews:    util.NewHashMap[string, WoxPreview](),
+
+		DisableGlobalActions: result.DisableGlobalActions,
+		SetQueryOnSelect:     result.SetQueryOnSelect,
+		URL:                  result.URL,
+	}
+	resultCache.LazyIcon = result.OnIcon
+	resultCache.OnShown = result.OnShown
+	if result.OnExpand != nil {
+		resultCache.Expand = result.OnExpand
+	} else if len(result.Children) > 0 {
+		children := result.Children
+		resultCache.Expand = func(ctx context.Context) []QueryResult {
+			return children
+		}
 	}
 
+	m.normalizeActivationModifiers(ctx, pluginInstance, result.Actions)
+
 	// store actions for ui invoke later
 	for actionIndex := range result.Actions {
 		var action = result.Actions[actionIndex]
@@ -577,11 +955,13 @@ func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, qu
 			result.Actions[actionIndex].Hotkey = strings.ReplaceAll(result.Actions[actionIndex].Hotkey, "command", "win")
 			result.Actions[actionIndex].Hotkey = strings.ReplaceAll(result.Actions[actionIndex].Hotkey, "option", "alt")
 		}
+		result.Actions[actionIndex].hotkeyHint = m.getActionHotkeyHint(ctx, result.Actions[actionIndex].Hotkey)
 
-		if action.Action != nil {
-			resultCache.Actions.Store(action.Id, action.Action)
+		if action.hasActionFunc() {
+			resultCache.Actions.Store(action.Id, result.Actions[actionIndex])
 		}
 	}
+	m.polishContextActions(ctx, pluginInstance, &result, resultCache)
 
 	// if query is input and trigger keyword is global, disable preview and group
 	if query.IsGlobalQuery() {
@@ -590,9 +970,13 @@ func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, qu
 		result.GroupScore = 0
 	}
 
+	if len(result.PreviewTabs) > 0 {
+		m.polishPreviewTabs(ctx, pluginInstance, query, &result, resultCache)
+	}
+
 	// store preview for ui invoke later
 	// because preview may contain some heavy data (E.g. image or large text), we will store preview in cache and only send preview to ui when user select the result
-	if !result.Preview.IsEmpty() && result.Preview.PreviewType != WoxPreviewTypeRemote {
+	if (!result.Preview.IsEmpty() || result.Preview.OnUpdate != nil) && result.Preview.PreviewType != WoxPreviewTypeRemote {
 		resultCache.Preview = result.Preview
 		result.Preview = WoxPreview{
 			PreviewType: WoxPreviewTypeRemote,
@@ -609,27 +993,60 @@ func (m *Manager) PolishResult(ctx context.Context, pluginInstance *Instance, qu
 		resultCache.Refresh = result.OnRefresh
 	}
 
-	ignoreAutoScore := pluginInstance.Metadata.IsSupportFeature(MetadataFeatureIgnoreAutoScore)
-	if !ignoreAutoScore {
-		score := m.calculateResultScore(ctx, pluginInstance.Metadata.Id, result.Title, result.SubTitle)
-		if score > 0 {
-			logger.Debug(ctx, fmt.Sprintf("<%s> result(%s) add score: %d", pluginInstance.Metadata.Name, result.Title, score))
-			result.Score += score
+	result.Score = m.adjustResultScore(ctx, pluginInstance, result.Title, result.SubTitle, result.Score)
+
+	// score decay is applied when results are sorted, so they rank by age at that time instead of at query time
+	if !result.Timestamp.IsZero() && pluginInstance.Metadata.IsSupportFeature(MetadataFeatureScoreDecay) {
+		if params, err := pluginInstance.Metadata.GetFeatureParamsForScoreDecay(); err == nil {
+			result.scoreHalfLife = params.HalfLife
+		} else {
+			logger.Error(ctx, fmt.Sprintf("<%s> failed to get score decay params: %s", pluginInstance.Metadata.Name, err.Error()))
 		}
 	}
-	// check if result is favorite result
-	// favorite result will not be affected by ignoreAutoScore setting, so we add score here
-	if setting.GetSettingManager().IsFavoriteResult(ctx, pluginInstance.Metadata.Id, result.Title, result.SubTitle) {
-		favScore := int64(100000)
-		logger.Debug(ctx, fmt.Sprintf("<%s> result(%s) is favorite result, add score: %d", pluginInstance.Metadata.Name, result.Title, favScore))
-		result.Score += favScore
-	}
 
-	m.resultCache.Store(result.Id, resultCache)
+	resultCache.Score.Store(result.Score)
+	resultCache.Timestamp = toLastUpdatedTimestamp(result.Timestamp)
+	resultCache.ScoreHalfLife = result.scoreHalfLife.Milliseconds()
+
+	m.tagResultPlugin(ctx, pluginInstance, query, &result)
+
+	m.storeResultCache(ctx, resultCache)
 
 	return result
 }
 
+// defaultAccessibilityLabel is read by screen readers when result doesn't set AccessibilityLabel
+func defaultAccessibilityLabel(title, subTitle string) string {
+	if subTitle == "" {
+		return title
+	}
+	return title + ", " + subTitle
+}
+
+// normalizeTruncation defaults empty truncation to end truncation, so UI always gets an explicit strategy
+func (m *Manager) normalizeTruncation(ctx context.Context, pluginInstance *Instance, truncation QueryResultTruncation) QueryResultTruncation {
+	switch truncation {
+	case QueryResultTruncationStart, QueryResultTruncationMiddle, QueryResultTruncationEnd:
+		return truncation
+	case "":
+		return QueryResultTruncationEnd
+	default:
+		logger.Warn(ctx, fmt.Sprintf("<%s> unknown result truncation: %s, use end truncation", pluginInstance.Metadata.Name, truncation))
+		return QueryResultTruncationEnd
+	}
+}
+
+// normalizeIconSize defaults empty icon size by result kind and clamps it, so UI always gets a size which fits the layout
+func normalizeIconSize(iconSize int, kind QueryResultKind) int {
+	if iconSize <= 0 {
+		if kind == QueryResultKindMedia {
+			return QueryResultIconSizeMedia
+		}
+		return QueryResultIconSizeDefault
+	}
+	return min(max(iconSize, QueryResultIconSizeMin), QueryResultIconSizeMax)
+}
+
 func (m *Manager) formatFileListPreview(ctx context.Context, filePaths []string) string {
 	totalFiles := len(filePaths)
 	if totalFiles == 0 {
@@ -655,6 +1072,33 @@ func (m *Manager) formatFileListPreview(ctx context.Context, filePaths []string)
 	return sb.String()
 }
 
+// adjustResultScore adds scores Wox computes for a result to the score given by plugin: usage score (unless plugin ignores auto score),
+// favorite score and the user defined plugin weight. Used for results and for scores updated later, see UpdateResultScore
+func (m *Manager) adjustResultScore(ctx context.Context, pluginInstance *Instance, title string, subTitle string, score int64) int64 {
+	ignoreAutoScore := pluginInstance.Metadata.IsSupportFeature(MetadataFeatureIgnoreAutoScore)
+	if !ignoreAutoScore {
+		autoScore := m.calculateResultScore(ctx, pluginInstance.Metadata.Id, title, subTitle)
+		if autoScore > 0 {
+			logger.Debug(ctx, fmt.Sprintf("<%s> result(%s) add score: %d", pluginInstance.Metadata.Name, title, autoScore))
+			score = addScore(score, autoScore)
+		}
+	}
+	// check if result is favorite result
+	// favorite result will not be affected by ignoreAutoScore setting, so we add score here
+	if setting.GetSettingManager().IsFavoriteResult(ctx, pluginInstance.Metadata.Id, title, subTitle) {
+		favScore := int64(100000)
+		logger.Debug(ctx, fmt.Sprintf("<%s> result(%s) is favorite result, add score: %d", pluginInstance.Metadata.Name, title, favScore))
+		score = addScore(score, favScore)
+	}
+
+	// apply user defined plugin weight at last, so it biases the final score
+	if multiplier := pluginInstance.GetScoreMultiplier(); multiplier != 1.0 {
+		score = ScoreFromFloat(ScoreToFloat(score) * multiplier)
+	}
+
+	return score
+}
+
 func (m *Manager) calculateResultScore(ctx context.Context, pluginId, title, subTitle string) int64 {
 	var score int64 = 0
 
@@ -697,29 +1141,40 @@ func (m *Manager) calculateResultScore(ctx context.Context, pluginId, title, sub
 func (m *Manager) polishRefreshableResult(ctx context.Context, resultCache *QueryResultCache, result RefreshableResult) RefreshableResult {
 	pluginInstance := resultCache.PluginInstance
 
+	result.Actions = m.applyResultURL(ctx, pluginInstance, resultCache.URL, false, result.Actions)
+	result.Actions = applySetQueryOnSelect(resultCache.SetQueryOnSelect, result.Actions)
+	assignStableActionIds(result.Actions)
 	for actionIndex := range result.Actions {
-		if result.Actions[actionIndex].Id == "" {
-			result.Actions[actionIndex].Id = uuid.NewString()
-		}
 		if result.Actions[actionIndex].Icon.IsEmpty() {
 			// set default action icon if not present
 			result.Actions[actionIndex].Icon = DefaultActionIcon
+		} else {
+			result.Actions[actionIndex].Icon = ConvertRelativePathToAbsolutePath(ctx, result.Actions[actionIndex].Icon, pluginInstance.PluginDirectory)
 		}
 	}
+	m.applyActionPreference(ctx, pluginInstance, resultCache.ResultKind, result.Actions)
 
 	// set first action as default if no default action is set
 	defaultActionCount := lo.CountBy(result.Actions, func(item QueryResultAction) bool {
 		return item.IsDefault
 	})
 	if defaultActionCount == 0 && len(result.Actions) > 0 {
-		result.Actions[0].IsDefault = true
-		result.Actions[0].Hotkey = "Enter"
+		// prefer plugin's own action, system actions (E.g. global actions) should not be triggered by Enter
+		defaultIndex := max(lo.IndexOf(lo.Map(result.Actions, func(item QueryResultAction, _ int) bool {
+			return item.IsSystemAction
+		}), false), 0)
+		result.Actions[defaultIndex].IsDefault = true
+		result.Actions[defaultIndex].Hotkey = "Enter"
 	}
 
-	//move default action to first one of the actions
-	sort.Slice(result.Actions, func(i, j int) bool {
+	//move default action to first one of the actions, keep order of others
+	sort.SliceStable(result.Actions, func(i, j int) bool {
 		return result.Actions[i].IsDefault
 	})
+	m.normalizeActivationModifiers(ctx, pluginInstance, result.Actions)
+	for actionIndex := range result.Actions {
+		result.Actions[actionIndex].hotkeyHint = m.getActionHotkeyHint(ctx, result.Actions[actionIndex].Hotkey)
+	}
 
 	// convert icon
 	result.Icon = ConvertIcon(ctx, result.Icon, pluginInstance.PluginDirectory)
@@ -733,6 +1188,11 @@ func (m *Manager) polishRefreshableResult(ctx context.Context, resultCache *Quer
 	result.Title = m.translatePlugin(ctx, pluginInstance, result.Title)
 	// translate subtitle
 	result.SubTitle = m.translatePlugin(ctx, pluginInstance, result.SubTitle)
+	// translate accessibility label, fallback to title and subtitle
+	result.AccessibilityLabel = m.translatePlugin(ctx, pluginInstance, result.AccessibilityLabel)
+	if result.AccessibilityLabel == "" {
+		result.AccessibilityLabel = defaultAccessibilityLabel(result.Title, result.SubTitle)
+	}
 	// translate tail text
 	for i := range result.Tails {
 		if result.Tails[i].Type == QueryResultTailTypeText {
@@ -748,25 +1208,27 @@ func (m *Manager) polishRefreshableResult(ctx context.Context, resultCache *Quer
 	result.Preview.PreviewProperties = previewProperties
 	// translate action names
 	for actionIndex := range result.Actions {
-		result.Actions[actionIndex].Name = m.translatePlugin(ctx, pluginInstance, result.Actions[actionIndex].Name)
+		result.Actions[actionIndex] = m.translateAction(ctx, pluginInstance, result.Actions[actionIndex])
 	}
 
 	// update result cache
 	resultCache.ResultTitle = result.Title
 	resultCache.ResultSubTitle = result.SubTitle
 	resultCache.ContextData = result.ContextData
-	resultCache.Actions = util.NewHashMap[string, func(ctx context.Context, actionContext ActionContext)]()
+	resultCache.Actions = util.NewHashMap[string, QueryResultAction]()
+	resultCache.ActionPreviews = util.NewHashMap[string, WoxPreview]()
 	for _, newAction := range result.Actions {
-		if newAction.Action != nil {
-			resultCache.Actions.Store(newAction.Id, newAction.Action)
+		if newAction.hasActionFunc() {
+			resultCache.Actions.Store(newAction.Id, newAction)
 		}
 	}
+	restoreContextActions(resultCache)
 
 	// convert non-remote preview to remote preview
 	// because preview may contain some heavy data (E.g. image or large text),
 	// we will store preview in cache and only send preview to ui when user select the result
 	if !result.Preview.IsEmpty() && result.Preview.PreviewType != WoxPreviewTypeRemote {
-		resultCache.Preview = result.Preview
+		resultCache.setPreview(result.Preview)
 		result.Preview = WoxPreview{
 			PreviewType: WoxPreviewTypeRemote,
 			PreviewData: fmt.Sprintf("/preview?id=%s", resultCache.ResultId),
@@ -776,18 +1238,107 @@ func (m *Manager) polishRefreshableResult(ctx context.Context, resultCache *Quer
 	return result
 }
 
+// Query queries all plugins which can handle the query, results are sent to results channel in batches,
+// and done channel will receive a value after all plugins finished.
+//
+// If an identical query is still in flight, the caller is attached to it instead of querying plugins again,
+// see inflightQuery. Cancel ctx if you are not interested in the results anymore.
+// Query dispatches the query to plugins through registered query middlewares, see QueryMiddleware
+//
+// A result id is emitted again in a later batch if a plugin returns a result with the same id more than once (E.g. a static result
+// enriched by the dynamic query, or results with explicit ids emitted progressively). The later batch then carries the merged result,
+// which replaces the earlier one: fields set by the later version win, empty fields keep the earlier value and actions are the union of both.
+// Batches may arrive in any order across plugins, merging only depends on the order in which results with the same id are emitted.
+// See resultBatchMerger
 func (m *Manager) Query(ctx context.Context, query Query) (results chan []QueryResultUI, done chan bool) {
-	results = make(chan []QueryResultUI, 10)
-	done = make(chan bool)
+	return m.buildQueryChain(m.query)(ctx, query)
+}
+
+func (m *Manager) query(ctx context.Context, query Query) (results chan []QueryResultUI, done chan bool) {
+	key := getInflightQueryKey(query)
+
+	m.inflightLock.Lock()
+	if inflight, exist := m.inflightQueries[key]; exist {
+		logger.Info(ctx, fmt.Sprintf("identical query is in flight, attach to it: %s", query.String()))
+		subscriber := inflight.subscribe(ctx)
+		m.inflightLock.Unlock()
+		return subscriber.results, subscriber.done
+	}
+
+	// the pipeline may outlive the first requester, it will be cancelled when all requesters left
+	queryCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	inflight := &inflightQuery{cancel: cancel}
+	m.inflightQueries[key] = inflight
+	subscriber := inflight.subscribe(ctx)
+
+	pipelineResults := make(chan []QueryResultUI, 10)
+	pipelineDone := make(chan bool)
+	merger := newResultBatchMerger()
+	util.Go(ctx, "inflight query dispatcher", func() {
+		for {
+			select {
+			case batch := <-pipelineResults:
+				inflight.publish(merger.merge(batch))
+			case <-pipelineDone:
+				// results may still be buffered when done is received
+			drain:
+				for {
+					select {
+					case batch := <-pipelineResults:
+						inflight.publish(merger.merge(batch))
+					default:
+						break drain
+					}
+				}
+
+				m.inflightLock.Lock()
+				delete(m.inflightQueries, key)
+				m.pendingPlugins.Delete(key)
+				inflight.finish()
+				m.inflightLock.Unlock()
+				return
+			}
+		}
+	})
+	// the query is registered, identical queries attach to it from now on. Dispatch without the lock,
+	// claiming the query may take up to queryClaimTimeoutMs and must not block other queries or the dispatcher finishing
+	m.inflightLock.Unlock()
+	m.queryPipeline(queryCtx, query, pipelineResults, pipelineDone)
 
+	return subscriber.results, subscriber.done
+}
+
+func (m *Manager) queryPipeline(ctx context.Context, query Query, results chan []QueryResultUI, done chan bool) {
 	// clear old result cache
 	m.resultCache.Clear()
+	m.resultCacheSize.Store(0)
+	m.expandedResults.Clear()
+	m.resultCacheEvict.queryKey.Store(getInflightQueryKey(query))
+
+	var stat *queryStatCollector
+	if setting.GetSettingManager().GetWoxSetting(ctx).EnableQueryDebug {
+		stat = newQueryStatCollector(query)
+		m.lastQueryStat.Store(stat)
+	}
 
+	instances := m.getInstances()
 	counter := &atomic.Int32{}
-	counter.Store(int32(len(m.instances)))
+	counter.Store(int32(len(instances)))
+	pending := util.NewHashMap[string, string]()
+	m.pendingPlugins.Store(getInflightQueryKey(query), pending)
 
-	for _, pluginInstance := range m.instances {
-		if !m.canOperateQuery(ctx, pluginInstance, query) {
+	// in mixed mode global plugins are queried too when user typed a trigger keyword, see promoteTriggeredResults
+	var globalQuery *Query
+	if query.Type == QueryTypeInput && query.TriggerKeyword != "" && setting.GetSettingManager().GetWoxSetting(ctx).MixGlobalResultsInTriggeredQuery {
+		query.mixedWithGlobal = true
+		globalQuery = lo.ToPtr(toMixedGlobalQuery(query))
+	}
+
+	// a claimed query is only sent to the plugin which claimed it, see QueryClaimer
+	claimant := m.findQueryClaimant(ctx, query)
+
+	for _, pluginInstance := range instances {
+		if claimant != nil && pluginInstance != claimant {
 			counter.Add(-1)
 			if counter.Load() == 0 {
 				done <- true
@@ -795,45 +1346,75 @@ func (m *Manager) Query(ctx context.Context, query Query) (results chan []QueryR
 			continue
 		}
 
-		if pluginInstance.Metadata.IsSupportFeature(MetadataFeatureDebounce) {
-			debounceParams, err := pluginInstance.Metadata.GetFeatureParamsForDebounce()
-			if err == nil {
-				logger.Debug(ctx, fmt.Sprintf("[%s] debounce query, will execute in %d ms", pluginInstance.Metadata.Name, debounceParams.intervalMs))
-				if v, ok := m.debounceQueryTimer.Load(pluginInstance.Metadata.Id); ok {
-					if v.timer.Stop() {
-						v.onStop()
-					}
+		pluginQuery := query
+		if !m.canOperateQuery(ctx, pluginInstance, query) {
+			if globalQuery == nil || !m.canOperateQuery(ctx, pluginInstance, *globalQuery) {
+				counter.Add(-1)
+				if counter.Load() == 0 {
+					done <- true
 				}
+				continue
+			}
+			pluginQuery = *globalQuery
+		}
 
-				timer := time.AfterFunc(time.Duration(debounceParams.intervalMs)*time.Millisecond, func() {
-					m.queryParallel(ctx, pluginInstance, query, results, done, counter)
-				})
-				onStop := func() {
-					logger.Debug(ctx, fmt.Sprintf("[%s] previous debounced query cancelled", pluginInstance.Metadata.Name))
-					counter.Add(-1)
-					if counter.Load() == 0 {
-						done <- true
-					}
+		pending.Store(pluginInstance.Metadata.Id, pluginInstance.Metadata.Name)
+		if debounceIntervalMs, shouldDebounce := m.getQueryDebounceInterval(ctx, pluginInstance, pluginQuery); shouldDebounce {
+			logger.Debug(ctx, fmt.Sprintf("[%s] debounce query, will execute in %d ms", pluginInstance.Metadata.Name, debounceIntervalMs))
+			if v, ok := m.debounceQueryTimer.Load(pluginInstance.Metadata.Id); ok {
+				if v.timer.Stop() {
+					v.onStop()
+				}
+			}
+
+			timer := time.AfterFunc(time.Duration(debounceIntervalMs)*time.Millisecond, func() {
+				m.queryParallel(ctx, pluginInstance, pluginQuery, results, done, counter, stat, pending)
+			})
+			onStop := func() {
+				logger.Debug(ctx, fmt.Sprintf("[%s] previous debounced query cancelled", pluginInstance.Metadata.Name))
+				pending.Delete(pluginInstance.Metadata.Id)
+				counter.Add(-1)
+				if counter.Load() == 0 {
+					done <- true
 				}
-				m.debounceQueryTimer.Store(pluginInstance.Metadata.Id, &debounceTimer{
-					timer:  timer,
-					onStop: onStop,
-				})
-				continue
-			} else {
-				logger.Error(ctx, fmt.Sprintf("[%s] %s, query directlly", pluginInstance.Metadata.Name, err))
 			}
+			m.debounceQueryTimer.Store(pluginInstance.Metadata.Id, &debounceTimer{
+				timer:  timer,
+				onStop: onStop,
+			})
+			continue
 		}
 
-		m.queryParallel(ctx, pluginInstance, query, results, done, counter)
+		m.queryParallel(ctx, pluginInstance, pluginQuery, results, done, counter, stat, pending)
 	}
 
 	return
 }
 
+// getQueryDebounceInterval returns how long to wait before querying the plugin, false if plugin should be queried directly.
+// Empty queries are always debounced, so deleting the query with backspace won't query home screen plugins on the way, see MetadataFeatureEmptyQuery
+func (m *Manager) getQueryDebounceInterval(ctx context.Context, pluginInstance *Instance, query Query) (int, bool) {
+	intervalMs := 0
+	if pluginInstance.Metadata.IsSupportFeature(MetadataFeatureDebounce) {
+		debounceParams, err := pluginInstance.Metadata.GetFeatureParamsForDebounce()
+		if err == nil {
+			intervalMs = debounceParams.intervalMs
+		} else {
+			logger.Error(ctx, fmt.Sprintf("[%s] %s, query directlly", pluginInstance.Metadata.Name, err))
+		}
+	}
+	if query.IsEmptyInput() {
+		intervalMs = max(intervalMs, emptyQueryDebounceMs)
+	}
+
+	return intervalMs, intervalMs > 0
+}
+
 func (m *Manager) QuerySilent(ctx context.Context, query Query) bool {
 	var startTimestamp = util.GetSystemTimestamp()
 	var results []QueryResultUI
+	timeoutTimer := time.NewTimer(time.Duration(setting.GetSettingManager().GetWoxSetting(ctx).QueryTimeout) * time.Millisecond)
+	defer timeoutTimer.Stop()
 	resultChan, doneChan := m.Query(ctx, query)
 	for {
 		select {
@@ -856,8 +1437,8 @@ func (m *Manager) QuerySilent(ctx context.Context, query Query) bool {
 			}
 
 			return false
-		case <-time.After(time.Minute):
-			logger.Error(ctx, "silent query timeout")
+		case <-timeoutTimer.C:
+			m.ReportQueryTimeout(ctx, "", query, util.GetSystemTimestamp()-startTimestamp)
 			return false
 		}
 	}
@@ -866,7 +1447,7 @@ func (m *Manager) QuerySilent(ctx context.Context, query Query) bool {
 func (m *Manager) QueryFallback(ctx context.Context, query Query, queryPlugin *Instance) (results []QueryResultUI) {
 	var queryResults []QueryResult
 	if query.IsGlobalQuery() {
-		for _, instance := range m.instances {
+		for _, instance := range m.getInstances() {
 			pluginInstance := instance
 			if v, ok := pluginInstance.Plugin.(FallbackSearcher); ok {
 				queryResults = v.QueryFallback(ctx, query)
@@ -915,17 +1496,43 @@ func (m *Manager) QueryFallback(ctx context.Context, query Query, queryPlugin *I
 	return results
 }
 
-func (m *Manager) queryParallel(ctx context.Context, pluginInstance *Instance, query Query, results chan []QueryResultUI, done chan bool, counter *atomic.Int32) {
+func (m *Manager) queryParallel(ctx context.Context, pluginInstance *Instance, query Query, results chan []QueryResultUI, done chan bool, counter *atomic.Int32, stat *queryStatCollector, pending *util.HashMap[string, string]) {
 	util.Go(ctx, fmt.Sprintf("[%s] parallel query", pluginInstance.Metadata.Name), func() {
-		queryResults := m.queryForPlugin(ctx, pluginInstance, query)
+		// static results are shown instantly, plugin is only queried for dynamic results
+		if staticResults := m.queryStaticResults(ctx, pluginInstance, query); len(staticResults) > 0 {
+			results <- lo.Map(staticResults, func(item QueryResult, index int) QueryResultUI {
+				return item.ToUI()
+			})
+		}
+
+		start := util.GetSystemTimestamp()
+		streamedCount := 0
+		queryResults := m.queryForPluginStreaming(ctx, pluginInstance, query, func(batch []QueryResult) {
+			if pluginInstance.unloaded.Load() {
+				return
+			}
+			streamedCount += len(batch)
+			results <- lo.Map(batch, func(item QueryResult, index int) QueryResultUI {
+				return item.ToUI()
+			})
+		})
+		stat.addPlugin(pluginInstance, streamedCount+len(queryResults), util.GetSystemTimestamp()-start)
+		if pluginInstance.unloaded.Load() {
+			// plugin was unloaded or reloaded while querying, results belong to the old instance
+			logger.Info(ctx, fmt.Sprintf("[%s] plugin unloaded during query, drop %d results", pluginInstance.Metadata.Name, len(queryResults)))
+			m.removePluginResultCache(pluginInstance)
+			queryResults = nil
+		}
 		results <- lo.Map(queryResults, func(item QueryResult, index int) QueryResultUI {
 			return item.ToUI()
 		})
+		pending.Delete(pluginInstance.Metadata.Id)
 		counter.Add(-1)
 		if counter.Load() == 0 {
 			done <- true
 		}
 	}, func() {
+		pending.Delete(pluginInstance.Metadata.Id)
 		counter.Add(-1)
 		if counter.Load() == 0 {
 			done <- true
@@ -933,6 +1540,55 @@ func (m *Manager) queryParallel(ctx context.Context, pluginInstance *Instance, q
 	})
 }
 
+// GetPendingPlugins returns names of plugins which haven't returned results of the query yet
+func (m *Manager) GetPendingPlugins(query Query) []string {
+	pending, found := m.pendingPlugins.Load(getInflightQueryKey(query))
+	if !found {
+		return nil
+	}
+
+	var names []string
+	pending.Range(func(pluginId string, pluginName string) bool {
+		names = append(names, pluginName)
+		return true
+	})
+	return names
+}
+
+// ReportQueryTimeout logs and tracks plugins which haven't returned when the overall query timeout fires, see setting.WoxSetting.QueryTimeout.
+// Each unfinished plugin gets a query_timeout analytics event and a recent error in DumpState
+func (m *Manager) ReportQueryTimeout(ctx context.Context, queryId string, query Query, costMs int64) {
+	var pluginIds []string
+	var pluginNames []string
+	if pending, found := m.pendingPlugins.Load(getInflightQueryKey(query)); found {
+		pending.Range(func(pluginId string, pluginName string) bool {
+			pluginIds = append(pluginIds, pluginId)
+			pluginNames = append(pluginNames, pluginName)
+			return true
+		})
+	}
+
+	logger.Warn(ctx, fmt.Sprintf("query timeout, query id: %s, query: %s, cost: %d ms, unfinished plugins: [%s]", queryId, query.String(), costMs, strings.Join(pluginNames, ", ")))
+	for index, pluginId := range pluginIds {
+		m.pluginErrors.add(pluginId, pluginNames[index], PluginErrorKindTimeout, fmt.Sprintf("query timeout after %d ms", costMs))
+		m.emitAnalyticsEvent(ctx, AnalyticsEvent{
+			Type:     AnalyticsEventQueryTimeout,
+			QueryId:  queryId,
+			PluginId: pluginId,
+		})
+	}
+}
+
+// GetLastQueryStat returns the timing breakdown of last query, false if query debug is disabled or no query yet
+func (m *Manager) GetLastQueryStat() (QueryStat, bool) {
+	stat := m.lastQueryStat.Load()
+	if stat == nil {
+		return QueryStat{}, false
+	}
+
+	return stat.snapshot(), true
+}
+
 func (m *Manager) translatePlugin(ctx context.Context, pluginInstance *Instance, key string) string {
 	if !strings.HasPrefix(key, "i18n:") {
 		return key
@@ -950,6 +1606,16 @@ func (m *Manager) GetUI() share.UI {
 }
 
 func (m *Manager) NewQuery(ctx context.Context, plainQuery share.PlainQuery) (Query, *Instance, error) {
+	return m.newQuery(ctx, plainQuery, false)
+}
+
+// NewUIQuery is NewQuery for queries typed by user in UI. Only these queries track the triggered plugin for
+// applyTriggerKeywordGrace, other callers (E.g. query hotkeys, suggestions, favorites) must not change it
+func (m *Manager) NewUIQuery(ctx context.Context, plainQuery share.PlainQuery) (Query, *Instance, error) {
+	return m.newQuery(ctx, plainQuery, true)
+}
+
+func (m *Manager) newQuery(ctx context.Context, plainQuery share.PlainQuery, isUIQuery bool) (Query, *Instance, error) {
 	if plainQuery.QueryType == QueryTypeInput {
 		newQuery := plainQuery.QueryText
 		woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
@@ -962,6 +1628,11 @@ func (m *Manager) NewQuery(ctx context.Context, plainQuery share.PlainQuery) (Qu
 			}
 		}
 		query, instance := newQueryInputWithPlugins(newQuery, GetPluginManager().GetPluginInstances())
+		if isUIQuery {
+			query, instance = m.applyTriggerKeywordGrace(ctx, query, instance, int64(woxSetting.TriggerKeywordDeletedGrace))
+		}
+		query = applyLiteralMatchMode(query, woxSetting.LiteralMatchPrefix)
+		query.CarriedContext = m.getCarriedContext(query)
 		query.Env.ActiveWindowTitle = m.GetUI().GetActiveWindowName()
 		query.Env.ActiveWindowPid = m.GetUI().GetActiveWindowPid()
 		query.Env.ActiveBrowserUrl = m.getActiveBrowserUrl(ctx)
@@ -969,16 +1640,13 @@ func (m *Manager) NewQuery(ctx context.Context, plainQuery share.PlainQuery) (Qu
 	}
 
 	if plainQuery.QueryType == QueryTypeSelection {
-		query := Query{
-			Type:      QueryTypeSelection,
-			RawQuery:  plainQuery.QueryText,
-			Search:    plainQuery.QueryText,
-			Selection: plainQuery.QuerySelection,
-		}
+		query, instance := newQuerySelectionWithPlugins(plainQuery.QueryText, plainQuery.QuerySelection, GetPluginManager().GetPluginInstances())
+		query = applyLiteralMatchMode(query, setting.GetSettingManager().GetWoxSetting(ctx).LiteralMatchPrefix)
+		query.Handoff = m.getHandoff(query)
 		query.Env.ActiveWindowTitle = m.GetUI().GetActiveWindowName()
 		query.Env.ActiveWindowPid = m.GetUI().GetActiveWindowPid()
 		query.Env.ActiveBrowserUrl = m.getActiveBrowserUrl(ctx)
-		return query, nil, nil
+		return query, instance, nil
 	}
 
 	return Query{}, nil, errors.New("invalid query type")
@@ -1034,43 +1702,305 @@ func (m *Manager) expandQueryShortcut(ctx context.Context, query string, querySh
 }
 
 func (m *Manager) ExecuteAction(ctx context.Context, resultId string, actionId string) error {
-	resultCache, found := m.resultCache.Load(resultId)
+	return m.ExecuteActionWithModifiers(ctx, resultId, actionId, nil)
+}
+
+// ExecuteActionWithModifiers executes an action with the modifier keys user held when invoking it, see ActionContext.Modifiers
+func (m *Manager) ExecuteActionWithModifiers(ctx context.Context, resultId string, actionId string, modifiers []string) error {
+	resultCache, action, err := m.loadResultAction(resultId, actionId)
+	if err != nil {
+		return err
+	}
+	if action.Input != nil {
+		return fmt.Errorf("action requires input: %s", action.Name)
+	}
+
+	actionContext := resultCache.getActionContext()
+	actionContext.Modifiers = normalizeInvocationModifiers(modifiers)
+	return m.executeAction(ctx, resultCache, action, actionContext)
+}
+
+// ExecuteActionWithInput executes an action which requires input with the text entered by user, see QueryResultAction.Input
+func (m *Manager) ExecuteActionWithInput(ctx context.Context, resultId string, actionId string, input string) error {
+	resultCache, action, err := m.loadResultAction(resultId, actionId)
+	if err != nil {
+		return err
+	}
+	if action.Input == nil {
+		return fmt.Errorf("action doesn't accept input: %s", action.Name)
+	}
+
+	actionContext := resultCache.getActionContext()
+	actionContext.Input = input
+	if action.Input.Validate != nil {
+		if validateErr := action.Input.Validate(ctx, actionContext, input); validateErr != nil {
+			return validateErr
+		}
+	}
+
+	return m.executeAction(ctx, resultCache, action, actionContext)
+}
+
+// ValidateActionInput validates the text user is typing for an action, nil if the action has no validation
+func (m *Manager) ValidateActionInput(ctx context.Context, resultId string, actionId string, input string) error {
+	resultCache, action, err := m.loadResultAction(resultId, actionId)
+	if err != nil {
+		return err
+	}
+	if action.Input == nil || action.Input.Validate == nil {
+		return nil
+	}
+
+	actionContext := resultCache.getActionContext()
+	actionContext.Input = input
+	return action.Input.Validate(ctx, actionContext, input)
+}
+
+// CancelActionInput is called when user cancels the input of an action, action is not executed
+func (m *Manager) CancelActionInput(ctx context.Context, resultId string, actionId string) error {
+	resultCache, action, err := m.loadResultAction(resultId, actionId)
+	if err != nil {
+		return err
+	}
+	if action.Input == nil || action.Input.OnCancel == nil {
+		return nil
+	}
+
+	action.Input.OnCancel(ctx, resultCache.getActionContext())
+	return nil
+}
+
+func (m *Manager) loadResultAction(resultId string, actionId string) (*QueryResultCache, QueryResultAction, error) {
+	resultCache, found := m.loadResultCache(resultId)
 	if !found {
-		return fmt.Errorf("result cache not found for result id (execute action): %s", resultId)
+		return nil, QueryResultAction{}, fmt.Errorf("result cache not found for result id (execute action): %s", resultId)
 	}
 	action, exist := resultCache.Actions.Load(actionId)
 	if !exist {
-		return fmt.Errorf("action not found for result id: %s, action id: %s", resultId, actionId)
+		return nil, QueryResultAction{}, fmt.Errorf("action not found for result id: %s, action id: %s", resultId, actionId)
 	}
 
-	action(ctx, ActionContext{
-		ContextData: resultCache.ContextData,
-	})
+	return resultCache, action, nil
+}
+
+func (m *Manager) executeAction(ctx context.Context, resultCache *QueryResultCache, action QueryResultAction, actionContext ActionContext) error {
+	if !action.AllowRepeat && m.actionDedup.isDuplicate(getActionInvocationKey(resultCache.ResultId, action.Id, actionContext.Input), time.Now()) {
+		// the first invocation is running or done, report no error so UI behaves the same as for the first one
+		logger.Info(ctx, fmt.Sprintf("<%s> drop duplicate invocation of action %s", resultCache.PluginInstance.Metadata.Name, action.Name))
+		return nil
+	}
 
-	util.Go(ctx, fmt.Sprintf("[%s] add actioned result", resultCache.PluginInstance.Metadata.Name), func() {
-		setting.GetSettingManager().AddActionedResult(ctx, resultCache.PluginInstance.Metadata.Id, resultCache.ResultTitle, resultCache.ResultSubTitle)
+	if action.Action == nil && action.BatchAction != nil {
+		action.Action = getSingleAction(action.BatchAction)
+	}
+
+	if action.Cancellable {
+		startErr := m.startCancellableAction(ctx, resultCache, action, actionContext)
+		if startErr != nil {
+			return startErr
+		}
+	} else {
+		action.Action(ctx, actionContext)
+	}
+
+	actioned := newActionedResult(ctx, resultCache)
+	util.Go(ctx, fmt.Sprintf("[%s] add actioned result", actioned.pluginInstance.Metadata.Name), func() {
+		setting.GetSettingManager().AddActionedResult(ctx, actioned.pluginInstance.Metadata.Id, actioned.resultTitle, actioned.resultSubTitle)
+		m.addRecentResult(ctx, actioned, action)
+		m.trackActionInvoked(ctx, actioned, action)
 	})
 
 	return nil
 }
 
-func (m *Manager) ExecuteRefresh(ctx context.Context, refreshableResultWithId RefreshableResultWithResultId) (RefreshableResultWithResultId, error) {
+// actionedResult is a copy of the result cache fields and settings used by the background work after an action is executed,
+// result cache may be refreshed (see polishRefreshableResult) and setting may be reloaded meanwhile, so they must not be read in background
+type actionedResult struct {
+	pluginInstance      *Instance
+	resultTitle         string
+	resultSubTitle      string
+	query               Query
+	enableRecentResults bool
+	enableAnalytics     bool
+}
+
+func newActionedResult(ctx context.Context, resultCache *QueryResultCache) actionedResult {
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	return actionedResult{
+		pluginInstance:      resultCache.PluginInstance,
+		resultTitle:         resultCache.ResultTitle,
+		resultSubTitle:      resultCache.ResultSubTitle,
+		query:               resultCache.Query,
+		enableRecentResults: woxSetting.EnableRecentResults,
+		enableAnalytics:     woxSetting.EnableAnalytics,
+	}
+}
+
+func (m *Manager) addRecentResult(ctx context.Context, actioned actionedResult, action QueryResultAction) {
+	if !actioned.enableRecentResults {
+		return
+	}
+	// system actions (E.g. add to favorite) are not the purpose of the result
+	if action.IsSystemAction {
+		return
+	}
+	if actioned.pluginInstance.Metadata.IsSupportFeature(MetadataFeatureIgnoreRecentResult) {
+		return
+	}
+	// selection queries can't be replayed and may contain sensitive data
+	if actioned.query.Type != QueryTypeInput {
+		return
+	}
+
+	setting.GetSettingManager().AddRecentResult(ctx, setting.RecentResult{
+		PluginId:       actioned.pluginInstance.Metadata.Id,
+		ResultTitle:    actioned.resultTitle,
+		ResultSubTitle: actioned.resultSubTitle,
+		ActionName:     action.Name,
+		Query: share.PlainQuery{
+			QueryType: QueryTypeInput,
+			QueryText: actioned.query.RawQuery,
+		},
+	})
+}
+
+// ExecuteRecentResult re-runs the action of a recent result.
+// Action functions can't be persisted, so we replay the original query against the original plugin,
+// find the same result by title and subtitle, and execute the action with same name (or the default action).
+func (m *Manager) ExecuteRecentResult(ctx context.Context, recentResult setting.RecentResult) error {
+	pluginInstance, pluginErr := m.getReplayPluginInstance(recentResult.PluginId)
+	if pluginErr != nil {
+		return pluginErr
+	}
+
+	query, _, queryErr := m.NewQuery(ctx, recentResult.Query)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	results := m.queryForPlugin(ctx, pluginInstance, query)
+	result, found := lo.Find(results, func(item QueryResult) bool {
+		return item.Title == recentResult.ResultTitle && item.SubTitle == recentResult.ResultSubTitle
+	})
+	if !found {
+		return fmt.Errorf("result is no longer available: %s", recentResult.ResultTitle)
+	}
+
+	action, found := lo.Find(result.Actions, func(item QueryResultAction) bool {
+		return item.Name == recentResult.ActionName
+	})
+	if !found {
+		action, found = lo.Find(result.Actions, func(item QueryResultAction) bool {
+			return item.IsDefault
+		})
+		if !found {
+			return fmt.Errorf("action not found for result: %s", recentResult.ResultTitle)
+		}
+	}
+
+	return m.ExecuteAction(ctx, result.Id, action.Id)
+}
+
+// ExecuteFavoriteResult re-runs the default action of a favorite result.
+// Like recent results, we replay the original query against the original plugin and find the result again,
+// by result id first, then by context data and title/subtitle, see setting.FavoriteResult
+func (m *Manager) ExecuteFavoriteResult(ctx context.Context, favoriteResult setting.FavoriteResult) error {
+	pluginInstance, pluginErr := m.getReplayPluginInstance(favoriteResult.PluginId)
+	if pluginErr != nil {
+		return pluginErr
+	}
+	if favoriteResult.Query.QueryType != QueryTypeInput {
+		return fmt.Errorf("favorite result can't be replayed: %s", favoriteResult.ResultTitle)
+	}
+
+	query, _, queryErr := m.NewQuery(ctx, favoriteResult.Query)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	results := m.queryForPlugin(ctx, pluginInstance, query)
+	result, found := lo.Find(results, func(item QueryResult) bool {
+		return favoriteResult.ResultId != "" && item.Id == favoriteResult.ResultId
+	})
+	if !found {
+		result, found = lo.Find(results, func(item QueryResult) bool {
+			if favoriteResult.ContextData != "" && item.ContextData != favoriteResult.ContextData {
+				return false
+			}
+			return item.Title == favoriteResult.ResultTitle && item.SubTitle == favoriteResult.ResultSubTitle
+		})
+	}
+	if !found {
+		return fmt.Errorf("result is no longer available: %s", favoriteResult.ResultTitle)
+	}
+
+	action, found := lo.Find(result.Actions, func(item QueryResultAction) bool {
+		return item.IsDefault
+	})
+	if !found {
+		return fmt.Errorf("action not found for result: %s", favoriteResult.ResultTitle)
+	}
+
+	return m.ExecuteAction(ctx, result.Id, action.Id)
+}
+
+// getReplayPluginInstance returns the instance to replay a persisted result against, disabled or uninstalled plugins can't be replayed
+func (m *Manager) getReplayPluginInstance(pluginId string) (*Instance, error) {
+	pluginInstance, exist := lo.Find(m.getInstances(), func(item *Instance) bool {
+		return item.Metadata.Id == pluginId
+	})
+	if !exist {
+		return nil, fmt.Errorf("plugin not found: %s", pluginId)
+	}
+	if pluginInstance.Setting.Disabled {
+		return nil, fmt.Errorf("plugin is disabled: %s", pluginInstance.Metadata.Name)
+	}
+
+	return pluginInstance, nil
+}
+
+// ExecuteRefresh calls the refresh function of given result.
+// isVisible indicates whether the result is currently visible in UI, refreshes of invisible results are throttled harder.
+// If the refresh is throttled, the given result is returned unchanged and UI will try again in next interval.
+func (m *Manager) ExecuteRefresh(ctx context.Context, refreshableResultWithId RefreshableResultWithResultId, isVisible bool) (RefreshableResultWithResultId, error) {
 	var refreshableResult RefreshableResult
 	copyErr := copier.Copy(&refreshableResult, &refreshableResultWithId)
 	if copyErr != nil {
 		return RefreshableResultWithResultId{}, fmt.Errorf("failed to copy refreshable result: %w", copyErr)
 	}
+	refreshableResult.LastUpdated = fromLastUpdatedTimestamp(refreshableResultWithId.LastUpdated)
 
-	resultCache, found := m.resultCache.Load(refreshableResultWithId.ResultId)
+	resultCache, found := m.loadResultCache(refreshableResultWithId.ResultId)
 	if !found {
 		return refreshableResultWithId, fmt.Errorf("result cache not found for result id (execute refresh): %s", refreshableResultWithId.ResultId)
 	}
 
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	// results of collapsed plugin groups are hidden until the group is expanded, see SetResultGroupCollapsed
+	if !isVisible && woxSetting.GroupResultsByPlugin && m.IsResultGroupCollapsed(resultCache.PluginInstance.Metadata.Id) {
+		return refreshableResultWithId, nil
+	}
+	if !isVisible && util.GetSystemTimestamp()-resultCache.LastRefreshTimestamp < int64(woxSetting.HiddenResultRefreshInterval) {
+		return refreshableResultWithId, nil
+	}
+	if !m.refreshLimiter.allow(woxSetting.MaxRefreshPerSecond, isVisible) {
+		logger.Debug(ctx, fmt.Sprintf("<%s> refresh of result(%s) is throttled", resultCache.PluginInstance.Metadata.Name, resultCache.ResultTitle))
+		return refreshableResultWithId, nil
+	}
+	resultCache.LastRefreshTimestamp = util.GetSystemTimestamp()
+
+	// fallback label is computed by Wox, clear it so it follows the refreshed title and subtitle
+	if refreshableResult.AccessibilityLabel == defaultAccessibilityLabel(refreshableResult.Title, refreshableResult.SubTitle) {
+		refreshableResult.AccessibilityLabel = ""
+	}
+
+	refreshableResult.TriggerKeyword = resultCache.Query.TriggerKeyword
+
 	//restore actions in cache
 	refreshableResult.Actions = []QueryResultAction{}
 	for _, action := range refreshableResultWithId.Actions {
 		// get actual action from cache
-		actionFunc, exist := resultCache.Actions.Load(action.Id)
+		cachedAction, exist := resultCache.Actions.Load(action.Id)
 		if !exist {
 			continue
 		}
@@ -1081,31 +2011,76 @@ func (m *Manager) ExecuteRefresh(ctx context.Context, refreshableResultWithId Re
 			IsDefault:              action.IsDefault,
 			PreventHideAfterAction: action.PreventHideAfterAction,
 			Hotkey:                 action.Hotkey,
-			Action:                 actionFunc,
+			ActivationModifier:     action.ActivationModifier,
+			Action:                 cachedAction.Action,
+			Preview:                cachedAction.Preview,
+			Cancellable:            cachedAction.Cancellable,
+			Input:                  cachedAction.Input,
 			IsSystemAction:         action.IsSystemAction,
 		})
 	}
 
-	newResult := resultCache.Refresh(ctx, refreshableResult)
+	timeout := time.Duration(woxSetting.MaxRefreshTimeout) * time.Millisecond
+	newResult, refreshErr := m.refreshWithDeadline(ctx, resultCache, refreshableResult, timeout)
+	if refreshErr != nil {
+		logger.Warn(ctx, fmt.Sprintf("<%s> %s", resultCache.PluginInstance.Metadata.Name, refreshErr.Error()))
+		return refreshableResultWithId, nil
+	}
+
+	return m.toRefreshableResultWithResultId(ctx, resultCache, newResult), nil
+}
+
+// refreshWithDeadline calls the refresh function of a result with a deadline, so a hung refresh won't block following refreshes.
+// Deadline is the refresh interval of the result, capped by maxTimeout. Plugins should respect ctx and abort when it's done,
+// result of a refresh that exceeds the deadline is discarded.
+func (m *Manager) refreshWithDeadline(ctx context.Context, resultCache *QueryResultCache, result RefreshableResult, maxTimeout time.Duration) (RefreshableResult, error) {
+	timeout := maxTimeout
+	if result.RefreshInterval > 0 && time.Duration(result.RefreshInterval)*time.Millisecond < timeout {
+		timeout = time.Duration(result.RefreshInterval) * time.Millisecond
+	}
+
+	refreshCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resultChan := make(chan RefreshableResult, 1)
+	util.Go(refreshCtx, fmt.Sprintf("[%s] refresh result(%s)", resultCache.PluginInstance.Metadata.Name, resultCache.ResultTitle), func() {
+		resultChan <- resultCache.Refresh(refreshCtx, result)
+	})
+
+	select {
+	case newResult := <-resultChan:
+		return newResult, nil
+	case <-refreshCtx.Done():
+		return result, fmt.Errorf("refresh of result(%s) is cancelled after %s: %w", resultCache.ResultTitle, timeout, refreshCtx.Err())
+	}
+}
 
+// toRefreshableResultWithResultId polishes a new state of a cached result and converts it to the UI representation
+func (m *Manager) toRefreshableResultWithResultId(ctx context.Context, resultCache *QueryResultCache, newResult RefreshableResult) RefreshableResultWithResultId {
 	// add default actions if there is no system action
 	if lo.CountBy(newResult.Actions, func(action QueryResultAction) bool {
 		return action.IsSystemAction
 	}) == 0 {
-		defaultActions := m.getDefaultActions(ctx, resultCache.PluginInstance, resultCache.Query, newResult.Title, newResult.SubTitle)
+		defaultActions := m.getDefaultActions(ctx, resultCache.PluginInstance, resultCache.Query, resultCache.ResultId, newResult.Title, newResult.SubTitle, newResult.ContextData)
 		newResult.Actions = append(newResult.Actions, defaultActions...)
+		if !resultCache.DisableGlobalActions {
+			globalActions := m.getGlobalActions(ctx, resultCache.PluginInstance, newResult.Title, newResult.Actions)
+			newResult.Actions = append(newResult.Actions, globalActions...)
+		}
 	}
 
 	newResult = m.polishRefreshableResult(ctx, resultCache, newResult)
 	return RefreshableResultWithResultId{
-		ResultId:        refreshableResultWithId.ResultId,
-		Title:           newResult.Title,
-		SubTitle:        newResult.SubTitle,
-		Icon:            newResult.Icon,
-		Tails:           newResult.Tails,
-		Preview:         newResult.Preview,
-		ContextData:     newResult.ContextData,
-		RefreshInterval: newResult.RefreshInterval,
+		ResultId:           resultCache.ResultId,
+		Title:              newResult.Title,
+		SubTitle:           newResult.SubTitle,
+		AccessibilityLabel: newResult.AccessibilityLabel,
+		Icon:               newResult.Icon,
+		Tails:              newResult.Tails,
+		Preview:            newResult.Preview,
+		ContextData:        newResult.ContextData,
+		RefreshInterval:    newResult.RefreshInterval,
+		LastUpdated:        toLastUpdatedTimestamp(newResult.LastUpdated),
 		Actions: lo.Map(newResult.Actions, func(action QueryResultAction, index int) QueryResultActionUI {
 			return QueryResultActionUI{
 				Id:                     action.Id,
@@ -1114,31 +2089,59 @@ func (m *Manager) ExecuteRefresh(ctx context.Context, refreshableResultWithId Re
 				IsDefault:              action.IsDefault,
 				PreventHideAfterAction: action.PreventHideAfterAction,
 				Hotkey:                 action.Hotkey,
+				HotkeyHint:             action.hotkeyHint,
+				ActivationModifier:     action.ActivationModifier,
+				HasPreview:             action.Preview != nil,
+				Cancellable:            action.Cancellable,
+				Input:                  action.getInputUI(),
+				AllowRepeat:            action.AllowRepeat,
 				IsSystemAction:         action.IsSystemAction,
 			}
 		}),
-	}, nil
+	}
 }
 
 func (m *Manager) GetResultPreview(ctx context.Context, resultId string) (WoxPreview, error) {
-	resultCache, found := m.resultCache.Load(resultId)
+	resultCache, found := m.loadResultCache(resultId)
 	if !found {
 		return WoxPreview{}, fmt.Errorf("result cache not found for result id (get preview): %s", resultId)
 	}
 
-	preview := m.polishPreview(ctx, resultCache.Preview)
+	preview := resultCache.getPreview()
+	if preview.PreviewType == WoxPreviewTypeStream {
+		// UI reads the file window by window, see ReadResultPreviewStream
+		preview.PreviewData = fmt.Sprintf("/preview/stream?id=%s", resultId)
+		return preview, nil
+	}
+
+	return m.polishPreviewForUI(ctx, resultCache.PluginInstance, preview), nil
+}
+
+func (m *Manager) polishPreviewForUI(ctx context.Context, pluginInstance *Instance, preview WoxPreview) WoxPreview {
+	preview = m.polishPreview(ctx, pluginInstance, preview)
 
 	// if preview text is too long, ellipsis it, otherwise UI maybe freeze when render
 	if preview.PreviewType == WoxPreviewTypeText {
 		preview.PreviewData = util.EllipsisMiddle(preview.PreviewData, 2000)
 		// translate preview data if preview type is text
-		preview.PreviewData = m.translatePlugin(ctx, resultCache.PluginInstance, preview.PreviewData)
+		preview.PreviewData = m.translatePlugin(ctx, pluginInstance, preview.PreviewData)
 	}
 
-	return preview, nil
+	return preview
 }
 
-func (m *Manager) polishPreview(ctx context.Context, preview WoxPreview) WoxPreview {
+// polishPreview resolves resources bundled with plugin (see WoxImageResourceScheme) and converts local images to urls UI can load
+func (m *Manager) polishPreview(ctx context.Context, pluginInstance *Instance, preview WoxPreview) WoxPreview {
+	if preview.PreviewType == WoxPreviewTypeFile && strings.HasPrefix(preview.PreviewData, WoxImageResourceScheme) {
+		filePath, resolveErr := resolvePluginResource(pluginInstance.PluginDirectory, preview.PreviewData)
+		if resolveErr != nil {
+			logger.Warn(ctx, fmt.Sprintf("<%s> failed to resolve file preview: %s", pluginInstance.Metadata.Name, resolveErr.Error()))
+			return WoxPreview{PreviewType: WoxPreviewTypeText, PreviewData: resolveErr.Error()}
+		}
+		preview.PreviewData = filePath
+		return preview
+	}
+
 	if preview.PreviewType == WoxPreviewTypeImage {
 		woxImage, err := ParseWoxImage(preview.PreviewData)
 		if err != nil {
@@ -1146,6 +2149,7 @@ func (m *Manager) polishPreview(ctx context.Context, preview WoxPreview) WoxPrev
 			return preview
 		}
 
+		woxImage = ConvertRelativePathToAbsolutePath(ctx, woxImage, pluginInstance.PluginDirectory)
 		if woxImage.ImageType == WoxImageTypeAbsolutePath {
 			newWoxImage := convertLocalImageToUrl(ctx, woxImage)
 			return WoxPreview{
@@ -1159,28 +2163,6 @@ func (m *Manager) polishPreview(ctx context.Context, preview WoxPreview) WoxPrev
 	return preview
 }
 
-func (m *Manager) ReplaceQueryVariable(ctx context.Context, query string) string {
-	if strings.Contains(query, QueryVariableSelectedText) {
-		selected, selectedErr := selection.GetSelected(ctx)
-		if selectedErr != nil {
-			logger.Error(ctx, fmt.Sprintf("failed to get selected text: %s", selectedErr.Error()))
-		} else {
-			if selected.Type == selection.SelectionTypeText {
-				query = strings.ReplaceAll(query, QueryVariableSelectedText, selected.Text)
-			} else {
-				logger.Error(ctx, fmt.Sprintf("selected data is not text, type: %s", selected.Type))
-			}
-		}
-	}
-
-	if strings.Contains(query, QueryVariableActiveBrowserUrl) {
-		activeBrowserUrl := m.activeBrowserUrl
-		query = strings.ReplaceAll(query, QueryVariableActiveBrowserUrl, activeBrowserUrl)
-	}
-
-	return query
-}
-
 func (m *Manager) IsHostStarted(ctx context.Context, runtime Runtime) bool {
 	if runtime == PLUGIN_RUNTIME_GO {
 		return true
@@ -1195,6 +2177,49 @@ func (m *Manager) IsHostStarted(ctx context.Context, runtime Runtime) bool {
 	return false
 }
 
+// StartQuerySession notifies plugins that a query session is started.
+// A query session starts when UI reports the window is shown (/on/show) and ends when UI reports the window is hidden (/on/hide),
+// all queries typed in between belong to the same session. Duplicated show events within a session are ignored.
+func (m *Manager) StartQuerySession(ctx context.Context) {
+	if !m.querySessionActive.CompareAndSwap(false, true) {
+		return
+	}
+
+	logger.Debug(ctx, "query session started")
+	for _, instance := range m.getInstances() {
+		m.executeQuerySessionCallbacks(ctx, instance, instance.QuerySessionStartCallbacks, "query session start")
+	}
+	m.preloadResults(ctx)
+}
+
+// EndQuerySession notifies plugins that current query session is ended, see StartQuerySession
+func (m *Manager) EndQuerySession(ctx context.Context) {
+	if !m.querySessionActive.CompareAndSwap(true, false) {
+		return
+	}
+
+	logger.Debug(ctx, "query session ended")
+	m.preloaded.Store(nil)
+	m.pendingCarry.Store(nil)
+	m.cancelTriggerKeywordGrace()
+	m.alternativeGroups.reset()
+	for _, instance := range m.getInstances() {
+		m.executeQuerySessionCallbacks(ctx, instance, instance.QuerySessionEndCallbacks, "query session end")
+	}
+}
+
+func (m *Manager) executeQuerySessionCallbacks(ctx context.Context, pluginInstance *Instance, callbacks []func(), desc string) {
+	if pluginInstance.Setting.Disabled {
+		return
+	}
+
+	for _, callback := range callbacks {
+		util.Go(ctx, fmt.Sprintf("[%s] execute %s callback", pluginInstance.Metadata.Name, desc), func() {
+			callback()
+		})
+	}
+}
+
 func (m *Manager) GetAIProvider(ctx context.Context, provider ai.ProviderName) (ai.Provider, error) {
 	if v, exist := m.aiProviders.Load(provider); exist {
 		return v, nil
@@ -1218,7 +2243,7 @@ func (m *Manager) GetAIProvider(ctx context.Context, provider ai.ProviderName) (
 }
 
 func (m *Manager) ExecutePluginDeeplink(ctx context.Context, pluginId string, arguments map[string]string) {
-	pluginInstance, exist := lo.Find(m.instances, func(item *Instance) bool {
+	pluginInstance, exist := lo.Find(m.getInstances(), func(item *Instance) bool {
 		return item.Metadata.Id == pluginId
 	})
 	if !exist {
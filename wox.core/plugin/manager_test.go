@@ -1,10 +1,15 @@
 package plugin
 
 import (
+	"context"
+	"errors"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 	"wox/setting"
 	"wox/util"
+	"wox/util/selection"
 )
 
 func Test_QueryShortcut(t *testing.T) {
@@ -34,3 +39,282 @@ func Test_QueryShortcut(t *testing.T) {
 	query = GetPluginManager().expandQueryShortcut(util.NewTraceContext(), "wix 1", shortcuts)
 	assert.Equal(t, "wpm install 1 x {1}", query)
 }
+
+func Test_RefreshWithDeadline(t *testing.T) {
+	ctx := util.NewTraceContext()
+	var aborted = make(chan bool, 1)
+	resultCache := &QueryResultCache{
+		ResultTitle:    "slow",
+		PluginInstance: &Instance{Metadata: Metadata{Name: "test"}},
+		Refresh: func(ctx context.Context, result RefreshableResult) RefreshableResult {
+			select {
+			case <-time.After(time.Second * 5):
+				result.Title = "refreshed"
+			case <-ctx.Done():
+				aborted <- true
+			}
+			return result
+		},
+	}
+
+	start := time.Now()
+	result, err := GetPluginManager().refreshWithDeadline(ctx, resultCache, RefreshableResult{Title: "old", RefreshInterval: 100}, time.Second)
+	assert.Error(t, err)
+	assert.Equal(t, "old", result.Title)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, <-aborted)
+
+	resultCache.Refresh = func(ctx context.Context, result RefreshableResult) RefreshableResult {
+		result.Title = "refreshed"
+		return result
+	}
+	result, err = GetPluginManager().refreshWithDeadline(ctx, resultCache, RefreshableResult{Title: "old"}, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "refreshed", result.Title)
+}
+
+func Test_SelectionResultUpdateAfterAction(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "selection-update-test", Name: "selection update test"})
+	query := Query{
+		Type:      QueryTypeSelection,
+		Selection: selection.Selection{Type: selection.SelectionTypeText, Text: "hello"},
+	}
+
+	// placeholder result, action uploads the selection and pushes the url
+	var resultId string
+	result := m.PolishResult(ctx, instance, query, QueryResult{
+		Title: "Upload selection",
+		Actions: []QueryResultAction{
+			{
+				Name: "Upload",
+				Action: func(ctx context.Context, actionContext ActionContext) {
+					m.UpdateResult(ctx, instance, resultId, RefreshableResult{
+						Title: "https://example.com/" + actionContext.Selection.Text,
+					})
+				},
+			},
+		},
+		RefreshInterval: 100,
+		OnRefresh: func(ctx context.Context, current RefreshableResult) RefreshableResult {
+			current.SubTitle = "refreshed " + query.Selection.Text
+			return current
+		},
+	})
+	resultId = result.Id
+
+	assert.Nil(t, m.ExecuteAction(ctx, resultId, result.Actions[0].Id))
+	_, updates := m.resultUpdater.takeAll()
+	update, found := updates[resultId]
+	assert.True(t, found)
+	assert.Equal(t, "https://example.com/hello", update.result.Title)
+	assert.Equal(t, QueryTypeSelection, update.cache.Query.Type)
+	assert.Equal(t, "hello", update.cache.Query.Selection.Text)
+	assert.Equal(t, "hello", update.cache.Preview.PreviewData)
+
+	refreshed, refreshErr := m.ExecuteRefresh(ctx, m.toRefreshableResultWithResultId(ctx, update.cache, update.result), true)
+	assert.Nil(t, refreshErr)
+	assert.Equal(t, "https://example.com/hello", refreshed.Title)
+	assert.Equal(t, "refreshed hello", refreshed.SubTitle)
+}
+
+func Test_TriggerKeywordInActionContext(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "trigger-keyword-test", Name: "trigger keyword test", TriggerKeywords: []string{"gh", "github"}})
+
+	for _, keyword := range []string{"gh", "github"} {
+		query, queryPlugin := newQueryInputWithPlugins(keyword+" issues", []*Instance{instance})
+		assert.Equal(t, instance, queryPlugin)
+
+		var actionKeyword, refreshKeyword string
+		result := m.PolishResult(ctx, instance, query, QueryResult{
+			Title: "Open issues",
+			Actions: []QueryResultAction{
+				{
+					Name:        "Open",
+					AllowRepeat: true,
+					Action: func(ctx context.Context, actionContext ActionContext) {
+						actionKeyword = actionContext.TriggerKeyword
+					},
+				},
+			},
+			RefreshInterval: 100,
+			OnRefresh: func(ctx context.Context, current RefreshableResult) RefreshableResult {
+				refreshKeyword = current.TriggerKeyword
+				return current
+			},
+		})
+
+		assert.Nil(t, m.ExecuteAction(ctx, result.Id, result.Actions[0].Id))
+		assert.Equal(t, keyword, actionKeyword)
+
+		resultCache, found := m.loadResultCache(result.Id)
+		assert.True(t, found)
+		_, refreshErr := m.ExecuteRefresh(ctx, m.toRefreshableResultWithResultId(ctx, resultCache, RefreshableResult{Title: result.Title, RefreshInterval: 100}), true)
+		assert.Nil(t, refreshErr)
+		assert.Equal(t, keyword, refreshKeyword)
+	}
+}
+
+func Test_SuppressPluginResultsAction(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "suppress-results-test", Name: "suppress results test", TriggerKeywords: []string{"*", "st"}})
+	findSuppressAction := func(query Query) (QueryResultAction, bool) {
+		return lo.Find(m.getDefaultActions(ctx, instance, query, "id", "title", "", ""), func(item QueryResultAction) bool {
+			return item.Name == "i18n:plugin_manager_suppress_plugin_results"
+		})
+	}
+
+	// user asked for the plugin by its trigger keyword, hiding its results makes no sense
+	_, found := findSuppressAction(Query{Type: QueryTypeInput, RawQuery: "st foo", TriggerKeyword: "st", Search: "foo"})
+	assert.False(t, found)
+	_, found = findSuppressAction(Query{Type: QueryTypeInput, RawQuery: " "})
+	assert.False(t, found)
+
+	action, found := findSuppressAction(Query{Type: QueryTypeInput, RawQuery: "foo", Search: "foo"})
+	assert.True(t, found)
+	action.Action(ctx, ActionContext{})
+	defer setting.GetSettingManager().RemoveSuppressedQuery(ctx, instance.Metadata.Id, "foo", setting.SuppressedQueryScopeExact)
+	assert.True(t, setting.GetSettingManager().IsQuerySuppressed(ctx, instance.Metadata.Id, "foo"))
+}
+
+func Test_UpdateResultScore(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	newInstance := func(id string) *Instance {
+		return newTestPluginInstance(Metadata{Id: id, Name: id})
+	}
+	instance := newInstance("score-update-test")
+	result := m.PolishResult(ctx, instance, Query{Type: QueryTypeInput}, QueryResult{Title: "Looking up", Score: 10})
+
+	assert.True(t, m.UpdateResultScore(ctx, instance, result.Id, 30))
+	// latest score wins
+	assert.True(t, m.UpdateResultScore(ctx, instance, result.Id, 50))
+	// result of other plugin or not in current query
+	assert.False(t, m.UpdateResultScore(ctx, newInstance("other-plugin"), result.Id, 100))
+	assert.False(t, m.UpdateResultScore(ctx, instance, "unknown-result-id", 100))
+
+	scores := m.resultUpdater.takeAllScores()
+	assert.Len(t, scores, 1)
+	assert.Equal(t, int64(50), scores[result.Id].score)
+	assert.Empty(t, m.resultUpdater.takeAllScores())
+
+	resultCache, found := m.loadResultCache(result.Id)
+	assert.True(t, found)
+	assert.Equal(t, int64(50), resultCache.Score.Load())
+
+	// updated score decays like the score of a new result
+	now := time.Now()
+	resultCache.Timestamp = now.Add(-time.Hour).UnixMilli()
+	resultCache.ScoreHalfLife = time.Hour.Milliseconds()
+	assert.Equal(t, decayScore(50, resultCache.Timestamp, resultCache.ScoreHalfLife, now), resultCache.decayedScore(now))
+	assert.Less(t, resultCache.decayedScore(now), int64(50))
+}
+
+type initErrorTestPlugin struct {
+	initPanic bool
+}
+
+func (p *initErrorTestPlugin) Init(ctx context.Context, initParams InitParams) {
+	if p.initPanic {
+		panic("missing dependency")
+	}
+}
+
+func (p *initErrorTestPlugin) Query(ctx context.Context, query Query) []QueryResult {
+	return []QueryResult{{Title: "ok"}}
+}
+
+func Test_PluginInitError(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	testPlugin := &initErrorTestPlugin{initPanic: true}
+	instance := newTestPluginInstance(Metadata{Id: "init-error-test", Name: "init-error-test", TriggerKeywords: []string{"*", "ie"}})
+	instance.Plugin = testPlugin
+	instance.API = NewAPI(instance)
+
+	m.initPlugin(ctx, instance)
+	assert.Contains(t, instance.GetInitError(), "missing dependency")
+
+	// global queries skip the plugin, triggered queries get an error result with an action to open settings
+	assert.False(t, m.canOperateQuery(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "foo", Search: "foo"}))
+	triggered := Query{Type: QueryTypeInput, RawQuery: "ie foo", TriggerKeyword: "ie", Search: "foo"}
+	assert.True(t, m.canOperateQuery(ctx, instance, triggered))
+	results := m.queryForPlugin(ctx, instance, triggered)
+	assert.Len(t, results, 1)
+	assert.Contains(t, results[0].SubTitle, "missing dependency")
+	assert.True(t, results[0].Actions[0].IsDefault)
+	assert.True(t, results[0].Actions[0].IsSystemAction)
+
+	// error is cleared after successful re-init
+	testPlugin.initPanic = false
+	m.initPlugin(ctx, instance)
+	assert.Empty(t, instance.GetInitError())
+	assert.True(t, m.canOperateQuery(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "foo", Search: "foo"}))
+
+	// plugins can report and clear init errors themselves
+	instance.API.ReportInitError(ctx, errors.New("api key is missing"))
+	assert.Equal(t, "api key is missing", instance.GetInitError())
+	instance.API.ReportInitError(ctx, nil)
+	assert.Empty(t, instance.GetInitError())
+}
+
+func Test_EnablePlugin(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := &Instance{
+		Metadata: Metadata{Id: "enable-plugin-test", Name: "enable-plugin-test", TriggerKeywords: []string{"ep"}},
+		Plugin:   &initErrorTestPlugin{initPanic: true},
+		Setting:  &setting.PluginSetting{Disabled: true, Settings: util.NewHashMap[string, string]()},
+	}
+	instance.API = NewAPI(instance)
+
+	// plugin disabled at startup was never initialized, enabling it runs init
+	assert.Nil(t, m.EnablePlugin(ctx, instance))
+	assert.False(t, instance.Setting.Disabled)
+	assert.Eventually(t, func() bool {
+		return instance.GetInitError() != ""
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_ModifiersInActionContext(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "modifiers-test", Name: "modifiers test", TriggerKeywords: []string{"*"}})
+
+	var openInBackground bool
+	var modifiers []string
+	query, _ := newQueryInputWithPlugins("open", []*Instance{instance})
+	result := m.PolishResult(ctx, instance, query, QueryResult{
+		Title: "Open url",
+		Actions: []QueryResultAction{
+			{
+				Name:        "Open",
+				AllowRepeat: true,
+				Action: func(ctx context.Context, actionContext ActionContext) {
+					openInBackground = actionContext.HasModifier("shift")
+					modifiers = actionContext.Modifiers
+				},
+			},
+		},
+	})
+
+	assert.Nil(t, m.ExecuteActionWithModifiers(ctx, result.Id, result.Actions[0].Id, []string{"Shift", "unknown", "shift"}))
+	assert.True(t, openInBackground)
+	assert.Equal(t, []string{"shift"}, modifiers)
+
+	// existing callers invoke actions without modifiers
+	assert.Nil(t, m.ExecuteAction(ctx, result.Id, result.Actions[0].Id))
+	assert.False(t, openInBackground)
+	assert.Empty(t, modifiers)
+}
@@ -1,12 +1,15 @@
 package plugin
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"path"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"wox/setting/definition"
+	"wox/util"
 )
 
 type MetadataFeatureName = string
@@ -31,6 +34,63 @@ const (
 
 	// enable this feature to execute custom deep link in plugin
 	MetadataFeatureDeepLink MetadataFeatureName = "deepLink"
+
+	// enable this feature to let Wox don't record actioned results of this plugin into recent results
+	MetadataFeatureIgnoreRecentResult MetadataFeatureName = "ignoreRecentResult"
+
+	// enable this feature to let Wox parse command of global queries (queries without trigger keyword), E.g. "todo:done buy milk"
+	// params see MetadataFeatureParamsGlobalCommand
+	MetadataFeatureGlobalCommand MetadataFeatureName = "globalCommand"
+
+	// enable this feature to tell UI how many results this plugin usually returns, so UI can reserve space before results arrive
+	// params see MetadataFeatureParamsResultCountHint
+	MetadataFeatureResultCountHint MetadataFeatureName = "resultCountHint"
+
+	// enable this feature to let Wox keep the order in which this plugin returns results (E.g. steps of a process),
+	// instead of sorting them by score. See preserveResultOrder for how they are placed among results of other plugins
+	MetadataFeaturePreserveOrder MetadataFeatureName = "preserveOrder"
+
+	// enable this feature to get Query.UnknownCommand when user typed something like a command which is not declared,
+	// Wox will also show a result suggesting the closest command if one is close enough. By default, unknown commands are treated as search silently
+	MetadataFeatureUnknownCommand MetadataFeatureName = "unknownCommand"
+
+	// enable this feature to let Wox decay scores of results by their age (see QueryResult.Timestamp), so newer results rank higher.
+	// params see MetadataFeatureParamsScoreDecay
+	MetadataFeatureScoreDecay MetadataFeatureName = "scoreDecay"
+
+	// enable this feature to let Wox query this plugin with an empty query when Wox is shown, results are shown before user types anything
+	// (E.g. recent items, favorites). See Manager.GetPreloadedResults
+	MetadataFeaturePreloadOnShow MetadataFeatureName = "preloadOnShow"
+
+	// enable this feature to get queries when query box is empty (E.g. a home screen with recent items, favorites or suggestions),
+	// other plugins never get empty queries. Unlike MetadataFeaturePreloadOnShow, plugin is queried every time query becomes empty,
+	// after a short debounce (see emptyQueryDebounceMs) so deleting the query with backspace doesn't query it on the way
+	MetadataFeatureEmptyQuery MetadataFeatureName = "emptyQuery"
+
+	// enable this feature to get Query.SelectionLanguage, the detected language of selected text (E.g. to pick target language of translation)
+	MetadataFeatureDetectSelectionLanguage MetadataFeatureName = "detectSelectionLanguage"
+
+	// enable this feature to tune how permissive shared string matchers are for this plugin (E.g. tight for a command list, loose for a file finder).
+	// Without it, matching uses normal sensitivity and no minimum score. params see MetadataFeatureParamsMatchSensitivity
+	MetadataFeatureMatchSensitivity MetadataFeatureName = "matchSensitivity"
+
+	// enable this feature to let plugin claim queries it recognizes (E.g. a full url), other plugins are not queried for a claimed query
+	// and only results of this plugin are shown. Plugin must implement QueryClaimer, if several plugins claim a query the first one wins
+	MetadataFeatureClaimQuery MetadataFeatureName = "claimQuery"
+
+	// enable this feature to let Wox skip querying this plugin until search is long enough (E.g. a network search shouldn't fire on a single character).
+	// params see MetadataFeatureParamsMinSearchLength
+	MetadataFeatureMinSearchLength MetadataFeatureName = "minSearchLength"
+)
+
+type MetadataPermission = string
+
+// permissions of sensitive operations, plugin must declare them in plugin.json and user will be asked to grant on first use.
+// see Manager.CheckPermission
+const (
+	MetadataPermissionClipboard MetadataPermission = "clipboard" // no API reads clipboard, plugin asks it with API.RequestPermission
+	MetadataPermissionSelection MetadataPermission = "selection" // selection queries, only checked if declared for backward compatibility
+	MetadataPermissionNetwork   MetadataPermission = "network"   // requests sent by API.GetHTTPClient
 )
 
 // Metadata parsed from plugin.json, see `Plugin.json.md` for more detail
@@ -50,14 +110,27 @@ type Metadata struct {
 	Commands           []MetadataCommand
 	SupportedOS        []string
 	Features           []MetadataFeature
+	Permissions        []MetadataPermission
 	SettingDefinitions definition.PluginSettingDefinitions
 }
 
-func (m *Metadata) GetIconOrDefault(pluginDirectory string, defaultImage WoxImage) WoxImage {
-	image := ParseWoxImageOrDefault(m.Icon, defaultImage)
+// GetIconOrDefault returns the plugin icon with relative path resolved against pluginDirectory, defaultImage if icon is missing or invalid
+func (m *Metadata) GetIconOrDefault(ctx context.Context, pluginDirectory string, defaultImage WoxImage) WoxImage {
+	if m.Icon == "" {
+		return defaultImage
+	}
+	image, parseErr := ParseWoxImage(m.Icon)
+	if parseErr != nil {
+		util.GetLogger().Warn(ctx, fmt.Sprintf("<%s> invalid plugin icon, use default icon: %s", m.Name, parseErr.Error()))
+		return defaultImage
+	}
 	if image.ImageType == WoxImageTypeRelativePath {
-		image.ImageData = path.Join(pluginDirectory, image.ImageData)
-		image.ImageType = WoxImageTypeAbsolutePath
+		absolutePath, resolveErr := resolvePluginResource(pluginDirectory, image.ImageData)
+		if resolveErr != nil {
+			util.GetLogger().Warn(ctx, fmt.Sprintf("<%s> failed to resolve plugin icon, use default icon: %s", m.Name, resolveErr.Error()))
+			return defaultImage
+		}
+		return NewWoxImageAbsolutePath(absolutePath)
 	}
 	return image
 }
@@ -71,6 +144,15 @@ func (m *Metadata) IsSupportFeature(f MetadataFeatureName) bool {
 	return false
 }
 
+func (m *Metadata) IsDeclarePermission(p MetadataPermission) bool {
+	for _, permission := range m.Permissions {
+		if strings.ToLower(permission) == strings.ToLower(p) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Metadata) GetFeatureParamsForDebounce() (MetadataFeatureParamsDebounce, error) {
 	for _, feature := range m.Features {
 		if strings.ToLower(feature.Name) == strings.ToLower(MetadataFeatureDebounce) {
@@ -126,14 +208,156 @@ func (m *Metadata) GetFeatureParamsForQueryEnv() (MetadataFeatureParamsQueryEnv,
 	return MetadataFeatureParamsQueryEnv{}, errors.New("plugin does not support queryEnv feature")
 }
 
+func (m *Metadata) GetFeatureParamsForGlobalCommand() (MetadataFeatureParamsGlobalCommand, error) {
+	for _, feature := range m.Features {
+		if strings.ToLower(feature.Name) == strings.ToLower(MetadataFeatureGlobalCommand) {
+			v, ok := feature.Params["pattern"]
+			if !ok {
+				return MetadataFeatureParamsGlobalCommand{}, errors.New("globalCommand feature does not have pattern param")
+			}
+			pattern, compileErr := regexp.Compile(v)
+			if compileErr != nil {
+				return MetadataFeatureParamsGlobalCommand{}, fmt.Errorf("globalCommand feature pattern param is not a valid regex: %s", compileErr.Error())
+			}
+			if pattern.SubexpIndex("command") < 0 {
+				return MetadataFeatureParamsGlobalCommand{}, errors.New("globalCommand feature pattern param must have a named group: command")
+			}
+
+			return MetadataFeatureParamsGlobalCommand{
+				Pattern: pattern,
+			}, nil
+		}
+	}
+
+	return MetadataFeatureParamsGlobalCommand{}, errors.New("plugin does not support globalCommand feature")
+}
+
+func (m *Metadata) GetFeatureParamsForResultCountHint() (MetadataFeatureParamsResultCountHint, error) {
+	for _, feature := range m.Features {
+		if strings.ToLower(feature.Name) == strings.ToLower(MetadataFeatureResultCountHint) {
+			v, ok := feature.Params["estimatedCount"]
+			if !ok {
+				return MetadataFeatureParamsResultCountHint{}, errors.New("resultCountHint feature does not have estimatedCount param")
+			}
+			estimatedCount, convertErr := strconv.Atoi(v)
+			if convertErr != nil {
+				return MetadataFeatureParamsResultCountHint{}, fmt.Errorf("resultCountHint feature estimatedCount param is not a valid number: %s", convertErr.Error())
+			}
+			if estimatedCount <= 0 {
+				return MetadataFeatureParamsResultCountHint{}, errors.New("resultCountHint feature estimatedCount param must be greater than 0")
+			}
+
+			return MetadataFeatureParamsResultCountHint{
+				EstimatedCount: estimatedCount,
+			}, nil
+		}
+	}
+
+	return MetadataFeatureParamsResultCountHint{}, errors.New("plugin does not support resultCountHint feature")
+}
+
+func (m *Metadata) GetFeatureParamsForScoreDecay() (MetadataFeatureParamsScoreDecay, error) {
+	for _, feature := range m.Features {
+		if strings.ToLower(feature.Name) == strings.ToLower(MetadataFeatureScoreDecay) {
+			v, ok := feature.Params["halfLife"]
+			if !ok {
+				return MetadataFeatureParamsScoreDecay{}, errors.New("scoreDecay feature does not have halfLife param")
+			}
+			halfLife, parseErr := time.ParseDuration(v)
+			if parseErr != nil {
+				return MetadataFeatureParamsScoreDecay{}, fmt.Errorf("scoreDecay feature halfLife param is not a valid duration (E.g. 6h): %s", parseErr.Error())
+			}
+			if halfLife <= 0 {
+				return MetadataFeatureParamsScoreDecay{}, errors.New("scoreDecay feature halfLife param must be greater than 0")
+			}
+
+			return MetadataFeatureParamsScoreDecay{
+				HalfLife: halfLife,
+			}, nil
+		}
+	}
+
+	return MetadataFeatureParamsScoreDecay{}, errors.New("plugin does not support scoreDecay feature")
+}
+
+func (m *Metadata) GetFeatureParamsForMatchSensitivity() (MetadataFeatureParamsMatchSensitivity, error) {
+	for _, feature := range m.Features {
+		if strings.ToLower(feature.Name) == strings.ToLower(MetadataFeatureMatchSensitivity) {
+			params := MetadataFeatureParamsMatchSensitivity{
+				Sensitivity: util.MatchSensitivityNormal,
+			}
+			if v, ok := feature.Params["sensitivity"]; ok {
+				sensitivity := strings.ToLower(v)
+				if sensitivity != util.MatchSensitivityStrict && sensitivity != util.MatchSensitivityNormal && sensitivity != util.MatchSensitivityLoose {
+					return MetadataFeatureParamsMatchSensitivity{}, fmt.Errorf("matchSensitivity feature sensitivity param must be strict, normal or loose: %s", v)
+				}
+				params.Sensitivity = sensitivity
+			}
+			if v, ok := feature.Params["minScore"]; ok {
+				minScore, convertErr := strconv.ParseInt(v, 10, 64)
+				if convertErr != nil {
+					return MetadataFeatureParamsMatchSensitivity{}, fmt.Errorf("matchSensitivity feature minScore param is not a valid number: %s", convertErr.Error())
+				}
+				params.MinScore = minScore
+			}
+
+			return params, nil
+		}
+	}
+
+	return MetadataFeatureParamsMatchSensitivity{}, errors.New("plugin does not support matchSensitivity feature")
+}
+
+func (m *Metadata) GetFeatureParamsForMinSearchLength() (MetadataFeatureParamsMinSearchLength, error) {
+	for _, feature := range m.Features {
+		if strings.ToLower(feature.Name) == strings.ToLower(MetadataFeatureMinSearchLength) {
+			v, ok := feature.Params["length"]
+			if !ok {
+				return MetadataFeatureParamsMinSearchLength{}, errors.New("minSearchLength feature does not have length param")
+			}
+			length, convertErr := strconv.Atoi(v)
+			if convertErr != nil {
+				return MetadataFeatureParamsMinSearchLength{}, fmt.Errorf("minSearchLength feature length param is not a valid number: %s", convertErr.Error())
+			}
+			if length < 0 {
+				return MetadataFeatureParamsMinSearchLength{}, errors.New("minSearchLength feature length param must not be negative")
+			}
+
+			params := MetadataFeatureParamsMinSearchLength{
+				Length:          length,
+				TriggeredLength: length,
+			}
+			if v, ok := feature.Params["triggeredLength"]; ok {
+				triggeredLength, convertErr := strconv.Atoi(v)
+				if convertErr != nil {
+					return MetadataFeatureParamsMinSearchLength{}, fmt.Errorf("minSearchLength feature triggeredLength param is not a valid number: %s", convertErr.Error())
+				}
+				if triggeredLength < 0 {
+					return MetadataFeatureParamsMinSearchLength{}, errors.New("minSearchLength feature triggeredLength param must not be negative")
+				}
+				params.TriggeredLength = triggeredLength
+			}
+
+			return params, nil
+		}
+	}
+
+	return MetadataFeatureParamsMinSearchLength{}, errors.New("plugin does not support minSearchLength feature")
+}
+
 type MetadataFeature struct {
 	Name   MetadataFeatureName
 	Params map[string]string
 }
 
+// MetadataCommand declares a command of the plugin, see Query.Command. In plugin.json a command can also be a simple string (E.g. "install")
 type MetadataCommand struct {
 	Command     string
 	Description string
+	// Optional, other names of the command (E.g. "i" for "install"), Query.Command is always the canonical Command
+	Aliases []string
+	// Optional, positional arguments of the command. If declared, Wox parses Search into Query.CommandInvocation
+	Arguments []MetadataCommandArgument
 }
 
 type MetadataWithDirectory struct {
@@ -149,6 +373,30 @@ type MetadataFeatureParamsDebounce struct {
 	intervalMs int
 }
 
+type MetadataFeatureParamsGlobalCommand struct {
+	// Regex matched against global queries, named group "command" becomes Query.Command and optional named group "search" becomes Query.Search.
+	// If there is no search group, text after the match becomes Query.Search. E.g. `^todo:(?P<command>\w+)`
+	Pattern *regexp.Regexp
+}
+
+type MetadataFeatureParamsResultCountHint struct {
+	EstimatedCount int // estimated number of results per query, it's a hint only
+}
+
+type MetadataFeatureParamsScoreDecay struct {
+	HalfLife time.Duration // score of a result halves every HalfLife since its timestamp
+}
+
+type MetadataFeatureParamsMatchSensitivity struct {
+	Sensitivity util.MatchSensitivity // defaults to normal
+	MinScore    int64                 // matches scoring lower are dropped, defaults to 0 (no threshold)
+}
+
+type MetadataFeatureParamsMinSearchLength struct {
+	Length          int // min characters of Query.Search for global queries
+	TriggeredLength int // min characters of Query.Search for queries with trigger keyword of this plugin, defaults to Length
+}
+
 type MetadataFeatureParamsQueryEnv struct {
 	RequireActiveWindowName bool
 	RequireActiveWindowPid  bool
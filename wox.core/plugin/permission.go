@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wox/i18n"
+	"wox/share"
+	"wox/util"
+)
+
+var ErrPermissionNotDeclared = errors.New("permission is not declared in plugin.json")
+var ErrPermissionDenied = errors.New("permission is denied by user")
+var ErrPermissionPending = errors.New("permission is waiting for user to grant")
+
+// PermissionRequest is a permission plugin asked for but user hasn't decided yet
+type PermissionRequest struct {
+	PluginInstance *Instance
+	Permission     MetadataPermission
+	Timestamp      int64
+}
+
+// CheckPermission checks whether plugin can perform a sensitive operation.
+//
+// Plugin must declare the permission in plugin.json first. On first use, user is notified and the request is pending until
+// user allows or denies it in the permission query (see GrantPermission), the decision is persisted in plugin setting.
+func (m *Manager) CheckPermission(ctx context.Context, pluginInstance *Instance, permission MetadataPermission) error {
+	if !pluginInstance.Metadata.IsDeclarePermission(permission) {
+		return fmt.Errorf("%s: %w", permission, ErrPermissionNotDeclared)
+	}
+
+	if pluginInstance.Setting.PermissionGrants != nil {
+		if granted, decided := pluginInstance.Setting.PermissionGrants.Load(permission); decided {
+			if granted {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", permission, ErrPermissionDenied)
+		}
+	}
+
+	requestKey := getPermissionRequestKey(pluginInstance, permission)
+	if m.permissionRequests.NotExist(requestKey) {
+		m.permissionRequests.Store(requestKey, PermissionRequest{
+			PluginInstance: pluginInstance,
+			Permission:     permission,
+			Timestamp:      util.GetSystemTimestamp(),
+		})
+		logger.Info(ctx, fmt.Sprintf("<%s> request permission: %s", pluginInstance.Metadata.Name, permission))
+		if m.ui != nil {
+			icon := pluginInstance.Metadata.GetIconOrDefault(ctx, pluginInstance.PluginDirectory, DefaultActionIcon)
+			m.ui.Notify(ctx, share.NotifyMsg{
+				Icon:           icon.String(),
+				Text:           fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_permission_request"), pluginInstance.Metadata.Name, permission),
+				DisplaySeconds: 5,
+			})
+		}
+	}
+
+	return fmt.Errorf("%s: %w", permission, ErrPermissionPending)
+}
+
+// GrantPermission persists user's decision of a permission
+func (m *Manager) GrantPermission(ctx context.Context, pluginInstance *Instance, permission MetadataPermission, granted bool) error {
+	if pluginInstance.Setting.PermissionGrants == nil {
+		pluginInstance.Setting.PermissionGrants = util.NewHashMap[string, bool]()
+	}
+	pluginInstance.Setting.PermissionGrants.Store(permission, granted)
+	m.permissionRequests.Delete(getPermissionRequestKey(pluginInstance, permission))
+
+	logger.Info(ctx, fmt.Sprintf("<%s> permission %s granted: %t", pluginInstance.Metadata.Name, permission, granted))
+	return pluginInstance.SaveSetting(ctx)
+}
+
+// RevokePermission removes user's decision of a permission, user will be asked again on next use
+func (m *Manager) RevokePermission(ctx context.Context, pluginInstance *Instance, permission MetadataPermission) error {
+	if pluginInstance.Setting.PermissionGrants == nil {
+		return nil
+	}
+	pluginInstance.Setting.PermissionGrants.Delete(permission)
+
+	logger.Info(ctx, fmt.Sprintf("<%s> permission %s revoked", pluginInstance.Metadata.Name, permission))
+	return pluginInstance.SaveSetting(ctx)
+}
+
+func (m *Manager) GetPermissionRequests() []PermissionRequest {
+	var requests []PermissionRequest
+	m.permissionRequests.Range(func(_ string, request PermissionRequest) bool {
+		requests = append(requests, request)
+		return true
+	})
+	return requests
+}
+
+func getPermissionRequestKey(pluginInstance *Instance, permission MetadataPermission) string {
+	return fmt.Sprintf("%s|%s", pluginInstance.Metadata.Id, permission)
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPermission(t *testing.T) {
+	ctx := context.Background()
+	m := GetPluginManager()
+
+	instance := &Instance{
+		Metadata: Metadata{Id: "permission-test", Name: "permission test", Permissions: []MetadataPermission{MetadataPermissionNetwork}},
+		Setting:  &setting.PluginSetting{Settings: util.NewHashMap[string, string]()},
+	}
+	defer m.permissionRequests.Delete(getPermissionRequestKey(instance, MetadataPermissionNetwork))
+
+	assert.True(t, errors.Is(m.CheckPermission(ctx, instance, MetadataPermissionClipboard), ErrPermissionNotDeclared))
+
+	// first use waits for user to decide
+	assert.True(t, errors.Is(m.CheckPermission(ctx, instance, MetadataPermissionNetwork), ErrPermissionPending))
+	assert.Len(t, m.GetPermissionRequests(), 1)
+
+	instance.Setting.PermissionGrants = util.NewHashMap[string, bool]()
+	instance.Setting.PermissionGrants.Store(MetadataPermissionNetwork, false)
+	assert.True(t, errors.Is(m.CheckPermission(ctx, instance, MetadataPermissionNetwork), ErrPermissionDenied))
+
+	instance.Setting.PermissionGrants.Store(MetadataPermissionNetwork, true)
+	assert.NoError(t, m.CheckPermission(ctx, instance, MetadataPermissionNetwork))
+}
+
+func TestHTTPClient_NetworkPermission(t *testing.T) {
+	ctx := context.Background()
+	m := GetPluginManager()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	undeclared := &Instance{
+		Metadata: Metadata{Id: "network-permission-undeclared-test", Name: "network permission undeclared test"},
+		Setting:  &setting.PluginSetting{Settings: util.NewHashMap[string, string]()},
+	}
+	_, err := undeclared.HTTPClient().Get(ctx, server.URL, nil)
+	assert.True(t, errors.Is(err, ErrPermissionNotDeclared))
+
+	declared := &Instance{
+		Metadata: Metadata{Id: "network-permission-test", Name: "network permission test", Permissions: []MetadataPermission{MetadataPermissionNetwork}},
+		Setting:  &setting.PluginSetting{Settings: util.NewHashMap[string, string]()},
+	}
+	defer m.permissionRequests.Delete(getPermissionRequestKey(declared, MetadataPermissionNetwork))
+	_, err = declared.HTTPClient().Get(ctx, server.URL, nil)
+	assert.True(t, errors.Is(err, ErrPermissionPending))
+	assert.Equal(t, 0, requests)
+
+	declared.Setting.PermissionGrants = util.NewHashMap[string, bool]()
+	declared.Setting.PermissionGrants.Store(MetadataPermissionNetwork, true)
+	body, err := declared.HTTPClient().Get(ctx, server.URL, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,21 @@
+package plugin
+
+// PickFileOptions configures the native file picker opened by API.PickFile
+type PickFileOptions struct {
+	// Title of the picker, support i18n. Optional
+	Title string
+	// Directory the picker starts in. Optional, empty means the last directory the OS remembers
+	DefaultDirectory string
+	// Allowed file extensions without dot, E.g. ["png", "jpg"]. Optional, empty means all files
+	Extensions []string
+	// If true, user can pick several files
+	AllowMultiple bool
+}
+
+// PickFolderOptions configures the native folder picker opened by API.PickFolder
+type PickFolderOptions struct {
+	// Title of the picker, support i18n. Optional
+	Title string
+	// Directory the picker starts in. Optional, empty means the last directory the OS remembers
+	DefaultDirectory string
+}
@@ -19,6 +19,13 @@ type FallbackSearcher interface {
 	QueryFallback(ctx context.Context, query Query) []QueryResult
 }
 
+// ResultSorter orders results returned by the plugin itself (E.g. by date, then by relevance), instead of by score.
+// CompareResults returns a negative number if a should be shown before b, zero keeps the order in which they were returned.
+// Results of other plugins are still ordered by score, see sortPluginResults
+type ResultSorter interface {
+	CompareResults(a, b QueryResult) int
+}
+
 type InitParams struct {
 	API             API
 	PluginDirectory string
@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+// PluginHealth summarizes problems of a plugin for users, see Manager.GetPluginHealth
+type PluginHealth struct {
+	PluginId     string
+	PluginName   string
+	Instance     *Instance // nil if plugin failed to load
+	Disabled     bool
+	ErrorCount   int // query errors and panics
+	TimeoutCount int
+	LoadFailed   bool
+	InitError    string              // why plugin failed to initialize, empty if it's initialized, see Instance.GetInitError
+	RecentErrors []PluginErrorRecord // latest first
+}
+
+func (h PluginHealth) IsHealthy() bool {
+	return !h.Disabled && h.InitError == "" && len(h.RecentErrors) == 0
+}
+
+// GetPluginHealth returns plugins which are disabled or have recent errors (see pluginErrorTracker), ordered by error count desc.
+// Healthy plugins are not returned
+func (m *Manager) GetPluginHealth() (healthList []PluginHealth) {
+	instances := m.GetPluginInstances()
+	for _, instance := range instances {
+		health := m.newPluginHealth(instance.Metadata.Id, instance.Metadata.Name)
+		health.Instance = instance
+		health.Disabled = instance.Setting != nil && instance.Setting.Disabled
+		health.InitError = instance.GetInitError()
+		if !health.IsHealthy() {
+			healthList = append(healthList, health)
+		}
+	}
+
+	// plugins failed to load have errors but no instance
+	for _, pluginId := range m.pluginErrors.pluginIds() {
+		isLoaded := lo.ContainsBy(instances, func(item *Instance) bool {
+			return item.Metadata.Id == pluginId
+		})
+		if !isLoaded {
+			healthList = append(healthList, m.newPluginHealth(pluginId, m.pluginErrors.name(pluginId)))
+		}
+	}
+
+	sort.SliceStable(healthList, func(i, j int) bool {
+		return len(healthList[i].RecentErrors) > len(healthList[j].RecentErrors)
+	})
+	return healthList
+}
+
+func (m *Manager) newPluginHealth(pluginId string, pluginName string) PluginHealth {
+	health := PluginHealth{
+		PluginId:     pluginId,
+		PluginName:   pluginName,
+		RecentErrors: m.pluginErrors.get(pluginId),
+	}
+	for _, record := range health.RecentErrors {
+		switch record.Kind {
+		case PluginErrorKindLoad:
+			health.LoadFailed = true
+		case PluginErrorKindInit:
+			// current init state is InitError, an old init error may have been cleared
+		case PluginErrorKindTimeout:
+			health.TimeoutCount++
+		default:
+			health.ErrorCount++
+		}
+	}
+	return health
+}
+
+// ResetPluginErrors clears recent errors of a plugin, E.g. after user fixed its settings
+func (m *Manager) ResetPluginErrors(pluginId string) {
+	m.pluginErrors.reset(pluginId)
+}
@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"wox/i18n"
+	"wox/share"
+	"wox/util"
+)
+
+// GetInitError returns why plugin failed to initialize, empty if it's initialized or recovered, see Manager.setPluginInitError
+func (i *Instance) GetInitError() string {
+	if initError := i.initError.Load(); initError != nil {
+		return *initError
+	}
+	return ""
+}
+
+// setPluginInitError marks plugin as failed to initialize (E.g. bad config, missing dependency), nil err clears it.
+// Global queries skip the plugin while it has an init error, queries triggered by its keyword get an error result explaining it
+// (see getResultForInitError). The error is cleared every time plugin is initialized again, E.g. reloaded
+func (m *Manager) setPluginInitError(ctx context.Context, pluginInstance *Instance, err error) {
+	if err == nil {
+		if pluginInstance.initError.Swap(nil) != nil {
+			logger.Info(ctx, fmt.Sprintf("<%s> init error cleared", pluginInstance.Metadata.Name))
+		}
+		return
+	}
+
+	message := redactSecrets(err.Error())
+	pluginInstance.initError.Store(&message)
+	m.pluginErrors.add(pluginInstance.Metadata.Id, pluginInstance.Metadata.Name, PluginErrorKindInit, fmt.Sprintf("init failed: %s", message))
+	logger.Error(ctx, fmt.Sprintf("<%s> init failed: %s", pluginInstance.Metadata.Name, message))
+}
+
+// getResultForInitError returns the result shown instead of querying a plugin which failed to initialize,
+// default action opens plugin settings so user can fix the config
+func (m *Manager) getResultForInitError(ctx context.Context, pluginInstance *Instance, initError string) QueryResult {
+	return QueryResult{
+		Title:    fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_manager_init_failed"), pluginInstance.Metadata.Name),
+		SubTitle: util.EllipsisEnd(initError, 60),
+		Icon:     getFailedResultIcon(pluginInstance.Metadata),
+		Preview: WoxPreview{
+			PreviewType: WoxPreviewTypeText,
+			PreviewData: initError,
+		},
+		Actions: []QueryResultAction{
+			{
+				Name:                   "i18n:plugin_manager_global_action_open_plugin_setting",
+				Icon:                   SettingIcon,
+				IsDefault:              true,
+				IsSystemAction:         true,
+				PreventHideAfterAction: true,
+				Action: func(ctx context.Context, actionContext ActionContext) {
+					m.openPluginSetting(ctx, pluginInstance)
+				},
+			},
+		},
+	}
+}
+
+func (m *Manager) openPluginSetting(ctx context.Context, pluginInstance *Instance) {
+	m.GetUI().OpenSettingWindow(ctx, share.SettingWindowContext{
+		Path:  "/plugin/setting",
+		Param: pluginInstance.Metadata.Name,
+	})
+}
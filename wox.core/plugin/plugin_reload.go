@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"wox/i18n"
+	"wox/share"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+// Reload unloads all user plugins (including dev plugins) and loads them again from disk, so changed metadata
+// (E.g. trigger keywords, commands) and plugin code take effect without restarting Wox.
+// System plugins are not reloaded. In-flight queries are cancelled and cached results of user plugins are removed,
+// because their actions belong to the unloaded instances. Plugins failed to load are reported via UI notification.
+func (m *Manager) Reload(ctx context.Context) error {
+	if !m.reloadLock.TryLock() {
+		return fmt.Errorf("plugins are being reloaded")
+	}
+	defer m.reloadLock.Unlock()
+
+	start := util.GetSystemTimestamp()
+	logger.Info(ctx, "start reloading user plugins")
+
+	m.cancelInflightQueries(ctx)
+
+	var devMetadataList []MetadataWithDirectory
+	for _, instance := range m.getInstances() {
+		if instance.IsSystemPlugin {
+			continue
+		}
+		if instance.IsDevPlugin {
+			devMetadataList = append(devMetadataList, MetadataWithDirectory{
+				Metadata:           instance.Metadata,
+				Directory:          instance.PluginDirectory,
+				IsDev:              true,
+				DevPluginDirectory: instance.DevPluginDirectory,
+			})
+		}
+		m.UnloadPlugin(ctx, instance)
+		m.removePluginResultCache(instance)
+	}
+
+	metadataList, scanErr := m.scanUserPluginMetadata(ctx)
+	if scanErr != nil {
+		return scanErr
+	}
+
+	// dev plugins are loaded from their own directories, they take precedence over the installed ones
+	for _, devMetadata := range devMetadataList {
+		metadata, parseErr := m.ParseMetadata(ctx, devMetadata.Directory)
+		if parseErr != nil {
+			m.notifyReloadError(ctx, devMetadata.Metadata.Name, parseErr)
+			continue
+		}
+		devMetadata.Metadata = metadata
+		metadataList = lo.Filter(metadataList, func(item MetadataWithDirectory, _ int) bool {
+			return item.Metadata.Id != metadata.Id
+		})
+		metadataList = append(metadataList, devMetadata)
+	}
+
+	failedCount := 0
+	for _, metadata := range metadataList {
+		pluginHost, exist := lo.Find(AllHosts, func(item Host) bool {
+			return strings.ToLower(string(item.GetRuntime(ctx))) == strings.ToLower(metadata.Metadata.Runtime)
+		})
+		if !exist {
+			failedCount++
+			m.notifyReloadError(ctx, metadata.Metadata.Name, fmt.Errorf("unsupported runtime: %s", metadata.Metadata.Runtime))
+			continue
+		}
+
+		loadErr := m.loadHostPlugin(ctx, pluginHost, metadata)
+		if loadErr != nil {
+			failedCount++
+			m.notifyReloadError(ctx, metadata.Metadata.Name, loadErr)
+			continue
+		}
+	}
+
+	logger.Info(ctx, fmt.Sprintf("finish reloading user plugins, loaded %d, failed %d, cost %d ms", len(metadataList)-failedCount, failedCount, util.GetSystemTimestamp()-start))
+	return nil
+}
+
+// cancelInflightQueries cancels all running query pipelines, subscribers will receive done once plugins returned
+func (m *Manager) cancelInflightQueries(ctx context.Context) {
+	m.inflightLock.Lock()
+	defer m.inflightLock.Unlock()
+
+	for key, inflight := range m.inflightQueries {
+		logger.Info(ctx, fmt.Sprintf("cancel inflight query: %s", key))
+		inflight.cancel()
+	}
+}
+
+// removePluginResultCache removes cached results of given plugin instance, actions of these results can't be executed anymore.
+// Results of a newer instance of the same plugin are kept
+func (m *Manager) removePluginResultCache(pluginInstance *Instance) {
+	pluginResults := m.resultCache.FilterList(func(_ string, resultCache *QueryResultCache) bool {
+		return resultCache.PluginInstance == pluginInstance
+	})
+	for _, resultCache := range pluginResults {
+		m.resultCache.Delete(resultCache.ResultId)
+		m.expandedResults.Delete(resultCache.ResultId)
+		m.resultCacheSize.Add(-estimateResultCacheSize(resultCache))
+		releaseResultCache(resultCache)
+	}
+}
+
+func (m *Manager) notifyReloadError(ctx context.Context, pluginName string, err error) {
+	logger.Error(ctx, fmt.Sprintf("failed to reload plugin %s: %s", pluginName, err.Error()))
+	if m.ui == nil {
+		return
+	}
+	m.ui.Notify(ctx, share.NotifyMsg{
+		Text:           fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_reload_failed"), pluginName, err.Error()),
+		DisplaySeconds: 5,
+	})
+}
@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"regexp"
+	"sync"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+const maxRecentPluginErrors = 10
+
+// secretPattern matches values of secret like keys (E.g. "token=xxx", "Authorization: Bearer xxx") in error messages
+var secretPattern = regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password|passwd)["']?\s*[:=]\s*["']?|bearer\s+)[^\s"',;&]+`)
+
+type PluginErrorKind string
+
+const (
+	PluginErrorKindLoad    PluginErrorKind = "load"    // plugin failed to load
+	PluginErrorKindInit    PluginErrorKind = "init"    // plugin failed to initialize, see Manager.setPluginInitError
+	PluginErrorKindQuery   PluginErrorKind = "query"   // query returned error or panicked
+	PluginErrorKindTimeout PluginErrorKind = "timeout" // plugin didn't return before query timeout
+)
+
+// PluginErrorRecord is an error of a plugin (E.g. query failed, load failed), message is redacted
+type PluginErrorRecord struct {
+	Timestamp int64
+	Kind      PluginErrorKind
+	Message   string
+}
+
+// PluginState is the state of a loaded plugin, see Manager.DumpState
+type PluginState struct {
+	Id                string
+	Name              string
+	Version           string
+	Runtime           string
+	IsSystemPlugin    bool
+	IsDevPlugin       bool
+	Disabled          bool
+	Unloaded          bool
+	TriggerKeywords   []string
+	Commands          []string
+	Features          []string
+	StaticResultCount int
+	LoadCostMs        int64
+	InitCostMs        int64
+	RecentErrors      []PluginErrorRecord // latest first
+}
+
+// State is a snapshot of plugin manager for troubleshooting, it never contains plugin settings or query results
+type State struct {
+	Timestamp          int64
+	Plugins            []PluginState
+	InflightQueryCount int
+	ResultCacheCount   int
+	ResultCacheSize    int64      // estimated size in bytes
+	LastQueryStat      *QueryStat // nil if no query yet
+}
+
+// pluginErrorTracker keeps latest errors of each plugin in memory, zero value is ready to use
+type pluginErrorTracker struct {
+	errors map[string][]PluginErrorRecord // by plugin id
+	names  map[string]string              // plugin name by plugin id, plugins failed to load have no instance to get name from
+	lock   sync.Mutex
+}
+
+func (t *pluginErrorTracker) add(pluginId string, pluginName string, kind PluginErrorKind, message string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.errors == nil {
+		t.errors = map[string][]PluginErrorRecord{}
+		t.names = map[string]string{}
+	}
+	t.names[pluginId] = pluginName
+	records := append(t.errors[pluginId], PluginErrorRecord{
+		Timestamp: util.GetSystemTimestamp(),
+		Kind:      kind,
+		Message:   redactSecrets(message),
+	})
+	if len(records) > maxRecentPluginErrors {
+		records = records[len(records)-maxRecentPluginErrors:]
+	}
+	t.errors[pluginId] = records
+}
+
+func (t *pluginErrorTracker) get(pluginId string) []PluginErrorRecord {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return lo.Reverse(append([]PluginErrorRecord{}, t.errors[pluginId]...))
+}
+
+func (t *pluginErrorTracker) reset(pluginId string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.errors, pluginId)
+}
+
+// pluginIds returns ids of plugins which have errors
+func (t *pluginErrorTracker) pluginIds() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return lo.Keys(t.errors)
+}
+
+func (t *pluginErrorTracker) name(pluginId string) string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.names[pluginId]
+}
+
+func redactSecrets(message string) string {
+	return secretPattern.ReplaceAllString(message, "${1}***")
+}
+
+// DumpState returns a snapshot of loaded plugins and query pipeline, used by the developer panel in UI
+func (m *Manager) DumpState() State {
+	state := State{
+		Timestamp:        util.GetSystemTimestamp(),
+		ResultCacheCount: m.resultCache.Len(),
+		ResultCacheSize:  m.resultCacheSize.Load(),
+	}
+
+	for _, instance := range m.GetPluginInstances() {
+		pluginState := PluginState{
+			Id:                instance.Metadata.Id,
+			Name:              instance.Metadata.Name,
+			Version:           instance.Metadata.Version,
+			Runtime:           instance.Metadata.Runtime,
+			IsSystemPlugin:    instance.IsSystemPlugin,
+			IsDevPlugin:       instance.IsDevPlugin,
+			Unloaded:          instance.unloaded.Load(),
+			TriggerKeywords:   instance.GetTriggerKeywords(),
+			StaticResultCount: len(instance.GetStaticResults()),
+			LoadCostMs:        instance.LoadFinishedTimestamp - instance.LoadStartTimestamp,
+			InitCostMs:        instance.InitFinishedTimestamp - instance.InitStartTimestamp,
+			RecentErrors:      m.pluginErrors.get(instance.Metadata.Id),
+		}
+		if instance.Setting != nil {
+			pluginState.Disabled = instance.Setting.Disabled
+			pluginState.Commands = lo.Map(instance.GetQueryCommands(), func(item MetadataCommand, _ int) string {
+				return item.Command
+			})
+		}
+		pluginState.Features = lo.Map(instance.Metadata.Features, func(item MetadataFeature, _ int) string {
+			return string(item.Name)
+		})
+		state.Plugins = append(state.Plugins, pluginState)
+	}
+
+	m.inflightLock.Lock()
+	state.InflightQueryCount = len(m.inflightQueries)
+	m.inflightLock.Unlock()
+
+	if stat, exist := m.GetLastQueryStat(); exist {
+		state.LastQueryStat = &stat
+	}
+
+	return state
+}
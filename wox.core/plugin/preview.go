@@ -1,5 +1,7 @@
 package plugin
 
+import "context"
+
 type WoxPreviewType = string
 type WoxPreviewScrollPosition = string
 
@@ -10,6 +12,9 @@ const (
 	WoxPreviewTypeUrl      = "url"
 	WoxPreviewTypeFile     = "file"   // when type is file(can be *.md, *.jpg, *.pdf and so on), data should be url/filepath
 	WoxPreviewTypeRemote   = "remote" // when type is remote, data should be url to load WoxPreview
+	// when type is stream, data should be absolute path of a (large) text file, E.g. a log file. UI fetches windows of the file by
+	// Manager.ReadResultPreviewStream instead of loading the whole file, starting from the end of the file. Only supported as result preview
+	WoxPreviewTypeStream = "stream"
 )
 
 const (
@@ -21,6 +26,12 @@ type WoxPreview struct {
 	PreviewData       string
 	PreviewProperties map[string]string // key support i18n
 	ScrollPosition    WoxPreviewScrollPosition
+	// Update preview every UpdateInterval milliseconds while its result is focused (E.g. tail a log file), without refreshing the whole result.
+	// Interval smaller than minPreviewUpdateInterval is raised to it, 0 means no update
+	UpdateInterval int
+	// Called with current preview, returns the new preview. ctx is cancelled when focus leaves the result or the call exceeds UpdateInterval,
+	// result of a cancelled call is discarded. OnUpdate and UpdateInterval of returned preview are ignored, the original ones keep being used
+	OnUpdate func(ctx context.Context, current WoxPreview) WoxPreview `json:"-"`
 }
 
 func (p *WoxPreview) IsEmpty() bool {
@@ -0,0 +1,207 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+const (
+	maxPreviewEnrichments        = 5    // at most this many enrichments are appended to a preview, by enricher order
+	maxPreviewEnrichmentLength   = 2000 // markdown of a single enrichment is truncated beyond this length
+	maxPreviewEnrichmentProperty = 10   // properties of a single enrichment beyond this count are dropped
+)
+
+// PreviewEnricher adds content to the preview of the focused result, register it by API.RegisterPreviewEnricher.
+// E.g. a git plugin can show blame info for file results of any plugin.
+//
+// Enrichers are invoked lazily when UI asks for the enriched preview of the focused result (see Manager.GetEnrichedResultPreview),
+// concurrently, and their ctx is cancelled when focus moves to another result. Enrichments are appended ordered by Priority desc,
+// then by plugin load order and registration order.
+type PreviewEnricher struct {
+	// Result kinds to enrich, empty means all kinds. See QueryResultKind
+	Kinds []QueryResultKind
+	// Enrichers with higher priority are appended first
+	Priority int
+	// Returns the content to append, return empty enrichment if there is nothing to add
+	Enrich func(ctx context.Context, result PreviewEnrichContext) PreviewEnrichment
+}
+
+// PreviewEnrichContext is the focused result passed to PreviewEnricher
+type PreviewEnrichContext struct {
+	PluginId    string // plugin which returned the result
+	Title       string
+	SubTitle    string
+	Kind        QueryResultKind
+	ContextData string
+	Preview     WoxPreview // preview computed by the plugin which returned the result
+}
+
+// PreviewEnrichment is the content appended to a preview.
+// Markdown is appended below markdown and text previews, other preview types only get Properties.
+type PreviewEnrichment struct {
+	Markdown   string
+	Properties map[string]string // key support i18n, keys already present in preview are not overridden
+}
+
+func (e PreviewEnrichment) isEmpty() bool {
+	return e.Markdown == "" && len(e.Properties) == 0
+}
+
+func (e PreviewEnricher) isMatch(kind QueryResultKind) bool {
+	return len(e.Kinds) == 0 || lo.Contains(e.Kinds, kind)
+}
+
+type registeredPreviewEnricher struct {
+	pluginInstance *Instance
+	enricher       PreviewEnricher
+}
+
+// GetEnrichedResultPreview returns the preview of a result with contents of registered preview enrichers appended, see PreviewEnricher.
+// UI shows the result preview first and calls this when the result is focused, ctx should be cancelled by caller when focus moves on.
+// Enrichers which don't return in time are skipped, the preview is returned with whatever has been computed.
+func (m *Manager) GetEnrichedResultPreview(ctx context.Context, resultId string) (WoxPreview, error) {
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return WoxPreview{}, fmt.Errorf("result cache not found for result id (get enriched preview): %s", resultId)
+	}
+
+	preview := resultCache.getPreview()
+	enrichers := m.getPreviewEnrichers(resultCache.ResultKind)
+	if len(enrichers) == 0 {
+		return m.polishPreviewForUI(ctx, resultCache.PluginInstance, preview), nil
+	}
+
+	enrichCtx, cancel := m.previewEnrich.start(ctx)
+	defer cancel()
+
+	enrichContext := PreviewEnrichContext{
+		PluginId:    resultCache.PluginInstance.Metadata.Id,
+		Title:       resultCache.ResultTitle,
+		SubTitle:    resultCache.ResultSubTitle,
+		Kind:        resultCache.ResultKind,
+		ContextData: resultCache.ContextData,
+		Preview:     preview,
+	}
+	// enrichers may still be running after timeout, guard enrichments so late ones can't race with applying them
+	enrichments := make([]PreviewEnrichment, len(enrichers))
+	var enrichmentsLock sync.Mutex
+	var waitGroup sync.WaitGroup
+	for index, registered := range enrichers {
+		waitGroup.Add(1)
+		util.Go(enrichCtx, fmt.Sprintf("[%s] preview enricher", registered.pluginInstance.Metadata.Name), func() {
+			defer waitGroup.Done()
+			enrichment := m.invokePreviewEnricher(enrichCtx, registered, enrichContext)
+			enrichmentsLock.Lock()
+			enrichments[index] = enrichment
+			enrichmentsLock.Unlock()
+		})
+	}
+
+	allDone := make(chan bool)
+	util.Go(enrichCtx, "wait preview enrichers", func() {
+		waitGroup.Wait()
+		close(allDone)
+	})
+	select {
+	case <-allDone:
+	case <-enrichCtx.Done():
+		if ctx.Err() != nil {
+			return WoxPreview{}, fmt.Errorf("preview enrichment is cancelled: %w", ctx.Err())
+		}
+		logger.Warn(ctx, fmt.Sprintf("preview enrichers of result(%s) timed out, use finished ones", resultCache.ResultTitle))
+	}
+
+	enrichmentsLock.Lock()
+	preview = applyPreviewEnrichments(preview, enrichments)
+	enrichmentsLock.Unlock()
+	return m.polishPreviewForUI(ctx, resultCache.PluginInstance, preview), nil
+}
+
+func (m *Manager) getPreviewEnrichers(kind QueryResultKind) (enrichers []registeredPreviewEnricher) {
+	for _, pluginInstance := range m.getInstances() {
+		if pluginInstance.Setting != nil && pluginInstance.Setting.Disabled {
+			continue
+		}
+		for _, enricher := range pluginInstance.PreviewEnrichers {
+			if enricher.isMatch(kind) {
+				enrichers = append(enrichers, registeredPreviewEnricher{pluginInstance: pluginInstance, enricher: enricher})
+			}
+		}
+	}
+
+	sort.SliceStable(enrichers, func(i, j int) bool {
+		return enrichers[i].enricher.Priority > enrichers[j].enricher.Priority
+	})
+	return enrichers
+}
+
+func (m *Manager) invokePreviewEnricher(ctx context.Context, registered registeredPreviewEnricher, enrichContext PreviewEnrichContext) (enrichment PreviewEnrichment) {
+	defer util.GoRecover(ctx, fmt.Sprintf("<%s> preview enricher panic", registered.pluginInstance.Metadata.Name))
+
+	enrichment = registered.enricher.Enrich(ctx, enrichContext)
+	if ctx.Err() != nil {
+		// enricher returned after it's cancelled, discard the result
+		return PreviewEnrichment{}
+	}
+
+	enrichment.Markdown = util.EllipsisEnd(m.translatePlugin(ctx, registered.pluginInstance, enrichment.Markdown), maxPreviewEnrichmentLength)
+	if len(enrichment.Properties) > 0 {
+		keys := lo.Keys(enrichment.Properties)
+		sort.Strings(keys)
+		properties := map[string]string{}
+		for _, key := range lo.Slice(keys, 0, maxPreviewEnrichmentProperty) {
+			properties[m.translatePlugin(ctx, registered.pluginInstance, key)] = enrichment.Properties[key]
+		}
+		enrichment.Properties = properties
+	}
+	return enrichment
+}
+
+// applyPreviewEnrichments appends enrichments to preview in given order, at most maxPreviewEnrichments non-empty ones
+func applyPreviewEnrichments(preview WoxPreview, enrichments []PreviewEnrichment) WoxPreview {
+	enrichments = lo.Filter(enrichments, func(item PreviewEnrichment, _ int) bool {
+		return !item.isEmpty()
+	})
+	enrichments = lo.Slice(enrichments, 0, maxPreviewEnrichments)
+	if len(enrichments) == 0 {
+		return preview
+	}
+
+	// enrichments are appended to a copy, the cached preview is shared
+	properties := map[string]string{}
+	for key, value := range preview.PreviewProperties {
+		properties[key] = value
+	}
+	var markdowns []string
+	for _, enrichment := range enrichments {
+		for key, value := range enrichment.Properties {
+			if _, exist := properties[key]; !exist {
+				properties[key] = value
+			}
+		}
+		if enrichment.Markdown != "" {
+			markdowns = append(markdowns, enrichment.Markdown)
+		}
+	}
+	preview.PreviewProperties = properties
+
+	if len(markdowns) > 0 {
+		switch {
+		case preview.PreviewType == "" || preview.IsEmpty():
+			preview.PreviewType = WoxPreviewTypeMarkdown
+			preview.PreviewData = strings.Join(markdowns, "\n\n---\n\n")
+		case preview.PreviewType == WoxPreviewTypeMarkdown:
+			preview.PreviewData = preview.PreviewData + "\n\n---\n\n" + strings.Join(markdowns, "\n\n---\n\n")
+		case preview.PreviewType == WoxPreviewTypeText:
+			preview.PreviewData = preview.PreviewData + "\n\n" + strings.Join(markdowns, "\n\n")
+		}
+	}
+
+	return preview
+}
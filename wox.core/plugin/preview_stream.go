@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+const (
+	// default size of a window of a stream preview in bytes, see ReadResultPreviewStream
+	defaultPreviewStreamWindowSize = 64 * 1024
+	maxPreviewStreamWindowSize     = 1024 * 1024
+	// files are read in chunks, cancellation is checked between chunks
+	previewStreamChunkSize = 16 * 1024
+	// reads of all stream previews share these slots, a new read cancels the oldest running one (E.g. user moved focus to another result)
+	maxConcurrentPreviewStreamReads = 2
+	previewStreamReadLimiterKey     = "previewStream"
+)
+
+// PreviewStreamWindow is a range of the file of a stream preview (see WoxPreviewTypeStream), UI fetches windows while user scrolls
+type PreviewStreamWindow struct {
+	Offset int64  // offset in bytes of Data in the file
+	Length int    // length in bytes of Data
+	Size   int64  // size of the whole file in bytes, UI can fetch earlier windows while Offset > 0 and later ones while Offset+Length < Size
+	Data   string // text of the range, cut at utf8 boundaries so it may be a few bytes shorter than requested
+}
+
+// ReadResultPreviewStream reads a window of the file of a stream preview, offset < 0 reads the last length bytes (tail of the file).
+// length <= 0 means defaultPreviewStreamWindowSize, it's capped at maxPreviewStreamWindowSize so a gigabyte log is never loaded into memory.
+// Reads are bounded by maxConcurrentPreviewStreamReads, reading stops when ctx is cancelled (E.g. UI aborts the request)
+func (m *Manager) ReadResultPreviewStream(ctx context.Context, resultId string, offset int64, length int) (PreviewStreamWindow, error) {
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return PreviewStreamWindow{}, fmt.Errorf("result cache not found for result id (read preview stream): %s", resultId)
+	}
+	preview := resultCache.getPreview()
+	if preview.PreviewType != WoxPreviewTypeStream {
+		return PreviewStreamWindow{}, fmt.Errorf("preview of result %s is not a stream preview", resultId)
+	}
+
+	readCtx, release, acquireErr := m.previewStreamReads.acquire(ctx, previewStreamReadLimiterKey, maxConcurrentPreviewStreamReads)
+	if acquireErr != nil {
+		return PreviewStreamWindow{}, fmt.Errorf("preview stream read is cancelled: %w", acquireErr)
+	}
+	defer release()
+
+	return readPreviewStreamWindow(readCtx, preview.PreviewData, offset, length)
+}
+
+func readPreviewStreamWindow(ctx context.Context, filePath string, offset int64, length int) (PreviewStreamWindow, error) {
+	if length <= 0 {
+		length = defaultPreviewStreamWindowSize
+	}
+	length = min(length, maxPreviewStreamWindowSize)
+
+	file, openErr := os.Open(filePath)
+	if openErr != nil {
+		return PreviewStreamWindow{}, openErr
+	}
+	defer file.Close()
+
+	stat, statErr := file.Stat()
+	if statErr != nil {
+		return PreviewStreamWindow{}, statErr
+	}
+	size := stat.Size()
+	if offset < 0 {
+		offset = max(size-int64(length), 0)
+	}
+	if offset > size {
+		offset = size
+	}
+	length = int(min(int64(length), size-offset))
+
+	data := make([]byte, length)
+	read := 0
+	for read < length {
+		if ctx.Err() != nil {
+			return PreviewStreamWindow{}, ctx.Err()
+		}
+		n, readErr := file.ReadAt(data[read:min(read+previewStreamChunkSize, length)], offset+int64(read))
+		read += n
+		if errors.Is(readErr, io.EOF) {
+			// file is truncated while reading
+			break
+		}
+		if readErr != nil {
+			return PreviewStreamWindow{}, readErr
+		}
+	}
+	data = data[:read]
+
+	// don't split runes, a window starting in the middle of a rune skips its remaining bytes
+	start := 0
+	if offset > 0 {
+		for start < len(data) && start < utf8.UTFMax && !utf8.RuneStart(data[start]) {
+			start++
+		}
+	}
+	end := len(data)
+	if offset+int64(end) < size {
+		for i := end - 1; i >= start && end-i <= utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:end]) {
+					end = i
+				}
+				break
+			}
+		}
+	}
+
+	return PreviewStreamWindow{
+		Offset: offset + int64(start),
+		Length: end - start,
+		Size:   size,
+		Data:   string(data[start:end]),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePreviewStreamFile(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "app.log")
+	assert.Nil(t, os.WriteFile(filePath, []byte(content), 0644))
+	return filePath
+}
+
+func TestReadPreviewStreamWindow_Tail(t *testing.T) {
+	filePath := writePreviewStreamFile(t, "line1\nline2\nline3\n")
+
+	window, err := readPreviewStreamWindow(context.Background(), filePath, -1, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, "line3\n", window.Data)
+	assert.Equal(t, int64(12), window.Offset)
+	assert.Equal(t, int64(18), window.Size)
+
+	// earlier window
+	window, err = readPreviewStreamWindow(context.Background(), filePath, 6, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, "line2\n", window.Data)
+
+	// file smaller than window
+	window, err = readPreviewStreamWindow(context.Background(), filePath, -1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), window.Offset)
+	assert.Equal(t, 18, window.Length)
+}
+
+func TestReadPreviewStreamWindow_CapsLength(t *testing.T) {
+	filePath := writePreviewStreamFile(t, strings.Repeat("a", maxPreviewStreamWindowSize+10))
+
+	window, err := readPreviewStreamWindow(context.Background(), filePath, 0, maxPreviewStreamWindowSize*2)
+	assert.Nil(t, err)
+	assert.Equal(t, maxPreviewStreamWindowSize, window.Length)
+	assert.Equal(t, int64(maxPreviewStreamWindowSize+10), window.Size)
+}
+
+func TestReadPreviewStreamWindow_RuneBoundaries(t *testing.T) {
+	// each rune is 3 bytes
+	filePath := writePreviewStreamFile(t, "日志文件")
+
+	window, err := readPreviewStreamWindow(context.Background(), filePath, 1, 7)
+	assert.Nil(t, err)
+	assert.Equal(t, "志", window.Data)
+	assert.Equal(t, int64(3), window.Offset)
+	assert.Equal(t, 3, window.Length)
+
+	window, err = readPreviewStreamWindow(context.Background(), filePath, -1, 8)
+	assert.Nil(t, err)
+	assert.Equal(t, "文件", window.Data)
+	assert.Equal(t, int64(6), window.Offset)
+}
+
+func TestReadPreviewStreamWindow_Cancelled(t *testing.T) {
+	filePath := writePreviewStreamFile(t, "line1\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := readPreviewStreamWindow(ctx, filePath, -1, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"wox/util"
+)
+
+// minimal interval of focused preview updates in milliseconds, so a plugin can't flood UI with updates
+const minPreviewUpdateInterval = 200
+
+// previewUpdater updates the preview of the focused result, see WoxPreview.OnUpdate.
+// Only one preview is updated at a time, focusing another result stops updating the previous one
+type previewUpdater struct {
+	lock     sync.Mutex
+	resultId string
+	cancel   context.CancelFunc
+}
+
+// FocusPreview is called by UI when focused result (with visible preview) changes, resultId is empty if no preview is visible.
+// It starts updating the preview of the result if the preview has OnUpdate, and stops updating the previous one
+func (m *Manager) FocusPreview(ctx context.Context, resultId string) {
+	m.previewUpdater.lock.Lock()
+	defer m.previewUpdater.lock.Unlock()
+
+	if m.previewUpdater.cancel != nil {
+		if m.previewUpdater.resultId == resultId {
+			return
+		}
+		m.previewUpdater.cancel()
+		m.previewUpdater.cancel = nil
+		m.previewUpdater.resultId = ""
+	}
+	if resultId == "" {
+		return
+	}
+
+	resultCache, found := m.resultCache.Load(resultId)
+	if !found {
+		return
+	}
+	preview := resultCache.getPreview()
+	if preview.OnUpdate == nil || preview.UpdateInterval <= 0 {
+		return
+	}
+
+	interval := time.Duration(max(preview.UpdateInterval, minPreviewUpdateInterval)) * time.Millisecond
+	// updating outlives the websocket request which focuses the result
+	updateCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.previewUpdater.resultId = resultId
+	m.previewUpdater.cancel = cancel
+
+	util.Go(updateCtx, fmt.Sprintf("[%s] preview update of result(%s)", resultCache.PluginInstance.Metadata.Name, resultCache.ResultTitle), func() {
+		m.runPreviewUpdate(updateCtx, resultId, interval)
+	})
+}
+
+func (m *Manager) runPreviewUpdate(ctx context.Context, resultId string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// result cache is cleared when query changes or result is collapsed
+		resultCache, found := m.resultCache.Load(resultId)
+		if !found {
+			return
+		}
+
+		current := resultCache.getPreview()
+		updateCtx, cancel := context.WithTimeout(ctx, interval)
+		newPreview := current.OnUpdate(updateCtx, current)
+		updateErr := updateCtx.Err()
+		cancel()
+		if updateErr != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn(ctx, fmt.Sprintf("<%s> preview update of result(%s) exceeds %s, discarded", resultCache.PluginInstance.Metadata.Name, resultCache.ResultTitle, interval))
+			continue
+		}
+
+		newPreview.UpdateInterval = current.UpdateInterval
+		newPreview.OnUpdate = current.OnUpdate
+		resultCache.setPreview(newPreview)
+		m.ui.UpdatePreview(ctx, resultId, m.polishPreviewForUI(ctx, resultCache.PluginInstance, newPreview))
+	}
+}
@@ -3,15 +3,22 @@ package plugin
 import (
 	"context"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
 	"wox/util"
 	"wox/util/selection"
 
 	"github.com/samber/lo"
+	"golang.org/x/text/unicode/norm"
 )
 
 type QueryType = string
 type QueryVariable = string
 type QueryResultTailType = string
+type QueryResultKind = string
+type QueryResultTruncation = string
 
 const (
 	QueryTypeInput     QueryType = "input"     // user input query
@@ -28,6 +35,32 @@ const (
 	QueryResultTailTypeImage QueryResultTailType = "image" // WoxImage type
 )
 
+// QueryResultKind is a layout hint for UI, E.g. media results may be rendered with larger thumbnails.
+// It's a hint only, UI renders unknown kinds as default results
+const (
+	QueryResultKindDefault QueryResultKind = ""
+	QueryResultKindFile    QueryResultKind = "file"
+	QueryResultKindCommand QueryResultKind = "command"
+	QueryResultKindContact QueryResultKind = "contact"
+	QueryResultKindMedia   QueryResultKind = "media"
+)
+
+// QueryResultTruncation tells UI where to cut a text which doesn't fit, the ellipsis is shown at that place.
+// E.g. middle truncation keeps both the root and the file name of a long path visible
+const (
+	QueryResultTruncationEnd    QueryResultTruncation = "end" // default
+	QueryResultTruncationStart  QueryResultTruncation = "start"
+	QueryResultTruncationMiddle QueryResultTruncation = "middle"
+)
+
+// Icon size hints in logical pixels, see QueryResult.IconSize
+const (
+	QueryResultIconSizeDefault = 30
+	QueryResultIconSizeMedia   = 64 // default size of QueryResultKindMedia results
+	QueryResultIconSizeMin     = 16
+	QueryResultIconSizeMax     = 128
+)
+
 // Query from Wox. See "Doc/Query.md" for details.
 type Query struct {
 	// By default, Wox will only pass QueryTypeInput query to plugin.
@@ -36,10 +69,14 @@ type Query struct {
 
 	// Raw query, this includes trigger keyword if it has.
 	// We didn't recommend use this property directly. You should always use Search property.
+	//
+	// NOTE: This is the sanitized form of user input, see sanitizeQueryText
 	RawQuery string
 
 	// Trigger keyword of a query. It can be empty if user is using global trigger keyword.
 	// Empty trigger keyword means this query will be a global query, see IsGlobalQuery.
+	// It's the keyword declared by plugin which matched (E.g. "github" of a plugin with "gh" and "github"), it's kept in actions and
+	// refreshes of results of this query, see ActionContext.TriggerKeyword and RefreshableResult.TriggerKeyword
 	//
 	// NOTE: Only available when query type is QueryTypeInput
 	TriggerKeyword string
@@ -50,24 +87,70 @@ type Query struct {
 	// NOTE: Only available when query type is QueryTypeInput
 	Command string
 
+	// Command declared by plugin (see Metadata.Commands) which Command matched, so plugin can switch on it and get its metadata.
+	// Nil if query doesn't have a command, or its command was parsed by globalCommand feature and is not declared
+	//
+	// NOTE: Only available when query type is QueryTypeInput
+	MatchedCommand *MetadataCommand
+
+	// Search parsed by arguments of MatchedCommand, nil if the command declares no arguments, see MetadataCommand.Arguments
+	//
+	// NOTE: Only available when query type is QueryTypeInput
+	CommandInvocation *CommandInvocation
+
+	// First search term which is in the position of a command (followed by a space, E.g. "frobnicate" in "wpm frobnicate x" or "wpm frobnicate ")
+	// but matched no command of the plugin. It's still part of Search. Always empty if plugin declares no commands
+	//
+	// NOTE: Only available when plugin enabled MetadataFeatureUnknownCommand
+	UnknownCommand string
+
 	// Search part of a query.
 	// Empty search means this query doesn't have a search part.
 	Search string
 
+	// True if user asked for literal matching by starting search with the literal match prefix (default "'", see setting.WoxSetting.LiteralMatchPrefix).
+	// The prefix is already stripped from Search. Plugins should match Search as a case-insensitive substring instead of fuzzy,
+	// shared matchers (E.g. IsStringMatchScore in system plugins) do it automatically because ctx of query is marked, see util.NewLiteralMatchContext
+	IsLiteralMatch bool
+
 	// User selected or drag-drop data, can be text or file or image etc
 	//
 	// NOTE: Only available when query type is QueryTypeSelection
 	Selection selection.Selection
 
+	// Best-guess language of selected text as ISO 639-1 code (E.g. "en", "zh"), see util.DetectLanguage.
+	// Empty if language can't be told, E.g. text is too short or mixes languages.
+	//
+	// NOTE: Only available when query type is QueryTypeSelection with text selection and plugin enabled MetadataFeatureDetectSelectionLanguage
+	SelectionLanguage string
+
 	// additional query environment data
 	// expose more context env data to plugin, E.g. plugin A only show result when active window title is "Chrome"
 	Env QueryEnv
+
+	// Context of the payload sent from another plugin, nil if this query is not a handoff.
+	//
+	// NOTE: Only available when query type is QueryTypeSelection, see Handoff
+	Handoff *Handoff
+
+	// Context data stashed by an action for the following queries (E.g. the repository picked in previous step), nil if nothing is carried.
+	//
+	// NOTE: Only available when query type is QueryTypeInput, see CarriedContext
+	CarriedContext *CarriedContext
+
+	// set for triggered queries when global plugins are queried too, see setting.WoxSetting.MixGlobalResultsInTriggeredQuery
+	mixedWithGlobal bool
 }
 
 func (q *Query) IsGlobalQuery() bool {
 	return q.Type == QueryTypeInput && q.TriggerKeyword == ""
 }
 
+// IsEmptyInput returns true if query box is empty, see MetadataFeatureEmptyQuery
+func (q *Query) IsEmptyInput() bool {
+	return q.Type == QueryTypeInput && q.RawQuery == ""
+}
+
 func (q *Query) String() string {
 	if q.Type == QueryTypeInput {
 		return q.RawQuery
@@ -95,10 +178,34 @@ type QueryResult struct {
 	Title string
 	// SubTitle support i18n
 	SubTitle string
-	Icon     WoxImage
+	// Optional, where UI truncates Title and SubTitle when they are too long, see QueryResultTruncation. Empty means QueryResultTruncationEnd
+	TitleTruncation    QueryResultTruncation
+	SubTitleTruncation QueryResultTruncation
+	// Optional, ranges of SubTitle matched by query which UI highlights, E.g. matched part of a file path shown in subtitle.
+	// Each range is [start, end) in runes (characters) of SubTitle. Ignored if SubTitle is translated (starts with "i18n:").
+	// UI drops the highlights when subtitle is changed by refresh
+	SubTitleHighlights [][2]int
+	// Optional, ranges of Title matched by query which UI highlights, same as SubTitleHighlights
+	TitleHighlights [][2]int
+	// Optional, short explanation why this result matched (E.g. "matched 'foo' in path"), support i18n. UI shows it as tooltip of the result,
+	// it's not rendered in the result list. If not set, it's generated from TitleHighlights and SubTitleHighlights
+	MatchReason string
+	// Text read by screen readers for this result, support i18n.
+	// It's optional, if you don't set it, Wox will use title and subtitle. Set it if title or subtitle contains decorative text (E.g. emoji)
+	AccessibilityLabel string
+	Icon               WoxImage
+	// Optional, size hint of the icon in logical pixels, E.g. larger thumbnails for image results. It's advisory, UI may render it smaller if space is tight.
+	// 0 means default size of Kind, sizes out of [QueryResultIconSizeMin, QueryResultIconSizeMax] are clamped so results won't break the layout
+	IconSize int
 	Preview  WoxPreview
+	// Optional, several named previews rendered as tabs in preview panel (E.g. description, diff and comments of a PR), Preview is ignored if set.
+	// See QueryResultPreviewTab
+	PreviewTabs []QueryResultPreviewTab
 	// Score of the result, the higher the score, the more relevant the result is, more likely to be displayed on top
+	// If you compute relevance in float, use ScoreFromFloat to convert it, see ScoreFixedPointScale
 	Score int64
+	// Layout hint for UI, see QueryResultKind. It's optional, empty means default layout
+	Kind QueryResultKind
 	// Group results, Wox will group results by group name
 	Group string
 	// Score of the group, the higher the score, the more relevant the group is, more likely to be displayed on top
@@ -108,12 +215,60 @@ type QueryResult struct {
 	// Additional data associate with this result, can be retrieved in Action function
 	ContextData string
 	Actions     []QueryResultAction
+	// Optional, secondary actions shown in a context menu when user right-clicks the result, separate from Actions which are run by Enter or
+	// picked from the action panel. They are executed the same way as Actions (see Manager.ExecuteAction), see polishContextActions for details
+	ContextActions []QueryResultAction
+	// Id of the action to use as default action, it overrides IsDefault of actions. Useful when the best default depends on the result,
+	// E.g. preview for images and open for other files. Action must have its Id set by plugin.
+	// If no action matches this id, IsDefault of actions is used as if it's not set
+	DefaultActionId string
+	// Optional, selecting the result (Enter) changes the query to this value instead of executing an action and hiding Wox,
+	// E.g. calculator sets the query to the computed value so user can continue calculating with it.
+	// It becomes the default action, it overrides IsDefault of actions and DefaultActionId, other actions are still available in action panel
+	SetQueryOnSelect string
+	// Optional, url of the web page or resource the result represents. When it's set, Wox adds "Open link" and "Copy link" actions,
+	// "Open link" becomes the default action unless plugin declared its own default action (IsDefault or DefaultActionId).
+	// Invalid urls (E.g. without scheme) are ignored, see NewOpenURLAction
+	URL string
 	// refresh result after specified interval, in milliseconds. If this value is 0, Wox will not refresh this result
 	// interval can only divisible by 100, if not, Wox will use the nearest number which is divisible by 100
 	// E.g. if you set 123, Wox will use 200, if you set 1234, Wox will use 1300
 	RefreshInterval int
 	// refresh result by calling OnRefresh function
 	OnRefresh func(ctx context.Context, current RefreshableResult) RefreshableResult
+	// If true, Wox will not append global actions (E.g. copy title) which user configured for all results
+	DisableGlobalActions bool
+	// Child results shown nested below this result when user expands it (E.g. files of a folder), without starting a new query.
+	// Children can be expandable too
+	Children []QueryResult
+	// Optional, computes child results lazily when user expands this result, Children is ignored if this is set.
+	// It's called every time the result is expanded
+	OnExpand func(ctx context.Context) []QueryResult
+	// Optional, results of the same plugin with the same collapse group are alternative representations of one entity (E.g. aliases of an app).
+	// Only the best one (highest score) is shown, with an action to show the others next to it. See Manager.collapseResults
+	CollapseGroup string
+	// Optional, when the data of this result was last updated, E.g. cached or offline data. Zero means the result is live.
+	// UI shows it as relative time (E.g. "updated 5m ago") and styles stale results, formatting and localization are done by UI.
+	// Plugins showing relative times in subtitles should use API.FormatRelativeTime, so all results format them the same way
+	LastUpdated time.Time
+	// Optional, computes the icon lazily when the result is about to be rendered (E.g. file type icons, favicons), Icon is used as placeholder meanwhile.
+	// Computed icon is cached until the query changes, see Manager.GetResultIcon
+	OnIcon func(ctx context.Context) WoxImage
+	// Optional, called when the result is shown to user, at most once per query. Together with Action (called when user picks the result),
+	// plugins can measure how often their results are picked. It's only called if user enabled analytics in settings, and it's called
+	// asynchronously so it must not block
+	OnShown func(ctx context.Context)
+	// Optional, when the item of this result was published (E.g. a news item). Only used if plugin enables MetadataFeatureScoreDecay,
+	// Score is then decayed by the age of the result whenever results are sorted, so newer results rank higher. See decayScore
+	Timestamp time.Time
+
+	// internal use, half life of score decay, set by Wox if plugin enables MetadataFeatureScoreDecay
+	scoreHalfLife time.Duration
+	// internal use, plugin which returned this result, set by Wox
+	pluginId       string
+	pluginName     string
+	pluginIcon     WoxImage
+	groupCollapsed bool
 }
 
 type QueryResultTail struct {
@@ -138,57 +293,163 @@ type QueryResultAction struct {
 	// Case insensitive, space insensitive
 	// If IsDefault is true, Hotkey will be set to enter key by default
 	Hotkey string
+	// Run this action when user presses Enter while holding the modifier, E.g. "cmd" to reveal a file instead of opening it.
+	// One of "cmd", "ctrl", "alt", "shift", cmd and win (or alt and option) are treated as the same key.
+	// Default action can't have a modifier, if several actions use the same modifier, only the first one keeps it
+	ActivationModifier string
+	// Optional, computes what the action would do (E.g. files to be deleted) without doing it.
+	// Wox shows it in preview panel when the action is focused in action list, instead of the result preview.
+	// It's computed lazily and cached until result is refreshed, ctx is cancelled if user focuses another action before it returns.
+	Preview func(ctx context.Context, actionContext ActionContext) WoxPreview
+	// If true, action runs in background and can be cancelled by user (see Manager.CancelAction), E.g. copying a big file.
+	// ctx passed to Action is done when cancelled, action should stop and clean up what it has done so far before returning.
+	Cancellable bool
+	// Optional, asks user for a text before running the action (E.g. new name for rename), see QueryResultActionInput.
+	// Entered text is passed to Action via ActionContext.Input
+	Input *QueryResultActionInput
+	// Optional, makes the action batch capable: when user selects several results and runs this action, Wox calls BatchAction once with
+	// action contexts of all selected results which have the same action (see Manager.ExecuteBatchAction), instead of Action for each of them.
+	// It returns nil if all succeeded, otherwise one error per action context (nil for succeeded ones), failures are reported to user.
+	// Action can be omitted if BatchAction is set, single result then runs BatchAction with one action context.
+	// Id must be set, otherwise the action only runs on the result it belongs to, see hasBatchAction
+	BatchAction func(ctx context.Context, actionContexts []ActionContext) []error
+	// If true, identical invocations in quick succession all run (E.g. an "increase volume" action user presses repeatedly).
+	// By default an invocation of the same action of the same result within 500ms after the previous one is dropped,
+	// so a double pressed Enter won't run the action twice (E.g. send a message twice), see actionDeduplicator
+	AllowRepeat bool
 
 	// internal use
 	IsSystemAction bool
+	declaredId     string // id set by plugin, empty if Wox derived it, see assignStableActionIds
+	hotkeyHint     string // see QueryResultActionUI.HotkeyHint
+}
+
+// QueryResultActionInput describes the text UI collects before running an action.
+// When the action is selected, UI shows an input field instead of running it. Confirming runs the action with the entered text
+// (see Manager.ExecuteActionWithInput), pressing escape cancels it and Action is not called
+type QueryResultActionInput struct {
+	// Prompt shown above the input field, support i18n
+	Prompt string
+	// Placeholder of the input field, support i18n
+	Placeholder  string
+	DefaultValue string
+	// Optional, returns an error if the input is invalid. UI validates while user is typing and shows the error,
+	// Wox also validates before running the action and doesn't run it if the input is invalid
+	Validate func(ctx context.Context, actionContext ActionContext, input string) error
+	// Optional, called when user cancels the input
+	OnCancel func(ctx context.Context, actionContext ActionContext)
 }
 
 type ActionContext struct {
 	// Additional data associate with this result
 	ContextData string
+	// Text entered by user, only available when action requires input, see QueryResultAction.Input
+	Input string
+	// Selection of the query which returned this result, so actions shared by results (E.g. global actions) can access it.
+	// NOTE: Only available when query type is QueryTypeSelection
+	Selection selection.Selection
+	// Trigger keyword of the query which returned this result (see Query.TriggerKeyword), so plugins with several trigger keywords
+	// can act differently per keyword. It's the keyword declared by plugin which matched, empty if result is from a global query
+	TriggerKeyword string
+	// Modifier keys held when user invoked the action (E.g. Shift+Enter or Shift+click), so a single action can branch on them,
+	// E.g. open vs open in background. Platform specific names like hotkeys ("cmd" on macOS, "win" on Windows), empty if no modifier is held.
+	// Use HasModifier to check a modifier regardless of platform. Prefer QueryResultAction.ActivationModifier if a modifier should run another action
+	Modifiers []string
+}
+
+// HasModifier returns true if the modifier (E.g. "shift", "cmd") was held when the action was invoked, see ActionContext.Modifiers
+func (c ActionContext) HasModifier(modifier string) bool {
+	return lo.Contains(c.Modifiers, toPlatformModifier(strings.ToLower(strings.TrimSpace(modifier))))
 }
 
 func (q *QueryResult) ToUI() QueryResultUI {
 	return QueryResultUI{
-		Id:          q.Id,
-		Title:       q.Title,
-		SubTitle:    q.SubTitle,
-		Icon:        q.Icon,
-		Preview:     q.Preview,
-		Score:       q.Score,
-		Group:       q.Group,
-		GroupScore:  q.GroupScore,
-		Tails:       q.Tails,
-		ContextData: q.ContextData,
+		Id:                 q.Id,
+		Title:              q.Title,
+		SubTitle:           q.SubTitle,
+		TitleTruncation:    q.TitleTruncation,
+		SubTitleTruncation: q.SubTitleTruncation,
+		SubTitleHighlights: q.SubTitleHighlights,
+		TitleHighlights:    q.TitleHighlights,
+		MatchReason:        q.MatchReason,
+		AccessibilityLabel: q.AccessibilityLabel,
+		Icon:               q.Icon,
+		IconSize:           q.IconSize,
+		Preview:            q.Preview,
+		Score:              q.Score,
+		Kind:               q.Kind,
+		Group:              q.Group,
+		GroupScore:         q.GroupScore,
+		Tails:              q.Tails,
+		ContextData:        q.ContextData,
 		Actions: lo.Map(q.Actions, func(action QueryResultAction, index int) QueryResultActionUI {
-			return QueryResultActionUI{
-				Id:                     action.Id,
-				Name:                   action.Name,
-				Icon:                   action.Icon,
-				IsDefault:              action.IsDefault,
-				PreventHideAfterAction: action.PreventHideAfterAction,
-				Hotkey:                 action.Hotkey,
-				IsSystemAction:         action.IsSystemAction,
-			}
+			return action.toUI()
+		}),
+		PreviewTabs: lo.Map(q.PreviewTabs, func(tab QueryResultPreviewTab, index int) QueryResultPreviewTabUI {
+			return QueryResultPreviewTabUI{Id: tab.Id, Name: tab.Name, IsDefault: tab.IsDefault}
+		}),
+		ContextActions: lo.Map(q.ContextActions, func(action QueryResultAction, index int) QueryResultActionUI {
+			return action.toUI()
 		}),
 		RefreshInterval: q.RefreshInterval,
+		Expandable:      len(q.Children) > 0 || q.OnExpand != nil,
+		LastUpdated:     toLastUpdatedTimestamp(q.LastUpdated),
+		HasLazyIcon:     q.OnIcon != nil,
+		Timestamp:       toLastUpdatedTimestamp(q.Timestamp),
+		ScoreHalfLife:   q.scoreHalfLife.Milliseconds(),
+		PluginId:        q.pluginId,
+		PluginName:      q.pluginName,
+		PluginIcon:      q.pluginIcon,
+		GroupCollapsed:  q.groupCollapsed,
 	}
 }
 
 type QueryResultUI struct {
-	QueryId         string
-	Id              string
-	Title           string
-	SubTitle        string
-	Icon            WoxImage
-	Preview         WoxPreview
-	Score           int64
-	Group           string
-	GroupScore      int64
-	Tails           []QueryResultTail
-	ContextData     string
-	Actions         []QueryResultActionUI
+	QueryId            string
+	Id                 string
+	Title              string
+	SubTitle           string
+	TitleTruncation    QueryResultTruncation // always set, see QueryResult.TitleTruncation
+	SubTitleTruncation QueryResultTruncation
+	SubTitleHighlights [][2]int // sorted and non-overlapping ranges in runes, see QueryResult.SubTitleHighlights
+	TitleHighlights    [][2]int
+	MatchReason        string // always translated, empty if result doesn't explain its match, see QueryResult.MatchReason
+	AccessibilityLabel string
+	Icon               WoxImage
+	IconSize           int // always set, see QueryResult.IconSize
+	Preview            WoxPreview
+	Score              int64
+	Kind               QueryResultKind
+	Group              string
+	GroupScore         int64
+	Tails              []QueryResultTail
+	ContextData        string
+	Actions            []QueryResultActionUI
+	// Tabs of preview panel, empty if result has a single preview. Preview is the default tab, see QueryResultPreviewTab
+	PreviewTabs []QueryResultPreviewTabUI
+	// Shown in the context menu on right click, executed by their id the same way as Actions. See QueryResult.ContextActions
+	ContextActions  []QueryResultActionUI
 	RefreshInterval int
+	// UI shows an expand indicator and expands the result by right arrow (or clicking the indicator), see Manager.ExpandResult.
+	// Children are rendered indented right below the parent and navigated by up/down like other results, focus stays on parent after expanding.
+	// Left arrow collapses the result, or moves focus to the parent if a child is focused
+	Expandable bool
+	// Id of the parent result if this result is a child of an expanded result
+	ParentId string
+	// Unix timestamp in milliseconds when the data was last updated, 0 if result is live, see QueryResult.LastUpdated
+	LastUpdated int64
+	// UI fetches the real icon by Manager.GetResultIcon when the result scrolls into view, Icon is only a placeholder
+	HasLazyIcon bool
+	// Unix timestamp in milliseconds of the result and half life of its score in milliseconds, both 0 if score doesn't decay.
+	// Score is decayed right before results are sent to UI and ScoreHalfLife is cleared then, see applyScoreDecay
+	Timestamp     int64
+	ScoreHalfLife int64
+	// Plugin which returned the result, UI shows its name and icon as header of the group if results are grouped by plugin
+	PluginId   string
+	PluginName string
+	PluginIcon WoxImage
+	// True if the group of this result is collapsed by user, UI shows only the group header. See Manager.SetResultGroupCollapsed
+	GroupCollapsed bool
 }
 
 type QueryResultActionUI struct {
@@ -198,25 +459,178 @@ type QueryResultActionUI struct {
 	IsDefault              bool
 	PreventHideAfterAction bool
 	Hotkey                 string
+	HotkeyHint             string // localized display text of Hotkey (E.g. "⌘⇧C"), empty if user hides hints, see Manager.getActionHotkeyHint
+	ActivationModifier     string
+	HasPreview             bool                      // UI should fetch action preview when action is focused, see QueryResultAction.Preview
+	Cancellable            bool                      // UI can show a cancel button while action is running, see QueryResultAction.Cancellable
+	Input                  *QueryResultActionInputUI // nil if action doesn't require input, see QueryResultAction.Input
+	IsBatch                bool                      // action can run on multiple selected results, see QueryResultAction.BatchAction
+	AllowRepeat            bool                      // identical invocations in quick succession all run, see QueryResultAction.AllowRepeat
 
 	// internal use
 	IsSystemAction bool
 }
 
+type QueryResultActionInputUI struct {
+	Prompt       string
+	Placeholder  string
+	DefaultValue string
+}
+
+func (a QueryResultAction) toUI() QueryResultActionUI {
+	return QueryResultActionUI{
+		Id:                     a.Id,
+		Name:                   a.Name,
+		Icon:                   a.Icon,
+		IsDefault:              a.IsDefault,
+		PreventHideAfterAction: a.PreventHideAfterAction,
+		Hotkey:                 a.Hotkey,
+		HotkeyHint:             a.hotkeyHint,
+		ActivationModifier:     a.ActivationModifier,
+		HasPreview:             a.Preview != nil,
+		Cancellable:            a.Cancellable,
+		Input:                  a.getInputUI(),
+		IsBatch:                a.BatchAction != nil && a.hasDeclaredId(),
+		AllowRepeat:            a.AllowRepeat,
+		IsSystemAction:         a.IsSystemAction,
+	}
+}
+
+// hasDeclaredId returns false if Wox derived the id of the action from its name, only plugin declared ids are guaranteed to identify the same action in every result
+func (a QueryResultAction) hasDeclaredId() bool {
+	return a.Id != "" && a.Id == a.declaredId
+}
+
+// hasActionFunc returns false if action has nothing to run, batch actions may only declare BatchAction
+func (a QueryResultAction) hasActionFunc() bool {
+	return a.Action != nil || a.BatchAction != nil
+}
+
+func (a QueryResultAction) getInputUI() *QueryResultActionInputUI {
+	if a.Input == nil {
+		return nil
+	}
+
+	return &QueryResultActionInputUI{
+		Prompt:       a.Input.Prompt,
+		Placeholder:  a.Input.Placeholder,
+		DefaultValue: a.Input.DefaultValue,
+	}
+}
+
 // store latest result value after query/refresh, so we can retrieve data later in action/refresh
 type QueryResultCache struct {
 	ResultId       string
 	ResultTitle    string
 	ResultSubTitle string
+	ResultKind     QueryResultKind
 	ContextData    string
 	Refresh        func(context.Context, RefreshableResult) RefreshableResult
 	PluginInstance *Instance
 	Query          Query
-	Preview        WoxPreview
-	Actions        *util.HashMap[string, func(ctx context.Context, actionContext ActionContext)]
+	Preview        WoxPreview                               // guarded by previewLock once stored, see getPreview and setPreview
+	Actions        *util.HashMap[string, QueryResultAction] // actions and context actions by action id
+	ContextActions []QueryResultAction                      // context actions stored in Actions, they are kept when result is refreshed
+	ActionPreviews *util.HashMap[string, WoxPreview]        // computed action previews by action id, cleared when actions are updated
+	PreviewTabs    []QueryResultPreviewTab
+	TabPreviews    *util.HashMap[string, WoxPreview] // computed lazy previews by tab id, see Manager.GetResultPreviewTab
+
+	DisableGlobalActions bool
+	SetQueryOnSelect     string                              // re-applied when result is refreshed, see QueryResult.SetQueryOnSelect
+	URL                  string                              // re-applied when result is refreshed, see QueryResult.URL
+	Expand               func(context.Context) []QueryResult // nil if result is not expandable
+	LazyIcon             func(context.Context) WoxImage      // nil if result has no lazy icon
+	OnShown              func(context.Context)               // nil if plugin doesn't track impressions, see QueryResult.OnShown
+	ResolvedIcon         atomic.Pointer[WoxImage]            // computed lazy icon, see Manager.GetResultIcon
+	ParentId             string
+
+	Score         atomic.Int64 // latest adjusted score before decay, replaced by Manager.UpdateResultScore
+	Timestamp     int64        // unix timestamp in milliseconds of the result, see QueryResult.Timestamp
+	ScoreHalfLife int64        // half life of score in milliseconds, 0 if score doesn't decay
+
+	LastRefreshTimestamp int64 // last time the refresh function was actually called
+	LastAccessTimestamp  int64 // last time the result is used by UI (E.g. action, refresh, preview), used to evict least recently used results
+
+	previewLock sync.RWMutex
+}
+
+// decayedScore returns the latest score decayed by the age of the result at now, see decayScore
+func (c *QueryResultCache) decayedScore(now time.Time) int64 {
+	return decayScore(c.Score.Load(), c.Timestamp, c.ScoreHalfLife, now)
+}
+
+// getPreview returns the cached preview, it may be replaced concurrently by refresh or preview updates
+func (c *QueryResultCache) getPreview() WoxPreview {
+	c.previewLock.RLock()
+	defer c.previewLock.RUnlock()
+	return c.Preview
+}
+
+func (c *QueryResultCache) setPreview(preview WoxPreview) {
+	c.previewLock.Lock()
+	defer c.previewLock.Unlock()
+	c.Preview = preview
+}
+
+func (c *QueryResultCache) getActionContext() ActionContext {
+	return ActionContext{
+		ContextData:    c.ContextData,
+		Selection:      c.Query.Selection,
+		TriggerKeyword: c.Query.TriggerKeyword,
+	}
+}
+
+// sanitizeQueryText normalizes user input (E.g. pasted text) before parsing:
+// tabs and line breaks become spaces so they still work as separators, other control characters (E.g. NUL) are dropped,
+// and text is normalized to NFC so composed and decomposed accents match each other.
+func sanitizeQueryText(query string) string {
+	var sb strings.Builder
+	for _, r := range query {
+		if r == '\t' || r == '\n' || r == '\r' {
+			sb.WriteRune(' ')
+			continue
+		}
+		if unicode.IsControl(r) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return norm.NFC.String(sb.String())
+}
+
+// newQuerySelectionWithPlugins creates a selection query from the text user typed after invoking selection mode.
+// If the text starts with a trigger keyword of a selection plugin followed by a space (E.g. "tr hello"), the query is scoped
+// to that plugin and the rest becomes Search, otherwise all selection plugins receive the whole text as Search.
+// In both cases Wox filters selection results by Search, see Manager.queryForPlugin
+func newQuerySelectionWithPlugins(query string, s selection.Selection, pluginInstances []*Instance) (Query, *Instance) {
+	query = sanitizeQueryText(query)
+	selectionQuery := Query{
+		Type:      QueryTypeSelection,
+		RawQuery:  query,
+		Search:    query,
+		Selection: s,
+	}
+
+	possibleTriggerKeyword, search, hasSpace := strings.Cut(query, " ")
+	if !hasSpace || possibleTriggerKeyword == "*" {
+		return selectionQuery, nil
+	}
+
+	pluginInstance, matchedTriggerKeyword, found := findTriggerKeywordPlugin(pluginInstances, possibleTriggerKeyword, func(instance *Instance) bool {
+		return instance.IsSupportSelection()
+	})
+	if !found {
+		return selectionQuery, nil
+	}
+
+	selectionQuery.TriggerKeyword = matchedTriggerKeyword
+	selectionQuery.Search = search
+	return selectionQuery, pluginInstance
 }
 
 func newQueryInputWithPlugins(query string, pluginInstances []*Instance) (Query, *Instance) {
+	query = sanitizeQueryText(query)
 	var terms = strings.Split(query, " ")
 	if len(terms) == 0 {
 		return Query{
@@ -227,15 +641,16 @@ func newQueryInputWithPlugins(query string, pluginInstances []*Instance) (Query,
 
 	var rawQuery = query
 	var triggerKeyword, command, search string
+	var matchedQueryCommand *MetadataCommand
+	var commandInvocation *CommandInvocation
+	var unknownCommand string
 	var possibleTriggerKeyword = terms[0]
 	var mustContainSpace = strings.Contains(query, " ")
 
-	pluginInstance, found := lo.Find(pluginInstances, func(instance *Instance) bool {
-		return lo.Contains(instance.GetTriggerKeywords(), possibleTriggerKeyword)
-	})
+	pluginInstance, matchedTriggerKeyword, found := findTriggerKeywordPlugin(pluginInstances, possibleTriggerKeyword, nil)
 	if found && mustContainSpace {
-		// non global trigger keyword
-		triggerKeyword = possibleTriggerKeyword
+		// non global trigger keyword, use the keyword defined by plugin so that case-insensitive matches look the same to plugins
+		triggerKeyword = matchedTriggerKeyword
 
 		if len(terms) == 1 {
 			// no command and search
@@ -248,16 +663,19 @@ func newQueryInputWithPlugins(query string, pluginInstances []*Instance) (Query,
 				search = terms[1]
 			} else {
 				var possibleCommand = terms[1]
-				if lo.ContainsBy(pluginInstance.GetQueryCommands(), func(item MetadataCommand) bool {
-					return item.Command == possibleCommand
-				}) {
+				if matchedCommand, commandFound := pluginInstance.MatchQueryCommand(possibleCommand); commandFound {
 					// command and search
-					command = possibleCommand
+					command = matchedCommand.Command
+					matchedQueryCommand = &matchedCommand
 					search = strings.Join(terms[2:], " ")
+					commandInvocation = parseCommandInvocation(matchedCommand, search)
 				} else {
 					// no command, only search
 					command = ""
 					search = strings.Join(terms[1:], " ")
+					if possibleCommand != "" && pluginInstance.Metadata.IsSupportFeature(MetadataFeatureUnknownCommand) && len(pluginInstance.GetQueryCommands()) > 0 {
+						unknownCommand = possibleCommand
+					}
 				}
 			}
 		}
@@ -270,10 +688,24 @@ func newQueryInputWithPlugins(query string, pluginInstances []*Instance) (Query,
 	}
 
 	return Query{
-		Type:           QueryTypeInput,
-		RawQuery:       query,
-		TriggerKeyword: triggerKeyword,
-		Command:        command,
-		Search:         search,
+		Type:              QueryTypeInput,
+		RawQuery:          query,
+		TriggerKeyword:    triggerKeyword,
+		Command:           command,
+		MatchedCommand:    matchedQueryCommand,
+		CommandInvocation: commandInvocation,
+		UnknownCommand:    unknownCommand,
+		Search:            search,
 	}, pluginInstance
 }
+
+// applyLiteralMatchMode strips the literal match prefix from Search and marks the query, see Query.IsLiteralMatch
+func applyLiteralMatchMode(query Query, literalMatchPrefix string) Query {
+	if literalMatchPrefix == "" || !strings.HasPrefix(query.Search, literalMatchPrefix) {
+		return query
+	}
+
+	query.Search = strings.TrimPrefix(query.Search, literalMatchPrefix)
+	query.IsLiteralMatch = true
+	return query
+}
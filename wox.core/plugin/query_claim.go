@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+// claimers must answer within this time, a slow claimer won't delay results of other plugins any longer
+const queryClaimTimeoutMs = 50
+
+// QueryClaimer lets a plugin own a query it recognizes (E.g. a full url), so only its results are shown and other plugins are not queried.
+// Plugin must enable MetadataFeatureClaimQuery too, ClaimQuery is ignored otherwise.
+// ClaimQuery is called before the query is dispatched, it should be cheap and return quickly (see queryClaimTimeoutMs)
+type QueryClaimer interface {
+	ClaimQuery(ctx context.Context, query Query) bool
+}
+
+// findQueryClaimant returns the plugin which claimed the query, nil if no plugin claimed it.
+// Only plugins which can operate the query are asked, see MetadataFeatureClaimQuery
+func (m *Manager) findQueryClaimant(ctx context.Context, query Query) *Instance {
+	candidates := lo.Filter(m.getInstances(), func(instance *Instance, _ int) bool {
+		if !instance.Metadata.IsSupportFeature(MetadataFeatureClaimQuery) {
+			return false
+		}
+		if _, ok := instance.Plugin.(QueryClaimer); !ok {
+			return false
+		}
+		return m.canOperateQuery(ctx, instance, query)
+	})
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	claimant := raceQueryClaims(ctx, candidates, query, time.Duration(queryClaimTimeoutMs)*time.Millisecond)
+	if claimant != nil {
+		logger.Info(ctx, fmt.Sprintf("[%s] claimed query: %s, other plugins are skipped", claimant.Metadata.Name, query.String()))
+	}
+	return claimant
+}
+
+// raceQueryClaims asks candidates concurrently, first plugin to claim wins and later claims are ignored.
+// Returns nil if no candidate claimed the query within timeout
+func raceQueryClaims(ctx context.Context, candidates []*Instance, query Query, timeout time.Duration) *Instance {
+	claimCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// buffered so late answers don't block after we returned
+	answers := make(chan *Instance, len(candidates))
+	for _, candidate := range candidates {
+		util.Go(claimCtx, fmt.Sprintf("[%s] claim query", candidate.Metadata.Name), func() {
+			if candidate.Plugin.(QueryClaimer).ClaimQuery(claimCtx, query) {
+				answers <- candidate
+			} else {
+				answers <- nil
+			}
+		}, func() {
+			answers <- nil
+		})
+	}
+
+	for range candidates {
+		select {
+		case claimant := <-answers:
+			if claimant != nil {
+				return claimant
+			}
+		case <-claimCtx.Done():
+			return nil
+		}
+	}
+
+	return nil
+}
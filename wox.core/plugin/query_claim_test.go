@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueryClaimer struct {
+	claim bool
+	delay time.Duration
+}
+
+func (c fakeQueryClaimer) Init(ctx context.Context, initParams InitParams) {
+}
+
+func (c fakeQueryClaimer) Query(ctx context.Context, query Query) []QueryResult {
+	return nil
+}
+
+func (c fakeQueryClaimer) ClaimQuery(ctx context.Context, query Query) bool {
+	select {
+	case <-time.After(c.delay):
+		return c.claim
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func newQueryClaimerInstance(id string, claimer fakeQueryClaimer) *Instance {
+	return &Instance{Plugin: claimer, Metadata: Metadata{Id: id, Name: id}}
+}
+
+func TestRaceQueryClaims_FirstClaimWins(t *testing.T) {
+	candidates := []*Instance{
+		newQueryClaimerInstance("slow", fakeQueryClaimer{claim: true, delay: 30 * time.Millisecond}),
+		newQueryClaimerInstance("fast", fakeQueryClaimer{claim: true, delay: time.Millisecond}),
+		newQueryClaimerInstance("declined", fakeQueryClaimer{claim: false}),
+	}
+	claimant := raceQueryClaims(context.Background(), candidates, Query{RawQuery: "https://github.com"}, time.Second)
+	assert.NotNil(t, claimant)
+	assert.Equal(t, "fast", claimant.Metadata.Id)
+}
+
+func TestRaceQueryClaims_NoClaim(t *testing.T) {
+	candidates := []*Instance{
+		newQueryClaimerInstance("a", fakeQueryClaimer{claim: false}),
+		newQueryClaimerInstance("b", fakeQueryClaimer{claim: false, delay: time.Millisecond}),
+	}
+	assert.Nil(t, raceQueryClaims(context.Background(), candidates, Query{RawQuery: "wox"}, time.Second))
+}
+
+func TestRaceQueryClaims_Timeout(t *testing.T) {
+	candidates := []*Instance{
+		newQueryClaimerInstance("too-slow", fakeQueryClaimer{claim: true, delay: time.Second}),
+	}
+	start := time.Now()
+	assert.Nil(t, raceQueryClaims(context.Background(), candidates, Query{RawQuery: "wox"}, 20*time.Millisecond))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
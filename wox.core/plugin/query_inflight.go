@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"wox/util"
+)
+
+// inflightQuery coalesces identical queries (E.g. double keypress or request replayed after reconnection).
+// The first requester starts the plugin pipeline, following identical requesters are attached to the same
+// pipeline as subscribers: they get all batches emitted so far replayed, then live batches, then done.
+// The pipeline is cancelled only when all subscribers left before it finished.
+type inflightQuery struct {
+	lock        sync.Mutex
+	batches     [][]QueryResultUI // batches emitted so far, replayed to late subscribers
+	subscribers []*inflightQuerySubscriber
+	finished    bool
+	cancel      context.CancelFunc
+}
+
+type inflightQuerySubscriber struct {
+	ctx      context.Context
+	results  chan []QueryResultUI
+	done     chan bool
+	finished chan struct{}
+}
+
+func getInflightQueryKey(query Query) string {
+	if query.CarriedContext != nil {
+		// same text means a different query within another carried context
+		return fmt.Sprintf("%s|%s|%s|%s", query.Type, query.RawQuery, query.Selection.String(), query.CarriedContext.ContextData)
+	}
+	return fmt.Sprintf("%s|%s|%s", query.Type, query.RawQuery, query.Selection.String())
+}
+
+// subscribe must be called with Manager.inflightLock held, so that a finishing query won't be joined
+func (q *inflightQuery) subscribe(ctx context.Context) *inflightQuerySubscriber {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	subscriber := &inflightQuerySubscriber{
+		ctx:      ctx,
+		results:  make(chan []QueryResultUI, len(q.batches)+10),
+		done:     make(chan bool, 1),
+		finished: make(chan struct{}),
+	}
+	for _, batch := range q.batches {
+		subscriber.results <- copyQueryResultsUI(batch)
+	}
+	q.subscribers = append(q.subscribers, subscriber)
+
+	util.Go(ctx, "inflight query subscriber watcher", func() {
+		select {
+		case <-ctx.Done():
+			q.unsubscribe(subscriber)
+		case <-subscriber.finished:
+		}
+	})
+
+	return subscriber
+}
+
+func (q *inflightQuery) unsubscribe(subscriber *inflightQuerySubscriber) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.finished {
+		return
+	}
+
+	for i, s := range q.subscribers {
+		if s == subscriber {
+			q.subscribers = append(q.subscribers[:i], q.subscribers[i+1:]...)
+			break
+		}
+	}
+	if len(q.subscribers) == 0 {
+		q.cancel()
+	}
+}
+
+// publish must be called by a single goroutine, together with finish, so batches arrive before done.
+// A slow subscriber blocks publish, so batches are sent without holding the lock, subscribing or leaving meanwhile is not blocked.
+// Subscribers joining meanwhile get this batch replayed instead
+func (q *inflightQuery) publish(batch []QueryResultUI) {
+	q.lock.Lock()
+	q.batches = append(q.batches, batch)
+	subscribers := append([]*inflightQuerySubscriber(nil), q.subscribers...)
+	q.lock.Unlock()
+
+	for _, subscriber := range subscribers {
+		// every subscriber gets its own copy, because requester may modify results (E.g. set query id)
+		select {
+		case subscriber.results <- copyQueryResultsUI(batch):
+		case <-subscriber.ctx.Done():
+		}
+	}
+}
+
+func (q *inflightQuery) finish() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.finished = true
+	for _, subscriber := range q.subscribers {
+		subscriber.done <- true
+		close(subscriber.finished)
+	}
+}
+
+func copyQueryResultsUI(results []QueryResultUI) []QueryResultUI {
+	copied := make([]QueryResultUI, len(results))
+	copy(copied, results)
+	return copied
+}
@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInflightQuery_SlowSubscriberDoesNotBlockSubscribing(t *testing.T) {
+	logger = util.GetLogger()
+	q := &inflightQuery{cancel: func() {}}
+
+	slowCtx, cancelSlow := context.WithCancel(context.Background())
+	defer cancelSlow()
+	slow := q.subscribe(slowCtx)
+
+	// slow subscriber never reads, publishing blocks once its buffer is full
+	published := make(chan struct{})
+	go func() {
+		for range cap(slow.results) + 1 {
+			q.publish([]QueryResultUI{{Id: "result"}})
+		}
+		close(published)
+	}()
+	assert.Eventually(t, func() bool { return len(slow.results) == cap(slow.results) }, time.Second, time.Millisecond)
+
+	subscribed := make(chan *inflightQuerySubscriber)
+	go func() {
+		subscribed <- q.subscribe(context.Background())
+	}()
+	select {
+	case late := <-subscribed:
+		assert.GreaterOrEqual(t, len(late.results), cap(slow.results))
+	case <-time.After(time.Second):
+		t.Fatal("subscribe is blocked by a slow subscriber")
+	}
+
+	// publishing continues once the slow subscriber left
+	cancelSlow()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publish is still blocked after slow subscriber left")
+	}
+}
+
+func TestInflightQuery_PublishThenFinish(t *testing.T) {
+	logger = util.GetLogger()
+	q := &inflightQuery{cancel: func() {}}
+	subscriber := q.subscribe(context.Background())
+
+	q.publish([]QueryResultUI{{Id: "a"}})
+	q.publish([]QueryResultUI{{Id: "b"}})
+	q.finish()
+
+	assert.Equal(t, "a", (<-subscriber.results)[0].Id)
+	assert.Equal(t, "b", (<-subscriber.results)[0].Id)
+	assert.True(t, <-subscriber.done)
+}
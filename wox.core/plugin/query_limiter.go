@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errQuerySuperseded = errors.New("query is superseded by a newer query of the plugin")
+
+// queryConcurrencyLimiter bounds running queries of each plugin, see setting.WoxSetting.MaxConcurrentQueriesPerPlugin.
+// It complements debounce: when a plugin is at the limit (E.g. user types fast and the plugin is slow), a new query cancels the oldest
+// running query of the plugin and waits for its slot. Only the latest waiting query is kept, older waiting queries give up,
+// so the query user is looking at always runs next. Plugins which ignore cancellation keep their slot until they return
+type queryConcurrencyLimiter struct {
+	lock    sync.Mutex
+	plugins map[string]*pluginQuerySlots // by plugin id
+}
+
+type pluginQuerySlots struct {
+	running  []*querySlot  // oldest first
+	waitSeq  uint64        // sequence of the latest waiting query
+	released chan struct{} // closed and replaced when a slot is released or a newer query starts waiting
+}
+
+type querySlot struct {
+	cancel context.CancelFunc
+}
+
+type queryLimitExemptContextKey struct{}
+
+// newQueryLimitExemptContext marks queries which are not typed by user (E.g. preload, QuerySync), they bypass the limiter,
+// so they neither wait for user queries nor cancel them
+func newQueryLimitExemptContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryLimitExemptContextKey{}, true)
+}
+
+func isQueryLimitExempt(ctx context.Context) bool {
+	exempt, _ := ctx.Value(queryLimitExemptContextKey{}).(bool)
+	return exempt
+}
+
+func newQueryConcurrencyLimiter() *queryConcurrencyLimiter {
+	return &queryConcurrencyLimiter{plugins: map[string]*pluginQuerySlots{}}
+}
+
+// acquire waits for a query slot of the plugin, limit <= 0 means no limit. The returned ctx is cancelled when a newer query needs the slot,
+// release must be called when the query returned. Error is returned if ctx is done or a newer query started waiting meanwhile.
+// Queries exempted by newQueryLimitExemptContext are not limited
+func (l *queryConcurrencyLimiter) acquire(ctx context.Context, pluginId string, limit int) (context.Context, func(), error) {
+	if limit <= 0 || isQueryLimitExempt(ctx) {
+		return ctx, func() {}, nil
+	}
+
+	l.lock.Lock()
+	slots, exist := l.plugins[pluginId]
+	if !exist {
+		slots = &pluginQuerySlots{released: make(chan struct{})}
+		l.plugins[pluginId] = slots
+	}
+	slots.waitSeq++
+	seq := slots.waitSeq
+	// wake up older waiting queries, they give up because they are not the latest anymore
+	slots.notifyLocked()
+
+	for {
+		if seq != slots.waitSeq {
+			l.lock.Unlock()
+			return nil, nil, errQuerySuperseded
+		}
+		if len(slots.running) < limit {
+			queryCtx, cancel := context.WithCancel(ctx)
+			slot := &querySlot{cancel: cancel}
+			slots.running = append(slots.running, slot)
+			l.lock.Unlock()
+			return queryCtx, func() { l.release(pluginId, slot) }, nil
+		}
+
+		// make room for this query, the oldest query returns soon if it honors cancellation
+		slots.running[0].cancel()
+		released := slots.released
+		l.lock.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		l.lock.Lock()
+	}
+}
+
+func (l *queryConcurrencyLimiter) release(pluginId string, slot *querySlot) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	slot.cancel()
+	slots, exist := l.plugins[pluginId]
+	if !exist {
+		return
+	}
+	for i, s := range slots.running {
+		if s == slot {
+			slots.running = append(slots.running[:i], slots.running[i+1:]...)
+			break
+		}
+	}
+	slots.notifyLocked()
+}
+
+func (s *pluginQuerySlots) notifyLocked() {
+	close(s.released)
+	s.released = make(chan struct{})
+}
@@ -0,0 +1,111 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryConcurrencyLimiter_RapidQueries(t *testing.T) {
+	limiter := newQueryConcurrencyLimiter()
+	const limit = 3
+	const queryCount = 50
+
+	var running, maxRunning, executed atomic.Int32
+	var lastExecuted atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < queryCount; i++ {
+		wg.Add(1)
+		isLast := i == queryCount-1
+		go func() {
+			defer wg.Done()
+			queryCtx, release, err := limiter.acquire(context.Background(), "plugin", limit)
+			if err != nil {
+				return
+			}
+			defer release()
+
+			current := running.Add(1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			executed.Add(1)
+			if isLast {
+				lastExecuted.Store(true)
+			}
+
+			// a slow plugin which honors cancellation
+			select {
+			case <-queryCtx.Done():
+			case <-time.After(50 * time.Millisecond):
+			}
+			running.Add(-1)
+		}()
+		time.Sleep(time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxRunning.Load(), int32(limit))
+	assert.Greater(t, executed.Load(), int32(0))
+	assert.True(t, lastExecuted.Load(), "latest query should always run")
+}
+
+func TestQueryConcurrencyLimiter_CancelOldest(t *testing.T) {
+	limiter := newQueryConcurrencyLimiter()
+
+	firstCtx, releaseFirst, err := limiter.acquire(context.Background(), "plugin", 1)
+	assert.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		_, releaseSecond, secondErr := limiter.acquire(context.Background(), "plugin", 1)
+		assert.NoError(t, secondErr)
+		releaseSecond()
+		close(acquired)
+	}()
+
+	select {
+	case <-firstCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("oldest query should be cancelled")
+	}
+	releaseFirst()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("newer query should run after the oldest one returned")
+	}
+}
+
+func TestQueryConcurrencyLimiter_NoLimit(t *testing.T) {
+	limiter := newQueryConcurrencyLimiter()
+	for i := 0; i < 10; i++ {
+		queryCtx, _, err := limiter.acquire(context.Background(), "plugin", -1)
+		assert.NoError(t, err)
+		assert.NoError(t, queryCtx.Err())
+	}
+}
+
+func TestQueryConcurrencyLimiter_Exempt(t *testing.T) {
+	limiter := newQueryConcurrencyLimiter()
+
+	userCtx, releaseUser, err := limiter.acquire(context.Background(), "plugin", 1)
+	assert.NoError(t, err)
+	defer releaseUser()
+
+	// exempt queries (E.g. preload) neither wait for nor cancel the running user query
+	exemptCtx, releaseExempt, err := limiter.acquire(newQueryLimitExemptContext(context.Background()), "plugin", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, exemptCtx.Err())
+	assert.NoError(t, userCtx.Err())
+	releaseExempt()
+	assert.NoError(t, userCtx.Err())
+}
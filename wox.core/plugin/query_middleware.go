@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"wox/util"
+)
+
+// QueryHandler dispatches a query and returns its results, see Manager.Query
+type QueryHandler func(ctx context.Context, query Query) (results chan []QueryResultUI, done chan bool)
+
+// QueryMiddleware intercepts every query before it reaches plugins. Handle can:
+//   - modify the query (or ctx) and pass it downstream by calling next
+//   - short-circuit the query by returning its own results without calling next, see NewStaticQueryResults
+//   - observe the query (E.g. logging) and call next unchanged
+type QueryMiddleware struct {
+	Name string
+	// Middlewares run in ascending priority, middlewares with the same priority run in registration order
+	Priority int
+	Handle   func(ctx context.Context, query Query, next QueryHandler) (results chan []QueryResultUI, done chan bool)
+}
+
+// RegisterQueryMiddleware adds a middleware to the query chain, a middleware with the same name is replaced
+func (m *Manager) RegisterQueryMiddleware(ctx context.Context, middleware QueryMiddleware) {
+	m.middlewareLock.Lock()
+	defer m.middlewareLock.Unlock()
+
+	for i, existing := range m.queryMiddlewares {
+		if existing.Name == middleware.Name {
+			m.queryMiddlewares = append(m.queryMiddlewares[:i:i], m.queryMiddlewares[i+1:]...)
+			break
+		}
+	}
+	m.queryMiddlewares = append(m.queryMiddlewares, middleware)
+	sort.SliceStable(m.queryMiddlewares, func(i, j int) bool {
+		return m.queryMiddlewares[i].Priority < m.queryMiddlewares[j].Priority
+	})
+
+	logger.Info(ctx, fmt.Sprintf("query middleware registered: %s, priority: %d", middleware.Name, middleware.Priority))
+}
+
+func (m *Manager) UnregisterQueryMiddleware(ctx context.Context, name string) {
+	m.middlewareLock.Lock()
+	defer m.middlewareLock.Unlock()
+
+	for i, existing := range m.queryMiddlewares {
+		if existing.Name == name {
+			m.queryMiddlewares = append(m.queryMiddlewares[:i:i], m.queryMiddlewares[i+1:]...)
+			logger.Info(ctx, fmt.Sprintf("query middleware unregistered: %s", name))
+			return
+		}
+	}
+}
+
+// buildQueryChain wraps handler with all registered middlewares, the first middleware is the outermost one
+func (m *Manager) buildQueryChain(handler QueryHandler) QueryHandler {
+	m.middlewareLock.RLock()
+	middlewares := m.queryMiddlewares
+	m.middlewareLock.RUnlock()
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware, next := middlewares[i], handler
+		handler = func(ctx context.Context, query Query) (chan []QueryResultUI, chan bool) {
+			return middleware.Handle(ctx, query, next)
+		}
+	}
+
+	return handler
+}
+
+// NewStaticQueryResults returns channels which emit the given results and then finish, used by middlewares to short-circuit a query.
+// Results are emitted before done, the sending stops if ctx is done before the caller reads them.
+func NewStaticQueryResults(ctx context.Context, results []QueryResultUI) (chan []QueryResultUI, chan bool) {
+	resultChan := make(chan []QueryResultUI)
+	doneChan := make(chan bool)
+	util.Go(ctx, "static query results", func() {
+		if len(results) > 0 {
+			select {
+			case resultChan <- results:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case doneChan <- true:
+		case <-ctx.Done():
+		}
+	})
+	return resultChan, doneChan
+}
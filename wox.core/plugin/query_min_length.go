@@ -0,0 +1,30 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// isSearchTooShort returns true if search of the input query is shorter than the min search length of plugin, so plugin is not queried,
+// see MetadataFeatureMinSearchLength. Length is counted in characters of Query.Search with spaces trimmed, which excludes trigger keyword and command.
+// Queries with trigger keyword of plugin use TriggeredLength, so plugin can still list things (E.g. its commands) when user explicitly asks for it.
+// Selection queries and empty queries are never skipped
+func isSearchTooShort(ctx context.Context, pluginInstance *Instance, query Query) bool {
+	if query.Type != QueryTypeInput || query.IsEmptyInput() || !pluginInstance.Metadata.IsSupportFeature(MetadataFeatureMinSearchLength) {
+		return false
+	}
+
+	params, err := pluginInstance.Metadata.GetFeatureParamsForMinSearchLength()
+	if err != nil {
+		logger.Error(ctx, fmt.Sprintf("[%s] %s, query without min search length", pluginInstance.Metadata.Name, err))
+		return false
+	}
+
+	minLength := params.Length
+	if query.TriggerKeyword != "" {
+		minLength = params.TriggeredLength
+	}
+	return utf8.RuneCountInString(strings.TrimSpace(query.Search)) < minLength
+}
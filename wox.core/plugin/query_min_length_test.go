@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMinSearchLengthInstance(params map[string]string) *Instance {
+	return &Instance{Metadata: Metadata{
+		Id:       "min-search-length",
+		Name:     "min-search-length",
+		Features: []MetadataFeature{{Name: MetadataFeatureMinSearchLength, Params: params}},
+	}}
+}
+
+func TestIsSearchTooShort_Boundary(t *testing.T) {
+	logger = util.GetLogger()
+	ctx := context.Background()
+	instance := newMinSearchLengthInstance(map[string]string{"length": "3"})
+
+	global := func(search string) Query {
+		return Query{Type: QueryTypeInput, RawQuery: search, Search: search}
+	}
+	assert.True(t, isSearchTooShort(ctx, instance, global("ab")))
+	assert.False(t, isSearchTooShort(ctx, instance, global("abc")))
+	assert.False(t, isSearchTooShort(ctx, instance, global("abcd")))
+	// spaces don't count, characters are counted instead of bytes
+	assert.True(t, isSearchTooShort(ctx, instance, global(" ab ")))
+	assert.False(t, isSearchTooShort(ctx, instance, global("微信群")))
+
+	// triggered query uses the same length by default, trigger keyword is not counted
+	triggered := Query{Type: QueryTypeInput, RawQuery: "gh ab", TriggerKeyword: "gh", Search: "ab"}
+	assert.True(t, isSearchTooShort(ctx, instance, triggered))
+	triggered.RawQuery, triggered.Search = "gh abc", "abc"
+	assert.False(t, isSearchTooShort(ctx, instance, triggered))
+
+	// empty and selection queries are never skipped
+	assert.False(t, isSearchTooShort(ctx, instance, Query{Type: QueryTypeInput}))
+	assert.False(t, isSearchTooShort(ctx, instance, Query{Type: QueryTypeSelection}))
+}
+
+func TestIsSearchTooShort_TriggeredLength(t *testing.T) {
+	logger = util.GetLogger()
+	ctx := context.Background()
+	instance := newMinSearchLengthInstance(map[string]string{"length": "3", "triggeredLength": "0"})
+
+	assert.True(t, isSearchTooShort(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "a", Search: "a"}))
+	// explicit trigger keyword with a command only is still queried
+	assert.False(t, isSearchTooShort(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "gh issues", TriggerKeyword: "gh", Command: "issues"}))
+}
+
+func TestIsSearchTooShort_InvalidParams(t *testing.T) {
+	logger = util.GetLogger()
+	ctx := context.Background()
+
+	for _, params := range []map[string]string{{}, {"length": "x"}, {"length": "-1"}, {"length": "3", "triggeredLength": "-1"}} {
+		instance := newMinSearchLengthInstance(params)
+		_, err := instance.Metadata.GetFeatureParamsForMinSearchLength()
+		assert.NotNil(t, err)
+		// invalid params don't block the plugin
+		assert.False(t, isSearchTooShort(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "a", Search: "a"}))
+	}
+
+	// plugins without the feature are always queried
+	assert.False(t, isSearchTooShort(ctx, &Instance{}, Query{Type: QueryTypeInput, RawQuery: "a", Search: "a"}))
+}
@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+	"time"
+	"wox/util"
+)
+
+// queryProgressMinIntervalMs is the min interval between two progress updates of a plugin sent to UI,
+// updates in between are coalesced and only the latest one is sent when the interval elapsed
+const queryProgressMinIntervalMs = 100
+
+type queryProgressContextKey struct{}
+
+// QueryProgress is sent to UI while a plugin is still querying (E.g. "Searching… 40%"), it's shown before any results arrive.
+// Progress of a plugin is cleared (Done is true) when the plugin finished the query, progress of all plugins is cleared
+// (Done is true and PluginId is empty) when the query is done or cancelled
+type QueryProgress struct {
+	QueryId  string
+	PluginId string
+	Progress int // 0-100, negative means indeterminate
+	Text     string
+	Done     bool
+}
+
+// queryProgressReporter sends progress of a query to UI, see NewQueryProgressContext
+type queryProgressReporter struct {
+	queryId  string
+	send     func(progress QueryProgress)
+	lock     sync.Mutex
+	closed   bool
+	reported bool
+	lastSent map[string]int64          // last sent timestamp by plugin id
+	pending  map[string]*QueryProgress // coalesced progress waiting for the interval to elapse by plugin id
+}
+
+// NewQueryProgressContext attaches a progress reporter of queryId to ctx, plugins queried with the returned ctx can report progress
+// by API.ReportQueryProgress. The returned close func must be called when the query is done or cancelled, it clears the progress in UI
+func NewQueryProgressContext(ctx context.Context, queryId string, send func(progress QueryProgress)) (context.Context, func()) {
+	reporter := &queryProgressReporter{
+		queryId:  queryId,
+		send:     send,
+		lastSent: map[string]int64{},
+		pending:  map[string]*QueryProgress{},
+	}
+	return context.WithValue(ctx, queryProgressContextKey{}, reporter), reporter.close
+}
+
+func getQueryProgressReporter(ctx context.Context) *queryProgressReporter {
+	reporter, _ := ctx.Value(queryProgressContextKey{}).(*queryProgressReporter)
+	return reporter
+}
+
+func (r *queryProgressReporter) report(pluginId string, progress int, text string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	queryProgress := &QueryProgress{QueryId: r.queryId, PluginId: pluginId, Progress: min(progress, 100), Text: text}
+	elapsed := util.GetSystemTimestamp() - r.lastSent[pluginId]
+	if elapsed >= queryProgressMinIntervalMs {
+		r.sendLocked(*queryProgress)
+		return
+	}
+
+	// a flush is already scheduled, it will send the latest progress
+	_, scheduled := r.pending[pluginId]
+	r.pending[pluginId] = queryProgress
+	if !scheduled {
+		time.AfterFunc(time.Duration(queryProgressMinIntervalMs-elapsed)*time.Millisecond, func() {
+			r.flush(pluginId)
+		})
+	}
+}
+
+func (r *queryProgressReporter) flush(pluginId string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	queryProgress, exist := r.pending[pluginId]
+	if !exist || r.closed {
+		return
+	}
+	delete(r.pending, pluginId)
+	r.sendLocked(*queryProgress)
+}
+
+// finish clears the progress of a plugin which finished the query, it's not rate limited
+func (r *queryProgressReporter) finish(pluginId string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.pending, pluginId)
+	if r.closed || r.lastSent[pluginId] == 0 {
+		return
+	}
+	r.sendLocked(QueryProgress{QueryId: r.queryId, PluginId: pluginId, Done: true})
+}
+
+func (r *queryProgressReporter) close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.pending = map[string]*QueryProgress{}
+	if r.reported {
+		r.send(QueryProgress{QueryId: r.queryId, Done: true})
+	}
+}
+
+func (r *queryProgressReporter) sendLocked(queryProgress QueryProgress) {
+	r.lastSent[queryProgress.PluginId] = util.GetSystemTimestamp()
+	r.reported = true
+	r.send(queryProgress)
+}
+
+// startQueryProgress makes ctx of the query the target of API.ReportQueryProgress of the plugin until the returned func is called.
+// It's keyed by plugin instead of passed by ctx, because host plugins call API in another ctx
+func (m *Manager) startQueryProgress(ctx context.Context, pluginInstance *Instance) func() {
+	reporter := getQueryProgressReporter(ctx)
+	if reporter == nil {
+		return func() {}
+	}
+
+	pluginId := pluginInstance.Metadata.Id
+	m.queryProgress.Store(pluginId, reporter)
+	return func() {
+		reporter.finish(pluginId)
+		// a newer query of the plugin may have started already
+		if current, exist := m.queryProgress.Load(pluginId); exist && current == reporter {
+			m.queryProgress.Delete(pluginId)
+		}
+	}
+}
+
+// ReportQueryProgress reports progress of the running query of a plugin, it's ignored if the plugin is not querying
+func (m *Manager) ReportQueryProgress(ctx context.Context, pluginInstance *Instance, progress int, text string) {
+	reporter, exist := m.queryProgress.Load(pluginInstance.Metadata.Id)
+	if !exist {
+		return
+	}
+
+	reporter.report(pluginInstance.Metadata.Id, progress, text)
+}
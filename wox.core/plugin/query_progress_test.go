@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sentQueryProgress struct {
+	lock     sync.Mutex
+	progress []QueryProgress
+}
+
+func (s *sentQueryProgress) send(progress QueryProgress) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.progress = append(s.progress, progress)
+}
+
+func (s *sentQueryProgress) get() []QueryProgress {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]QueryProgress(nil), s.progress...)
+}
+
+func newTestQueryProgressReporter(sent *sentQueryProgress) *queryProgressReporter {
+	ctx, _ := NewQueryProgressContext(context.Background(), "query-id", sent.send)
+	return getQueryProgressReporter(ctx)
+}
+
+func TestQueryProgress_UpdatesAreCoalesced(t *testing.T) {
+	sent := &sentQueryProgress{}
+	reporter := newTestQueryProgressReporter(sent)
+
+	// first update is sent immediately, following ones within the interval are coalesced into the latest
+	for i := 1; i <= 5; i++ {
+		reporter.report("plugin", i*10, "searching")
+	}
+	assert.Len(t, sent.get(), 1)
+	assert.Equal(t, 10, sent.get()[0].Progress)
+
+	assert.Eventually(t, func() bool { return len(sent.get()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 50, sent.get()[1].Progress)
+
+	// nothing more is sent once pending update is flushed
+	time.Sleep(2 * queryProgressMinIntervalMs * time.Millisecond)
+	assert.Len(t, sent.get(), 2)
+}
+
+func TestQueryProgress_PluginsAreLimitedSeparately(t *testing.T) {
+	sent := &sentQueryProgress{}
+	reporter := newTestQueryProgressReporter(sent)
+
+	reporter.report("a", 10, "")
+	reporter.report("b", 20, "")
+	assert.Len(t, sent.get(), 2)
+}
+
+func TestQueryProgress_ProgressIsCapped(t *testing.T) {
+	sent := &sentQueryProgress{}
+	reporter := newTestQueryProgressReporter(sent)
+
+	reporter.report("plugin", 150, "")
+	assert.Equal(t, 100, sent.get()[0].Progress)
+}
+
+func TestQueryProgress_FinishAndClose(t *testing.T) {
+	sent := &sentQueryProgress{}
+	reporter := newTestQueryProgressReporter(sent)
+
+	reporter.report("plugin", 10, "")
+	reporter.report("plugin", 20, "")
+	// finish is not rate limited and drops the pending update
+	reporter.finish("plugin")
+	assert.Equal(t, QueryProgress{QueryId: "query-id", PluginId: "plugin", Done: true}, sent.get()[1])
+
+	// a plugin which never reported progress doesn't need to be cleared
+	reporter.finish("other")
+	assert.Len(t, sent.get(), 2)
+
+	reporter.close()
+	reporter.close()
+	reporter.report("plugin", 30, "")
+	time.Sleep(2 * queryProgressMinIntervalMs * time.Millisecond)
+	assert.Equal(t, []QueryProgress{
+		{QueryId: "query-id", PluginId: "plugin", Progress: 10},
+		{QueryId: "query-id", PluginId: "plugin", Done: true},
+		{QueryId: "query-id", Done: true},
+	}, sent.get())
+}
+
+func TestQueryProgress_CloseWithoutProgressSendsNothing(t *testing.T) {
+	sent := &sentQueryProgress{}
+	reporter := newTestQueryProgressReporter(sent)
+
+	reporter.close()
+	assert.Empty(t, sent.get())
+}
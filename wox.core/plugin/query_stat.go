@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"sort"
+	"sync"
+	"wox/util"
+)
+
+// QueryPluginStat is the timing of a single plugin in a query
+type QueryPluginStat struct {
+	PluginId    string
+	PluginName  string
+	ResultCount int
+	CostMs      int64
+}
+
+// QueryStat is the timing breakdown of a query, only collected when EnableQueryDebug is on in wox setting
+type QueryStat struct {
+	Query          string
+	StartTimestamp int64
+	TotalCostMs    int64
+	Plugins        []QueryPluginStat // sorted by cost desc
+}
+
+type queryStatCollector struct {
+	QueryStat
+	lock sync.Mutex
+}
+
+func newQueryStatCollector(query Query) *queryStatCollector {
+	return &queryStatCollector{
+		QueryStat: QueryStat{
+			Query:          query.String(),
+			StartTimestamp: util.GetSystemTimestamp(),
+		},
+	}
+}
+
+// addPlugin is safe to call on nil collector, which means query debug is disabled
+func (s *queryStatCollector) addPlugin(pluginInstance *Instance, resultCount int, costMs int64) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Plugins = append(s.Plugins, QueryPluginStat{
+		PluginId:    pluginInstance.Metadata.Id,
+		PluginName:  pluginInstance.Metadata.Name,
+		ResultCount: resultCount,
+		CostMs:      costMs,
+	})
+	sort.SliceStable(s.Plugins, func(i, j int) bool {
+		return s.Plugins[i].CostMs > s.Plugins[j].CostMs
+	})
+	s.TotalCostMs = util.GetSystemTimestamp() - s.StartTimestamp
+}
+
+func (s *queryStatCollector) snapshot() QueryStat {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	plugins := make([]QueryPluginStat, len(s.Plugins))
+	copy(plugins, s.Plugins)
+	return QueryStat{
+		Query:          s.Query,
+		StartTimestamp: s.StartTimestamp,
+		TotalCostMs:    s.TotalCostMs,
+		Plugins:        plugins,
+	}
+}
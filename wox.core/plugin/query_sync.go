@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wox/setting"
+	"wox/util"
+)
+
+// QuerySync runs the query through the same pipeline as Query, waits for all plugins to finish and returns the final result list,
+// merged by result id, processed by result middlewares and sorted the same way as UI does. Useful for scripting and tests.
+//
+// Actions of returned results can be executed by ExecuteAction until the next query clears the result cache.
+// If plugins don't finish within WoxSetting.QueryTimeout, results collected so far are returned with an error.
+func (m *Manager) QuerySync(ctx context.Context, query Query) ([]QueryResultUI, error) {
+	startTimestamp := util.GetSystemTimestamp()
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	timeoutTimer := time.NewTimer(time.Duration(woxSetting.QueryTimeout) * time.Millisecond)
+	defer timeoutTimer.Stop()
+
+	// detach from the in-flight query once returned, so plugins still running are cancelled.
+	// Sync queries are not typed by user, they must not cancel or be cancelled by queries of UI
+	queryCtx, cancel := context.WithCancel(newQueryLimitExemptContext(ctx))
+	defer cancel()
+
+	var results []QueryResultUI
+	resultChan, doneChan := m.Query(queryCtx, query)
+	for {
+		select {
+		case batch := <-resultChan:
+			results = append(results, batch...)
+		case <-doneChan:
+			// results may still be buffered when done is received
+		drain:
+			for {
+				select {
+				case batch := <-resultChan:
+					results = append(results, batch...)
+				default:
+					break drain
+				}
+			}
+			logger.Info(ctx, fmt.Sprintf("sync query done, total results: %d, cost %d ms", len(results), util.GetSystemTimestamp()-startTimestamp))
+			return m.finishSyncQueryResults(ctx, query, results), nil
+		case <-timeoutTimer.C:
+			m.ReportQueryTimeout(ctx, "", query, util.GetSystemTimestamp()-startTimestamp)
+			return m.finishSyncQueryResults(ctx, query, results), fmt.Errorf("query timeout after %d ms: %s", woxSetting.QueryTimeout, query.String())
+		case <-ctx.Done():
+			return nil, fmt.Errorf("query is cancelled: %w", ctx.Err())
+		}
+	}
+}
+
+func (m *Manager) finishSyncQueryResults(ctx context.Context, query Query, results []QueryResultUI) []QueryResultUI {
+	results = m.ProcessResults(ctx, query, results)
+	return sortQueryResultsUI(results, setting.GetSettingManager().GetWoxSetting(ctx).LangCode)
+}
@@ -1,9 +1,11 @@
 package plugin
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"wox/setting"
+	"wox/util/selection"
 )
 
 func getFakePluginInstances() []*Instance {
@@ -47,9 +49,302 @@ func Test_NewQuery(t *testing.T) {
 	assert.Equal(t, q.TriggerKeyword, "wpm")
 	assert.Equal(t, q.Command, "install")
 	assert.Equal(t, q.Search, "q q1")
+	assert.Equal(t, "Install Wox plugins", q.MatchedCommand.Description)
 
 	q, _ = newQueryInputWithPlugins("other install q q1", getFakePluginInstances())
 	assert.Equal(t, q.TriggerKeyword, "")
 	assert.Equal(t, q.Command, "")
 	assert.Equal(t, q.Search, "other install q q1")
+	assert.Nil(t, q.MatchedCommand)
+}
+
+func Test_NewQuery_CaseInsensitiveTrigger(t *testing.T) {
+	// case-sensitive by default
+	q, _ := newQueryInputWithPlugins("WPM Install q", getFakePluginInstances())
+	assert.Equal(t, "", q.TriggerKeyword)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "WPM Install q", q.Search)
+
+	instances := getFakePluginInstances()
+	instances[0].Setting.CaseInsensitiveTriggerKeywords = true
+
+	q, instance := newQueryInputWithPlugins("WPM Install q", instances)
+	assert.Equal(t, instances[0], instance)
+	assert.Equal(t, "WPM Install q", q.RawQuery)
+	assert.Equal(t, "wpm", q.TriggerKeyword)
+	assert.Equal(t, "install", q.Command)
+	assert.Equal(t, "q", q.Search)
+
+	q, _ = newQueryInputWithPlugins("Wpm INSTALL", instances)
+	assert.Equal(t, "wpm", q.TriggerKeyword)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "INSTALL", q.Search)
+
+	q, _ = newQueryInputWithPlugins("wPm unknown q", instances)
+	assert.Equal(t, "wpm", q.TriggerKeyword)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "unknown q", q.Search)
+}
+
+func getOverlappingKeywordPluginInstances() []*Instance {
+	newInstance := func(id string, triggerKeywords ...string) *Instance {
+		return &Instance{
+			Metadata: Metadata{Id: id, TriggerKeywords: triggerKeywords},
+			Setting:  &setting.PluginSetting{},
+		}
+	}
+	return []*Instance{
+		newInstance("plugin-g", "g"),
+		newInstance("plugin-gh", "gh"),
+		newInstance("plugin-git-insensitive", "git"),
+		newInstance("plugin-git", "Git"),
+	}
+}
+
+func Test_NewQuery_OverlappingTriggerKeywords(t *testing.T) {
+	instances := getOverlappingKeywordPluginInstances()
+	instances[2].Setting.CaseInsensitiveTriggerKeywords = true
+
+	q, instance := newQueryInputWithPlugins("g q", instances)
+	assert.Equal(t, instances[0], instance)
+	assert.Equal(t, "g", q.TriggerKeyword)
+
+	q, instance = newQueryInputWithPlugins("gh q", instances)
+	assert.Equal(t, instances[1], instance)
+	assert.Equal(t, "gh", q.TriggerKeyword)
+
+	// same case wins over case-insensitive match, regardless of plugin order
+	q, instance = newQueryInputWithPlugins("Git q", instances)
+	assert.Equal(t, instances[3], instance)
+	assert.Equal(t, "Git", q.TriggerKeyword)
+
+	q, instance = newQueryInputWithPlugins("git q", instances)
+	assert.Equal(t, instances[2], instance)
+	assert.Equal(t, "git", q.TriggerKeyword)
+
+	q, instance = newQueryInputWithPlugins("GIT q", instances)
+	assert.Equal(t, instances[2], instance)
+	assert.Equal(t, "git", q.TriggerKeyword)
+
+	q, instance = newQueryInputWithPlugins("gi q", instances)
+	assert.Nil(t, instance)
+	assert.Equal(t, "", q.TriggerKeyword)
+	assert.Equal(t, "gi q", q.Search)
+}
+
+func Test_NewQuery_SameTriggerKeywordIsDeterministic(t *testing.T) {
+	instances := getOverlappingKeywordPluginInstances()
+	instances[0].Metadata.Id = "plugin-z"
+	instances[1].Metadata.TriggerKeywords = []string{"g"}
+
+	// plugin id breaks the tie, so reversing load order returns the same plugin
+	_, instance := newQueryInputWithPlugins("g q", instances)
+	assert.Equal(t, "plugin-gh", instance.Metadata.Id)
+
+	reversed := []*Instance{instances[3], instances[2], instances[1], instances[0]}
+	_, instance = newQueryInputWithPlugins("g q", reversed)
+	assert.Equal(t, "plugin-gh", instance.Metadata.Id)
+}
+
+func Test_NewQuery_PrefixCommand(t *testing.T) {
+	// exact match only by default
+	q, _ := newQueryInputWithPlugins("wpm ins q", getFakePluginInstances())
+	assert.Equal(t, "wpm", q.TriggerKeyword)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "ins q", q.Search)
+
+	instances := getFakePluginInstances()
+	instances[0].Metadata.Commands = append(instances[0].Metadata.Commands, MetadataCommand{Command: "info"})
+	instances[0].Setting.PrefixQueryCommands = true
+
+	q, _ = newQueryInputWithPlugins("wpm ins q", instances)
+	assert.Equal(t, "install", q.Command)
+	assert.Equal(t, "q", q.Search)
+
+	q, _ = newQueryInputWithPlugins("wpm u q", instances)
+	assert.Equal(t, "uninstall", q.Command)
+	assert.Equal(t, "q", q.Search)
+
+	// "in" matches both install and info, fallback to search
+	q, _ = newQueryInputWithPlugins("wpm in q", instances)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "in q", q.Search)
+
+	// exact match wins over ambiguous prefix
+	q, _ = newQueryInputWithPlugins("wpm info q", instances)
+	assert.Equal(t, "info", q.Command)
+	assert.Equal(t, "q", q.Search)
+
+	// prefix is case-sensitive unless case-insensitive matching is enabled
+	q, _ = newQueryInputWithPlugins("wpm INS q", instances)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "INS q", q.Search)
+
+	instances[0].Setting.CaseInsensitiveTriggerKeywords = true
+	q, _ = newQueryInputWithPlugins("wpm INS q", instances)
+	assert.Equal(t, "install", q.Command)
+	assert.Equal(t, "q", q.Search)
+
+	q, _ = newQueryInputWithPlugins("wpm IN q", instances)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "IN q", q.Search)
+}
+
+func Test_NewQuery_SelectionScope(t *testing.T) {
+	s := selection.Selection{Type: selection.SelectionTypeText, Text: "hello"}
+
+	// plugin doesn't support selection, all selection plugins receive the whole text
+	q, instance := newQuerySelectionWithPlugins("wpm install", s, getFakePluginInstances())
+	assert.Nil(t, instance)
+	assert.Equal(t, "", q.TriggerKeyword)
+	assert.Equal(t, "wpm install", q.Search)
+
+	instances := getFakePluginInstances()
+	instances[0].Metadata.Features = []MetadataFeature{{Name: MetadataFeatureQuerySelection}}
+
+	q, instance = newQuerySelectionWithPlugins("wpm install", s, instances)
+	assert.Equal(t, instances[0], instance)
+	assert.Equal(t, QueryTypeSelection, q.Type)
+	assert.Equal(t, "wpm", q.TriggerKeyword)
+	assert.Equal(t, "install", q.Search)
+	assert.Equal(t, "hello", q.Selection.Text)
+
+	// keyword without space is treated as search
+	q, instance = newQuerySelectionWithPlugins("wpm", s, instances)
+	assert.Nil(t, instance)
+	assert.Equal(t, "", q.TriggerKeyword)
+	assert.Equal(t, "wpm", q.Search)
+
+	q, instance = newQuerySelectionWithPlugins("* install", s, instances)
+	assert.Nil(t, instance)
+	assert.Equal(t, "* install", q.Search)
+}
+
+func Test_NewQuery_UnknownCommand(t *testing.T) {
+	// not reported by default
+	q, _ := newQueryInputWithPlugins("wpm instal wox", getFakePluginInstances())
+	assert.Equal(t, "", q.UnknownCommand)
+	assert.Equal(t, "instal wox", q.Search)
+
+	instances := getFakePluginInstances()
+	instances[0].Metadata.Features = []MetadataFeature{{Name: MetadataFeatureUnknownCommand}}
+	q, _ = newQueryInputWithPlugins("wpm instal wox", instances)
+	assert.Equal(t, "instal", q.UnknownCommand)
+	assert.Equal(t, "instal wox", q.Search)
+
+	closest, found := findClosestCommand("instal", instances[0].Metadata.Commands)
+	assert.True(t, found)
+	assert.Equal(t, "install", closest.Command)
+	closest, found = findClosestCommand("UNINSTAL", instances[0].Metadata.Commands)
+	assert.True(t, found)
+	assert.Equal(t, "uninstall", closest.Command)
+	_, found = findClosestCommand("frobnicate", instances[0].Metadata.Commands)
+	assert.False(t, found)
+}
+
+func Test_NewQuery_CommandAlias(t *testing.T) {
+	instances := getFakePluginInstances()
+	instances[0].Metadata.Commands[0].Aliases = []string{"i", "add"}
+
+	q, _ := newQueryInputWithPlugins("wpm i q q1", instances)
+	assert.Equal(t, "install", q.Command)
+	assert.Equal(t, "install", q.MatchedCommand.Command)
+	assert.Equal(t, "q q1", q.Search)
+
+	q, _ = newQueryInputWithPlugins("wpm add q", instances)
+	assert.Equal(t, "install", q.Command)
+	assert.Equal(t, "q", q.Search)
+
+	// aliases are matched exactly, even if prefix matching is enabled
+	instances[0].Setting.PrefixQueryCommands = true
+	q, _ = newQueryInputWithPlugins("wpm ad q", instances)
+	assert.Equal(t, "", q.Command)
+	assert.Equal(t, "ad q", q.Search)
+
+	// command wins over alias of another command
+	instances[0].Metadata.Commands[1].Aliases = []string{"install"}
+	q, _ = newQueryInputWithPlugins("wpm install q", instances)
+	assert.Equal(t, "install", q.Command)
+}
+
+func Test_NewQuery_CommandArguments(t *testing.T) {
+	// no arguments declared, no invocation
+	q, _ := newQueryInputWithPlugins("wpm install q", getFakePluginInstances())
+	assert.Nil(t, q.CommandInvocation)
+
+	instances := getFakePluginInstances()
+	instances[0].Metadata.Commands[0].Aliases = []string{"i"}
+	instances[0].Metadata.Commands[0].Arguments = []MetadataCommandArgument{
+		{Name: "name", Required: true},
+		{Name: "version", Type: MetadataCommandArgumentTypeNumber},
+		{Name: "force", Type: MetadataCommandArgumentTypeBool},
+	}
+
+	q, _ = newQueryInputWithPlugins(`wpm i "hello world" 2 yes extra`, instances)
+	assert.NotNil(t, q.CommandInvocation)
+	assert.Equal(t, "install", q.CommandInvocation.Command)
+	assert.Equal(t, "hello world", q.CommandInvocation.GetString("name"))
+	version, ok := q.CommandInvocation.GetNumber("version")
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), version)
+	assert.True(t, q.CommandInvocation.GetBool("force"))
+	assert.Equal(t, []string{"extra"}, q.CommandInvocation.Extra)
+	assert.Empty(t, q.CommandInvocation.Missing)
+	assert.Empty(t, q.CommandInvocation.Invalid)
+
+	q, _ = newQueryInputWithPlugins("wpm install ", instances)
+	assert.Equal(t, []string{"name"}, q.CommandInvocation.Missing)
+
+	q, _ = newQueryInputWithPlugins("wpm install wox latest", instances)
+	assert.Equal(t, "wox", q.CommandInvocation.GetString("name"))
+	assert.Equal(t, []string{"version"}, q.CommandInvocation.Invalid)
+	_, ok = q.CommandInvocation.GetNumber("version")
+	assert.False(t, ok)
+	assert.False(t, q.CommandInvocation.GetBool("force"))
+}
+
+func Test_ParseCommandInvocation_Rest(t *testing.T) {
+	command := MetadataCommand{
+		Command: "commit",
+		Arguments: []MetadataCommandArgument{
+			{Name: "branch", Required: true},
+			{Name: "message", Required: true, Rest: true},
+		},
+	}
+
+	invocation := parseCommandInvocation(command, `main fix "quoted" bug`)
+	assert.Equal(t, "main", invocation.GetString("branch"))
+	assert.Equal(t, `fix "quoted" bug`, invocation.GetString("message"))
+	assert.Empty(t, invocation.Extra)
+
+	invocation = parseCommandInvocation(command, "main")
+	assert.Equal(t, []string{"message"}, invocation.Missing)
+
+	// unclosed quote takes the rest
+	invocation = parseCommandInvocation(command, `"feature x`)
+	assert.Equal(t, "feature x", invocation.GetString("branch"))
+}
+
+func Test_MetadataCommand_UnmarshalJSON(t *testing.T) {
+	var commands []MetadataCommand
+	err := json.Unmarshal([]byte(`["install", {"Command": "uninstall", "Aliases": ["rm"], "Arguments": [{"Name": "name", "Required": true}]}]`), &commands)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(commands))
+	assert.Equal(t, "install", commands[0].Command)
+	assert.Equal(t, "uninstall", commands[1].Command)
+	assert.Equal(t, []string{"rm"}, commands[1].Aliases)
+	assert.Equal(t, "name", commands[1].Arguments[0].Name)
+	assert.True(t, commands[1].Arguments[0].Required)
+}
+
+func Test_SanitizeQuery(t *testing.T) {
+	q, _ := newQueryInputWithPlugins("wpm\tinstall\x00 q", getFakePluginInstances())
+	assert.Equal(t, q.RawQuery, "wpm install q")
+	assert.Equal(t, q.TriggerKeyword, "wpm")
+	assert.Equal(t, q.Command, "install")
+	assert.Equal(t, q.Search, "q")
+
+	// decomposed e + combining acute accent
+	q, _ = newQueryInputWithPlugins("cafe\u0301", getFakePluginInstances())
+	assert.Equal(t, q.Search, "caf\u00e9")
 }
@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"wox/util"
+	"wox/util/selection"
+)
+
+// max time a custom query variable resolver can take, the variable is kept as is if it's exceeded
+const queryVariableResolveTimeoutMs = 500
+
+// query variables are named "{namespace:name}", E.g. "{wox:selected_text}" or custom "{myplugin:project}".
+// Names are case-insensitive, they are lowercased before being registered or resolved
+var queryVariablePattern = regexp.MustCompile(`(?i)\{([a-z0-9_\-]+):([a-z0-9_\-]+)\}`)
+
+// QueryVariableResolver returns the value of a custom query variable, register it by API.RegisterQueryVariable.
+// It's called every time a query containing the variable is expanded (E.g. a query hotkey is pressed), it should return quickly
+type QueryVariableResolver func(ctx context.Context) (string, error)
+
+type registeredQueryVariable struct {
+	Variable QueryVariable // E.g. "{myplugin:project}"
+	Resolver QueryVariableResolver
+}
+
+// parseCustomQueryVariable validates name of a custom query variable ("namespace:name", braces are optional) and returns the variable.
+// Namespace "wox" is reserved for builtin variables, see QueryVariableSelectedText
+func parseCustomQueryVariable(name string) (QueryVariable, error) {
+	variable := strings.ToLower(name)
+	if !strings.HasPrefix(variable, "{") {
+		variable = "{" + variable + "}"
+	}
+	match := queryVariablePattern.FindStringSubmatch(variable)
+	if match == nil || match[0] != variable {
+		return "", fmt.Errorf("invalid query variable name: %s, it should be like namespace:name", name)
+	}
+	if match[1] == "wox" {
+		return "", fmt.Errorf("query variable namespace wox is reserved: %s", name)
+	}
+	return variable, nil
+}
+
+// ReplaceQueryVariable replaces builtin (see QueryVariableSelectedText) and custom query variables (see API.RegisterQueryVariable) in query
+func (m *Manager) ReplaceQueryVariable(ctx context.Context, query string) string {
+	return expandQueryVariables(ctx, query, m.findQueryVariableResolver)
+}
+
+func (m *Manager) findQueryVariableResolver(variable QueryVariable) (QueryVariableResolver, bool) {
+	switch variable {
+	case QueryVariableSelectedText:
+		return func(ctx context.Context) (string, error) {
+			selected, selectedErr := selection.GetSelected(ctx)
+			if selectedErr != nil {
+				return "", fmt.Errorf("failed to get selected text: %w", selectedErr)
+			}
+			if selected.Type != selection.SelectionTypeText {
+				return "", fmt.Errorf("selected data is not text, type: %s", selected.Type)
+			}
+			return selected.Text, nil
+		}, true
+	case QueryVariableActiveBrowserUrl:
+		return func(ctx context.Context) (string, error) {
+			return m.activeBrowserUrl, nil
+		}, true
+	}
+
+	registered, found := m.getRegisteredQueryVariable(variable)
+	if !found {
+		return nil, false
+	}
+	return func(ctx context.Context) (string, error) {
+		return resolveQueryVariableWithTimeout(ctx, registered.Resolver, time.Duration(queryVariableResolveTimeoutMs)*time.Millisecond)
+	}, true
+}
+
+// getRegisteredQueryVariable returns the custom query variable registered by an enabled plugin, see API.RegisterQueryVariable
+func (m *Manager) getRegisteredQueryVariable(variable QueryVariable) (registeredQueryVariable, bool) {
+	for _, instance := range m.getInstances() {
+		if instance.Setting.Disabled {
+			continue
+		}
+		for _, registered := range instance.QueryVariables {
+			if registered.Variable == variable {
+				return registered, true
+			}
+		}
+	}
+	return registeredQueryVariable{}, false
+}
+
+// expandQueryVariables replaces query variables in query in a single pass from left to right, each variable is resolved once
+// even if it appears several times. Resolved values are not expanded again, so a value containing a variable (E.g. selected text)
+// can't cause infinite expansion. Unknown variables and failed resolvers keep the variable as is
+func expandQueryVariables(ctx context.Context, query string, findResolver func(variable QueryVariable) (QueryVariableResolver, bool)) string {
+	type resolveResult struct {
+		value string
+		ok    bool
+	}
+	resolved := map[QueryVariable]resolveResult{}
+	return queryVariablePattern.ReplaceAllStringFunc(query, func(text string) string {
+		variable := strings.ToLower(text)
+		result, exist := resolved[variable]
+		if !exist {
+			if resolver, found := findResolver(variable); found {
+				if resolvedValue, err := resolver(ctx); err != nil {
+					util.GetLogger().Error(ctx, fmt.Sprintf("failed to resolve query variable %s: %s", variable, err.Error()))
+				} else {
+					result = resolveResult{value: resolvedValue, ok: true}
+				}
+			}
+			resolved[variable] = result
+		}
+
+		if !result.ok {
+			// keep the variable as typed
+			return text
+		}
+		return result.value
+	})
+}
+
+func resolveQueryVariableWithTimeout(ctx context.Context, resolver QueryVariableResolver, timeout time.Duration) (string, error) {
+	resolveCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type resolveResult struct {
+		value string
+		err   error
+	}
+	resultChan := make(chan resolveResult, 1)
+	util.Go(resolveCtx, "resolve query variable", func() {
+		value, err := resolver(resolveCtx)
+		resultChan <- resolveResult{value: value, err: err}
+	}, func() {
+		resultChan <- resolveResult{err: fmt.Errorf("resolver panic")}
+	})
+
+	select {
+	case result := <-resultChan:
+		return result.value, result.err
+	case <-resolveCtx.Done():
+		return "", resolveCtx.Err()
+	}
+}
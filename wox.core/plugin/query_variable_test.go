@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCustomQueryVariable(t *testing.T) {
+	variable, err := parseCustomQueryVariable("myplugin:project")
+	assert.Nil(t, err)
+	assert.Equal(t, "{myplugin:project}", variable)
+
+	variable, err = parseCustomQueryVariable("{MyPlugin:Project}")
+	assert.Nil(t, err)
+	assert.Equal(t, "{myplugin:project}", variable)
+
+	_, err = parseCustomQueryVariable("project")
+	assert.NotNil(t, err)
+	_, err = parseCustomQueryVariable("my plugin:project")
+	assert.NotNil(t, err)
+	_, err = parseCustomQueryVariable("wox:selected_text")
+	assert.NotNil(t, err)
+}
+
+func TestExpandQueryVariables(t *testing.T) {
+	calls := map[string]int{}
+	resolvers := map[QueryVariable]QueryVariableResolver{
+		"{my:project}": func(ctx context.Context) (string, error) {
+			calls["project"]++
+			return "wox", nil
+		},
+		// value containing a variable is not expanded again
+		"{my:loop}": func(ctx context.Context) (string, error) {
+			return "{my:loop} {my:project}", nil
+		},
+		"{my:broken}": func(ctx context.Context) (string, error) {
+			return "", errors.New("not available")
+		},
+	}
+	findResolver := func(variable QueryVariable) (QueryVariableResolver, bool) {
+		resolver, found := resolvers[variable]
+		return resolver, found
+	}
+
+	expanded := expandQueryVariables(context.Background(), "gh {my:project} {My:Project}", findResolver)
+	assert.Equal(t, "gh wox wox", expanded)
+	assert.Equal(t, 1, calls["project"])
+
+	assert.Equal(t, "{my:loop} {my:project}", expandQueryVariables(context.Background(), "{my:loop}", findResolver))
+	assert.Equal(t, "x {my:broken} {Other:Unknown}", expandQueryVariables(context.Background(), "x {my:broken} {Other:Unknown}", findResolver))
+}
+
+func TestResolveQueryVariableWithTimeout(t *testing.T) {
+	_, err := resolveQueryVariableWithTimeout(context.Background(), func(ctx context.Context) (string, error) {
+		time.Sleep(time.Second)
+		return "late", nil
+	}, 20*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	value, err := resolveQueryVariableWithTimeout(context.Background(), func(ctx context.Context) (string, error) {
+		return "fast", nil
+	}, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "fast", value)
+}
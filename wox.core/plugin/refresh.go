@@ -1,24 +1,45 @@
 package plugin
 
+import "time"
+
 type RefreshableResult struct {
-	Title           string
-	SubTitle        string
-	Icon            WoxImage
-	Preview         WoxPreview
-	Tails           []QueryResultTail
-	ContextData     string
-	RefreshInterval int // set to 0 if you don't want to refresh this result anymore
-	Actions         []QueryResultAction
+	Title              string
+	SubTitle           string
+	AccessibilityLabel string
+	Icon               WoxImage
+	Preview            WoxPreview
+	Tails              []QueryResultTail
+	ContextData        string
+	RefreshInterval    int // set to 0 if you don't want to refresh this result anymore
+	Actions            []QueryResultAction
+	LastUpdated        time.Time // see QueryResult.LastUpdated
+	TriggerKeyword     string    // read only, trigger keyword of the query which returned this result, see ActionContext.TriggerKeyword
 }
 
 type RefreshableResultWithResultId struct {
-	ResultId        string
-	Title           string
-	SubTitle        string
-	Icon            WoxImage
-	Preview         WoxPreview
-	Tails           []QueryResultTail
-	ContextData     string
-	RefreshInterval int
-	Actions         []QueryResultActionUI
+	ResultId           string
+	Title              string
+	SubTitle           string
+	AccessibilityLabel string
+	Icon               WoxImage
+	Preview            WoxPreview
+	Tails              []QueryResultTail
+	ContextData        string
+	RefreshInterval    int
+	Actions            []QueryResultActionUI
+	LastUpdated        int64 // unix timestamp in milliseconds, 0 if result is live
+}
+
+func toLastUpdatedTimestamp(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromLastUpdatedTimestamp(timestamp int64) time.Time {
+	if timestamp <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(timestamp)
 }
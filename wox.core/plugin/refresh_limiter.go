@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"sync"
+	"wox/util"
+)
+
+// refreshLimiter is a global token bucket shared by all refreshable results, so that a plugin returning
+// lots of results with small refresh interval won't flood itself and wox with refresh calls.
+//
+// Visible results can use all tokens, while results not visible in UI can only use the upper half of the bucket,
+// which keeps capacity reserved for the results user is actually looking at.
+type refreshLimiter struct {
+	lock             sync.Mutex
+	tokens           float64
+	lastRefillMillis int64
+}
+
+func newRefreshLimiter() *refreshLimiter {
+	// lastRefillMillis is zero, so the bucket will be full on first refresh
+	return &refreshLimiter{}
+}
+
+// allow reports whether a refresh can be executed now, maxPerSecond <= 0 means no limit
+func (l *refreshLimiter) allow(maxPerSecond int, isVisible bool) bool {
+	if maxPerSecond <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	capacity := float64(maxPerSecond)
+	now := util.GetSystemTimestamp()
+	l.tokens += float64(now-l.lastRefillMillis) * capacity / 1000
+	if l.tokens > capacity {
+		l.tokens = capacity
+	}
+	l.lastRefillMillis = now
+
+	threshold := 1.0
+	if !isVisible {
+		threshold = max(capacity/2, 1)
+	}
+	if l.tokens < threshold {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
@@ -0,0 +1,118 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/sahilm/fuzzy"
+)
+
+type builtResultMatch struct {
+	index           int
+	score           int64
+	subTitleMatched bool
+}
+
+// BuildResults matches items against query search with the shared matcher (the same one used by system plugins, honoring pinyin and
+// literal match settings), drops items which don't match and returns results sorted by score desc, with highlights of the matched text.
+// Title is matched first, subtitle is only matched if title doesn't match and scores half. All items match an empty search in their order.
+//
+// Use BuildResultsFunc to set the rest of the result (E.g. actions)
+func BuildResults[T any](ctx context.Context, query Query, items []T, texts func(item T) (title, subTitle string)) []QueryResult {
+	return BuildResultsFunc(ctx, query, items, texts, nil)
+}
+
+// BuildResultsFunc is BuildResults with decorate called for each matched item to complete its result (E.g. icon, actions),
+// it's not called for items which don't match
+func BuildResultsFunc[T any](ctx context.Context, query Query, items []T, texts func(item T) (title, subTitle string), decorate func(item T, result *QueryResult)) []QueryResult {
+	usePinYin := setting.GetSettingManager().GetWoxSetting(ctx).UsePinYin
+	matchOptions := util.GetMatchOptionsContext(ctx)
+	match := func(text string) (bool, int64) {
+		if query.IsLiteralMatch {
+			return util.IsStringMatchScoreLiteral(text, query.Search)
+		}
+		return util.IsStringMatchScoreWithOptions(text, query.Search, usePinYin, matchOptions)
+	}
+
+	// texts are kept by index instead of building results, so items which don't match allocate nothing
+	var matches []builtResultMatch
+	for index, item := range items {
+		if query.Search == "" {
+			matches = append(matches, builtResultMatch{index: index})
+			continue
+		}
+
+		title, subTitle := texts(item)
+		if isMatch, score := match(title); isMatch {
+			matches = append(matches, builtResultMatch{index: index, score: score})
+			continue
+		}
+		if subTitle == "" {
+			continue
+		}
+		if isMatch, score := match(subTitle); isMatch {
+			matches = append(matches, builtResultMatch{index: index, score: score / 2, subTitleMatched: true})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b builtResultMatch) int {
+		if a.score > b.score {
+			return -1
+		}
+		if a.score < b.score {
+			return 1
+		}
+		return 0
+	})
+
+	results := make([]QueryResult, 0, len(matches))
+	for _, matched := range matches {
+		item := items[matched.index]
+		title, subTitle := texts(item)
+		result := QueryResult{Title: title, SubTitle: subTitle, Score: matched.score}
+		if query.Search != "" {
+			if matched.subTitleMatched {
+				result.SubTitleHighlights = getMatchHighlights(subTitle, query.Search, query.IsLiteralMatch)
+			} else {
+				result.TitleHighlights = getMatchHighlights(title, query.Search, query.IsLiteralMatch)
+			}
+		}
+		if decorate != nil {
+			decorate(item, &result)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// getMatchHighlights returns ranges of text in runes matched by search, see QueryResult.TitleHighlights.
+// Texts matched by pinyin have no highlights because matched characters can't be mapped back
+func getMatchHighlights(text string, search string, isLiteral bool) [][2]int {
+	if !isLiteral {
+		if fuzzyMatches := fuzzy.Find(search, []string{text}); len(fuzzyMatches) > 0 {
+			var highlights [][2]int
+			for _, byteIndex := range fuzzyMatches[0].MatchedIndexes {
+				start := utf8.RuneCountInString(text[:byteIndex])
+				// adjacent matched characters are merged into one range
+				if last := len(highlights) - 1; last >= 0 && highlights[last][1] == start {
+					highlights[last][1]++
+					continue
+				}
+				highlights = append(highlights, [2]int{start, start + 1})
+			}
+			return highlights
+		}
+	}
+
+	lowerText := strings.ToLower(text)
+	byteIndex := strings.Index(lowerText, strings.ToLower(search))
+	if byteIndex < 0 {
+		return nil
+	}
+	start := utf8.RuneCountInString(lowerText[:byteIndex])
+	return [][2]int{{start, start + utf8.RuneCountInString(search)}}
+}
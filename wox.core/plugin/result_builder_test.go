@@ -0,0 +1,15 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMatchHighlights(t *testing.T) {
+	// adjacent matched characters are merged
+	assert.Equal(t, [][2]int{{0, 2}, {8, 9}}, getMatchHighlights("Visual Studio", "vit", false))
+	// ranges are in runes, not bytes
+	assert.Equal(t, [][2]int{{2, 4}}, getMatchHighlights("我爱摄影", "摄影", true))
+	assert.Nil(t, getMatchHighlights("Chrome", "firefox", true))
+}
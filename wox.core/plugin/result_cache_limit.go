@@ -0,0 +1,197 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"wox/setting"
+	"wox/util"
+)
+
+// rough memory cost of a cached result and of each of its actions besides their strings (struct, maps, closures)
+const resultCacheBaseSize = 512
+const resultCacheActionSize = 256
+
+// ResultCacheUsage is the memory usage of cached results of current query, for debugging
+type ResultCacheUsage struct {
+	Count         int
+	EstimatedSize int64 // bytes
+	MaxSize       int64 // bytes, negative means no limit
+	EvictedCount  int64 // results evicted since Wox started
+}
+
+type resultCacheEvictor struct {
+	lock         sync.Mutex
+	evictedCount atomic.Int64
+	queryKey     atomic.Value // key of the latest dispatched query, its results may not be sent to UI yet, see getInflightQueryKey
+}
+
+// GetResultCacheUsage returns the current usage of result cache, see setting.WoxSetting.MaxResultCacheSize
+func (m *Manager) GetResultCacheUsage(ctx context.Context) ResultCacheUsage {
+	usage := ResultCacheUsage{
+		MaxSize:      m.getMaxResultCacheSize(ctx),
+		EvictedCount: m.resultCacheEvict.evictedCount.Load(),
+	}
+	m.resultCache.Range(func(_ string, resultCache *QueryResultCache) bool {
+		usage.Count++
+		usage.EstimatedSize += estimateResultCacheSize(resultCache)
+		return true
+	})
+	return usage
+}
+
+// loadResultCache loads result cache for UI operations and marks it as recently used
+func (m *Manager) loadResultCache(resultId string) (*QueryResultCache, bool) {
+	resultCache, found := m.resultCache.Load(resultId)
+	if found {
+		resultCache.LastAccessTimestamp = util.GetSystemTimestamp()
+	}
+	return resultCache, found
+}
+
+func (m *Manager) storeResultCache(ctx context.Context, resultCache *QueryResultCache) {
+	resultCache.LastAccessTimestamp = util.GetSystemTimestamp()
+	// a later batch of the query may emit the same result again, see resultBatchMerger
+	if previous, exist := m.resultCache.Load(resultCache.ResultId); exist && previous != resultCache {
+		mergeResultCache(previous, resultCache)
+		m.resultCacheSize.Add(-estimateResultCacheSize(previous))
+	}
+	m.resultCache.Store(resultCache.ResultId, resultCache)
+
+	maxSize := m.getMaxResultCacheSize(ctx)
+	if m.resultCacheSize.Add(estimateResultCacheSize(resultCache)) > maxSize && maxSize > 0 {
+		m.evictResultCache(ctx, maxSize)
+	}
+}
+
+// evictResultCache removes least recently used results until estimated size is below maxSize.
+// Results of the current query are never evicted, they may be stored before UI received them and UI will act on them.
+// Results visible in UI (expanded children and focused preview) are never evicted either
+func (m *Manager) evictResultCache(ctx context.Context, maxSize int64) {
+	// one eviction at a time is enough, results stored meanwhile are counted by the next one
+	if !m.resultCacheEvict.lock.TryLock() {
+		return
+	}
+	defer m.resultCacheEvict.lock.Unlock()
+
+	visibleIds := m.getVisibleResultIds()
+	currentQueryKey, _ := m.resultCacheEvict.queryKey.Load().(string)
+	var totalSize int64
+	var candidates []*QueryResultCache
+	m.resultCache.Range(func(resultId string, resultCache *QueryResultCache) bool {
+		totalSize += estimateResultCacheSize(resultCache)
+		if !visibleIds[resultId] && (currentQueryKey == "" || getInflightQueryKey(resultCache.Query) != currentQueryKey) {
+			candidates = append(candidates, resultCache)
+		}
+		return true
+	})
+	// size is estimated on store only, caches may have grown since (E.g. computed action previews)
+	m.resultCacheSize.Store(totalSize)
+	if totalSize <= maxSize {
+		return
+	}
+
+	slices.SortFunc(candidates, func(a, b *QueryResultCache) int {
+		return int(a.LastAccessTimestamp - b.LastAccessTimestamp)
+	})
+	var evicted int64
+	for _, resultCache := range candidates {
+		if totalSize <= maxSize {
+			break
+		}
+		size := estimateResultCacheSize(resultCache)
+		m.resultCache.Delete(resultCache.ResultId)
+		releaseResultCache(resultCache)
+		totalSize -= size
+		evicted++
+	}
+	m.resultCacheSize.Store(totalSize)
+	m.resultCacheEvict.evictedCount.Add(evicted)
+
+	logger.Info(ctx, fmt.Sprintf("result cache exceeds %d bytes, evicted %d results, estimated size now: %d bytes", maxSize, evicted, totalSize))
+}
+
+func (m *Manager) getVisibleResultIds() map[string]bool {
+	visibleIds := map[string]bool{}
+
+	m.shownResults.lock.Lock()
+	for _, result := range m.shownResults.results {
+		visibleIds[result.Id] = true
+	}
+	m.shownResults.lock.Unlock()
+
+	m.expandedResults.Range(func(parentId string, childIds []string) bool {
+		visibleIds[parentId] = true
+		for _, childId := range childIds {
+			visibleIds[childId] = true
+		}
+		return true
+	})
+
+	m.previewUpdater.lock.Lock()
+	if m.previewUpdater.resultId != "" {
+		visibleIds[m.previewUpdater.resultId] = true
+	}
+	m.previewUpdater.lock.Unlock()
+
+	return visibleIds
+}
+
+func (m *Manager) getMaxResultCacheSize(ctx context.Context) int64 {
+	maxSize := setting.GetSettingManager().GetWoxSetting(ctx).MaxResultCacheSize
+	if maxSize < 0 {
+		return -1
+	}
+	return int64(maxSize) * 1024 * 1024
+}
+
+// releaseResultCache drops closures and previews of an evicted result, so memory they reference can be garbage collected
+// even if the evicted cache is still referenced somewhere (E.g. a running refresh)
+func releaseResultCache(resultCache *QueryResultCache) {
+	resultCache.Actions.Clear()
+	resultCache.ActionPreviews.Clear()
+	if resultCache.TabPreviews != nil {
+		resultCache.TabPreviews.Clear()
+	}
+	resultCache.PreviewTabs = nil
+	resultCache.Refresh = nil
+	resultCache.Expand = nil
+	resultCache.setPreview(WoxPreview{})
+	resultCache.ContextData = ""
+}
+
+func estimateResultCacheSize(resultCache *QueryResultCache) int64 {
+	size := int64(resultCacheBaseSize + len(resultCache.ResultId) + len(resultCache.ResultTitle) + len(resultCache.ResultSubTitle) + len(resultCache.ContextData))
+	size += estimatePreviewSize(resultCache.getPreview())
+	if icon := resultCache.ResolvedIcon.Load(); icon != nil {
+		size += int64(len(icon.ImageData))
+	}
+	resultCache.Actions.Range(func(_ string, action QueryResultAction) bool {
+		size += int64(resultCacheActionSize + len(action.Id) + len(action.Name) + len(action.Hotkey))
+		return true
+	})
+	resultCache.ActionPreviews.Range(func(_ string, preview WoxPreview) bool {
+		size += estimatePreviewSize(preview)
+		return true
+	})
+	for _, tab := range resultCache.PreviewTabs {
+		size += int64(len(tab.Id)+len(tab.Name)) + estimatePreviewSize(tab.Preview)
+	}
+	if resultCache.TabPreviews != nil {
+		resultCache.TabPreviews.Range(func(_ string, preview WoxPreview) bool {
+			size += estimatePreviewSize(preview)
+			return true
+		})
+	}
+	return size
+}
+
+func estimatePreviewSize(preview WoxPreview) int64 {
+	size := int64(len(preview.PreviewData))
+	for key, value := range preview.PreviewProperties {
+		size += int64(len(key) + len(value))
+	}
+	return size
+}
@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvictionTestResultCache(resultId string, query Query) *QueryResultCache {
+	return &QueryResultCache{
+		ResultId:       resultId,
+		Query:          query,
+		Actions:        util.NewHashMap[string, QueryResultAction](),
+		ActionPreviews: util.NewHashMap[string, WoxPreview](),
+	}
+}
+
+func TestEvictResultCache_KeepsResultsOfCurrentQuery(t *testing.T) {
+	logger = util.GetLogger()
+	m := &Manager{
+		resultCache:     util.NewHashMap[string, *QueryResultCache](),
+		expandedResults: util.NewHashMap[string, []string](),
+	}
+	current := Query{Type: QueryTypeInput, RawQuery: "wpm install"}
+	m.resultCacheEvict.queryKey.Store(getInflightQueryKey(current))
+
+	// results of current query are not sent to UI yet, so none of them is visible
+	for _, resultId := range []string{"current-1", "current-2"} {
+		m.resultCache.Store(resultId, newEvictionTestResultCache(resultId, current))
+	}
+	m.resultCache.Store("stale", newEvictionTestResultCache("stale", Query{Type: QueryTypeInput, RawQuery: "wpm"}))
+
+	m.evictResultCache(context.Background(), 1)
+
+	assert.Equal(t, int64(1), m.resultCacheEvict.evictedCount.Load())
+	_, staleFound := m.resultCache.Load("stale")
+	assert.False(t, staleFound)
+	for _, resultId := range []string{"current-1", "current-2"} {
+		_, found := m.resultCache.Load(resultId)
+		assert.True(t, found, resultId)
+	}
+}
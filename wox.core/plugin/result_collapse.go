@@ -0,0 +1,141 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"wox/share"
+)
+
+// collapseGroupState remembers the collapse groups (see QueryResult.CollapseGroup) user expanded in current query session.
+// It's reset when the query session ends, so every time Wox is shown the groups start collapsed
+type collapseGroupState struct {
+	lock     sync.Mutex
+	expanded map[string]bool // by plugin id and collapse group, see getCollapseGroupKey
+}
+
+func getCollapseGroupKey(pluginId string, group string) string {
+	return pluginId + "/" + group
+}
+
+func (s *collapseGroupState) isExpanded(key string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.expanded[key]
+}
+
+func (s *collapseGroupState) setExpanded(key string, expanded bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.expanded == nil {
+		s.expanded = map[string]bool{}
+	}
+	if expanded {
+		s.expanded[key] = true
+	} else {
+		delete(s.expanded, key)
+	}
+}
+
+func (s *collapseGroupState) reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expanded = nil
+}
+
+// collapseResults shows only the best result of each collapse group of a plugin (see QueryResult.CollapseGroup) and adds an action to it
+// which shows the alternatives. Expanding or collapsing a group reruns current query, the state is kept for the rest of the query session,
+// so the group stays expanded while user keeps typing. It must be called before results are prepared, hidden alternatives are not cached
+func (m *Manager) collapseResults(ctx context.Context, pluginInstance *Instance, query Query, results []QueryResult) []QueryResult {
+	isExpanded := func(group string) bool {
+		return m.alternativeGroups.isExpanded(getCollapseGroupKey(pluginInstance.Metadata.Id, group))
+	}
+	results, alternativeCounts := collapseResultGroups(results, isExpanded)
+	if len(alternativeCounts) == 0 {
+		return results
+	}
+
+	for i := range results {
+		group := results[i].CollapseGroup
+		count, isBest := alternativeCounts[group]
+		if !isBest {
+			continue
+		}
+		// only the first result of a group is the best one, alternatives follow it when the group is expanded
+		delete(alternativeCounts, group)
+
+		expanded := isExpanded(group)
+		actionName := "i18n:plugin_collapse_group_expand"
+		actionIcon := PreviewIcon
+		if expanded {
+			actionName = "i18n:plugin_collapse_group_collapse"
+			actionIcon = HideIcon
+		}
+		results[i].Actions = append(slices.Clone(results[i].Actions), QueryResultAction{
+			Name:                   actionName,
+			Icon:                   actionIcon,
+			PreventHideAfterAction: true,
+			Action: func(ctx context.Context, actionContext ActionContext) {
+				logger.Debug(ctx, fmt.Sprintf("<%s> collapse group(%s) expanded: %t, %d alternatives", pluginInstance.Metadata.Name, group, !expanded, count))
+				m.alternativeGroups.setExpanded(getCollapseGroupKey(pluginInstance.Metadata.Id, group), !expanded)
+				m.GetUI().ChangeQuery(ctx, share.PlainQuery{
+					QueryType:      query.Type,
+					QueryText:      query.RawQuery,
+					QuerySelection: query.Selection,
+				})
+			},
+		})
+	}
+
+	return results
+}
+
+// collapseResultGroups returns results with alternatives of collapsed groups removed, the best result of a group is the one with highest
+// score (the first one on tie). Alternatives of expanded groups are moved right after the best result and get its score, so they stay
+// next to it after sorting. Results without collapse group keep their order. alternativeCounts is the number of alternatives by group,
+// groups with a single result are not included
+func collapseResultGroups(results []QueryResult, isExpanded func(group string) bool) (collapsed []QueryResult, alternativeCounts map[string]int) {
+	bestIndexes := map[string]int{}
+	alternativeCounts = map[string]int{}
+	for i, result := range results {
+		if result.CollapseGroup == "" {
+			continue
+		}
+		bestIndex, exist := bestIndexes[result.CollapseGroup]
+		if !exist {
+			bestIndexes[result.CollapseGroup] = i
+			continue
+		}
+		alternativeCounts[result.CollapseGroup]++
+		if result.Score > results[bestIndex].Score {
+			bestIndexes[result.CollapseGroup] = i
+		}
+	}
+	if len(alternativeCounts) == 0 {
+		return results, nil
+	}
+
+	for i, result := range results {
+		group := result.CollapseGroup
+		if group == "" || alternativeCounts[group] == 0 {
+			collapsed = append(collapsed, result)
+			continue
+		}
+		if bestIndexes[group] != i {
+			continue
+		}
+
+		collapsed = append(collapsed, result)
+		if !isExpanded(group) {
+			continue
+		}
+		for j, alternative := range results {
+			if j != i && alternative.CollapseGroup == group {
+				alternative.Score = result.Score
+				collapsed = append(collapsed, alternative)
+			}
+		}
+	}
+	return collapsed, alternativeCounts
+}
@@ -0,0 +1,38 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollapseResultGroups(t *testing.T) {
+	results := []QueryResult{
+		{Title: "Visual Studio Code", Score: 10, CollapseGroup: "vscode"},
+		{Title: "Calculator", Score: 50},
+		{Title: "code", Score: 30, CollapseGroup: "vscode"},
+		{Title: "VS Code", Score: 30, CollapseGroup: "vscode"},
+		{Title: "Terminal", Score: 5, CollapseGroup: "terminal"},
+	}
+	titles := func(results []QueryResult) []string {
+		return lo.Map(results, func(item QueryResult, _ int) string { return item.Title })
+	}
+
+	collapsed, counts := collapseResultGroups(results, func(group string) bool { return false })
+	// best of a group is the one with highest score, first one on tie
+	assert.Equal(t, []string{"Calculator", "code", "Terminal"}, titles(collapsed))
+	assert.Equal(t, map[string]int{"vscode": 2}, counts)
+
+	expanded, _ := collapseResultGroups(results, func(group string) bool { return group == "vscode" })
+	assert.Equal(t, []string{"Calculator", "code", "Visual Studio Code", "VS Code", "Terminal"}, titles(expanded))
+	// alternatives get score of the best result so they stay next to it
+	assert.Equal(t, []int64{50, 30, 30, 30, 5}, lo.Map(expanded, func(item QueryResult, _ int) int64 { return item.Score }))
+}
+
+func TestCollapseResultGroups_NoGroup(t *testing.T) {
+	results := []QueryResult{{Title: "a"}, {Title: "b", CollapseGroup: "single"}}
+	collapsed, counts := collapseResultGroups(results, func(group string) bool { return false })
+	assert.Equal(t, results, collapsed)
+	assert.Empty(t, counts)
+}
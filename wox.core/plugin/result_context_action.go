@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// polishContextActions prepares QueryResult.ContextActions and stores them in result cache, it must be called after primary actions are stored.
+//
+// Context actions are executed by id through the same path as primary actions, so they get ids distinct from primary actions, except:
+//   - a context action with the same id as a primary action refers to that action, it's listed in both places and executing it
+//     runs the primary action, Action of the context action is ignored
+//   - context actions with duplicated ids are dropped, the first one wins
+//
+// Context actions are only reachable from the context menu: they are never default and have no hotkey or activation modifier.
+// Global actions (see WoxSetting.GlobalActions) are not added to context actions
+func (m *Manager) polishContextActions(ctx context.Context, pluginInstance *Instance, result *QueryResult, resultCache *QueryResultCache) {
+	var contextActions []QueryResultAction
+	var storedActions []QueryResultAction
+	for _, action := range result.ContextActions {
+		if action.Id != "" {
+			if primaryAction, isPrimary := lo.Find(result.Actions, func(item QueryResultAction) bool { return item.Id == action.Id }); isPrimary {
+				contextActions = append(contextActions, primaryAction)
+				continue
+			}
+			if lo.ContainsBy(contextActions, func(item QueryResultAction) bool { return item.Id == action.Id }) {
+				logger.Warn(ctx, fmt.Sprintf("<%s> result(%s) has duplicated context action id: %s", pluginInstance.Metadata.Name, result.Title, action.Id))
+				continue
+			}
+		}
+		if action.Action == nil {
+			logger.Warn(ctx, fmt.Sprintf("<%s> result(%s) context action(%s) has no action function", pluginInstance.Metadata.Name, result.Title, action.Name))
+			continue
+		}
+
+		if action.Id == "" {
+			action.Id = uuid.NewString()
+		}
+		if action.Icon.IsEmpty() {
+			action.Icon = DefaultActionIcon
+		}
+		action.IsDefault = false
+		action.Hotkey = ""
+		action.ActivationModifier = ""
+		action = m.translateAction(ctx, pluginInstance, action)
+
+		contextActions = append(contextActions, action)
+		storedActions = append(storedActions, action)
+	}
+
+	result.ContextActions = contextActions
+	resultCache.ContextActions = storedActions
+	for _, action := range storedActions {
+		resultCache.Actions.Store(action.Id, action)
+	}
+}
+
+// restoreContextActions stores context actions again after actions of result cache are replaced by refresh,
+// context actions whose id is now used by a primary action are dropped
+func restoreContextActions(resultCache *QueryResultCache) {
+	resultCache.ContextActions = lo.Filter(resultCache.ContextActions, func(action QueryResultAction, _ int) bool {
+		return !resultCache.Actions.Exist(action.Id)
+	})
+	for _, action := range resultCache.ContextActions {
+		resultCache.Actions.Store(action.Id, action)
+	}
+}
@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueryResultCountHint is sent to UI alongside the first result batch of a query, see Manager.EstimateResultCount.
+// Another hint with EstimatedCount 0 is sent when the query is done, UI then sizes the list by the actual count
+type QueryResultCountHint struct {
+	QueryId        string
+	EstimatedCount int
+}
+
+// EstimateResultCount sums up estimated result counts declared by plugins (see MetadataFeatureResultCountHint) which will handle the query.
+// Plugins without the hint are not counted, so 0 means unknown.
+//
+// It's only a hint for UI to reserve space and avoid layout thrash: the actual count may be less or more than the estimate,
+// UI should grow the list when more results arrive and shrink it to the actual count once the query is done.
+func (m *Manager) EstimateResultCount(ctx context.Context, query Query) int {
+	var estimatedCount int
+	for _, pluginInstance := range m.getInstances() {
+		if !pluginInstance.Metadata.IsSupportFeature(MetadataFeatureResultCountHint) {
+			continue
+		}
+		if !m.canOperateQuery(ctx, pluginInstance, query) {
+			continue
+		}
+
+		params, err := pluginInstance.Metadata.GetFeatureParamsForResultCountHint()
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("[%s] %s, ignore result count hint", pluginInstance.Metadata.Name, err))
+			continue
+		}
+		estimatedCount += params.EstimatedCount
+	}
+
+	return estimatedCount
+}
@@ -0,0 +1,55 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExpandResult returns the child results of an expandable result (see QueryResult.Children and QueryResult.OnExpand), UI calls it when user expands the result.
+// Children are prepared like normal results (default actions, translation, score etc.), so they can be executed, refreshed and expanded by their own ids.
+// Expanding an already expanded result collapses it first, so OnExpand always produces fresh children.
+func (m *Manager) ExpandResult(ctx context.Context, resultId string) ([]QueryResultUI, error) {
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return nil, fmt.Errorf("result cache not found for result id (expand): %s", resultId)
+	}
+	if resultCache.Expand == nil {
+		return nil, fmt.Errorf("result is not expandable: %s", resultCache.ResultTitle)
+	}
+
+	m.CollapseResult(ctx, resultId)
+
+	pluginInstance := resultCache.PluginInstance
+	var childIds []string
+	var children []QueryResultUI
+	for _, child := range resultCache.Expand(ctx) {
+		child = m.prepareQueryResult(ctx, pluginInstance, resultCache.Query, child)
+		if childCache, exist := m.resultCache.Load(child.Id); exist {
+			childCache.ParentId = resultId
+		}
+
+		childUI := child.ToUI()
+		childUI.ParentId = resultId
+		children = append(children, childUI)
+		childIds = append(childIds, child.Id)
+	}
+	m.expandedResults.Store(resultId, childIds)
+
+	logger.Debug(ctx, fmt.Sprintf("<%s> result(%s) expanded, %d children", pluginInstance.Metadata.Name, resultCache.ResultTitle, len(children)))
+	return children, nil
+}
+
+// CollapseResult removes children (and their expanded children) of an expanded result from result cache.
+// After collapsing, actions and refreshes of the removed children are rejected, so UI should stop refreshing them.
+func (m *Manager) CollapseResult(ctx context.Context, resultId string) {
+	childIds, found := m.expandedResults.Load(resultId)
+	if !found {
+		return
+	}
+	m.expandedResults.Delete(resultId)
+
+	for _, childId := range childIds {
+		m.CollapseResult(ctx, childId)
+		m.resultCache.Delete(childId)
+	}
+}
@@ -0,0 +1,152 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+// ResultExportSchemaVersion is the version of the result export format, see ResultExport.
+// Increase it when the format changes incompatibly, so external consumers can adapt
+const ResultExportSchemaVersion = 1
+
+// ResultExport is query results in a documented and versioned json format (see docs/result_export.md), for bridging to other launchers and scripts.
+// Unlike QueryResultUI, json field names are fixed by tags and don't follow renames of internal structs
+type ResultExport struct {
+	SchemaVersion int              `json:"schemaVersion"`
+	Query         string           `json:"query"`
+	Results       []ExportedResult `json:"results"`
+}
+
+type ExportedResult struct {
+	Id       string           `json:"id"`
+	Title    string           `json:"title"`
+	SubTitle string           `json:"subTitle"`
+	Icon     ExportedImage    `json:"icon"`
+	Score    int64            `json:"score"`
+	Group    string           `json:"group,omitempty"`
+	PluginId string           `json:"pluginId,omitempty"`
+	Preview  *ExportedPreview `json:"preview,omitempty"` // nil if result has no preview
+	Actions  []ExportedAction `json:"actions"`
+}
+
+type ExportedImage struct {
+	Type string `json:"type"` // same as WoxImageType
+	Data string `json:"data"`
+}
+
+type ExportedPreview struct {
+	Type       string            `json:"type"` // same as WoxPreviewType
+	Data       string            `json:"data"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type ExportedAction struct {
+	Id        string        `json:"id"`
+	Name      string        `json:"name"`
+	Icon      ExportedImage `json:"icon"`
+	IsDefault bool          `json:"isDefault"`
+	Hotkey    string        `json:"hotkey,omitempty"`
+}
+
+// ExportResults converts results of query (E.g. returned by QuerySync) to ResultExport.
+// Previews cached by Wox are resolved, so exported results don't depend on the /preview endpoint
+func (m *Manager) ExportResults(ctx context.Context, query string, results []QueryResultUI) ResultExport {
+	return newResultExport(query, results, func(result QueryResultUI) WoxPreview {
+		if result.Preview.PreviewType != WoxPreviewTypeRemote {
+			return result.Preview
+		}
+		preview, err := m.GetResultPreview(ctx, result.Id)
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to resolve preview of result %s for export: %s", result.Id, err.Error()))
+			return result.Preview
+		}
+		return preview
+	})
+}
+
+func newResultExport(query string, results []QueryResultUI, getPreview func(result QueryResultUI) WoxPreview) ResultExport {
+	return ResultExport{
+		SchemaVersion: ResultExportSchemaVersion,
+		Query:         query,
+		Results: lo.Map(results, func(result QueryResultUI, _ int) ExportedResult {
+			exported := ExportedResult{
+				Id:       result.Id,
+				Title:    result.Title,
+				SubTitle: result.SubTitle,
+				Icon:     ExportedImage{Type: result.Icon.ImageType, Data: result.Icon.ImageData},
+				Score:    result.Score,
+				Group:    result.Group,
+				PluginId: result.PluginId,
+				Actions: lo.Map(result.Actions, func(action QueryResultActionUI, _ int) ExportedAction {
+					return ExportedAction{
+						Id:        action.Id,
+						Name:      action.Name,
+						Icon:      ExportedImage{Type: action.Icon.ImageType, Data: action.Icon.ImageData},
+						IsDefault: action.IsDefault,
+						Hotkey:    action.Hotkey,
+					}
+				}),
+			}
+			if preview := getPreview(result); !preview.IsEmpty() {
+				exported.Preview = &ExportedPreview{
+					Type:       preview.PreviewType,
+					Data:       preview.PreviewData,
+					Properties: preview.PreviewProperties,
+				}
+			}
+			return exported
+		}),
+	}
+}
+
+// ParseResultExport parses json produced from ResultExport. Exports of a newer schema version are rejected,
+// because their fields may have a different meaning
+func ParseResultExport(data []byte) (ResultExport, error) {
+	var export ResultExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return ResultExport{}, fmt.Errorf("failed to parse result export: %w", err)
+	}
+	if export.SchemaVersion <= 0 {
+		return ResultExport{}, fmt.Errorf("result export has no schema version")
+	}
+	if export.SchemaVersion > ResultExportSchemaVersion {
+		return ResultExport{}, fmt.Errorf("unsupported result export schema version %d, max supported version is %d", export.SchemaVersion, ResultExportSchemaVersion)
+	}
+
+	return export, nil
+}
+
+// ToQueryResultsUI converts exported results back, previews are inlined instead of loaded from Wox
+func (e ResultExport) ToQueryResultsUI() []QueryResultUI {
+	return lo.Map(e.Results, func(exported ExportedResult, _ int) QueryResultUI {
+		result := QueryResultUI{
+			Id:       exported.Id,
+			Title:    exported.Title,
+			SubTitle: exported.SubTitle,
+			Icon:     WoxImage{ImageType: exported.Icon.Type, ImageData: exported.Icon.Data},
+			Score:    exported.Score,
+			Group:    exported.Group,
+			PluginId: exported.PluginId,
+			Actions: lo.Map(exported.Actions, func(action ExportedAction, _ int) QueryResultActionUI {
+				return QueryResultActionUI{
+					Id:        action.Id,
+					Name:      action.Name,
+					Icon:      WoxImage{ImageType: action.Icon.Type, ImageData: action.Icon.Data},
+					IsDefault: action.IsDefault,
+					Hotkey:    action.Hotkey,
+				}
+			}),
+		}
+		if exported.Preview != nil {
+			result.Preview = WoxPreview{
+				PreviewType:       exported.Preview.Type,
+				PreviewData:       exported.Preview.Data,
+				PreviewProperties: exported.Preview.Properties,
+			}
+		}
+		return result
+	})
+}
@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultExport_RoundTrip(t *testing.T) {
+	cachedPreview := WoxPreview{PreviewType: WoxPreviewTypeMarkdown, PreviewData: "# readme", PreviewProperties: map[string]string{"Size": "1 KB"}}
+	results := []QueryResultUI{
+		{
+			Id:       "r1",
+			Title:    "readme.md",
+			SubTitle: "/tmp/readme.md",
+			Icon:     WoxImage{ImageType: WoxImageTypeEmoji, ImageData: "📄"},
+			Score:    100,
+			Group:    "Files",
+			PluginId: "file-plugin",
+			Preview:  WoxPreview{PreviewType: WoxPreviewTypeRemote, PreviewData: "/preview?id=r1"},
+			Actions: []QueryResultActionUI{
+				{Id: "open", Name: "Open", IsDefault: true},
+				{Id: "copy", Name: "Copy path", Hotkey: "cmd+c", IsBatch: true},
+			},
+		},
+		{Id: "r2", Title: "no preview"},
+	}
+
+	export := newResultExport("f readme", results, func(result QueryResultUI) WoxPreview {
+		if result.Preview.PreviewType == WoxPreviewTypeRemote {
+			return cachedPreview
+		}
+		return result.Preview
+	})
+	assert.Equal(t, ResultExportSchemaVersion, export.SchemaVersion)
+	assert.Equal(t, "f readme", export.Query)
+	assert.Equal(t, WoxPreviewTypeMarkdown, export.Results[0].Preview.Type)
+	assert.Nil(t, export.Results[1].Preview)
+
+	data, err := json.Marshal(export)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"schemaVersion":1`)
+	assert.Contains(t, string(data), `"isDefault":true`)
+	assert.NotContains(t, string(data), `"preview":null`)
+
+	parsed, parseErr := ParseResultExport(data)
+	assert.Nil(t, parseErr)
+	assert.Equal(t, export, parsed)
+
+	converted := parsed.ToQueryResultsUI()
+	assert.Len(t, converted, 2)
+	assert.Equal(t, cachedPreview, converted[0].Preview)
+	assert.Equal(t, results[0].Icon, converted[0].Icon)
+	assert.Equal(t, "cmd+c", converted[0].Actions[1].Hotkey)
+	assert.True(t, converted[0].Actions[0].IsDefault)
+	assert.True(t, converted[1].Preview.IsEmpty())
+}
+
+func TestParseResultExport_SchemaVersion(t *testing.T) {
+	_, err := ParseResultExport([]byte(`{"query":"q","results":[]}`))
+	assert.NotNil(t, err)
+
+	_, err = ParseResultExport([]byte(fmt.Sprintf(`{"schemaVersion":%d,"results":[]}`, ResultExportSchemaVersion+1)))
+	assert.NotNil(t, err)
+
+	_, err = ParseResultExport([]byte(`{"schemaVersion":1,"results":`))
+	assert.NotNil(t, err)
+
+	export, err := ParseResultExport([]byte(`{"schemaVersion":1,"query":"q","results":[{"id":"r1","title":"t","actions":[]}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "r1", export.Results[0].Id)
+}
@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wox/util"
+)
+
+const lazyIconTimeout = 5 * time.Second
+
+// GetResultIcon computes the icon of a result with QueryResult.OnIcon, UI calls it when the result is about to be rendered.
+// Computed icon is cached in result cache, so scrolling back to the result returns it immediately.
+// ctx should be cancelled by caller when the result scrolls out of view before the icon resolves,
+// the computation is abandoned and not cached, UI keeps the placeholder and asks again next time the result is rendered.
+func (m *Manager) GetResultIcon(ctx context.Context, resultId string) (WoxImage, error) {
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return WoxImage{}, fmt.Errorf("result cache not found for result id (get icon): %s", resultId)
+	}
+	if resultCache.LazyIcon == nil {
+		return WoxImage{}, fmt.Errorf("result has no lazy icon: %s", resultCache.ResultTitle)
+	}
+	if icon := resultCache.ResolvedIcon.Load(); icon != nil {
+		return *icon, nil
+	}
+
+	iconCtx, cancel := context.WithTimeout(ctx, lazyIconTimeout)
+	defer cancel()
+
+	pluginInstance := resultCache.PluginInstance
+	iconChan := make(chan WoxImage, 1)
+	util.Go(iconCtx, fmt.Sprintf("[%s] result(%s) icon", pluginInstance.Metadata.Name, resultCache.ResultTitle), func() {
+		iconChan <- resultCache.LazyIcon(iconCtx)
+	})
+
+	select {
+	case icon := <-iconChan:
+		if icon.IsEmpty() {
+			return WoxImage{}, fmt.Errorf("lazy icon of result(%s) is empty", resultCache.ResultTitle)
+		}
+		icon = ConvertIcon(ctx, icon, pluginInstance.PluginDirectory)
+		resultCache.ResolvedIcon.Store(&icon)
+		return icon, nil
+	case <-iconCtx.Done():
+		return WoxImage{}, fmt.Errorf("icon of result(%s) is cancelled: %w", resultCache.ResultTitle, iconCtx.Err())
+	}
+}
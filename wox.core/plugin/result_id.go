@@ -0,0 +1,31 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// newStableResultId derives the id of a result which has no explicit id from plugin id, title and context data,
+// so the "same" result keeps its id across keystrokes and queries. UI keeps focus on it, and frecency, pinning and favorites can recognize it.
+// Subtitle is not part of the id because it often carries volatile info (E.g. "updated 5m ago").
+//
+// If a plugin returns several results with the same title and context data in one query, they get an occurrence number in the order
+// they are returned, so their ids are still stable as long as the plugin returns them in the same order.
+// Plugins that can identify their results (E.g. by database id) should set QueryResult.Id explicitly.
+func (m *Manager) newStableResultId(pluginId string, title string, contextData string) string {
+	for occurrence := 0; ; occurrence++ {
+		id := stableResultId(pluginId, title, contextData, occurrence)
+		if !m.resultCache.Exist(id) {
+			return id
+		}
+	}
+}
+
+func stableResultId(pluginId string, title string, contextData string, occurrence int) string {
+	name := fmt.Sprintf("%s|%s|%s", pluginId, title, contextData)
+	if occurrence > 0 {
+		name = fmt.Sprintf("%s|%d", name, occurrence)
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()
+}
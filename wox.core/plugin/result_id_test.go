@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStableResultId(t *testing.T) {
+	m := &Manager{resultCache: util.NewHashMap[string, *QueryResultCache]()}
+	store := func(resultId string) {
+		m.resultCache.Store(resultId, newEvictionTestResultCache(resultId, Query{Type: QueryTypeInput, RawQuery: "wpm"}))
+	}
+
+	first := m.newStableResultId("wpm", "Install", "wox-plugin-a")
+	assert.Equal(t, first, m.newStableResultId("wpm", "Install", "wox-plugin-a"))
+	assert.NotEqual(t, first, m.newStableResultId("wpm", "Install", "wox-plugin-b"))
+	assert.NotEqual(t, first, m.newStableResultId("other", "Install", "wox-plugin-a"))
+
+	// the same title and context data in one query get an occurrence number
+	store(first)
+	second := m.newStableResultId("wpm", "Install", "wox-plugin-a")
+	assert.NotEqual(t, first, second)
+	store(second)
+	third := m.newStableResultId("wpm", "Install", "wox-plugin-a")
+	assert.NotContains(t, []string{first, second}, third)
+
+	// next query returns the same results in the same order, they get the same ids
+	m.resultCache.Clear()
+	for _, expected := range []string{first, second, third} {
+		resultId := m.newStableResultId("wpm", "Install", "wox-plugin-a")
+		assert.Equal(t, expected, resultId)
+		store(resultId)
+	}
+}
@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"wox/i18n"
+	"wox/setting"
+
+	"github.com/samber/lo"
+)
+
+// shownResults records results sent to UI for the current query, so a result can be invoked by its position (E.g. Cmd+1..9)
+type shownResults struct {
+	lock    sync.Mutex
+	queryId string
+	results []QueryResultUI
+
+	firstShownIds map[string]bool // ids of results already shown in current query, see QueryResult.OnShown
+}
+
+// record appends results of queryId and returns the ones shown for the first time in this query,
+// results are sent again when they are refreshed or updated
+func (s *shownResults) record(queryId string, results []QueryResultUI) []QueryResultUI {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.queryId != queryId || s.firstShownIds == nil {
+		s.queryId = queryId
+		s.results = nil
+		s.firstShownIds = map[string]bool{}
+	}
+	s.results = append(s.results, results...)
+
+	return lo.Filter(results, func(result QueryResultUI, _ int) bool {
+		if s.firstShownIds[result.Id] {
+			return false
+		}
+		s.firstShownIds[result.Id] = true
+		return true
+	})
+}
+
+// RecordShownResults must be called with every batch sent to UI, batches of a new query id replace the old ones
+func (m *Manager) RecordShownResults(ctx context.Context, queryId string, results []QueryResultUI) {
+	firstShown := m.shownResults.record(queryId, results)
+
+	m.trackResultsShown(ctx, queryId, results)
+	m.notifyResultsShown(ctx, firstShown)
+}
+
+// GetShownResultAtIndex returns the result at the given 1-based position of the sorted result list of the query.
+// Returns error if query is not the current one or there are fewer results than index.
+func (m *Manager) GetShownResultAtIndex(ctx context.Context, queryId string, index int) (QueryResultUI, error) {
+	m.shownResults.lock.Lock()
+	defer m.shownResults.lock.Unlock()
+
+	if m.shownResults.queryId != queryId {
+		return QueryResultUI{}, fmt.Errorf("query is not current query: %s", queryId)
+	}
+
+	sorted := sortQueryResultsUI(m.shownResults.results, setting.GetSettingManager().GetWoxSetting(ctx).LangCode)
+	// results of collapsed groups are hidden in UI, so they are not counted
+	sorted = lo.Filter(sorted, func(result QueryResultUI, _ int) bool {
+		return !result.GroupCollapsed
+	})
+	if index < 1 || index > len(sorted) {
+		return QueryResultUI{}, fmt.Errorf("no result at index %d, total results: %d", index, len(sorted))
+	}
+
+	return sorted[index-1], nil
+}
+
+// ExecuteResultAtIndex executes the default action of the result at the given 1-based position, see GetShownResultAtIndex
+func (m *Manager) ExecuteResultAtIndex(ctx context.Context, queryId string, index int) (QueryResultUI, QueryResultActionUI, error) {
+	result, err := m.GetShownResultAtIndex(ctx, queryId, index)
+	if err != nil {
+		return QueryResultUI{}, QueryResultActionUI{}, err
+	}
+
+	defaultAction, found := getDefaultActionUI(result.Actions)
+	if !found {
+		return QueryResultUI{}, QueryResultActionUI{}, fmt.Errorf("result at index %d has no action", index)
+	}
+
+	executeErr := m.ExecuteAction(ctx, result.Id, defaultAction.Id)
+	if executeErr != nil {
+		return QueryResultUI{}, QueryResultActionUI{}, executeErr
+	}
+
+	return result, defaultAction, nil
+}
+
+func getDefaultActionUI(actions []QueryResultActionUI) (QueryResultActionUI, bool) {
+	for _, action := range actions {
+		if action.IsDefault {
+			return action, true
+		}
+	}
+	if len(actions) > 0 {
+		return actions[0], true
+	}
+
+	return QueryResultActionUI{}, false
+}
+
+// sortQueryResultsUI sorts results the same way as UI does: groups by group score desc, then results in group by score desc.
+// Scores of time sensitive results which are not decayed yet (see applyScoreDecay) are decayed by their age at sort time, see decayScore.
+// Scores of time sensitive results are decayed by their age at sort time, see decayScore.
+// Results with equal score are ordered by title, using the collation of langCode (see newTitleCollator).
+// Group header rows in UI are not selectable, so they are not counted.
+func sortQueryResultsUI(results []QueryResultUI, langCode i18n.LangCode) []QueryResultUI {
+	collator := newTitleCollator(langCode)
+
+	var groups []string
+	groupScores := map[string]int64{}
+	for _, result := range results {
+		if groupScore, exist := groupScores[result.Group]; !exist {
+			groups = append(groups, result.Group)
+			groupScores[result.Group] = result.GroupScore
+		} else if result.GroupScore > groupScore {
+			groupScores[result.Group] = result.GroupScore
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groupScores[groups[i]] > groupScores[groups[j]]
+	})
+
+	now := time.Now()
+	var sorted []QueryResultUI
+	for _, group := range groups {
+		var groupResults []QueryResultUI
+		for _, result := range results {
+			if result.Group == group {
+				groupResults = append(groupResults, result)
+			}
+		}
+		sort.SliceStable(groupResults, func(i, j int) bool {
+			scoreI, scoreJ := groupResults[i].sortScore(now), groupResults[j].sortScore(now)
+			if scoreI != scoreJ {
+				return scoreI > scoreJ
+			}
+			return compareResultTitle(collator, groupResults[i].Title, groupResults[j].Title) < 0
+		})
+		sorted = append(sorted, groupResults...)
+	}
+
+	return sorted
+}
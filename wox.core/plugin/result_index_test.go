@@ -0,0 +1,29 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func shownIds(results []QueryResultUI) []string {
+	return lo.Map(results, func(result QueryResultUI, _ int) string { return result.Id })
+}
+
+func TestShownResults_FirstShownOncePerQuery(t *testing.T) {
+	var s shownResults
+
+	firstShown := s.record("q1", []QueryResultUI{{Id: "a"}, {Id: "b"}})
+	assert.Equal(t, []string{"a", "b"}, shownIds(firstShown))
+
+	// refreshed result is sent again in the same query
+	firstShown = s.record("q1", []QueryResultUI{{Id: "b"}, {Id: "c"}})
+	assert.Equal(t, []string{"c"}, shownIds(firstShown))
+	assert.Len(t, s.results, 4)
+
+	// a new query starts over
+	firstShown = s.record("q2", []QueryResultUI{{Id: "a"}})
+	assert.Equal(t, []string{"a"}, shownIds(firstShown))
+	assert.Len(t, s.results, 1)
+}
@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// resultBatchMerger merges results with the same id across batches of a query, so a plugin can emit a result early and enrich it later
+// (E.g. a second batch adds preview or actions). See Manager.Query for the contract. Zero value is not ready to use, see newResultBatchMerger
+type resultBatchMerger struct {
+	sent map[string]QueryResultUI // merged results sent so far by result id
+}
+
+func newResultBatchMerger() *resultBatchMerger {
+	return &resultBatchMerger{sent: map[string]QueryResultUI{}}
+}
+
+// merge returns the batch with every result merged into the version sent before, results with the same id in the batch are merged into one
+func (r *resultBatchMerger) merge(batch []QueryResultUI) []QueryResultUI {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	var merged []QueryResultUI
+	indexInBatch := map[string]int{}
+	for _, result := range batch {
+		if previous, exist := r.sent[result.Id]; exist {
+			result = mergeQueryResultUI(previous, result)
+		}
+		r.sent[result.Id] = result
+
+		if index, exist := indexInBatch[result.Id]; exist {
+			merged[index] = result
+			continue
+		}
+		indexInBatch[result.Id] = len(merged)
+		merged = append(merged, result)
+	}
+	return merged
+}
+
+// mergeQueryResultUI merges a later version of a result into the earlier one: fields set in later win (last write wins),
+// fields left empty in later keep the earlier value, actions and context actions are the union of both (see mergeQueryResultActions)
+func mergeQueryResultUI(earlier QueryResultUI, later QueryResultUI) QueryResultUI {
+	merged := later
+	if merged.QueryId == "" {
+		merged.QueryId = earlier.QueryId
+	}
+	if merged.Title == "" {
+		merged.Title = earlier.Title
+		merged.TitleHighlights = earlier.TitleHighlights
+	}
+	if merged.SubTitle == "" {
+		merged.SubTitle = earlier.SubTitle
+		merged.SubTitleHighlights = earlier.SubTitleHighlights
+	}
+	if merged.MatchReason == "" {
+		merged.MatchReason = earlier.MatchReason
+	}
+	if merged.AccessibilityLabel == "" {
+		merged.AccessibilityLabel = earlier.AccessibilityLabel
+	}
+	if merged.Icon.IsEmpty() {
+		merged.Icon = earlier.Icon
+	}
+	if merged.Preview.IsEmpty() {
+		merged.Preview = earlier.Preview
+	}
+	if merged.Score == 0 {
+		merged.Score = earlier.Score
+	}
+	if merged.Kind == QueryResultKindDefault {
+		merged.Kind = earlier.Kind
+	}
+	if merged.Group == "" && merged.GroupScore == 0 {
+		merged.Group = earlier.Group
+		merged.GroupScore = earlier.GroupScore
+	}
+	if len(merged.Tails) == 0 {
+		merged.Tails = earlier.Tails
+	}
+	if merged.ContextData == "" {
+		merged.ContextData = earlier.ContextData
+	}
+	if len(merged.PreviewTabs) == 0 {
+		merged.PreviewTabs = earlier.PreviewTabs
+	}
+	if merged.RefreshInterval == 0 {
+		merged.RefreshInterval = earlier.RefreshInterval
+	}
+	if merged.ParentId == "" {
+		merged.ParentId = earlier.ParentId
+	}
+	if merged.LastUpdated == 0 {
+		merged.LastUpdated = earlier.LastUpdated
+	}
+	if merged.Timestamp == 0 {
+		merged.Timestamp = earlier.Timestamp
+		merged.ScoreHalfLife = earlier.ScoreHalfLife
+	}
+	merged.Expandable = merged.Expandable || earlier.Expandable
+	merged.HasLazyIcon = merged.HasLazyIcon || earlier.HasLazyIcon
+	merged.Actions = mergeQueryResultActions(earlier.Actions, later.Actions)
+	merged.ContextActions = mergeQueryResultActions(earlier.ContextActions, later.ContextActions)
+	return merged
+}
+
+// mergeQueryResultActions returns the union of actions by id: actions in later replace the ones with the same id in place,
+// new actions are appended. If later has a default action, it's the only default action and is moved to the first place
+func mergeQueryResultActions(earlier []QueryResultActionUI, later []QueryResultActionUI) []QueryResultActionUI {
+	if len(earlier) == 0 {
+		return later
+	}
+	if len(later) == 0 {
+		return earlier
+	}
+
+	merged := append([]QueryResultActionUI{}, earlier...)
+	for _, action := range later {
+		if index := lo.IndexOf(lo.Map(merged, func(item QueryResultActionUI, _ int) string { return item.Id }), action.Id); index >= 0 {
+			merged[index] = action
+		} else {
+			merged = append(merged, action)
+		}
+	}
+
+	if defaultAction, hasDefault := lo.Find(later, func(item QueryResultActionUI) bool { return item.IsDefault }); hasDefault {
+		for i := range merged {
+			if merged[i].Id != defaultAction.Id && merged[i].IsDefault {
+				merged[i].IsDefault = false
+				if strings.EqualFold(merged[i].Hotkey, "Enter") {
+					merged[i].Hotkey = ""
+				}
+			}
+		}
+		merged = append(
+			lo.Filter(merged, func(item QueryResultActionUI, _ int) bool { return item.IsDefault }),
+			lo.Filter(merged, func(item QueryResultActionUI, _ int) bool { return !item.IsDefault })...,
+		)
+	}
+	return merged
+}
+
+// mergeResultCache keeps what the earlier version of a result stored in cache if the later version with the same id doesn't set it,
+// so actions and previews merged in UI (see mergeQueryResultUI) can still be executed and loaded
+func mergeResultCache(earlier *QueryResultCache, later *QueryResultCache) {
+	if earlier.PluginInstance != later.PluginInstance {
+		return
+	}
+
+	earlier.Actions.Range(func(actionId string, action QueryResultAction) bool {
+		if !later.Actions.Exist(actionId) {
+			later.Actions.Store(actionId, action)
+		}
+		return true
+	})
+	for _, action := range earlier.ContextActions {
+		if !lo.ContainsBy(later.ContextActions, func(item QueryResultAction) bool { return item.Id == action.Id }) {
+			later.ContextActions = append(later.ContextActions, action)
+		}
+	}
+	if later.Preview.IsEmpty() && later.Preview.OnUpdate == nil {
+		later.Preview = earlier.getPreview()
+	}
+	if len(later.PreviewTabs) == 0 {
+		later.PreviewTabs = earlier.PreviewTabs
+		later.TabPreviews = earlier.TabPreviews
+	}
+	if later.Refresh == nil {
+		later.Refresh = earlier.Refresh
+	}
+	if later.Expand == nil {
+		later.Expand = earlier.Expand
+	}
+	if later.LazyIcon == nil {
+		later.LazyIcon = earlier.LazyIcon
+	}
+	if later.OnShown == nil {
+		later.OnShown = earlier.OnShown
+	}
+	if later.ContextData == "" {
+		later.ContextData = earlier.ContextData
+	}
+}
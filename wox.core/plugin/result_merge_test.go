@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func actionIds(actions []QueryResultActionUI) []string {
+	return lo.Map(actions, func(action QueryResultActionUI, _ int) string {
+		return action.Id
+	})
+}
+
+func TestResultBatchMerger_SameIdAcrossBatches(t *testing.T) {
+	merger := newResultBatchMerger()
+
+	first := merger.merge([]QueryResultUI{
+		{Id: "a", Title: "A", SubTitle: "loading", Score: 10, Actions: []QueryResultActionUI{{Id: "open", IsDefault: true, Hotkey: "Enter"}}},
+		{Id: "b", Title: "B"},
+	})
+	assert.Len(t, first, 2)
+
+	// later batch of another plugin doesn't affect "a"
+	assert.Equal(t, []string{"c"}, lo.Map(merger.merge([]QueryResultUI{{Id: "c", Title: "C"}}), func(result QueryResultUI, _ int) string {
+		return result.Id
+	}))
+
+	second := merger.merge([]QueryResultUI{
+		{Id: "a", SubTitle: "loaded", Preview: WoxPreview{PreviewType: WoxPreviewTypeText, PreviewData: "detail"}, Actions: []QueryResultActionUI{{Id: "copy"}}},
+	})
+	assert.Len(t, second, 1)
+	merged := second[0]
+	assert.Equal(t, "A", merged.Title)
+	assert.Equal(t, "loaded", merged.SubTitle)
+	assert.Equal(t, int64(10), merged.Score)
+	assert.Equal(t, "detail", merged.Preview.PreviewData)
+	assert.Equal(t, []string{"open", "copy"}, actionIds(merged.Actions))
+	assert.True(t, merged.Actions[0].IsDefault)
+}
+
+func TestResultBatchMerger_OutOfOrderBatches(t *testing.T) {
+	enriched := QueryResultUI{Id: "a", Title: "A (enriched)", Icon: NewWoxImageEmoji("🚀"), Actions: []QueryResultActionUI{{Id: "copy"}, {Id: "share", IsDefault: true, Hotkey: "Enter"}}}
+	basic := QueryResultUI{Id: "a", Title: "A", Actions: []QueryResultActionUI{{Id: "open", IsDefault: true, Hotkey: "Enter"}, {Id: "copy", Name: "Copy title"}}}
+
+	// basic arrives after enriched: last write wins on fields, icon is kept because basic doesn't set it
+	merger := newResultBatchMerger()
+	merger.merge([]QueryResultUI{enriched})
+	merged := merger.merge([]QueryResultUI{basic})[0]
+	assert.Equal(t, "A", merged.Title)
+	assert.Equal(t, "🚀", merged.Icon.ImageData)
+	assert.Equal(t, []string{"open", "copy", "share"}, actionIds(merged.Actions))
+	assert.Equal(t, "Copy title", merged.Actions[1].Name)
+	assert.Equal(t, 1, lo.CountBy(merged.Actions, func(action QueryResultActionUI) bool { return action.IsDefault }))
+	assert.Equal(t, "", merged.Actions[2].Hotkey)
+
+	// same batches in the other order
+	merger = newResultBatchMerger()
+	merger.merge([]QueryResultUI{basic})
+	merged = merger.merge([]QueryResultUI{enriched})[0]
+	assert.Equal(t, "A (enriched)", merged.Title)
+	assert.Equal(t, []string{"share", "open", "copy"}, actionIds(merged.Actions))
+	assert.Equal(t, 1, lo.CountBy(merged.Actions, func(action QueryResultActionUI) bool { return action.IsDefault }))
+}
+
+func TestResultBatchMerger_SameIdInOneBatch(t *testing.T) {
+	merger := newResultBatchMerger()
+	merged := merger.merge([]QueryResultUI{
+		{Id: "a", Title: "A", Actions: []QueryResultActionUI{{Id: "open"}}},
+		{Id: "b", Title: "B"},
+		{Id: "a", SubTitle: "sub", Actions: []QueryResultActionUI{{Id: "copy"}}},
+	})
+	assert.Equal(t, []string{"a", "b"}, lo.Map(merged, func(result QueryResultUI, _ int) string { return result.Id }))
+	assert.Equal(t, "A", merged[0].Title)
+	assert.Equal(t, "sub", merged[0].SubTitle)
+	assert.Equal(t, []string{"open", "copy"}, actionIds(merged[0].Actions))
+}
+
+func TestDedupResultsById(t *testing.T) {
+	results := dedupResultsById([]QueryResultUI{{Id: "a", Title: "old"}, {Id: "b"}, {Id: "a", Title: "new"}})
+	assert.Len(t, results, 2)
+	assert.Equal(t, "new", results[0].Title)
+}
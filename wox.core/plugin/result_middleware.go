@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"wox/setting"
+)
+
+// ResultMiddleware processes results of every query before they are sent to UI, E.g. filter, dedup or annotate them.
+//
+// Middlewares run per flush, not on the final result set: results of a query reach UI in several flushes as plugins return them,
+// Handle receives the results of one flush sorted the same way as UI (see sortQueryResultsUI) and never sees results of earlier flushes.
+// So a middleware can only filter, dedup or rank within a flush, results it keeps are merged with the ones already shown by UI.
+// A middleware which needs the whole result set must keep its own state per query, E.g. keyed by Query.RawQuery.
+// UI sorts results by score again, so a middleware should reorder results by changing their scores.
+// Return stop=true to skip remaining middlewares.
+type ResultMiddleware struct {
+	Name string
+	// Middlewares run in ascending priority, middlewares with the same priority run in registration order
+	Priority int
+	Handle   func(ctx context.Context, query Query, results []QueryResultUI) (processed []QueryResultUI, stop bool)
+}
+
+// RegisterResultMiddleware adds a middleware to the result chain, a middleware with the same name is replaced
+func (m *Manager) RegisterResultMiddleware(ctx context.Context, middleware ResultMiddleware) {
+	m.middlewareLock.Lock()
+	defer m.middlewareLock.Unlock()
+
+	for i, existing := range m.resultMiddlewares {
+		if existing.Name == middleware.Name {
+			m.resultMiddlewares = append(m.resultMiddlewares[:i:i], m.resultMiddlewares[i+1:]...)
+			break
+		}
+	}
+	m.resultMiddlewares = append(m.resultMiddlewares, middleware)
+	sort.SliceStable(m.resultMiddlewares, func(i, j int) bool {
+		return m.resultMiddlewares[i].Priority < m.resultMiddlewares[j].Priority
+	})
+
+	logger.Info(ctx, fmt.Sprintf("result middleware registered: %s, priority: %d", middleware.Name, middleware.Priority))
+}
+
+func (m *Manager) UnregisterResultMiddleware(ctx context.Context, name string) {
+	m.middlewareLock.Lock()
+	defer m.middlewareLock.Unlock()
+
+	for i, existing := range m.resultMiddlewares {
+		if existing.Name == name {
+			m.resultMiddlewares = append(m.resultMiddlewares[:i:i], m.resultMiddlewares[i+1:]...)
+			logger.Info(ctx, fmt.Sprintf("result middleware unregistered: %s", name))
+			return
+		}
+	}
+}
+
+// ProcessResults runs results through registered result middlewares, it must be called with every batch before it's sent to UI.
+// A middleware which panics is skipped, the results it received are passed to the next one.
+func (m *Manager) ProcessResults(ctx context.Context, query Query, results []QueryResultUI) []QueryResultUI {
+	// a flush may contain several batches, later batches carry merged versions of results emitted before, see Manager.Query
+	results = dedupResultsById(results)
+	applyScoreDecay(results, time.Now())
+
+	m.middlewareLock.RLock()
+	middlewares := m.resultMiddlewares
+	m.middlewareLock.RUnlock()
+
+	if len(middlewares) == 0 || len(results) == 0 {
+		return results
+	}
+
+	results = sortQueryResultsUI(results, setting.GetSettingManager().GetWoxSetting(ctx).LangCode)
+	for _, middleware := range middlewares {
+		processed, stop, err := runResultMiddleware(ctx, middleware, query, results)
+		if err != nil {
+			logger.Error(ctx, fmt.Sprintf("result middleware %s failed, skip it: %s", middleware.Name, err.Error()))
+			continue
+		}
+
+		results = processed
+		if stop {
+			break
+		}
+	}
+
+	return results
+}
+
+func runResultMiddleware(ctx context.Context, middleware ResultMiddleware, query Query, results []QueryResultUI) (processed []QueryResultUI, stop bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	// middleware may modify the slice in place, give it a copy so a failed middleware won't leave partial changes
+	processed, stop = middleware.Handle(ctx, query, append([]QueryResultUI(nil), results...))
+	return processed, stop, nil
+}
+
+// dedupResultsById keeps the last version of results with the same id, at the position of the first one
+func dedupResultsById(results []QueryResultUI) []QueryResultUI {
+	if len(results) == 0 {
+		return results
+	}
+
+	indexById := map[string]int{}
+	var deduped []QueryResultUI
+	for _, result := range results {
+		if index, exist := indexById[result.Id]; exist {
+			deduped[index] = result
+			continue
+		}
+		indexById[result.Id] = len(deduped)
+		deduped = append(deduped, result)
+	}
+	return deduped
+}
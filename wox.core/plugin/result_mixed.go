@@ -0,0 +1,32 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+)
+
+// results of the triggered plugin are ranked above global results in mixed queries, groups are sorted by GroupScore
+const triggeredResultGroupScore = int64(1) << 40
+
+// toMixedGlobalQuery returns the query global plugins receive in mixed mode: trigger keyword is stripped,
+// command and search after it become the search term
+func toMixedGlobalQuery(query Query) Query {
+	return Query{
+		Type:           QueryTypeInput,
+		RawQuery:       query.RawQuery,
+		Search:         strings.TrimSpace(query.Command + " " + query.Search),
+		IsLiteralMatch: query.IsLiteralMatch,
+		Env:            query.Env,
+	}
+}
+
+// promoteTriggeredResults puts results of the triggered plugin into their own section above global results.
+// Results without group are grouped under plugin name, groups of the plugin keep their order among themselves
+func (m *Manager) promoteTriggeredResults(ctx context.Context, pluginInstance *Instance, results []QueryResult) {
+	for i := range results {
+		if results[i].Group == "" {
+			results[i].Group = m.translatePlugin(ctx, pluginInstance, pluginInstance.Metadata.Name)
+		}
+		results[i].GroupScore += triggeredResultGroupScore
+	}
+}
@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ResultOrderKeeper keeps results already shown in UI in place when later batches of the same query arrive,
+// so the row user has highlighted won't jump away. Only used when UI asks to freeze order for a query.
+//
+// A late result is placed below all shown results, unless its score beats the best shown result materially
+// (by more than 20%), in which case it's still allowed to move to the top.
+type ResultOrderKeeper struct {
+	freeze        bool
+	hasShown      bool
+	minShownScore int64
+	maxShownScore int64
+}
+
+func NewResultOrderKeeper(freeze bool) *ResultOrderKeeper {
+	return &ResultOrderKeeper{freeze: freeze}
+}
+
+// Keep adjusts scores of a batch which is about to be sent to UI, it must be called in the order batches are sent
+func (k *ResultOrderKeeper) Keep(results []QueryResultUI) {
+	if !k.freeze || len(results) == 0 {
+		return
+	}
+
+	if k.hasShown {
+		materialScore := addScore(k.maxShownScore, max(abs(k.maxShownScore)/5, 1))
+		for i := range results {
+			if results[i].Score <= materialScore && results[i].Score >= k.minShownScore {
+				results[i].Score = addScore(k.minShownScore, -1)
+			}
+		}
+	}
+
+	for _, result := range results {
+		if !k.hasShown {
+			k.minShownScore = result.Score
+			k.maxShownScore = result.Score
+			k.hasShown = true
+			continue
+		}
+		k.minShownScore = min(k.minShownScore, result.Score)
+		k.maxShownScore = max(k.maxShownScore, result.Score)
+	}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// preserveResultOrder rewrites scores of results returned by a plugin with MetadataFeaturePreserveOrder, so that sorting by score
+// keeps the emission order. Within each group, the first result gets the best score of that group and each following
+// result gets one less, so the block of results is placed by its best score among results of other plugins.
+// Results of other plugins whose score falls into the block (best score - count, best score] may still be interleaved.
+func preserveResultOrder(results []QueryResult) {
+	bestScores := map[string]int64{}
+	for _, result := range results {
+		if bestScore, exist := bestScores[result.Group]; !exist || result.Score > bestScore {
+			bestScores[result.Group] = result.Score
+		}
+	}
+
+	for i := range results {
+		results[i].Score = bestScores[results[i].Group]
+		bestScores[results[i].Group] = addScore(bestScores[results[i].Group], -1)
+	}
+}
+
+// sortPluginResults orders results of a plugin which implements ResultSorter, then rewrites their scores like preserveResultOrder,
+// so each group of the plugin keeps the order of the comparator and is placed among results of other plugins by its best score.
+// Sorting is stable, results the comparator treats as equal keep the order in which the plugin returned them.
+// If the comparator panics, results keep the order in which the plugin returned them
+func sortPluginResults(ctx context.Context, pluginInstance *Instance, sorter ResultSorter, results []QueryResult) {
+	sorted := slices.Clone(results)
+	sortErr := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		slices.SortStableFunc(sorted, sorter.CompareResults)
+		return nil
+	}()
+	if sortErr != nil {
+		logger.Error(ctx, fmt.Sprintf("<%s> failed to sort results, keep returned order: %s", pluginInstance.Metadata.Name, sortErr.Error()))
+	} else {
+		copy(results, sorted)
+	}
+
+	preserveResultOrder(results)
+}
@@ -0,0 +1,32 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wox/setting"
+
+	"github.com/samber/lo"
+)
+
+// PeekResultPreview returns the preview of a result user hovers with mouse, without focusing it (see WoxSetting.EnablePreviewPeek).
+// Focus, actions and the preview of the focused result are untouched, UI shows the peeked preview in place of it until hover ends.
+//
+// A lazy default preview tab is computed by its OnPreview and cached just like opening the tab, so focusing the result later
+// doesn't compute it again. ctx should be cancelled by caller when hover ends before the preview is loaded, see GetResultPreviewTab
+func (m *Manager) PeekResultPreview(ctx context.Context, resultId string) (WoxPreview, error) {
+	if !setting.GetSettingManager().GetWoxSetting(ctx).EnablePreviewPeek {
+		return WoxPreview{}, errors.New("preview peek is disabled")
+	}
+
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return WoxPreview{}, fmt.Errorf("result cache not found for result id (peek preview): %s", resultId)
+	}
+
+	if defaultTab, hasDefaultTab := lo.Find(resultCache.PreviewTabs, func(item QueryResultPreviewTab) bool { return item.IsDefault }); hasDefaultTab {
+		return m.GetResultPreviewTab(ctx, resultId, defaultTab.Id)
+	}
+
+	return m.GetResultPreview(ctx, resultId)
+}
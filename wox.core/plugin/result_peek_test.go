@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeekResultPreview(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	oldEnablePreviewPeek := woxSetting.EnablePreviewPeek
+	defer func() { woxSetting.EnablePreviewPeek = oldEnablePreviewPeek }()
+
+	m := GetPluginManager()
+	instance := &Instance{Metadata: Metadata{Id: "preview-peek-test", Name: "preview peek test"}}
+	var computed atomic.Int32
+	for resultId, tabs := range map[string][]QueryResultPreviewTab{
+		"peek-plain": nil,
+		"peek-tabs": {
+			{Id: "info", Name: "Info", Preview: WoxPreview{PreviewType: WoxPreviewTypeMarkdown, PreviewData: "# info"}},
+			{Id: "diff", Name: "Diff", IsDefault: true, OnPreview: func(ctx context.Context) WoxPreview {
+				computed.Add(1)
+				return WoxPreview{PreviewType: WoxPreviewTypeMarkdown, PreviewData: "# diff"}
+			}},
+		},
+	} {
+		resultCache := newEvictionTestResultCache(resultId, Query{Type: QueryTypeInput, RawQuery: "peek"})
+		resultCache.PluginInstance = instance
+		resultCache.Preview = WoxPreview{PreviewType: WoxPreviewTypeMarkdown, PreviewData: "# plain"}
+		resultCache.PreviewTabs = tabs
+		resultCache.TabPreviews = util.NewHashMap[string, WoxPreview]()
+		m.resultCache.Store(resultId, resultCache)
+		defer m.resultCache.Delete(resultId)
+	}
+
+	woxSetting.EnablePreviewPeek = false
+	_, err := m.PeekResultPreview(ctx, "peek-plain")
+	assert.Error(t, err)
+
+	woxSetting.EnablePreviewPeek = true
+	_, err = m.PeekResultPreview(ctx, "not-exist")
+	assert.Error(t, err)
+
+	preview, err := m.PeekResultPreview(ctx, "peek-plain")
+	assert.Nil(t, err)
+	assert.Equal(t, "# plain", preview.PreviewData)
+
+	// default tab is computed once and cached for focusing the result later
+	preview, err = m.PeekResultPreview(ctx, "peek-tabs")
+	assert.Nil(t, err)
+	assert.Equal(t, "# diff", preview.PreviewData)
+	preview, err = m.GetResultPreviewTab(ctx, "peek-tabs", "diff")
+	assert.Nil(t, err)
+	assert.Equal(t, "# diff", preview.PreviewData)
+	assert.Equal(t, int32(1), computed.Load())
+}
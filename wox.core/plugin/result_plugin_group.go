@@ -0,0 +1,42 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"wox/setting"
+)
+
+// tagResultPlugin tags result with the plugin which returned it. In global queries with WoxSetting.GroupResultsByPlugin enabled,
+// result is grouped under plugin name, groups returned by plugin are ignored.
+//
+// GroupScore of the result is its own score, groups are ordered by the highest group score of their results (see sortQueryResultsUI),
+// so the group with the best result comes first no matter in which order plugins return
+func (m *Manager) tagResultPlugin(ctx context.Context, pluginInstance *Instance, query Query, result *QueryResult) {
+	result.pluginId = pluginInstance.Metadata.Id
+	result.pluginName = m.translatePlugin(ctx, pluginInstance, pluginInstance.Metadata.Name)
+	result.pluginIcon = pluginInstance.GetIcon(ctx)
+
+	if !query.IsGlobalQuery() || !setting.GetSettingManager().GetWoxSetting(ctx).GroupResultsByPlugin {
+		return
+	}
+
+	result.Group = result.pluginName
+	result.GroupScore = result.Score
+	result.groupCollapsed = m.IsResultGroupCollapsed(pluginInstance.Metadata.Id)
+}
+
+// SetResultGroupCollapsed collapses or expands the group of a plugin when results are grouped by plugin, see WoxSetting.GroupResultsByPlugin.
+// Hidden results of collapsed groups are not refreshed. The state lasts until Wox restarts
+func (m *Manager) SetResultGroupCollapsed(ctx context.Context, pluginId string, collapsed bool) {
+	logger.Info(ctx, fmt.Sprintf("set result group of plugin %s collapsed: %t", pluginId, collapsed))
+	if collapsed {
+		m.collapsedGroups.Store(pluginId, true)
+	} else {
+		m.collapsedGroups.Delete(pluginId)
+	}
+}
+
+func (m *Manager) IsResultGroupCollapsed(pluginId string) bool {
+	_, collapsed := m.collapsedGroups.Load(pluginId)
+	return collapsed
+}
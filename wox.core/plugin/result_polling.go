@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+)
+
+// default interval in ms between two polls of a polling result, see NewPollingResult
+const defaultPollingResultInterval = 1000
+
+// PollingStatus is the state of async work reported by PollingResult.Poll
+type PollingStatus struct {
+	Title    string // optional, current title is kept if empty
+	SubTitle string // optional, current subtitle is kept if empty (E.g. "Downloading 42%")
+	// work reached a terminal state (succeeded or failed), polling stops and the cancel action is removed
+	Done bool
+	// optional, replaces actions of the result (E.g. "Open" once a download is done), current actions are kept if nil
+	Actions []QueryResultAction
+}
+
+// PollingResult describes a result which represents async work (E.g. a build or download), see NewPollingResult
+type PollingResult struct {
+	Title    string
+	SubTitle string
+	Icon     WoxImage
+	Actions  []QueryResultAction // actions while work is pending
+	Interval int                 // ms between two polls, defaultPollingResultInterval if <= 0
+	// Poll reports current status of the work, it's called every Interval while the result is shown until Done is reported.
+	// ctx is cancelled when the refresh times out, see WoxSetting.MaxRefreshTimeout
+	Poll func(ctx context.Context) PollingStatus
+	// optional, adds a cancel action while work is pending. Polling stops after work is cancelled
+	OnCancel func(ctx context.Context)
+}
+
+// NewPollingResult returns a result which polls the status of async work and updates itself until work is done,
+// it builds on RefreshInterval and OnRefresh so plugins don't have to track the state themselves. E.g.
+//
+//	plugin.NewPollingResult(plugin.PollingResult{
+//		Title: "Building project",
+//		Poll: func(ctx context.Context) plugin.PollingStatus {
+//			if build.Finished() {
+//				return plugin.PollingStatus{SubTitle: "Build succeeded", Done: true, Actions: []plugin.QueryResultAction{plugin.NewOpenAction(build.Output())}}
+//			}
+//			return plugin.PollingStatus{SubTitle: fmt.Sprintf("%d%%", build.Progress())}
+//		},
+//		OnCancel: func(ctx context.Context) { build.Cancel() },
+//	})
+func NewPollingResult(p PollingResult) QueryResult {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultPollingResultInterval
+	}
+
+	cancelled := &atomic.Bool{}
+	withCancelAction := func(actions []QueryResultAction) []QueryResultAction {
+		if p.OnCancel == nil {
+			return actions
+		}
+		return append(slices.Clone(actions), QueryResultAction{
+			Name:                   "i18n:plugin_polling_result_cancel",
+			Icon:                   TerminateAppIcon,
+			PreventHideAfterAction: true,
+			Action: func(ctx context.Context, actionContext ActionContext) {
+				if cancelled.CompareAndSwap(false, true) {
+					p.OnCancel(ctx)
+				}
+			},
+		})
+	}
+
+	return QueryResult{
+		Title:           p.Title,
+		SubTitle:        p.SubTitle,
+		Icon:            p.Icon,
+		Actions:         withCancelAction(p.Actions),
+		RefreshInterval: interval,
+		OnRefresh: func(ctx context.Context, current RefreshableResult) RefreshableResult {
+			if cancelled.Load() {
+				current.SubTitle = "i18n:plugin_polling_result_cancelled"
+				current.Actions = p.Actions
+				current.RefreshInterval = 0
+				return current
+			}
+
+			status := p.Poll(ctx)
+			if status.Title != "" {
+				current.Title = status.Title
+			}
+			if status.SubTitle != "" {
+				current.SubTitle = status.SubTitle
+			}
+			if status.Done {
+				current.RefreshInterval = 0
+				current.Actions = p.Actions
+				if status.Actions != nil {
+					current.Actions = status.Actions
+				}
+			} else if status.Actions != nil {
+				current.Actions = withCancelAction(status.Actions)
+			}
+			return current
+		},
+	}
+}
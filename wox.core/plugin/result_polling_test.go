@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func refreshPollingResult(result QueryResult, current RefreshableResult) RefreshableResult {
+	return result.OnRefresh(context.Background(), current)
+}
+
+func toRefreshableResult(result QueryResult) RefreshableResult {
+	return RefreshableResult{Title: result.Title, SubTitle: result.SubTitle, RefreshInterval: result.RefreshInterval, Actions: result.Actions}
+}
+
+func TestNewPollingResult_UntilDone(t *testing.T) {
+	progress := 0
+	result := NewPollingResult(PollingResult{
+		Title: "Downloading",
+		Poll: func(ctx context.Context) PollingStatus {
+			progress += 50
+			if progress >= 100 {
+				return PollingStatus{SubTitle: "Done", Done: true, Actions: []QueryResultAction{{Name: "Open"}}}
+			}
+			return PollingStatus{SubTitle: "50%"}
+		},
+		OnCancel: func(ctx context.Context) {},
+	})
+	assert.Equal(t, defaultPollingResultInterval, result.RefreshInterval)
+	assert.Len(t, result.Actions, 1, "cancel action is added while pending")
+
+	current := refreshPollingResult(result, toRefreshableResult(result))
+	assert.Equal(t, "50%", current.SubTitle)
+	assert.Equal(t, "Downloading", current.Title)
+	assert.Equal(t, defaultPollingResultInterval, current.RefreshInterval)
+
+	current = refreshPollingResult(result, current)
+	assert.Equal(t, "Done", current.SubTitle)
+	assert.Equal(t, 0, current.RefreshInterval)
+	assert.Equal(t, []string{"Open"}, lo.Map(current.Actions, func(action QueryResultAction, _ int) string { return action.Name }))
+}
+
+func TestNewPollingResult_Cancel(t *testing.T) {
+	cancelCount := 0
+	result := NewPollingResult(PollingResult{
+		Title:    "Building",
+		Interval: 500,
+		Actions:  []QueryResultAction{{Name: "Show log"}},
+		Poll: func(ctx context.Context) PollingStatus {
+			t.Fatal("cancelled work must not be polled")
+			return PollingStatus{}
+		},
+		OnCancel: func(ctx context.Context) { cancelCount++ },
+	})
+	assert.Equal(t, 500, result.RefreshInterval)
+
+	cancelAction := result.Actions[len(result.Actions)-1]
+	cancelAction.Action(context.Background(), ActionContext{})
+	cancelAction.Action(context.Background(), ActionContext{})
+	assert.Equal(t, 1, cancelCount)
+
+	current := refreshPollingResult(result, toRefreshableResult(result))
+	assert.Equal(t, 0, current.RefreshInterval)
+	assert.Equal(t, "i18n:plugin_polling_result_cancelled", current.SubTitle)
+	assert.Len(t, current.Actions, 1)
+}
@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"wox/share"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+// preloadBudget is the max time plugins can take to preload results, it's about the duration of the show animation so preloaded
+// results are ready when the window is visible. Plugins returned later are dropped for this query session
+const preloadBudget = 150 * time.Millisecond
+
+type preloadedResults struct {
+	done    chan struct{} // closed when all plugins returned or budget is used up
+	results []QueryResultUI
+}
+
+// preloadResults queries plugins with MetadataFeaturePreloadOnShow with an empty query in background when query session starts,
+// so UI can show suggestions right after Wox is shown instead of an empty list, see GetPreloadedResults.
+// It doesn't block showing the window, plugins are queried in parallel and cancelled when preloadBudget is used up.
+func (m *Manager) preloadResults(ctx context.Context) {
+	preloadPlugins := lo.Filter(m.getInstances(), func(instance *Instance, _ int) bool {
+		return !instance.Setting.Disabled && instance.Metadata.IsSupportFeature(MetadataFeaturePreloadOnShow)
+	})
+	if len(preloadPlugins) == 0 {
+		m.preloaded.Store(nil)
+		return
+	}
+
+	query, _, queryErr := m.NewQuery(ctx, share.PlainQuery{QueryType: QueryTypeInput})
+	if queryErr != nil {
+		logger.Error(ctx, fmt.Sprintf("failed to create preload query: %s", queryErr.Error()))
+		return
+	}
+
+	preloaded := &preloadedResults{done: make(chan struct{})}
+	m.preloaded.Store(preloaded)
+
+	util.Go(ctx, "preload results", func() {
+		start := util.GetSystemTimestamp()
+		// preload runs while user may start typing, it must not take query slots of plugins
+		preloadCtx, cancel := context.WithTimeout(newQueryLimitExemptContext(ctx), preloadBudget)
+		defer cancel()
+
+		var resultsLock sync.Mutex
+		var results []QueryResultUI
+		var waitGroup sync.WaitGroup
+		for _, instance := range preloadPlugins {
+			waitGroup.Add(1)
+			util.Go(preloadCtx, fmt.Sprintf("[%s] preload results", instance.Metadata.Name), func() {
+				defer waitGroup.Done()
+				pluginResults := m.queryForPlugin(preloadCtx, instance, query)
+				if preloadCtx.Err() != nil {
+					logger.Warn(ctx, fmt.Sprintf("<%s> preload results exceeded budget, dropped", instance.Metadata.Name))
+					return
+				}
+				resultsLock.Lock()
+				for _, result := range pluginResults {
+					results = append(results, result.ToUI())
+				}
+				resultsLock.Unlock()
+			})
+		}
+
+		allDone := make(chan struct{})
+		util.Go(preloadCtx, "wait preload results", func() {
+			waitGroup.Wait()
+			close(allDone)
+		})
+		select {
+		case <-allDone:
+		case <-preloadCtx.Done():
+		}
+
+		resultsLock.Lock()
+		preloaded.results = results
+		resultsLock.Unlock()
+		close(preloaded.done)
+		logger.Debug(ctx, fmt.Sprintf("preloaded %d results, cost %d ms", len(results), util.GetSystemTimestamp()-start))
+	})
+}
+
+// GetPreloadedResults returns results preloaded for current query session, UI asks for them when query is empty.
+// If preloading is still running, it waits until it's done, which takes at most preloadBudget.
+// Returns nil if no plugin preloads results or query session is not started
+func (m *Manager) GetPreloadedResults(ctx context.Context) []QueryResultUI {
+	preloaded := m.preloaded.Load()
+	if preloaded == nil {
+		return nil
+	}
+
+	select {
+	case <-preloaded.done:
+	case <-ctx.Done():
+		return nil
+	}
+
+	// results are shared by all empty queries of the session, UI sets query id on them
+	return append([]QueryResultUI{}, preloaded.results...)
+}
@@ -0,0 +1,115 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wox/util"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const previewTabTimeout = 10 * time.Second
+
+// QueryResultPreviewTab is a named preview of a result, see QueryResult.PreviewTabs.
+//
+// UI shows the default tab (the one with IsDefault, or the first tab) when the result is focused, other tabs are only loaded
+// when user opens them (see Manager.GetResultPreviewTab). So heavy previews (E.g. a diff) should use OnPreview instead of Preview,
+// they are computed only if user opens the tab and cached until the query changes
+type QueryResultPreviewTab struct {
+	// Tab id, unique in the result. It's optional, Wox will assign a random id if you don't set it
+	Id string
+	// Tab title, support i18n
+	Name      string
+	IsDefault bool
+	// Static preview of the tab, ignored if OnPreview is set
+	Preview WoxPreview
+	// Optional, computes the preview lazily when user opens the tab
+	OnPreview func(ctx context.Context) WoxPreview `json:"-"`
+}
+
+type QueryResultPreviewTabUI struct {
+	Id        string
+	Name      string
+	IsDefault bool
+}
+
+// polishPreviewTabs assigns tab ids, translates tab names and points result preview to the default tab, so UI without tab support
+// still shows the default tab as the result preview. Previews are disabled for global queries, tabs are dropped as well
+func (m *Manager) polishPreviewTabs(ctx context.Context, pluginInstance *Instance, query Query, result *QueryResult, resultCache *QueryResultCache) {
+	if query.IsGlobalQuery() {
+		result.PreviewTabs = nil
+		return
+	}
+
+	var tabs []QueryResultPreviewTab
+	for _, tab := range result.PreviewTabs {
+		if tab.OnPreview == nil && tab.Preview.IsEmpty() {
+			logger.Warn(ctx, fmt.Sprintf("<%s> result(%s) preview tab(%s) has no preview", pluginInstance.Metadata.Name, result.Title, tab.Name))
+			continue
+		}
+		if tab.Id == "" || lo.ContainsBy(tabs, func(item QueryResultPreviewTab) bool { return item.Id == tab.Id }) {
+			tab.Id = uuid.NewString()
+		}
+		tab.Name = m.translatePlugin(ctx, pluginInstance, tab.Name)
+		tabs = append(tabs, tab)
+	}
+	if len(tabs) == 0 {
+		result.PreviewTabs = nil
+		return
+	}
+
+	// only one default tab, first one wins
+	defaultIndex := max(lo.IndexOf(lo.Map(tabs, func(item QueryResultPreviewTab, _ int) bool { return item.IsDefault }), true), 0)
+	for i := range tabs {
+		tabs[i].IsDefault = i == defaultIndex
+	}
+
+	result.PreviewTabs = tabs
+	result.Preview = WoxPreview{
+		PreviewType: WoxPreviewTypeRemote,
+		PreviewData: fmt.Sprintf("/preview/tab?id=%s&tabId=%s", result.Id, tabs[defaultIndex].Id),
+	}
+	resultCache.PreviewTabs = tabs
+}
+
+// GetResultPreviewTab returns the preview of a tab of result, lazy previews are computed on first call and cached.
+// ctx should be cancelled by caller when user leaves the tab before it's loaded, the computation is abandoned and not cached
+func (m *Manager) GetResultPreviewTab(ctx context.Context, resultId string, tabId string) (WoxPreview, error) {
+	resultCache, found := m.loadResultCache(resultId)
+	if !found {
+		return WoxPreview{}, fmt.Errorf("result cache not found for result id (get preview tab): %s", resultId)
+	}
+	tab, tabFound := lo.Find(resultCache.PreviewTabs, func(item QueryResultPreviewTab) bool { return item.Id == tabId })
+	if !tabFound {
+		return WoxPreview{}, fmt.Errorf("preview tab not found for result id: %s, tab id: %s", resultId, tabId)
+	}
+
+	pluginInstance := resultCache.PluginInstance
+	if tab.OnPreview == nil {
+		return m.polishPreviewForUI(ctx, pluginInstance, tab.Preview), nil
+	}
+	if preview, exist := resultCache.TabPreviews.Load(tabId); exist {
+		return m.polishPreviewForUI(ctx, pluginInstance, preview), nil
+	}
+
+	tabCtx, cancel := context.WithTimeout(ctx, previewTabTimeout)
+	defer cancel()
+
+	previewChan := make(chan WoxPreview, 1)
+	util.Go(tabCtx, fmt.Sprintf("[%s] result(%s) preview tab(%s)", pluginInstance.Metadata.Name, resultCache.ResultTitle, tab.Name), func() {
+		previewChan <- tab.OnPreview(tabCtx)
+	})
+
+	select {
+	case preview := <-previewChan:
+		if preview.PreviewType == WoxPreviewTypeMarkdown || preview.PreviewType == WoxPreviewTypeText {
+			preview.PreviewData = m.translatePlugin(ctx, pluginInstance, preview.PreviewData)
+		}
+		resultCache.TabPreviews.Store(tabId, preview)
+		return m.polishPreviewForUI(ctx, pluginInstance, preview), nil
+	case <-tabCtx.Done():
+		return WoxPreview{}, fmt.Errorf("preview tab(%s) of result(%s) is cancelled: %w", tab.Name, resultCache.ResultTitle, tabCtx.Err())
+	}
+}
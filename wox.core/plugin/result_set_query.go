@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"wox/share"
+)
+
+const setQueryOnSelectActionName = "i18n:plugin_action_set_query"
+
+// setQueryOnSelectActionId is the fixed id of the action, names are translated and ids are generated after polish,
+// so refreshed results which return the action added by previous polish are recognized by it
+const setQueryOnSelectActionId = "wox.set_query_on_select"
+
+// applySetQueryOnSelect prepends the action which changes the query to value (see QueryResult.SetQueryOnSelect) and makes it the
+// default action, other actions of the result stay available in the action panel but lose IsDefault and Enter.
+// It must be called before plugin defaults are resolved and before user preferences are applied, so user can still pick another default
+func applySetQueryOnSelect(value string, actions []QueryResultAction) []QueryResultAction {
+	if value == "" {
+		return actions
+	}
+
+	polished := []QueryResultAction{newSetQueryOnSelectAction(value)}
+	for _, action := range actions {
+		// refreshed results may return the action added by previous polish
+		if action.Id == setQueryOnSelectActionId {
+			continue
+		}
+		if action.IsDefault && strings.EqualFold(action.Hotkey, "Enter") {
+			action.Hotkey = ""
+		}
+		action.IsDefault = false
+		polished = append(polished, action)
+	}
+	return polished
+}
+
+func newSetQueryOnSelectAction(value string) QueryResultAction {
+	return QueryResultAction{
+		Id:                     setQueryOnSelectActionId,
+		Name:                   setQueryOnSelectActionName,
+		Icon:                   SearchIcon,
+		IsDefault:              true,
+		PreventHideAfterAction: true,
+		Action: func(ctx context.Context, actionContext ActionContext) {
+			GetPluginManager().GetUI().ChangeQuery(ctx, share.PlainQuery{
+				QueryType: QueryTypeInput,
+				QueryText: value,
+			})
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"wox/util"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// refreshPolishedResult refreshes a polished result the way UI does: the shown result is sent back and its refreshed version is returned
+func refreshPolishedResult(t *testing.T, ctx context.Context, m *Manager, result RefreshableResultWithResultId) RefreshableResultWithResultId {
+	refreshed, err := m.ExecuteRefresh(ctx, result, true)
+	assert.Nil(t, err)
+	return refreshed
+}
+
+func countActionsById(actions []QueryResultActionUI, id string) int {
+	return lo.CountBy(actions, func(action QueryResultActionUI) bool { return action.Id == id })
+}
+
+func Test_SetQueryOnSelectAfterRefresh(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "set-query-refresh-test", Name: "set query refresh test"})
+	result := m.PolishResult(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "sq"}, QueryResult{
+		Title:            "Folder",
+		SetQueryOnSelect: "sq folder/",
+		Actions: []QueryResultAction{
+			{Name: "Open", Action: func(ctx context.Context, actionContext ActionContext) {}},
+		},
+		RefreshInterval: 100,
+		OnRefresh: func(ctx context.Context, current RefreshableResult) RefreshableResult {
+			return current
+		},
+	})
+	resultUI := result.ToUI()
+	assert.Equal(t, 1, countActionsById(resultUI.Actions, setQueryOnSelectActionId))
+
+	refreshed := RefreshableResultWithResultId{ResultId: resultUI.Id, Title: resultUI.Title, RefreshInterval: resultUI.RefreshInterval, Actions: resultUI.Actions}
+	for i := 0; i < 2; i++ {
+		refreshed = refreshPolishedResult(t, ctx, m, refreshed)
+		assert.Equal(t, 1, countActionsById(refreshed.Actions, setQueryOnSelectActionId))
+		assert.Equal(t, setQueryOnSelectActionId, refreshed.Actions[0].Id)
+		assert.True(t, refreshed.Actions[0].IsDefault)
+	}
+}
@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"wox/util"
+)
+
+const (
+	// results are handed to Wox in batches of this size, so a plugin adding results one by one doesn't produce a frame per result
+	resultSinkBatchSize = 64
+	// batches which are flushed but not processed by Wox yet, Add and Flush block once it's reached (backpressure)
+	resultSinkMaxPendingBatches = 4
+)
+
+var errResultSinkClosed = errors.New("result sink is closed, the query has finished")
+
+// ResultSink receives results of a query from a ResultSinkQuerier. Wox coalesces added results into batches and shows each batch as soon as it's flushed,
+// so plugins building large result sets can emit them incrementally without collecting them into a slice first.
+//
+// Contract:
+//   - Add and AddBatch buffer results, a batch is flushed automatically once it's full. Flush sends buffered results right away, E.g. after a slow step
+//   - all methods are safe for concurrent use, results keep the order in which they were added
+//   - when Wox can't keep up, Add, AddBatch and Flush block until it catches up (backpressure)
+//   - once the query is cancelled (E.g. user typed another character), all methods return the context error and drop the results,
+//     plugin should stop producing results. Methods called after QueryWithSink returned return an error too
+//   - buffered results are flushed when QueryWithSink returns, calling Flush at the end is not needed
+//   - results added to the sink must not be modified afterwards
+type ResultSink interface {
+	Add(ctx context.Context, result QueryResult) error
+	AddBatch(ctx context.Context, results []QueryResult) error
+	Flush(ctx context.Context) error
+}
+
+// ResultSinkQuerier is implemented by plugins which emit results into a ResultSink instead of returning them, see ResultSink for the contract.
+// Plugin.Query is not called for plugins implementing it, selection queries routed to selection handlers are not affected (see SelectionHandler).
+// Each flushed batch is processed by Wox on its own, so collapsing (see QueryResult.CollapseGroup) and ResultSorter apply within a batch
+type ResultSinkQuerier interface {
+	QueryWithSink(ctx context.Context, query Query, sink ResultSink)
+}
+
+type resultSink struct {
+	lock      sync.Mutex
+	queryCtx  context.Context
+	buffer    []QueryResult
+	batchSize int
+	batches   chan []QueryResult
+	closed    bool
+}
+
+func newResultSink(queryCtx context.Context, batchSize int, maxPendingBatches int) *resultSink {
+	return &resultSink{
+		queryCtx:  queryCtx,
+		buffer:    make([]QueryResult, 0, batchSize),
+		batchSize: batchSize,
+		batches:   make(chan []QueryResult, maxPendingBatches),
+	}
+}
+
+func (s *resultSink) Add(ctx context.Context, result QueryResult) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.checkState(ctx); err != nil {
+		return err
+	}
+	s.buffer = append(s.buffer, result)
+	if len(s.buffer) >= s.batchSize {
+		return s.flushLocked(ctx)
+	}
+	return nil
+}
+
+func (s *resultSink) AddBatch(ctx context.Context, results []QueryResult) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.checkState(ctx); err != nil {
+		return err
+	}
+	for len(results) > 0 {
+		count := min(s.batchSize-len(s.buffer), len(results))
+		s.buffer = append(s.buffer, results[:count]...)
+		results = results[count:]
+		if len(s.buffer) >= s.batchSize {
+			if err := s.flushLocked(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *resultSink) Flush(ctx context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.checkState(ctx); err != nil {
+		return err
+	}
+	return s.flushLocked(ctx)
+}
+
+func (s *resultSink) checkState(ctx context.Context) error {
+	if s.closed {
+		return errResultSinkClosed
+	}
+	if err := s.queryCtx.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// flushLocked sends buffered results as a batch, lock is held while waiting for Wox, so batches keep their order and other callers are blocked too
+func (s *resultSink) flushLocked(ctx context.Context) error {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+
+	batch := s.buffer
+	s.buffer = make([]QueryResult, 0, s.batchSize)
+	select {
+	case s.batches <- batch:
+		return nil
+	case <-s.queryCtx.Done():
+		return s.queryCtx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting results and returns the buffered ones, batches which are already flushed are still delivered
+func (s *resultSink) close() []QueryResult {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.batches)
+	remaining := s.buffer
+	s.buffer = nil
+	return remaining
+}
+
+// invokePluginQueryWithSink calls ResultSinkQuerier.QueryWithSink, onBatch is invoked with every flushed batch while plugin is still querying.
+// Results which are still buffered when plugin returns are returned, all results are returned if onBatch is nil
+func (m *Manager) invokePluginQueryWithSink(ctx context.Context, pluginInstance *Instance, querier ResultSinkQuerier, query Query, onBatch func(batch []QueryResult)) []QueryResult {
+	sink := newResultSink(ctx, resultSinkBatchSize, resultSinkMaxPendingBatches)
+
+	var collected []QueryResult
+	var consumer sync.WaitGroup
+	consumer.Add(1)
+	util.Go(ctx, fmt.Sprintf("<%s> result sink consumer", pluginInstance.Metadata.Name), func() {
+		defer consumer.Done()
+		for batch := range sink.batches {
+			if onBatch == nil {
+				collected = append(collected, batch...)
+				continue
+			}
+			// keep draining after a panic, otherwise plugin would be blocked by backpressure forever
+			func() {
+				defer util.GoRecover(ctx, fmt.Sprintf("<%s> handle result batch panic", pluginInstance.Metadata.Name))
+				onBatch(batch)
+			}()
+		}
+	})
+
+	// sink is closed even if plugin panics, so the consumer won't leak
+	defer func() {
+		sink.close()
+		consumer.Wait()
+	}()
+
+	querier.QueryWithSink(ctx, query, sink)
+	remaining := sink.close()
+	consumer.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return append(collected, remaining...)
+}
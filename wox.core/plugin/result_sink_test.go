@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSinkTestResults(count int) []QueryResult {
+	return lo.Times(count, func(index int) QueryResult {
+		return QueryResult{Title: fmt.Sprintf("result %d", index)}
+	})
+}
+
+func sinkResultTitles(results []QueryResult) []string {
+	return lo.Map(results, func(item QueryResult, _ int) string { return item.Title })
+}
+
+func TestResultSink_Batches(t *testing.T) {
+	ctx := context.Background()
+	sink := newResultSink(ctx, 3, 10)
+
+	assert.Nil(t, sink.AddBatch(ctx, newSinkTestResults(7)))
+	assert.Nil(t, sink.Add(ctx, QueryResult{Title: "single"}))
+	assert.Len(t, sink.batches, 2)
+	assert.Equal(t, []string{"result 0", "result 1", "result 2"}, sinkResultTitles(<-sink.batches))
+	assert.Equal(t, []string{"result 3", "result 4", "result 5"}, sinkResultTitles(<-sink.batches))
+
+	assert.Nil(t, sink.Flush(ctx))
+	assert.Equal(t, []string{"result 6", "single"}, sinkResultTitles(<-sink.batches))
+
+	assert.Nil(t, sink.Add(ctx, QueryResult{Title: "last"}))
+	assert.Equal(t, []string{"last"}, sinkResultTitles(sink.close()))
+	assert.ErrorIs(t, sink.Add(ctx, QueryResult{Title: "after close"}), errResultSinkClosed)
+}
+
+func TestResultSink_Cancelled(t *testing.T) {
+	queryCtx, cancel := context.WithCancel(context.Background())
+	sink := newResultSink(queryCtx, 1, 1)
+	assert.Nil(t, sink.Add(context.Background(), QueryResult{Title: "first"}))
+
+	// second batch is blocked by backpressure until query is cancelled
+	addErr := make(chan error)
+	go func() {
+		addErr <- sink.Add(context.Background(), QueryResult{Title: "second"})
+	}()
+	select {
+	case <-addErr:
+		assert.Fail(t, "add should be blocked while batches are not consumed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-addErr, context.Canceled)
+	assert.ErrorIs(t, sink.Flush(context.Background()), context.Canceled)
+}
+
+type sinkTestPlugin struct {
+	count int
+	sink  ResultSink
+}
+
+func (p *sinkTestPlugin) QueryWithSink(ctx context.Context, query Query, sink ResultSink) {
+	p.sink = sink
+	for _, result := range newSinkTestResults(p.count) {
+		if err := sink.Add(ctx, result); err != nil {
+			return
+		}
+	}
+}
+
+func TestInvokePluginQueryWithSink(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{}
+	instance := &Instance{Metadata: Metadata{Id: "sink-test", Name: "sink-test"}}
+	querier := &sinkTestPlugin{count: resultSinkBatchSize*2 + 10}
+
+	var streamed [][]QueryResult
+	remaining := m.invokePluginQueryWithSink(ctx, instance, querier, Query{}, func(batch []QueryResult) {
+		streamed = append(streamed, batch)
+	})
+	assert.Len(t, streamed, 2)
+	assert.Len(t, streamed[0], resultSinkBatchSize)
+	assert.Equal(t, "result 0", streamed[0][0].Title)
+	assert.Len(t, remaining, 10)
+	assert.Equal(t, fmt.Sprintf("result %d", resultSinkBatchSize*2), remaining[0].Title)
+
+	// results added after query finished are rejected
+	assert.ErrorIs(t, querier.sink.Add(ctx, QueryResult{Title: "late"}), errResultSinkClosed)
+
+	// without onBatch all results are returned at once
+	all := m.invokePluginQueryWithSink(ctx, instance, querier, Query{}, nil)
+	assert.Equal(t, sinkResultTitles(newSinkTestResults(querier.count)), sinkResultTitles(all))
+}
+
+const sinkBenchmarkResultCount = 1000
+
+// BenchmarkResultEmit_PerResult emits every result as its own batch, which is what plugins streaming results without a sink end up doing
+func BenchmarkResultEmit_PerResult(b *testing.B) {
+	results := newSinkTestResults(sinkBenchmarkResultCount)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batches := make(chan []QueryResult, resultSinkMaxPendingBatches)
+		done := make(chan struct{})
+		go func() {
+			for range batches {
+			}
+			close(done)
+		}()
+		for _, result := range results {
+			batches <- []QueryResult{result}
+		}
+		close(batches)
+		<-done
+	}
+}
+
+// BenchmarkResultEmit_Append collects all results into a growing slice before returning them, nothing is shown until the last one is added
+func BenchmarkResultEmit_Append(b *testing.B) {
+	results := newSinkTestResults(sinkBenchmarkResultCount)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var collected []QueryResult
+		for _, result := range results {
+			collected = append(collected, result)
+		}
+		_ = collected
+	}
+}
+
+func BenchmarkResultEmit_Sink(b *testing.B) {
+	results := newSinkTestResults(sinkBenchmarkResultCount)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sink := newResultSink(ctx, resultSinkBatchSize, resultSinkMaxPendingBatches)
+		done := make(chan struct{})
+		go func() {
+			for range sink.batches {
+			}
+			close(done)
+		}()
+		for _, result := range results {
+			sink.Add(ctx, result)
+		}
+		sink.close()
+		<-done
+	}
+}
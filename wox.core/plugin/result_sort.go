@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"strings"
+	"wox/i18n"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// newTitleCollator returns a collator of the given language for ordering result titles,
+// E.g. "Äpfel" is ordered right after "Apfel" in German instead of after "Z".
+// Returns nil if langCode is empty or unknown, titles are compared by bytes then.
+func newTitleCollator(langCode i18n.LangCode) *collate.Collator {
+	if langCode == "" {
+		return nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(string(langCode), "_", "-"))
+	if err != nil {
+		return nil
+	}
+
+	return collate.New(tag)
+}
+
+// compareResultTitle is used to break ties of results with equal score
+func compareResultTitle(collator *collate.Collator, a, b string) int {
+	if collator == nil {
+		return strings.Compare(a, b)
+	}
+
+	return collator.CompareString(a, b)
+}
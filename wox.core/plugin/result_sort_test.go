@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+	"wox/i18n"
+	"wox/util"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedTitles(titles []string, langCode i18n.LangCode) []string {
+	results := lo.Map(titles, func(title string, _ int) QueryResultUI {
+		return QueryResultUI{Title: title, Score: 10}
+	})
+	return lo.Map(sortQueryResultsUI(results, langCode), func(result QueryResultUI, _ int) string {
+		return result.Title
+	})
+}
+
+func TestSortQueryResultsUI_GermanUmlauts(t *testing.T) {
+	titles := []string{"Zucker", "Äpfel", "Apfel", "Öl", "Ofen"}
+	assert.Equal(t, []string{"Apfel", "Äpfel", "Ofen", "Öl", "Zucker"}, sortedTitles(titles, "de_DE"))
+}
+
+func TestSortQueryResultsUI_FrenchAccents(t *testing.T) {
+	titles := []string{"zèbre", "éléphant", "école", "cote", "côte"}
+	assert.Equal(t, []string{"cote", "côte", "école", "éléphant", "zèbre"}, sortedTitles(titles, "fr_FR"))
+}
+
+func TestSortQueryResultsUI_NoLocale(t *testing.T) {
+	// without locale, titles are compared by bytes, accented letters are ordered after ascii letters
+	titles := []string{"Äpfel", "Zucker", "Apfel"}
+	assert.Equal(t, []string{"Apfel", "Zucker", "Äpfel"}, sortedTitles(titles, ""))
+}
+
+func TestSortQueryResultsUI_ScoreFirst(t *testing.T) {
+	results := []QueryResultUI{{Title: "Apfel", Score: 1}, {Title: "Zucker", Score: 2}}
+	sorted := sortQueryResultsUI(results, "de_DE")
+	assert.Equal(t, "Zucker", sorted[0].Title)
+}
+
+func TestSortQueryResultsUI_GroupByBestScore(t *testing.T) {
+	// group score of a group is the highest group score of its results, no matter which result arrives first
+	results := []QueryResultUI{
+		{Title: "Calc", Group: "Calculator", GroupScore: 10, Score: 10},
+		{Title: "Notes", Group: "Files", GroupScore: 50, Score: 50},
+		{Title: "Sum", Group: "Calculator", GroupScore: 80, Score: 80},
+	}
+	sorted := sortQueryResultsUI(results, "")
+	assert.Equal(t, []string{"Sum", "Calc", "Notes"}, lo.Map(sorted, func(result QueryResultUI, _ int) string {
+		return result.Title
+	}))
+}
+
+func TestPreserveResultOrder(t *testing.T) {
+	results := []QueryResult{
+		{Title: "Step 1", Score: 10},
+		{Title: "Step 2", Score: 50},
+		{Title: "Step 3", Score: 20},
+		{Title: "Other", Score: 5, Group: "other"},
+	}
+	preserveResultOrder(results)
+
+	assert.Equal(t, int64(50), results[0].Score)
+	assert.Equal(t, int64(49), results[1].Score)
+	assert.Equal(t, int64(48), results[2].Score)
+	assert.Equal(t, int64(5), results[3].Score)
+}
+
+// sorts by date desc, then by score desc
+type dateResultSorter struct{}
+
+func (s dateResultSorter) CompareResults(a, b QueryResult) int {
+	if a.ContextData != b.ContextData {
+		return strings.Compare(b.ContextData, a.ContextData)
+	}
+	return cmp.Compare(b.Score, a.Score)
+}
+
+type panicResultSorter struct{}
+
+func (s panicResultSorter) CompareResults(a, b QueryResult) int {
+	panic("bad comparator")
+}
+
+func TestSortPluginResults(t *testing.T) {
+	// logger of the manager is used by the bad comparator case
+	GetPluginManager()
+	instance := &Instance{Metadata: Metadata{Name: "test"}}
+	results := []QueryResult{
+		{Title: "Old", ContextData: "2024-01-01", Score: 90},
+		{Title: "New", ContextData: "2024-03-01", Score: 10},
+		{Title: "Middle low", ContextData: "2024-02-01", Score: 20},
+		{Title: "Middle high", ContextData: "2024-02-01", Score: 30},
+		{Title: "Other", Score: 5, Group: "other"},
+	}
+	sortPluginResults(util.NewTraceContext(), instance, dateResultSorter{}, results)
+
+	assert.Equal(t, []string{"New", "Middle high", "Middle low", "Old", "Other"}, lo.Map(results, func(result QueryResult, _ int) string {
+		return result.Title
+	}))
+	// block keeps the best score of the plugin, so it's placed among other plugins by it
+	assert.Equal(t, []int64{90, 89, 88, 87, 5}, lo.Map(results, func(result QueryResult, _ int) int64 {
+		return result.Score
+	}))
+
+	// global sort by score keeps the order of the comparator
+	uiResults := lo.Map(results, func(result QueryResult, _ int) QueryResultUI {
+		return QueryResultUI{Title: result.Title, Score: result.Score}
+	})
+	uiResults = append(uiResults, QueryResultUI{Title: "Best of other plugin", Score: 100}, QueryResultUI{Title: "Worse of other plugin", Score: 50})
+	assert.Equal(t, []string{"Best of other plugin", "New", "Middle high", "Middle low", "Old", "Worse of other plugin", "Other"},
+		lo.Map(sortQueryResultsUI(uiResults, ""), func(result QueryResultUI, _ int) string {
+			return result.Title
+		}))
+
+	// stable, equal results keep the returned order
+	results = []QueryResult{
+		{Title: "A", ContextData: "2024-01-01", Score: 10},
+		{Title: "B", ContextData: "2024-01-01", Score: 10},
+	}
+	sortPluginResults(util.NewTraceContext(), instance, dateResultSorter{}, results)
+	assert.Equal(t, "A", results[0].Title)
+	assert.Equal(t, "B", results[1].Title)
+
+	// bad comparator keeps the returned order
+	results = []QueryResult{
+		{Title: "First", Score: 10},
+		{Title: "Second", Score: 50},
+	}
+	sortPluginResults(util.NewTraceContext(), instance, panicResultSorter{}, results)
+	assert.Equal(t, "First", results[0].Title)
+	assert.Equal(t, int64(50), results[0].Score)
+	assert.Equal(t, "Second", results[1].Title)
+}
@@ -0,0 +1,197 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"wox/util"
+)
+
+const (
+	resultUpdateFlushInterval = 50 * time.Millisecond
+	resultUpdateMaxPending    = 200
+)
+
+// resultUpdater forwards results pushed by plugins (see API.UpdateResult) to UI.
+//
+// Backpressure: updates are coalesced by result id (latest wins) and flushed to UI in one batch at most every
+// resultUpdateFlushInterval. Only one batch is sent to UI at a time, so if UI renders slower than plugin pushes,
+// intermediate updates of the same result are dropped and only the latest one is rendered.
+// If there are already resultUpdateMaxPending different results waiting, updates of new result ids are rejected.
+// Score updates (see API.UpdateResultScore) are coalesced and flushed the same way, separately from result updates.
+type resultUpdater struct {
+	lock    sync.Mutex
+	pending map[string]pendingResultUpdate
+	order   []string // keep the push order of result ids
+	scores  map[string]pendingScoreUpdate
+}
+
+type pendingResultUpdate struct {
+	cache  *QueryResultCache // cache entry when update was pushed, used to detect query changes
+	result RefreshableResult
+}
+
+type pendingScoreUpdate struct {
+	cache *QueryResultCache // cache entry when update was pushed, used to detect query changes
+	score int64
+}
+
+// ResultScoreUpdate is a new score of a result sent to UI, see API.UpdateResultScore
+type ResultScoreUpdate struct {
+	ResultId string
+	Score    int64
+}
+
+func newResultUpdater() *resultUpdater {
+	return &resultUpdater{
+		pending: map[string]pendingResultUpdate{},
+		scores:  map[string]pendingScoreUpdate{},
+	}
+}
+
+func (u *resultUpdater) push(resultId string, update pendingResultUpdate) bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if _, exist := u.pending[resultId]; !exist {
+		if len(u.pending) >= resultUpdateMaxPending {
+			return false
+		}
+		u.order = append(u.order, resultId)
+	}
+	u.pending[resultId] = update
+	return true
+}
+
+func (u *resultUpdater) pushScore(resultId string, update pendingScoreUpdate) bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if _, exist := u.scores[resultId]; !exist && len(u.scores) >= resultUpdateMaxPending {
+		return false
+	}
+	u.scores[resultId] = update
+	return true
+}
+
+func (u *resultUpdater) takeAllScores() map[string]pendingScoreUpdate {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	scores := u.scores
+	u.scores = map[string]pendingScoreUpdate{}
+	return scores
+}
+
+func (u *resultUpdater) takeAll() (resultIds []string, updates map[string]pendingResultUpdate) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	resultIds, updates = u.order, u.pending
+	u.order = nil
+	u.pending = map[string]pendingResultUpdate{}
+	return
+}
+
+// UpdateResult queues a new state of a result which was returned by the plugin in current query.
+// Returns false if the update is dropped, E.g. result is not in current query anymore (query changed) or too many updates are pending.
+func (m *Manager) UpdateResult(ctx context.Context, pluginInstance *Instance, resultId string, result RefreshableResult) bool {
+	resultCache, found := m.resultCache.Load(resultId)
+	if !found {
+		logger.Debug(ctx, fmt.Sprintf("<%s> result cache not found for result id (update result): %s", pluginInstance.Metadata.Name, resultId))
+		return false
+	}
+	if resultCache.PluginInstance.Metadata.Id != pluginInstance.Metadata.Id {
+		logger.Warn(ctx, fmt.Sprintf("<%s> trying to update result of other plugin: %s", pluginInstance.Metadata.Name, resultId))
+		return false
+	}
+
+	if !m.resultUpdater.push(resultId, pendingResultUpdate{cache: resultCache, result: result}) {
+		logger.Warn(ctx, fmt.Sprintf("<%s> too many pending result updates, drop update of result: %s", pluginInstance.Metadata.Name, resultCache.ResultTitle))
+		return false
+	}
+
+	return true
+}
+
+// UpdateResultScore queues a new score of a result which was returned by the plugin in current query, E.g. after a background lookup
+// found out the result is more relevant. Score is adjusted like scores of new results (usage, favorite, plugin weight) and stored in the
+// result cache, it's decayed by the age of the result when it's sent (see MetadataFeatureScoreDecay). Then UI re-sorts
+// results without rebuilding them: results with equal scores keep their order and the focused result stays focused.
+// Returns false if the update is dropped, E.g. result is not in current query anymore (query changed) or too many updates are pending.
+// Updates pushed before the query changed are dropped when flushed, they never apply to results of the new query
+func (m *Manager) UpdateResultScore(ctx context.Context, pluginInstance *Instance, resultId string, score int64) bool {
+	resultCache, found := m.resultCache.Load(resultId)
+	if !found {
+		logger.Debug(ctx, fmt.Sprintf("<%s> result cache not found for result id (update result score): %s", pluginInstance.Metadata.Name, resultId))
+		return false
+	}
+	if resultCache.PluginInstance.Metadata.Id != pluginInstance.Metadata.Id {
+		logger.Warn(ctx, fmt.Sprintf("<%s> trying to update score of result of other plugin: %s", pluginInstance.Metadata.Name, resultId))
+		return false
+	}
+
+	score = m.adjustResultScore(ctx, pluginInstance, resultCache.ResultTitle, resultCache.ResultSubTitle, score)
+	resultCache.Score.Store(score)
+	if !m.resultUpdater.pushScore(resultId, pendingScoreUpdate{cache: resultCache, score: score}) {
+		logger.Warn(ctx, fmt.Sprintf("<%s> too many pending score updates, drop score update of result: %s", pluginInstance.Metadata.Name, resultCache.ResultTitle))
+		return false
+	}
+
+	return true
+}
+
+func (m *Manager) startResultUpdater(ctx context.Context) {
+	util.Go(ctx, "flush result updates", func() {
+		for range time.NewTicker(resultUpdateFlushInterval).C {
+			m.flushResultUpdates(util.NewTraceContext())
+		}
+	})
+}
+
+func (m *Manager) flushResultUpdates(ctx context.Context) {
+	m.flushScoreUpdates(ctx)
+
+	resultIds, updates := m.resultUpdater.takeAll()
+	if len(resultIds) == 0 {
+		return
+	}
+
+	var results []RefreshableResultWithResultId
+	for _, resultId := range resultIds {
+		update := updates[resultId]
+		// query changed after the update was pushed, the result cache is cleared or replaced by new query
+		currentCache, found := m.resultCache.Load(resultId)
+		if !found || currentCache != update.cache {
+			continue
+		}
+
+		results = append(results, m.toRefreshableResultWithResultId(ctx, currentCache, update.result))
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	// this call waits for UI to respond, which makes following updates coalesced while UI is rendering
+	m.ui.UpdateResults(ctx, results)
+}
+
+func (m *Manager) flushScoreUpdates(ctx context.Context) {
+	var scores []ResultScoreUpdate
+	now := time.Now()
+	for resultId, update := range m.resultUpdater.takeAllScores() {
+		// query changed after the update was pushed, the result cache is cleared or replaced by new query
+		currentCache, found := m.resultCache.Load(resultId)
+		if !found || currentCache != update.cache {
+			continue
+		}
+		// latest stored score is sent, it's decayed here like scores of new results before they are sent, see applyScoreDecay
+		scores = append(scores, ResultScoreUpdate{ResultId: resultId, Score: currentCache.decayedScore(now)})
+	}
+	if len(scores) == 0 {
+		return
+	}
+
+	m.ui.UpdateResultScores(ctx, scores)
+}
@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+const resultURLOpenActionName = "i18n:plugin_action_open_link"
+
+// fixed ids of the url actions, refreshed results which return the actions added by previous polish are recognized by them
+const resultURLOpenActionId = "wox.result_url_open"
+const resultURLCopyActionId = "wox.result_url_copy"
+
+// applyResultURL adds the actions of a result backed by an url (see QueryResult.URL): "Open link" and "Copy link".
+// If plugin declared its own default action (IsDefault or DefaultActionId), it's assumed to open the url already,
+// so only "Copy link" is added. Otherwise "Open link" becomes the default action. Nothing is added if url is invalid
+func (m *Manager) applyResultURL(ctx context.Context, pluginInstance *Instance, url string, hasDefaultActionId bool, actions []QueryResultAction) []QueryResultAction {
+	if url == "" {
+		return actions
+	}
+	if err := validateOpenUrl(url); err != nil {
+		logger.Warn(ctx, fmt.Sprintf("<%s> result url is invalid, skip url actions: %s", pluginInstance.Metadata.Name, err.Error()))
+		return actions
+	}
+
+	// refreshed results may return the actions added by previous polish
+	actions = lo.Filter(actions, func(action QueryResultAction, _ int) bool {
+		return action.Id != resultURLOpenActionId && action.Id != resultURLCopyActionId
+	})
+
+	hasDefault := hasDefaultActionId || lo.ContainsBy(actions, func(action QueryResultAction) bool { return action.IsDefault })
+	if !hasDefault {
+		openAction := NewOpenURLAction(resultURLOpenActionName, url)
+		openAction.Id = resultURLOpenActionId
+		openAction.IsDefault = true
+		actions = append([]QueryResultAction{openAction}, actions...)
+	}
+	copyAction := NewCopyLinkAction(url)
+	copyAction.Id = resultURLCopyActionId
+	return append(actions, copyAction)
+}
@@ -0,0 +1,35 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ResultURLActionsAfterRefresh(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	m := GetPluginManager()
+	instance := newTestPluginInstance(Metadata{Id: "result-url-refresh-test", Name: "result url refresh test"})
+	result := m.PolishResult(ctx, instance, Query{Type: QueryTypeInput, RawQuery: "url"}, QueryResult{
+		Title:           "Wox",
+		URL:             "https://github.com/Wox-launcher/Wox",
+		RefreshInterval: 100,
+		OnRefresh: func(ctx context.Context, current RefreshableResult) RefreshableResult {
+			return current
+		},
+	})
+	resultUI := result.ToUI()
+	assert.Equal(t, 1, countActionsById(resultUI.Actions, resultURLOpenActionId))
+	assert.Equal(t, 1, countActionsById(resultUI.Actions, resultURLCopyActionId))
+
+	refreshed := RefreshableResultWithResultId{ResultId: resultUI.Id, Title: resultUI.Title, RefreshInterval: resultUI.RefreshInterval, Actions: resultUI.Actions}
+	for i := 0; i < 2; i++ {
+		refreshed = refreshPolishedResult(t, ctx, m, refreshed)
+		assert.Equal(t, 1, countActionsById(refreshed.Actions, resultURLOpenActionId))
+		assert.Equal(t, 1, countActionsById(refreshed.Actions, resultURLCopyActionId))
+		assert.Equal(t, resultURLOpenActionId, refreshed.Actions[0].Id)
+	}
+}
@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"wox/i18n"
+	"wox/setting"
+
+	"github.com/samber/lo"
+)
+
+var validWoxImageTypes = []WoxImageType{
+	WoxImageTypeAbsolutePath,
+	WoxImageTypeRelativePath,
+	WoxImageTypeBase64,
+	WoxImageTypeSvg,
+	WoxImageTypeLottie,
+	WoxImageTypeEmoji,
+	WoxImageTypeUrl,
+	WoxImageTypeTheme,
+}
+
+// validateQueryResult returns problems of a result returned by plugin which would break UI or actions, nil if result is valid
+func validateQueryResult(pluginInstance *Instance, result QueryResult) (problems []string) {
+	if strings.TrimSpace(result.Title) == "" {
+		problems = append(problems, "title is empty")
+	}
+	if iconProblem := validateWoxImage(pluginInstance, result.Icon); iconProblem != "" {
+		problems = append(problems, fmt.Sprintf("icon %s", iconProblem))
+	}
+	for _, action := range result.Actions {
+		if !action.hasActionFunc() {
+			problems = append(problems, fmt.Sprintf("action(%s) has no action function", action.Name))
+		}
+	}
+	return problems
+}
+
+// validateWoxImage returns why image can't be rendered, empty image is valid because UI doesn't render it at all
+func validateWoxImage(pluginInstance *Instance, image WoxImage) string {
+	if image.IsEmpty() {
+		return ""
+	}
+	if !lo.Contains(validWoxImageTypes, image.ImageType) {
+		return fmt.Sprintf("has unknown type: %s", image.ImageType)
+	}
+	image = normalizeResourceImage(image)
+	if image.ImageType == WoxImageTypeRelativePath {
+		if _, resolveErr := resolvePluginResource(pluginInstance.PluginDirectory, image.ImageData); resolveErr != nil {
+			return resolveErr.Error()
+		}
+	}
+	if image.ImageType == WoxImageTypeAbsolutePath {
+		if !isImageFileExist(image.ImageData) {
+			return fmt.Sprintf("file not found: %s", image.ImageData)
+		}
+	}
+	return ""
+}
+
+// fixQueryResult fixes up problems found by validateQueryResult, so the result can still be shown
+func (m *Manager) fixQueryResult(ctx context.Context, pluginInstance *Instance, result QueryResult) QueryResult {
+	if strings.TrimSpace(result.Title) == "" {
+		if strings.TrimSpace(result.SubTitle) != "" {
+			result.Title = result.SubTitle
+			result.SubTitle = ""
+		} else {
+			result.Title = i18n.GetI18nManager().TranslateWox(ctx, "plugin_manager_untitled_result")
+		}
+	}
+	if validateWoxImage(pluginInstance, result.Icon) != "" {
+		result.Icon = ParseWoxImageOrDefault(pluginInstance.Metadata.Icon, DefaultActionIcon)
+	}
+	result.Actions = lo.Filter(result.Actions, func(action QueryResultAction, _ int) bool {
+		return action.hasActionFunc()
+	})
+	return result
+}
+
+// validateQueryResults protects UI from malformed results of buggy plugins, see WoxSetting.StrictResultValidation.
+// By default invalid results are fixed up (placeholder title, plugin icon, actions without function are dropped) and logged.
+// In strict mode invalid results are dropped and replaced by one error result listing the problems, so plugin developers notice them.
+func (m *Manager) validateQueryResults(ctx context.Context, pluginInstance *Instance, query Query, results []QueryResult) []QueryResult {
+	strict := setting.GetSettingManager().GetWoxSetting(ctx).StrictResultValidation
+
+	var validResults []QueryResult
+	var allProblems []string
+	for _, result := range results {
+		problems := validateQueryResult(pluginInstance, result)
+		if len(problems) == 0 {
+			validResults = append(validResults, result)
+			continue
+		}
+
+		message := fmt.Sprintf("invalid result(%s): %s", result.Title, strings.Join(problems, ", "))
+		logger.Warn(ctx, fmt.Sprintf("<%s> plugin id: %s, %s", pluginInstance.Metadata.Name, pluginInstance.Metadata.Id, message))
+		if strict {
+			allProblems = append(allProblems, message)
+			continue
+		}
+		validResults = append(validResults, m.fixQueryResult(ctx, pluginInstance, result))
+	}
+
+	// failed result also records the problems in recent errors of the plugin, see DumpState
+	if len(allProblems) > 0 {
+		failedResult := m.GetResultForFailedQuery(ctx, pluginInstance.Metadata, query, fmt.Errorf("%s", strings.Join(allProblems, "\n")))
+		validResults = append(validResults, failedResult)
+	}
+
+	return validResults
+}
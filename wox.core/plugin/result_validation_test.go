@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateQueryResults(t *testing.T) {
+	ctx := util.NewTraceContext()
+
+	pluginDirectory := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(pluginDirectory, "icon.png"), []byte("png"), 0644))
+	instance := &Instance{
+		Metadata:        Metadata{Id: "result-validation-test", Name: "result validation test", Icon: "emoji:🧪"},
+		PluginDirectory: pluginDirectory,
+	}
+	action := QueryResultAction{Name: "Open", Action: func(ctx context.Context, actionContext ActionContext) {}}
+
+	valid := QueryResult{Title: "valid", Icon: NewWoxImageAbsolutePath(filepath.Join(pluginDirectory, "icon.png")), Actions: []QueryResultAction{action}}
+	assert.Empty(t, validateQueryResult(instance, valid))
+	assert.Empty(t, validateQueryResult(instance, QueryResult{Title: "relative", Icon: NewWoxImageResource("res://icon.png")}))
+
+	invalid := QueryResult{
+		SubTitle: "only subtitle",
+		Icon:     NewWoxImageAbsolutePath(filepath.Join(pluginDirectory, "missing.png")),
+		Actions:  []QueryResultAction{action, {Name: "Broken"}},
+	}
+	assert.Len(t, validateQueryResult(instance, invalid), 3)
+	assert.NotEmpty(t, validateQueryResult(instance, QueryResult{Title: "outside", Icon: NewWoxImageResource("res://../icon.png")}))
+	assert.NotEmpty(t, validateQueryResult(instance, QueryResult{Title: "unknown", Icon: WoxImage{ImageType: "gif", ImageData: "foo"}}))
+
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	oldStrict := woxSetting.StrictResultValidation
+	defer func() { woxSetting.StrictResultValidation = oldStrict }()
+
+	// invalid results are fixed up by default
+	woxSetting.StrictResultValidation = false
+	results := GetPluginManager().validateQueryResults(ctx, instance, Query{}, []QueryResult{valid, invalid})
+	assert.Len(t, results, 2)
+	assert.Equal(t, "only subtitle", results[1].Title)
+	assert.Equal(t, "", results[1].SubTitle)
+	assert.Equal(t, NewWoxImageEmoji("🧪"), results[1].Icon)
+	assert.Len(t, results[1].Actions, 1)
+
+	// strict mode replaces them with one error result
+	woxSetting.StrictResultValidation = true
+	results = GetPluginManager().validateQueryResults(ctx, instance, Query{}, []QueryResult{valid, invalid, invalid})
+	assert.Len(t, results, 2)
+	assert.Equal(t, "valid", results[0].Title)
+	assert.Contains(t, results[1].Preview.PreviewData, "missing.png")
+}
+
+func TestIsImageFileExist(t *testing.T) {
+	imagePath := filepath.Join(t.TempDir(), "icon.png")
+	assert.False(t, isImageFileExist(imagePath))
+
+	// cached until imageExistCacheTTLMs passed
+	assert.Nil(t, os.WriteFile(imagePath, []byte("png"), 0644))
+	assert.False(t, isImageFileExist(imagePath))
+	imageExistCache.Store(imagePath, imageExistCacheItem{exist: false, timestamp: util.GetSystemTimestamp() - imageExistCacheTTLMs})
+	assert.True(t, isImageFileExist(imagePath))
+}
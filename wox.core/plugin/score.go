@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"math"
+	"time"
+)
+
+// ScoreFixedPointScale is the fixed point scale between float score and QueryResult.Score.
+//
+// QueryResult.Score is int64 on the wire, plugins that compute relevance as float (E.g. probability, fuzzy ratio or
+// a weighted blend of several signals) should compute in float and convert with ScoreFromFloat only once at the end,
+// so fractional weights are not lost. E.g. 0.8 => 800, 12.5 => 12500
+const ScoreFixedPointScale = 1000
+
+// ScoreFromFloat converts a float score to QueryResult.Score, out of range values are clamped and NaN is treated as 0
+func ScoreFromFloat(score float64) int64 {
+	if math.IsNaN(score) {
+		return 0
+	}
+
+	scaled := math.Round(score * ScoreFixedPointScale)
+	if scaled >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if scaled <= math.MinInt64 {
+		return math.MinInt64
+	}
+	return int64(scaled)
+}
+
+// ScoreToFloat converts QueryResult.Score back to float score, see ScoreFromFloat
+func ScoreToFloat(score int64) float64 {
+	return float64(score) / ScoreFixedPointScale
+}
+
+// addScore adds two scores, saturating instead of overflowing when plugins return extreme scores
+func addScore(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	if b < 0 && a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
+}
+
+// decayScore returns the score of a result decayed by its age, see MetadataFeatureScoreDecay:
+//
+//	decayed = score * 0.5 ^ ((now - timestamp) / halfLife)
+//
+// E.g. with half life 6h, a result published 12 hours ago keeps a quarter of its score. Results from the future are not boosted,
+// negative scores are not decayed because decaying would move them up. Score is returned as is if timestamp or half life is not set.
+// It's computed when results are sent to UI instead of when plugin returns them, so ranking stays fresh between queries, see applyScoreDecay.
+func decayScore(score int64, timestamp int64, halfLife int64, now time.Time) int64 {
+	if timestamp <= 0 || halfLife <= 0 || score <= 0 {
+		return score
+	}
+
+	age := max(now.UnixMilli()-timestamp, 0)
+	return ScoreFromFloat(ScoreToFloat(score) * math.Pow(0.5, float64(age)/float64(halfLife)))
+}
+
+// sortScore is the score used to sort results, see decayScore
+func (r *QueryResultUI) sortScore(now time.Time) int64 {
+	return decayScore(r.Score, r.Timestamp, r.ScoreHalfLife, now)
+}
+
+// applyScoreDecay replaces scores of results with their decayed scores before results are sent to UI, which sorts by Score only.
+// ScoreHalfLife is cleared so the score is not decayed again when Wox sorts the shown results, see sortQueryResultsUI
+func applyScoreDecay(results []QueryResultUI, now time.Time) {
+	for i := range results {
+		if results[i].ScoreHalfLife <= 0 {
+			continue
+		}
+		results[i].Score = results[i].sortScore(now)
+		results[i].ScoreHalfLife = 0
+	}
+}
@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreFromFloat(t *testing.T) {
+	assert.Equal(t, int64(800), ScoreFromFloat(0.8))
+	assert.Equal(t, int64(12500), ScoreFromFloat(12.5))
+	assert.Equal(t, int64(-1), ScoreFromFloat(-0.0012))
+	assert.Equal(t, int64(0), ScoreFromFloat(math.NaN()))
+	assert.Equal(t, int64(math.MaxInt64), ScoreFromFloat(math.Inf(1)))
+	assert.Equal(t, int64(math.MinInt64), ScoreFromFloat(-1e30))
+	assert.Equal(t, 0.8, ScoreToFloat(ScoreFromFloat(0.8)))
+}
+
+func TestAddScore(t *testing.T) {
+	assert.Equal(t, int64(3), addScore(1, 2))
+	assert.Equal(t, int64(math.MaxInt64), addScore(math.MaxInt64-1, 100000))
+	assert.Equal(t, int64(math.MinInt64), addScore(math.MinInt64+1, -100))
+}
+
+func TestDecayScore(t *testing.T) {
+	now := time.Now()
+	halfLife := (6 * time.Hour).Milliseconds()
+	assert.Equal(t, int64(1000), decayScore(1000, now.UnixMilli(), halfLife, now))
+	assert.Equal(t, int64(500), decayScore(1000, now.Add(-6*time.Hour).UnixMilli(), halfLife, now))
+	assert.Equal(t, int64(250), decayScore(1000, now.Add(-12*time.Hour).UnixMilli(), halfLife, now))
+	assert.Equal(t, int64(1000), decayScore(1000, now.Add(time.Hour).UnixMilli(), halfLife, now))
+	assert.Equal(t, int64(-1000), decayScore(-1000, now.Add(-6*time.Hour).UnixMilli(), halfLife, now))
+	assert.Equal(t, int64(1000), decayScore(1000, 0, halfLife, now))
+	assert.Equal(t, int64(math.MaxInt64), decayScore(math.MaxInt64, now.UnixMilli(), halfLife, now))
+}
+
+func TestApplyScoreDecay(t *testing.T) {
+	now := time.Now()
+	halfLife := (6 * time.Hour).Milliseconds()
+	results := []QueryResultUI{
+		{Title: "old", Score: 1000, Timestamp: now.Add(-6 * time.Hour).UnixMilli(), ScoreHalfLife: halfLife},
+		{Title: "live", Score: 600},
+	}
+
+	applyScoreDecay(results, now)
+	assert.Equal(t, int64(500), results[0].Score)
+	assert.Equal(t, int64(0), results[0].ScoreHalfLife)
+	assert.Equal(t, int64(600), results[1].Score)
+
+	// decayed score is not decayed again when shown results are sorted
+	sorted := sortQueryResultsUI(results, "en_US")
+	assert.Equal(t, "live", sorted[0].Title)
+	assert.Equal(t, int64(500), sorted[1].sortScore(now.Add(6*time.Hour)))
+}
@@ -0,0 +1,237 @@
+package plugin
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"wox/util"
+	"wox/util/keyring"
+)
+
+const secretServiceName = "wox"
+
+// account of the key of fallback secret files in OS credential store, see getSecretFileCipher
+const secretFileKeyAccount = "secret-file-key"
+
+// secretFileLock guards the fallback key, which is shared by all plugins
+var secretFileLock sync.Mutex
+
+// SecretStore saves credentials (E.g. api keys, tokens) of a plugin to the credential store of OS,
+// so they are not kept in plain text settings. Secrets are namespaced by plugin id.
+//
+// If OS credential store is not available (keyring returns ErrNotSupported, E.g. secret-tool is missing or no Secret Service
+// is running on linux), secrets are saved to a file encrypted with AES-GCM instead. The key of that file is kept in OS credential store
+// once it becomes available. Until then the key is saved to secret.key in Wox data directory, only readable by current user,
+// which protects secrets from being read along with plugin storage but not from anyone who can read files of current user.
+type SecretStore struct {
+	pluginId   string
+	pluginName string
+	lock       sync.Mutex
+}
+
+func newSecretStore(pluginId string, pluginName string) *SecretStore {
+	return &SecretStore{pluginId: pluginId, pluginName: pluginName}
+}
+
+func (s *SecretStore) account(key string) string {
+	return fmt.Sprintf("%s/%s", s.pluginId, key)
+}
+
+// Get returns the secret of key, empty string and false if it doesn't exist.
+// A secret saved to the fallback file while OS credential store was not available is moved into the store once it's available
+func (s *SecretStore) Get(ctx context.Context, key string) (string, bool, error) {
+	secret, err := keyring.Get(secretServiceName, s.account(key))
+	if err == nil {
+		return secret, true, nil
+	}
+	if !errors.Is(err, keyring.ErrNotFound) && !errors.Is(err, keyring.ErrNotSupported) {
+		return "", false, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	secrets, loadErr := s.loadFile()
+	if loadErr != nil {
+		return "", false, loadErr
+	}
+	secret, exist := secrets[key]
+	if !exist || errors.Is(err, keyring.ErrNotSupported) {
+		return secret, exist, nil
+	}
+
+	if setErr := keyring.Set(secretServiceName, s.account(key), secret); setErr != nil {
+		logger.Warn(ctx, fmt.Sprintf("<%s> failed to move secret from encrypted file to os credential store: %s", s.pluginName, setErr.Error()))
+		return secret, true, nil
+	}
+	delete(secrets, key)
+	if saveErr := s.saveFile(secrets); saveErr != nil {
+		logger.Warn(ctx, fmt.Sprintf("<%s> failed to remove moved secret from encrypted file: %s", s.pluginName, saveErr.Error()))
+	}
+	return secret, true, nil
+}
+
+func (s *SecretStore) Set(ctx context.Context, key string, secret string) error {
+	err := keyring.Set(secretServiceName, s.account(key), secret)
+	if err == nil {
+		// drop the copy saved while OS credential store was not available, it would be returned once the store is gone again
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.removeFromFile(key)
+	}
+	if !errors.Is(err, keyring.ErrNotSupported) {
+		return err
+	}
+
+	logger.Warn(ctx, fmt.Sprintf("<%s> os credential store is not available, save secret to encrypted file", s.pluginName))
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	secrets, loadErr := s.loadFile()
+	if loadErr != nil {
+		return loadErr
+	}
+	secrets[key] = secret
+	return s.saveFile(secrets)
+}
+
+// Delete removes the secret from both OS credential store and the fallback file, so a copy saved while the store was not available
+// doesn't come back later
+func (s *SecretStore) Delete(ctx context.Context, key string) error {
+	err := keyring.Delete(secretServiceName, s.account(key))
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) && !errors.Is(err, keyring.ErrNotSupported) {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.removeFromFile(key)
+}
+
+// removeFromFile deletes key from the fallback file if it's there, s.lock must be held
+func (s *SecretStore) removeFromFile(key string) error {
+	secrets, loadErr := s.loadFile()
+	if loadErr != nil {
+		return loadErr
+	}
+	if _, exist := secrets[key]; !exist {
+		return nil
+	}
+	delete(secrets, key)
+	return s.saveFile(secrets)
+}
+
+func (s *SecretStore) getFilePath() string {
+	return path.Join(util.GetLocation().GetPluginStorageDirectory(), fmt.Sprintf("%s.secrets", s.pluginId))
+}
+
+func (s *SecretStore) loadFile() (map[string]string, error) {
+	secrets := map[string]string{}
+	content, readErr := os.ReadFile(s.getFilePath())
+	if readErr != nil {
+		if os.IsNotExist(readErr) {
+			return secrets, nil
+		}
+		return nil, readErr
+	}
+
+	gcm, gcmErr := getSecretFileCipher()
+	if gcmErr != nil {
+		return nil, gcmErr
+	}
+	if len(content) < gcm.NonceSize() {
+		return nil, fmt.Errorf("secret file is corrupted")
+	}
+	plain, openErr := gcm.Open(nil, content[:gcm.NonceSize()], content[gcm.NonceSize():], nil)
+	if openErr != nil {
+		return nil, fmt.Errorf("failed to decrypt secret file: %w", openErr)
+	}
+	if unmarshalErr := json.Unmarshal(plain, &secrets); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to decode secret file: %w", unmarshalErr)
+	}
+	return secrets, nil
+}
+
+func (s *SecretStore) saveFile(secrets map[string]string) error {
+	plain, marshalErr := json.Marshal(secrets)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	gcm, gcmErr := getSecretFileCipher()
+	if gcmErr != nil {
+		return gcmErr
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	return os.WriteFile(s.getFilePath(), gcm.Seal(nonce, nonce, plain, nil), 0600)
+}
+
+// getSecretFileCipher returns the cipher of fallback secret files, key is created on first use
+func getSecretFileCipher() (cipher.AEAD, error) {
+	secretFileLock.Lock()
+	defer secretFileLock.Unlock()
+
+	key, keyErr := loadSecretFileKey()
+	if keyErr != nil {
+		return nil, keyErr
+	}
+
+	block, blockErr := aes.NewCipher(key)
+	if blockErr != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", blockErr)
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadSecretFileKey reads the key from OS credential store, a key saved to file while the store was not available is moved into it.
+// The key file is only used if OS credential store is not available
+func loadSecretFileKey() ([]byte, error) {
+	encodedKey, getErr := keyring.Get(secretServiceName, secretFileKeyAccount)
+	if getErr == nil {
+		return base64.StdEncoding.DecodeString(encodedKey)
+	}
+	if !errors.Is(getErr, keyring.ErrNotFound) && !errors.Is(getErr, keyring.ErrNotSupported) {
+		return nil, getErr
+	}
+
+	keyPath := path.Join(util.GetLocation().GetWoxDataDirectory(), "secret.key")
+	key, readErr := os.ReadFile(keyPath)
+	isNewKey := os.IsNotExist(readErr)
+	if isNewKey {
+		key = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+	} else if readErr != nil {
+		return nil, readErr
+	}
+
+	if errors.Is(getErr, keyring.ErrNotFound) {
+		setErr := keyring.Set(secretServiceName, secretFileKeyAccount, base64.StdEncoding.EncodeToString(key))
+		if setErr == nil {
+			// key is kept in OS credential store from now on, don't leave it readable on disk
+			os.Remove(keyPath)
+			return key, nil
+		}
+		if !errors.Is(setErr, keyring.ErrNotSupported) {
+			return nil, setErr
+		}
+	}
+
+	if isNewKey {
+		if err := os.WriteFile(keyPath, key, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"context"
+	"wox/util/selection"
+)
+
+// SelectionHandler handles selection queries of a specific selection type, register it by API.RegisterSelectionHandler.
+//
+// Once a plugin registered any selection handler, Wox routes selection queries to matching handlers instead of Plugin.Query,
+// so plugin doesn't need to branch on query type and selection type. Plugins which don't register handlers still receive
+// selection queries in Plugin.Query (with MetadataFeatureQuerySelection enabled).
+type SelectionHandler struct {
+	Type selection.SelectionType
+	// Optional, handler is only invoked when predicate returns true. E.g. only handle text which looks like an url
+	Predicate func(ctx context.Context, selection selection.Selection) bool
+	Handler   func(ctx context.Context, query Query) []QueryResult
+}
+
+func (h SelectionHandler) isMatch(ctx context.Context, s selection.Selection) bool {
+	if h.Type != s.Type {
+		return false
+	}
+	if h.Predicate != nil && !h.Predicate(ctx, s) {
+		return false
+	}
+	return true
+}
+
+// invokePluginQuery routes selection queries to registered selection handlers if there are any, otherwise calls Plugin.Query
+// (or ResultSinkQuerier.QueryWithSink, onBatch receives batches flushed while querying, see invokePluginQueryWithSink)
+func (m *Manager) invokePluginQuery(ctx context.Context, pluginInstance *Instance, query Query, onBatch func(batch []QueryResult)) (results []QueryResult) {
+	if query.Type != QueryTypeSelection || len(pluginInstance.SelectionHandlers) == 0 {
+		if querier, ok := pluginInstance.Plugin.(ResultSinkQuerier); ok {
+			return m.invokePluginQueryWithSink(ctx, pluginInstance, querier, query, onBatch)
+		}
+		return pluginInstance.Plugin.Query(ctx, query)
+	}
+
+	for _, handler := range pluginInstance.SelectionHandlers {
+		if handler.isMatch(ctx, query.Selection) {
+			results = append(results, handler.Handler(ctx, query)...)
+		}
+	}
+	return results
+}
@@ -0,0 +1,315 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+// version of the export file format, increase it when the format changes incompatibly
+const pluginSettingsExportVersion = 1
+
+// plugin setting keys which are never exported or imported, permissions must be granted by user on this machine, see Manager.CheckPermission
+var nonTransferablePluginSettingKeys = []string{"PermissionGrants"}
+
+const (
+	PluginSettingsChangeScopeSetting = "setting"
+	PluginSettingsChangeScopeStore   = "store"
+)
+
+// PluginSettingsExport is the content of an exported plugin settings file, see Manager.ExportPluginSettings.
+// Secrets (see SecretStore) are kept in OS credential store and are never exported, neither are permission grants.
+type PluginSettingsExport struct {
+	Version   int
+	Timestamp int64
+	Plugins   []PluginSettingsExportItem
+}
+
+type PluginSettingsExportItem struct {
+	PluginId      string
+	PluginName    string
+	PluginVersion string
+	Setting       *setting.PluginSetting
+	Store         map[string]json.RawMessage // nil if storage is not exported
+}
+
+type PluginSettingsImportOptions struct {
+	// Only report what would change without changing anything
+	DryRun bool
+	// Keep existing values of conflicting keys instead of overwriting them with imported values
+	KeepExisting bool
+}
+
+// PluginSettingsChange is a key which is (or would be in dry run) changed by import
+type PluginSettingsChange struct {
+	PluginId   string
+	PluginName string
+	Scope      string // PluginSettingsChangeScopeSetting or PluginSettingsChangeScopeStore
+	Key        string // setting keys are field names of plugin setting, plugin defined settings are prefixed by "Settings."
+	OldValue   string // json encoded, empty if key doesn't exist before import
+	NewValue   string // json encoded
+	Conflict   bool   // key exists with a different value
+	Skipped    bool   // conflicting key kept because of KeepExisting
+}
+
+type PluginSettingsImportReport struct {
+	Changes  []PluginSettingsChange
+	Warnings []string // E.g. plugin not installed or version mismatch
+}
+
+// ExportPluginSettings exports settings of all installed plugins, and their storage (see KVStore) if includeStore is true.
+// Plugins which user excluded from export (see setting.PluginSetting.ExcludeFromExport) are skipped
+func (m *Manager) ExportPluginSettings(ctx context.Context, includeStore bool) PluginSettingsExport {
+	export := PluginSettingsExport{
+		Version:   pluginSettingsExportVersion,
+		Timestamp: util.GetSystemTimestamp(),
+	}
+
+	for _, instance := range m.getInstances() {
+		if instance.Setting == nil || instance.Setting.ExcludeFromExport {
+			continue
+		}
+
+		exportedSetting := *instance.Setting
+		exportedSetting.PermissionGrants = nil
+		item := PluginSettingsExportItem{
+			PluginId:      instance.Metadata.Id,
+			PluginName:    instance.Metadata.Name,
+			PluginVersion: instance.Metadata.Version,
+			Setting:       &exportedSetting,
+		}
+		if includeStore {
+			item.Store = instance.Store().export(ctx)
+		}
+		export.Plugins = append(export.Plugins, item)
+	}
+
+	logger.Info(ctx, fmt.Sprintf("exported settings of %d plugins, include store: %t", len(export.Plugins), includeStore))
+	return export
+}
+
+// ExportPluginSettingsToFile writes ExportPluginSettings to filePath as json
+func (m *Manager) ExportPluginSettingsToFile(ctx context.Context, filePath string, includeStore bool) error {
+	content, marshalErr := json.MarshalIndent(m.ExportPluginSettings(ctx, includeStore), "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("failed to encode plugin settings: %w", marshalErr)
+	}
+
+	return os.WriteFile(filePath, content, 0600)
+}
+
+// ImportPluginSettingsFromFile reads an exported plugin settings file and imports it, see ImportPluginSettings
+func (m *Manager) ImportPluginSettingsFromFile(ctx context.Context, filePath string, options PluginSettingsImportOptions) (PluginSettingsImportReport, error) {
+	content, readErr := os.ReadFile(filePath)
+	if readErr != nil {
+		return PluginSettingsImportReport{}, readErr
+	}
+
+	var export PluginSettingsExport
+	if unmarshalErr := json.Unmarshal(content, &export); unmarshalErr != nil {
+		return PluginSettingsImportReport{}, fmt.Errorf("failed to decode plugin settings: %w", unmarshalErr)
+	}
+
+	return m.ImportPluginSettings(ctx, export, options)
+}
+
+// ImportPluginSettings merges exported plugin settings into installed plugins key by key, keys not in export are kept.
+// Plugins which are not installed are skipped, plugins with a different version are imported with a warning.
+func (m *Manager) ImportPluginSettings(ctx context.Context, export PluginSettingsExport, options PluginSettingsImportOptions) (PluginSettingsImportReport, error) {
+	var report PluginSettingsImportReport
+	if export.Version > pluginSettingsExportVersion {
+		return report, fmt.Errorf("plugin settings export version %d is not supported, please upgrade Wox", export.Version)
+	}
+
+	for _, item := range export.Plugins {
+		instance, found := m.getPluginInstanceById(item.PluginId)
+		if !found {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("plugin %s (%s) is not installed, skipped", item.PluginName, item.PluginId))
+			continue
+		}
+		if item.PluginVersion != instance.Metadata.Version {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("plugin %s is exported from version %s, installed version is %s", instance.Metadata.Name, item.PluginVersion, instance.Metadata.Version))
+		}
+
+		if item.Setting != nil {
+			changes, importErr := m.importPluginSetting(ctx, instance, item.Setting, options)
+			if importErr != nil {
+				return report, fmt.Errorf("failed to import settings of plugin %s: %w", instance.Metadata.Name, importErr)
+			}
+			report.Changes = append(report.Changes, changes...)
+		}
+		if item.Store != nil {
+			changes, importErr := m.importPluginStore(ctx, instance, item.Store, options)
+			if importErr != nil {
+				return report, fmt.Errorf("failed to import storage of plugin %s: %w", instance.Metadata.Name, importErr)
+			}
+			report.Changes = append(report.Changes, changes...)
+		}
+	}
+
+	logger.Info(ctx, fmt.Sprintf("imported plugin settings, dry run: %t, changes: %d, warnings: %d", options.DryRun, len(report.Changes), len(report.Warnings)))
+	return report, nil
+}
+
+func (m *Manager) importPluginSetting(ctx context.Context, instance *Instance, imported *setting.PluginSetting, options PluginSettingsImportOptions) ([]PluginSettingsChange, error) {
+	current, flattenErr := flattenPluginSetting(instance.Setting)
+	if flattenErr != nil {
+		return nil, flattenErr
+	}
+	incoming, flattenErr := flattenPluginSetting(imported)
+	if flattenErr != nil {
+		return nil, flattenErr
+	}
+	// a crafted or old export file may still contain them
+	for _, key := range nonTransferablePluginSettingKeys {
+		delete(incoming, key)
+	}
+
+	changes := diffImportedValues(instance, PluginSettingsChangeScopeSetting, current, incoming, options)
+	applied := lo.Filter(changes, func(change PluginSettingsChange, _ int) bool { return !change.Skipped })
+	if options.DryRun || len(applied) == 0 {
+		return changes, nil
+	}
+
+	for _, change := range applied {
+		current[change.Key] = change.NewValue
+	}
+	merged, unflattenErr := unflattenPluginSetting(current)
+	if unflattenErr != nil {
+		return nil, unflattenErr
+	}
+	merged.Name = instance.Setting.Name
+	*instance.Setting = *merged
+	if saveErr := instance.SaveSetting(ctx); saveErr != nil {
+		return nil, saveErr
+	}
+
+	// notify plugin about changed plugin defined settings, the same as API.SaveSetting
+	for _, change := range applied {
+		if key, isPluginSetting := strings.CutPrefix(change.Key, "Settings."); isPluginSetting {
+			value, _ := instance.Setting.GetSetting(key)
+			for _, callback := range instance.SettingChangeCallbacks {
+				callback(key, value)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func (m *Manager) importPluginStore(ctx context.Context, instance *Instance, imported map[string]json.RawMessage, options PluginSettingsImportOptions) ([]PluginSettingsChange, error) {
+	current := lo.MapValues(instance.Store().export(ctx), compactJSON)
+	incoming := lo.MapValues(imported, compactJSON)
+
+	changes := diffImportedValues(instance, PluginSettingsChangeScopeStore, current, incoming, options)
+	if options.DryRun {
+		return changes, nil
+	}
+
+	for _, change := range changes {
+		if change.Skipped {
+			continue
+		}
+		if setErr := instance.Store().Set(ctx, change.Key, json.RawMessage(change.NewValue)); setErr != nil {
+			return nil, setErr
+		}
+	}
+	return changes, nil
+}
+
+// diffImportedValues returns keys of incoming which are different from current, sorted by key
+func diffImportedValues(instance *Instance, scope string, current map[string]string, incoming map[string]string, options PluginSettingsImportOptions) (changes []PluginSettingsChange) {
+	keys := lo.Keys(incoming)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		oldValue, exist := current[key]
+		if exist && oldValue == incoming[key] {
+			continue
+		}
+		changes = append(changes, PluginSettingsChange{
+			PluginId:   instance.Metadata.Id,
+			PluginName: instance.Metadata.Name,
+			Scope:      scope,
+			Key:        key,
+			OldValue:   oldValue,
+			NewValue:   incoming[key],
+			Conflict:   exist,
+			Skipped:    exist && options.KeepExisting,
+		})
+	}
+	return changes
+}
+
+// flattenPluginSetting converts plugin setting to json encoded values by key, so settings can be compared and merged key by key.
+// Plugin defined settings are flattened with "Settings." prefix, Name is readonly and is not included
+func flattenPluginSetting(pluginSetting *setting.PluginSetting) (map[string]string, error) {
+	content, marshalErr := json.Marshal(pluginSetting)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	var fields map[string]json.RawMessage
+	if unmarshalErr := json.Unmarshal(content, &fields); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	flattened := map[string]string{}
+	for key, value := range fields {
+		if key == "Name" || key == "Settings" {
+			continue
+		}
+		flattened[key] = string(value)
+	}
+	if pluginSetting.Settings != nil {
+		pluginSetting.Settings.Range(func(key string, value string) bool {
+			encoded, _ := json.Marshal(value)
+			flattened["Settings."+key] = string(encoded)
+			return true
+		})
+	}
+	return flattened, nil
+}
+
+func unflattenPluginSetting(flattened map[string]string) (*setting.PluginSetting, error) {
+	fields := map[string]json.RawMessage{}
+	settings := map[string]json.RawMessage{}
+	for key, value := range flattened {
+		if settingKey, isPluginSetting := strings.CutPrefix(key, "Settings."); isPluginSetting {
+			settings[settingKey] = json.RawMessage(value)
+		} else {
+			fields[key] = json.RawMessage(value)
+		}
+	}
+	settingsContent, marshalErr := json.Marshal(settings)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	fields["Settings"] = settingsContent
+
+	content, marshalErr := json.Marshal(fields)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	var pluginSetting = &setting.PluginSetting{}
+	if unmarshalErr := json.Unmarshal(content, pluginSetting); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return pluginSetting, nil
+}
+
+// compactJSON removes insignificant spaces (E.g. indents of export file), so values can be compared as strings
+func compactJSON(value json.RawMessage, _ string) string {
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, value); err != nil {
+		return string(value)
+	}
+	return buffer.String()
+}
@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSettingTransferTestInstance() *Instance {
+	instance := &Instance{
+		Metadata: Metadata{Id: "setting-transfer-test", Name: "setting transfer test", Version: "1.0.0"},
+		Setting: &setting.PluginSetting{
+			Settings:         util.NewHashMap[string, string](),
+			PermissionGrants: util.NewHashMap[string, bool](),
+		},
+	}
+	instance.Setting.Settings.Store("theme", "dark")
+	instance.Setting.PermissionGrants.Store(MetadataPermissionNetwork, false)
+	return instance
+}
+
+func getSettingTransferTestTheme(pluginSetting *setting.PluginSetting) string {
+	theme, _ := pluginSetting.GetSetting("theme")
+	return theme
+}
+
+func TestExportPluginSettings_PermissionGrants(t *testing.T) {
+	GetPluginManager() // initializes logger
+
+	instance := newSettingTransferTestInstance()
+	m := &Manager{instances: []*Instance{instance}}
+
+	export := m.ExportPluginSettings(context.Background(), false)
+	assert.Len(t, export.Plugins, 1)
+	assert.Nil(t, export.Plugins[0].Setting.PermissionGrants)
+	assert.Equal(t, "dark", getSettingTransferTestTheme(export.Plugins[0].Setting))
+
+	// exporting must not change the installed plugin
+	granted, decided := instance.Setting.PermissionGrants.Load(MetadataPermissionNetwork)
+	assert.True(t, decided)
+	assert.False(t, granted)
+}
+
+func TestImportPluginSettings(t *testing.T) {
+	ctx := context.Background()
+	GetPluginManager() // initializes logger
+	defer os.Remove(path.Join(util.GetLocation().GetPluginSettingDirectory(), "setting-transfer-test.json"))
+
+	instance := newSettingTransferTestInstance()
+	m := &Manager{instances: []*Instance{instance}}
+
+	imported := &setting.PluginSetting{
+		Settings:         util.NewHashMap[string, string](),
+		PermissionGrants: util.NewHashMap[string, bool](),
+		ScoreMultiplier:  2,
+	}
+	imported.Settings.Store("theme", "light")
+	imported.PermissionGrants.Store(MetadataPermissionNetwork, true)
+	export := PluginSettingsExport{
+		Version: pluginSettingsExportVersion,
+		Plugins: []PluginSettingsExportItem{
+			{PluginId: "setting-transfer-test", PluginVersion: "0.9.0", Setting: imported},
+			{PluginId: "not-installed", PluginName: "not installed", Setting: imported},
+		},
+	}
+
+	report, err := m.ImportPluginSettings(ctx, export, PluginSettingsImportOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Len(t, report.Warnings, 2)
+	keys := map[string]PluginSettingsChange{}
+	for _, change := range report.Changes {
+		keys[change.Key] = change
+	}
+	assert.NotContains(t, keys, "PermissionGrants")
+	assert.True(t, keys["Settings.theme"].Conflict)
+	assert.Equal(t, "dark", getSettingTransferTestTheme(instance.Setting))
+
+	report, err = m.ImportPluginSettings(ctx, export, PluginSettingsImportOptions{KeepExisting: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "dark", getSettingTransferTestTheme(instance.Setting))
+	assert.Equal(t, 0.0, instance.Setting.ScoreMultiplier)
+
+	_, err = m.ImportPluginSettings(ctx, export, PluginSettingsImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "light", getSettingTransferTestTheme(instance.Setting))
+	assert.Equal(t, 2.0, instance.Setting.ScoreMultiplier)
+
+	// imported file can't grant permissions, user's decision is kept
+	granted, decided := instance.Setting.PermissionGrants.Load(MetadataPermissionNetwork)
+	assert.True(t, decided)
+	assert.False(t, granted)
+
+	_, err = m.ImportPluginSettings(ctx, PluginSettingsExport{Version: pluginSettingsExportVersion + 1}, PluginSettingsImportOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"context"
+	"wox/setting"
+	"wox/util"
+)
+
+// StaticResult is an always available result (E.g. quit, lock) registered by API.RegisterStaticResults.
+// Wox matches it against input queries itself and shows it instantly, without invoking the plugin.
+type StaticResult struct {
+	Result QueryResult
+	// Texts matched against query search, Result.Title is used if empty
+	MatchTexts []string
+}
+
+// queryStaticResults returns static results of the plugin which match the query, they are polished the same way as query results
+func (m *Manager) queryStaticResults(ctx context.Context, pluginInstance *Instance, query Query) (results []QueryResult) {
+	staticResults := pluginInstance.GetStaticResults()
+	if len(staticResults) == 0 || query.Type != QueryTypeInput || query.Search == "" {
+		return nil
+	}
+
+	usePinYin := setting.GetSettingManager().GetWoxSetting(ctx).UsePinYin
+	for _, staticResult := range staticResults {
+		matchTexts := staticResult.MatchTexts
+		if len(matchTexts) == 0 {
+			matchTexts = []string{m.translatePlugin(ctx, pluginInstance, staticResult.Result.Title)}
+		}
+
+		var isMatch bool
+		var bestScore int64
+		for _, matchText := range matchTexts {
+			if match, score := util.IsStringMatchScore(matchText, query.Search, usePinYin); match {
+				isMatch = true
+				bestScore = max(bestScore, score)
+			}
+		}
+		if !isMatch {
+			continue
+		}
+
+		// static results are shared by queries, copy slices which are modified when polishing
+		result := staticResult.Result
+		result.Actions = append([]QueryResultAction(nil), result.Actions...)
+		result.Tails = append([]QueryResultTail(nil), result.Tails...)
+		if result.Score == 0 {
+			result.Score = bestScore
+		}
+		results = append(results, m.prepareQueryResult(ctx, pluginInstance, query, result))
+	}
+
+	return results
+}
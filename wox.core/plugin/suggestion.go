@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"wox/setting"
+	"wox/util"
+)
+
+const maxQuerySuggestions = 5
+
+// suggestions are requested on every keystroke, only compute them once user pauses typing
+const querySuggestionDebounce = 100 * time.Millisecond
+
+// QuerySuggestion is a query UI suggests as user types (E.g. "did you mean", recent queries), shown separately from results.
+// UI changes the query to Query when user selects it, the same as API.ChangeQuery
+type QuerySuggestion struct {
+	Query string
+	// Description support i18n, it's optional
+	Description string
+	Icon        WoxImage
+}
+
+// SuggestionProvider returns query suggestions for given query, register it by API.RegisterSuggestionProvider.
+// It's only invoked for queries the plugin can handle (E.g. query with the plugin's trigger keyword), and should return quickly
+type SuggestionProvider func(ctx context.Context, query Query) []QuerySuggestion
+
+// QuerySuggestions returns suggestions for query from query history and plugin suggestion providers, at most maxQuerySuggestions.
+// Calls are debounced: if another call starts before the debounce period is over, this one returns nil
+func (m *Manager) QuerySuggestions(ctx context.Context, query Query) []QuerySuggestion {
+	if query.Type != QueryTypeInput || query.RawQuery == "" {
+		return nil
+	}
+
+	seq := m.suggestionSeq.Add(1)
+	select {
+	case <-time.After(querySuggestionDebounce):
+	case <-ctx.Done():
+		return nil
+	}
+	if m.suggestionSeq.Load() != seq {
+		return nil
+	}
+
+	var suggestions []QuerySuggestion
+	var seen = map[string]bool{query.RawQuery: true}
+	var add = func(suggestion QuerySuggestion) bool {
+		if suggestion.Query == "" || seen[suggestion.Query] {
+			return len(suggestions) < maxQuerySuggestions
+		}
+		seen[suggestion.Query] = true
+		suggestions = append(suggestions, suggestion)
+		return len(suggestions) < maxQuerySuggestions
+	}
+
+	for _, pluginInstance := range m.getInstances() {
+		if len(pluginInstance.SuggestionProviders) == 0 || !m.canOperateQuery(ctx, pluginInstance, query) {
+			continue
+		}
+		for _, suggestion := range m.invokeSuggestionProviders(ctx, pluginInstance, query) {
+			if !add(suggestion) {
+				return suggestions
+			}
+		}
+	}
+
+	lowerQuery := strings.ToLower(query.RawQuery)
+	for _, history := range setting.GetSettingManager().GetLatestQueryHistory(ctx, 100) {
+		if history.Query.QueryType != QueryTypeInput || !strings.HasPrefix(strings.ToLower(history.Query.QueryText), lowerQuery) {
+			continue
+		}
+		if !add(QuerySuggestion{Query: history.Query.QueryText, Icon: PluginQueryHistoryIcon}) {
+			return suggestions
+		}
+	}
+
+	return suggestions
+}
+
+func (m *Manager) invokeSuggestionProviders(ctx context.Context, pluginInstance *Instance, query Query) (suggestions []QuerySuggestion) {
+	defer util.GoRecover(ctx, fmt.Sprintf("<%s> suggestion provider panic", pluginInstance.Metadata.Name))
+
+	for _, provider := range pluginInstance.SuggestionProviders {
+		for _, suggestion := range provider(ctx, query) {
+			suggestion.Description = m.translatePlugin(ctx, pluginInstance, suggestion.Description)
+			if suggestion.Icon.IsEmpty() {
+				suggestion.Icon = ParseWoxImageOrDefault(pluginInstance.Metadata.Icon, DefaultActionIcon)
+			}
+			suggestion.Icon = ConvertIcon(ctx, suggestion.Icon, pluginInstance.PluginDirectory)
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
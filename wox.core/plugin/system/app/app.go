@@ -15,6 +15,7 @@ import (
 	"wox/setting/definition"
 	"wox/util"
 	"wox/util/clipboard"
+	"wox/util/window"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/google/uuid"
@@ -160,6 +161,11 @@ func (a *ApplicationPlugin) Query(ctx context.Context, query plugin.Query) []plu
 						Name: "i18n:plugin_app_open",
 						Icon: plugin.OpenIcon,
 						Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+							// focus the existing window instead of launching a new instance if app is running
+							if info.IsRunning() && window.ActivateWindowByPid(info.Pid) {
+								return
+							}
+
 							runErr := util.ShellOpen(info.Path)
 							if runErr != nil {
 								a.api.Log(ctx, plugin.LogLevelError, fmt.Sprintf("error opening app %s: %s", info.Path, runErr.Error()))
@@ -3,10 +3,12 @@ package app
 import (
 	"context"
 	"testing"
+	"time"
 	"wox/ai"
 	"wox/plugin"
 	"wox/share"
 	"wox/util"
+	"wox/util/selection"
 
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +38,10 @@ func (e emptyAPIImpl) GetTranslation(ctx context.Context, key string) string {
 	return ""
 }
 
+func (e emptyAPIImpl) FormatRelativeTime(ctx context.Context, t time.Time) string {
+	return ""
+}
+
 func (e emptyAPIImpl) GetSetting(ctx context.Context, key string) string {
 	return ""
 }
@@ -52,9 +58,95 @@ func (e emptyAPIImpl) OnDeepLink(ctx context.Context, callback func(arguments ma
 func (e emptyAPIImpl) OnUnload(ctx context.Context, callback func()) {
 }
 
+func (e emptyAPIImpl) OnQuerySessionStart(ctx context.Context, callback func()) {
+}
+
+func (e emptyAPIImpl) OnQuerySessionEnd(ctx context.Context, callback func()) {
+}
+
+func (e emptyAPIImpl) OnStartup(ctx context.Context, callback func(ctx context.Context) error) {
+}
+
+func (e emptyAPIImpl) RegisterQueryVariable(ctx context.Context, name string, resolver plugin.QueryVariableResolver) {
+}
+
+func (e emptyAPIImpl) ReportInitError(ctx context.Context, err error) {
+}
+
+func (e emptyAPIImpl) RegisterHeadlessAction(ctx context.Context, action plugin.HeadlessAction) {
+}
+
 func (e emptyAPIImpl) RegisterQueryCommands(ctx context.Context, commands []plugin.MetadataCommand) {
 }
 
+func (e emptyAPIImpl) RegisterSelectionHandler(ctx context.Context, handler plugin.SelectionHandler) {
+}
+
+func (e emptyAPIImpl) UpdateResult(ctx context.Context, resultId string, result plugin.RefreshableResult) bool {
+	return false
+}
+
+func (e emptyAPIImpl) UpdateResultScore(ctx context.Context, resultId string, score int64) bool {
+	return false
+}
+
+func (e emptyAPIImpl) SetStatus(ctx context.Context, text string) {
+}
+
+func (e emptyAPIImpl) ClearStatus(ctx context.Context) {
+}
+
+func (e emptyAPIImpl) ExecuteUIBatch(ctx context.Context, commands []share.UICommand) error {
+	return nil
+}
+
+func (e emptyAPIImpl) GetStore(ctx context.Context) *plugin.KVStore {
+	return nil
+}
+
+func (e emptyAPIImpl) GetSecrets(ctx context.Context) *plugin.SecretStore {
+	return nil
+}
+
+func (e emptyAPIImpl) RegisterStaticResults(ctx context.Context, results []plugin.StaticResult) {
+}
+
+func (e emptyAPIImpl) RegisterSuggestionProvider(ctx context.Context, provider plugin.SuggestionProvider) {
+}
+
+func (e emptyAPIImpl) RegisterPreviewEnricher(ctx context.Context, enricher plugin.PreviewEnricher) {
+}
+
+func (e emptyAPIImpl) GetHTTPClient(ctx context.Context) *util.RateLimitedHTTPClient {
+	return nil
+}
+
+func (e emptyAPIImpl) RequestPermission(ctx context.Context, permission plugin.MetadataPermission) error {
+	return nil
+}
+
+func (e emptyAPIImpl) SendToPlugin(ctx context.Context, targetPluginId string, payload selection.Selection, contextData string) error {
+	return nil
+}
+
+func (e emptyAPIImpl) CarryContext(ctx context.Context, contextData string) {
+}
+
+func (e emptyAPIImpl) ReportQueryProgress(ctx context.Context, progress int, text string) {
+}
+
+func (e emptyAPIImpl) PickFile(ctx context.Context, options plugin.PickFileOptions) ([]string, error) {
+	return nil, nil
+}
+
+func (e emptyAPIImpl) PickFolder(ctx context.Context, options plugin.PickFolderOptions) (string, error) {
+	return "", nil
+}
+
+func (e emptyAPIImpl) ShowDialog(ctx context.Context, spec share.DialogSpec) (map[string]string, error) {
+	return nil, share.ErrDialogCancelled
+}
+
 func (e emptyAPIImpl) AIChatStream(ctx context.Context, model ai.Model, conversations []ai.Conversation, callback ai.ChatStreamFunc) error {
 	return nil
 }
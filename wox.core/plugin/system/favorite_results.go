@@ -0,0 +1,115 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"wox/i18n"
+	"wox/plugin"
+	"wox/setting"
+
+	"github.com/samber/lo"
+)
+
+var favoriteResultsIcon = plugin.NewWoxImageEmoji("⭐")
+
+func init() {
+	plugin.AllSystemPlugin = append(plugin.AllSystemPlugin, &FavoriteResultsPlugin{})
+}
+
+type FavoriteResultsPlugin struct {
+	api plugin.API
+}
+
+func (f *FavoriteResultsPlugin) GetMetadata() plugin.Metadata {
+	return plugin.Metadata{
+		Id:            "bea34aa9-4f33-451f-9399-f916489f66f6",
+		Name:          "Wox Favorite Results",
+		Author:        "Wox Launcher",
+		Website:       "https://github.com/Wox-launcher/Wox",
+		Version:       "1.0.0",
+		MinWoxVersion: "2.0.0",
+		Runtime:       "Go",
+		Description:   "List and open your favorite results",
+		Icon:          favoriteResultsIcon.String(),
+		Entry:         "",
+		TriggerKeywords: []string{
+			"fav",
+		},
+		Features: []plugin.MetadataFeature{
+			{
+				Name: plugin.MetadataFeatureIgnoreAutoScore,
+			},
+			{
+				Name: plugin.MetadataFeatureIgnoreRecentResult,
+			},
+		},
+		SupportedOS: []string{
+			"Windows",
+			"Macos",
+			"Linux",
+		},
+	}
+}
+
+func (f *FavoriteResultsPlugin) Init(ctx context.Context, initParams plugin.InitParams) {
+	f.api = initParams.API
+}
+
+func (f *FavoriteResultsPlugin) Query(ctx context.Context, query plugin.Query) (results []plugin.QueryResult) {
+	favoriteResults := setting.GetSettingManager().GetFavoriteResults(ctx)
+	for index, favoriteResult := range favoriteResults {
+		if query.Search != "" {
+			isMatch, _ := IsStringMatchScore(ctx, favoriteResult.ResultTitle, query.Search)
+			if !isMatch {
+				continue
+			}
+		}
+
+		icon := favoriteResultsIcon
+		pluginName := favoriteResult.PluginId
+		pluginInstance, found := lo.Find(plugin.GetPluginManager().GetPluginInstances(), func(item *plugin.Instance) bool {
+			return item.Metadata.Id == favoriteResult.PluginId
+		})
+		if found {
+			icon = plugin.ParseWoxImageOrDefault(pluginInstance.Metadata.Icon, favoriteResultsIcon)
+			pluginName = pluginInstance.Metadata.Name
+		}
+
+		// results of disabled or uninstalled plugins are still listed so user can remove them, running them shows the reason
+		subTitle := pluginName
+		if !found {
+			subTitle = fmt.Sprintf("%s · %s", pluginName, i18n.GetI18nManager().TranslateWox(ctx, "plugin_favorite_results_plugin_not_found"))
+		} else if pluginInstance.Setting.Disabled {
+			subTitle = fmt.Sprintf("%s · %s", pluginName, i18n.GetI18nManager().TranslateWox(ctx, "plugin_favorite_results_plugin_disabled"))
+		} else if favoriteResult.ResultSubTitle != "" {
+			subTitle = fmt.Sprintf("%s · %s", pluginName, favoriteResult.ResultSubTitle)
+		}
+
+		results = append(results, plugin.QueryResult{
+			Title:    favoriteResult.ResultTitle,
+			SubTitle: subTitle,
+			Icon:     icon,
+			// keep the favorite order
+			Score: int64(len(favoriteResults) - index),
+			Actions: []plugin.QueryResultAction{
+				{
+					Name: "i18n:plugin_favorite_results_open",
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						if err := plugin.GetPluginManager().ExecuteFavoriteResult(ctx, favoriteResult); err != nil {
+							f.api.Notify(ctx, err.Error())
+						}
+					},
+				},
+				{
+					Name: "i18n:plugin_manager_remove_from_favorite",
+					Icon: plugin.RemoveFromFavIcon,
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						setting.GetSettingManager().RemoveFavoriteResult(ctx, favoriteResult)
+					},
+				},
+			},
+		})
+	}
+
+	return
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"wox/plugin"
 	"wox/setting/definition"
-	"wox/util"
+	"wox/util/selection"
 
 	"github.com/samber/lo"
 )
@@ -61,19 +61,24 @@ func (c *Plugin) Query(ctx context.Context, query plugin.Query) []plugin.QueryRe
 		return plugin.QueryResult{
 			Title:    item.Name,
 			SubTitle: item.Path,
-			Icon:     fileIcon,
+			// file name at the end of the path is the informative part
+			SubTitleTruncation: plugin.QueryResultTruncationMiddle,
+			Icon:               fileIcon,
 			Actions: []plugin.QueryResultAction{
+				plugin.NewOpenAction(item.Path),
+				plugin.NewRevealAction(item.Path),
 				{
-					Name: "i18n:plugin_file_open",
+					Name: "i18n:plugin_file_send_to_plugin",
 					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
-						util.ShellOpen(item.Path)
-					},
-				},
-				{
-					Name: "i18n:plugin_file_open_containing_folder",
-					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
-						util.ShellOpenFileInFolder(item.Path)
+						sendErr := c.api.SendToPlugin(ctx, "", selection.Selection{
+							Type:      selection.SelectionTypeFile,
+							FilePaths: []string{item.Path},
+						}, actionContext.ContextData)
+						if sendErr != nil {
+							c.api.Notify(ctx, sendErr.Error())
+						}
 					},
+					PreventHideAfterAction: true,
 				},
 			},
 		}
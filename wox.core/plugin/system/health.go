@@ -0,0 +1,158 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"wox/i18n"
+	"wox/plugin"
+	"wox/share"
+	"wox/util"
+)
+
+var healthIcon = plugin.PluginDoctorIcon
+
+func init() {
+	plugin.AllSystemPlugin = append(plugin.AllSystemPlugin, &HealthPlugin{})
+}
+
+// HealthPlugin lists plugins which errored, timed out or are disabled, so users can fix a misbehaving install without reading logs
+type HealthPlugin struct {
+	api plugin.API
+}
+
+func (h *HealthPlugin) GetMetadata() plugin.Metadata {
+	return plugin.Metadata{
+		Id:              "80a6d23b-a1b3-4d70-87d6-4ff05a443d76",
+		Name:            "Wox Health",
+		Author:          "Wox Launcher",
+		Website:         "https://github.com/Wox-launcher/Wox",
+		Version:         "1.0.0",
+		MinWoxVersion:   "2.0.0",
+		Runtime:         "Go",
+		Description:     "Show plugins which errored, timed out or are disabled",
+		Icon:            healthIcon.String(),
+		TriggerKeywords: []string{"health"},
+		SupportedOS:     []string{"Windows", "Macos", "Linux"},
+		Features: []plugin.MetadataFeature{
+			{
+				Name: plugin.MetadataFeatureIgnoreAutoScore,
+			},
+			{
+				Name: plugin.MetadataFeatureIgnoreRecentResult,
+			},
+		},
+	}
+}
+
+func (h *HealthPlugin) Init(ctx context.Context, initParams plugin.InitParams) {
+	h.api = initParams.API
+}
+
+func (h *HealthPlugin) Query(ctx context.Context, query plugin.Query) (results []plugin.QueryResult) {
+	healthList := plugin.GetPluginManager().GetPluginHealth()
+	if len(healthList) == 0 {
+		return []plugin.QueryResult{
+			{
+				Title: "i18n:plugin_health_all_healthy",
+				Icon:  plugin.CorrectIcon,
+			},
+		}
+	}
+
+	for index, health := range healthList {
+		if query.Search != "" {
+			if isMatch, _ := IsStringMatchScore(ctx, health.PluginName, query.Search); !isMatch {
+				continue
+			}
+		}
+
+		icon := plugin.ErrorIcon
+		if health.Instance != nil {
+			icon = plugin.ParseWoxImageOrDefault(health.Instance.Metadata.Icon, plugin.ErrorIcon)
+		}
+
+		results = append(results, plugin.QueryResult{
+			Title:    health.PluginName,
+			SubTitle: h.getHealthSummary(ctx, health),
+			Icon:     icon,
+			Preview:  h.getErrorsPreview(health),
+			// keep the order of health list, most errors first
+			Score:   int64(len(healthList) - index),
+			Actions: h.getHealthActions(health),
+		})
+	}
+
+	return results
+}
+
+func (h *HealthPlugin) getHealthSummary(ctx context.Context, health plugin.PluginHealth) string {
+	var problems []string
+	if health.Disabled {
+		problems = append(problems, i18n.GetI18nManager().TranslateWox(ctx, "plugin_health_disabled"))
+	}
+	if health.LoadFailed {
+		problems = append(problems, i18n.GetI18nManager().TranslateWox(ctx, "plugin_health_load_failed"))
+	}
+	if health.InitError != "" {
+		problems = append(problems, i18n.GetI18nManager().TranslateWox(ctx, "plugin_health_init_failed"))
+	}
+	if health.ErrorCount > 0 {
+		problems = append(problems, fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_health_errors"), health.ErrorCount))
+	}
+	if health.TimeoutCount > 0 {
+		problems = append(problems, fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_health_timeouts"), health.TimeoutCount))
+	}
+	return strings.Join(problems, " · ")
+}
+
+func (h *HealthPlugin) getErrorsPreview(health plugin.PluginHealth) plugin.WoxPreview {
+	if len(health.RecentErrors) == 0 {
+		return plugin.WoxPreview{}
+	}
+
+	var sb strings.Builder
+	for _, record := range health.RecentErrors {
+		sb.WriteString(fmt.Sprintf("- `%s` **%s** %s\n", util.FormatTimestamp(record.Timestamp), record.Kind, record.Message))
+	}
+	return plugin.WoxPreview{
+		PreviewType: plugin.WoxPreviewTypeMarkdown,
+		PreviewData: sb.String(),
+	}
+}
+
+func (h *HealthPlugin) getHealthActions(health plugin.PluginHealth) (actions []plugin.QueryResultAction) {
+	if health.Disabled {
+		actions = append(actions, plugin.QueryResultAction{
+			Name: "i18n:plugin_health_enable_plugin",
+			Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+				if err := plugin.GetPluginManager().EnablePlugin(ctx, health.Instance); err != nil {
+					h.api.Notify(ctx, err.Error())
+				}
+			},
+		})
+	}
+	if len(health.RecentErrors) > 0 {
+		actions = append(actions, plugin.QueryResultAction{
+			Name: "i18n:plugin_health_reset_errors",
+			Icon: plugin.TrashIcon,
+			Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+				plugin.GetPluginManager().ResetPluginErrors(health.PluginId)
+			},
+		})
+	}
+	if health.Instance != nil {
+		actions = append(actions, plugin.QueryResultAction{
+			Name: "i18n:plugin_health_open_settings",
+			Icon: plugin.SettingIcon,
+			Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+				plugin.GetPluginManager().GetUI().OpenSettingWindow(ctx, share.SettingWindowContext{
+					Path:  "/plugin/setting",
+					Param: health.PluginName,
+				})
+			},
+			PreventHideAfterAction: true,
+		})
+	}
+	return actions
+}
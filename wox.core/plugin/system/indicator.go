@@ -60,7 +60,7 @@ func (i *IndicatorPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 				Title:    triggerKeyword,
 				SubTitle: fmt.Sprintf(i18n.GetI18nManager().TranslateWox(ctx, "plugin_indicator_activate_plugin"), pluginInstance.Metadata.Name),
 				Score:    10,
-				Icon:     pluginInstance.Metadata.GetIconOrDefault(pluginInstance.PluginDirectory, indicatorIcon),
+				Icon:     pluginInstance.Metadata.GetIconOrDefault(ctx, pluginInstance.PluginDirectory, indicatorIcon),
 				Actions: []plugin.QueryResultAction{
 					{
 						Name:                   "i18n:plugin_indicator_activate",
@@ -82,7 +82,7 @@ func (i *IndicatorPlugin) Query(ctx context.Context, query plugin.Query) []plugi
 					Title:    fmt.Sprintf("%s %s ", triggerKeyword, metadataCommand.Command),
 					SubTitle: metadataCommand.Description,
 					Score:    10,
-					Icon:     pluginInstance.Metadata.GetIconOrDefault(pluginInstance.PluginDirectory, indicatorIcon),
+					Icon:     pluginInstance.Metadata.GetIconOrDefault(ctx, pluginInstance.PluginDirectory, indicatorIcon),
 					Actions: []plugin.QueryResultAction{
 						{
 							Name:                   "i18n:plugin_indicator_activate",
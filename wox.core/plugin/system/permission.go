@@ -0,0 +1,130 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"wox/plugin"
+
+	"github.com/samber/lo"
+)
+
+var permissionIcon = plugin.NewWoxImageEmoji("🔐")
+
+func init() {
+	plugin.AllSystemPlugin = append(plugin.AllSystemPlugin, &PermissionPlugin{})
+}
+
+// PermissionPlugin lets user allow or deny permissions requested by plugins, see plugin.Manager.CheckPermission
+type PermissionPlugin struct {
+	api plugin.API
+}
+
+func (p *PermissionPlugin) GetMetadata() plugin.Metadata {
+	return plugin.Metadata{
+		Id:            "5f0f6c71-3b43-4c8e-9b37-9a7f1c6f2c1e",
+		Name:          "Wox Plugin Permissions",
+		Author:        "Wox Launcher",
+		Website:       "https://github.com/Wox-launcher/Wox",
+		Version:       "1.0.0",
+		MinWoxVersion: "2.0.0",
+		Runtime:       "Go",
+		Description:   "Allow or deny permissions requested by plugins",
+		Icon:          permissionIcon.String(),
+		Entry:         "",
+		TriggerKeywords: []string{
+			"permission",
+		},
+		Features: []plugin.MetadataFeature{
+			{
+				Name: plugin.MetadataFeatureIgnoreAutoScore,
+			},
+			{
+				Name: plugin.MetadataFeatureIgnoreRecentResult,
+			},
+		},
+		SupportedOS: []string{
+			"Windows",
+			"Macos",
+			"Linux",
+		},
+	}
+}
+
+func (p *PermissionPlugin) Init(ctx context.Context, initParams plugin.InitParams) {
+	p.api = initParams.API
+}
+
+func (p *PermissionPlugin) Query(ctx context.Context, query plugin.Query) (results []plugin.QueryResult) {
+	// pending requests first
+	for _, request := range plugin.GetPluginManager().GetPermissionRequests() {
+		pluginInstance := request.PluginInstance
+		permission := request.Permission
+		if !p.isMatch(ctx, pluginInstance, query) {
+			continue
+		}
+
+		results = append(results, plugin.QueryResult{
+			Title:    fmt.Sprintf("%s: %s", pluginInstance.Metadata.Name, permission),
+			SubTitle: "i18n:plugin_permission_pending",
+			Icon:     pluginInstance.Metadata.GetIconOrDefault(ctx, pluginInstance.PluginDirectory, permissionIcon),
+			Score:    1000,
+			Actions: []plugin.QueryResultAction{
+				{
+					Name: "i18n:plugin_permission_allow",
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						p.grant(ctx, pluginInstance, permission, true)
+					},
+				},
+				{
+					Name: "i18n:plugin_permission_deny",
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						p.grant(ctx, pluginInstance, permission, false)
+					},
+				},
+			},
+		})
+	}
+
+	for _, pluginInstance := range plugin.GetPluginManager().GetPluginInstances() {
+		if pluginInstance.Setting.PermissionGrants == nil || !p.isMatch(ctx, pluginInstance, query) {
+			continue
+		}
+
+		pluginInstance.Setting.PermissionGrants.Range(func(permission string, granted bool) bool {
+			subTitle := lo.Ternary(granted, "i18n:plugin_permission_granted", "i18n:plugin_permission_denied")
+			results = append(results, plugin.QueryResult{
+				Title:    fmt.Sprintf("%s: %s", pluginInstance.Metadata.Name, permission),
+				SubTitle: subTitle,
+				Icon:     pluginInstance.Metadata.GetIconOrDefault(ctx, pluginInstance.PluginDirectory, permissionIcon),
+				Actions: []plugin.QueryResultAction{
+					{
+						Name:                   "i18n:plugin_permission_revoke",
+						PreventHideAfterAction: true,
+						Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+							if err := plugin.GetPluginManager().RevokePermission(ctx, pluginInstance, permission); err != nil {
+								p.api.Notify(ctx, err.Error())
+							}
+						},
+					},
+				},
+			})
+			return true
+		})
+	}
+
+	return
+}
+
+func (p *PermissionPlugin) isMatch(ctx context.Context, pluginInstance *plugin.Instance, query plugin.Query) bool {
+	if query.Search == "" {
+		return true
+	}
+	isMatch, _ := IsStringMatchScore(ctx, pluginInstance.Metadata.Name, query.Search)
+	return isMatch
+}
+
+func (p *PermissionPlugin) grant(ctx context.Context, pluginInstance *plugin.Instance, permission plugin.MetadataPermission, granted bool) {
+	if err := plugin.GetPluginManager().GrantPermission(ctx, pluginInstance, permission, granted); err != nil {
+		p.api.Notify(ctx, err.Error())
+	}
+}
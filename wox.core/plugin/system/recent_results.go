@@ -0,0 +1,105 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"wox/plugin"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+var recentResultsIcon = plugin.NewWoxImageEmoji("🕘")
+
+func init() {
+	plugin.AllSystemPlugin = append(plugin.AllSystemPlugin, &RecentResultsPlugin{})
+}
+
+type RecentResultsPlugin struct {
+	api plugin.API
+}
+
+func (r *RecentResultsPlugin) GetMetadata() plugin.Metadata {
+	return plugin.Metadata{
+		Id:            "7ce81d50-6f03-4dbb-a0da-d74f479da0c7",
+		Name:          "Wox Recent Results",
+		Author:        "Wox Launcher",
+		Website:       "https://github.com/Wox-launcher/Wox",
+		Version:       "1.0.0",
+		MinWoxVersion: "2.0.0",
+		Runtime:       "Go",
+		Description:   "Re-open results you actioned recently",
+		Icon:          recentResultsIcon.String(),
+		Entry:         "",
+		TriggerKeywords: []string{
+			"recent",
+		},
+		Features: []plugin.MetadataFeature{
+			{
+				Name: plugin.MetadataFeatureIgnoreAutoScore,
+			},
+			{
+				Name: plugin.MetadataFeatureIgnoreRecentResult,
+			},
+		},
+		SupportedOS: []string{
+			"Windows",
+			"Macos",
+			"Linux",
+		},
+	}
+}
+
+func (r *RecentResultsPlugin) Init(ctx context.Context, initParams plugin.InitParams) {
+	r.api = initParams.API
+}
+
+func (r *RecentResultsPlugin) Query(ctx context.Context, query plugin.Query) (results []plugin.QueryResult) {
+	recentResults := setting.GetSettingManager().GetRecentResults(ctx)
+	for index, recentResult := range recentResults {
+		if query.Search != "" {
+			isMatch, _ := IsStringMatchScore(ctx, recentResult.ResultTitle, query.Search)
+			if !isMatch {
+				continue
+			}
+		}
+
+		icon := recentResultsIcon
+		pluginName := recentResult.PluginId
+		pluginInstance, found := lo.Find(plugin.GetPluginManager().GetPluginInstances(), func(item *plugin.Instance) bool {
+			return item.Metadata.Id == recentResult.PluginId
+		})
+		if found {
+			icon = plugin.ParseWoxImageOrDefault(pluginInstance.Metadata.Icon, recentResultsIcon)
+			pluginName = pluginInstance.Metadata.Name
+		}
+
+		results = append(results, plugin.QueryResult{
+			Title:    recentResult.ResultTitle,
+			SubTitle: fmt.Sprintf("%s · %s · %s", pluginName, recentResult.ActionName, util.FormatTimestamp(recentResult.Timestamp)),
+			Icon:     icon,
+			// keep the recent order
+			Score: int64(len(recentResults) - index),
+			Actions: []plugin.QueryResultAction{
+				{
+					Name: "i18n:plugin_recent_results_run_again",
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						if err := plugin.GetPluginManager().ExecuteRecentResult(ctx, recentResult); err != nil {
+							r.api.Notify(ctx, err.Error())
+						}
+					},
+				},
+				{
+					Name: "i18n:plugin_recent_results_clear",
+					Icon: plugin.TrashIcon,
+					Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+						setting.GetSettingManager().ClearRecentResults(ctx)
+					},
+				},
+			},
+		})
+	}
+
+	return
+}
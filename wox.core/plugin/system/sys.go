@@ -99,6 +99,21 @@ func (r *SysPlugin) Init(ctx context.Context, initParams plugin.InitParams) {
 				plugin.GetPluginManager().GetUI().OpenSettingWindow(ctx, share.DefaultSettingWindowContext)
 			},
 		},
+		{
+			Title:    "i18n:plugin_sys_reload_plugins",
+			SubTitle: "i18n:plugin_sys_reload_plugins_subtitle",
+			Icon:     plugin.PluginWPMIcon,
+			Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+				util.Go(ctx, "reload plugins", func() {
+					reloadErr := plugin.GetPluginManager().Reload(ctx)
+					if reloadErr != nil {
+						r.api.Notify(ctx, reloadErr.Error())
+						return
+					}
+					r.api.Notify(ctx, "i18n:plugin_sys_reload_plugins_success")
+				})
+			},
+		},
 		{
 			Title: "i18n:plugin_sys_open_system_settings",
 			Icon:  plugin.SettingIcon,
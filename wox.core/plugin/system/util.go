@@ -34,28 +34,36 @@ var pinyinMatchCache = util.NewHashMap[string, cacheResult]()
 var windowIconCache = util.NewHashMap[string, plugin.WoxImage]()
 
 func IsStringMatchScore(ctx context.Context, term string, subTerm string) (bool, int64) {
+	if util.IsLiteralMatchContext(ctx) {
+		return util.IsStringMatchScoreLiteral(term, subTerm)
+	}
+
 	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	matchOptions := util.GetMatchOptionsContext(ctx)
+	// plugins may use different match options (see plugin.MetadataFeatureMatchSensitivity), and results differ with and without pinyin
+	key := fmt.Sprintf("%s\x00%s\x00%t\x00%s\x00%d", term, subTerm, woxSetting.UsePinYin, matchOptions.Sensitivity, matchOptions.MinScore)
 	if woxSetting.UsePinYin {
-		key := term + subTerm
 		if result, ok := pinyinMatchCache.Load(key); ok {
 			return result.match, result.score
 		}
 	}
 
-	match, score := util.IsStringMatchScore(term, subTerm, woxSetting.UsePinYin)
+	match, score := util.IsStringMatchScoreWithOptions(term, subTerm, woxSetting.UsePinYin, matchOptions)
 	if woxSetting.UsePinYin {
-		key := term + subTerm
 		pinyinMatchCache.Store(key, cacheResult{match, score})
 	}
 	return match, score
 }
 
 func IsStringMatchScoreNoPinYin(ctx context.Context, term string, subTerm string) (bool, int64) {
-	return util.IsStringMatchScore(term, subTerm, false)
+	if util.IsLiteralMatchContext(ctx) {
+		return util.IsStringMatchScoreLiteral(term, subTerm)
+	}
+	return util.IsStringMatchScoreWithOptions(term, subTerm, false, util.GetMatchOptionsContext(ctx))
 }
 
 func IsStringMatchNoPinYin(ctx context.Context, term string, subTerm string) bool {
-	match, _ := util.IsStringMatchScore(term, subTerm, false)
+	match, _ := IsStringMatchScoreNoPinYin(ctx, term, subTerm)
 	return match
 }
 
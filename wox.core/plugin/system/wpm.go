@@ -512,7 +512,10 @@ func (w *WPMPlugin) reloadDevCommand(ctx context.Context) []plugin.QueryResult {
 
 func (w *WPMPlugin) addDevCommand(ctx context.Context, query plugin.Query) []plugin.QueryResult {
 	w.api.Log(ctx, plugin.LogLevelInfo, "Please choose a directory to add local plugin")
-	pluginDirectories := plugin.GetPluginManager().GetUI().PickFiles(ctx, share.PickFilesParams{IsDirectory: true})
+	pluginDirectories, pickErr := plugin.GetPluginManager().GetUI().PickFiles(ctx, share.PickFilesParams{IsDirectory: true})
+	if pickErr != nil {
+		w.api.Log(ctx, plugin.LogLevelError, fmt.Sprintf("Failed to pick directory: %s", pickErr.Error()))
+	}
 	if len(pluginDirectories) == 0 {
 		w.api.Notify(ctx, "i18n:plugin_wpm_choose_directory")
 		return []plugin.QueryResult{}
@@ -579,7 +582,10 @@ func (w *WPMPlugin) createPlugin(ctx context.Context, template pluginTemplate, p
 	}
 
 	w.creatingProcess = "i18n:plugin_wpm_choose_directory_prompt"
-	pluginDirectories := plugin.GetPluginManager().GetUI().PickFiles(ctx, share.PickFilesParams{IsDirectory: true})
+	pluginDirectories, pickErr := plugin.GetPluginManager().GetUI().PickFiles(ctx, share.PickFilesParams{IsDirectory: true})
+	if pickErr != nil {
+		w.api.Log(ctx, plugin.LogLevelError, fmt.Sprintf("Failed to pick directory: %s", pickErr.Error()))
+	}
 	if len(pluginDirectories) == 0 {
 		w.api.Notify(ctx, "You need to choose a directory to create the plugin")
 		return
@@ -681,7 +687,7 @@ func (w *WPMPlugin) reloadLocalDistPlugin(ctx context.Context, localPlugin plugi
 	distPluginMetadata.IsDev = true
 	distPluginMetadata.DevPluginDirectory = localPlugin.Directory
 
-	reloadErr := plugin.GetPluginManager().ReloadPlugin(ctx, distPluginMetadata)
+	reloadErr := plugin.GetPluginManager().ReloadPluginWithMetadata(ctx, distPluginMetadata)
 	if reloadErr != nil {
 		w.api.Log(ctx, plugin.LogLevelError, fmt.Sprintf("Failed to reload plugin: %s", reloadErr.Error()))
 		return reloadErr
@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"strings"
+)
+
+// findTriggerKeywordPlugin returns the plugin whose trigger keyword matches keyword most specifically, so the result doesn't depend on
+// the order plugins are loaded when several plugins have overlapping keywords (E.g. "g", "gh" and "git", or "git" of two plugins):
+//  1. a keyword with the same case wins over a case-insensitive match, see PluginSetting.CaseInsensitiveTriggerKeywords
+//  2. a longer keyword wins
+//  3. ties are broken by plugin id
+//
+// Only plugins accepted by filter are considered, filter can be nil
+func findTriggerKeywordPlugin(pluginInstances []*Instance, keyword string, filter func(instance *Instance) bool) (*Instance, string, bool) {
+	var best *Instance
+	var bestKeyword string
+	for _, instance := range pluginInstances {
+		if filter != nil && !filter(instance) {
+			continue
+		}
+		matchedKeyword, matched := instance.MatchTriggerKeyword(keyword)
+		if !matched {
+			continue
+		}
+		if best == nil || isMoreSpecificTriggerKeyword(keyword, instance, matchedKeyword, best, bestKeyword) {
+			best, bestKeyword = instance, matchedKeyword
+		}
+	}
+
+	return best, bestKeyword, best != nil
+}
+
+func isMoreSpecificTriggerKeyword(typed string, instance *Instance, keyword string, other *Instance, otherKeyword string) bool {
+	if exact, otherExact := keyword == typed, otherKeyword == typed; exact != otherExact {
+		return exact
+	}
+	if len(keyword) != len(otherKeyword) {
+		return len(keyword) > len(otherKeyword)
+	}
+	return strings.Compare(instance.Metadata.Id, other.Metadata.Id) < 0
+}
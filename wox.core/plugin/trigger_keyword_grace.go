@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"wox/share"
+	"wox/util"
+)
+
+// triggerKeywordGrace keeps queries triggered by the plugin of the previous query for a while after user deleted (part of) its
+// trigger keyword, see Manager.applyTriggerKeywordGrace
+type triggerKeywordGrace struct {
+	lock  sync.Mutex
+	last  *triggeredQuery
+	timer *time.Timer // reruns a kept query as global query when grace period is over
+}
+
+// triggeredQuery is the latest input query with a trigger keyword
+type triggeredQuery struct {
+	pluginInstance *Instance
+	keyword        string // trigger keyword as typed by user
+	rest           string // raw query after the trigger keyword, E.g. "issues foo" of "gh issues foo"
+	timestamp      int64
+}
+
+// applyTriggerKeywordGrace keeps query triggered by the plugin of the last triggered query when user deleted (part of) its trigger keyword
+// and nothing else (E.g. "gh issues foo" => "g issues foo"), so results of the plugin don't vanish abruptly while user is editing.
+// The grace period is graceMs after the last triggered query, queries typed later are global queries as usual. When grace period is over
+// the kept query is rerun as global query, typing another query or hiding Wox cancels the rerun. graceMs <= 0 disables it
+func (m *Manager) applyTriggerKeywordGrace(ctx context.Context, query Query, instance *Instance, graceMs int64) (Query, *Instance) {
+	m.keywordGrace.lock.Lock()
+	defer m.keywordGrace.lock.Unlock()
+
+	if m.keywordGrace.timer != nil {
+		m.keywordGrace.timer.Stop()
+		m.keywordGrace.timer = nil
+	}
+
+	now := util.GetSystemTimestamp()
+	if instance != nil && query.TriggerKeyword != "" {
+		keyword, rest, _ := strings.Cut(query.RawQuery, " ")
+		m.keywordGrace.last = &triggeredQuery{pluginInstance: instance, keyword: keyword, rest: rest, timestamp: now}
+		return query, instance
+	}
+
+	last := m.keywordGrace.last
+	if graceMs <= 0 || last == nil || last.pluginInstance.Setting.Disabled || now-last.timestamp > graceMs {
+		return query, instance
+	}
+	if !isTriggerKeywordDeleted(last.keyword, last.rest, query.RawQuery) {
+		return query, instance
+	}
+
+	keptQuery, keptInstance := newQueryInputWithPlugins(last.keyword+" "+last.rest, []*Instance{last.pluginInstance})
+	if keptInstance == nil {
+		return query, instance
+	}
+	keptQuery.RawQuery = query.RawQuery
+	keptQuery.Env = query.Env
+
+	rawQuery := query.RawQuery
+	remaining := time.Duration(graceMs-(now-last.timestamp)) * time.Millisecond
+	logger.Info(ctx, fmt.Sprintf("[%s] trigger keyword deleted, keep plugin for %d ms: %s", last.pluginInstance.Metadata.Name, remaining.Milliseconds(), rawQuery))
+	var timer *time.Timer
+	timer = time.AfterFunc(remaining, func() {
+		m.keywordGrace.lock.Lock()
+		isCurrent := m.keywordGrace.timer == timer
+		m.keywordGrace.timer = nil
+		m.keywordGrace.lock.Unlock()
+		if !isCurrent {
+			return
+		}
+
+		rerunCtx := util.NewTraceContext()
+		logger.Info(rerunCtx, fmt.Sprintf("trigger keyword grace period is over, rerun as global query: %s", rawQuery))
+		m.GetUI().ChangeQuery(rerunCtx, share.PlainQuery{QueryType: QueryTypeInput, QueryText: rawQuery})
+	})
+	m.keywordGrace.timer = timer
+
+	return keptQuery, keptInstance
+}
+
+// cancelTriggerKeywordGrace forgets the last triggered query and cancels the pending rerun, E.g. when Wox is hidden
+func (m *Manager) cancelTriggerKeywordGrace() {
+	m.keywordGrace.lock.Lock()
+	defer m.keywordGrace.lock.Unlock()
+
+	if m.keywordGrace.timer != nil {
+		m.keywordGrace.timer.Stop()
+		m.keywordGrace.timer = nil
+	}
+	m.keywordGrace.last = nil
+}
+
+// isTriggerKeywordDeleted returns true if rawQuery is the triggered query "<keyword> <rest>" with (part of) keyword deleted and nothing else changed
+func isTriggerKeywordDeleted(keyword string, rest string, rawQuery string) bool {
+	if rawQuery == "" || !strings.HasSuffix(rawQuery, rest) {
+		return false
+	}
+
+	edited := strings.TrimSpace(strings.TrimSuffix(rawQuery, rest))
+	return edited != keyword && strings.HasPrefix(keyword, edited)
+}
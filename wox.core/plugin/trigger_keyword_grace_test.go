@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"wox/setting"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTriggerKeywordDeleted(t *testing.T) {
+	assert.True(t, isTriggerKeywordDeleted("gh", "issues foo", "g issues foo"))
+	assert.True(t, isTriggerKeywordDeleted("gh", "issues foo", " issues foo"))
+	assert.True(t, isTriggerKeywordDeleted("gh", "issues foo", "issues foo"))
+	assert.True(t, isTriggerKeywordDeleted("gh", "", "g"))
+
+	// search is edited too
+	assert.False(t, isTriggerKeywordDeleted("gh", "issues foo", "g issues fo"))
+	// keyword is changed instead of deleted
+	assert.False(t, isTriggerKeywordDeleted("gh", "issues foo", "gx issues foo"))
+	assert.False(t, isTriggerKeywordDeleted("gh", "issues foo", "gh issues foo"))
+	// query is cleared
+	assert.False(t, isTriggerKeywordDeleted("gh", "", ""))
+}
+
+func TestApplyTriggerKeywordGrace(t *testing.T) {
+	logger = util.GetLogger()
+	gh := &Instance{Metadata: Metadata{Id: "plugin-gh", TriggerKeywords: []string{"gh"}}, Setting: &setting.PluginSetting{}}
+	instances := []*Instance{gh}
+	m := &Manager{}
+	defer m.cancelTriggerKeywordGrace()
+
+	parse := func(raw string, graceMs int64) (Query, *Instance) {
+		query, instance := newQueryInputWithPlugins(raw, instances)
+		return m.applyTriggerKeywordGrace(context.Background(), query, instance, graceMs)
+	}
+
+	query, instance := parse("gh issues foo", 60000)
+	assert.Equal(t, gh, instance)
+
+	// within grace period, the plugin is kept
+	query, instance = parse("g issues foo", 60000)
+	assert.Equal(t, gh, instance)
+	assert.Equal(t, "gh", query.TriggerKeyword)
+	assert.Equal(t, "issues foo", query.Search)
+	assert.Equal(t, "g issues foo", query.RawQuery)
+
+	// other edits switch to global query
+	query, instance = parse("g issues fo", 60000)
+	assert.Nil(t, instance)
+	assert.Equal(t, "", query.TriggerKeyword)
+
+	// disabled by default
+	parse("gh issues foo", 0)
+	_, instance = parse("g issues foo", 0)
+	assert.Nil(t, instance)
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"wox/util"
+)
+
+// max time startup callbacks of a plugin can take, see API.OnStartup
+const pluginWarmUpTimeout = 60 * time.Second
+
+type PluginWarmUpState = string
+
+const (
+	PluginWarmUpStateRunning PluginWarmUpState = "running"
+	PluginWarmUpStateDone    PluginWarmUpState = "done"
+	PluginWarmUpStateFailed  PluginWarmUpState = "failed"
+	PluginWarmUpStateTimeout PluginWarmUpState = "timeout"
+)
+
+// PluginWarmUpStatus is the state of startup callbacks of a plugin, see Instance.GetWarmUpStatus
+type PluginWarmUpStatus struct {
+	State          PluginWarmUpState
+	Error          string // set if State is failed or timeout
+	StartTimestamp int64
+	CostMs         int64 // 0 while running
+}
+
+// warmUpPlugin runs startup callbacks of plugin in background after it's initialized, so plugins can warm caches
+// (E.g. build an index, authenticate) before the first query. Queries are served while warming up, plugin should handle that itself
+func (m *Manager) warmUpPlugin(ctx context.Context, instance *Instance) {
+	if len(instance.StartupCallbacks) == 0 || instance.Setting.Disabled {
+		return
+	}
+
+	util.Go(ctx, fmt.Sprintf("[%s] warm up plugin", instance.Metadata.Name), func() {
+		start := util.GetSystemTimestamp()
+		instance.warmUpStatus.Store(&PluginWarmUpStatus{State: PluginWarmUpStateRunning, StartTimestamp: start})
+
+		err := runStartupCallbacks(ctx, instance.StartupCallbacks, pluginWarmUpTimeout)
+		status := &PluginWarmUpStatus{State: PluginWarmUpStateDone, StartTimestamp: start, CostMs: util.GetSystemTimestamp() - start}
+		if errors.Is(err, context.DeadlineExceeded) {
+			status.State = PluginWarmUpStateTimeout
+			status.Error = err.Error()
+			logger.Warn(ctx, fmt.Sprintf("[%s] warm up timeout after %d ms", instance.Metadata.Name, status.CostMs))
+		} else if err != nil {
+			status.State = PluginWarmUpStateFailed
+			status.Error = err.Error()
+			logger.Error(ctx, fmt.Sprintf("[%s] warm up failed: %s", instance.Metadata.Name, err.Error()))
+		} else {
+			logger.Info(ctx, fmt.Sprintf("[%s] warm up finished, cost %d ms", instance.Metadata.Name, status.CostMs))
+		}
+		instance.warmUpStatus.Store(status)
+	})
+}
+
+// runStartupCallbacks runs callbacks one by one, errors of them are joined. A callback ignoring ctx keeps running in background
+// after timeout, but it won't be waited for
+func runStartupCallbacks(ctx context.Context, callbacks []func(ctx context.Context) error, timeout time.Duration) error {
+	warmUpCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var errs []error
+	for _, callback := range callbacks {
+		errChan := make(chan error, 1)
+		util.Go(warmUpCtx, "run startup callback", func() {
+			errChan <- callback(warmUpCtx)
+		}, func() {
+			errChan <- fmt.Errorf("startup callback panic")
+		})
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case <-warmUpCtx.Done():
+			return warmUpCtx.Err()
+		}
+	}
+
+	return errors.Join(errs...)
+}
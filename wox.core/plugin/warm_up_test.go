@@ -0,0 +1,38 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStartupCallbacks(t *testing.T) {
+	var called []int
+	err := runStartupCallbacks(context.Background(), []func(ctx context.Context) error{
+		func(ctx context.Context) error { called = append(called, 1); return nil },
+		func(ctx context.Context) error { called = append(called, 2); return errors.New("index not found") },
+		func(ctx context.Context) error { called = append(called, 3); return nil },
+	}, time.Second)
+	assert.Equal(t, []int{1, 2, 3}, called)
+	assert.ErrorContains(t, err, "index not found")
+}
+
+func TestRunStartupCallbacks_Timeout(t *testing.T) {
+	start := time.Now()
+	err := runStartupCallbacks(context.Background(), []func(ctx context.Context) error{
+		// ignores ctx, it must not block warm up
+		func(ctx context.Context) error { time.Sleep(time.Second); return nil },
+	}, 20*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRunStartupCallbacks_Panic(t *testing.T) {
+	err := runStartupCallbacks(context.Background(), []func(ctx context.Context) error{
+		func(ctx context.Context) error { panic("boom") },
+	}, time.Second)
+	assert.NotNil(t, err)
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"wox/i18n"
 	"wox/setting/definition"
@@ -15,6 +17,7 @@ import (
 	"wox/util/autostart"
 	"wox/util/hotkey"
 
+	"github.com/samber/lo"
 	"github.com/tidwall/pretty"
 )
 
@@ -25,6 +28,9 @@ var logger *util.Log
 type Manager struct {
 	woxSetting *WoxSetting
 	woxAppData *WoxAppData
+
+	// guards lists of woxAppData which are changed by actions running concurrently with queries, E.g. favorites and recent results
+	woxAppDataLock sync.RWMutex
 }
 
 func GetSettingManager() *Manager {
@@ -89,14 +95,13 @@ func (m *Manager) loadWoxSetting(ctx context.Context) error {
 		}
 	}
 
-	woxSettingFile, openErr := os.Open(woxSettingPath)
-	if openErr != nil {
-		return openErr
+	woxSettingJson, readErr := os.ReadFile(woxSettingPath)
+	if readErr != nil {
+		return readErr
 	}
-	defer woxSettingFile.Close()
 
 	woxSetting := &WoxSetting{}
-	decodeErr := json.NewDecoder(woxSettingFile).Decode(woxSetting)
+	decodeErr := json.Unmarshal(woxSettingJson, woxSetting)
 	if decodeErr != nil {
 		return decodeErr
 	}
@@ -113,12 +118,66 @@ func (m *Manager) loadWoxSetting(ctx context.Context) error {
 	if woxSetting.LastQueryMode == "" {
 		woxSetting.LastQueryMode = defaultWoxSetting.LastQueryMode
 	}
+	if woxSetting.EscapeMode == "" {
+		woxSetting.EscapeMode = defaultWoxSetting.EscapeMode
+	}
 	if woxSetting.AppWidth == 0 {
 		woxSetting.AppWidth = defaultWoxSetting.AppWidth
 	}
 	if woxSetting.ThemeId == "" {
 		woxSetting.ThemeId = defaultWoxSetting.ThemeId
 	}
+	if woxSetting.RestoreLastQueryWindowSeconds <= 0 {
+		woxSetting.RestoreLastQueryWindowSeconds = defaultWoxSetting.RestoreLastQueryWindowSeconds
+	}
+	if woxSetting.MaxRefreshPerSecond == 0 {
+		woxSetting.MaxRefreshPerSecond = defaultWoxSetting.MaxRefreshPerSecond
+	}
+	if woxSetting.HiddenResultRefreshInterval == 0 {
+		woxSetting.HiddenResultRefreshInterval = defaultWoxSetting.HiddenResultRefreshInterval
+	}
+	if woxSetting.MaxRefreshTimeout == 0 {
+		woxSetting.MaxRefreshTimeout = defaultWoxSetting.MaxRefreshTimeout
+	}
+	if woxSetting.MaxConcurrentQueriesPerPlugin == 0 {
+		woxSetting.MaxConcurrentQueriesPerPlugin = defaultWoxSetting.MaxConcurrentQueriesPerPlugin
+	}
+	// 0 is never saved (see UpdateWoxSetting), so it means the setting is missing and the soft deadline stays off
+	if woxSetting.QuerySoftDeadline == 0 {
+		woxSetting.QuerySoftDeadline = defaultWoxSetting.QuerySoftDeadline
+	}
+	if woxSetting.QueryCoalesceWindow == 0 {
+		woxSetting.QueryCoalesceWindow = defaultWoxSetting.QueryCoalesceWindow
+	}
+	if woxSetting.ResultFlushWindow == 0 {
+		woxSetting.ResultFlushWindow = defaultWoxSetting.ResultFlushWindow
+	}
+	if woxSetting.ResultFlushBatchSize == 0 {
+		woxSetting.ResultFlushBatchSize = defaultWoxSetting.ResultFlushBatchSize
+	}
+	if woxSetting.QueryTimeout <= 0 {
+		woxSetting.QueryTimeout = defaultWoxSetting.QueryTimeout
+	}
+	if woxSetting.MaxResultCacheSize == 0 {
+		woxSetting.MaxResultCacheSize = defaultWoxSetting.MaxResultCacheSize
+	}
+	if woxSetting.LiteralMatchPrefix == "" {
+		woxSetting.LiteralMatchPrefix = defaultWoxSetting.LiteralMatchPrefix
+	}
+	if woxSetting.BackgroundActionModifier == "" {
+		woxSetting.BackgroundActionModifier = defaultWoxSetting.BackgroundActionModifier
+	}
+	// nil means the setting is not saved yet, empty means user disabled all global actions
+	if woxSetting.GlobalActions == nil {
+		woxSetting.GlobalActions = defaultWoxSetting.GlobalActions
+	}
+	// false of bool settings which default to true can't be told apart from missing, so check whether they are saved
+	var savedKeys map[string]json.RawMessage
+	if json.Unmarshal(woxSettingJson, &savedKeys) == nil {
+		if _, saved := savedKeys["EnableRecentResults"]; !saved {
+			woxSetting.EnableRecentResults = defaultWoxSetting.EnableRecentResults
+		}
+	}
 
 	m.woxSetting = woxSetting
 
@@ -226,6 +285,19 @@ func (m *Manager) UpdateWoxSetting(ctx context.Context, key, value string) error
 		m.woxSetting.LangCode = newLangCode
 	} else if key == "LastQueryMode" {
 		m.woxSetting.LastQueryMode = value
+	} else if key == "RestoreLastQueryWindowSeconds" {
+		windowSeconds, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		m.woxSetting.RestoreLastQueryWindowSeconds = windowSeconds
+	} else if key == "RerunRestoredQuery" {
+		m.woxSetting.RerunRestoredQuery = value == "true"
+	} else if key == "EscapeMode" {
+		if value != EscapeModeHide && value != EscapeModeClearThenHide && value != EscapeModeHideThenClear {
+			return fmt.Errorf("unsupported escape mode: %s", value)
+		}
+		m.woxSetting.EscapeMode = value
 	} else if key == "ThemeId" {
 		m.woxSetting.ThemeId = value
 	} else if key == "QueryHotkeys" {
@@ -234,6 +306,11 @@ func (m *Manager) UpdateWoxSetting(ctx context.Context, key, value string) error
 		if unmarshalErr := json.Unmarshal([]byte(value), &queryHotkeys); unmarshalErr != nil {
 			return unmarshalErr
 		}
+		for _, queryHotkey := range queryHotkeys {
+			if strings.TrimSpace(queryHotkey.Query) == "" && strings.TrimSpace(queryHotkey.HeadlessAction) == "" {
+				return fmt.Errorf("query of hotkey %s must not be empty unless a headless action is set", queryHotkey.Hotkey)
+			}
+		}
 		m.woxSetting.QueryHotkeys.Set(queryHotkeys)
 	} else if key == "QueryShortcuts" {
 		// value is a json string
@@ -255,6 +332,158 @@ func (m *Manager) UpdateWoxSetting(ctx context.Context, key, value string) error
 		m.woxSetting.ShowPosition = PositionType(value)
 	} else if key == "EnableAutoBackup" {
 		m.woxSetting.EnableAutoBackup = value == "true"
+	} else if key == "MaxRefreshPerSecond" {
+		maxRefreshPerSecond, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		// 0 is replaced by default value when setting is loaded, negative means no limit
+		if maxRefreshPerSecond == 0 {
+			return fmt.Errorf("max refresh per second must not be 0, use a negative value for no limit")
+		}
+		m.woxSetting.MaxRefreshPerSecond = maxRefreshPerSecond
+	} else if key == "MaxConcurrentQueriesPerPlugin" {
+		maxConcurrentQueries, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		// 0 is replaced by default value when setting is loaded, negative means no limit
+		if maxConcurrentQueries == 0 {
+			return fmt.Errorf("max concurrent queries per plugin must not be 0, use a negative value for no limit")
+		}
+		m.woxSetting.MaxConcurrentQueriesPerPlugin = maxConcurrentQueries
+	} else if key == "HiddenResultRefreshInterval" {
+		interval, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if interval == 0 {
+			return fmt.Errorf("hidden result refresh interval must not be 0, use a negative value for no limit")
+		}
+		m.woxSetting.HiddenResultRefreshInterval = interval
+	} else if key == "MaxRefreshTimeout" {
+		timeout, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("max refresh timeout must be greater than 0")
+		}
+		m.woxSetting.MaxRefreshTimeout = timeout
+	} else if key == "MaxResultCacheSize" {
+		size, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if size == 0 {
+			return fmt.Errorf("max result cache size must not be 0, use a negative value to disable the limit")
+		}
+		m.woxSetting.MaxResultCacheSize = size
+	} else if key == "LiteralMatchPrefix" {
+		if value == "" || strings.ContainsAny(value, " \t") {
+			return fmt.Errorf("literal match prefix must not be empty or contain spaces")
+		}
+		m.woxSetting.LiteralMatchPrefix = value
+	} else if key == "QuerySoftDeadline" {
+		deadline, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if deadline == 0 {
+			return fmt.Errorf("query soft deadline must not be 0")
+		}
+		m.woxSetting.QuerySoftDeadline = deadline
+	} else if key == "TriggerKeywordDeletedGrace" {
+		grace, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if grace < 0 {
+			return fmt.Errorf("trigger keyword deleted grace must not be negative")
+		}
+		m.woxSetting.TriggerKeywordDeletedGrace = grace
+	} else if key == "QueryCoalesceWindow" {
+		window, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if window == 0 {
+			return fmt.Errorf("query coalesce window must not be 0")
+		}
+		m.woxSetting.QueryCoalesceWindow = window
+	} else if key == "ResultFlushWindow" {
+		window, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if window == 0 {
+			return fmt.Errorf("result flush window must not be 0")
+		}
+		m.woxSetting.ResultFlushWindow = window
+	} else if key == "ResultFlushBatchSize" {
+		batchSize, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if batchSize == 0 {
+			return fmt.Errorf("result flush batch size must not be 0")
+		}
+		m.woxSetting.ResultFlushBatchSize = batchSize
+	} else if key == "QueryTimeout" {
+		timeout, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return parseErr
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("query timeout must be greater than 0")
+		}
+		m.woxSetting.QueryTimeout = timeout
+	} else if key == "GlobalActions" {
+		// value is a json string
+		globalActions := []GlobalAction{}
+		if unmarshalErr := json.Unmarshal([]byte(value), &globalActions); unmarshalErr != nil {
+			return unmarshalErr
+		}
+		m.woxSetting.GlobalActions = globalActions
+	} else if key == "ActionPreferences" {
+		// value is a json string
+		actionPreferences := []ActionPreference{}
+		if unmarshalErr := json.Unmarshal([]byte(value), &actionPreferences); unmarshalErr != nil {
+			return unmarshalErr
+		}
+		m.woxSetting.ActionPreferences = actionPreferences
+	} else if key == "EnableRecentResults" {
+		m.woxSetting.EnableRecentResults = value == "true"
+		if !m.woxSetting.EnableRecentResults {
+			m.ClearRecentResults(ctx)
+		}
+	} else if key == "BackgroundActionModifier" {
+		modifier := strings.ToLower(strings.TrimSpace(value))
+		if !lo.Contains([]string{"cmd", "win", "ctrl", "alt", "option", "shift", BackgroundActionModifierNone}, modifier) {
+			return fmt.Errorf("unknown background action modifier: %s", value)
+		}
+		m.woxSetting.BackgroundActionModifier = modifier
+	} else if key == "HideActionHotkeyHints" {
+		m.woxSetting.HideActionHotkeyHints = value == "true"
+	} else if key == "EnablePreviewPeek" {
+		m.woxSetting.EnablePreviewPeek = value == "true"
+	} else if key == "MixGlobalResultsInTriggeredQuery" {
+		m.woxSetting.MixGlobalResultsInTriggeredQuery = value == "true"
+	} else if key == "GroupResultsByPlugin" {
+		m.woxSetting.GroupResultsByPlugin = value == "true"
+	} else if key == "StrictResultValidation" {
+		m.woxSetting.StrictResultValidation = value == "true"
+	} else if key == "EnableQueryDebug" {
+		m.woxSetting.EnableQueryDebug = value == "true"
+	} else if key == "EnableAnalytics" {
+		m.woxSetting.EnableAnalytics = value == "true"
+	} else if key == "CustomBrowserPath" {
+		m.woxSetting.CustomBrowserPath.Set(value)
+	} else if key == "TerminalCommand" {
+		if value != "" && !strings.Contains(value, util.TerminalCommandPlaceholder) {
+			return fmt.Errorf("terminal command must contain %s placeholder", util.TerminalCommandPlaceholder)
+		}
+		m.woxSetting.TerminalCommand.Set(value)
 	} else {
 		return fmt.Errorf("unknown key: %s", key)
 	}
@@ -422,21 +651,188 @@ func (m *Manager) AddActionedResult(ctx context.Context, pluginId string, result
 	m.saveWoxAppData(ctx, "add actioned result")
 }
 
-func (m *Manager) AddFavoriteResult(ctx context.Context, pluginId string, resultTitle string, resultSubTitle string) {
-	util.GetLogger().Info(ctx, fmt.Sprintf("add favorite result: %s, %s", resultTitle, resultSubTitle))
-	resultHash := NewResultHash(pluginId, resultTitle, resultSubTitle)
-	m.woxAppData.FavoriteResults.Store(resultHash, true)
+func (m *Manager) AddRecentResult(ctx context.Context, recentResult RecentResult) {
+	recentResult.Timestamp = util.GetSystemTimestamp()
+
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	// only keep the latest one for the same result
+	m.woxAppData.RecentResults = lo.Filter(m.woxAppData.RecentResults, func(item RecentResult, _ int) bool {
+		return !(item.PluginId == recentResult.PluginId && item.ResultTitle == recentResult.ResultTitle && item.ResultSubTitle == recentResult.ResultSubTitle)
+	})
+	m.woxAppData.RecentResults = append(m.woxAppData.RecentResults, recentResult)
+
+	// if recent results are more than 50, remove the oldest ones
+	if len(m.woxAppData.RecentResults) > 50 {
+		m.woxAppData.RecentResults = m.woxAppData.RecentResults[len(m.woxAppData.RecentResults)-50:]
+	}
+
+	m.saveWoxAppData(ctx, "add recent result")
+}
+
+// GetRecentResults returns recent results, order by time desc
+func (m *Manager) GetRecentResults(ctx context.Context) []RecentResult {
+	m.woxAppDataLock.RLock()
+	defer m.woxAppDataLock.RUnlock()
+
+	return lo.Reverse(slices.Clone(m.woxAppData.RecentResults))
+}
+
+func (m *Manager) ClearRecentResults(ctx context.Context) {
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	m.woxAppData.RecentResults = []RecentResult{}
+	m.saveWoxAppData(ctx, "clear recent results")
+}
+
+func (m *Manager) AddFavoriteResult(ctx context.Context, favoriteResult FavoriteResult) {
+	util.GetLogger().Info(ctx, fmt.Sprintf("add favorite result: %s, %s", favoriteResult.ResultTitle, favoriteResult.ResultSubTitle))
+
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	favoriteResult.Timestamp = util.GetSystemTimestamp()
+	m.woxAppData.Favorites = lo.Filter(m.woxAppData.Favorites, func(item FavoriteResult, _ int) bool {
+		return item.Key() != favoriteResult.Key()
+	})
+	m.woxAppData.Favorites = append(m.woxAppData.Favorites, favoriteResult)
+	m.woxAppData.FavoriteResults.Delete(favoriteResult.resultHash())
 	m.saveWoxAppData(ctx, "add favorite result")
 }
 
+// GetFavoriteResults returns favorite results, order by time desc
+func (m *Manager) GetFavoriteResults(ctx context.Context) []FavoriteResult {
+	m.woxAppDataLock.RLock()
+	defer m.woxAppDataLock.RUnlock()
+
+	return lo.Reverse(slices.Clone(m.woxAppData.Favorites))
+}
+
+// IsFavoriteResult returns true if any result of the plugin with the same title and subtitle is favorite, used to boost scores
 func (m *Manager) IsFavoriteResult(ctx context.Context, pluginId string, resultTitle string, resultSubTitle string) bool {
 	resultHash := NewResultHash(pluginId, resultTitle, resultSubTitle)
-	return m.woxAppData.FavoriteResults.Exist(resultHash)
+
+	m.woxAppDataLock.RLock()
+	defer m.woxAppDataLock.RUnlock()
+
+	if m.woxAppData.FavoriteResults.Exist(resultHash) {
+		return true
+	}
+	return lo.ContainsBy(m.woxAppData.Favorites, func(item FavoriteResult) bool {
+		return item.resultHash() == resultHash
+	})
 }
 
-func (m *Manager) RemoveFavoriteResult(ctx context.Context, pluginId string, resultTitle string, resultSubTitle string) {
-	util.GetLogger().Info(ctx, fmt.Sprintf("remove favorite result: %s, %s", resultTitle, resultSubTitle))
-	resultHash := NewResultHash(pluginId, resultTitle, resultSubTitle)
-	m.woxAppData.FavoriteResults.Delete(resultHash)
+// MatchFavoriteResult returns true if the result is favorite, see FavoriteResult.Key.
+//
+// Favorites added by older versions are only stored as hash of plugin id, title and subtitle (see WoxAppData.FavoriteResults),
+// the hash can't be turned back into a result, so the first result matching the hash is migrated to Favorites when it shows up
+func (m *Manager) MatchFavoriteResult(ctx context.Context, favoriteResult FavoriteResult) bool {
+	m.woxAppDataLock.RLock()
+	isFavorite := lo.ContainsBy(m.woxAppData.Favorites, func(item FavoriteResult) bool {
+		return item.Key() == favoriteResult.Key()
+	})
+	isLegacyFavorite := m.woxAppData.FavoriteResults.Exist(favoriteResult.resultHash())
+	m.woxAppDataLock.RUnlock()
+	if isFavorite || !isLegacyFavorite {
+		return isFavorite
+	}
+
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	// check again, another query may have migrated it in the meantime
+	if !m.woxAppData.FavoriteResults.Exist(favoriteResult.resultHash()) {
+		return lo.ContainsBy(m.woxAppData.Favorites, func(item FavoriteResult) bool {
+			return item.Key() == favoriteResult.Key()
+		})
+	}
+	util.GetLogger().Info(ctx, fmt.Sprintf("migrate favorite result: %s, %s", favoriteResult.ResultTitle, favoriteResult.ResultSubTitle))
+	favoriteResult.Timestamp = util.GetSystemTimestamp()
+	m.woxAppData.Favorites = append(m.woxAppData.Favorites, favoriteResult)
+	m.woxAppData.FavoriteResults.Delete(favoriteResult.resultHash())
+	m.saveWoxAppData(ctx, "migrate favorite result")
+	return true
+}
+
+// RemoveFavoriteResult removes the favorite with the same key, other favorites with the same title (E.g. different clipboard items) are kept
+func (m *Manager) RemoveFavoriteResult(ctx context.Context, favoriteResult FavoriteResult) {
+	util.GetLogger().Info(ctx, fmt.Sprintf("remove favorite result: %s, %s", favoriteResult.ResultTitle, favoriteResult.ResultSubTitle))
+
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	m.woxAppData.FavoriteResults.Delete(favoriteResult.resultHash())
+	m.woxAppData.Favorites = lo.Filter(m.woxAppData.Favorites, func(item FavoriteResult, _ int) bool {
+		return item.Key() != favoriteResult.Key()
+	})
 	m.saveWoxAppData(ctx, "remove favorite result")
 }
+
+// AddSuppressedQuery hides results of a plugin for a query, see SuppressedQuery. Adding the same suppression again only refreshes its timestamp
+func (m *Manager) AddSuppressedQuery(ctx context.Context, pluginId string, query string, scope SuppressedQueryScope) error {
+	query = NormalizeSuppressedQuery(query)
+	if query == "" {
+		return fmt.Errorf("query is empty")
+	}
+	if scope != SuppressedQueryScopeExact && scope != SuppressedQueryScopePrefix {
+		return fmt.Errorf("unknown suppressed query scope: %s", scope)
+	}
+
+	util.GetLogger().Info(ctx, fmt.Sprintf("add suppressed query: %s, plugin: %s, scope: %s", query, pluginId, scope))
+
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	m.woxAppData.SuppressedQueries = lo.Filter(m.woxAppData.SuppressedQueries, func(item SuppressedQuery, _ int) bool {
+		return !(item.PluginId == pluginId && item.Query == query && item.Scope == scope)
+	})
+	m.woxAppData.SuppressedQueries = append(m.woxAppData.SuppressedQueries, SuppressedQuery{
+		PluginId:  pluginId,
+		Query:     query,
+		Scope:     scope,
+		Timestamp: util.GetSystemTimestamp(),
+	})
+	m.saveWoxAppData(ctx, "add suppressed query")
+	return nil
+}
+
+// GetSuppressedQueries returns suppressed queries, order by time desc
+func (m *Manager) GetSuppressedQueries(ctx context.Context) []SuppressedQuery {
+	m.woxAppDataLock.RLock()
+	defer m.woxAppDataLock.RUnlock()
+
+	return lo.Reverse(slices.Clone(m.woxAppData.SuppressedQueries))
+}
+
+func (m *Manager) IsQuerySuppressed(ctx context.Context, pluginId string, rawQuery string) bool {
+	m.woxAppDataLock.RLock()
+	defer m.woxAppDataLock.RUnlock()
+
+	return lo.ContainsBy(m.woxAppData.SuppressedQueries, func(item SuppressedQuery) bool {
+		return item.IsMatch(pluginId, rawQuery)
+	})
+}
+
+func (m *Manager) RemoveSuppressedQuery(ctx context.Context, pluginId string, query string, scope SuppressedQueryScope) {
+	query = NormalizeSuppressedQuery(query)
+	util.GetLogger().Info(ctx, fmt.Sprintf("remove suppressed query: %s, plugin: %s, scope: %s", query, pluginId, scope))
+
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	m.woxAppData.SuppressedQueries = lo.Filter(m.woxAppData.SuppressedQueries, func(item SuppressedQuery, _ int) bool {
+		return !(item.PluginId == pluginId && item.Query == query && item.Scope == scope)
+	})
+	m.saveWoxAppData(ctx, "remove suppressed query")
+}
+
+func (m *Manager) ClearSuppressedQueries(ctx context.Context) {
+	m.woxAppDataLock.Lock()
+	defer m.woxAppDataLock.Unlock()
+
+	m.woxAppData.SuppressedQueries = []SuppressedQuery{}
+	m.saveWoxAppData(ctx, "clear suppressed queries")
+}
@@ -26,6 +26,27 @@ type PluginSetting struct {
 	// So don't use this directly, use Instance.GetQueryCommands instead
 	QueryCommands []PluginQueryCommand
 
+	// Match trigger keywords and query commands of this plugin case-insensitively, e.g. "WPM Install" will trigger "wpm install".
+	// Default is false (case-sensitive) for compatibility
+	CaseInsensitiveTriggerKeywords bool
+
+	// Match query commands of this plugin by unique prefix, e.g. "wpm ins q" will be treated as "wpm install q".
+	// Prefix matching multiple commands is ambiguous and will be treated as search
+	PrefixQueryCommands bool
+
+	// User defined multiplier applied to scores of this plugin's results, used to bias the ranking of global queries.
+	// 1.0 means no change, 0 means not set (same as 1.0)
+	//
+	// So don't use this directly, use Instance.GetScoreMultiplier instead
+	ScoreMultiplier float64
+
+	// Permissions user has decided, key is permission name, value is true if granted and false if denied.
+	// Permissions not in this map are not decided yet, user will be asked on first use
+	PermissionGrants *util.HashMap[string, bool]
+
+	// Don't include settings and storage of this plugin when exporting plugin settings, E.g. when settings contain credentials
+	ExcludeFromExport bool
+
 	Settings *util.HashMap[string, string]
 }
 
@@ -3,6 +3,7 @@ package setting
 import (
 	"context"
 	"fmt"
+	"strings"
 	"wox/share"
 	"wox/util"
 )
@@ -12,7 +13,11 @@ type ResultHash string
 type WoxAppData struct {
 	QueryHistories  []QueryHistory
 	ActionedResults *util.HashMap[ResultHash, []ActionedResult]
-	FavoriteResults *util.HashMap[ResultHash, bool]
+	FavoriteResults *util.HashMap[ResultHash, bool] // favorites of older versions which are not migrated to Favorites yet, see Manager.MatchFavoriteResult
+	Favorites       []FavoriteResult
+	RecentResults   []RecentResult
+	// SuppressedQueries hide results of a plugin for some queries, see SuppressedQuery
+	SuppressedQueries []SuppressedQuery
 }
 
 type QueryHistory struct {
@@ -24,14 +29,83 @@ type ActionedResult struct {
 	Timestamp int64
 }
 
+// RecentResult is a result actioned by user recently, used to replay the action later
+type RecentResult struct {
+	PluginId       string
+	ResultTitle    string
+	ResultSubTitle string
+	ActionName     string
+	Query          share.PlainQuery // the query which produced this result
+	Timestamp      int64
+}
+
+// FavoriteResult is a result marked as favorite by user, listed by the favorites query.
+// Action functions can't be persisted, so we store the query which produced the result and enough data to find it again
+// in the new results: result id (stable if plugin sets it), context data and title/subtitle as fallback.
+type FavoriteResult struct {
+	PluginId       string
+	ResultId       string
+	ResultTitle    string
+	ResultSubTitle string
+	ContextData    string
+	Query          share.PlainQuery // the query which produced this result, empty for selection queries because they can't be replayed
+	Timestamp      int64
+}
+
+// Key identifies a favorite result, same result with different context data (E.g. different clipboard items with same title) are different favorites.
+// Result id is not part of the key because most plugins generate a new one for every query
+func (f FavoriteResult) Key() ResultHash {
+	return ResultHash(util.Md5([]byte(fmt.Sprintf("%s|%s|%s|%s", f.PluginId, f.ResultTitle, f.ResultSubTitle, f.ContextData))))
+}
+
+// resultHash is the hash favorites were stored as before FavoriteResult was added, see WoxAppData.FavoriteResults
+func (f FavoriteResult) resultHash() ResultHash {
+	return NewResultHash(f.PluginId, f.ResultTitle, f.ResultSubTitle)
+}
+
+type SuppressedQueryScope string
+
+const (
+	SuppressedQueryScopeExact  SuppressedQueryScope = "exact"  // query equals the suppressed query
+	SuppressedQueryScopePrefix SuppressedQueryScope = "prefix" // query starts with the suppressed query, E.g. "git" also hides "github"
+)
+
+// SuppressedQuery hides all results of a plugin for a query, user adds it by the "hide results" action of a result of that plugin.
+// Queries are compared after NormalizeSuppressedQuery, only input queries can be suppressed
+type SuppressedQuery struct {
+	PluginId  string
+	Query     string // normalized query text, including trigger keyword if any
+	Scope     SuppressedQueryScope
+	Timestamp int64
+}
+
+func (s SuppressedQuery) IsMatch(pluginId string, rawQuery string) bool {
+	if s.PluginId != pluginId {
+		return false
+	}
+	rawQuery = NormalizeSuppressedQuery(rawQuery)
+	if s.Scope == SuppressedQueryScopePrefix {
+		return strings.HasPrefix(rawQuery, s.Query)
+	}
+	return rawQuery == s.Query
+}
+
+// NormalizeSuppressedQuery ignores case and extra spaces, so "Foo  bar " and "foo bar" are the same query
+func NormalizeSuppressedQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
 func NewResultHash(pluginId string, title, subTitle string) ResultHash {
 	return ResultHash(util.Md5([]byte(fmt.Sprintf("%s%s%s", pluginId, title, subTitle))))
 }
 
 func GetDefaultWoxAppData(ctx context.Context) WoxAppData {
 	return WoxAppData{
-		QueryHistories:  []QueryHistory{},
-		ActionedResults: util.NewHashMap[ResultHash, []ActionedResult](),
-		FavoriteResults: util.NewHashMap[ResultHash, bool](),
+		QueryHistories:    []QueryHistory{},
+		ActionedResults:   util.NewHashMap[ResultHash, []ActionedResult](),
+		FavoriteResults:   util.NewHashMap[ResultHash, bool](),
+		Favorites:         []FavoriteResult{},
+		RecentResults:     []RecentResult{},
+		SuppressedQueries: []SuppressedQuery{},
 	}
 }
@@ -0,0 +1,146 @@
+package setting
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"wox/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// initFavoriteTestManager returns the setting manager with no favorites, favorites of the test environment are restored after test
+func initFavoriteTestManager(t *testing.T) *Manager {
+	ctx := context.Background()
+	assert.NoError(t, util.GetLocation().Init())
+	m := GetSettingManager()
+	assert.NoError(t, m.Init(ctx))
+
+	favorites := slices.Clone(m.woxAppData.Favorites)
+	legacyFavorites := m.woxAppData.FavoriteResults
+	m.woxAppData.Favorites = []FavoriteResult{}
+	m.woxAppData.FavoriteResults = util.NewHashMap[ResultHash, bool]()
+	t.Cleanup(func() {
+		m.woxAppData.Favorites = favorites
+		m.woxAppData.FavoriteResults = legacyFavorites
+		m.saveWoxAppData(ctx, "restore favorites after test")
+	})
+	return m
+}
+
+func TestFavoriteResult_SameTitle(t *testing.T) {
+	ctx := context.Background()
+	m := initFavoriteTestManager(t)
+
+	first := FavoriteResult{PluginId: "clipboard", ResultId: "1", ResultTitle: "hello", ContextData: "item-1"}
+	second := FavoriteResult{PluginId: "clipboard", ResultId: "2", ResultTitle: "hello", ContextData: "item-2"}
+	m.AddFavoriteResult(ctx, first)
+	m.AddFavoriteResult(ctx, second)
+
+	// result id changes on every query, favorite is still matched
+	first.ResultId = "3"
+	assert.True(t, m.MatchFavoriteResult(ctx, first))
+	assert.True(t, m.IsFavoriteResult(ctx, "clipboard", "hello", ""))
+
+	// adding again only moves it to the top
+	m.AddFavoriteResult(ctx, first)
+	favorites := m.GetFavoriteResults(ctx)
+	assert.Len(t, favorites, 2)
+	assert.Equal(t, "item-1", favorites[0].ContextData)
+
+	// removing one must keep the other with the same title
+	m.RemoveFavoriteResult(ctx, second)
+	favorites = m.GetFavoriteResults(ctx)
+	assert.Len(t, favorites, 1)
+	assert.Equal(t, "item-1", favorites[0].ContextData)
+	assert.False(t, m.MatchFavoriteResult(ctx, second))
+	assert.True(t, m.IsFavoriteResult(ctx, "clipboard", "hello", ""))
+
+	m.RemoveFavoriteResult(ctx, first)
+	assert.Empty(t, m.GetFavoriteResults(ctx))
+	assert.False(t, m.IsFavoriteResult(ctx, "clipboard", "hello", ""))
+}
+
+func TestFavoriteResult_MigrateLegacy(t *testing.T) {
+	ctx := context.Background()
+	m := initFavoriteTestManager(t)
+
+	m.woxAppData.FavoriteResults.Store(NewResultHash("app", "Terminal", "/Applications/Terminal.app"), true)
+	assert.True(t, m.IsFavoriteResult(ctx, "app", "Terminal", "/Applications/Terminal.app"))
+	assert.Empty(t, m.GetFavoriteResults(ctx))
+
+	legacy := FavoriteResult{PluginId: "app", ResultTitle: "Terminal", ResultSubTitle: "/Applications/Terminal.app", ContextData: "terminal"}
+	assert.False(t, m.MatchFavoriteResult(ctx, FavoriteResult{PluginId: "app", ResultTitle: "Finder"}))
+
+	// concurrent queries must migrate it only once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, m.MatchFavoriteResult(ctx, legacy))
+		}()
+	}
+	wg.Wait()
+
+	favorites := m.GetFavoriteResults(ctx)
+	assert.Len(t, favorites, 1)
+	assert.Equal(t, "terminal", favorites[0].ContextData)
+	assert.False(t, m.woxAppData.FavoriteResults.Exist(legacy.resultHash()))
+	assert.True(t, m.IsFavoriteResult(ctx, "app", "Terminal", "/Applications/Terminal.app"))
+}
+
+func TestSuppressedQuery(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, util.GetLocation().Init())
+	m := GetSettingManager()
+	assert.NoError(t, m.Init(ctx))
+
+	suppressedQueries := slices.Clone(m.woxAppData.SuppressedQueries)
+	m.woxAppData.SuppressedQueries = []SuppressedQuery{}
+	t.Cleanup(func() {
+		m.woxAppData.SuppressedQueries = suppressedQueries
+		m.saveWoxAppData(ctx, "restore suppressed queries after test")
+	})
+
+	assert.Error(t, m.AddSuppressedQuery(ctx, "web", "  ", SuppressedQueryScopeExact))
+	assert.Error(t, m.AddSuppressedQuery(ctx, "web", "git", "contains"))
+
+	assert.NoError(t, m.AddSuppressedQuery(ctx, "web", "Foo  Bar ", SuppressedQueryScopeExact))
+	assert.NoError(t, m.AddSuppressedQuery(ctx, "web", "git", SuppressedQueryScopePrefix))
+	assert.True(t, m.IsQuerySuppressed(ctx, "web", "foo bar"))
+	assert.False(t, m.IsQuerySuppressed(ctx, "web", "foo bar baz"))
+	assert.False(t, m.IsQuerySuppressed(ctx, "file", "foo bar"))
+	assert.True(t, m.IsQuerySuppressed(ctx, "web", "GitHub"))
+
+	// adding again only moves it to the top
+	assert.NoError(t, m.AddSuppressedQuery(ctx, "web", "foo bar", SuppressedQueryScopeExact))
+	suppressed := m.GetSuppressedQueries(ctx)
+	assert.Len(t, suppressed, 2)
+	assert.Equal(t, "foo bar", suppressed[0].Query)
+
+	m.RemoveSuppressedQuery(ctx, "web", "FOO BAR", SuppressedQueryScopeExact)
+	assert.False(t, m.IsQuerySuppressed(ctx, "web", "foo bar"))
+	assert.Len(t, m.GetSuppressedQueries(ctx), 1)
+
+	// queries check suppressions while user adds them
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, m.AddSuppressedQuery(ctx, "web", "foo", SuppressedQueryScopeExact))
+		}()
+		go func() {
+			defer wg.Done()
+			m.IsQuerySuppressed(ctx, "web", "foo")
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, m.GetSuppressedQueries(ctx), 2)
+
+	m.ClearSuppressedQueries(ctx)
+	assert.Empty(t, m.GetSuppressedQueries(ctx))
+	assert.False(t, m.IsQuerySuppressed(ctx, "web", "github"))
+}
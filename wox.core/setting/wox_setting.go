@@ -27,17 +27,116 @@ type WoxSetting struct {
 	AIProviders          []AIProvider
 	EnableAutoBackup     bool // Enable automatic data backup
 
+	// Only used by LastQueryModeRestore: last query older than this is not restored, Wox starts with an empty query.
+	// If RerunRestoredQuery is false, results still shown in UI are reused, otherwise plugins are queried again
+	RestoreLastQueryWindowSeconds int
+	RerunRestoredQuery            bool
+
+	// What Escape does in the query box, see EscapeMode
+	EscapeMode EscapeMode
+
+	// Browser used to open urls from plugin actions, empty means system default browser
+	CustomBrowserPath PlatformSettingValue[string]
+
+	// Terminal command used by "run in terminal" actions, must contain {command} placeholder, E.g. `alacritty -e sh -c {command}`.
+	// Empty means the default terminal of the OS
+	TerminalCommand PlatformSettingValue[string]
+
 	// HTTP proxy settings
 	HttpProxyEnabled PlatformSettingValue[bool]
 	HttpProxyUrl     PlatformSettingValue[string]
 
+	// Record actioned results so they can be re-opened from the recent results query
+	EnableRecentResults bool
+
+	// Actions appended to every result, in this order, see GlobalAction
+	GlobalActions []GlobalAction
+
+	// User overrides of action order and default action of results, see ActionPreference
+	ActionPreferences []ActionPreference
+
+	// Refresh throttling of refreshable results
+	MaxRefreshPerSecond         int // max refresh calls per second across all results, negative means no limit
+	HiddenResultRefreshInterval int // min interval in ms between two refreshes of a result that is not visible in UI
+	MaxRefreshTimeout           int // max time in ms a single refresh can take before it's cancelled
+
+	// Max running queries of each plugin, when exceeded the oldest query of the plugin is cancelled, negative means no limit
+	MaxConcurrentQueriesPerPlugin int
+
+	// If true, a query with trigger keyword also queries global plugins (with the trigger keyword stripped),
+	// results of the triggered plugin are shown in their own section above global results
+	MixGlobalResultsInTriggeredQuery bool
+
+	// Time in ms after the last query with trigger keyword, within which deleting the trigger keyword keeps querying its plugin
+	// (E.g. "gh issues foo" => "g issues foo"), so results don't vanish abruptly while editing. 0 switches to global query immediately
+	TriggerKeywordDeletedGrace int
+
+	// If true, results of global queries are grouped by the plugin which returned them, user can collapse the groups.
+	// Groups are ordered by the best score of their results, groups returned by plugins are ignored in this mode
+	GroupResultsByPlugin bool
+
+	// Time in ms within which rapid query updates (E.g. pasting or IME) are coalesced, only the latest of them is processed.
+	// A query after a quiet period is processed immediately, negative means no coalescing
+	QueryCoalesceWindow int
+
+	// Time in ms within which results of a query are coalesced into a single frame sent to UI, negative means results are sent as soon as they arrive
+	ResultFlushWindow int
+
+	// Max number of results sent to UI in a frame, results with the best scores are sent first and the rest follows in next frames.
+	// All remaining results are sent when the query is finished. Negative means no limit
+	ResultFlushBatchSize int
+
+	// Time in ms after which a query is finalized with the results arrived so far and slow plugins are cancelled,
+	// negative means waiting for all plugins. It's off by default since results of slow plugins would be dropped silently, users opt in
+	QuerySoftDeadline int
+
+	// Time in ms after which a query is aborted if plugins still haven't returned, it's the overall safety net and
+	// only fires if soft deadline is disabled or flushing results hangs. Unfinished plugins are logged and tracked
+	QueryTimeout int
+
+	// Max estimated memory in MB used by cached results (actions, previews etc.) of current query,
+	// least recently used results are evicted beyond it. Negative means no limit
+	MaxResultCacheSize int
+
+	// Search starting with this marker is matched literally (case-insensitive substring) instead of fuzzy, E.g. "'report" with default marker.
+	// The marker is stripped from Query.Search, see Query.IsLiteralMatch
+	LiteralMatchPrefix string
+
+	// Pressing this modifier with Enter runs the default action of the focused result in background: Wox doesn't hide, keeps the query
+	// and takes focus back after the action, so several results can be opened in succession (E.g. open 5 tabs).
+	// One of "cmd" (win), "ctrl", "alt" (option), "shift", or BackgroundActionModifierNone to disable it.
+	// Disabled by default, the modifier is reserved once it's set and activation modifiers of plugin actions using it are dropped
+	BackgroundActionModifier string
+
+	// Hide hotkeys of actions in action panel (E.g. "⌘⇧C"), hotkeys still work when hidden
+	HideActionHotkeyHints bool
+
+	// Show preview of the result under mouse cursor without focusing it, the focused result's preview is restored when mouse leaves.
+	// Off by default since some users find previews changing on hover distracting
+	EnablePreviewPeek bool
+
+	// drop malformed results (E.g. empty title, unknown icon type) of plugins and show the problems as an error result instead of fixing them up,
+	// helps plugin developers to notice bugs of their plugins
+	StrictResultValidation bool
+
+	// collect per plugin timing of last query and allow dumping plugin state, both are only served on request through the
+	// GetQueryStat and DumpState websocket methods, UI has no built-in view of them yet
+	EnableQueryDebug bool
+
+	// Record usage events (query issued, result shown, action invoked) to local files, off by default. See docs/analytics.md
+	EnableAnalytics bool
+
 	// UI related
 	AppWidth int
 	ThemeId  string
 }
 
+const BackgroundActionModifierNone = "none"
+
 type LastQueryMode = string
 
+type EscapeMode = string
+
 type PositionType string
 
 const (
@@ -46,15 +145,43 @@ const (
 	PositionTypeLastLocation PositionType = "last_location"
 )
 
+type GlobalAction = string
+
+const (
+	GlobalActionCopyTitle         GlobalAction = "copy_title"          // copy result title to clipboard
+	GlobalActionOpenPluginSetting GlobalAction = "open_plugin_setting" // open setting page of the plugin which returns the result
+	GlobalActionReportIssue       GlobalAction = "report_issue"        // open website of the plugin to report an issue
+)
+
 const (
 	LastQueryModePreserve LastQueryMode = "preserve" // preserve last query and select all for quick modify
 	LastQueryModeEmpty    LastQueryMode = "empty"    // empty last query
+	LastQueryModeRestore  LastQueryMode = "restore"  // restore last input query when Wox is shown, until it's older than RestoreLastQueryWindowSeconds
+)
+
+// Escape always hides Wox if the query box is empty
+const (
+	EscapeModeHide          EscapeMode = "hide"            // hide Wox, query box is kept or cleared by LastQueryMode
+	EscapeModeClearThenHide EscapeMode = "clear_then_hide" // clear the query box first, hide Wox by the next Escape
+	EscapeModeHideThenClear EscapeMode = "hide_then_clear" // hide Wox first, clear the query box if Wox is shown again with the same query
 )
 
 const (
 	DefaultThemeId = "e4006bd3-6bfe-4020-8d1c-4c32a8e567e5"
 )
 
+// ActionPreference reorders actions of results and picks their default action, E.g. "copy path" instead of "open" for files.
+// Actions are identified by their id, actions without id declared by plugin get one derived from their declared name (E.g. "plugin_action_open").
+// The most specific preference matching a result is applied: plugin and kind, then plugin only, then kind only
+type ActionPreference struct {
+	PluginId   string // empty matches results of any plugin
+	ResultKind string // empty matches results of any kind, see plugin.QueryResultKind
+	// Action to use as default, it overrides IsDefault and DefaultActionId declared by plugin. Empty keeps the default of plugin
+	DefaultAction string
+	// Actions shown first (after the default action) in this order, other actions follow in plugin order
+	Order []string
+}
+
 type QueryShortcut struct {
 	Shortcut string // support index placeholder, e.g. shortcut "wi" => "wpm install {0} to {1}", when user input "wi 1 2", the query will be "wpm install 1 to 2"
 	Query    string
@@ -78,6 +205,9 @@ type QueryHotkey struct {
 	Hotkey            string
 	Query             string // Support plugin.QueryVariable
 	IsSilentExecution bool   // If true, the query will be executed without showing the query in the input box
+	// If set, the headless action "<plugin id or name>:<action name>" is executed instead of the query, the query is passed to
+	// the action as context data. See plugin.HeadlessAction
+	HeadlessAction string
 }
 
 func GetDefaultWoxSetting(ctx context.Context) WoxSetting {
@@ -107,6 +237,7 @@ func GetDefaultWoxSetting(ctx context.Context) WoxSetting {
 		HideOnLostFocus:      true,
 		LangCode:             langCode,
 		LastQueryMode:        LastQueryModeEmpty,
+		EscapeMode:           EscapeModeHide,
 		ShowPosition:         PositionTypeMouseScreen,
 		AppWidth:             800,
 		ThemeId:              DefaultThemeId,
@@ -125,7 +256,32 @@ func GetDefaultWoxSetting(ctx context.Context) WoxSetting {
 			MacValue:   "",
 			LinuxValue: "",
 		},
-		EnableAutoBackup: true,
+		EnableAutoBackup:              true,
+		EnableRecentResults:           true,
+		MaxRefreshPerSecond:           50,
+		HiddenResultRefreshInterval:   3000,
+		MaxRefreshTimeout:             5000,
+		MaxConcurrentQueriesPerPlugin: 3,
+		QuerySoftDeadline:             -1,
+		QueryCoalesceWindow:           30,
+		ResultFlushWindow:             24,
+		ResultFlushBatchSize:          100,
+		QueryTimeout:                  60000,
+		MaxResultCacheSize:            64,
+		LiteralMatchPrefix:            "'",
+		BackgroundActionModifier:      BackgroundActionModifierNone,
+		RestoreLastQueryWindowSeconds: 300,
+		GlobalActions:                 []GlobalAction{GlobalActionCopyTitle, GlobalActionOpenPluginSetting, GlobalActionReportIssue},
+		CustomBrowserPath: PlatformSettingValue[string]{
+			WinValue:   "",
+			MacValue:   "",
+			LinuxValue: "",
+		},
+		TerminalCommand: PlatformSettingValue[string]{
+			WinValue:   "",
+			MacValue:   "",
+			LinuxValue: "",
+		},
 	}
 }
 
@@ -2,6 +2,7 @@ package share
 
 import (
 	"context"
+	"errors"
 	"wox/util/selection"
 )
 
@@ -38,7 +39,11 @@ type UI interface {
 	ShowApp(ctx context.Context, showContext ShowContext)
 	ToggleApp(ctx context.Context)
 	OpenSettingWindow(ctx context.Context, windowContext SettingWindowContext)
-	PickFiles(ctx context.Context, params PickFilesParams) []string
+	// PickFiles opens the native file picker, it returns empty paths and no error if user cancelled
+	PickFiles(ctx context.Context, params PickFilesParams) ([]string, error)
+	// ShowDialog shows a modal form and waits until user submits it, responses are values of fields by key plus the pressed
+	// button (see DialogButtonResponseKey). ErrDialogCancelled is returned if user cancelled, ErrDialogTimeout if user didn't respond in time
+	ShowDialog(ctx context.Context, spec DialogSpec) (responses map[string]string, err error)
 	GetActiveWindowName() string
 	GetActiveWindowPid() int
 	GetServerPort(ctx context.Context) int
@@ -48,6 +53,13 @@ type UI interface {
 	UninstallTheme(ctx context.Context, theme Theme)
 	RestoreTheme(ctx context.Context)
 	Notify(ctx context.Context, msg NotifyMsg)
+	SetStatus(ctx context.Context, msg StatusMsg)
+	ClearStatus(ctx context.Context, pluginId string)
+	UpdateResults(ctx context.Context, results any)                  // results is []plugin.RefreshableResultWithResultId
+	UpdatePreview(ctx context.Context, resultId string, preview any) // preview is plugin.WoxPreview
+	// UpdateResultScores changes scores of shown results and re-sorts them stably, focused result stays focused
+	UpdateResultScores(ctx context.Context, scores any) // scores is []plugin.ResultScoreUpdate
+	ExecuteBatch(ctx context.Context, commands []UICommand) error
 }
 
 type ShowContext struct {
@@ -55,7 +67,58 @@ type ShowContext struct {
 }
 
 type PickFilesParams struct {
-	IsDirectory bool
+	IsDirectory      bool
+	Title            string   // title of the picker, can be empty
+	DefaultDirectory string   // directory the picker starts in, can be empty
+	Extensions       []string // allowed file extensions without dot (E.g. "png"), empty means all files. Ignored when picking directory
+	AllowMultiple    bool     // ignored when picking directory
+}
+
+var ErrDialogCancelled = errors.New("dialog is cancelled by user")
+var ErrDialogTimeout = errors.New("dialog is not answered in time")
+
+// DialogButtonResponseKey is the key of the pressed button in responses of UI.ShowDialog, field keys can't use it
+const DialogButtonResponseKey = "$button"
+
+type DialogFieldType = string
+
+const (
+	DialogFieldTypeText     DialogFieldType = "text"
+	DialogFieldTypePassword DialogFieldType = "password"
+	DialogFieldTypeSelect   DialogFieldType = "select"
+	DialogFieldTypeCheckbox DialogFieldType = "checkbox" // value is "true" or "false"
+)
+
+// DialogSpec describes a modal form shown by UI.ShowDialog (E.g. OAuth consent, multi-field setup of an integration)
+type DialogSpec struct {
+	Id             string // set by UI.ShowDialog
+	Title          string
+	Description    string // can be empty
+	Fields         []DialogField
+	Buttons        []DialogButton // empty means a submit and a cancel button
+	TimeoutSeconds int            // 0 means 5 minutes, the dialog is closed when it times out
+}
+
+type DialogField struct {
+	Key          string
+	Type         DialogFieldType
+	Label        string
+	Placeholder  string              // can be empty
+	DefaultValue string              // can be empty, "true" or "false" for checkbox
+	Options      []DialogFieldOption // only used by select
+	Required     bool                // UI doesn't submit until required fields are filled, checkbox is never missing
+}
+
+type DialogFieldOption struct {
+	Label string
+	Value string
+}
+
+type DialogButton struct {
+	Key   string
+	Label string
+	// If true, pressing the button cancels the dialog without checking required fields, same as Escape
+	IsCancel bool
 }
 
 type NotifyMsg struct {
@@ -64,3 +127,34 @@ type NotifyMsg struct {
 	Text           string // can be empty
 	DisplaySeconds int    // 0 means display forever
 }
+
+// StatusMsg is a subtle status line shown at the bottom of the window (E.g. "Syncing…") until it's cleared, unlike NotifyMsg it doesn't expire.
+// Each plugin has at most one status, setting it again replaces the previous one. If several plugins have a status,
+// UI shows the latest set one, the previous one is shown again when it's cleared
+type StatusMsg struct {
+	PluginId string // can be empty
+	Icon     string // WoxImage.String(), can be empty
+	Text     string
+}
+
+// MaxUICommandBatchSize is the max number of commands in one UI command batch
+const MaxUICommandBatchSize = 20
+
+type UICommandType string
+
+const (
+	UICommandTypeChangeQuery    UICommandType = "ChangeQuery"
+	UICommandTypeHideApp        UICommandType = "HideApp"
+	UICommandTypeShowApp        UICommandType = "ShowApp"
+	UICommandTypeShowToolbarMsg UICommandType = "ShowToolbarMsg"
+)
+
+// UICommand is a single command in a batch, see UI.ExecuteBatch.
+// Commands in a batch are sent to UI in one websocket request and applied in order, so they won't interleave with other commands.
+// If a command fails, following commands are skipped, commands applied before are not rolled back.
+type UICommand struct {
+	Type        UICommandType
+	Query       PlainQuery  // only available when type is UICommandTypeChangeQuery
+	ShowContext ShowContext // only available when type is UICommandTypeShowApp
+	Msg         NotifyMsg   // only available when type is UICommandTypeShowToolbarMsg
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wox/i18n"
+	"wox/share"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const dialogDefaultTimeout = 5 * time.Minute
+
+// ShowDialog shows a modal form in UI and waits for user, see share.UI.ShowDialog.
+// If it times out or ctx is done before user responded, the dialog is closed in UI
+func (u *uiImpl) ShowDialog(ctx context.Context, spec share.DialogSpec) (map[string]string, error) {
+	if err := validateDialogSpec(spec); err != nil {
+		return nil, err
+	}
+
+	spec.Id = uuid.NewString()
+	if len(spec.Buttons) == 0 {
+		spec.Buttons = []share.DialogButton{
+			{Key: "cancel", Label: i18n.GetI18nManager().TranslateWox(ctx, "ui_dialog_cancel"), IsCancel: true},
+			{Key: "submit", Label: i18n.GetI18nManager().TranslateWox(ctx, "ui_dialog_submit")},
+		}
+	}
+	timeout := dialogDefaultTimeout
+	if spec.TimeoutSeconds > 0 {
+		timeout = time.Duration(spec.TimeoutSeconds) * time.Second
+	}
+
+	respData, err := u.invokeWebsocketMethodWithTimeout(ctx, "ShowDialog", spec, timeout)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, errUIRequestTimeout) {
+			// nobody waits for the answer anymore
+			u.invokeWebsocketMethod(context.WithoutCancel(ctx), "CloseDialog", map[string]string{"Id": spec.Id})
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, errUIRequestTimeout) {
+			return nil, share.ErrDialogTimeout
+		}
+		return nil, err
+	}
+
+	// cancelled by user
+	if respData == nil {
+		return nil, share.ErrDialogCancelled
+	}
+	values, ok := respData.(map[string]any)
+	if !ok {
+		logger.Error(ctx, fmt.Sprintf("show dialog response data type error: %T", respData))
+		return nil, fmt.Errorf("unexpected show dialog response: %T", respData)
+	}
+
+	responses := map[string]string{}
+	for key, value := range values {
+		responses[key] = fmt.Sprintf("%v", value)
+	}
+	return responses, nil
+}
+
+func validateDialogSpec(spec share.DialogSpec) error {
+	keys := map[string]bool{}
+	for _, field := range spec.Fields {
+		if field.Key == "" || field.Key == share.DialogButtonResponseKey {
+			return fmt.Errorf("invalid dialog field key: %q", field.Key)
+		}
+		if keys[field.Key] {
+			return fmt.Errorf("duplicate dialog field key: %s", field.Key)
+		}
+		keys[field.Key] = true
+
+		if !lo.Contains([]share.DialogFieldType{share.DialogFieldTypeText, share.DialogFieldTypePassword, share.DialogFieldTypeSelect, share.DialogFieldTypeCheckbox}, field.Type) {
+			return fmt.Errorf("unsupported type of dialog field %s: %s", field.Key, field.Type)
+		}
+		if field.Type == share.DialogFieldTypeSelect && len(field.Options) == 0 {
+			return fmt.Errorf("select dialog field %s has no options", field.Key)
+		}
+	}
+
+	buttonKeys := map[string]bool{}
+	for _, button := range spec.Buttons {
+		if button.Key == "" || buttonKeys[button.Key] {
+			return fmt.Errorf("invalid or duplicate dialog button key: %q", button.Key)
+		}
+		buttonKeys[button.Key] = true
+	}
+	return nil
+}
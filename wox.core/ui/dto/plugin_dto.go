@@ -28,4 +28,6 @@ type PluginDto struct {
 	IsDev              bool
 	IsInstalled        bool
 	IsDisable          bool // only available when plugin is installed
+
+	WarmUp *plugin.PluginWarmUpStatus // nil if plugin has no startup callbacks, only available when plugin is installed
 }
@@ -6,23 +6,54 @@ import (
 )
 
 type WoxSettingDto struct {
-	EnableAutostart      bool
-	MainHotkey           string
-	SelectionHotkey      string
-	UsePinYin            bool
-	SwitchInputMethodABC bool
-	HideOnStart          bool
-	HideOnLostFocus      bool
-	ShowTray             bool
-	LangCode             i18n.LangCode
-	QueryHotkeys         []setting.QueryHotkey
-	QueryShortcuts       []setting.QueryShortcut
-	LastQueryMode        setting.LastQueryMode
-	AIProviders          []setting.AIProvider
-	HttpProxyEnabled     bool
-	HttpProxyUrl         string
-	ShowPosition         setting.PositionType
-	EnableAutoBackup     bool
+	EnableAutostart        bool
+	MainHotkey             string
+	SelectionHotkey        string
+	UsePinYin              bool
+	SwitchInputMethodABC   bool
+	HideOnStart            bool
+	HideOnLostFocus        bool
+	ShowTray               bool
+	LangCode               i18n.LangCode
+	QueryHotkeys           []setting.QueryHotkey
+	QueryShortcuts         []setting.QueryShortcut
+	LastQueryMode          setting.LastQueryMode
+	AIProviders            []setting.AIProvider
+	HttpProxyEnabled       bool
+	HttpProxyUrl           string
+	ShowPosition           setting.PositionType
+	EnableAutoBackup       bool
+	CustomBrowserPath      string
+	TerminalCommand        string
+	EnableQueryDebug       bool
+	StrictResultValidation bool
+	EnableAnalytics        bool
+	EnableRecentResults    bool
+	GlobalActions          []setting.GlobalAction
+	ActionPreferences      []setting.ActionPreference
+
+	RestoreLastQueryWindowSeconds int
+	RerunRestoredQuery            bool
+
+	EscapeMode setting.EscapeMode
+
+	MaxRefreshPerSecond              int
+	HiddenResultRefreshInterval      int
+	MaxRefreshTimeout                int
+	MaxConcurrentQueriesPerPlugin    int
+	QuerySoftDeadline                int
+	QueryCoalesceWindow              int
+	ResultFlushWindow                int
+	ResultFlushBatchSize             int
+	MixGlobalResultsInTriggeredQuery bool
+	TriggerKeywordDeletedGrace       int
+	GroupResultsByPlugin             bool
+	QueryTimeout                     int
+	MaxResultCacheSize               int
+	LiteralMatchPrefix               string
+	BackgroundActionModifier         string
+	HideActionHotkeyHints            bool
+	EnablePreviewPeek                bool
 
 	// UI related
 	AppWidth int
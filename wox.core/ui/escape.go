@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"context"
+	"wox/plugin"
+	"wox/setting"
+	"wox/share"
+)
+
+// HandleEscape hides Wox or clears the query box when user pressed Escape in the query box, see setting.EscapeMode.
+// query is the query shown in UI when Escape was pressed
+func (m *Manager) HandleEscape(ctx context.Context, query share.PlainQuery) {
+	escapeMode := setting.GetSettingManager().GetWoxSetting(ctx).EscapeMode
+	if shouldClearQueryOnEscape(escapeMode, query, m.lastHiddenQuery.Load()) {
+		logger.Debug(ctx, "escape pressed, clear query")
+		m.GetUI(ctx).ChangeQuery(ctx, share.PlainQuery{QueryType: plugin.QueryTypeInput})
+		return
+	}
+
+	m.GetUI(ctx).HideApp(ctx)
+}
+
+// shouldClearQueryOnEscape tells whether Escape should clear query instead of hiding Wox, an empty query is never cleared.
+// lastHiddenQuery is the query shown in UI when Wox was hidden last time, nil if Wox was never hidden
+func shouldClearQueryOnEscape(escapeMode setting.EscapeMode, query share.PlainQuery, lastHiddenQuery *share.PlainQuery) bool {
+	if query.IsEmpty() {
+		return false
+	}
+
+	switch escapeMode {
+	case setting.EscapeModeClearThenHide:
+		return true
+	case setting.EscapeModeHideThenClear:
+		// Wox was hidden by the first Escape and shown again with the same query, user didn't change it since then
+		return lastHiddenQuery != nil && lastHiddenQuery.QueryType == query.QueryType && lastHiddenQuery.QueryText == query.QueryText &&
+			lastHiddenQuery.QuerySelection.String() == query.QuerySelection.String()
+	}
+	return false
+}
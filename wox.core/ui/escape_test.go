@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+	"wox/plugin"
+	"wox/setting"
+	"wox/share"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldClearQueryOnEscape(t *testing.T) {
+	empty := share.PlainQuery{QueryType: plugin.QueryTypeInput}
+	query := share.PlainQuery{QueryType: plugin.QueryTypeInput, QueryText: "wpm install"}
+	other := share.PlainQuery{QueryType: plugin.QueryTypeInput, QueryText: "wpm"}
+
+	assert.False(t, shouldClearQueryOnEscape(setting.EscapeModeHide, query, &query))
+
+	assert.True(t, shouldClearQueryOnEscape(setting.EscapeModeClearThenHide, query, nil))
+	assert.False(t, shouldClearQueryOnEscape(setting.EscapeModeClearThenHide, empty, nil))
+
+	// first Escape hides, Escape after shown again with the same query clears
+	assert.False(t, shouldClearQueryOnEscape(setting.EscapeModeHideThenClear, query, nil))
+	assert.False(t, shouldClearQueryOnEscape(setting.EscapeModeHideThenClear, query, &other))
+	assert.True(t, shouldClearQueryOnEscape(setting.EscapeModeHideThenClear, query, &query))
+	assert.False(t, shouldClearQueryOnEscape(setting.EscapeModeHideThenClear, empty, &empty))
+}
@@ -126,18 +126,19 @@ func serveAndWait(ctx context.Context, port int) {
 	}
 }
 
-func requestUI(ctx context.Context, request WebsocketMsg) error {
+func requestUI(ctx context.Context, transport uiTransport, request WebsocketMsg) error {
 	request.Type = WebsocketMsgTypeRequest
 	request.Success = true
-	marshalData, marshalErr := json.Marshal(request)
-	if marshalErr != nil {
-		logger.Error(ctx, fmt.Sprintf("failed to marshal websocket request: %s", marshalErr.Error()))
-		return marshalErr
-	}
 
 	jsonData, _ := json.Marshal(request.Data)
 	util.GetLogger().Info(ctx, fmt.Sprintf("[Wox -> UI] %s: %s", request.Method, jsonData))
-	return m.Broadcast(marshalData)
+	sendErr := transport.Send(ctx, request)
+	if sendErr != nil {
+		logger.Error(ctx, fmt.Sprintf("failed to send websocket request: %s", sendErr.Error()))
+		return sendErr
+	}
+
+	return nil
 }
 
 func responseUI(ctx context.Context, response WebsocketMsg) {
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"wox/plugin"
+	"wox/setting"
+	"wox/share"
+	"wox/util"
+
+	"github.com/samber/lo"
+)
+
+// restoreLastQuery shows the last input query again when Wox is shown, see setting.LastQueryModeRestore.
+// Selection queries are never restored, the latest input query before them is restored instead.
+// If the last input query is older than RestoreLastQueryWindowSeconds, Wox starts with an empty query
+func (m *Manager) restoreLastQuery(ctx context.Context) {
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+	if woxSetting.LastQueryMode != setting.LastQueryModeRestore {
+		return
+	}
+
+	// UI keeps the query box and results when hidden in restore mode, except for selection queries
+	hidden := m.lastHiddenQuery.Load()
+	uiHasQuery := hidden != nil && hidden.QueryType == plugin.QueryTypeInput && hidden.QueryText != ""
+
+	history, found := lo.Find(setting.GetSettingManager().GetLatestQueryHistory(ctx, 10), func(item setting.QueryHistory) bool {
+		return item.Query.QueryType == plugin.QueryTypeInput
+	})
+	if !found || util.GetSystemTimestamp()-history.Timestamp > int64(woxSetting.RestoreLastQueryWindowSeconds)*1000 {
+		if uiHasQuery {
+			logger.Debug(ctx, "last query is stale, start with empty query")
+			m.GetUI(ctx).ChangeQuery(ctx, share.PlainQuery{QueryType: plugin.QueryTypeInput})
+		}
+		return
+	}
+
+	if uiHasQuery && hidden.QueryText == history.Query.QueryText && !woxSetting.RerunRestoredQuery {
+		// results of the query are still shown
+		return
+	}
+
+	logger.Debug(ctx, fmt.Sprintf("restore last query: %s", history.Query.QueryText))
+	m.GetUI(ctx).ChangeQuery(ctx, share.PlainQuery{
+		QueryType: plugin.QueryTypeInput,
+		QueryText: history.Query.QueryText,
+	})
+}
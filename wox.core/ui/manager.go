@@ -10,6 +10,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"wox/i18n"
 	"wox/plugin"
 	"wox/resource"
@@ -47,6 +48,9 @@ type Manager struct {
 
 	activeWindowName string //active window name before wox is activated
 	activeWindowPid  int    //active window pid before wox is activated
+
+	lastHiddenQuery atomic.Pointer[share.PlainQuery] // query shown in UI when Wox was hidden, see restoreLastQuery
+	queryCoalescer  queryCoalescer                   // drops queries superseded within a storm of query updates, see handleWebsocketQuery
 }
 
 func GetUIManager() *Manager {
@@ -56,6 +60,7 @@ func GetUIManager() *Manager {
 		managerInstance.selectionHotkey = &hotkey.Hotkey{}
 		managerInstance.ui = &uiImpl{
 			requestMap: util.NewHashMap[string, chan WebsocketMsg](),
+			transport:  &websocketTransport{},
 		}
 		managerInstance.themes = util.NewHashMap[string, share.Theme]()
 		logger = util.GetLogger()
@@ -241,6 +246,20 @@ func (m *Manager) RegisterQueryHotkey(ctx context.Context, queryHotkey setting.Q
 	err := hk.Register(ctx, queryHotkey.Hotkey, func() {
 		newCtx := util.NewTraceContext()
 		query := plugin.GetPluginManager().ReplaceQueryVariable(newCtx, queryHotkey.Query)
+		if queryHotkey.HeadlessAction != "" {
+			// action may take a while, hotkey callback must return quickly so following hotkeys are not blocked
+			util.Go(newCtx, fmt.Sprintf("headless action of hotkey %s", queryHotkey.Hotkey), func() {
+				if err := plugin.GetPluginManager().ExecuteHeadlessActionByReference(newCtx, queryHotkey.HeadlessAction, query); err != nil {
+					logger.Error(newCtx, fmt.Sprintf("failed to execute headless action of hotkey %s: %s", queryHotkey.Hotkey, err.Error()))
+					m.ui.Notify(newCtx, share.NotifyMsg{
+						Text:           err.Error(),
+						DisplaySeconds: 5,
+					})
+				}
+			})
+			return
+		}
+
 		plainQuery := share.PlainQuery{
 			QueryType: plugin.QueryTypeInput,
 			QueryText: query,
@@ -380,7 +399,7 @@ func (m *Manager) ChangeTheme(ctx context.Context, theme share.Theme) {
 func (m *Manager) ToggleWindow() {
 	ctx := util.NewTraceContext()
 	logger.Info(ctx, "[UI] toggle window")
-	requestUI(ctx, WebsocketMsg{
+	requestUI(ctx, m.ui.(*uiImpl).transport, WebsocketMsg{
 		RequestId: uuid.NewString(),
 		Method:    "toggleWindow",
 	})
@@ -406,6 +425,9 @@ func (m *Manager) PostUIReady(ctx context.Context) {
 }
 
 func (m *Manager) PostOnShow(ctx context.Context) {
+	plugin.GetPluginManager().StartQuerySession(ctx)
+	m.restoreLastQuery(ctx)
+
 	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
 	if woxSetting.SwitchInputMethodABC {
 		util.GetLogger().Info(ctx, "switch input method to ABC")
@@ -417,7 +439,9 @@ func (m *Manager) PostOnShow(ctx context.Context) {
 }
 
 func (m *Manager) PostOnHide(ctx context.Context, query share.PlainQuery) {
+	m.lastHiddenQuery.Store(&query)
 	setting.GetSettingManager().AddQueryHistory(ctx, query)
+	plugin.GetPluginManager().EndQuerySession(ctx)
 }
 
 func (m *Manager) IsSystemTheme(id string) bool {
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// queryCoalescer protects the query pipeline from storms of query updates (E.g. pasting, IME or key repeat sending many updates at once),
+// independent of per plugin debounce. A query arriving after a quiet period is processed immediately, so normal typing gets no delay.
+// A query arriving within the window after the previous one waits for the window and is dropped if a newer query arrived meanwhile,
+// so only the latest query of a storm is processed and the settled query is always processed. See setting.WoxSetting.QueryCoalesceWindow
+//
+// Each websocket message is handled in its own goroutine, so queries are ordered by the time UI sent them instead of arrival,
+// a query arriving after a newer one is dropped right away
+type queryCoalescer struct {
+	mu           sync.Mutex
+	latestSentAt int64
+	lastArrival  time.Time
+}
+
+// settle returns false if the query is superseded by a newer one and should be dropped.
+// sentAt is the time UI sent the query, in any unit as long as UI is consistent.
+// Queries still being composed by IME are always delayed, as user is likely to keep typing
+func (c *queryCoalescer) settle(ctx context.Context, window time.Duration, isComposing bool, sentAt int64) bool {
+	if window <= 0 {
+		return true
+	}
+
+	c.mu.Lock()
+	if sentAt < c.latestSentAt {
+		c.mu.Unlock()
+		return false
+	}
+	c.latestSentAt = sentAt
+	now := time.Now()
+	inStorm := !c.lastArrival.IsZero() && now.Sub(c.lastArrival) < window
+	c.lastArrival = now
+	c.mu.Unlock()
+
+	if !inStorm && !isComposing {
+		return true
+	}
+
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return sentAt == c.latestSentAt
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCoalescer_QuietQueryIsNotDelayed(t *testing.T) {
+	var c queryCoalescer
+	start := time.Now()
+	assert.True(t, c.settle(context.Background(), time.Second, false, 1))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestQueryCoalescer_OnlyLatestOfStormIsProcessed(t *testing.T) {
+	var c queryCoalescer
+	window := 50 * time.Millisecond
+
+	// first query of the storm is processed immediately
+	assert.True(t, c.settle(context.Background(), window, false, 1))
+
+	settled := make([]bool, 5)
+	var wg sync.WaitGroup
+	for i := range settled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			settled[i] = c.settle(context.Background(), window, false, int64(i+2))
+		}()
+		time.Sleep(5 * time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []bool{false, false, false, false, true}, settled)
+}
+
+func TestQueryCoalescer_ComposingQueryIsDelayed(t *testing.T) {
+	var c queryCoalescer
+	start := time.Now()
+	assert.True(t, c.settle(context.Background(), 30*time.Millisecond, true, 1))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestQueryCoalescer_Disabled(t *testing.T) {
+	var c queryCoalescer
+	for i := range 3 {
+		assert.True(t, c.settle(context.Background(), -1, true, int64(3-i)))
+	}
+}
+
+func TestQueryCoalescer_OrderedBySentTime(t *testing.T) {
+	var c queryCoalescer
+	window := 50 * time.Millisecond
+
+	// newer query arrives first, the older one arriving afterwards is dropped without waiting
+	assert.True(t, c.settle(context.Background(), window, false, 2))
+	start := time.Now()
+	assert.False(t, c.settle(context.Background(), window, false, 1))
+	assert.Less(t, time.Since(start), window)
+}
+
+func TestQueryCoalescer_OlderQueryArrivingInStormIsDropped(t *testing.T) {
+	var c queryCoalescer
+	window := 50 * time.Millisecond
+	assert.True(t, c.settle(context.Background(), window, false, 1))
+
+	settled := make(chan bool, 1)
+	go func() {
+		settled <- c.settle(context.Background(), window, false, 3)
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	// sent before the pending query but handled after it
+	assert.False(t, c.settle(context.Background(), window, false, 2))
+	assert.True(t, <-settled)
+}
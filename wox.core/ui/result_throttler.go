@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"wox/plugin"
+	"wox/util"
+)
+
+// resultThrottler coalesces results of a query arriving within a window into a single frame, so chatty plugins won't flood the socket
+// and the renderer with a frame per batch. Pending results are merged (a result sent again with the same id replaces the pending one),
+// each frame carries at most batchSize results with the best scores, the rest waits for the next frame. Done always flushes all pending
+// results at once, so the final state is sent before the query is finished. See setting.WoxSetting.ResultFlushWindow and ResultFlushBatchSize
+type resultThrottler struct {
+	lock      sync.Mutex
+	pending   []plugin.QueryResultUI
+	window    time.Duration // negative or zero means results are sent as soon as they arrive
+	batchSize int           // negative or zero means no limit
+	onFlush   func(results []plugin.QueryResultUI, reason string)
+	done      bool
+	cancel    context.CancelFunc
+}
+
+func newResultThrottler(window time.Duration, batchSize int, onFlush func(results []plugin.QueryResultUI, reason string)) *resultThrottler {
+	return &resultThrottler{
+		window:    window,
+		batchSize: batchSize,
+		onFlush:   onFlush,
+	}
+}
+
+func (t *resultThrottler) Start(ctx context.Context) {
+	if t.window <= 0 {
+		return
+	}
+
+	tickerCtx, cancel := context.WithCancel(ctx)
+	t.cancel = cancel
+	ticker := time.NewTicker(t.window)
+	util.Go(ctx, "result throttler ticker", func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.flush("tick", false)
+			case <-tickerCtx.Done():
+				return
+			}
+		}
+	})
+}
+
+func (t *resultThrottler) Add(ctx context.Context, results []plugin.QueryResultUI) {
+	t.lock.Lock()
+	if t.done {
+		t.lock.Unlock()
+		return
+	}
+	for _, result := range results {
+		if index := slices.IndexFunc(t.pending, func(item plugin.QueryResultUI) bool { return item.Id == result.Id }); index != -1 {
+			t.pending[index] = result
+		} else {
+			t.pending = append(t.pending, result)
+		}
+	}
+	t.lock.Unlock()
+
+	if t.window <= 0 {
+		t.flush("immediate", true)
+	}
+}
+
+// Done stops throttling and sends all pending results in one frame, results added afterwards are ignored
+func (t *resultThrottler) Done(ctx context.Context) {
+	if t.cancel != nil {
+		t.cancel()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.done {
+		return
+	}
+	t.done = true
+	if len(t.pending) > 0 {
+		frame := t.pending
+		t.pending = nil
+		t.onFlush(frame, "done")
+	}
+}
+
+// flush sends pending results in one frame, limited to batchSize unless all is true.
+// Lock is held while sending, so frames keep their order and no frame is sent after the final one
+func (t *resultThrottler) flush(reason string, all bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.done || len(t.pending) == 0 {
+		return
+	}
+
+	var frame []plugin.QueryResultUI
+	frame, t.pending = takeTopResults(t.pending, t.batchSize, all)
+	t.onFlush(frame, reason)
+}
+
+// takeTopResults splits results into a frame of at most batchSize results with the best scores and the remaining ones,
+// both keep the arrival order of results. batchSize <= 0 or all takes every result
+func takeTopResults(results []plugin.QueryResultUI, batchSize int, all bool) (frame []plugin.QueryResultUI, remaining []plugin.QueryResultUI) {
+	if all || batchSize <= 0 || len(results) <= batchSize {
+		return results, nil
+	}
+
+	ranked := make([]int, len(results))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	slices.SortStableFunc(ranked, func(a, b int) int {
+		if results[a].Score == results[b].Score {
+			return 0
+		}
+		if results[a].Score > results[b].Score {
+			return -1
+		}
+		return 1
+	})
+
+	selected := make([]bool, len(results))
+	for _, index := range ranked[:batchSize] {
+		selected[index] = true
+	}
+	for i, result := range results {
+		if selected[i] {
+			frame = append(frame, result)
+		} else {
+			remaining = append(remaining, result)
+		}
+	}
+	return frame, remaining
+}
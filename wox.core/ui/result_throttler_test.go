@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"wox/plugin"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedFrames struct {
+	lock    sync.Mutex
+	frames  [][]string
+	reasons []string
+}
+
+func (r *recordedFrames) onFlush(results []plugin.QueryResultUI, reason string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.frames = append(r.frames, lo.Map(results, func(item plugin.QueryResultUI, _ int) string { return item.Id }))
+	r.reasons = append(r.reasons, reason)
+}
+
+func TestResultThrottler_CoalescesWithinWindow(t *testing.T) {
+	var recorded recordedFrames
+	throttler := newResultThrottler(50*time.Millisecond, 0, recorded.onFlush)
+	throttler.Start(context.Background())
+
+	for i := 0; i < 5; i++ {
+		throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: string(rune('a' + i))}})
+	}
+	time.Sleep(80 * time.Millisecond)
+	throttler.Done(context.Background())
+
+	assert.Equal(t, [][]string{{"a", "b", "c", "d", "e"}}, recorded.frames)
+	assert.Equal(t, []string{"tick"}, recorded.reasons)
+}
+
+func TestResultThrottler_MergesSameResult(t *testing.T) {
+	var recorded recordedFrames
+	throttler := newResultThrottler(time.Hour, 0, recorded.onFlush)
+	throttler.Start(context.Background())
+
+	throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: "a", Title: "old"}, {Id: "b"}})
+	throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: "a", Title: "new"}})
+	assert.Equal(t, "new", throttler.pending[0].Title)
+	throttler.Done(context.Background())
+
+	assert.Equal(t, [][]string{{"a", "b"}}, recorded.frames)
+}
+
+func TestResultThrottler_DoneSendsAllPending(t *testing.T) {
+	var recorded recordedFrames
+	throttler := newResultThrottler(time.Hour, 2, recorded.onFlush)
+	throttler.Start(context.Background())
+
+	throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: "a"}, {Id: "b"}, {Id: "c"}})
+	throttler.Done(context.Background())
+	// results after done are ignored
+	throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: "d"}})
+
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, recorded.frames)
+	assert.Equal(t, []string{"done"}, recorded.reasons)
+}
+
+func TestResultThrottler_NoWindow(t *testing.T) {
+	var recorded recordedFrames
+	throttler := newResultThrottler(-1, 0, recorded.onFlush)
+	throttler.Start(context.Background())
+
+	throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: "a"}})
+	throttler.Add(context.Background(), []plugin.QueryResultUI{{Id: "b"}})
+	throttler.Done(context.Background())
+
+	assert.Equal(t, [][]string{{"a"}, {"b"}}, recorded.frames)
+}
+
+func TestTakeTopResults(t *testing.T) {
+	results := []plugin.QueryResultUI{{Id: "a", Score: 10}, {Id: "b", Score: 30}, {Id: "c", Score: 20}, {Id: "d", Score: 30}}
+	ids := func(results []plugin.QueryResultUI) []string {
+		return lo.Map(results, func(item plugin.QueryResultUI, _ int) string { return item.Id })
+	}
+
+	frame, remaining := takeTopResults(results, 2, false)
+	assert.Equal(t, []string{"b", "d"}, ids(frame))
+	assert.Equal(t, []string{"a", "c"}, ids(remaining))
+
+	frame, remaining = takeTopResults(results, 3, false)
+	// arrival order is kept in frame
+	assert.Equal(t, []string{"b", "c", "d"}, ids(frame))
+	assert.Equal(t, []string{"a"}, ids(remaining))
+
+	frame, remaining = takeTopResults(results, 2, true)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids(frame))
+	assert.Empty(t, remaining)
+}
@@ -1,11 +1,13 @@
 package ui
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"wox/ai"
 	"wox/i18n"
@@ -16,6 +18,7 @@ import (
 	"wox/util"
 	"wox/util/hotkey"
 
+	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
 	"github.com/samber/lo"
 	"github.com/tidwall/gjson"
@@ -29,6 +32,10 @@ var routers = map[string]func(w http.ResponseWriter, r *http.Request){
 	"/plugin/uninstall": handlePluginUninstall,
 	"/plugin/disable":   handlePluginDisable,
 	"/plugin/enable":    handlePluginEnable,
+	"/plugin/reload":    handlePluginReload,
+
+	"/plugin/settings/export": handlePluginSettingsExport,
+	"/plugin/settings/import": handlePluginSettingsImport,
 
 	//	themes
 	"/theme":           handleTheme,
@@ -67,13 +74,33 @@ var routers = map[string]func(w http.ResponseWriter, r *http.Request){
 	"/ping":             handlePing,
 	"/image":            handleImage,
 	"/preview":          handlePreview,
+	"/preview/action":   handleActionPreview,
+	"/preview/enriched": handleEnrichedPreview,
+	"/preview/tab":      handlePreviewTab,
+	"/preview/stream":   handlePreviewStream,
+	"/preview/peek":     handlePreviewPeek,
+	"/result/expand":    handleResultExpand,
+	"/result/collapse":  handleResultCollapse,
+	"/result/cache":     handleResultCacheUsage,
+	"/result/icon":      handleResultIcon,
 	"/open":             handleOpen,
 	"/backup/now":       handleBackupNow,
 	"/backup/restore":   handleBackupRestore,
 	"/backup/all":       handleBackupAll,
 	"/hotkey/available": handleHotkeyAvailable,
 	"/query/icon":       handleQueryIcon,
+	"/query/export":     handleQueryExport,
 	"/deeplink":         handleDeeplink,
+
+	// suppressed queries
+	"/query/suppressed":        handleSuppressedQueries,
+	"/query/suppressed/add":    handleSuppressedQueryAdd,
+	"/query/suppressed/remove": handleSuppressedQueryRemove,
+	"/query/suppressed/clear":  handleSuppressedQueryClear,
+
+	// headless actions
+	"/action/headless":      handleHeadlessAction,
+	"/action/headless/list": handleHeadlessActionList,
 }
 
 func handleHome(w http.ResponseWriter, r *http.Request) {
@@ -122,6 +149,167 @@ func handlePreview(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, preview)
 }
 
+func handlePreviewTab(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeErrorResponse(w, "id is empty")
+		return
+	}
+	tabId := r.URL.Query().Get("tabId")
+	if tabId == "" {
+		writeErrorResponse(w, "tabId is empty")
+		return
+	}
+
+	// request context is cancelled if UI aborts the request, E.g. user switches to another tab
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	preview, err := plugin.GetPluginManager().GetResultPreviewTab(ctx, id, tabId)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, preview)
+}
+
+func handlePreviewPeek(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeErrorResponse(w, "id is empty")
+		return
+	}
+
+	// request context is cancelled if UI aborts the request, E.g. mouse leaves the result before its preview is loaded
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	preview, err := plugin.GetPluginManager().PeekResultPreview(ctx, id)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, preview)
+}
+
+func handlePreviewStream(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeErrorResponse(w, "id is empty")
+		return
+	}
+	// offset is optional, reads the tail of the file by default
+	offset := int64(-1)
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		parsed, parseErr := strconv.ParseInt(offsetStr, 10, 64)
+		if parseErr != nil {
+			writeErrorResponse(w, fmt.Sprintf("invalid offset: %s", offsetStr))
+			return
+		}
+		offset = parsed
+	}
+	length, _ := strconv.Atoi(r.URL.Query().Get("length"))
+
+	// request context is cancelled if UI aborts the request, E.g. user focuses another result
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	window, err := plugin.GetPluginManager().ReadResultPreviewStream(ctx, id, offset, length)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, window)
+}
+
+func handleEnrichedPreview(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeErrorResponse(w, "id is empty")
+		return
+	}
+
+	// request context is cancelled if UI aborts the request, E.g. user focuses another result
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	preview, err := plugin.GetPluginManager().GetEnrichedResultPreview(ctx, id)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, preview)
+}
+
+func handleActionPreview(w http.ResponseWriter, r *http.Request) {
+	resultId := r.URL.Query().Get("resultId")
+	if resultId == "" {
+		writeErrorResponse(w, "resultId is empty")
+		return
+	}
+	actionId := r.URL.Query().Get("actionId")
+	if actionId == "" {
+		writeErrorResponse(w, "actionId is empty")
+		return
+	}
+
+	// request context is cancelled if UI aborts the request, E.g. user focuses another action
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	preview, err := plugin.GetPluginManager().GetActionPreview(ctx, resultId, actionId)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, preview)
+}
+
+func handleResultExpand(w http.ResponseWriter, r *http.Request) {
+	resultId := r.URL.Query().Get("resultId")
+	if resultId == "" {
+		writeErrorResponse(w, "resultId is empty")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	children, err := plugin.GetPluginManager().ExpandResult(ctx, resultId)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, children)
+}
+
+func handleResultIcon(w http.ResponseWriter, r *http.Request) {
+	resultId := r.URL.Query().Get("resultId")
+	if resultId == "" {
+		writeErrorResponse(w, "resultId is empty")
+		return
+	}
+
+	// request context is cancelled if UI aborts the request, E.g. result scrolls out of view
+	ctx := context.WithValue(r.Context(), util.ContextKeyTraceId, uuid.NewString())
+	icon, err := plugin.GetPluginManager().GetResultIcon(ctx, resultId)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, icon)
+}
+
+func handleResultCollapse(w http.ResponseWriter, r *http.Request) {
+	resultId := r.URL.Query().Get("resultId")
+	if resultId == "" {
+		writeErrorResponse(w, "resultId is empty")
+		return
+	}
+
+	plugin.GetPluginManager().CollapseResult(util.NewTraceContext(), resultId)
+	writeSuccessResponse(w, "")
+}
+
+func handleResultCacheUsage(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponse(w, plugin.GetPluginManager().GetResultCacheUsage(util.NewTraceContext()))
+}
+
 func handleTheme(w http.ResponseWriter, r *http.Request) {
 	theme := GetUIManager().GetCurrentTheme(util.NewTraceContext())
 	writeSuccessResponse(w, theme)
@@ -166,6 +354,7 @@ func handlePluginInstalled(w http.ResponseWriter, r *http.Request) {
 		installedPlugin.IsDev = pluginInstance.IsDevPlugin
 		installedPlugin.IsInstalled = true
 		installedPlugin.IsDisable = pluginInstance.Setting.Disabled
+		installedPlugin.WarmUp = pluginInstance.GetWarmUpStatus()
 
 		//load screenshot urls from store if exist
 		storePlugin, foundErr := plugin.GetStoreManager().GetStorePluginManifestById(getCtx, pluginInstance.Metadata.Id)
@@ -317,8 +506,7 @@ func handlePluginEnable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	findPlugin.Setting.Disabled = false
-	err := findPlugin.SaveSetting(ctx)
+	err := plugin.GetPluginManager().EnablePlugin(ctx, findPlugin)
 	if err != nil {
 		writeErrorResponse(w, "can't enable plugin: "+err.Error())
 		return
@@ -327,6 +515,25 @@ func handlePluginEnable(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, "")
 }
 
+func handlePluginReload(w http.ResponseWriter, r *http.Request) {
+	ctx := util.NewTraceContext()
+
+	// reload given plugin only if id is specified, otherwise reload all user plugins
+	body, _ := io.ReadAll(r.Body)
+	var reloadErr error
+	if idResult := gjson.GetBytes(body, "id"); idResult.Exists() {
+		reloadErr = plugin.GetPluginManager().ReloadPlugin(ctx, idResult.String())
+	} else {
+		reloadErr = plugin.GetPluginManager().Reload(ctx)
+	}
+	if reloadErr != nil {
+		writeErrorResponse(w, "can't reload plugins: "+reloadErr.Error())
+		return
+	}
+
+	writeSuccessResponse(w, "")
+}
+
 func handleThemeStore(w http.ResponseWriter, r *http.Request) {
 	ctx := util.NewTraceContext()
 
@@ -478,6 +685,8 @@ func handleSettingWox(w http.ResponseWriter, r *http.Request) {
 	settingDto.QueryHotkeys = woxSetting.QueryHotkeys.Get()
 	settingDto.HttpProxyEnabled = woxSetting.HttpProxyEnabled.Get()
 	settingDto.HttpProxyUrl = woxSetting.HttpProxyUrl.Get()
+	settingDto.CustomBrowserPath = woxSetting.CustomBrowserPath.Get()
+	settingDto.TerminalCommand = woxSetting.TerminalCommand.Get()
 
 	writeSuccessResponse(w, settingDto)
 }
@@ -536,11 +745,32 @@ func handleSettingPluginUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if kv.Key == "Disabled" {
-		pluginInstance.Setting.Disabled = kv.Value == "true"
-		pluginInstance.SaveSetting(ctx)
+		if kv.Value == "true" {
+			pluginInstance.Setting.Disabled = true
+			pluginInstance.SaveSetting(ctx)
+		} else {
+			plugin.GetPluginManager().EnablePlugin(ctx, pluginInstance)
+		}
 	} else if kv.Key == "TriggerKeywords" {
 		pluginInstance.Setting.TriggerKeywords = strings.Split(kv.Value, ",")
 		pluginInstance.SaveSetting(ctx)
+	} else if kv.Key == "ScoreMultiplier" {
+		multiplier, parseErr := strconv.ParseFloat(kv.Value, 64)
+		if parseErr != nil || multiplier <= 0 {
+			writeErrorResponse(w, fmt.Sprintf("invalid score multiplier: %s", kv.Value))
+			return
+		}
+		pluginInstance.Setting.ScoreMultiplier = multiplier
+		pluginInstance.SaveSetting(ctx)
+	} else if kv.Key == "CaseInsensitiveTriggerKeywords" {
+		pluginInstance.Setting.CaseInsensitiveTriggerKeywords = kv.Value == "true"
+		pluginInstance.SaveSetting(ctx)
+	} else if kv.Key == "ExcludeFromExport" {
+		pluginInstance.Setting.ExcludeFromExport = kv.Value == "true"
+		pluginInstance.SaveSetting(ctx)
+	} else if kv.Key == "PrefixQueryCommands" {
+		pluginInstance.Setting.PrefixQueryCommands = kv.Value == "true"
+		pluginInstance.SaveSetting(ctx)
 	} else {
 		var isPlatformSpecific = false
 		for _, settingDefinition := range pluginInstance.Metadata.SettingDefinitions {
@@ -578,6 +808,43 @@ func handleBackupNow(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, "")
 }
 
+func handlePluginSettingsExport(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	pathResult := gjson.GetBytes(body, "path")
+	if !pathResult.Exists() || pathResult.String() == "" {
+		writeErrorResponse(w, "path is empty")
+		return
+	}
+
+	exportErr := plugin.GetPluginManager().ExportPluginSettingsToFile(util.NewTraceContext(), pathResult.String(), gjson.GetBytes(body, "includeStore").Bool())
+	if exportErr != nil {
+		writeErrorResponse(w, exportErr.Error())
+		return
+	}
+
+	writeSuccessResponse(w, "")
+}
+
+func handlePluginSettingsImport(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	pathResult := gjson.GetBytes(body, "path")
+	if !pathResult.Exists() || pathResult.String() == "" {
+		writeErrorResponse(w, "path is empty")
+		return
+	}
+
+	report, importErr := plugin.GetPluginManager().ImportPluginSettingsFromFile(util.NewTraceContext(), pathResult.String(), plugin.PluginSettingsImportOptions{
+		DryRun:       gjson.GetBytes(body, "dryRun").Bool(),
+		KeepExisting: gjson.GetBytes(body, "keepExisting").Bool(),
+	})
+	if importErr != nil {
+		writeErrorResponse(w, importErr.Error())
+		return
+	}
+
+	writeSuccessResponse(w, report)
+}
+
 func handleBackupRestore(w http.ResponseWriter, r *http.Request) {
 	body, _ := io.ReadAll(r.Body)
 	idResult := gjson.GetBytes(body, "id")
@@ -742,6 +1009,40 @@ func handleQueryIcon(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, iconImage)
 }
 
+// handleQueryExport runs the query and returns its results in the export format, see plugin.ResultExport
+func handleQueryExport(w http.ResponseWriter, r *http.Request) {
+	ctx := util.NewTraceContext()
+
+	body, _ := io.ReadAll(r.Body)
+	queryResult := gjson.GetBytes(body, "query")
+	if !queryResult.Exists() {
+		writeErrorResponse(w, "query is empty")
+		return
+	}
+
+	var plainQuery share.PlainQuery
+	unmarshalErr := json.Unmarshal([]byte(queryResult.String()), &plainQuery)
+	if unmarshalErr != nil {
+		logger.Error(ctx, unmarshalErr.Error())
+		writeErrorResponse(w, unmarshalErr.Error())
+		return
+	}
+
+	query, _, err := plugin.GetPluginManager().NewQuery(ctx, plainQuery)
+	if err != nil {
+		writeErrorResponse(w, err.Error())
+		return
+	}
+
+	results, queryErr := plugin.GetPluginManager().QuerySync(ctx, query)
+	if queryErr != nil {
+		// results collected before timeout are still exported
+		logger.Warn(ctx, fmt.Sprintf("export query is not complete: %s", queryErr.Error()))
+	}
+
+	writeSuccessResponse(w, plugin.GetPluginManager().ExportResults(ctx, query.RawQuery, results))
+}
+
 func handleDeeplink(w http.ResponseWriter, r *http.Request) {
 	ctx := util.NewTraceContext()
 
@@ -757,6 +1058,32 @@ func handleDeeplink(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, "")
 }
 
+// handleHeadlessAction executes a headless action without a query, body is {"action": "<plugin id or name>:<action name>", "contextData": "..."}.
+// It responds after the action is finished, see plugin.HeadlessAction
+func handleHeadlessAction(w http.ResponseWriter, r *http.Request) {
+	ctx := util.NewTraceContext()
+
+	body, _ := io.ReadAll(r.Body)
+	action := gjson.GetBytes(body, "action").String()
+	if action == "" {
+		writeErrorResponse(w, "action is empty")
+		return
+	}
+
+	executeErr := plugin.GetPluginManager().ExecuteHeadlessActionByReference(ctx, action, gjson.GetBytes(body, "contextData").String())
+	if executeErr != nil {
+		logger.Error(ctx, executeErr.Error())
+		writeErrorResponse(w, executeErr.Error())
+		return
+	}
+
+	writeSuccessResponse(w, "")
+}
+
+func handleHeadlessActionList(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponse(w, plugin.GetPluginManager().GetHeadlessActions())
+}
+
 func handleAIModels(w http.ResponseWriter, r *http.Request) {
 	ctx := util.NewTraceContext()
 
@@ -829,3 +1156,55 @@ func handleUserDataLocationUpdate(w http.ResponseWriter, r *http.Request) {
 	logger.Info(ctx, fmt.Sprintf("User data directory successfully changed to: %s", newLocation))
 	writeSuccessResponse(w, "User data directory updated successfully")
 }
+
+func handleSuppressedQueries(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponse(w, setting.GetSettingManager().GetSuppressedQueries(util.NewTraceContext()))
+}
+
+// parseSuppressedQuery reads pluginId, query and scope (default exact) from request body
+func parseSuppressedQuery(r *http.Request) (pluginId string, query string, scope setting.SuppressedQueryScope, err error) {
+	body, _ := io.ReadAll(r.Body)
+	pluginId = gjson.GetBytes(body, "pluginId").String()
+	if pluginId == "" {
+		return "", "", "", fmt.Errorf("pluginId is empty")
+	}
+	query = gjson.GetBytes(body, "query").String()
+	if query == "" {
+		return "", "", "", fmt.Errorf("query is empty")
+	}
+	scope = setting.SuppressedQueryScope(gjson.GetBytes(body, "scope").String())
+	if scope == "" {
+		scope = setting.SuppressedQueryScopeExact
+	}
+	return pluginId, query, scope, nil
+}
+
+func handleSuppressedQueryAdd(w http.ResponseWriter, r *http.Request) {
+	pluginId, query, scope, parseErr := parseSuppressedQuery(r)
+	if parseErr != nil {
+		writeErrorResponse(w, parseErr.Error())
+		return
+	}
+
+	if addErr := setting.GetSettingManager().AddSuppressedQuery(util.NewTraceContext(), pluginId, query, scope); addErr != nil {
+		writeErrorResponse(w, addErr.Error())
+		return
+	}
+	writeSuccessResponse(w, "")
+}
+
+func handleSuppressedQueryRemove(w http.ResponseWriter, r *http.Request) {
+	pluginId, query, scope, parseErr := parseSuppressedQuery(r)
+	if parseErr != nil {
+		writeErrorResponse(w, parseErr.Error())
+		return
+	}
+
+	setting.GetSettingManager().RemoveSuppressedQuery(util.NewTraceContext(), pluginId, query, scope)
+	writeSuccessResponse(w, "")
+}
+
+func handleSuppressedQueryClear(w http.ResponseWriter, r *http.Request) {
+	setting.GetSettingManager().ClearSuppressedQueries(util.NewTraceContext())
+	writeSuccessResponse(w, "")
+}
@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// uiTransport delivers websocket messages from wox to UI.
+// It's abstracted from the global websocket server so that uiImpl can be tested with a fake transport.
+type uiTransport interface {
+	Send(ctx context.Context, msg WebsocketMsg) error
+}
+
+// websocketTransport broadcasts messages to all UI clients connected to the websocket server
+type websocketTransport struct{}
+
+func (t *websocketTransport) Send(ctx context.Context, msg WebsocketMsg) error {
+	marshalData, marshalErr := json.Marshal(msg)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	return m.Broadcast(marshalData)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 	"wox/plugin"
 	"wox/setting"
@@ -21,6 +23,8 @@ import (
 
 type uiImpl struct {
 	requestMap *util.HashMap[string, chan WebsocketMsg]
+	transport  uiTransport
+	statuses   uiStatusStack
 }
 
 func (u *uiImpl) ChangeQuery(ctx context.Context, query share.PlainQuery) {
@@ -86,6 +90,75 @@ func (u *uiImpl) Notify(ctx context.Context, msg share.NotifyMsg) {
 	}
 }
 
+func (u *uiImpl) SetStatus(ctx context.Context, msg share.StatusMsg) {
+	u.statuses.push(msg)
+	u.invokeWebsocketMethod(ctx, "SetStatus", msg)
+}
+
+func (u *uiImpl) ClearStatus(ctx context.Context, pluginId string) {
+	current, exist := u.statuses.remove(pluginId)
+	if !exist {
+		u.invokeWebsocketMethod(ctx, "ClearStatus", nil)
+		return
+	}
+	u.invokeWebsocketMethod(ctx, "SetStatus", current)
+}
+
+func (u *uiImpl) UpdateResults(ctx context.Context, results any) {
+	u.invokeWebsocketMethod(ctx, "UpdateResults", results)
+}
+
+func (u *uiImpl) UpdateResultScores(ctx context.Context, scores any) {
+	u.invokeWebsocketMethod(ctx, "UpdateResultScores", scores)
+}
+
+func (u *uiImpl) UpdatePreview(ctx context.Context, resultId string, preview any) {
+	u.invokeWebsocketMethod(ctx, "UpdatePreview", map[string]any{
+		"ResultId": resultId,
+		"Preview":  preview,
+	})
+}
+
+func (u *uiImpl) ExecuteBatch(ctx context.Context, commands []share.UICommand) error {
+	if len(commands) == 0 {
+		return nil
+	}
+	if len(commands) > share.MaxUICommandBatchSize {
+		return fmt.Errorf("too many commands in batch: %d, max: %d", len(commands), share.MaxUICommandBatchSize)
+	}
+
+	var batch []map[string]any
+	for _, command := range commands {
+		var data any
+		switch command.Type {
+		case share.UICommandTypeChangeQuery:
+			data = command.Query
+		case share.UICommandTypeHideApp:
+			data = nil
+		case share.UICommandTypeShowApp:
+			GetUIManager().SetActiveWindowName(window.GetActiveWindowName())
+			GetUIManager().SetActiveWindowPid(window.GetActiveWindowPid())
+			data = getShowAppParams(ctx, command.ShowContext.SelectAll)
+		case share.UICommandTypeShowToolbarMsg:
+			data = command.Msg
+		default:
+			return fmt.Errorf("unsupported ui command in batch: %s", command.Type)
+		}
+		batch = append(batch, map[string]any{
+			"Method": string(command.Type),
+			"Data":   data,
+		})
+	}
+
+	// ui applies commands in order and stops at the first failed one, response data tells which command failed
+	respData, err := u.invokeWebsocketMethod(ctx, "Batch", batch)
+	if err != nil {
+		return fmt.Errorf("failed to execute ui command batch: %s, %v", err.Error(), respData)
+	}
+
+	return nil
+}
+
 func (u *uiImpl) isNotifyInToolbar(ctx context.Context, pluginId string) bool {
 	isVisible, err := u.invokeWebsocketMethod(ctx, "IsVisible", nil)
 	if err != nil {
@@ -136,21 +209,25 @@ func (u *uiImpl) isNotifyInToolbar(ctx context.Context, pluginId string) bool {
 	return false
 }
 
-func (u *uiImpl) PickFiles(ctx context.Context, params share.PickFilesParams) []string {
+func (u *uiImpl) PickFiles(ctx context.Context, params share.PickFilesParams) ([]string, error) {
 	respData, err := u.invokeWebsocketMethod(ctx, "PickFiles", params)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	// cancelled by user
+	if respData == nil {
+		return nil, nil
 	}
 	if _, ok := respData.([]any); !ok {
 		logger.Error(ctx, fmt.Sprintf("pick files response data type error: %T", respData))
-		return nil
+		return nil, fmt.Errorf("unexpected pick files response: %T", respData)
 	}
 
 	var result []string
 	lo.ForEach(respData.([]any), func(file any, _ int) {
 		result = append(result, file.(string))
 	})
-	return result
+	return result, nil
 }
 
 func (u *uiImpl) GetActiveWindowName() string {
@@ -161,7 +238,19 @@ func (u *uiImpl) GetActiveWindowPid() int {
 	return GetUIManager().GetActiveWindowPid()
 }
 
+var errUIRequestTimeout = errors.New("request timeout")
+
 func (u *uiImpl) invokeWebsocketMethod(ctx context.Context, method string, data any) (responseData any, responseErr error) {
+	var timeout = time.Second * 2
+	if method == "PickFiles" {
+		// pick files may take a long time
+		timeout = time.Second * 180
+	}
+	return u.invokeWebsocketMethodWithTimeout(ctx, method, data, timeout)
+}
+
+// invokeWebsocketMethodWithTimeout waits for the response of UI until timeout or ctx is done
+func (u *uiImpl) invokeWebsocketMethodWithTimeout(ctx context.Context, method string, data any, timeout time.Duration) (responseData any, responseErr error) {
 	requestID := uuid.NewString()
 	resultChan := make(chan WebsocketMsg)
 	u.requestMap.Store(requestID, resultChan)
@@ -169,7 +258,7 @@ func (u *uiImpl) invokeWebsocketMethod(ctx context.Context, method string, data
 
 	traceId := util.GetContextTraceId(ctx)
 
-	err := requestUI(ctx, WebsocketMsg{
+	err := requestUI(ctx, u.transport, WebsocketMsg{
 		RequestId: requestID,
 		TraceId:   traceId,
 		Method:    method,
@@ -180,15 +269,12 @@ func (u *uiImpl) invokeWebsocketMethod(ctx context.Context, method string, data
 		return "", err
 	}
 
-	var timeout = time.Second * 2
-	if method == "PickFiles" {
-		// pick files may take a long time
-		timeout = time.Second * 180
-	}
 	select {
 	case <-time.NewTimer(timeout).C:
 		logger.Error(ctx, fmt.Sprintf("invoke ui method %s response timeout", method))
-		return "", fmt.Errorf("request timeout, request id: %s", requestID)
+		return "", fmt.Errorf("%w, request id: %s", errUIRequestTimeout, requestID)
+	case <-ctx.Done():
+		return "", ctx.Err()
 	case response := <-resultChan:
 		if !response.Success {
 			return response.Data, errors.New("ui method response error")
@@ -217,6 +303,7 @@ func getShowAppParams(ctx context.Context, selectAll bool) map[string]any {
 		"Position":       position,
 		"QueryHistories": setting.GetSettingManager().GetLatestQueryHistory(ctx, 10),
 		"LastQueryMode":  woxSetting.LastQueryMode,
+		"EscapeMode":     woxSetting.EscapeMode,
 	}
 }
 
@@ -233,8 +320,30 @@ func onUIWebsocketRequest(ctx context.Context, request WebsocketMsg) {
 		handleWebsocketQuery(ctx, request)
 	case "Action":
 		handleWebsocketAction(ctx, request)
+	case "BatchAction":
+		handleWebsocketBatchAction(ctx, request)
+	case "ActionByIndex":
+		handleWebsocketActionByIndex(ctx, request)
+	case "CancelAction":
+		handleWebsocketCancelAction(ctx, request)
+	case "ValidateActionInput":
+		handleWebsocketValidateActionInput(ctx, request)
+	case "CancelActionInput":
+		handleWebsocketCancelActionInput(ctx, request)
 	case "Refresh":
 		handleWebsocketRefresh(ctx, request)
+	case "GetQueryStat":
+		handleWebsocketGetQueryStat(ctx, request)
+	case "DumpState":
+		handleWebsocketDumpState(ctx, request)
+	case "QuerySuggestions":
+		handleWebsocketQuerySuggestions(ctx, request)
+	case "FocusPreview":
+		handleWebsocketFocusPreview(ctx, request)
+	case "SetResultGroupCollapsed":
+		handleWebsocketSetResultGroupCollapsed(ctx, request)
+	case "Escape":
+		handleWebsocketEscape(ctx, request)
 	}
 }
 
@@ -294,6 +403,15 @@ func handleWebsocketLog(ctx context.Context, request WebsocketMsg) {
 	responseUISuccess(ctx, request)
 }
 
+// getUITransport returns transport of the websocket UI, false if UI is not a websocket UI (E.g. a mock in tests)
+func getUITransport(ctx context.Context) (uiTransport, bool) {
+	ui, ok := GetUIManager().GetUI(ctx).(*uiImpl)
+	if !ok {
+		return nil, false
+	}
+	return ui.transport, true
+}
+
 func handleWebsocketQuery(ctx context.Context, request WebsocketMsg) {
 	queryId, queryIdErr := getWebsocketMsgParameter(ctx, request, "queryId")
 	if queryIdErr != nil {
@@ -340,32 +458,153 @@ func handleWebsocketQuery(ctx context.Context, request WebsocketMsg) {
 		return
 	}
 
+	// isComposing is optional, true if the query text contains uncommitted IME composition
+	isComposing := false
+	if isComposingStr, isComposingErr := getWebsocketMsgParameter(ctx, request, "isComposing"); isComposingErr == nil {
+		isComposing = isComposingStr == "true"
+	}
+	// queryTimestamp is optional, time UI sent the query, messages are handled concurrently so arrival order is not reliable
+	queryTimestamp := time.Now().UnixMicro()
+	if queryTimestampStr, queryTimestampErr := getWebsocketMsgParameter(ctx, request, "queryTimestamp"); queryTimestampErr == nil {
+		if parsed, parseErr := strconv.ParseInt(queryTimestampStr, 10, 64); parseErr == nil {
+			queryTimestamp = parsed
+		}
+	}
+	if changedQuery.QueryType == plugin.QueryTypeInput {
+		coalesceWindow := time.Duration(setting.GetSettingManager().GetWoxSetting(ctx).QueryCoalesceWindow) * time.Millisecond
+		if !GetUIManager().queryCoalescer.settle(ctx, coalesceWindow, isComposing, queryTimestamp) {
+			logger.Debug(ctx, fmt.Sprintf("query superseded by a newer query, skip: %s, queryId: %s", changedQuery.String(), queryId))
+			responseUISuccessWithData(ctx, request, []string{})
+			return
+		}
+	}
+
 	logger.Info(ctx, fmt.Sprintf("start to handle query changed: %s, queryId: %s", changedQuery.String(), queryId))
 
 	if changedQuery.QueryType == plugin.QueryTypeInput && changedQuery.QueryText == "" {
-		responseUISuccessWithData(ctx, request, []string{})
-		return
+		// show results preloaded when Wox was shown if any, see plugin.MetadataFeaturePreloadOnShow
+		preloadedResults := plugin.GetPluginManager().GetPreloadedResults(ctx)
+		isEmptyQuerySupported := plugin.GetPluginManager().IsEmptyQuerySupported()
+		if len(preloadedResults) == 0 && !isEmptyQuerySupported {
+			responseUISuccessWithData(ctx, request, []string{})
+			return
+		}
+		if len(preloadedResults) > 0 {
+			lo.ForEach(preloadedResults, func(_ plugin.QueryResultUI, index int) {
+				preloadedResults[index].QueryId = queryId
+			})
+			plugin.GetPluginManager().RecordShownResults(ctx, queryId, preloadedResults)
+			responseUISuccessWithData(ctx, request, preloadedResults)
+		}
+		// plugins opted in for empty queries are queried as usual, see plugin.MetadataFeatureEmptyQuery
+		if !isEmptyQuerySupported {
+			return
+		}
 	}
 	if changedQuery.QueryType == plugin.QueryTypeSelection && changedQuery.QuerySelection.String() == "" {
 		responseUISuccessWithData(ctx, request, []string{})
 		return
 	}
 
-	query, queryPlugin, queryErr := plugin.GetPluginManager().NewQuery(ctx, changedQuery)
+	query, queryPlugin, queryErr := plugin.GetPluginManager().NewUIQuery(ctx, changedQuery)
 	if queryErr != nil {
 		logger.Error(ctx, queryErr.Error())
 		responseUIError(ctx, request, queryErr.Error())
 		return
 	}
 
+	plugin.GetPluginManager().TrackQueryIssued(ctx, queryId, query, queryPlugin)
+
+	// leave the query when this request is finished, so the query pipeline can be cancelled if no one else is waiting for it
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// freezeOrder is optional, if true results already shown won't be reordered by later batches
+	freezeOrder := false
+	if freezeOrderStr, freezeOrderErr := getWebsocketMsgParameter(ctx, request, "freezeOrder"); freezeOrderErr == nil {
+		freezeOrder = freezeOrderStr == "true"
+	}
+	orderKeeper := plugin.NewResultOrderKeeper(freezeOrder)
+
+	// read per query so changed settings take effect for the next query
+	woxSetting := setting.GetSettingManager().GetWoxSetting(ctx)
+
 	var totalResultCount int
 	var startTimestamp = util.GetSystemTimestamp()
-	var resultDebouncer = util.NewDebouncer(24, func(results []plugin.QueryResultUI, reason string) {
-		logger.Info(ctx, fmt.Sprintf("query %s: %s, result flushed (reason: %s), total results: %d", query.Type, query.String(), reason, totalResultCount))
+	resultFlushWindow := time.Duration(woxSetting.ResultFlushWindow) * time.Millisecond
+	var resultFlusher = newResultThrottler(resultFlushWindow, woxSetting.ResultFlushBatchSize, func(results []plugin.QueryResultUI, reason string) {
+		results = plugin.GetPluginManager().ProcessResults(ctx, query, results)
+		orderKeeper.Keep(results)
+		plugin.GetPluginManager().RecordShownResults(ctx, queryId, results)
+		logger.Info(ctx, fmt.Sprintf("query %s: %s, %d results flushed (reason: %s), total results: %d", query.Type, query.String(), len(results), reason, totalResultCount))
 		responseUISuccessWithData(ctx, request, results)
 	})
-	resultDebouncer.Start(ctx)
+	resultFlusher.Start(ctx)
 	logger.Info(ctx, fmt.Sprintf("query %s: %s, result flushed (new start)", query.Type, query.String()))
+	// finish flushes the remaining results, it's called when all plugins are done or soft deadline is reached
+	finish := func() {
+		// if there is no result, show fallback search, there is nothing to search for an empty query
+		if totalResultCount == 0 && !query.IsEmptyInput() {
+			fallbackResults := plugin.GetPluginManager().QueryFallback(ctx, query, queryPlugin)
+			if len(fallbackResults) > 0 {
+				lo.ForEach(fallbackResults, func(_ plugin.QueryResultUI, index int) {
+					fallbackResults[index].QueryId = queryId
+				})
+				resultFlusher.Add(ctx, fallbackResults)
+				logger.Info(ctx, fmt.Sprintf("no result, show %d fallback results", len(fallbackResults)))
+			} else {
+				logger.Info(ctx, "no result, no fallback results")
+			}
+		}
+
+		resultFlusher.Done(ctx)
+	}
+
+	// soft deadline shows partial results instead of waiting for slow plugins, they are cancelled when this request returns
+	var softDeadlineChan <-chan time.Time
+	if softDeadline := woxSetting.QuerySoftDeadline; softDeadline > 0 {
+		softDeadlineTimer := time.NewTimer(time.Duration(softDeadline) * time.Millisecond)
+		defer softDeadlineTimer.Stop()
+		softDeadlineChan = softDeadlineTimer.C
+	}
+	timeoutTimer := time.NewTimer(time.Duration(woxSetting.QueryTimeout) * time.Millisecond)
+	defer timeoutTimer.Stop()
+
+	// estimated result count is sent before the first batch, it's only a hint, see Manager.EstimateResultCount.
+	// Hints are sent without waiting for UI response so they keep their order, a zero hint tells UI the query is done
+	sendResultCountHint := func(estimatedCount int) {
+		transport, ok := getUITransport(ctx)
+		if !ok {
+			return
+		}
+		requestUI(ctx, transport, WebsocketMsg{
+			RequestId: uuid.NewString(),
+			TraceId:   util.GetContextTraceId(ctx),
+			Method:    "QueryResultCountHint",
+			Data:      plugin.QueryResultCountHint{QueryId: queryId, EstimatedCount: estimatedCount},
+		})
+	}
+	estimatedCount := plugin.GetPluginManager().EstimateResultCount(ctx, query)
+	if estimatedCount > 0 {
+		sendResultCountHint(estimatedCount)
+		defer sendResultCountHint(0)
+	}
+
+	// progress is sent without waiting for UI response, so updates keep their order and won't block the plugin, see plugin.QueryProgress
+	ctx, closeProgress := plugin.NewQueryProgressContext(ctx, queryId, func(progress plugin.QueryProgress) {
+		transport, ok := getUITransport(ctx)
+		if !ok {
+			return
+		}
+		requestUI(ctx, transport, WebsocketMsg{
+			RequestId: uuid.NewString(),
+			TraceId:   util.GetContextTraceId(ctx),
+			Method:    "QueryProgress",
+			Data:      progress,
+		})
+	})
+	defer closeProgress()
+
 	resultChan, doneChan := plugin.GetPluginManager().Query(ctx, query)
 	for {
 		select {
@@ -377,29 +616,18 @@ func handleWebsocketQuery(ctx context.Context, request WebsocketMsg) {
 				results[index].QueryId = queryId
 			})
 			totalResultCount += len(results)
-			resultDebouncer.Add(ctx, results)
+			resultFlusher.Add(ctx, results)
 		case <-doneChan:
 			logger.Info(ctx, fmt.Sprintf("query done, total results: %d, cost %d ms", totalResultCount, util.GetSystemTimestamp()-startTimestamp))
-
-			// if there is no result, show fallback search
-			if totalResultCount == 0 {
-				fallbackResults := plugin.GetPluginManager().QueryFallback(ctx, query, queryPlugin)
-				if len(fallbackResults) > 0 {
-					lo.ForEach(fallbackResults, func(_ plugin.QueryResultUI, index int) {
-						fallbackResults[index].QueryId = queryId
-					})
-					resultDebouncer.Add(ctx, fallbackResults)
-					logger.Info(ctx, fmt.Sprintf("no result, show %d fallback results", len(fallbackResults)))
-				} else {
-					logger.Info(ctx, "no result, no fallback results")
-				}
-			}
-
-			resultDebouncer.Done(ctx)
+			finish()
+			return
+		case <-softDeadlineChan:
+			logger.Warn(ctx, fmt.Sprintf("query soft deadline reached, total results: %d, cost %d ms, unfinished plugins: %s", totalResultCount, util.GetSystemTimestamp()-startTimestamp, strings.Join(plugin.GetPluginManager().GetPendingPlugins(query), ", ")))
+			finish()
 			return
-		case <-time.After(time.Minute):
-			logger.Info(ctx, fmt.Sprintf("query timeout, query: %s, request id: %s", query.String(), request.RequestId))
-			resultDebouncer.Done(ctx)
+		case <-timeoutTimer.C:
+			plugin.GetPluginManager().ReportQueryTimeout(ctx, queryId, query, util.GetSystemTimestamp()-startTimestamp)
+			resultFlusher.Done(ctx)
 			responseUIError(ctx, request, fmt.Sprintf("query timeout, query: %s, request id: %s", query.String(), request.RequestId))
 			return
 		}
@@ -407,6 +635,85 @@ func handleWebsocketQuery(ctx context.Context, request WebsocketMsg) {
 
 }
 
+func handleWebsocketQuerySuggestions(ctx context.Context, request WebsocketMsg) {
+	queryText, queryTextErr := getWebsocketMsgParameter(ctx, request, "queryText")
+	if queryTextErr != nil {
+		logger.Error(ctx, queryTextErr.Error())
+		responseUIError(ctx, request, queryTextErr.Error())
+		return
+	}
+
+	suggestions := []plugin.QuerySuggestion{}
+	if queryText != "" {
+		query, _, queryErr := plugin.GetPluginManager().NewQuery(ctx, share.PlainQuery{
+			QueryType: plugin.QueryTypeInput,
+			QueryText: queryText,
+		})
+		if queryErr != nil {
+			logger.Error(ctx, queryErr.Error())
+			responseUIError(ctx, request, queryErr.Error())
+			return
+		}
+		suggestions = append(suggestions, plugin.GetPluginManager().QuerySuggestions(ctx, query)...)
+	}
+
+	responseUISuccessWithData(ctx, request, suggestions)
+}
+
+func handleWebsocketFocusPreview(ctx context.Context, request WebsocketMsg) {
+	// resultId is empty if no result is focused or preview is hidden
+	resultId, idErr := getWebsocketMsgParameter(ctx, request, "resultId")
+	if idErr != nil {
+		logger.Error(ctx, idErr.Error())
+		responseUIError(ctx, request, idErr.Error())
+		return
+	}
+
+	plugin.GetPluginManager().FocusPreview(ctx, resultId)
+	responseUISuccess(ctx, request)
+}
+
+func handleWebsocketSetResultGroupCollapsed(ctx context.Context, request WebsocketMsg) {
+	pluginId, idErr := getWebsocketMsgParameter(ctx, request, "pluginId")
+	if idErr != nil {
+		logger.Error(ctx, idErr.Error())
+		responseUIError(ctx, request, idErr.Error())
+		return
+	}
+	collapsed, collapsedErr := getWebsocketMsgParameter(ctx, request, "collapsed")
+	if collapsedErr != nil {
+		logger.Error(ctx, collapsedErr.Error())
+		responseUIError(ctx, request, collapsedErr.Error())
+		return
+	}
+
+	plugin.GetPluginManager().SetResultGroupCollapsed(ctx, pluginId, collapsed == "true")
+	responseUISuccess(ctx, request)
+}
+
+func handleWebsocketCancelAction(ctx context.Context, request WebsocketMsg) {
+	resultId, idErr := getWebsocketMsgParameter(ctx, request, "resultId")
+	if idErr != nil {
+		logger.Error(ctx, idErr.Error())
+		responseUIError(ctx, request, idErr.Error())
+		return
+	}
+	actionId, actionIdErr := getWebsocketMsgParameter(ctx, request, "actionId")
+	if actionIdErr != nil {
+		logger.Error(ctx, actionIdErr.Error())
+		responseUIError(ctx, request, actionIdErr.Error())
+		return
+	}
+
+	cancelErr := plugin.GetPluginManager().CancelAction(ctx, resultId, actionId)
+	if cancelErr != nil {
+		responseUIError(ctx, request, cancelErr.Error())
+		return
+	}
+
+	responseUISuccess(ctx, request)
+}
+
 func handleWebsocketAction(ctx context.Context, request WebsocketMsg) {
 	resultId, idErr := getWebsocketMsgParameter(ctx, request, "resultId")
 	if idErr != nil {
@@ -421,7 +728,24 @@ func handleWebsocketAction(ctx context.Context, request WebsocketMsg) {
 		return
 	}
 
-	executeErr := plugin.GetPluginManager().ExecuteAction(ctx, resultId, actionId)
+	// modifiers is an optional json array of modifier keys held when user invoked the action, see plugin.ActionContext.Modifiers
+	var modifiers []string
+	if modifiersStr, modifiersErr := getWebsocketMsgParameter(ctx, request, "modifiers"); modifiersErr == nil && modifiersStr != "" {
+		if unmarshalErr := json.Unmarshal([]byte(modifiersStr), &modifiers); unmarshalErr != nil {
+			logger.Warn(ctx, fmt.Sprintf("invalid action modifiers: %s", modifiersStr))
+		}
+	}
+
+	// input is only sent for actions which require input, see plugin.QueryResultAction.Input
+	// background is sent when user presses Enter with the background action modifier, see plugin.Manager.ExecuteActionInBackground
+	var executeErr error
+	if input, inputErr := getWebsocketMsgParameter(ctx, request, "input"); inputErr == nil {
+		executeErr = plugin.GetPluginManager().ExecuteActionWithInput(ctx, resultId, actionId, input)
+	} else if background, _ := getWebsocketMsgParameter(ctx, request, "background"); background == "true" {
+		executeErr = plugin.GetPluginManager().ExecuteActionInBackground(ctx, resultId, actionId)
+	} else {
+		executeErr = plugin.GetPluginManager().ExecuteActionWithModifiers(ctx, resultId, actionId, modifiers)
+	}
 	if executeErr != nil {
 		responseUIError(ctx, request, executeErr.Error())
 		return
@@ -430,6 +754,89 @@ func handleWebsocketAction(ctx context.Context, request WebsocketMsg) {
 	responseUISuccess(ctx, request)
 }
 
+// handleWebsocketBatchAction runs an action on all selected results, resultIds is a json array and the first one is the focused result
+func handleWebsocketBatchAction(ctx context.Context, request WebsocketMsg) {
+	resultIdsStr, idsErr := getWebsocketMsgParameter(ctx, request, "resultIds")
+	if idsErr != nil {
+		logger.Error(ctx, idsErr.Error())
+		responseUIError(ctx, request, idsErr.Error())
+		return
+	}
+	var resultIds []string
+	if unmarshalErr := json.Unmarshal([]byte(resultIdsStr), &resultIds); unmarshalErr != nil {
+		logger.Error(ctx, unmarshalErr.Error())
+		responseUIError(ctx, request, unmarshalErr.Error())
+		return
+	}
+	actionId, actionIdErr := getWebsocketMsgParameter(ctx, request, "actionId")
+	if actionIdErr != nil {
+		logger.Error(ctx, actionIdErr.Error())
+		responseUIError(ctx, request, actionIdErr.Error())
+		return
+	}
+
+	executeErr := plugin.GetPluginManager().ExecuteBatchAction(ctx, resultIds, actionId)
+	if executeErr != nil {
+		responseUIError(ctx, request, executeErr.Error())
+		return
+	}
+
+	responseUISuccess(ctx, request)
+}
+
+func handleWebsocketValidateActionInput(ctx context.Context, request WebsocketMsg) {
+	resultId, idErr := getWebsocketMsgParameter(ctx, request, "resultId")
+	if idErr != nil {
+		logger.Error(ctx, idErr.Error())
+		responseUIError(ctx, request, idErr.Error())
+		return
+	}
+	actionId, actionIdErr := getWebsocketMsgParameter(ctx, request, "actionId")
+	if actionIdErr != nil {
+		logger.Error(ctx, actionIdErr.Error())
+		responseUIError(ctx, request, actionIdErr.Error())
+		return
+	}
+	input, inputErr := getWebsocketMsgParameter(ctx, request, "input")
+	if inputErr != nil {
+		logger.Error(ctx, inputErr.Error())
+		responseUIError(ctx, request, inputErr.Error())
+		return
+	}
+
+	// invalid input is not a request error, UI shows the message below the input field
+	validateErr := plugin.GetPluginManager().ValidateActionInput(ctx, resultId, actionId, input)
+	if validateErr != nil {
+		responseUISuccessWithData(ctx, request, map[string]any{"Valid": false, "Error": validateErr.Error()})
+		return
+	}
+
+	responseUISuccessWithData(ctx, request, map[string]any{"Valid": true, "Error": ""})
+}
+
+func handleWebsocketCancelActionInput(ctx context.Context, request WebsocketMsg) {
+	resultId, idErr := getWebsocketMsgParameter(ctx, request, "resultId")
+	if idErr != nil {
+		logger.Error(ctx, idErr.Error())
+		responseUIError(ctx, request, idErr.Error())
+		return
+	}
+	actionId, actionIdErr := getWebsocketMsgParameter(ctx, request, "actionId")
+	if actionIdErr != nil {
+		logger.Error(ctx, actionIdErr.Error())
+		responseUIError(ctx, request, actionIdErr.Error())
+		return
+	}
+
+	cancelErr := plugin.GetPluginManager().CancelActionInput(ctx, resultId, actionId)
+	if cancelErr != nil {
+		responseUIError(ctx, request, cancelErr.Error())
+		return
+	}
+
+	responseUISuccess(ctx, request)
+}
+
 func handleWebsocketRefresh(ctx context.Context, request WebsocketMsg) {
 	resultStr, resultErr := getWebsocketMsgParameter(ctx, request, "refreshableResult")
 	if resultErr != nil {
@@ -467,7 +874,13 @@ func handleWebsocketRefresh(ctx context.Context, request WebsocketMsg) {
 		result.Preview = preview
 	}
 
-	newResult, refreshErr := plugin.GetPluginManager().ExecuteRefresh(ctx, result)
+	// isVisible is optional for backward compatibility, treat result as visible if ui doesn't tell
+	isVisible := true
+	if isVisibleStr, visibleErr := getWebsocketMsgParameter(ctx, request, "isVisible"); visibleErr == nil {
+		isVisible = isVisibleStr != "false"
+	}
+
+	newResult, refreshErr := plugin.GetPluginManager().ExecuteRefresh(ctx, result, isVisible)
 	logger.Debug(ctx, fmt.Sprintf("finished refresh %s, cost: %dms", result.ResultId, util.GetSystemTimestamp()-startTime))
 	if refreshErr != nil {
 		logger.Error(ctx, refreshErr.Error())
@@ -478,6 +891,100 @@ func handleWebsocketRefresh(ctx context.Context, request WebsocketMsg) {
 	responseUISuccessWithData(ctx, request, newResult)
 }
 
+// handleWebsocketActionByIndex executes the default action of the Nth (1-based) result in current sorted result list, E.g. Cmd+1..9.
+// If there are fewer than N results, nothing is executed and an error is returned.
+func handleWebsocketActionByIndex(ctx context.Context, request WebsocketMsg) {
+	queryId, queryIdErr := getWebsocketMsgParameter(ctx, request, "queryId")
+	if queryIdErr != nil {
+		logger.Error(ctx, queryIdErr.Error())
+		responseUIError(ctx, request, queryIdErr.Error())
+		return
+	}
+	indexStr, indexErr := getWebsocketMsgParameter(ctx, request, "index")
+	if indexErr != nil {
+		logger.Error(ctx, indexErr.Error())
+		responseUIError(ctx, request, indexErr.Error())
+		return
+	}
+	index, parseErr := strconv.Atoi(indexStr)
+	if parseErr != nil {
+		responseUIError(ctx, request, fmt.Sprintf("invalid index: %s", indexStr))
+		return
+	}
+
+	result, action, executeErr := plugin.GetPluginManager().ExecuteResultAtIndex(ctx, queryId, index)
+	if executeErr != nil {
+		responseUIError(ctx, request, executeErr.Error())
+		return
+	}
+
+	// ui needs to know which action is executed to decide whether to hide
+	responseUISuccessWithData(ctx, request, map[string]any{
+		"ResultId":               result.Id,
+		"ActionId":               action.Id,
+		"PreventHideAfterAction": action.PreventHideAfterAction,
+	})
+}
+
+// handleWebsocketEscape is only requested by UI when escape mode is not setting.EscapeModeHide, UI hides itself otherwise
+func handleWebsocketEscape(ctx context.Context, request WebsocketMsg) {
+	queryType, queryTypeErr := getWebsocketMsgParameter(ctx, request, "queryType")
+	if queryTypeErr != nil {
+		logger.Error(ctx, queryTypeErr.Error())
+		responseUIError(ctx, request, queryTypeErr.Error())
+		return
+	}
+	queryText, queryTextErr := getWebsocketMsgParameter(ctx, request, "queryText")
+	if queryTextErr != nil {
+		logger.Error(ctx, queryTextErr.Error())
+		responseUIError(ctx, request, queryTextErr.Error())
+		return
+	}
+	querySelectionJson, querySelectionErr := getWebsocketMsgParameter(ctx, request, "querySelection")
+	if querySelectionErr != nil {
+		logger.Error(ctx, querySelectionErr.Error())
+		responseUIError(ctx, request, querySelectionErr.Error())
+		return
+	}
+	var querySelection selection.Selection
+	if unmarshalErr := json.Unmarshal([]byte(querySelectionJson), &querySelection); unmarshalErr != nil {
+		logger.Error(ctx, fmt.Sprintf("failed to unmarshal query selection: %s", unmarshalErr.Error()))
+		responseUIError(ctx, request, unmarshalErr.Error())
+		return
+	}
+
+	responseUISuccess(ctx, request)
+	GetUIManager().HandleEscape(ctx, share.PlainQuery{
+		QueryType:      queryType,
+		QueryText:      queryText,
+		QuerySelection: querySelection,
+	})
+}
+
+func handleWebsocketGetQueryStat(ctx context.Context, request WebsocketMsg) {
+	if !setting.GetSettingManager().GetWoxSetting(ctx).EnableQueryDebug {
+		responseUIError(ctx, request, "query debug is not enabled")
+		return
+	}
+
+	stat, exist := plugin.GetPluginManager().GetLastQueryStat()
+	if !exist {
+		responseUIError(ctx, request, "no query stat available")
+		return
+	}
+
+	responseUISuccessWithData(ctx, request, stat)
+}
+
+func handleWebsocketDumpState(ctx context.Context, request WebsocketMsg) {
+	if !setting.GetSettingManager().GetWoxSetting(ctx).EnableQueryDebug {
+		responseUIError(ctx, request, "query debug is not enabled")
+		return
+	}
+
+	responseUISuccessWithData(ctx, request, plugin.GetPluginManager().DumpState())
+}
+
 func getWebsocketMsgParameter(ctx context.Context, msg WebsocketMsg, key string) (string, error) {
 	jsonData, marshalErr := json.Marshal(msg.Data)
 	if marshalErr != nil {
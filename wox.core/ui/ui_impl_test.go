@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+	"wox/plugin"
+	"wox/setting"
+	"wox/share"
+	"wox/util"
+
+	"github.com/olahol/melody"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTransport records messages sent to UI and answers requests with the responder instead of a real UI
+type fakeTransport struct {
+	lock      sync.Mutex
+	ui        *uiImpl
+	sent      []WebsocketMsg
+	responder func(request WebsocketMsg) any
+	// methods which UI never answers, E.g. a dialog user didn't respond to
+	unanswered []string
+}
+
+func (f *fakeTransport) Send(ctx context.Context, msg WebsocketMsg) error {
+	f.lock.Lock()
+	f.sent = append(f.sent, msg)
+	f.lock.Unlock()
+
+	if slices.Contains(f.unanswered, msg.Method) {
+		return nil
+	}
+
+	var data any
+	if f.responder != nil {
+		data = f.responder(msg)
+	}
+	if resultChan, exist := f.ui.requestMap.Load(msg.RequestId); exist {
+		go func() {
+			resultChan <- WebsocketMsg{RequestId: msg.RequestId, Type: WebsocketMsgTypeResponse, Method: msg.Method, Success: true, Data: data}
+		}()
+	}
+	return nil
+}
+
+func (f *fakeTransport) methods() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var methods []string
+	for _, msg := range f.sent {
+		methods = append(methods, msg.Method)
+	}
+	return methods
+}
+
+func newFakeUI() (*uiImpl, *fakeTransport) {
+	logger = util.GetLogger()
+	transport := &fakeTransport{}
+	u := &uiImpl{
+		requestMap: util.NewHashMap[string, chan WebsocketMsg](),
+		transport:  transport,
+	}
+	transport.ui = u
+	return u, transport
+}
+
+func TestUIImpl_ChangeQuery(t *testing.T) {
+	u, transport := newFakeUI()
+	query := share.PlainQuery{QueryType: plugin.QueryTypeInput, QueryText: "wpm install"}
+	u.ChangeQuery(util.NewTraceContext(), query)
+
+	assert.Equal(t, []string{"ChangeQuery"}, transport.methods())
+	assert.Equal(t, WebsocketMsgTypeRequest, transport.sent[0].Type)
+	assert.Equal(t, query, transport.sent[0].Data)
+	assert.Equal(t, 0, u.requestMap.Len())
+}
+
+func TestUIImpl_NotifyInToolbar(t *testing.T) {
+	u, transport := newFakeUI()
+	transport.responder = func(request WebsocketMsg) any {
+		switch request.Method {
+		case "IsVisible":
+			return true
+		case "GetCurrentQuery":
+			return share.PlainQuery{QueryType: plugin.QueryTypeSelection}
+		}
+		return nil
+	}
+
+	msg := share.NotifyMsg{Text: "hello", DisplaySeconds: 3}
+	u.Notify(util.NewTraceContext(), msg)
+
+	assert.Equal(t, []string{"IsVisible", "GetCurrentQuery", "ShowToolbarMsg"}, transport.methods())
+	assert.Equal(t, msg, transport.sent[2].Data)
+}
+
+func TestUIImpl_ExecuteBatch(t *testing.T) {
+	u, transport := newFakeUI()
+	err := u.ExecuteBatch(util.NewTraceContext(), []share.UICommand{
+		{Type: share.UICommandTypeShowToolbarMsg, Msg: share.NotifyMsg{Text: "done"}},
+		{Type: share.UICommandTypeChangeQuery, Query: share.PlainQuery{QueryType: plugin.QueryTypeInput, QueryText: "wpm"}},
+		{Type: share.UICommandTypeHideApp},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Batch"}, transport.methods())
+	batch := transport.sent[0].Data.([]map[string]any)
+	assert.Equal(t, "ShowToolbarMsg", batch[0]["Method"])
+	assert.Equal(t, "ChangeQuery", batch[1]["Method"])
+	assert.Equal(t, "HideApp", batch[2]["Method"])
+
+	tooMany := make([]share.UICommand, share.MaxUICommandBatchSize+1)
+	for i := range tooMany {
+		tooMany[i].Type = share.UICommandTypeHideApp
+	}
+	assert.Error(t, u.ExecuteBatch(util.NewTraceContext(), tooMany))
+	assert.Len(t, transport.methods(), 1)
+}
+
+func TestUIImpl_Status(t *testing.T) {
+	u, transport := newFakeUI()
+	ctx := util.NewTraceContext()
+	syncing := share.StatusMsg{PluginId: "a", Text: "Syncing…"}
+	indexing := share.StatusMsg{PluginId: "b", Text: "Indexing…"}
+
+	u.SetStatus(ctx, syncing)
+	u.SetStatus(ctx, indexing)
+	assert.Equal(t, indexing, transport.sent[1].Data)
+
+	// clearing the latest status shows the previous one again
+	u.ClearStatus(ctx, "b")
+	assert.Equal(t, "SetStatus", transport.sent[2].Method)
+	assert.Equal(t, syncing, transport.sent[2].Data)
+
+	u.ClearStatus(ctx, "a")
+	assert.Equal(t, "ClearStatus", transport.sent[3].Method)
+}
+
+func TestUIImpl_ShowDialog(t *testing.T) {
+	u, transport := newFakeUI()
+	transport.responder = func(request WebsocketMsg) any {
+		return map[string]any{"token": "abc", "remember": true, share.DialogButtonResponseKey: "submit"}
+	}
+	spec := share.DialogSpec{
+		Title: "Connect",
+		Fields: []share.DialogField{
+			{Key: "token", Type: share.DialogFieldTypePassword, Label: "Token", Required: true},
+			{Key: "remember", Type: share.DialogFieldTypeCheckbox, Label: "Remember"},
+		},
+	}
+
+	responses, err := u.ShowDialog(util.NewTraceContext(), spec)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": "abc", "remember": "true", share.DialogButtonResponseKey: "submit"}, responses)
+	sentSpec := transport.sent[0].Data.(share.DialogSpec)
+	assert.NotEmpty(t, sentSpec.Id)
+	assert.Len(t, sentSpec.Buttons, 2)
+
+	// UI answers nil if user cancelled
+	transport.responder = nil
+	_, err = u.ShowDialog(util.NewTraceContext(), spec)
+	assert.ErrorIs(t, err, share.ErrDialogCancelled)
+
+	invalid := spec
+	invalid.Fields = append(invalid.Fields, share.DialogField{Key: "token", Type: share.DialogFieldTypeText})
+	_, err = u.ShowDialog(util.NewTraceContext(), invalid)
+	assert.Error(t, err)
+	invalid.Fields = []share.DialogField{{Key: "region", Type: share.DialogFieldTypeSelect}}
+	_, err = u.ShowDialog(util.NewTraceContext(), invalid)
+	assert.Error(t, err)
+}
+
+func TestUIImpl_ShowDialogCancelled(t *testing.T) {
+	u, transport := newFakeUI()
+	transport.unanswered = []string{"ShowDialog"}
+
+	ctx, cancel := context.WithTimeout(util.NewTraceContext(), 50*time.Millisecond)
+	defer cancel()
+	_, err := u.ShowDialog(ctx, share.DialogSpec{Title: "Connect"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// dialog is closed in UI when nobody waits for it anymore
+	assert.Equal(t, []string{"ShowDialog", "CloseDialog"}, transport.methods())
+	assert.Equal(t, map[string]string{"Id": transport.sent[0].Data.(share.DialogSpec).Id}, transport.sent[1].Data)
+
+	transport.sent = nil
+	_, err = u.ShowDialog(util.NewTraceContext(), share.DialogSpec{Title: "Connect", TimeoutSeconds: 1})
+	assert.ErrorIs(t, err, share.ErrDialogTimeout)
+	assert.Equal(t, []string{"ShowDialog", "CloseDialog"}, transport.methods())
+}
+
+func TestHandleWebsocketAction_Modifiers(t *testing.T) {
+	ctx := util.NewTraceContext()
+	logger = util.GetLogger()
+	assert.Nil(t, util.GetLocation().Init())
+	assert.Nil(t, setting.GetSettingManager().Init(ctx))
+	if m == nil {
+		m = melody.New()
+	}
+
+	pluginManager := plugin.GetPluginManager()
+	instance := &plugin.Instance{
+		Metadata:       plugin.Metadata{Id: "websocket-modifiers-test", Name: "websocket modifiers test"},
+		Setting:        &setting.PluginSetting{Settings: util.NewHashMap[string, string]()},
+		IsSystemPlugin: true,
+	}
+	var modifiers []string
+	result := pluginManager.PolishResult(ctx, instance, plugin.Query{Type: plugin.QueryTypeInput, RawQuery: "open"}, plugin.QueryResult{
+		Title: "Open url",
+		Actions: []plugin.QueryResultAction{
+			{
+				Name:        "Open",
+				AllowRepeat: true,
+				Action: func(ctx context.Context, actionContext plugin.ActionContext) {
+					modifiers = actionContext.Modifiers
+				},
+			},
+		},
+	})
+
+	// UI sends modifiers held with Enter as a json array
+	handleWebsocketAction(ctx, WebsocketMsg{
+		RequestId: "modifiers",
+		Method:    "Action",
+		Data:      map[string]any{"resultId": result.Id, "actionId": result.Actions[0].Id, "modifiers": []string{"ctrl", "shift"}},
+	})
+	assert.Equal(t, []string{"ctrl", "shift"}, modifiers)
+
+	handleWebsocketAction(ctx, WebsocketMsg{
+		RequestId: "no-modifiers",
+		Method:    "Action",
+		Data:      map[string]any{"resultId": result.Id, "actionId": result.Actions[0].Id},
+	})
+	assert.Empty(t, modifiers)
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"sync"
+	"wox/share"
+
+	"github.com/samber/lo"
+)
+
+// uiStatusStack keeps status of each plugin, the latest set one is on top and shown by UI, see share.StatusMsg.
+// Zero value is ready to use
+type uiStatusStack struct {
+	statuses []share.StatusMsg
+	lock     sync.Mutex
+}
+
+func (s *uiStatusStack) push(msg share.StatusMsg) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.statuses = lo.Filter(s.statuses, func(item share.StatusMsg, _ int) bool {
+		return item.PluginId != msg.PluginId
+	})
+	s.statuses = append(s.statuses, msg)
+}
+
+// remove removes the status of given plugin and returns the status to show now, false if there is no status left
+func (s *uiStatusStack) remove(pluginId string) (share.StatusMsg, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.statuses = lo.Filter(s.statuses, func(item share.StatusMsg, _ int) bool {
+		return item.PluginId != pluginId
+	})
+	if len(s.statuses) == 0 {
+		return share.StatusMsg{}, false
+	}
+	return s.statuses[len(s.statuses)-1], true
+}
@@ -6,6 +6,8 @@ import "github.com/google/uuid"
 const (
 	ContextKeyTraceId       = "trace"
 	ContextKeyComponentName = "component"
+	ContextKeyLiteralMatch  = "literalMatch"
+	ContextKeyMatchOptions  = "matchOptions"
 )
 
 func NewTraceContext() context.Context {
@@ -19,3 +21,24 @@ func NewComponentContext(ctx context.Context, componentName string) context.Cont
 func NewTraceContextWith(traceId string) context.Context {
 	return context.WithValue(context.Background(), ContextKeyTraceId, traceId)
 }
+
+// NewLiteralMatchContext marks ctx so that string matchers which take ctx match literally instead of fuzzy
+func NewLiteralMatchContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ContextKeyLiteralMatch, true)
+}
+
+func IsLiteralMatchContext(ctx context.Context) bool {
+	literalMatch, ok := ctx.Value(ContextKeyLiteralMatch).(bool)
+	return ok && literalMatch
+}
+
+// NewMatchOptionsContext sets options used by string matchers which take ctx, see IsStringMatchScoreWithOptions
+func NewMatchOptionsContext(ctx context.Context, options MatchOptions) context.Context {
+	return context.WithValue(ctx, ContextKeyMatchOptions, options)
+}
+
+// GetMatchOptionsContext returns options set by NewMatchOptionsContext, zero value (normal sensitivity) if not set
+func GetMatchOptionsContext(ctx context.Context) MatchOptions {
+	options, _ := ctx.Value(ContextKeyMatchOptions).(MatchOptions)
+	return options
+}
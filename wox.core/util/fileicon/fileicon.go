@@ -0,0 +1,15 @@
+// Package fileicon resolves native icons of files from OS, E.g. app bundle icons and file type icons
+package fileicon
+
+import "image"
+
+// GetFileIcon returns the icon OS shows for the file at path (E.g. icon of an app bundle or executable, or the icon of its file type).
+// File must exist
+func GetFileIcon(path string) (image.Image, error) {
+	return getFileIcon(path)
+}
+
+// GetFileTypeIcon returns the icon OS shows for files with the given extension (E.g. ".pdf"), file doesn't need to exist
+func GetFileTypeIcon(extension string) (image.Image, error) {
+	return getFileTypeIcon(extension)
+}
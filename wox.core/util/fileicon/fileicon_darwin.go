@@ -0,0 +1,71 @@
+package fileicon
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework Cocoa
+// #import <Cocoa/Cocoa.h>
+// #include <stdlib.h>
+//
+// static int iconToPng(NSImage *icon, unsigned char **data) {
+//     if (icon == nil) return 0;
+//     [icon setSize:NSMakeSize(64, 64)];
+//     CGImageRef cgImage = [icon CGImageForProposedRect:NULL context:nil hints:nil];
+//     if (cgImage == NULL) return 0;
+//     NSBitmapImageRep *rep = [[NSBitmapImageRep alloc] initWithCGImage:cgImage];
+//     NSData *png = [rep representationUsingType:NSBitmapImageFileTypePNG properties:@{}];
+//     int length = (int)[png length];
+//     *data = malloc(length);
+//     memcpy(*data, [png bytes], length);
+//     [rep release];
+//     return length;
+// }
+//
+// int getFileIcon(const char *path, unsigned char **data) {
+//     @autoreleasepool {
+//         NSImage *icon = [[NSWorkspace sharedWorkspace] iconForFile:[NSString stringWithUTF8String:path]];
+//         return iconToPng(icon, data);
+//     }
+// }
+//
+// int getFileTypeIcon(const char *extension, unsigned char **data) {
+//     @autoreleasepool {
+//         NSImage *icon = [[NSWorkspace sharedWorkspace] iconForFileType:[NSString stringWithUTF8String:extension]];
+//         return iconToPng(icon, data);
+//     }
+// }
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"unsafe"
+)
+
+func getFileIcon(path string) (image.Image, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var data *C.uchar
+	length := C.getFileIcon(cPath, &data)
+	return decodeIcon(data, length)
+}
+
+func getFileTypeIcon(extension string) (image.Image, error) {
+	cExtension := C.CString(strings.TrimPrefix(extension, "."))
+	defer C.free(unsafe.Pointer(cExtension))
+
+	var data *C.uchar
+	length := C.getFileTypeIcon(cExtension, &data)
+	return decodeIcon(data, length)
+}
+
+func decodeIcon(data *C.uchar, length C.int) (image.Image, error) {
+	if length == 0 {
+		return nil, errors.New("failed to get file icon")
+	}
+	defer C.free(unsafe.Pointer(data))
+
+	return png.Decode(bytes.NewReader(C.GoBytes(unsafe.Pointer(data), length)))
+}
@@ -0,0 +1,217 @@
+package fileicon
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"image/png"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// size svg icons are rasterized to, same as icons of other platforms
+const iconSize = 64
+
+// linux has no API to get file icons without a GUI toolkit, so icons are looked up in freedesktop icon themes by the name
+// of the mime type (E.g. "application-pdf"), see https://specifications.freedesktop.org/icon-theme-spec/latest/
+func getFileIcon(path string) (image.Image, error) {
+	info, statErr := os.Stat(path)
+	if statErr != nil {
+		return nil, statErr
+	}
+
+	if info.IsDir() {
+		return loadThemeIcon([]string{"folder", "inode-directory"})
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".desktop" {
+		if iconName := getDesktopEntryIcon(path); iconName != "" {
+			if filepath.IsAbs(iconName) {
+				return loadIconFile(iconName)
+			}
+			return loadThemeIcon([]string{iconName, "application-x-executable"})
+		}
+	}
+	if filepath.Ext(path) == "" && info.Mode()&0111 != 0 {
+		return loadThemeIcon([]string{"application-x-executable"})
+	}
+
+	return getFileTypeIcon(filepath.Ext(path))
+}
+
+func getFileTypeIcon(extension string) (image.Image, error) {
+	var names []string
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	if mimeType, _, parseErr := mime.ParseMediaType(mime.TypeByExtension(extension)); parseErr == nil {
+		// E.g. "application/pdf" => "application-pdf", fallback to generic icon of the media type like "text-x-generic"
+		names = append(names, strings.ReplaceAll(mimeType, "/", "-"))
+		if media, _, found := strings.Cut(mimeType, "/"); found {
+			names = append(names, media+"-x-generic")
+		}
+	}
+	names = append(names, "application-x-generic", "text-x-generic")
+
+	return loadThemeIcon(names)
+}
+
+// getDesktopEntryIcon returns the Icon key of a desktop entry, which is either an icon name or an absolute path
+func getDesktopEntryIcon(path string) string {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	inDesktopEntry := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inDesktopEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if inDesktopEntry {
+			if value, found := strings.CutPrefix(line, "Icon="); found {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+// loadThemeIcon returns the first icon found by names, in order of preference
+func loadThemeIcon(names []string) (image.Image, error) {
+	themeDirs := getIconThemeDirs()
+	for _, name := range names {
+		if iconPath := findThemeIcon(themeDirs, name); iconPath != "" {
+			if img, err := loadIconFile(iconPath); err == nil {
+				return img, nil
+			}
+		}
+	}
+
+	return nil, errors.New("failed to find file icon in icon themes")
+}
+
+// getIconThemeDirs returns directories of the current icon theme and its fallbacks, followed by pixmaps directory which has no theme
+func getIconThemeDirs() []string {
+	var baseDirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		baseDirs = append(baseDirs, filepath.Join(home, ".icons"))
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dataDir := range append([]string{dataHome}, strings.Split(dataDirs, ":")...) {
+		if dataDir != "" {
+			baseDirs = append(baseDirs, filepath.Join(dataDir, "icons"))
+		}
+	}
+
+	themes := []string{"Adwaita", "hicolor"}
+	if theme := getCurrentIconTheme(); theme != "" && theme != "Adwaita" && theme != "hicolor" {
+		themes = append([]string{theme}, themes...)
+	}
+
+	var themeDirs []string
+	for _, theme := range themes {
+		for _, baseDir := range baseDirs {
+			themeDir := filepath.Join(baseDir, theme)
+			if _, err := os.Stat(themeDir); err == nil {
+				themeDirs = append(themeDirs, themeDir)
+			}
+		}
+	}
+	return append(themeDirs, "/usr/share/pixmaps")
+}
+
+func getCurrentIconTheme() string {
+	output, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "icon-theme").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(strings.TrimSpace(string(output)), "'")
+}
+
+// findThemeIcon returns the path of the icon closest to iconSize, svg icons are preferred as they scale.
+// Theme directories are laid out as "<size>/<context>" or "<context>/<size>", pixmaps has icons at top level
+func findThemeIcon(themeDirs []string, name string) string {
+	for _, themeDir := range themeDirs {
+		var candidates []string
+		for _, pattern := range []string{"*/*/%s.svg", "%s.svg", "*/*/%s.png", "%s.png"} {
+			matches, _ := filepath.Glob(filepath.Join(themeDir, strings.ReplaceAll(pattern, "%s", name)))
+			candidates = append(candidates, matches...)
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		best, bestDistance := "", -1
+		for _, candidate := range candidates {
+			distance := getIconSizeDistance(candidate)
+			if bestDistance == -1 || distance < bestDistance {
+				best, bestDistance = candidate, distance
+			}
+		}
+		return best
+	}
+	return ""
+}
+
+func getIconSizeDistance(iconPath string) int {
+	if strings.HasSuffix(iconPath, ".svg") {
+		return 0
+	}
+
+	file, openErr := os.Open(iconPath)
+	if openErr != nil {
+		return 1 << 30
+	}
+	defer file.Close()
+
+	config, decodeErr := png.DecodeConfig(file)
+	if decodeErr != nil {
+		return 1 << 30
+	}
+	if config.Width < iconSize {
+		// upscaled icons are blurry, prefer larger ones
+		return (iconSize - config.Width) * 2
+	}
+	return config.Width - iconSize
+}
+
+func loadIconFile(iconPath string) (image.Image, error) {
+	file, openErr := os.Open(iconPath)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer file.Close()
+
+	if !strings.HasSuffix(strings.ToLower(iconPath), ".svg") {
+		img, _, decodeErr := image.Decode(file)
+		return img, decodeErr
+	}
+
+	icon, readErr := oksvg.ReadIconStream(file, oksvg.WarnErrorMode)
+	if readErr != nil {
+		return nil, readErr
+	}
+	icon.SetTarget(0, 0, iconSize, iconSize)
+	rgba := image.NewRGBA(image.Rect(0, 0, iconSize, iconSize))
+	icon.Draw(rasterx.NewDasher(iconSize, iconSize, rasterx.NewScannerGV(iconSize, iconSize, rgba, rgba.Bounds())), 1)
+	return rgba, nil
+}
@@ -0,0 +1,104 @@
+package fileicon
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	win "github.com/lxn/win"
+)
+
+var (
+	shell32       = syscall.NewLazyDLL("shell32.dll")
+	shGetFileInfo = shell32.NewProc("SHGetFileInfoW")
+)
+
+const (
+	shgfiIcon              = 0x000000100
+	shgfiLargeIcon         = 0x000000000
+	shgfiUseFileAttributes = 0x000000010
+	fileAttributeNormal    = 0x80
+)
+
+// SHFILEINFOW
+type shFileInfo struct {
+	hIcon         win.HICON
+	iIcon         int32
+	dwAttributes  uint32
+	szDisplayName [260]uint16
+	szTypeName    [80]uint16
+}
+
+func getFileIcon(path string) (image.Image, error) {
+	return getShellIcon(path, 0)
+}
+
+func getFileTypeIcon(extension string) (image.Image, error) {
+	// with SHGFI_USEFILEATTRIBUTES, shell only looks at the extension of a fake file name
+	return getShellIcon("file."+strings.TrimPrefix(extension, "."), shgfiUseFileAttributes)
+}
+
+func getShellIcon(path string, flags uintptr) (image.Image, error) {
+	lpPath, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var info shFileInfo
+	ret, _, _ := shGetFileInfo.Call(
+		uintptr(unsafe.Pointer(lpPath)),
+		fileAttributeNormal,
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info),
+		shgfiIcon|shgfiLargeIcon|flags,
+	)
+	if ret == 0 || info.hIcon == 0 {
+		return nil, fmt.Errorf("no icon found for %s", path)
+	}
+	defer win.DestroyIcon(info.hIcon)
+
+	return iconToImage(info.hIcon)
+}
+
+func iconToImage(icon win.HICON) (image.Image, error) {
+	var iconInfo win.ICONINFO
+	if !win.GetIconInfo(icon, &iconInfo) {
+		return nil, fmt.Errorf("failed to get icon info")
+	}
+	defer win.DeleteObject(win.HGDIOBJ(iconInfo.HbmColor))
+	defer win.DeleteObject(win.HGDIOBJ(iconInfo.HbmMask))
+
+	hdc := win.GetDC(0)
+	defer win.ReleaseDC(0, hdc)
+
+	// hotspot of an icon is its center
+	width := int(iconInfo.XHotspot * 2)
+	height := int(iconInfo.YHotspot * 2)
+
+	var bmpInfo win.BITMAPINFO
+	bmpInfo.BmiHeader.BiSize = uint32(unsafe.Sizeof(bmpInfo.BmiHeader))
+	bmpInfo.BmiHeader.BiWidth = int32(width)
+	bmpInfo.BmiHeader.BiHeight = -int32(height) // top-down DIB
+	bmpInfo.BmiHeader.BiPlanes = 1
+	bmpInfo.BmiHeader.BiBitCount = 32
+	bmpInfo.BmiHeader.BiCompression = win.BI_RGB
+
+	bits := make([]byte, width*height*4)
+	if win.GetDIBits(hdc, win.HBITMAP(iconInfo.HbmColor), 0, uint32(height), &bits[0], &bmpInfo, win.DIB_RGB_COLORS) == 0 {
+		return nil, fmt.Errorf("failed to get DIB bits")
+	}
+
+	// bitmap data is in BGRA format
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			base := y*width*4 + x*4
+			img.SetRGBA(x, y, color.RGBA{R: bits[base+2], G: bits[base+1], B: bits[base], A: bits[base+3]})
+		}
+	}
+
+	return img, nil
+}
@@ -0,0 +1,194 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// HTTPClient is a http client with an on-disk response cache keyed by url, for plugins which fetch the same data repeatedly.
+//
+// Only successful GET responses are cached. Wox is a private cache, so responses with "Cache-Control: private" are cached as well. Cache-Control of responses is honored: no-store responses are not cached,
+// max-age (or Expires header) decides how long a response is fresh, fresh responses are returned without network request.
+// Stale responses are revalidated with If-None-Match/If-Modified-Since, a 304 response refreshes the cached one.
+// Cache is keyed by url only, don't use it for responses which depend on request headers (E.g. different users).
+type HTTPClient struct {
+	cacheDirectory string
+}
+
+type httpCacheEntry struct {
+	Url          string
+	ETag         string
+	LastModified string
+	ExpireAt     int64 // timestamp in ms, response must be revalidated after it
+	Body         []byte
+}
+
+// NewHTTPClient creates a client which stores its cache in its own directory, namespace is usually the plugin id
+func NewHTTPClient(namespace string) *HTTPClient {
+	return &HTTPClient{
+		cacheDirectory: path.Join(GetLocation().GetHTTPCacheDirectory(), namespace),
+	}
+}
+
+func (c *HTTPClient) Get(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	entry, cached := c.loadEntry(url)
+	if cached && GetSystemTimestamp() < entry.ExpireAt {
+		return entry.Body, nil
+	}
+
+	req, err := newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := GetHTTPClient(ctx).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached {
+		if expireAt, cacheable := getHTTPCacheExpireAt(resp.Header); cacheable {
+			entry.ExpireAt = expireAt
+			c.saveEntry(ctx, entry)
+		}
+		return entry.Body, nil
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("http %s %s failed, status code: %d", req.Method, req.URL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if expireAt, cacheable := getHTTPCacheExpireAt(resp.Header); cacheable && resp.StatusCode == http.StatusOK {
+		c.saveEntry(ctx, httpCacheEntry{
+			Url:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			ExpireAt:     expireAt,
+			Body:         body,
+		})
+	} else {
+		c.Invalidate(url)
+	}
+
+	return body, nil
+}
+
+// Invalidate removes cached response of the url, next Get will fetch it from network
+func (c *HTTPClient) Invalidate(url string) error {
+	removeErr := os.Remove(c.entryPath(url))
+	if removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+	return nil
+}
+
+// InvalidateAll removes all cached responses of this client
+func (c *HTTPClient) InvalidateAll() error {
+	return os.RemoveAll(c.cacheDirectory)
+}
+
+func (c *HTTPClient) entryPath(url string) string {
+	return path.Join(c.cacheDirectory, Md5([]byte(url))+".json")
+}
+
+func (c *HTTPClient) loadEntry(url string) (httpCacheEntry, bool) {
+	data, readErr := os.ReadFile(c.entryPath(url))
+	if readErr != nil {
+		return httpCacheEntry{}, false
+	}
+
+	var entry httpCacheEntry
+	if unmarshalErr := json.Unmarshal(data, &entry); unmarshalErr != nil || entry.Url != url {
+		return httpCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *HTTPClient) saveEntry(ctx context.Context, entry httpCacheEntry) {
+	data, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+	if directoryErr := GetLocation().EnsureDirectoryExist(c.cacheDirectory); directoryErr != nil {
+		GetLogger().Warn(ctx, fmt.Sprintf("failed to create http cache directory: %s", directoryErr.Error()))
+		return
+	}
+
+	// write to temp file first, so concurrent readers never see a partial entry
+	entryPath := c.entryPath(entry.Url)
+	tmpPath := entryPath + ".tmp"
+	if writeErr := os.WriteFile(tmpPath, data, 0644); writeErr != nil {
+		GetLogger().Warn(ctx, fmt.Sprintf("failed to write http cache: %s", writeErr.Error()))
+		return
+	}
+	if renameErr := os.Rename(tmpPath, entryPath); renameErr != nil {
+		GetLogger().Warn(ctx, fmt.Sprintf("failed to write http cache: %s", renameErr.Error()))
+	}
+}
+
+// getHTTPCacheExpireAt returns until when a response is fresh according to its headers,
+// cacheable is false if response must not be stored.
+// Responses without freshness info are stored but revalidated on every request, which is only useful if they have validators.
+func getHTTPCacheExpireAt(header http.Header) (expireAt int64, cacheable bool) {
+	now := GetSystemTimestamp()
+
+	// directives can come in any order, no-store and no-cache win over max-age (E.g. "max-age=60, no-store")
+	noStore, noCache, maxAgeSeconds := false, false, -1
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		switch directive {
+		case "no-store":
+			noStore = true
+		case "no-cache":
+			noCache = true
+		}
+		if maxAge, found := strings.CutPrefix(directive, "max-age="); found {
+			if seconds, err := strconv.Atoi(maxAge); err == nil {
+				maxAgeSeconds = seconds
+			}
+		}
+	}
+	if noStore {
+		return 0, false
+	}
+	if noCache {
+		return now, header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+	}
+	if maxAgeSeconds >= 0 {
+		return now + int64(maxAgeSeconds)*1000, true
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		if expiresTime, err := http.ParseTime(expires); err == nil {
+			return expiresTime.UnixMilli(), true
+		}
+		// invalid Expires (E.g. "0") means already expired
+		return now, header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+	}
+
+	return now, header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHTTPCacheExpireAt(t *testing.T) {
+	newHeader := func(kv ...string) http.Header {
+		header := http.Header{}
+		for i := 0; i < len(kv); i += 2 {
+			header.Set(kv[i], kv[i+1])
+		}
+		return header
+	}
+
+	now := GetSystemTimestamp()
+
+	expireAt, cacheable := getHTTPCacheExpireAt(newHeader("Cache-Control", "max-age=60"))
+	assert.True(t, cacheable)
+	assert.InDelta(t, now+60*1000, expireAt, 1000)
+
+	// no-store wins no matter where it is
+	_, cacheable = getHTTPCacheExpireAt(newHeader("Cache-Control", "max-age=60, no-store"))
+	assert.False(t, cacheable)
+	_, cacheable = getHTTPCacheExpireAt(newHeader("Cache-Control", "no-store, max-age=60"))
+	assert.False(t, cacheable)
+
+	// no-cache must be revalidated, only useful with validators
+	expireAt, cacheable = getHTTPCacheExpireAt(newHeader("Cache-Control", "max-age=60, no-cache", "ETag", `"v1"`))
+	assert.True(t, cacheable)
+	assert.LessOrEqual(t, expireAt, GetSystemTimestamp())
+	_, cacheable = getHTTPCacheExpireAt(newHeader("Cache-Control", "no-cache"))
+	assert.False(t, cacheable)
+
+	// max-age overrides Expires
+	expireAt, cacheable = getHTTPCacheExpireAt(newHeader("Cache-Control", "private, max-age=0", "Expires", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)))
+	assert.True(t, cacheable)
+	assert.InDelta(t, now, expireAt, 1000)
+
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	expireAt, cacheable = getHTTPCacheExpireAt(newHeader("Expires", expires.Format(http.TimeFormat)))
+	assert.True(t, cacheable)
+	assert.Equal(t, expires.UnixMilli(), expireAt)
+
+	// invalid Expires means already expired
+	_, cacheable = getHTTPCacheExpireAt(newHeader("Expires", "0"))
+	assert.False(t, cacheable)
+	_, cacheable = getHTTPCacheExpireAt(newHeader("Expires", "0", "Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT"))
+	assert.True(t, cacheable)
+
+	_, cacheable = getHTTPCacheExpireAt(newHeader())
+	assert.False(t, cacheable)
+}
+
+func TestHTTPClient_Get(t *testing.T) {
+	var requests, notModified atomic.Int32
+	cacheControl := "max-age=60"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := &HTTPClient{cacheDirectory: t.TempDir()}
+
+	// fresh response is served from cache
+	for i := 0; i < 2; i++ {
+		body, err := client.Get(ctx, server.URL+"/fresh", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+	}
+	assert.Equal(t, int32(1), requests.Load())
+
+	// stale response is revalidated, 304 returns the cached body
+	cacheControl = "no-cache"
+	requests.Store(0)
+	for i := 0; i < 2; i++ {
+		body, err := client.Get(ctx, server.URL+"/stale", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+	}
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), notModified.Load())
+
+	// no-store response is never cached, even with max-age
+	cacheControl = "max-age=60, no-store"
+	requests.Store(0)
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(ctx, server.URL+"/no-store", nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+	_, cached := client.loadEntry(server.URL + "/no-store")
+	assert.False(t, cached)
+
+	assert.NoError(t, client.Invalidate(server.URL+"/fresh"))
+	_, cached = client.loadEntry(server.URL + "/fresh")
+	assert.False(t, cached)
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	rateLimitMaxRetries    = 2
+	rateLimitMaxRetryAfter = time.Minute // don't let a server block us longer than this
+)
+
+// RateLimitedHTTPClient sends requests through a RateLimiter. If server responds 429 or 503 with Retry-After header,
+// the limiter is blocked until then (so other requests sharing the limiter wait as well) and the request is retried.
+type RateLimitedHTTPClient struct {
+	limiter *RateLimiter
+	guard   func(ctx context.Context) error // checked before every request, see WithGuard
+}
+
+// NewRateLimitedHTTPClient creates a client using the given limiter, nil means a new limiter with default rate
+func NewRateLimitedHTTPClient(limiter *RateLimiter) *RateLimitedHTTPClient {
+	if limiter == nil {
+		limiter = NewRateLimiter(DefaultRateLimitPerSecond, DefaultRateLimitBurst)
+	}
+
+	return &RateLimitedHTTPClient{limiter: limiter}
+}
+
+func (c *RateLimitedHTTPClient) Limiter() *RateLimiter {
+	return c.limiter
+}
+
+// WithGuard makes every request fail with the error returned by guard, E.g. when plugin is not allowed to access network
+func (c *RateLimitedHTTPClient) WithGuard(guard func(ctx context.Context) error) *RateLimitedHTTPClient {
+	c.guard = guard
+	return c
+}
+
+// Do sends the request, the response is returned as it is (including error status codes) after retries are exhausted.
+// Requests with body are only retried if the body can be replayed (req.GetBody is set).
+func (c *RateLimitedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if c.guard != nil {
+		if err := c.guard(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := GetHTTPClient(req.Context()).Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+
+		retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			return resp, nil
+		}
+		c.limiter.BlockUntil(time.Now().Add(retryAfter))
+
+		if attempt >= rateLimitMaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, nil
+			}
+			req.Body = body
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		GetLogger().Info(req.Context(), fmt.Sprintf("http %s %s is throttled by server, retry after %s", req.Method, req.URL, retryAfter))
+	}
+}
+
+// Get is like HttpGetWithHeaders but goes through the limiter
+func (c *RateLimitedHTTPClient) Get(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	req, err := newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("http %s %s failed, status code: %d", req.Method, req.URL, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// parseRetryAfter parses Retry-After header, which is either seconds or a http date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, dateErr := http.ParseTime(value); dateErr == nil {
+		retryAfter = time.Until(date)
+	} else {
+		return 0, false
+	}
+
+	return max(0, min(retryAfter, rateLimitMaxRetryAfter)), true
+}
@@ -0,0 +1,28 @@
+package util
+
+import "context"
+
+// iterateCheckInterval is how many items are processed between two cancellation checks,
+// checking ctx on every item is measurable in tight loops while a few dozen items is still far below what user can notice
+const iterateCheckInterval = 32
+
+// Iterate calls fn for each item in order and stops early once ctx is cancelled (E.g. user typed another query),
+// so CPU-bound plugins don't keep building results nobody will see. fn can return false to stop iterating.
+// Returns ctx.Err() if iteration is stopped by ctx, nil otherwise. Overhead is a couple of nanoseconds per item, see BenchmarkIterate.
+func Iterate[T any](ctx context.Context, items []T, fn func(index int, item T) bool) error {
+	done := ctx.Done()
+	for i, item := range items {
+		if i%iterateCheckInterval == 0 && done != nil {
+			select {
+			case <-done:
+				return ctx.Err()
+			default:
+			}
+		}
+		if !fn(i, item) {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterate(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	sum := 0
+	err := Iterate(context.Background(), items, func(index int, item int) bool {
+		sum += item
+		return true
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 4950, sum)
+
+	// stop by fn
+	visited := 0
+	err = Iterate(context.Background(), items, func(index int, item int) bool {
+		visited++
+		return index < 9
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, visited)
+}
+
+func TestIterateCancelled(t *testing.T) {
+	items := make([]int, 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	visited := 0
+	err := Iterate(ctx, items, func(index int, item int) bool {
+		visited++
+		return true
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, visited)
+
+	// cancelled in the middle, stops within one check interval
+	ctx, cancel = context.WithCancel(context.Background())
+	visited = 0
+	err = Iterate(ctx, items, func(index int, item int) bool {
+		visited++
+		if index == 100 {
+			cancel()
+		}
+		return true
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, visited, 100+iterateCheckInterval+1)
+}
+
+func BenchmarkIterate(b *testing.B) {
+	items := make([]int, 10000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.Run("range", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			for _, item := range items {
+				sum += item
+			}
+		}
+	})
+	b.Run("iterate", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			Iterate(ctx, items, func(index int, item int) bool {
+				sum += item
+				return true
+			})
+		}
+	})
+}
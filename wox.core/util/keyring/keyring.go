@@ -0,0 +1,25 @@
+package keyring
+
+import "errors"
+
+// ErrNotFound is returned when there is no secret for the given service and account
+var ErrNotFound = errors.New("secret not found")
+
+// ErrNotSupported is returned when the credential store of OS is not available, E.g. secret-tool is not installed
+// or no Secret Service is running on linux
+var ErrNotSupported = errors.New("os credential store is not available")
+
+// Set saves a secret to the credential store of OS (Keychain on macOS, Credential Manager on Windows, Secret Service on linux)
+func Set(service string, account string, secret string) error {
+	return set(service, account, secret)
+}
+
+// Get reads a secret from the credential store of OS, returns ErrNotFound if it doesn't exist
+func Get(service string, account string) (string, error) {
+	return get(service, account)
+}
+
+// Delete removes a secret from the credential store of OS, deleting a secret that doesn't exist is not an error
+func Delete(service string, account string) error {
+	return del(service, account)
+}
@@ -0,0 +1,49 @@
+package keyring
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const securityCmd = "/usr/bin/security"
+
+// errSecItemNotFound exit code of security command
+const errSecItemNotFound = 44
+
+func set(service string, account string, secret string) error {
+	// use interactive mode and pass command via stdin, so the secret won't be visible in process list
+	cmd := exec.Command(securityCmd, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(service), quote(account), quote(secret)))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to save secret to keychain: %s, %s", err.Error(), string(output))
+	}
+	return nil
+}
+
+func get(service string, account string) (string, error) {
+	output, err := exec.Command(securityCmd, "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == errSecItemNotFound {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read secret from keychain: %w", err)
+	}
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+func del(service string, account string) error {
+	err := exec.Command(securityCmd, "delete-generic-password", "-s", service, "-a", account).Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == errSecItemNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to delete secret from keychain: %w", err)
+	}
+	return nil
+}
+
+// quote escapes a value for the command line parser of security interactive mode
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,75 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secret-tool is the command line client of libsecret, which talks to the Secret Service (E.g. gnome-keyring, kwallet)
+
+func set(service string, account string, secret string) error {
+	if !isSecretToolAvailable() {
+		return ErrNotSupported
+	}
+
+	// secret is passed via stdin, so it won't be visible in process list
+	cmd := exec.Command("secret-tool", "store", "--label", fmt.Sprintf("%s %s", service, account), "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if isSecretServiceUnavailable(string(output)) {
+			return ErrNotSupported
+		}
+		return fmt.Errorf("failed to save secret to secret service: %s, %s", err.Error(), string(output))
+	}
+	return nil
+}
+
+func get(service string, account string) (string, error) {
+	if !isSecretToolAvailable() {
+		return "", ErrNotSupported
+	}
+
+	output, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	if err != nil {
+		// secret-tool exits with 1 and prints nothing if secret is not found
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && isSecretServiceUnavailable(string(exitErr.Stderr)) {
+			return "", ErrNotSupported
+		}
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(output) == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read secret from secret service: %w", err)
+	}
+	return string(output), nil
+}
+
+func del(service string, account string) error {
+	if !isSecretToolAvailable() {
+		return ErrNotSupported
+	}
+
+	// clear exits with 1 if nothing is deleted, which is not an error for us
+	output, err := exec.Command("secret-tool", "clear", "service", service, "account", account).CombinedOutput()
+	if err != nil && isSecretServiceUnavailable(string(output)) {
+		return ErrNotSupported
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return fmt.Errorf("failed to delete secret from secret service: %w", err)
+	}
+	return nil
+}
+
+func isSecretToolAvailable() bool {
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+// isSecretServiceUnavailable returns true if secret-tool failed because no Secret Service is running (E.g. headless session without
+// gnome-keyring), callers fall back like secret-tool is not installed, see ErrNotSupported
+func isSecretServiceUnavailable(output string) bool {
+	return strings.Contains(output, "org.freedesktop.secrets") || strings.Contains(output, "D-Bus") || strings.Contains(output, "DBus")
+}
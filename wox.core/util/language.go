@@ -0,0 +1,157 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LanguageUnknown is returned by DetectLanguage when language of text can't be told
+const LanguageUnknown = ""
+
+// languageMinLetters is the min number of letters to detect a language, CJK scripts are dense so they need fewer
+const languageMinLetters = 8
+const languageMinLettersCJK = 2
+
+// languageDominantRatio is the min ratio of letters in the dominant script, below it text is treated as mixed languages
+const languageDominantRatio = 0.8
+
+// languageMinStopWords is the min number of stop words of the best latin language
+const languageMinStopWords = 2
+
+// languageStopWords are frequent words which tell languages written in latin script apart
+var languageStopWords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "for", "with", "this", "you", "was", "on", "be", "have", "not", "what", "how"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "ich", "zu", "mit", "den", "sie", "es", "auf", "für", "wie", "wir", "auch", "sind"},
+	"fr": {"le", "la", "les", "et", "est", "un", "une", "des", "je", "pas", "que", "qui", "dans", "pour", "avec", "ce", "sur", "vous", "nous", "au"},
+	"es": {"el", "los", "las", "y", "es", "un", "una", "que", "por", "para", "con", "del", "se", "no", "como", "pero", "muy", "está", "yo", "su"},
+	"pt": {"o", "os", "as", "e", "é", "um", "uma", "que", "não", "para", "com", "do", "da", "em", "se", "eu", "mas", "você", "isso", "está"},
+	"it": {"il", "lo", "gli", "e", "è", "un", "una", "che", "non", "per", "con", "del", "della", "sono", "io", "ma", "come", "questo", "anche", "mi"},
+	"nl": {"de", "het", "een", "en", "is", "niet", "van", "dat", "ik", "je", "op", "te", "met", "zijn", "voor", "maar", "ook", "wat", "er", "hij"},
+}
+
+// DetectLanguage returns a best-guess ISO 639-1 code (E.g. "en", "zh", "ja") of text, it's meant for picking sensible defaults
+// (E.g. target language of translation), not for accuracy. LanguageUnknown is returned if text is too short or mixes languages.
+//
+// Languages with their own script are told by script (zh, ja, ko, ru, uk, el, ar, he, th, hi), languages written in latin script
+// are told by stop words (en, de, fr, es, pt, it, nl)
+func DetectLanguage(text string) string {
+	scripts := map[string]int{}
+	total := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		total++
+		scripts[getLetterScript(r)]++
+	}
+
+	// Japanese mixes kanji with kana, kanji alone is treated as Chinese
+	if scripts["kana"] > 0 {
+		scripts["kana"] += scripts["han"]
+		delete(scripts, "han")
+	}
+
+	dominant, dominantCount := "", 0
+	for script, count := range scripts {
+		if count > dominantCount {
+			dominant, dominantCount = script, count
+		}
+	}
+	if dominant == "" || float64(dominantCount) < float64(total)*languageDominantRatio {
+		return LanguageUnknown
+	}
+
+	minLetters := languageMinLetters
+	if dominant == "han" || dominant == "kana" || dominant == "hangul" {
+		minLetters = languageMinLettersCJK
+	}
+	if dominantCount < minLetters {
+		return LanguageUnknown
+	}
+
+	switch dominant {
+	case "han":
+		return "zh"
+	case "kana":
+		return "ja"
+	case "hangul":
+		return "ko"
+	case "cyrillic":
+		if strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+			return "uk"
+		}
+		return "ru"
+	case "greek":
+		return "el"
+	case "arabic":
+		return "ar"
+	case "hebrew":
+		return "he"
+	case "thai":
+		return "th"
+	case "devanagari":
+		return "hi"
+	case "latin":
+		return detectLatinLanguage(text)
+	}
+	return LanguageUnknown
+}
+
+func getLetterScript(r rune) string {
+	switch {
+	case unicode.Is(unicode.Latin, r):
+		return "latin"
+	case unicode.Is(unicode.Han, r):
+		return "han"
+	case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+		return "kana"
+	case unicode.Is(unicode.Hangul, r):
+		return "hangul"
+	case unicode.Is(unicode.Cyrillic, r):
+		return "cyrillic"
+	case unicode.Is(unicode.Greek, r):
+		return "greek"
+	case unicode.Is(unicode.Arabic, r):
+		return "arabic"
+	case unicode.Is(unicode.Hebrew, r):
+		return "hebrew"
+	case unicode.Is(unicode.Thai, r):
+		return "thai"
+	case unicode.Is(unicode.Devanagari, r):
+		return "devanagari"
+	}
+	return "other"
+}
+
+// detectLatinLanguage picks the latin language with most stop words in text, it must be a clear winner
+func detectLatinLanguage(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	hits := map[string]int{}
+	for _, word := range words {
+		for language, stopWords := range languageStopWords {
+			for _, stopWord := range stopWords {
+				if word == stopWord {
+					hits[language]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestHits, secondHits := "", 0, 0
+	for language, count := range hits {
+		if count > bestHits {
+			best, bestHits, secondHits = language, count, bestHits
+		} else if count > secondHits {
+			secondHits = count
+		}
+	}
+	// ties (E.g. short text of Spanish and Portuguese which share many words) are unknown
+	if bestHits < languageMinStopWords || bestHits == secondHits {
+		return LanguageUnknown
+	}
+	return best
+}
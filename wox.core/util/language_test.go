@@ -0,0 +1,30 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	assert.Equal(t, "en", DetectLanguage("This is the best way to learn a new language"))
+	assert.Equal(t, "de", DetectLanguage("Das ist nicht so einfach, wie es aussieht"))
+	assert.Equal(t, "fr", DetectLanguage("Je ne sais pas ce que tu veux dire avec ça"))
+	assert.Equal(t, "es", DetectLanguage("El perro de mi vecino es muy grande y no para de ladrar"))
+	assert.Equal(t, "zh", DetectLanguage("今天天气很好"))
+	assert.Equal(t, "ja", DetectLanguage("今日はいい天気ですね"))
+	assert.Equal(t, "ko", DetectLanguage("안녕하세요"))
+	assert.Equal(t, "ru", DetectLanguage("Привет, как дела?"))
+	assert.Equal(t, "uk", DetectLanguage("Привіт, як справи? Все добре"))
+}
+
+func TestDetectLanguage_Unknown(t *testing.T) {
+	// too short
+	assert.Equal(t, LanguageUnknown, DetectLanguage("ok"))
+	assert.Equal(t, LanguageUnknown, DetectLanguage("字"))
+	assert.Equal(t, LanguageUnknown, DetectLanguage("12345 !!"))
+	// mixed languages
+	assert.Equal(t, LanguageUnknown, DetectLanguage("hello world 你好世界"))
+	// latin without stop words
+	assert.Equal(t, LanguageUnknown, DetectLanguage("Kubernetes Terraform Ansible"))
+}
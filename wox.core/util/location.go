@@ -94,6 +94,9 @@ func (l *Location) Init() error {
 	if directoryErr := l.EnsureDirectoryExist(l.GetPluginSettingDirectory()); directoryErr != nil {
 		return directoryErr
 	}
+	if directoryErr := l.EnsureDirectoryExist(l.GetPluginStorageDirectory()); directoryErr != nil {
+		return directoryErr
+	}
 	if directoryErr := l.EnsureDirectoryExist(l.GetUIDirectory()); directoryErr != nil {
 		return directoryErr
 	}
@@ -103,6 +106,12 @@ func (l *Location) Init() error {
 	if directoryErr := l.EnsureDirectoryExist(l.GetImageCacheDirectory()); directoryErr != nil {
 		return directoryErr
 	}
+	if directoryErr := l.EnsureDirectoryExist(l.GetHTTPCacheDirectory()); directoryErr != nil {
+		return directoryErr
+	}
+	if directoryErr := l.EnsureDirectoryExist(l.GetAnalyticsDirectory()); directoryErr != nil {
+		return directoryErr
+	}
 	if directoryErr := l.EnsureDirectoryExist(l.GetBackupDirectory()); directoryErr != nil {
 		return directoryErr
 	}
@@ -149,6 +158,11 @@ func (l *Location) GetPluginSettingDirectory() string {
 	return path.Join(l.userDataDirectory, "settings")
 }
 
+// key-value storage of plugins, see plugin.KVStore
+func (l *Location) GetPluginStorageDirectory() string {
+	return path.Join(l.userDataDirectory, "storage")
+}
+
 func (l *Location) GetUserDataDirectory() string {
 	return l.userDataDirectory
 }
@@ -177,6 +191,16 @@ func (l *Location) GetImageCacheDirectory() string {
 	return path.Join(l.GetCacheDirectory(), "images")
 }
 
+// responses cached by HTTPClient
+func (l *Location) GetHTTPCacheDirectory() string {
+	return path.Join(l.GetCacheDirectory(), "http")
+}
+
+// usage events written when user enabled analytics, see plugin.AnalyticsEvent
+func (l *Location) GetAnalyticsDirectory() string {
+	return path.Join(l.userDataDirectory, "analytics")
+}
+
 func (l *Location) GetBackupDirectory() string {
 	return path.Join(l.woxDataDirectory, "backup")
 }
@@ -10,6 +10,10 @@ func ShellOpen(path string) error {
 	return exec.Command("open", path).Start()
 }
 
+func ShellOpenWithApp(appPath string, path string) error {
+	return exec.Command("open", "-a", appPath, path).Start()
+}
+
 func ShellRun(name string, arg ...string) (*exec.Cmd, error) {
 	cmd := exec.Command(name, arg...)
 	cmd.Stdout = GetLogger().GetWriter()
@@ -1,14 +1,21 @@
 package util
 
 import (
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 )
 
 func ShellOpen(path string) error {
 	return exec.Command("xdg-open", path).Start()
 }
 
+func ShellOpenWithApp(appPath string, path string) error {
+	return exec.Command(appPath, path).Start()
+}
+
 func ShellRun(name string, arg ...string) (*exec.Cmd, error) {
 	cmd := exec.Command(name, arg...)
 	cmd.Stdout = GetLogger().GetWriter()
@@ -44,5 +51,21 @@ func ShellRunOutput(name string, arg ...string) ([]byte, error) {
 }
 
 func ShellOpenFileInFolder(path string) error {
-	return exec.Command("xdg-open", path).Start()
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	// most file managers implement the freedesktop FileManager1 interface, which is able to select the item.
+	// dbus-send splits array items by comma, which is left unescaped in uri paths
+	fileUri := strings.ReplaceAll((&url.URL{Scheme: "file", Path: absPath}).String(), ",", "%2C")
+	showErr := exec.Command("dbus-send", "--session", "--dest=org.freedesktop.FileManager1", "--type=method_call",
+		"/org/freedesktop/FileManager1", "org.freedesktop.FileManager1.ShowItems",
+		"array:string:"+fileUri, "string:").Run()
+	if showErr == nil {
+		return nil
+	}
+
+	// fallback to open the parent folder without selection
+	return exec.Command("xdg-open", filepath.Dir(absPath)).Start()
 }
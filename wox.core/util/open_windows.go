@@ -11,6 +11,10 @@ func ShellOpen(path string) error {
 	return exec.Command("cmd", "/C", "start", "explorer.exe", path).Start()
 }
 
+func ShellOpenWithApp(appPath string, path string) error {
+	return exec.Command(appPath, path).Start()
+}
+
 func ShellRun(name string, arg ...string) (*exec.Cmd, error) {
 	return ShellRunWithEnv(name, []string{"PYTHONIOENCODING=utf-8"}, arg...)
 }
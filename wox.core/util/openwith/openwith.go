@@ -0,0 +1,43 @@
+package openwith
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// openCheckDuration is how long Open waits for the app to fail before it's considered started successfully.
+// Apps that can't open a file (E.g. unsupported type) usually exit with error right away
+const openCheckDuration = 500 * time.Millisecond
+
+// App is an application which can open a file, see GetApps
+type App struct {
+	Name string
+	// Path identifies the app on the OS and is passed to Open:
+	// application bundle on macOS (E.g. /Applications/Preview.app), desktop entry file on linux, executable on windows
+	Path string
+	// true if it's the default application of the file type
+	IsDefault bool
+}
+
+// waitStarted starts cmd and returns error if it fails to start or exits with error within openCheckDuration
+func waitStarted(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		if err != nil {
+			return fmt.Errorf("application failed to open the file: %w", err)
+		}
+		return nil
+	case <-time.After(openCheckDuration):
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package openwith
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation -framework CoreServices
+#include <stdlib.h>
+
+char *getOpenWithApps(const char *filePath);
+*/
+import "C"
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unsafe"
+)
+
+// GetApps returns applications which can open filePath according to Launch Services, the default application comes first
+func GetApps(filePath string) ([]App, error) {
+	cFilePath := C.CString(filePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+
+	cPaths := C.getOpenWithApps(cFilePath)
+	defer C.free(unsafe.Pointer(cPaths))
+
+	var apps []App
+	for index, appPath := range strings.Split(C.GoString(cPaths), "\n") {
+		if appPath == "" {
+			continue
+		}
+		apps = append(apps, App{
+			Name:      strings.TrimSuffix(filepath.Base(appPath), ".app"),
+			Path:      appPath,
+			IsDefault: index == 0,
+		})
+	}
+
+	return apps, nil
+}
+
+// Open opens filePath with the application bundle, see App.Path.
+// open command exits with error if the app can't be found or can't open the file
+func Open(appPath string, filePath string) error {
+	return waitStarted(exec.Command("open", "-a", appPath, filePath))
+}
@@ -0,0 +1,33 @@
+#import <Foundation/Foundation.h>
+#import <CoreServices/CoreServices.h>
+
+// returns paths of applications which can open the file separated by newline, the first line is the default application (empty if there is none).
+// caller must free the returned string
+char *getOpenWithApps(const char *filePath) {
+    @autoreleasepool {
+        NSURL *fileURL = [NSURL fileURLWithPath:[NSString stringWithUTF8String:filePath]];
+        NSMutableArray *paths = [[NSMutableArray alloc] init];
+
+        CFURLRef defaultAppURL = LSCopyDefaultApplicationURLForURL((__bridge CFURLRef)fileURL, kLSRolesAll, NULL);
+        if (defaultAppURL != NULL) {
+            [paths addObject:[(__bridge NSURL *)defaultAppURL path]];
+            CFRelease(defaultAppURL);
+        } else {
+            // keep first line for the default application
+            [paths addObject:@""];
+        }
+
+        CFArrayRef appURLs = LSCopyApplicationURLsForURL((__bridge CFURLRef)fileURL, kLSRolesAll);
+        if (appURLs != NULL) {
+            for (NSURL *appURL in (__bridge NSArray *)appURLs) {
+                NSString *path = [appURL path];
+                if (![paths containsObject:path]) {
+                    [paths addObject:path];
+                }
+            }
+            CFRelease(appURLs);
+        }
+
+        return strdup([[paths componentsJoinedByString:@"\n"] UTF8String]);
+    }
+}
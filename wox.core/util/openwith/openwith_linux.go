@@ -0,0 +1,160 @@
+package openwith
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type desktopEntry struct {
+	name      string
+	exec      string
+	mimeTypes []string
+	hidden    bool
+}
+
+// GetApps returns applications which declare they can open the type of filePath, the default application comes first.
+// Apps are read from desktop entries in XDG data dirs, type of file is detected by xdg-mime
+func GetApps(filePath string) ([]App, error) {
+	mimeOutput, err := exec.Command("xdg-mime", "query", "filetype", filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect file type: %w", err)
+	}
+	mimeType := strings.TrimSpace(string(mimeOutput))
+
+	var defaultDesktopId string
+	if defaultOutput, defaultErr := exec.Command("xdg-mime", "query", "default", mimeType).Output(); defaultErr == nil {
+		defaultDesktopId = strings.TrimSpace(string(defaultOutput))
+	}
+
+	var apps []App
+	seen := map[string]bool{}
+	for _, dir := range getApplicationDirs() {
+		entries, readErr := os.ReadDir(dir)
+		if readErr != nil {
+			continue
+		}
+		for _, entry := range entries {
+			// desktop entries in former dirs take precedence, see XDG desktop entry spec
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".desktop") || seen[entry.Name()] {
+				continue
+			}
+			seen[entry.Name()] = true
+
+			desktopPath := filepath.Join(dir, entry.Name())
+			desktop, parseErr := parseDesktopEntry(desktopPath)
+			if parseErr != nil || desktop.hidden || !slices.Contains(desktop.mimeTypes, mimeType) {
+				continue
+			}
+
+			app := App{Name: desktop.name, Path: desktopPath, IsDefault: entry.Name() == defaultDesktopId}
+			if app.IsDefault {
+				apps = append([]App{app}, apps...)
+			} else {
+				apps = append(apps, app)
+			}
+		}
+	}
+
+	return apps, nil
+}
+
+// Open opens filePath with the app of given desktop entry, see App.Path
+func Open(appPath string, filePath string) error {
+	desktop, err := parseDesktopEntry(appPath)
+	if err != nil {
+		return err
+	}
+	if desktop.exec == "" {
+		return fmt.Errorf("no Exec in desktop entry: %s", appPath)
+	}
+
+	args := expandDesktopExec(desktop.exec, filePath)
+	return waitStarted(exec.Command(args[0], args[1:]...))
+}
+
+func getApplicationDirs() []string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+	}
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+
+	var dirs []string
+	if dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "applications"))
+	}
+	for _, dir := range strings.Split(dataDirs, ":") {
+		if dir != "" {
+			dirs = append(dirs, filepath.Join(dir, "applications"))
+		}
+	}
+	return dirs
+}
+
+func parseDesktopEntry(path string) (desktopEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return desktopEntry{}, err
+	}
+	defer file.Close()
+
+	var desktop desktopEntry
+	inMainGroup := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inMainGroup = line == "[Desktop Entry]"
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !inMainGroup || !found {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "Name":
+			desktop.name = strings.TrimSpace(value)
+		case "Exec":
+			desktop.exec = strings.TrimSpace(value)
+		case "MimeType":
+			desktop.mimeTypes = strings.FieldsFunc(value, func(r rune) bool { return r == ';' })
+		case "NoDisplay", "Hidden":
+			desktop.hidden = desktop.hidden || strings.TrimSpace(value) == "true"
+		}
+	}
+
+	return desktop, scanner.Err()
+}
+
+// expandDesktopExec splits Exec of a desktop entry into args, file field codes are replaced by filePath and other field codes are removed.
+// If Exec has no file field code, filePath is appended
+func expandDesktopExec(execLine string, filePath string) []string {
+	var args []string
+	hasFileCode := false
+	for _, field := range strings.Fields(execLine) {
+		field = strings.Trim(field, `"`)
+		switch field {
+		case "%f", "%F", "%u", "%U":
+			args = append(args, filePath)
+			hasFileCode = true
+		case "%i", "%c", "%k":
+		default:
+			args = append(args, strings.ReplaceAll(field, "%%", "%"))
+		}
+	}
+	if !hasFileCode {
+		args = append(args, filePath)
+	}
+	return args
+}
@@ -0,0 +1,14 @@
+package openwith
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandDesktopExec(t *testing.T) {
+	assert.Equal(t, []string{"gedit", "/tmp/a b.txt"}, expandDesktopExec("gedit %U", "/tmp/a b.txt"))
+	assert.Equal(t, []string{"code", "--new-window", "/tmp/a.txt"}, expandDesktopExec("code --new-window %F %i", "/tmp/a.txt"))
+	// file is appended if Exec has no file field code
+	assert.Equal(t, []string{"vlc", "/tmp/a.mp4"}, expandDesktopExec("vlc", "/tmp/a.mp4"))
+}
@@ -0,0 +1,115 @@
+package openwith
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// GetApps returns applications registered to open the extension of filePath, the default application comes first.
+// Apps are read from OpenWithProgids and OpenWithList of the extension in registry
+func GetApps(filePath string) ([]App, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext == "" {
+		return nil, nil
+	}
+
+	var progIds []string
+	defaultProgId := readStringValue(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Explorer\FileExts\`+ext+`\UserChoice`, "ProgId")
+	if defaultProgId == "" {
+		defaultProgId = readStringValue(registry.CLASSES_ROOT, ext, "")
+	}
+	if defaultProgId != "" {
+		progIds = append(progIds, defaultProgId)
+	}
+	progIds = append(progIds, readValueNames(registry.CLASSES_ROOT, ext+`\OpenWithProgids`)...)
+
+	var apps []App
+	seen := map[string]bool{}
+	addApp := func(exePath string, name string, isDefault bool) {
+		if exePath == "" || seen[strings.ToLower(exePath)] {
+			return
+		}
+		if _, err := os.Stat(exePath); err != nil {
+			return
+		}
+		seen[strings.ToLower(exePath)] = true
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(exePath), filepath.Ext(exePath))
+		}
+		apps = append(apps, App{Name: name, Path: exePath, IsDefault: isDefault})
+	}
+
+	for _, progId := range progIds {
+		exePath := parseCommandExecutable(readStringValue(registry.CLASSES_ROOT, progId+`\shell\open\command`, ""))
+		addApp(exePath, readStringValue(registry.CLASSES_ROOT, progId, ""), progId == defaultProgId)
+	}
+
+	// OpenWithList has executable names (E.g. "notepad.exe") as values of "a", "b", "c"...
+	openWithListKey := `Software\Microsoft\Windows\CurrentVersion\Explorer\FileExts\` + ext + `\OpenWithList`
+	for _, valueName := range readValueNames(registry.CURRENT_USER, openWithListKey) {
+		exeName := readStringValue(registry.CURRENT_USER, openWithListKey, valueName)
+		if exeName == "" || valueName == "MRUList" {
+			continue
+		}
+		appKey := `Applications\` + exeName
+		exePath := parseCommandExecutable(readStringValue(registry.CLASSES_ROOT, appKey+`\shell\open\command`, ""))
+		addApp(exePath, readStringValue(registry.CLASSES_ROOT, appKey, "FriendlyAppName"), false)
+	}
+
+	return apps, nil
+}
+
+// Open opens filePath with the executable of app, see App.Path
+func Open(appPath string, filePath string) error {
+	return waitStarted(exec.Command(appPath, filePath))
+}
+
+func readStringValue(root registry.Key, path string, name string) string {
+	key, err := registry.OpenKey(root, path, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	if err != nil {
+		return ""
+	}
+	if expanded, expandErr := registry.ExpandString(value); expandErr == nil {
+		return expanded
+	}
+	return value
+}
+
+func readValueNames(root registry.Key, path string) []string {
+	key, err := registry.OpenKey(root, path, registry.QUERY_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+
+	names, err := key.ReadValueNames(0)
+	if err != nil {
+		return nil
+	}
+	return names
+}
+
+// parseCommandExecutable returns the executable of an open command in registry, E.g. `"C:\Program Files\App\app.exe" "%1"`
+func parseCommandExecutable(command string) string {
+	command = strings.TrimSpace(command)
+	if strings.HasPrefix(command, `"`) {
+		if end := strings.Index(command[1:], `"`); end >= 0 {
+			return command[1 : end+1]
+		}
+		return ""
+	}
+	if index := strings.Index(strings.ToLower(command), ".exe"); index >= 0 {
+		return command[:index+len(".exe")]
+	}
+	return ""
+}
@@ -0,0 +1,6 @@
+package quicklook
+
+import "errors"
+
+// ErrNotSupported is returned by Preview on platforms without a native file previewer
+var ErrNotSupported = errors.New("quick look is not supported on this platform")
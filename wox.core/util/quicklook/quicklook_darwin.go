@@ -0,0 +1,12 @@
+package quicklook
+
+import "os/exec"
+
+func IsSupported() bool {
+	return true
+}
+
+// Preview shows the Quick Look panel of path and returns once it's shown, the panel is closed by user
+func Preview(path string) error {
+	return exec.Command("qlmanage", "-p", path).Start()
+}
@@ -0,0 +1,48 @@
+package quicklook
+
+import (
+	"net/url"
+	"path/filepath"
+	"slices"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const sushiBusName = "org.gnome.NautilusPreviewer"
+
+// IsSupported returns true if the OS has a native file previewer, on linux it's GNOME Sushi which is only available
+// if it's running or can be activated on the session bus
+func IsSupported() bool {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return false
+	}
+
+	for _, method := range []string{"org.freedesktop.DBus.ListNames", "org.freedesktop.DBus.ListActivatableNames"} {
+		var names []string
+		if conn.BusObject().Call(method, 0).Store(&names) == nil && slices.Contains(names, sushiBusName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Preview shows path in GNOME Sushi (the previewer of Nautilus), ErrNotSupported is returned if it's not available
+func Preview(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return ErrNotSupported
+	}
+
+	fileUri := (&url.URL{Scheme: "file", Path: absPath}).String()
+	call := conn.Object(sushiBusName, "/org/gnome/NautilusPreviewer").Call(sushiBusName+".ShowFile", 0, fileUri, int32(0), false)
+	if call.Err != nil {
+		return ErrNotSupported
+	}
+	return nil
+}
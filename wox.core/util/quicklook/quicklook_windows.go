@@ -0,0 +1,11 @@
+package quicklook
+
+// IsSupported returns true if the OS has a native file previewer
+func IsSupported() bool {
+	return false
+}
+
+// Preview shows the native file previewer of path, windows has none so ErrNotSupported is always returned
+func Preview(path string) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRateLimitPerSecond = 5.0
+	DefaultRateLimitBurst     = 10
+)
+
+// RateLimiter is a token bucket limiter. It holds at most burst tokens and refills perSecond tokens every second,
+// each request takes one token. Share one limiter between requests to the same service to throttle them together.
+type RateLimiter struct {
+	lock         sync.Mutex
+	perSecond    float64
+	burst        float64
+	tokens       float64
+	lastRefill   time.Time
+	blockedUntil time.Time // set by server side throttling, E.g. Retry-After header
+}
+
+// NewRateLimiter creates a limiter which allows perSecond requests per second with bursts up to burst requests.
+// Non-positive values are replaced by DefaultRateLimitPerSecond and DefaultRateLimitBurst.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if perSecond <= 0 {
+		perSecond = DefaultRateLimitPerSecond
+	}
+	if burst <= 0 {
+		burst = DefaultRateLimitBurst
+	}
+
+	return &RateLimiter{
+		perSecond:  perSecond,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// BlockUntil stops handing out tokens until t, used when server asks client to slow down
+func (l *RateLimiter) BlockUntil(t time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if t.After(l.blockedUntil) {
+		l.blockedUntil = t
+	}
+}
+
+// reserve takes a token if available, otherwise returns how long to wait before trying again
+func (l *RateLimiter) reserve() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.lastRefill).Seconds()*l.perSecond)
+	l.lastRefill = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.perSecond * float64(time.Second))
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(100, 2)
+	ctx := context.Background()
+
+	// burst is available right away
+	start := time.Now()
+	assert.NoError(t, limiter.Wait(ctx))
+	assert.NoError(t, limiter.Wait(ctx))
+	assert.Less(t, time.Since(start), 5*time.Millisecond)
+
+	// next token is refilled after 1/perSecond
+	start = time.Now()
+	assert.NoError(t, limiter.Wait(ctx))
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+
+	limiter.BlockUntil(time.Now().Add(50 * time.Millisecond))
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(cancelCtx), context.DeadlineExceeded)
+
+	// an earlier time doesn't shorten the block
+	limiter.BlockUntil(time.Now())
+	start = time.Now()
+	assert.NoError(t, limiter.Wait(ctx))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestNewRateLimiter_Defaults(t *testing.T) {
+	limiter := NewRateLimiter(0, -1)
+	assert.Equal(t, DefaultRateLimitPerSecond, limiter.perSecond)
+	assert.Equal(t, float64(DefaultRateLimitBurst), limiter.burst)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	retryAfter, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, retryAfter)
+
+	retryAfter, ok = parseRetryAfter("3600")
+	assert.True(t, ok)
+	assert.Equal(t, rateLimitMaxRetryAfter, retryAfter)
+
+	retryAfter, ok = parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), retryAfter)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestRateLimitedHTTPClient_Retry(t *testing.T) {
+	GetLocation().Init()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewRateLimitedHTTPClient(nil)
+	body, err := client.Get(context.Background(), server.URL, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), requests.Load())
+
+	// without Retry-After the throttled response is returned as it is
+	noRetryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer noRetryServer.Close()
+
+	_, err = client.Get(context.Background(), noRetryServer.URL, nil)
+	assert.Error(t, err)
+}
+
+func TestRateLimitedHTTPClient_RetryLimit(t *testing.T) {
+	GetLocation().Init()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "wox", r.Header.Get("X-Client"))
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewRateLimitedHTTPClient(nil)
+	_, err := client.Get(context.Background(), server.URL, map[string]string{"X-Client": "wox"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(rateLimitMaxRetries+1), requests.Load())
+
+	// a body which can't be replayed is sent only once
+	requests.Store(0)
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader("payload")))
+	assert.NoError(t, err)
+	req.Header.Set("X-Client", "wox")
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,57 @@
+package sharesheet
+
+import (
+	"errors"
+	"fmt"
+)
+
+type PayloadType string
+
+const (
+	PayloadTypeText  PayloadType = "text"
+	PayloadTypeUrl   PayloadType = "url"
+	PayloadTypeFiles PayloadType = "files"
+)
+
+// ErrNotSupported is returned by Share on platforms without a native share sheet
+var ErrNotSupported = errors.New("share sheet is not supported on this platform")
+
+// Payload is the content passed to the share sheet of the OS, create it by NewTextPayload, NewUrlPayload or NewFilesPayload
+type Payload struct {
+	Type      PayloadType
+	Text      string   // only available when type is PayloadTypeText
+	Url       string   // only available when type is PayloadTypeUrl
+	FilePaths []string // absolute paths, only available when type is PayloadTypeFiles
+}
+
+func NewTextPayload(text string) Payload {
+	return Payload{Type: PayloadTypeText, Text: text}
+}
+
+func NewUrlPayload(url string) Payload {
+	return Payload{Type: PayloadTypeUrl, Url: url}
+}
+
+func NewFilesPayload(filePaths []string) Payload {
+	return Payload{Type: PayloadTypeFiles, FilePaths: filePaths}
+}
+
+func (p Payload) validate() error {
+	switch p.Type {
+	case PayloadTypeText:
+		if p.Text == "" {
+			return errors.New("text to share is empty")
+		}
+	case PayloadTypeUrl:
+		if p.Url == "" {
+			return errors.New("url to share is empty")
+		}
+	case PayloadTypeFiles:
+		if len(p.FilePaths) == 0 {
+			return errors.New("no files to share")
+		}
+	default:
+		return fmt.Errorf("unknown share payload type: %s", p.Type)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package sharesheet
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation -framework AppKit
+#include <stdlib.h>
+
+void showShareSheet(const char *text, const char *url, const char **filePaths, int count);
+*/
+import "C"
+import (
+	"unsafe"
+
+	"golang.design/x/hotkey/mainthread"
+)
+
+func IsSupported() bool {
+	return true
+}
+
+// Share shows the share sheet at mouse position and returns once it's shown, it doesn't wait for user.
+// Dismissing the share sheet without choosing a service is not an error, nothing is shared then
+func Share(payload Payload) error {
+	if err := payload.validate(); err != nil {
+		return err
+	}
+
+	mainthread.Call(func() {
+		var cText, cUrl *C.char
+		if payload.Type == PayloadTypeText {
+			cText = C.CString(payload.Text)
+			defer C.free(unsafe.Pointer(cText))
+		}
+		if payload.Type == PayloadTypeUrl {
+			cUrl = C.CString(payload.Url)
+			defer C.free(unsafe.Pointer(cUrl))
+		}
+
+		var cFilePaths []*C.char
+		for _, filePath := range payload.FilePaths {
+			cFilePath := C.CString(filePath)
+			defer C.free(unsafe.Pointer(cFilePath))
+			cFilePaths = append(cFilePaths, cFilePath)
+		}
+
+		var cFilePathsPtr **C.char
+		if len(cFilePaths) > 0 {
+			cFilePathsPtr = (**C.char)(unsafe.Pointer(&cFilePaths[0]))
+		}
+		C.showShareSheet(cText, cUrl, cFilePathsPtr, C.int(len(cFilePaths)))
+	})
+	return nil
+}
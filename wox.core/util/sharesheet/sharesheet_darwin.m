@@ -0,0 +1,64 @@
+#import <Foundation/Foundation.h>
+#import <AppKit/AppKit.h>
+
+// share sheet must be anchored to a view, we show it in a transparent window at mouse position and close the window when it's done
+@interface WoxShareSheetDelegate : NSObject <NSSharingServicePickerDelegate>
+@property (retain) NSWindow *anchorWindow;
+@end
+
+@implementation WoxShareSheetDelegate
+- (void)sharingServicePicker:(NSSharingServicePicker *)sharingServicePicker didChooseSharingService:(NSSharingService *)service {
+    // service is nil if user dismissed the share sheet
+    [self.anchorWindow orderOut:nil];
+    self.anchorWindow = nil;
+}
+@end
+
+static WoxShareSheetDelegate *currentShareSheetDelegate = nil;
+
+void showShareSheet(const char *text, const char *url, const char **filePaths, int count) {
+    @try {
+        NSMutableArray *items = [[NSMutableArray alloc] init];
+        if (text != NULL) {
+            [items addObject:[NSString stringWithUTF8String:text]];
+        }
+        if (url != NULL) {
+            NSURL *shareURL = [NSURL URLWithString:[NSString stringWithUTF8String:url]];
+            if (shareURL != nil) {
+                [items addObject:shareURL];
+            }
+        }
+        for (int i = 0; i < count; i++) {
+            [items addObject:[NSURL fileURLWithPath:[NSString stringWithUTF8String:filePaths[i]]]];
+        }
+        if (items.count == 0) {
+            NSLog(@"Nothing to share.");
+            return;
+        }
+
+        NSPoint mouseLocation = [NSEvent mouseLocation];
+        NSWindow *anchorWindow = [[NSWindow alloc] initWithContentRect:NSMakeRect(mouseLocation.x, mouseLocation.y, 1, 1)
+                                                             styleMask:NSWindowStyleMaskBorderless
+                                                               backing:NSBackingStoreBuffered
+                                                                 defer:NO];
+        [anchorWindow setReleasedWhenClosed:NO];
+        [anchorWindow setOpaque:NO];
+        [anchorWindow setBackgroundColor:[NSColor clearColor]];
+        [anchorWindow setLevel:NSPopUpMenuWindowLevel];
+        [anchorWindow makeKeyAndOrderFront:nil];
+        [NSApp activateIgnoringOtherApps:YES];
+
+        if (currentShareSheetDelegate != nil) {
+            [currentShareSheetDelegate.anchorWindow orderOut:nil];
+        } else {
+            currentShareSheetDelegate = [[WoxShareSheetDelegate alloc] init];
+        }
+        currentShareSheetDelegate.anchorWindow = anchorWindow;
+
+        NSSharingServicePicker *picker = [[NSSharingServicePicker alloc] initWithItems:items];
+        picker.delegate = currentShareSheetDelegate;
+        [picker showRelativeToRect:anchorWindow.contentView.bounds ofView:anchorWindow.contentView preferredEdge:NSRectEdgeMinY];
+    } @catch (NSException *exception) {
+        NSLog(@"Exception in showShareSheet: %@", exception);
+    }
+}
@@ -0,0 +1,16 @@
+//go:build !darwin
+
+package sharesheet
+
+// IsSupported returns true if the OS has a native share sheet
+func IsSupported() bool {
+	return false
+}
+
+// Share shows the native share sheet with payload, see darwin implementation
+func Share(payload Payload) error {
+	if err := payload.validate(); err != nil {
+		return err
+	}
+	return ErrNotSupported
+}
@@ -2,9 +2,24 @@ package util
 
 import (
 	"github.com/sahilm/fuzzy"
+	"math"
 	"strings"
 )
 
+// MatchSensitivity tunes how permissive fuzzy matching is, see IsStringMatchScoreWithOptions
+type MatchSensitivity = string
+
+const (
+	MatchSensitivityStrict MatchSensitivity = "strict" // subTerm must appear as a whole in term (or its pinyin), E.g. for command lists
+	MatchSensitivityNormal MatchSensitivity = "normal" // default, see IsStringMatchScore
+	MatchSensitivityLoose  MatchSensitivity = "loose"  // any fuzzy match counts even if it scores badly, E.g. for file finders
+)
+
+type MatchOptions struct {
+	Sensitivity MatchSensitivity // empty means MatchSensitivityNormal
+	MinScore    int64            // matches scoring lower are dropped, 0 means only the threshold of Sensitivity applies
+}
+
 // PadChar left-pads s with the rune r, to length n.
 // If n is smaller than s, PadChar is a no-op.
 func LeftPad(s string, n int, r rune) string {
@@ -44,10 +59,52 @@ func IsStringMatch(term string, subTerm string, usePinYin bool) bool {
 }
 
 func IsStringMatchScore(term string, subTerm string, usePinYin bool) (isMatch bool, score int64) {
+	return isStringMatchScore(term, subTerm, usePinYin, 0)
+}
+
+// IsStringMatchScoreWithOptions is IsStringMatchScore with tuned sensitivity and minimum score, see MatchOptions
+func IsStringMatchScoreWithOptions(term string, subTerm string, usePinYin bool, options MatchOptions) (isMatch bool, score int64) {
+	switch options.Sensitivity {
+	case MatchSensitivityStrict:
+		if !isStringContainedWithPinYin(strings.ToLower(term), strings.ToLower(subTerm), usePinYin) {
+			return false, 0
+		}
+		// contained in pinyin may still score too low for fuzzy match, E.g. term = 网易云音乐, subTerm = yun
+		isMatch, score = true, int64(len(subTerm))
+		if fuzzyMatch, fuzzyScore := IsStringMatchScore(term, subTerm, usePinYin); fuzzyMatch {
+			score = fuzzyScore
+		}
+	case MatchSensitivityLoose:
+		isMatch, score = isStringMatchScore(term, subTerm, usePinYin, math.MinInt64)
+	default:
+		isMatch, score = IsStringMatchScore(term, subTerm, usePinYin)
+	}
+
+	if isMatch && options.MinScore != 0 && score < options.MinScore {
+		return false, 0
+	}
+	return isMatch, score
+}
+
+func isStringContainedWithPinYin(term string, subTerm string, usePinYin bool) bool {
+	if strings.Contains(term, subTerm) {
+		return true
+	}
+	if usePinYin {
+		for _, pyTerm := range getPinYin(term) {
+			if strings.Contains(strings.ToLower(pyTerm), subTerm) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isStringMatchScore(term string, subTerm string, usePinYin bool, minFuzzyScore int64) (isMatch bool, score int64) {
 	term = strings.ToLower(term)
 	subTerm = strings.ToLower(subTerm)
 
-	match, s := isStringMatchScoreFuzzy(term, subTerm, usePinYin)
+	match, s := isStringMatchScoreFuzzy(term, subTerm, usePinYin, minFuzzyScore)
 	if match {
 		return true, s
 	}
@@ -61,9 +118,22 @@ func IsStringMatchScore(term string, subTerm string, usePinYin bool) (isMatch bo
 	return false, 0
 }
 
-func isStringMatchScoreFuzzy(term string, subTerm string, usePinYin bool) (isMatch bool, score int64) {
-	var minMatchScore int64 = 0
+// IsStringMatchScoreLiteral matches subTerm as a case-insensitive substring of term, results starting with subTerm get a higher score
+func IsStringMatchScoreLiteral(term string, subTerm string) (isMatch bool, score int64) {
+	term = strings.ToLower(term)
+	subTerm = strings.ToLower(subTerm)
+
+	index := strings.Index(term, subTerm)
+	if index < 0 {
+		return false, 0
+	}
+	if index == 0 {
+		return true, int64(len(subTerm)) * 2
+	}
+	return true, int64(len(subTerm))
+}
 
+func isStringMatchScoreFuzzy(term string, subTerm string, usePinYin bool, minMatchScore int64) (isMatch bool, score int64) {
 	if usePinYin {
 		var matchScore int64 = -100000000
 		pyTerms := getPinYin(term)
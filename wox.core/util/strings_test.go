@@ -51,3 +51,41 @@ func TestIsStringMatchScoreLong(t *testing.T) {
 	elapsed := GetSystemTimestamp() - start
 	assert.Less(t, elapsed, int64(1000))
 }
+
+func TestStringMatcherLiteral(t *testing.T) {
+	match, score := IsStringMatchScoreLiteral("Windows Terminal", "term")
+	assert.True(t, match)
+	assert.Equal(t, int64(4), score)
+
+	match, score = IsStringMatchScoreLiteral("Terminal", "term")
+	assert.True(t, match)
+	assert.Equal(t, int64(8), score)
+
+	match, _ = IsStringMatchScoreLiteral("Microsoft SQL Server Management Studio", "mssms")
+	assert.False(t, match)
+}
+
+func TestIsStringMatchScoreWithOptions(t *testing.T) {
+	// "mssms" matches fuzzily but isn't a substring
+	match, _ := IsStringMatchScoreWithOptions("Microsoft SQL Server Management Studio", "mssms", false, MatchOptions{})
+	assert.True(t, match)
+	match, _ = IsStringMatchScoreWithOptions("Microsoft SQL Server Management Studio", "mssms", false, MatchOptions{Sensitivity: MatchSensitivityStrict})
+	assert.False(t, match)
+	match, _ = IsStringMatchScoreWithOptions("Windows Terminal", "term", false, MatchOptions{Sensitivity: MatchSensitivityStrict})
+	assert.True(t, match)
+	match, _ = IsStringMatchScoreWithOptions("网易云音乐", "yun", true, MatchOptions{Sensitivity: MatchSensitivityStrict})
+	assert.True(t, match)
+
+	// scattered characters score negative, only loose sensitivity accepts them
+	match, _ = IsStringMatchScoreWithOptions("Microsoft Remote Desktop", "mtop", false, MatchOptions{})
+	assert.False(t, match)
+	match, score := IsStringMatchScoreWithOptions("Microsoft Remote Desktop", "mtop", false, MatchOptions{Sensitivity: MatchSensitivityLoose})
+	assert.True(t, match)
+	assert.Less(t, score, int64(0))
+
+	_, score = IsStringMatchScoreWithOptions("Windows Terminal", "term", false, MatchOptions{})
+	match, _ = IsStringMatchScoreWithOptions("Windows Terminal", "term", false, MatchOptions{MinScore: score})
+	assert.True(t, match)
+	match, _ = IsStringMatchScoreWithOptions("Windows Terminal", "term", false, MatchOptions{MinScore: score + 1})
+	assert.False(t, match)
+}
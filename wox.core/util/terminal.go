@@ -0,0 +1,28 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TerminalCommandPlaceholder is replaced by the command to run in a custom terminal command template,
+// E.g. `wezterm start -- sh -c {command}` or `alacritty -e sh -c {command}`
+const TerminalCommandPlaceholder = "{command}"
+
+// RunInTerminal opens a new terminal window running the command.
+// terminalTemplate is the user configured terminal command, empty means the default terminal of the OS.
+func RunInTerminal(terminalTemplate string, command string) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command is empty")
+	}
+
+	if terminalTemplate == "" {
+		return runInDefaultTerminal(command)
+	}
+	if !strings.Contains(terminalTemplate, TerminalCommandPlaceholder) {
+		return fmt.Errorf("terminal command must contain %s placeholder", TerminalCommandPlaceholder)
+	}
+
+	// command is quoted as one argument, so the template decides how to run it (E.g. with sh -c)
+	return runShellCommand(strings.ReplaceAll(terminalTemplate, TerminalCommandPlaceholder, quoteShellArg(command)))
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func runInDefaultTerminal(command string) error {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(command)
+	script := `tell application "Terminal"
+	activate
+	do script "` + escaped + `"
+end tell`
+	return exec.Command("osascript", "-e", script).Start()
+}
+
+func runShellCommand(command string) error {
+	return exec.Command("sh", "-c", command).Start()
+}
+
+// quoteShellArg quotes s as a single POSIX shell argument
+func quoteShellArg(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// terminals tried in order when user has not configured one, with the arguments to run a command
+var linuxTerminals = [][]string{
+	{"x-terminal-emulator", "-e"},
+	{"gnome-terminal", "--"},
+	{"konsole", "-e"},
+	{"xfce4-terminal", "-x"},
+	{"xterm", "-e"},
+}
+
+func runInDefaultTerminal(command string) error {
+	for _, terminal := range linuxTerminals {
+		if _, err := exec.LookPath(terminal[0]); err != nil {
+			continue
+		}
+
+		args := append(terminal[1:], "sh", "-c", command)
+		return exec.Command(terminal[0], args...).Start()
+	}
+
+	return fmt.Errorf("no terminal found, please set terminal command in settings")
+}
+
+func runShellCommand(command string) error {
+	return exec.Command("sh", "-c", command).Start()
+}
+
+// quoteShellArg quotes s as a single POSIX shell argument
+func quoteShellArg(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+func runInDefaultTerminal(command string) error {
+	// start cmd in its own console window instead of through `start`, so the command is parsed by one cmd only.
+	// /s strips the outer quotes and keeps the rest as it is, so quotes and &, | in command are run by the new console
+	// instead of being split by an outer cmd. /k keeps the window open after the command finishes
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine:       `cmd /s /k "` + command + `"`,
+		CreationFlags: windows.CREATE_NEW_CONSOLE,
+	}
+	return cmd.Start()
+}
+
+func runShellCommand(command string) error {
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /c ` + command, HideWindow: true}
+	return cmd.Start()
+}
+
+// quoteShellArg quotes s as a single cmd argument
+func quoteShellArg(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
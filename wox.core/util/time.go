@@ -291,3 +291,41 @@ func FormatNanoSecondsToMicroSeconds(nano int64) string {
 func GetDaysInMonth(year int, month int) int {
 	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
+
+// FormatRelativeTime formats t relative to now in a compact form, E.g. "just now", "5m ago" or "in 2h".
+// translate returns the format of a key (see relative_time_* keys in lang files), so result follows the language of the caller.
+// Differences within 45 seconds (both past and future) are "just now", zero t returns empty string
+func FormatRelativeTime(t time.Time, now time.Time, translate func(key string) string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	diff := now.Sub(t)
+	direction := "ago"
+	if diff < 0 {
+		direction = "in"
+		diff = -diff
+	}
+	if diff < 45*time.Second {
+		return translate("relative_time_just_now")
+	}
+
+	const day = 24 * time.Hour
+	var unit string
+	var count int64
+	switch {
+	case diff < time.Hour:
+		unit, count = "minutes", int64(max(diff/time.Minute, 1))
+	case diff < day:
+		unit, count = "hours", int64(diff/time.Hour)
+	case diff < 30*day:
+		unit, count = "days", int64(diff/day)
+	case diff < 360*day:
+		unit, count = "months", int64(diff/(30*day))
+	default:
+		// 360 to 365 days would be "12mo", show it as a year instead
+		unit, count = "years", int64(max(diff/(365*day), 1))
+	}
+
+	return fmt.Sprintf(translate(fmt.Sprintf("relative_time_%s_%s", unit, direction)), count)
+}
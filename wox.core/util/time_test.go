@@ -0,0 +1,44 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRelativeTime(t *testing.T) {
+	formats := map[string]string{
+		"relative_time_just_now":    "just now",
+		"relative_time_minutes_ago": "%dm ago",
+		"relative_time_hours_ago":   "%dh ago",
+		"relative_time_days_ago":    "%dd ago",
+		"relative_time_months_ago":  "%dmo ago",
+		"relative_time_years_ago":   "%dy ago",
+		"relative_time_minutes_in":  "in %dm",
+		"relative_time_hours_in":    "in %dh",
+		"relative_time_days_in":     "in %dd",
+		"relative_time_months_in":   "in %dmo",
+		"relative_time_years_in":    "in %dy",
+	}
+	translate := func(key string) string { return formats[key] }
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "", FormatRelativeTime(time.Time{}, now, translate))
+	assert.Equal(t, "just now", FormatRelativeTime(now, now, translate))
+	assert.Equal(t, "just now", FormatRelativeTime(now.Add(-44*time.Second), now, translate))
+	assert.Equal(t, "just now", FormatRelativeTime(now.Add(30*time.Second), now, translate))
+	assert.Equal(t, "1m ago", FormatRelativeTime(now.Add(-50*time.Second), now, translate))
+	assert.Equal(t, "59m ago", FormatRelativeTime(now.Add(-59*time.Minute-59*time.Second), now, translate))
+	assert.Equal(t, "2h ago", FormatRelativeTime(now.Add(-2*time.Hour-30*time.Minute), now, translate))
+	assert.Equal(t, "3d ago", FormatRelativeTime(now.Add(-3*24*time.Hour), now, translate))
+	assert.Equal(t, "2mo ago", FormatRelativeTime(now.Add(-65*24*time.Hour), now, translate))
+	assert.Equal(t, "11mo ago", FormatRelativeTime(now.Add(-359*24*time.Hour), now, translate))
+	assert.Equal(t, "1y ago", FormatRelativeTime(now.Add(-362*24*time.Hour), now, translate))
+	assert.Equal(t, "1y ago", FormatRelativeTime(now.Add(-400*24*time.Hour), now, translate))
+
+	// future times
+	assert.Equal(t, "in 5m", FormatRelativeTime(now.Add(5*time.Minute), now, translate))
+	assert.Equal(t, "in 1d", FormatRelativeTime(now.Add(36*time.Hour), now, translate))
+	assert.Equal(t, "in 2y", FormatRelativeTime(now.Add(800*24*time.Hour), now, translate))
+}
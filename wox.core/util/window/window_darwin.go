@@ -8,6 +8,8 @@ package window
 int getActiveWindowIcon(unsigned char **iconData);
 char* getActiveWindowName();
 int getActiveWindowPid();
+int activateWindowByPid(int pid);
+int activateWindowByBundleId(const char *bundleId);
 */
 import "C"
 import (
@@ -44,3 +46,15 @@ func GetActiveWindowPid() int {
 	pid := C.getActiveWindowPid()
 	return int(pid)
 }
+
+// ActivateWindowByPid brings the windows of the given process to front, returns false if the process has no window
+func ActivateWindowByPid(pid int) bool {
+	return C.activateWindowByPid(C.int(pid)) == 1
+}
+
+// ActivateWindowByBundleId brings the windows of the running application with the given bundle id to front
+func ActivateWindowByBundleId(bundleId string) bool {
+	cBundleId := C.CString(bundleId)
+	defer C.free(unsafe.Pointer(cBundleId))
+	return C.activateWindowByBundleId(cBundleId) == 1
+}
@@ -52,4 +52,27 @@ int getActiveWindowPid() {
 
         return [activeApp processIdentifier];
     }
-}
\ No newline at end of file
+}
+
+int activateWindowByPid(int pid) {
+    @autoreleasepool {
+        NSRunningApplication *app = [NSRunningApplication runningApplicationWithProcessIdentifier:pid];
+        if (!app) {
+            return 0;
+        }
+
+        return [app activateWithOptions:NSApplicationActivateAllWindows] ? 1 : 0;
+    }
+}
+
+int activateWindowByBundleId(const char *bundleId) {
+    @autoreleasepool {
+        NSString *bundleIdentifier = [NSString stringWithUTF8String:bundleId];
+        NSArray<NSRunningApplication *> *apps = [NSRunningApplication runningApplicationsWithBundleIdentifier:bundleIdentifier];
+        if ([apps count] == 0) {
+            return 0;
+        }
+
+        return [[apps firstObject] activateWithOptions:NSApplicationActivateAllWindows] ? 1 : 0;
+    }
+}
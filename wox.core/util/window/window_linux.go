@@ -1,7 +1,12 @@
 package window
 
-import "errors"
-import "image"
+import (
+	"errors"
+	"image"
+	"os/exec"
+	"strconv"
+	"strings"
+)
 
 func GetActiveWindowIcon() (image.Image, error) {
 	return nil, errors.New("not implemented")
@@ -14,3 +19,29 @@ func GetActiveWindowName() string {
 func GetActiveWindowPid() int {
 	return -1
 }
+
+// ActivateWindowByPid brings a window of the given process to front, requires wmctrl to be installed.
+// Returns false if wmctrl is not available or the process has no window
+func ActivateWindowByPid(pid int) bool {
+	output, err := exec.Command("wmctrl", "-lp").Output()
+	if err != nil {
+		return false
+	}
+
+	// line format: <window id> <desktop> <pid> <host> <title>
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] != strconv.Itoa(pid) {
+			continue
+		}
+
+		return exec.Command("wmctrl", "-i", "-a", fields[0]).Run() == nil
+	}
+
+	return false
+}
+
+// ActivateWindowByBundleId is only supported on macOS
+func ActivateWindowByBundleId(bundleId string) bool {
+	return false
+}
@@ -136,4 +136,34 @@ int getActiveWindowPid() {
     DWORD processId;
     GetWindowThreadProcessId(hwnd, &processId);
     return processId;
-}
\ No newline at end of file
+}
+
+typedef struct {
+    DWORD pid;
+    HWND hwnd;
+} findWindowParam;
+
+BOOL CALLBACK findWindowByPid(HWND hwnd, LPARAM lParam) {
+    findWindowParam *param = (findWindowParam*)lParam;
+    DWORD processId;
+    GetWindowThreadProcessId(hwnd, &processId);
+    // only top level visible windows without owner, E.g. skip tool windows and dialogs
+    if (processId == param->pid && IsWindowVisible(hwnd) && GetWindow(hwnd, GW_OWNER) == NULL) {
+        param->hwnd = hwnd;
+        return FALSE;
+    }
+    return TRUE;
+}
+
+int activateWindowByPid(DWORD pid) {
+    findWindowParam param = {pid, NULL};
+    EnumWindows(findWindowByPid, (LPARAM)&param);
+    if (!param.hwnd) {
+        return 0;
+    }
+
+    if (IsIconic(param.hwnd)) {
+        ShowWindow(param.hwnd, SW_RESTORE);
+    }
+    return SetForegroundWindow(param.hwnd) ? 1 : 0;
+}
@@ -9,6 +9,7 @@ package window
 char* getActiveWindowIcon(unsigned char **iconData, int *iconSize, int *width, int *height);
 char* getActiveWindowName();
 int getActiveWindowPid();
+int activateWindowByPid(DWORD pid);
 */
 import "C"
 import (
@@ -62,3 +63,13 @@ func GetActiveWindowPid() int {
 	pid := C.getActiveWindowPid()
 	return int(pid)
 }
+
+// ActivateWindowByPid brings the main window of the given process to front, returns false if the process has no visible window
+func ActivateWindowByPid(pid int) bool {
+	return C.activateWindowByPid(C.DWORD(pid)) == 1
+}
+
+// ActivateWindowByBundleId is only supported on macOS
+func ActivateWindowByBundleId(bundleId string) bool {
+	return false
+}
@@ -1,7 +1,7 @@
 import { logger } from "./logger"
 import path from "path"
 import { PluginAPI } from "./pluginAPI"
-import { Context, MapString, Plugin, PluginInitParams, Query, QueryEnv, RefreshableResult, Result, ResultAction, Selection } from "@wox-launcher/wox-plugin"
+import { CarriedContext, Context, MapString, Plugin, PluginInitParams, Query, QueryEnv, RefreshableResult, Result, ResultAction, Selection } from "@wox-launcher/wox-plugin"
 import { WebSocket } from "ws"
 import * as crypto from "crypto"
 import { AI } from "@wox-launcher/wox-plugin/types/ai"
@@ -39,8 +39,16 @@ export async function handleRequestFromWox(ctx: Context, request: PluginJsonRpcR
       return onDeepLink(ctx, request)
     case "onUnload":
       return onUnload(ctx, request)
+    case "onQuerySessionStart":
+      return onQuerySessionStart(ctx, request)
+    case "onQuerySessionEnd":
+      return onQuerySessionEnd(ctx, request)
     case "onLLMStream":
       return onLLMStream(ctx, request)
+    case "onHeadlessAction":
+      return onHeadlessAction(ctx, request)
+    case "onStartup":
+      return onStartup(ctx, request)
     default:
       logger.info(ctx, `unknown method handler: ${request.Method}`)
       throw new Error(`unknown method handler: ${request.Method}`)
@@ -105,7 +113,7 @@ async function initPlugin(ctx: Context, request: PluginJsonRpcRequest, ws: WebSo
   }
 
   const init = getMethod(ctx, request, "init")
-  const pluginApi = new PluginAPI(ws, request.PluginId, request.PluginName)
+  const pluginApi = new PluginAPI(ws, request.PluginId, request.PluginName, plugin.Actions)
   plugin.API = pluginApi
   return init(ctx, { API: pluginApi, PluginDirectory: request.Params.PluginDirectory } as PluginInitParams)
 }
@@ -164,6 +172,40 @@ async function onUnload(ctx: Context, request: PluginJsonRpcRequest) {
   await plugin.API.unloadCallbacks.get(callbackId)?.()
 }
 
+async function onQuerySessionStart(ctx: Context, request: PluginJsonRpcRequest) {
+  const plugin = pluginInstances.get(request.PluginId)
+  if (plugin === undefined || plugin === null) {
+    logger.error(ctx, `plugin not found: ${request.PluginName}, forget to load plugin?`)
+    throw new Error(`plugin not found: ${request.PluginName}, forget to load plugin?`)
+  }
+
+  const callbackId = request.Params.CallbackId
+  const callbackFunc = plugin.API.querySessionStartCallbacks.get(callbackId)
+  if (callbackFunc === undefined || callbackFunc === null) {
+    logger.error(ctx, `query session start callback not found: ${callbackId}`)
+    throw new Error(`query session start callback not found: ${callbackId}`)
+  }
+
+  await callbackFunc()
+}
+
+async function onQuerySessionEnd(ctx: Context, request: PluginJsonRpcRequest) {
+  const plugin = pluginInstances.get(request.PluginId)
+  if (plugin === undefined || plugin === null) {
+    logger.error(ctx, `plugin not found: ${request.PluginName}, forget to load plugin?`)
+    throw new Error(`plugin not found: ${request.PluginName}, forget to load plugin?`)
+  }
+
+  const callbackId = request.Params.CallbackId
+  const callbackFunc = plugin.API.querySessionEndCallbacks.get(callbackId)
+  if (callbackFunc === undefined || callbackFunc === null) {
+    logger.error(ctx, `query session end callback not found: ${callbackId}`)
+    throw new Error(`query session end callback not found: ${callbackId}`)
+  }
+
+  await callbackFunc()
+}
+
 async function onLLMStream(ctx: Context, request: PluginJsonRpcRequest) {
   const plugin = pluginInstances.get(request.PluginId)
   if (plugin === undefined || plugin === null) {
@@ -183,6 +225,42 @@ async function onLLMStream(ctx: Context, request: PluginJsonRpcRequest) {
   callbackFunc(<AI.ChatStreamDataType>streamType, data)
 }
 
+async function onHeadlessAction(ctx: Context, request: PluginJsonRpcRequest) {
+  const plugin = pluginInstances.get(request.PluginId)
+  if (plugin === undefined || plugin === null) {
+    logger.error(ctx, `plugin not found: ${request.PluginName}, forget to load plugin?`)
+    throw new Error(`plugin not found: ${request.PluginName}, forget to load plugin?`)
+  }
+
+  const callbackId = request.Params.CallbackId
+  const callbackFunc = plugin.API.headlessActionCallbacks.get(callbackId)
+  if (callbackFunc === undefined || callbackFunc === null) {
+    logger.error(ctx, `headless action callback not found: ${callbackId}`)
+    throw new Error(`headless action callback not found: ${callbackId}`)
+  }
+
+  // thrown error is sent back to Wox as error response, Wox reports it to the caller of the action
+  await callbackFunc(ctx, { ContextData: request.Params.ContextData })
+}
+
+async function onStartup(ctx: Context, request: PluginJsonRpcRequest) {
+  const plugin = pluginInstances.get(request.PluginId)
+  if (plugin === undefined || plugin === null) {
+    logger.error(ctx, `plugin not found: ${request.PluginName}, forget to load plugin?`)
+    throw new Error(`plugin not found: ${request.PluginName}, forget to load plugin?`)
+  }
+
+  const callbackId = request.Params.CallbackId
+  const callbackFunc = plugin.API.startupCallbacks.get(callbackId)
+  if (callbackFunc === undefined || callbackFunc === null) {
+    logger.error(ctx, `startup callback not found: ${callbackId}`)
+    throw new Error(`startup callback not found: ${callbackId}`)
+  }
+
+  // thrown error is sent back to Wox as error response, Wox logs it as warm up failure
+  await callbackFunc(ctx)
+}
+
 async function query(ctx: Context, request: PluginJsonRpcRequest) {
   const plugin = pluginInstances.get(request.PluginId)
   if (plugin === undefined || plugin === null) {
@@ -204,6 +282,8 @@ async function query(ctx: Context, request: PluginJsonRpcRequest) {
     Search: request.Params.Search,
     Selection: JSON.parse(request.Params.Selection) as Selection,
     Env: JSON.parse(request.Params.Env) as QueryEnv,
+    // carried context is a json string, "null" if there is none
+    CarriedContext: (JSON.parse(request.Params.CarriedContext || "null") as CarriedContext | null) ?? undefined,
     IsGlobalQuery: () => request.Params.Type === "input" && request.Params.TriggerKeyword === ""
   } as Query)
 
@@ -244,14 +324,15 @@ async function action(ctx: Context, request: PluginJsonRpcRequest) {
     throw new Error(`plugin not found: ${request.PluginName}, forget to load plugin?`)
   }
 
-  const pluginAction = plugin.Actions.get(request.Params.ActionId)
+  const pluginAction = plugin.Actions.get(request.Params.ActionId) ?? plugin.API.staticResultActions.get(request.Params.ActionId)
   if (pluginAction === undefined || pluginAction === null) {
     logger.error(ctx, `<${request.PluginName}> plugin action not found: ${request.PluginName}`)
     return
   }
 
   pluginAction({
-    ContextData: request.Params.ContextData
+    ContextData: request.Params.ContextData,
+    TriggerKeyword: request.Params.TriggerKeyword ?? ""
   })
   
   return
@@ -273,6 +354,7 @@ async function refresh(ctx: Context, request: PluginJsonRpcRequest) {
   const result = JSON.parse(request.Params.RefreshableResult) as RefreshableResultWithResultId
   const refreshableResult = {
     ...result,
+    TriggerKeyword: request.Params.TriggerKeyword ?? "",
     Actions: result.Actions.map(action => ({
       ...action,
       Action: plugin.Actions.get(action.Id)
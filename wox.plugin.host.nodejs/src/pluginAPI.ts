@@ -1,4 +1,4 @@
-import { ChangeQueryParam, Context, MapString, PublicAPI } from "@wox-launcher/wox-plugin"
+import { ChangeQueryParam, Context, DialogSpec, HeadlessAction, MapString, Permission, PickFileOptions, PickFolderOptions, PublicAPI, RefreshableResult, ResultAction, Selection, StaticResult, UICommand } from "@wox-launcher/wox-plugin"
 import { WebSocket } from "ws"
 import * as crypto from "crypto"
 import { waitingForResponse } from "./index"
@@ -7,27 +7,41 @@ import { logger } from "./logger"
 import { MetadataCommand, PluginSettingDefinitionItem } from "@wox-launcher/wox-plugin/types/setting"
 import { AI } from "@wox-launcher/wox-plugin/types/ai"
 import { PluginJsonRpcTypeRequest } from "./jsonrpc"
-import { PluginJsonRpcRequest } from "./types"
+import { PluginJsonRpcRequest, RefreshableResultWithResultId, ResultActionUI } from "./types"
 
 export class PluginAPI implements PublicAPI {
   ws: WebSocket
   pluginId: string
   pluginName: string
+  // action cache of plugin instance, actions of updated results are invoked by Wox like actions of query results
+  actions: Map<string, ResultAction["Action"]>
   settingChangeCallbacks: Map<string, (key: string, value: string) => void>
   getDynamicSettingCallbacks: Map<string, (key: string) => PluginSettingDefinitionItem>
   deepLinkCallbacks: Map<string, (params: MapString) => void>
   unloadCallbacks: Map<string, () => Promise<void>>
+  querySessionStartCallbacks: Map<string, () => Promise<void>>
+  querySessionEndCallbacks: Map<string, () => Promise<void>>
   llmStreamCallbacks: Map<string, AI.ChatStreamFunc>
+  headlessActionCallbacks: Map<string, HeadlessAction["Action"]>
+  startupCallbacks: Map<string, (ctx: Context) => Promise<void>>
+  // actions of static results, they are not cleared by queries like the action cache
+  staticResultActions: Map<string, ResultAction["Action"]>
 
-  constructor(ws: WebSocket, pluginId: string, pluginName: string) {
+  constructor(ws: WebSocket, pluginId: string, pluginName: string, actions: Map<string, ResultAction["Action"]>) {
     this.ws = ws
     this.pluginId = pluginId
     this.pluginName = pluginName
+    this.actions = actions
     this.settingChangeCallbacks = new Map<string, (key: string, value: string) => void>()
     this.getDynamicSettingCallbacks = new Map<string, (key: string) => PluginSettingDefinitionItem>()
     this.deepLinkCallbacks = new Map<string, (params: MapString) => void>()
     this.unloadCallbacks = new Map<string, () => Promise<void>>()
+    this.querySessionStartCallbacks = new Map<string, () => Promise<void>>()
+    this.querySessionEndCallbacks = new Map<string, () => Promise<void>>()
     this.llmStreamCallbacks = new Map<string, AI.ChatStreamFunc>()
+    this.headlessActionCallbacks = new Map<string, HeadlessAction["Action"]>()
+    this.startupCallbacks = new Map<string, (ctx: Context) => Promise<void>>()
+    this.staticResultActions = new Map<string, ResultAction["Action"]>()
   }
 
   async invokeMethod(ctx: Context, method: string, params: { [key: string]: string }): Promise<unknown> {
@@ -79,6 +93,14 @@ export class PluginAPI implements PublicAPI {
     await this.invokeMethod(ctx, "Notify", { message })
   }
 
+  async SetStatus(ctx: Context, text: string): Promise<void> {
+    await this.invokeMethod(ctx, "SetStatus", { text })
+  }
+
+  async ClearStatus(ctx: Context): Promise<void> {
+    await this.invokeMethod(ctx, "ClearStatus", {})
+  }
+
   async GetTranslation(ctx: Context, key: string): Promise<string> {
     return (await this.invokeMethod(ctx, "GetTranslation", { key })) as string
   }
@@ -115,6 +137,18 @@ export class PluginAPI implements PublicAPI {
     await this.invokeMethod(ctx, "OnUnload", { callbackId })
   }
 
+  async OnQuerySessionStart(ctx: Context, callback: () => Promise<void>): Promise<void> {
+    const callbackId = crypto.randomUUID()
+    this.querySessionStartCallbacks.set(callbackId, callback)
+    await this.invokeMethod(ctx, "OnQuerySessionStart", { callbackId })
+  }
+
+  async OnQuerySessionEnd(ctx: Context, callback: () => Promise<void>): Promise<void> {
+    const callbackId = crypto.randomUUID()
+    this.querySessionEndCallbacks.set(callbackId, callback)
+    await this.invokeMethod(ctx, "OnQuerySessionEnd", { callbackId })
+  }
+
   async RegisterQueryCommands(ctx: Context, commands: MetadataCommand[]): Promise<void> {
     await this.invokeMethod(ctx, "RegisterQueryCommands", { commands: JSON.stringify(commands) })
   }
@@ -124,4 +158,163 @@ export class PluginAPI implements PublicAPI {
     this.llmStreamCallbacks.set(callbackId, callback)
     await this.invokeMethod(ctx, "LLMStream", { callbackId, conversations: JSON.stringify(conversations) })
   }
+
+  async RegisterHeadlessAction(ctx: Context, action: HeadlessAction): Promise<void> {
+    const callbackId = crypto.randomUUID()
+    this.headlessActionCallbacks.set(callbackId, action.Action)
+    await this.invokeMethod(ctx, "RegisterHeadlessAction", { callbackId, name: action.Name, description: action.Description })
+  }
+
+  async RegisterStaticResults(ctx: Context, results: StaticResult[]): Promise<void> {
+    this.staticResultActions.clear()
+    results.forEach(staticResult => {
+      staticResult.Result.Actions?.forEach(action => {
+        if (action.Id === undefined || action.Id === null) {
+          action.Id = crypto.randomUUID()
+        }
+        this.staticResultActions.set(action.Id, action.Action)
+      })
+    })
+
+    // functions of results are omitted by JSON.stringify, actions are invoked by id
+    await this.invokeMethod(ctx, "RegisterStaticResults", { results: JSON.stringify(results) })
+  }
+
+  async FormatRelativeTime(ctx: Context, timestamp: number): Promise<string> {
+    return (await this.invokeMethod(ctx, "FormatRelativeTime", { timestamp: Math.trunc(timestamp).toString() })) as string
+  }
+
+  async OnStartup(ctx: Context, callback: (ctx: Context) => Promise<void>): Promise<void> {
+    const callbackId = crypto.randomUUID()
+    this.startupCallbacks.set(callbackId, callback)
+    await this.invokeMethod(ctx, "OnStartup", { callbackId })
+  }
+
+  async ReportInitError(ctx: Context, error: string): Promise<void> {
+    await this.invokeMethod(ctx, "ReportInitError", { error })
+  }
+
+  async UpdateResultScore(ctx: Context, resultId: string, score: number): Promise<boolean> {
+    return (await this.invokeMethod(ctx, "UpdateResultScore", { resultId, score: Math.trunc(score).toString() })) === "true"
+  }
+
+  async UpdateResult(ctx: Context, resultId: string, result: RefreshableResult): Promise<boolean> {
+    const actions = result.Actions ?? []
+    actions.forEach(action => {
+      if (action.Id === undefined || action.Id === null) {
+        action.Id = crypto.randomUUID()
+      }
+      this.actions.set(action.Id, action.Action)
+    })
+
+    const updated = await this.invokeMethod(ctx, "UpdateResult", {
+      result: JSON.stringify({
+        ResultId: resultId,
+        Title: result.Title,
+        SubTitle: result.SubTitle,
+        Icon: result.Icon,
+        Preview: result.Preview,
+        Tails: result.Tails,
+        ContextData: result.ContextData,
+        RefreshInterval: result.RefreshInterval,
+        Actions: actions.map(action => ({
+          Id: action.Id,
+          Name: action.Name,
+          Icon: action.Icon,
+          IsDefault: action.IsDefault,
+          PreventHideAfterAction: action.PreventHideAfterAction,
+          Hotkey: action.Hotkey
+        } as ResultActionUI))
+      } as RefreshableResultWithResultId)
+    })
+    return updated === "true"
+  }
+
+  async ExecuteUIBatch(ctx: Context, commands: UICommand[]): Promise<void> {
+    const error = (await this.invokeMethod(ctx, "ExecuteUIBatch", { commands: JSON.stringify(commands) })) as string
+    if (error) {
+      throw new Error(error)
+    }
+  }
+
+  async RequestPermission(ctx: Context, permission: Permission): Promise<void> {
+    // empty result means permission is granted, otherwise it's the reason
+    const reason = (await this.invokeMethod(ctx, "RequestPermission", { permission })) as string
+    if (reason) {
+      throw new Error(reason)
+    }
+  }
+
+  async SendToPlugin(ctx: Context, targetPluginId: string, payload: Selection, contextData?: string): Promise<void> {
+    const error = (await this.invokeMethod(ctx, "SendToPlugin", { targetPluginId, payload: JSON.stringify(payload), contextData: contextData ?? "" })) as string
+    if (error) {
+      throw new Error(error)
+    }
+  }
+
+  async CarryContext(ctx: Context, contextData: string): Promise<void> {
+    await this.invokeMethod(ctx, "CarryContext", { contextData })
+  }
+
+  async ReportQueryProgress(ctx: Context, progress: number, text: string): Promise<void> {
+    await this.invokeMethod(ctx, "ReportQueryProgress", { progress: Math.trunc(progress).toString(), text })
+  }
+
+  async PickFile(ctx: Context, options: PickFileOptions): Promise<string[]> {
+    // paths are returned as json array, empty if user cancelled or the picker can't be opened
+    const paths = (await this.invokeMethod(ctx, "PickFile", { options: JSON.stringify(options) })) as string
+    return paths ? (JSON.parse(paths) as string[]) : []
+  }
+
+  async PickFolder(ctx: Context, options: PickFolderOptions): Promise<string> {
+    const paths = (await this.invokeMethod(ctx, "PickFolder", { options: JSON.stringify(options) })) as string
+    return paths ? ((JSON.parse(paths) as string[])[0] ?? "") : ""
+  }
+
+  async ShowDialog(ctx: Context, spec: DialogSpec): Promise<MapString> {
+    // Error is "cancelled", "timeout" or the error message
+    const result = JSON.parse((await this.invokeMethod(ctx, "ShowDialog", { spec: JSON.stringify(spec) })) as string) as { Responses: MapString | null; Error: string }
+    if (result.Error) {
+      throw new Error(result.Error)
+    }
+    return result.Responses ?? {}
+  }
+
+  async StoreGet<T = unknown>(ctx: Context, key: string): Promise<T | undefined> {
+    // value is returned as json, empty string means key doesn't exist
+    const value = (await this.invokeMethod(ctx, "StoreGet", { key })) as string
+    return value ? (JSON.parse(value) as T) : undefined
+  }
+
+  async StoreSet(ctx: Context, key: string, value: unknown): Promise<void> {
+    const error = (await this.invokeMethod(ctx, "StoreSet", { key, value: JSON.stringify(value) })) as string
+    if (error) {
+      throw new Error(error)
+    }
+  }
+
+  async StoreDelete(ctx: Context, key: string): Promise<void> {
+    const error = (await this.invokeMethod(ctx, "StoreDelete", { key })) as string
+    if (error) {
+      throw new Error(error)
+    }
+  }
+
+  async SecretGet(ctx: Context, key: string): Promise<string> {
+    return (await this.invokeMethod(ctx, "SecretGet", { key })) as string
+  }
+
+  async SecretSet(ctx: Context, key: string, value: string): Promise<void> {
+    const error = (await this.invokeMethod(ctx, "SecretSet", { key, value })) as string
+    if (error) {
+      throw new Error(error)
+    }
+  }
+
+  async SecretDelete(ctx: Context, key: string): Promise<void> {
+    const error = (await this.invokeMethod(ctx, "SecretDelete", { key })) as string
+    if (error) {
+      throw new Error(error)
+    }
+  }
 }
@@ -12,6 +12,7 @@ from wox_plugin import (
     RefreshableResult,
     PluginInitParams,
     ActionContext,
+    HeadlessActionContext,
 )
 from .plugin_manager import plugin_instances, PluginInstance
 from .plugin_api import PluginAPI
@@ -38,6 +39,14 @@ async def handle_request_from_wox(ctx: Context, request: Dict[str, Any], ws: web
         return await refresh(ctx, request)
     elif method == "unloadPlugin":
         return await unload_plugin(ctx, request)
+    elif method == "onHeadlessAction":
+        return await on_headless_action(ctx, request)
+    elif method == "onStartup":
+        return await on_startup(ctx, request)
+    elif method == "onQuerySessionStart":
+        return await on_query_session(ctx, request, is_start=True)
+    elif method == "onQuerySessionEnd":
+        return await on_query_session(ctx, request, is_start=False)
     else:
         await logger.info(ctx.get_trace_id(), f"unknown method handler: {method}")
         raise Exception(f"unknown method handler: {method}")
@@ -127,7 +136,7 @@ async def init_plugin(ctx: Context, request: Dict[str, Any], ws: websockets.asyn
 
     try:
         # Create plugin API instance
-        api = PluginAPI(ws, plugin_id, plugin_name)
+        api = PluginAPI(ws, plugin_id, plugin_name, plugin_instance.actions)
         plugin_instance.api = api
         params: Dict[str, str] = request.get("Params", {})
         plugin_directory: str = params.get("PluginDirectory", "")
@@ -227,12 +236,15 @@ async def action(ctx: Context, request: Dict[str, Any]) -> None:
         params: Dict[str, str] = request.get("Params", {})
         action_id = params.get("ActionId", "")
         context_data = params.get("ContextData", "")
+        trigger_keyword = params.get("TriggerKeyword", "")
 
-        # Get action from cache
+        # Get action from cache, fallback to actions of static results
         action_func = plugin_instance.actions.get(action_id)
+        if not action_func and isinstance(plugin_instance.api, PluginAPI):
+            action_func = plugin_instance.api.static_result_actions.get(action_id)
         if action_func:
             # Handle both coroutine and regular functions
-            result = action_func(ActionContext(context_data=context_data))
+            result = action_func(ActionContext(context_data=context_data, trigger_keyword=trigger_keyword))
             if asyncio.iscoroutine(result):
                 asyncio.create_task(result)
 
@@ -245,6 +257,87 @@ async def action(ctx: Context, request: Dict[str, Any]) -> None:
         raise e
 
 
+async def on_headless_action(ctx: Context, request: Dict[str, Any]) -> None:
+    """Handle headless action request, it waits for the action so errors are reported back to Wox"""
+    plugin_id = request.get("PluginId", "")
+    plugin_name = request.get("PluginName", "")
+    plugin_instance = plugin_instances.get(plugin_id)
+    if not plugin_instance or not isinstance(plugin_instance.api, PluginAPI):
+        raise Exception(f"plugin not found: {plugin_name}, forget to load plugin?")
+
+    params: Dict[str, str] = request.get("Params", {})
+    callback_id = params.get("CallbackId", "")
+    callback = plugin_instance.api.headless_action_callbacks.get(callback_id)
+    if not callback:
+        raise Exception(f"headless action callback not found: {callback_id}")
+
+    try:
+        result = callback(HeadlessActionContext(context_data=params.get("ContextData", "")))
+        if asyncio.iscoroutine(result):
+            await result
+    except Exception as e:
+        error_stack = traceback.format_exc()
+        await logger.error(
+            ctx.get_trace_id(),
+            f"<{plugin_name}> headless action failed: {str(e)}\nStack trace:\n{error_stack}",
+        )
+        raise e
+
+
+async def on_startup(ctx: Context, request: Dict[str, Any]) -> None:
+    """Handle startup request, it waits for the callback so errors are reported back to Wox"""
+    plugin_id = request.get("PluginId", "")
+    plugin_name = request.get("PluginName", "")
+    plugin_instance = plugin_instances.get(plugin_id)
+    if not plugin_instance or not isinstance(plugin_instance.api, PluginAPI):
+        raise Exception(f"plugin not found: {plugin_name}, forget to load plugin?")
+
+    params: Dict[str, str] = request.get("Params", {})
+    callback_id = params.get("CallbackId", "")
+    callback = plugin_instance.api.startup_callbacks.get(callback_id)
+    if not callback:
+        raise Exception(f"startup callback not found: {callback_id}")
+
+    try:
+        await callback(ctx)
+    except Exception as e:
+        error_stack = traceback.format_exc()
+        await logger.error(
+            ctx.get_trace_id(),
+            f"<{plugin_name}> startup failed: {str(e)}\nStack trace:\n{error_stack}",
+        )
+        raise e
+
+
+async def on_query_session(ctx: Context, request: Dict[str, Any], is_start: bool) -> None:
+    """Handle query session start or end request"""
+    plugin_id = request.get("PluginId", "")
+    plugin_name = request.get("PluginName", "")
+    plugin_instance = plugin_instances.get(plugin_id)
+    if not plugin_instance or not isinstance(plugin_instance.api, PluginAPI):
+        raise Exception(f"plugin not found: {plugin_name}, forget to load plugin?")
+
+    session_event = "start" if is_start else "end"
+    params: Dict[str, str] = request.get("Params", {})
+    callback_id = params.get("CallbackId", "")
+    callbacks = plugin_instance.api.query_session_start_callbacks if is_start else plugin_instance.api.query_session_end_callbacks
+    callback = callbacks.get(callback_id)
+    if not callback:
+        raise Exception(f"query session {session_event} callback not found: {callback_id}")
+
+    try:
+        result = callback()
+        if asyncio.iscoroutine(result):
+            await result
+    except Exception as e:
+        error_stack = traceback.format_exc()
+        await logger.error(
+            ctx.get_trace_id(),
+            f"<{plugin_name}> query session {session_event} failed: {str(e)}\nStack trace:\n{error_stack}",
+        )
+        raise e
+
+
 async def refresh(ctx: Context, request: Dict[str, Any]) -> dict[str, Any]:
     """Handle refresh request"""
     plugin_id = request.get("PluginId", "")
@@ -260,6 +353,7 @@ async def refresh(ctx: Context, request: Dict[str, Any]) -> dict[str, Any]:
 
         # Convert dict to RefreshableResult object
         refreshable_result = RefreshableResult.from_json(json.dumps(refreshable_result_dict))
+        refreshable_result.trigger_keyword = params.get("TriggerKeyword", "")
 
         # replace action with cached action
         for action in refreshable_result.actions:
@@ -1,7 +1,7 @@
 import asyncio
 import json
 import uuid
-from typing import Any, Dict, Callable
+from typing import Any, Awaitable, Dict, Callable
 import websockets
 from . import logger
 from wox_plugin import (
@@ -12,21 +12,48 @@ from wox_plugin import (
     Conversation,
     AIModel,
     ChatStreamCallback,
+    HeadlessAction,
+    HeadlessActionContext,
+    DialogSpec,
+    DialogError,
+    RefreshableResult,
+    ActionContext,
+    UICommand,
+    Permission,
+    PermissionDeniedError,
+    Selection,
+    StaticResult,
+    PickFileOptions,
+    PickFolderOptions,
 )
 from .constants import PLUGIN_JSONRPC_TYPE_REQUEST
 from .plugin_manager import waiting_for_response
 
 
 class PluginAPI(PublicAPI):
-    def __init__(self, ws: websockets.asyncio.server.ServerConnection, plugin_id: str, plugin_name: str):
+    def __init__(
+        self,
+        ws: websockets.asyncio.server.ServerConnection,
+        plugin_id: str,
+        plugin_name: str,
+        actions: Dict[str, Callable[[ActionContext], Awaitable[None]]],
+    ):
         self.ws = ws
         self.plugin_id = plugin_id
         self.plugin_name = plugin_name
+        # action cache of plugin instance, actions of updated results are invoked by Wox like actions of query results
+        self.actions = actions
         self.setting_change_callbacks: Dict[str, Callable[[str, str], None]] = {}
         self.get_dynamic_setting_callbacks: Dict[str, Callable[[str], str]] = {}
         self.deep_link_callbacks: Dict[str, Callable[[Dict[str, str]], None]] = {}
         self.unload_callbacks: Dict[str, Callable[[], None]] = {}
+        self.query_session_start_callbacks: Dict[str, Callable[[], Any]] = {}
+        self.query_session_end_callbacks: Dict[str, Callable[[], Any]] = {}
         self.llm_stream_callbacks: Dict[str, ChatStreamCallback] = {}
+        self.headless_action_callbacks: Dict[str, Callable[[HeadlessActionContext], Any]] = {}
+        self.startup_callbacks: Dict[str, Callable[[Context], Awaitable[None]]] = {}
+        # actions of static results, they are not cleared by queries like the action cache
+        self.static_result_actions: Dict[str, Callable[[ActionContext], Awaitable[None]]] = {}
 
     async def invoke_method(self, ctx: Context, method: str, params: Dict[str, Any]) -> Any:
         """Invoke a method on Wox"""
@@ -82,6 +109,14 @@ class PluginAPI(PublicAPI):
         """Show a notification message"""
         await self.invoke_method(ctx, "Notify", {"message": message})
 
+    async def set_status(self, ctx: Context, text: str) -> None:
+        """Show a status line until it's cleared"""
+        await self.invoke_method(ctx, "SetStatus", {"text": text})
+
+    async def clear_status(self, ctx: Context) -> None:
+        """Clear the status line"""
+        await self.invoke_method(ctx, "ClearStatus", {})
+
     async def log(self, ctx: Context, level: str, msg: str) -> None:
         """Write log"""
         await self.invoke_method(ctx, "Log", {"level": level, "message": msg})
@@ -128,6 +163,18 @@ class PluginAPI(PublicAPI):
         self.unload_callbacks[callback_id] = callback
         await self.invoke_method(ctx, "OnUnload", {"callbackId": callback_id})
 
+    async def on_query_session_start(self, ctx: Context, callback: Callable[[], Any]) -> None:
+        """Register query session start callback"""
+        callback_id = str(uuid.uuid4())
+        self.query_session_start_callbacks[callback_id] = callback
+        await self.invoke_method(ctx, "OnQuerySessionStart", {"callbackId": callback_id})
+
+    async def on_query_session_end(self, ctx: Context, callback: Callable[[], Any]) -> None:
+        """Register query session end callback"""
+        callback_id = str(uuid.uuid4())
+        self.query_session_end_callbacks[callback_id] = callback
+        await self.invoke_method(ctx, "OnQuerySessionEnd", {"callbackId": callback_id})
+
     async def register_query_commands(self, ctx: Context, commands: list[MetadataCommand]) -> None:
         """Register query commands"""
         await self.invoke_method(
@@ -154,3 +201,152 @@ class PluginAPI(PublicAPI):
                 "conversations": json.dumps([conv.__dict__ for conv in conversations]),
             },
         )
+
+    async def register_headless_action(self, ctx: Context, action: HeadlessAction) -> None:
+        """Register a headless action"""
+        callback_id = str(uuid.uuid4())
+        self.headless_action_callbacks[callback_id] = action.action
+        await self.invoke_method(
+            ctx,
+            "RegisterHeadlessAction",
+            {"callbackId": callback_id, "name": action.name, "description": action.description},
+        )
+
+    async def register_static_results(self, ctx: Context, results: list[StaticResult]) -> None:
+        """Replace static results of plugin"""
+        self.static_result_actions.clear()
+        for static_result in results:
+            for action in static_result.result.actions:
+                if not action.id:
+                    action.id = str(uuid.uuid4())
+                if action.action:
+                    self.static_result_actions[action.id] = action.action
+
+        await self.invoke_method(
+            ctx,
+            "RegisterStaticResults",
+            {"results": json.dumps([json.loads(static_result.to_json()) for static_result in results])},
+        )
+
+    async def format_relative_time(self, ctx: Context, timestamp: int) -> str:
+        """Format a unix timestamp in milliseconds relative to now"""
+        result = await self.invoke_method(ctx, "FormatRelativeTime", {"timestamp": str(int(timestamp))})
+        return str(result) if result is not None else ""
+
+    async def on_startup(self, ctx: Context, callback: Callable[[Context], Awaitable[None]]) -> None:
+        """Register startup callback"""
+        callback_id = str(uuid.uuid4())
+        self.startup_callbacks[callback_id] = callback
+        await self.invoke_method(ctx, "OnStartup", {"callbackId": callback_id})
+
+    async def report_init_error(self, ctx: Context, error: str) -> None:
+        """Report init error, empty error clears it"""
+        await self.invoke_method(ctx, "ReportInitError", {"error": error})
+
+    async def update_result_score(self, ctx: Context, result_id: str, score: int) -> bool:
+        """Update score of a result in current query"""
+        result = await self.invoke_method(ctx, "UpdateResultScore", {"resultId": result_id, "score": str(int(score))})
+        return result == "true"
+
+    async def update_result(self, ctx: Context, result_id: str, result: RefreshableResult) -> bool:
+        """Push a new state of a result in current query"""
+        for action in result.actions:
+            if not action.id:
+                action.id = str(uuid.uuid4())
+            if action.action:
+                self.actions[action.id] = action.action
+
+        result_dict = json.loads(result.to_json())
+        result_dict["ResultId"] = result_id
+        updated = await self.invoke_method(ctx, "UpdateResult", {"result": json.dumps(result_dict)})
+        return updated == "true"
+
+    async def execute_ui_batch(self, ctx: Context, commands: list[UICommand]) -> None:
+        """Apply multiple UI commands in order"""
+        error = await self.invoke_method(
+            ctx,
+            "ExecuteUIBatch",
+            {"commands": json.dumps([json.loads(command.to_json()) for command in commands])},
+        )
+        if error:
+            raise RuntimeError(error)
+
+    async def request_permission(self, ctx: Context, permission: Permission) -> None:
+        """Check permission before a sensitive operation"""
+        # empty result means permission is granted, otherwise it's the reason
+        reason = await self.invoke_method(ctx, "RequestPermission", {"permission": Permission(permission).value})
+        if reason:
+            raise PermissionDeniedError(reason)
+
+    async def send_to_plugin(self, ctx: Context, target_plugin_id: str, payload: Selection, context_data: str = "") -> None:
+        """Hand payload to another plugin"""
+        error = await self.invoke_method(
+            ctx,
+            "SendToPlugin",
+            {"targetPluginId": target_plugin_id, "payload": payload.to_json(), "contextData": context_data},
+        )
+        if error:
+            raise RuntimeError(error)
+
+    async def carry_context(self, ctx: Context, context_data: str) -> None:
+        """Make context data available to the next input queries"""
+        await self.invoke_method(ctx, "CarryContext", {"contextData": context_data})
+
+    async def report_query_progress(self, ctx: Context, progress: int, text: str) -> None:
+        """Show progress of the running query"""
+        await self.invoke_method(ctx, "ReportQueryProgress", {"progress": str(int(progress)), "text": text})
+
+    async def pick_file(self, ctx: Context, options: PickFileOptions) -> list[str]:
+        """Open the native file picker"""
+        # paths are returned as json array, empty if user cancelled or the picker can't be opened
+        result = await self.invoke_method(ctx, "PickFile", {"options": options.to_json()})
+        return json.loads(str(result)) if result else []
+
+    async def pick_folder(self, ctx: Context, options: PickFolderOptions) -> str:
+        """Open the native folder picker"""
+        result = await self.invoke_method(ctx, "PickFolder", {"options": options.to_json()})
+        paths = json.loads(str(result)) if result else []
+        return paths[0] if paths else ""
+
+    async def show_dialog(self, ctx: Context, spec: DialogSpec) -> Dict[str, str]:
+        """Show a dialog and wait until user submits it"""
+        result = json.loads(str(await self.invoke_method(ctx, "ShowDialog", {"spec": spec.to_json()})))
+        # Error is "cancelled", "timeout" or the error message
+        if result.get("Error"):
+            raise DialogError(result["Error"])
+        return result.get("Responses") or {}
+
+    async def store_get(self, ctx: Context, key: str) -> Any:
+        """Get a value from the key-value store of plugin"""
+        # value is returned as json, empty string means key doesn't exist
+        result = await self.invoke_method(ctx, "StoreGet", {"key": key})
+        return json.loads(str(result)) if result else None
+
+    async def store_set(self, ctx: Context, key: str, value: Any) -> None:
+        """Save a value to the key-value store of plugin"""
+        error = await self.invoke_method(ctx, "StoreSet", {"key": key, "value": json.dumps(value)})
+        if error:
+            raise RuntimeError(error)
+
+    async def store_delete(self, ctx: Context, key: str) -> None:
+        """Delete a value from the key-value store of plugin"""
+        error = await self.invoke_method(ctx, "StoreDelete", {"key": key})
+        if error:
+            raise RuntimeError(error)
+
+    async def secret_get(self, ctx: Context, key: str) -> str:
+        """Get a secret of plugin"""
+        result = await self.invoke_method(ctx, "SecretGet", {"key": key})
+        return str(result) if result is not None else ""
+
+    async def secret_set(self, ctx: Context, key: str, value: str) -> None:
+        """Save a secret of plugin"""
+        error = await self.invoke_method(ctx, "SecretSet", {"key": key, "value": value})
+        if error:
+            raise RuntimeError(error)
+
+    async def secret_delete(self, ctx: Context, key: str) -> None:
+        """Delete a secret of plugin"""
+        error = await self.invoke_method(ctx, "SecretDelete", {"key": key})
+        if error:
+            raise RuntimeError(error)
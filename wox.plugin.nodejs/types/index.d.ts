@@ -79,12 +79,28 @@ export interface Query {
    */
   Env: QueryEnv
 
+  /**
+   * Context data stashed by an action with CarryContext, see CarriedContext
+   *
+   * NOTE: Only available when query type is input and an action carried context
+   */
+  CarriedContext?: CarriedContext
+
   /**
    * Whether current query is global query
    */
   IsGlobalQuery(): boolean
 }
 
+/**
+ * Context data stashed by an action with CarryContext for the queries user types next (E.g. pick a repository, then search issues within it).
+ * It's dropped when user clears the query box, Wox is hidden, another context is carried or no query is made within 5 minutes
+ */
+export interface CarriedContext {
+  SourcePluginId: string
+  ContextData: string
+}
+
 export interface Result {
   Id?: string
   Title: string
@@ -120,6 +136,10 @@ export interface RefreshableResult {
   ContextData: string
   RefreshInterval: number
   Actions: ResultAction[]
+  /**
+   * Trigger keyword of the query which returned this result, empty for global queries. Changing it has no effect
+   */
+  TriggerKeyword?: string
 }
 
 export interface ResultAction {
@@ -150,6 +170,10 @@ export interface ResultAction {
 
 export interface ActionContext {
   ContextData: string
+  /**
+   * Trigger keyword of the query which returned this result (E.g. "gh" or "github" for a plugin with both), empty for global queries
+   */
+  TriggerKeyword: string
 }
 
 export interface PluginInitParams {
@@ -184,6 +208,17 @@ export interface PublicAPI {
    */
   Notify: (ctx: Context, message: string) => Promise<void>
 
+  /**
+   * Show a status line (E.g. "Syncing…") at the bottom of Wox window until ClearStatus is called, text supports i18n.
+   * Setting it again replaces the previous status of plugin
+   */
+  SetStatus: (ctx: Context, text: string) => Promise<void>
+
+  /**
+   * Clear the status line set by SetStatus
+   */
+  ClearStatus: (ctx: Context) => Promise<void>
+
   /**
    * Write log
    */
@@ -228,6 +263,16 @@ export interface PublicAPI {
    */
   OnUnload: (ctx: Context, callback: () => Promise<void>) => Promise<void>
 
+  /**
+   * Register a callback called when Wox window is shown and a new query session starts, E.g. to open a connection used by queries
+   */
+  OnQuerySessionStart: (ctx: Context, callback: () => Promise<void>) => Promise<void>
+
+  /**
+   * Register a callback called when Wox window is hidden and the query session ends, E.g. to release resources opened on session start
+   */
+  OnQuerySessionEnd: (ctx: Context, callback: () => Promise<void>) => Promise<void>
+
   /**
    * Register query commands
    */
@@ -237,6 +282,262 @@ export interface PublicAPI {
    * Chat using LLM
    */
   LLMStream: (ctx: Context, conversations: AI.Conversation[], callback: AI.ChatStreamFunc) => Promise<void>
+
+  /**
+   * Register a named action which runs without a query (E.g. "toggle-dark-mode"), invoked by a query hotkey
+   * or /action/headless endpoint as "<plugin id or name>:<action name>"
+   */
+  RegisterHeadlessAction: (ctx: Context, action: HeadlessAction) => Promise<void>
+
+  /**
+   * Replace the static results of the plugin, they are matched and shown by Wox without invoking the plugin.
+   * Changes take effect from the next query
+   */
+  RegisterStaticResults: (ctx: Context, results: StaticResult[]) => Promise<void>
+
+  /**
+   * Format a unix timestamp in milliseconds relative to now in the language of Wox (E.g. "5m ago", "in 2h", "just now").
+   * Empty string is returned for 0
+   */
+  FormatRelativeTime: (ctx: Context, timestamp: number) => Promise<string>
+
+  /**
+   * Register a callback to warm up the plugin (E.g. build an index, authenticate) before the first query.
+   * It's called in background after plugin is initialized with a timeout, thrown errors are logged
+   */
+  OnStartup: (ctx: Context, callback: (ctx: Context) => Promise<void>) => Promise<void>
+
+  /**
+   * Tell Wox plugin can't work (E.g. bad config, missing dependency). Plugin is skipped by global queries and queries triggered
+   * by its keyword show the error. Empty error clears it once plugin recovered
+   */
+  ReportInitError: (ctx: Context, error: string) => Promise<void>
+
+  /**
+   * Change score of a result returned in current query without rebuilding it, UI re-sorts the results.
+   * Returns false if the update is dropped because query has changed
+   */
+  UpdateResultScore: (ctx: Context, resultId: string, score: number) => Promise<boolean>
+
+  /**
+   * Push a new state of a result returned in current query to UI, E.g. when a subscription receives new data.
+   * Result must have an explicit id set by plugin. Returns false if the update is dropped because query has changed
+   */
+  UpdateResult: (ctx: Context, resultId: string, result: RefreshableResult) => Promise<boolean>
+
+  /**
+   * Apply multiple UI commands in order as one request, E.g. show a message, then change query and hide app.
+   * If a command fails, following commands are skipped and an error is thrown, commands applied before are not rolled back
+   */
+  ExecuteUIBatch: (ctx: Context, commands: UICommand[]) => Promise<void>
+
+  /**
+   * Must be called before a sensitive operation (E.g. reading clipboard), the permission must be declared in plugin.json.
+   * Throws an error with the reason if permission is denied, the operation should be skipped then
+   */
+  RequestPermission: (ctx: Context, permission: Permission) => Promise<void>
+
+  /**
+   * Hand payload to another plugin (E.g. pipe a file result into a compression plugin), it's delivered to target plugin as a selection query.
+   * Empty targetPluginId means any plugin supports selection query. contextData is passed to target plugin as is
+   */
+  SendToPlugin: (ctx: Context, targetPluginId: string, payload: Selection, contextData?: string) => Promise<void>
+
+  /**
+   * Make contextData available to the next input queries as Query.CarriedContext, see CarriedContext
+   */
+  CarryContext: (ctx: Context, contextData: string) => Promise<void>
+
+  /**
+   * Show progress of the running query in UI before results arrive (E.g. "Searching… 40%"), text supports i18n.
+   * Progress is 0-100, negative means indeterminate. Updates are rate limited
+   */
+  ReportQueryProgress: (ctx: Context, progress: number, text: string) => Promise<void>
+
+  /**
+   * Open the native file picker and return paths of chosen files, empty if user cancelled or the picker can't be opened
+   */
+  PickFile: (ctx: Context, options: PickFileOptions) => Promise<string[]>
+
+  /**
+   * Open the native folder picker and return path of the chosen folder, empty if user cancelled or the picker can't be opened
+   */
+  PickFolder: (ctx: Context, options: PickFolderOptions) => Promise<string>
+
+  /**
+   * Show a modal form and wait until user submits it, texts support i18n. Returns the value of each field by key,
+   * and the key of the pressed button as "$button" if spec has buttons.
+   * Throws an error with message "cancelled" if user cancelled the dialog, "timeout" if user didn't respond in time
+   */
+  ShowDialog: (ctx: Context, spec: DialogSpec) => Promise<MapString>
+
+  /**
+   * Get a value from the persistent key-value store of plugin, undefined is returned if key doesn't exist
+   */
+  StoreGet: <T = unknown>(ctx: Context, key: string) => Promise<T | undefined>
+
+  /**
+   * Save a json serializable value to the persistent key-value store of plugin, saved to disk immediately.
+   * Throws an error if the value can't be saved (E.g. store exceeds the size limit)
+   */
+  StoreSet: (ctx: Context, key: string, value: unknown) => Promise<void>
+
+  /**
+   * Delete a value from the persistent key-value store of plugin
+   */
+  StoreDelete: (ctx: Context, key: string) => Promise<void>
+
+  /**
+   * Get a secret (E.g. api token) of plugin from the OS keyring, empty string is returned if key doesn't exist
+   */
+  SecretGet: (ctx: Context, key: string) => Promise<string>
+
+  /**
+   * Save a secret of plugin to the OS keyring, or an encrypted file if keyring is not available
+   */
+  SecretSet: (ctx: Context, key: string, value: string) => Promise<void>
+
+  /**
+   * Delete a secret of plugin
+   */
+  SecretDelete: (ctx: Context, key: string) => Promise<void>
+}
+
+export interface PickFileOptions {
+  /**
+   * Title of the picker, supports i18n
+   */
+  Title?: string
+  /**
+   * Directory the picker starts in, empty means the last directory the OS remembers
+   */
+  DefaultDirectory?: string
+  /**
+   * Allowed file extensions without dot, E.g. ["png", "jpg"]. Empty means all files
+   */
+  Extensions?: string[]
+  /**
+   * If true, user can pick several files
+   */
+  AllowMultiple?: boolean
+}
+
+export interface PickFolderOptions {
+  /**
+   * Title of the picker, supports i18n
+   */
+  Title?: string
+  /**
+   * Directory the picker starts in, empty means the last directory the OS remembers
+   */
+  DefaultDirectory?: string
+}
+
+export type Permission = "clipboard" | "selection" | "network"
+
+export type UICommandType = "ChangeQuery" | "HideApp" | "ShowApp" | "ShowToolbarMsg"
+
+export interface UICommand {
+  Type: UICommandType
+  /**
+   * Only used by ChangeQuery
+   */
+  Query?: ChangeQueryParam
+  /**
+   * Only used by ShowApp
+   */
+  ShowContext?: {
+    // Select all text of query box after app is shown
+    SelectAll: boolean
+  }
+  /**
+   * Only used by ShowToolbarMsg
+   */
+  Msg?: {
+    // Text supports i18n
+    Text: string
+    // 0 means display forever
+    DisplaySeconds?: number
+  }
+}
+
+export type DialogFieldType = "text" | "password" | "select" | "checkbox"
+
+export interface DialogSpec {
+  Title: string
+  Description?: string
+  Fields: DialogField[]
+  /**
+   * Empty means a submit and a cancel button
+   */
+  Buttons?: DialogButton[]
+  /**
+   * 0 means 5 minutes, the dialog is closed when it times out
+   */
+  TimeoutSeconds?: number
+}
+
+export interface DialogField {
+  Key: string
+  Type: DialogFieldType
+  Label: string
+  Placeholder?: string
+  /**
+   * "true" or "false" for checkbox
+   */
+  DefaultValue?: string
+  /**
+   * Only used by select
+   */
+  Options?: DialogFieldOption[]
+  /**
+   * UI doesn't submit until required fields are filled, checkbox is never missing
+   */
+  Required?: boolean
+}
+
+export interface DialogFieldOption {
+  Label: string
+  Value: string
+}
+
+export interface DialogButton {
+  Key: string
+  Label: string
+  /**
+   * If true, pressing the button cancels the dialog without checking required fields, same as Escape
+   */
+  IsCancel?: boolean
+}
+
+/**
+ * Always available result (E.g. quit, lock), Wox matches it against input queries itself and shows it instantly, without invoking the plugin
+ */
+export interface StaticResult {
+  Result: Result
+  /**
+   * Texts matched against query search, Result.Title is used if empty
+   */
+  MatchTexts?: string[]
+}
+
+export interface HeadlessAction {
+  /**
+   * Unique within plugin, letters, digits, "-" and "_"
+   */
+  Name: string
+  Description: string
+  /**
+   * Thrown error is reported to caller, hotkeys show it as a notification
+   */
+  Action: (ctx: Context, actionContext: HeadlessActionContext) => Promise<void>
+}
+
+export interface HeadlessActionContext {
+  /**
+   * Data passed as is by caller: the query of a query hotkey or "contextData" of /action/headless request
+   */
+  ContextData: string
 }
 
 export type WoxImageType = "absolute" | "relative" | "base64" | "svg" | "url" | "emoji" | "lottie"
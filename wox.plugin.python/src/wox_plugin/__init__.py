@@ -17,6 +17,7 @@ from .models.query import (
     QueryType,
     SelectionType,
     MetadataCommand,
+    CarriedContext,
 )
 from .models.result import (
     Result,
@@ -25,6 +26,9 @@ from .models.result import (
     ActionContext,
     RefreshableResult,
     ResultTailType,
+    HeadlessAction,
+    HeadlessActionContext,
+    StaticResult,
 )
 
 from .models.ai import (
@@ -33,6 +37,18 @@ from .models.ai import (
     ConversationRole,
     ChatStreamDataType,
 )
+from .models.dialog import (
+    DialogSpec,
+    DialogField,
+    DialogFieldType,
+    DialogFieldOption,
+    DialogButton,
+    DialogError,
+    DIALOG_BUTTON_RESPONSE_KEY,
+)
+from .models.ui import UICommand, UICommandType
+from .models.permission import Permission, PermissionDeniedError
+from .models.picker import PickFileOptions, PickFolderOptions
 from .models.image import WoxImage, WoxImageType
 from .models.preview import WoxPreview, WoxPreviewType, WoxPreviewScrollPosition
 
@@ -47,6 +63,7 @@ __all__: List[str] = [
     "Context",
     "Query",
     "QueryEnv",
+    "CarriedContext",
     "Selection",
     "Result",
     "WoxImage",
@@ -55,6 +72,9 @@ __all__: List[str] = [
     "ResultAction",
     "ActionContext",
     "RefreshableResult",
+    "HeadlessAction",
+    "HeadlessActionContext",
+    "StaticResult",
     "MetadataCommand",
     "PluginSettingDefinitionItem",
     "PluginSettingValueStyle",
@@ -75,6 +95,23 @@ __all__: List[str] = [
     "InvalidQueryError",
     "PluginInitError",
     "APIError",
+    # Dialog
+    "DialogSpec",
+    "DialogField",
+    "DialogFieldType",
+    "DialogFieldOption",
+    "DialogButton",
+    "DialogError",
+    "DIALOG_BUTTON_RESPONSE_KEY",
+    # UI
+    "UICommand",
+    "UICommandType",
+    # Permission
+    "Permission",
+    "PermissionDeniedError",
+    # Picker
+    "PickFileOptions",
+    "PickFolderOptions",
     # Image
     "WoxImage",
     "WoxImageType",
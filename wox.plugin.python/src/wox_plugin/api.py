@@ -1,9 +1,14 @@
-from typing import Protocol, Callable, Dict, List
+from typing import Any, Protocol, Callable, Awaitable, Dict, List
 
 from .models.query import MetadataCommand
 from .models.context import Context
-from .models.query import ChangeQueryParam
+from .models.query import ChangeQueryParam, Selection
 from .models.ai import AIModel, Conversation, ChatStreamCallback
+from .models.result import HeadlessAction, RefreshableResult, StaticResult
+from .models.dialog import DialogSpec
+from .models.ui import UICommand
+from .models.permission import Permission
+from .models.picker import PickFileOptions, PickFolderOptions
 
 
 class PublicAPI(Protocol):
@@ -25,6 +30,17 @@ class PublicAPI(Protocol):
         """Show a notification message"""
         ...
 
+    async def set_status(self, ctx: Context, text: str) -> None:
+        """
+        Show a status line (E.g. "Syncing…") at the bottom of Wox window until clear_status is called, text supports i18n.
+        Setting it again replaces the previous status of plugin
+        """
+        ...
+
+    async def clear_status(self, ctx: Context) -> None:
+        """Clear the status line set by set_status"""
+        ...
+
     async def log(self, ctx: Context, level: str, msg: str) -> None:
         """Write log message"""
         ...
@@ -57,6 +73,14 @@ class PublicAPI(Protocol):
         """Register unload callback"""
         ...
 
+    async def on_query_session_start(self, ctx: Context, callback: Callable[[], Any]) -> None:
+        """Register a callback called when Wox window is shown and a new query session starts, E.g. to open a connection used by queries"""
+        ...
+
+    async def on_query_session_end(self, ctx: Context, callback: Callable[[], Any]) -> None:
+        """Register a callback called when Wox window is hidden and the query session ends, E.g. to release resources of the session"""
+        ...
+
     async def register_query_commands(self, ctx: Context, commands: List[MetadataCommand]) -> None:
         """Register query commands"""
         ...
@@ -81,3 +105,124 @@ class PublicAPI(Protocol):
                      - data: str, the stream content
         """
         ...
+
+    async def register_headless_action(self, ctx: Context, action: HeadlessAction) -> None:
+        """Register a named action which runs without a query, invoked by a query hotkey or /action/headless endpoint"""
+        ...
+
+    async def register_static_results(self, ctx: Context, results: List[StaticResult]) -> None:
+        """
+        Replace the static results of the plugin, they are matched and shown by Wox without invoking the plugin.
+        Changes take effect from the next query
+        """
+        ...
+
+    async def format_relative_time(self, ctx: Context, timestamp: int) -> str:
+        """
+        Format a unix timestamp in milliseconds relative to now in the language of Wox (E.g. "5m ago", "in 2h", "just now").
+        Empty string is returned for 0
+        """
+        ...
+
+    async def on_startup(self, ctx: Context, callback: Callable[[Context], Awaitable[None]]) -> None:
+        """
+        Register a callback to warm up the plugin (E.g. build an index, authenticate) before the first query.
+        It's called in background after plugin is initialized with a timeout, raised exceptions are logged
+        """
+        ...
+
+    async def report_init_error(self, ctx: Context, error: str) -> None:
+        """
+        Tell Wox plugin can't work (E.g. bad config, missing dependency). Plugin is skipped by global queries and queries
+        triggered by its keyword show the error. Empty error clears it once plugin recovered
+        """
+        ...
+
+    async def update_result_score(self, ctx: Context, result_id: str, score: int) -> bool:
+        """
+        Change score of a result returned in current query without rebuilding it, UI re-sorts the results.
+        Returns False if the update is dropped because query has changed
+        """
+        ...
+
+    async def update_result(self, ctx: Context, result_id: str, result: RefreshableResult) -> bool:
+        """
+        Push a new state of a result returned in current query to UI, E.g. when a subscription receives new data.
+        Result must have an explicit id set by plugin. Returns False if the update is dropped because query has changed
+        """
+        ...
+
+    async def execute_ui_batch(self, ctx: Context, commands: List[UICommand]) -> None:
+        """
+        Apply multiple UI commands in order as one request, E.g. show a message, then change query and hide app.
+        Raises RuntimeError if a command fails, following commands are skipped and commands applied before are not rolled back
+        """
+        ...
+
+    async def request_permission(self, ctx: Context, permission: Permission) -> None:
+        """
+        Must be called before a sensitive operation (E.g. reading clipboard), the permission must be declared in plugin.json.
+        Raises PermissionDeniedError with the reason if permission is denied, the operation should be skipped then
+        """
+        ...
+
+    async def send_to_plugin(self, ctx: Context, target_plugin_id: str, payload: Selection, context_data: str = "") -> None:
+        """
+        Hand payload to another plugin (E.g. pipe a file result into a compression plugin), it's delivered to target plugin as a
+        selection query. Empty target_plugin_id means any plugin supports selection query. context_data is passed to target plugin as is
+        """
+        ...
+
+    async def carry_context(self, ctx: Context, context_data: str) -> None:
+        """Make context_data available to the next input queries as Query.carried_context, see CarriedContext"""
+        ...
+
+    async def report_query_progress(self, ctx: Context, progress: int, text: str) -> None:
+        """
+        Show progress of the running query in UI before results arrive (E.g. "Searching… 40%"), text supports i18n.
+        Progress is 0-100, negative means indeterminate. Updates are rate limited
+        """
+        ...
+
+    async def pick_file(self, ctx: Context, options: PickFileOptions) -> List[str]:
+        """Open the native file picker and return paths of chosen files, empty if user cancelled or the picker can't be opened"""
+        ...
+
+    async def pick_folder(self, ctx: Context, options: PickFolderOptions) -> str:
+        """Open the native folder picker and return path of the chosen folder, empty if user cancelled or the picker can't be opened"""
+        ...
+
+    async def show_dialog(self, ctx: Context, spec: DialogSpec) -> Dict[str, str]:
+        """
+        Show a modal form and wait until user submits it, texts support i18n. Returns the value of each field by key,
+        and the key of the pressed button as DIALOG_BUTTON_RESPONSE_KEY if spec has buttons.
+        Raises DialogError if user cancelled the dialog or didn't respond in time
+        """
+        ...
+
+    async def store_get(self, ctx: Context, key: str) -> Any:
+        """Get a value from the persistent key-value store of plugin, None is returned if key doesn't exist"""
+        ...
+
+    async def store_set(self, ctx: Context, key: str, value: Any) -> None:
+        """
+        Save a json serializable value to the persistent key-value store of plugin, saved to disk immediately.
+        Raises RuntimeError if the value can't be saved (E.g. store exceeds the size limit)
+        """
+        ...
+
+    async def store_delete(self, ctx: Context, key: str) -> None:
+        """Delete a value from the persistent key-value store of plugin"""
+        ...
+
+    async def secret_get(self, ctx: Context, key: str) -> str:
+        """Get a secret (E.g. api token) of plugin from the OS keyring, empty string is returned if key doesn't exist"""
+        ...
+
+    async def secret_set(self, ctx: Context, key: str, value: str) -> None:
+        """Save a secret of plugin to the OS keyring, or an encrypted file if keyring is not available"""
+        ...
+
+    async def secret_delete(self, ctx: Context, key: str) -> None:
+        """Delete a secret of plugin"""
+        ...
@@ -0,0 +1,99 @@
+from typing import List
+from dataclasses import dataclass, field
+from enum import Enum
+import json
+
+
+class DialogFieldType(str, Enum):
+    """Dialog field type enum for Wox"""
+
+    TEXT = "text"
+    PASSWORD = "password"
+    SELECT = "select"
+    CHECKBOX = "checkbox"  # value is "true" or "false"
+
+
+# Key of the pressed button in responses of show_dialog, field keys can't use it
+DIALOG_BUTTON_RESPONSE_KEY = "$button"
+
+
+@dataclass
+class DialogFieldOption:
+    """Option of a select field"""
+
+    label: str
+    value: str
+
+
+@dataclass
+class DialogField:
+    """Field of a dialog"""
+
+    key: str
+    type: DialogFieldType
+    label: str
+    placeholder: str = field(default="")
+    # "true" or "false" for checkbox
+    default_value: str = field(default="")
+    # Only used by select
+    options: List[DialogFieldOption] = field(default_factory=list)
+    # UI doesn't submit until required fields are filled, checkbox is never missing
+    required: bool = field(default=False)
+
+
+@dataclass
+class DialogButton:
+    """Button of a dialog"""
+
+    key: str
+    label: str
+    # If true, pressing the button cancels the dialog without checking required fields, same as Escape
+    is_cancel: bool = field(default=False)
+
+
+@dataclass
+class DialogSpec:
+    """Modal form shown by show_dialog (E.g. OAuth consent, multi-field setup of an integration)"""
+
+    title: str
+    fields: List[DialogField] = field(default_factory=list)
+    description: str = field(default="")
+    # Empty means a submit and a cancel button
+    buttons: List[DialogButton] = field(default_factory=list)
+    # 0 means 5 minutes, the dialog is closed when it times out
+    timeout_seconds: int = field(default=0)
+
+    def to_json(self) -> str:
+        """Convert to JSON string with camelCase naming"""
+        return json.dumps(
+            {
+                "Title": self.title,
+                "Description": self.description,
+                "Fields": [
+                    {
+                        "Key": f.key,
+                        "Type": f.type,
+                        "Label": f.label,
+                        "Placeholder": f.placeholder,
+                        "DefaultValue": f.default_value,
+                        "Options": [{"Label": option.label, "Value": option.value} for option in f.options],
+                        "Required": f.required,
+                    }
+                    for f in self.fields
+                ],
+                "Buttons": [{"Key": button.key, "Label": button.label, "IsCancel": button.is_cancel} for button in self.buttons],
+                "TimeoutSeconds": self.timeout_seconds,
+            }
+        )
+
+
+class DialogError(Exception):
+    """Raised by show_dialog if the dialog is not submitted, message is "cancelled", "timeout" or the error"""
+
+    @property
+    def is_cancelled(self) -> bool:
+        return str(self) == "cancelled"
+
+    @property
+    def is_timeout(self) -> bool:
+        return str(self) == "timeout"
@@ -0,0 +1,13 @@
+from enum import Enum
+
+
+class Permission(str, Enum):
+    """Permission of request_permission, it must be declared in plugin.json"""
+
+    CLIPBOARD = "clipboard"
+    SELECTION = "selection"
+    NETWORK = "network"
+
+
+class PermissionDeniedError(Exception):
+    """Raised by request_permission if permission is denied, message is the reason"""
@@ -0,0 +1,42 @@
+from typing import List
+from dataclasses import dataclass, field
+import json
+
+
+@dataclass
+class PickFileOptions:
+    """Options of pick_file"""
+
+    # Title of the picker, supports i18n
+    title: str = field(default="")
+    # Directory the picker starts in, empty means the last directory the OS remembers
+    default_directory: str = field(default="")
+    # Allowed file extensions without dot, E.g. ["png", "jpg"]. Empty means all files
+    extensions: List[str] = field(default_factory=list)
+    # If true, user can pick several files
+    allow_multiple: bool = field(default=False)
+
+    def to_json(self) -> str:
+        """Convert to JSON string with camelCase naming"""
+        return json.dumps(
+            {
+                "Title": self.title,
+                "DefaultDirectory": self.default_directory,
+                "Extensions": self.extensions,
+                "AllowMultiple": self.allow_multiple,
+            }
+        )
+
+
+@dataclass
+class PickFolderOptions:
+    """Options of pick_folder"""
+
+    # Title of the picker, supports i18n
+    title: str = field(default="")
+    # Directory the picker starts in, empty means the last directory the OS remembers
+    default_directory: str = field(default="")
+
+    def to_json(self) -> str:
+        """Convert to JSON string with camelCase naming"""
+        return json.dumps({"Title": self.title, "DefaultDirectory": self.default_directory})
@@ -1,4 +1,4 @@
-from typing import List
+from typing import List, Optional
 from dataclasses import dataclass, field
 from enum import Enum
 import json
@@ -124,6 +124,18 @@ class QueryEnv:
         )
 
 
+@dataclass
+class CarriedContext:
+    """
+    Context data stashed by an action with carry_context for the queries user types next, E.g. pick a repository,
+    then search issues within it. It's dropped when user clears the query box, Wox is hidden, another context is
+    carried or no query is made within 5 minutes
+    """
+
+    source_plugin_id: str = field(default="")
+    context_data: str = field(default="")
+
+
 @dataclass
 class Query:
     """
@@ -137,6 +149,8 @@ class Query:
     trigger_keyword: str = field(default="")
     command: str = field(default="")
     search: str = field(default="")
+    # Only available when query type is input and an action carried context, see CarriedContext
+    carried_context: Optional[CarriedContext] = field(default=None)
 
     def to_json(self) -> str:
         """Convert to JSON string with camelCase naming"""
@@ -149,6 +163,11 @@ class Query:
                 "TriggerKeyword": self.trigger_keyword,
                 "Command": self.command,
                 "Search": self.search,
+                "CarriedContext": json.dumps(
+                    {"SourcePluginId": self.carried_context.source_plugin_id, "ContextData": self.carried_context.context_data}
+                    if self.carried_context
+                    else None
+                ),
             }
         )
 
@@ -160,6 +179,9 @@ class Query:
         if not data.get("Type"):
             data["Type"] = QueryType.INPUT
 
+        # carried context is a json string, "null" if there is none
+        carried_context = json.loads(data.get("CarriedContext") or "null")
+
         return cls(
             type=QueryType(data.get("Type")),
             raw_query=data.get("RawQuery", ""),
@@ -168,6 +190,14 @@ class Query:
             trigger_keyword=data.get("TriggerKeyword", ""),
             command=data.get("Command", ""),
             search=data.get("Search", ""),
+            carried_context=(
+                CarriedContext(
+                    source_plugin_id=carried_context.get("SourcePluginId", ""),
+                    context_data=carried_context.get("ContextData", ""),
+                )
+                if carried_context
+                else None
+            ),
         )
 
     def is_global_query(self) -> bool:
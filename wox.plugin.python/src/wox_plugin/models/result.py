@@ -52,12 +52,15 @@ class ActionContext:
     """Context for result actions"""
 
     context_data: str
+    # Trigger keyword of the query which returned this result (E.g. "gh" or "github" for a plugin with both), empty for global queries
+    trigger_keyword: str = field(default="")
 
     def to_json(self) -> str:
         """Convert to JSON string with camelCase naming"""
         return json.dumps(
             {
                 "ContextData": self.context_data,
+                "TriggerKeyword": self.trigger_keyword,
             }
         )
 
@@ -67,6 +70,7 @@ class ActionContext:
         data = json.loads(json_str)
         return cls(
             context_data=data.get("ContextData", ""),
+            trigger_keyword=data.get("TriggerKeyword", ""),
         )
 
 
@@ -190,6 +194,8 @@ class RefreshableResult:
     context_data: str = field(default="")
     refresh_interval: int = field(default=0)
     actions: List[ResultAction] = field(default_factory=list)
+    # Trigger keyword of the query which returned this result, empty for global queries. Changing it has no effect
+    trigger_keyword: str = field(default="")
 
     def to_json(self) -> str:
         """Convert to JSON string with camelCase naming"""
@@ -203,6 +209,7 @@ class RefreshableResult:
                 "ContextData": self.context_data,
                 "RefreshInterval": self.refresh_interval,
                 "Actions": [json.loads(action.to_json()) for action in self.actions],
+                "TriggerKeyword": self.trigger_keyword,
             },
         )
 
@@ -219,6 +226,7 @@ class RefreshableResult:
             context_data=data.get("ContextData", ""),
             refresh_interval=data.get("RefreshInterval", 0),
             actions=[ResultAction.from_json(json.dumps(action)) for action in data["Actions"]],
+            trigger_keyword=data.get("TriggerKeyword", ""),
         )
 
     def __await__(self):
@@ -227,3 +235,45 @@ class RefreshableResult:
             return self
 
         return _awaitable().__await__()
+
+
+@dataclass
+class HeadlessActionContext:
+    """Context supplied by the caller of a headless action"""
+
+    # Data passed as is by caller: the query of a query hotkey or "contextData" of /action/headless request
+    context_data: str
+
+
+@dataclass
+class HeadlessAction:
+    """
+    Named action which runs without a query (E.g. "toggle-dark-mode"), invoked by a query hotkey
+    or /action/headless endpoint as "<plugin id or name>:<action name>"
+    """
+
+    # Unique within plugin, letters, digits, "-" and "_"
+    name: str
+    # Raised exception is reported to caller, hotkeys show it as a notification
+    action: Callable[[HeadlessActionContext], Awaitable[None]]
+    description: str = field(default="")
+
+
+@dataclass
+class StaticResult:
+    """
+    Always available result (E.g. quit, lock) registered by register_static_results.
+    Wox matches it against input queries itself and shows it instantly, without invoking the plugin
+    """
+
+    result: Result
+    # Texts matched against query search, result title is used if empty
+    match_texts: List[str] = field(default_factory=list)
+
+    def to_json(self) -> str:
+        """Convert to JSON string with camelCase naming"""
+        result = json.loads(self.result.to_json())
+        # scores are integers in Wox
+        result["Score"] = int(self.result.score)
+        result["GroupScore"] = int(self.result.group_score)
+        return json.dumps({"Result": result, "MatchTexts": self.match_texts})
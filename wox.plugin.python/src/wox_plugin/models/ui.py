@@ -0,0 +1,44 @@
+from typing import Optional
+from dataclasses import dataclass, field
+from enum import Enum
+import json
+
+from .query import ChangeQueryParam
+
+
+class UICommandType(str, Enum):
+    """UI command type enum for Wox"""
+
+    CHANGE_QUERY = "ChangeQuery"
+    HIDE_APP = "HideApp"
+    SHOW_APP = "ShowApp"
+    SHOW_TOOLBAR_MSG = "ShowToolbarMsg"
+
+
+@dataclass
+class UICommand:
+    """
+    Single command of execute_ui_batch. Commands are applied in order, if a command fails,
+    following commands are skipped and commands applied before are not rolled back
+    """
+
+    type: UICommandType
+    # Only used by CHANGE_QUERY
+    query: Optional[ChangeQueryParam] = field(default=None)
+    # Only used by SHOW_APP, select all text of query box after app is shown
+    select_all: bool = field(default=False)
+    # Only used by SHOW_TOOLBAR_MSG, text supports i18n
+    msg_text: str = field(default="")
+    # Only used by SHOW_TOOLBAR_MSG, 0 means display forever
+    msg_display_seconds: int = field(default=0)
+
+    def to_json(self) -> str:
+        """Convert to JSON string with camelCase naming"""
+        return json.dumps(
+            {
+                "Type": self.type,
+                "Query": json.loads(self.query.to_json()) if self.query else {},
+                "ShowContext": {"SelectAll": self.select_all},
+                "Msg": {"Text": self.msg_text, "DisplaySeconds": self.msg_display_seconds},
+            }
+        )
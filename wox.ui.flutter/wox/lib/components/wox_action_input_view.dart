@@ -0,0 +1,89 @@
+import 'package:flutter/material.dart';
+import 'package:flutter/services.dart';
+import 'package:get/get.dart';
+import 'package:wox/entity/wox_query.dart';
+import 'package:wox/modules/setting/wox_setting_controller.dart';
+
+/// Prompt of an action which requires input, pops entered value, or null if user cancelled
+class WoxActionInputView extends StatefulWidget {
+  final WoxResultActionInput input;
+
+  // returns error message of invalid input, empty if input is valid
+  final Future<String> Function(String value) validate;
+
+  const WoxActionInputView({super.key, required this.input, required this.validate});
+
+  @override
+  State<WoxActionInputView> createState() => _WoxActionInputViewState();
+}
+
+class _WoxActionInputViewState extends State<WoxActionInputView> {
+  late final TextEditingController textController;
+  String errorText = "";
+
+  @override
+  void initState() {
+    super.initState();
+    textController = TextEditingController(text: widget.input.defaultValue);
+  }
+
+  @override
+  void dispose() {
+    textController.dispose();
+    super.dispose();
+  }
+
+  String tr(String key) {
+    return Get.find<WoxSettingController>().tr(key);
+  }
+
+  Future<void> validate(String value) async {
+    final error = await widget.validate(value);
+    // user may have typed more while waiting for wox.core, only show error of the latest value
+    if (!mounted || value != textController.text) {
+      return;
+    }
+    setState(() => errorText = error);
+  }
+
+  void cancel() {
+    Navigator.of(context).pop(null);
+  }
+
+  Future<void> submit() async {
+    final value = textController.text;
+    final error = await widget.validate(value);
+    if (!mounted) {
+      return;
+    }
+    if (error.isNotEmpty) {
+      setState(() => errorText = error);
+      return;
+    }
+
+    Navigator.of(context).pop(value);
+  }
+
+  @override
+  Widget build(BuildContext context) {
+    return CallbackShortcuts(
+      bindings: {
+        const SingleActivator(LogicalKeyboardKey.escape): cancel,
+        const SingleActivator(LogicalKeyboardKey.enter): submit,
+      },
+      child: AlertDialog(
+        title: widget.input.prompt.isNotEmpty ? Text(widget.input.prompt) : null,
+        content: TextField(
+          controller: textController,
+          autofocus: true,
+          decoration: InputDecoration(hintText: widget.input.placeholder, errorText: errorText.isEmpty ? null : errorText),
+          onChanged: validate,
+        ),
+        actions: [
+          TextButton(onPressed: cancel, child: Text(tr("ui_cancel"))),
+          FilledButton(onPressed: submit, child: Text(tr("ui_dialog_submit"))),
+        ],
+      ),
+    );
+  }
+}
@@ -0,0 +1,125 @@
+import 'package:flutter/material.dart';
+import 'package:flutter/services.dart';
+import 'package:wox/entity/wox_dialog.dart';
+
+// key of the pressed button in the submitted values, see DialogButtonResponseKey in wox.core
+const woxDialogButtonResponseKey = "\$button";
+
+/// Modal form of a plugin, pops submitted values by field key, or null if user cancelled
+class WoxDialogView extends StatefulWidget {
+  final WoxDialogSpec spec;
+
+  const WoxDialogView({super.key, required this.spec});
+
+  @override
+  State<WoxDialogView> createState() => _WoxDialogViewState();
+}
+
+class _WoxDialogViewState extends State<WoxDialogView> {
+  final Map<String, String> values = {};
+  final Map<String, TextEditingController> textControllers = {};
+  final Set<String> missingFields = {};
+
+  @override
+  void initState() {
+    super.initState();
+    for (var field in widget.spec.fields) {
+      values[field.key] = field.type == "checkbox" ? (field.defaultValue == "true").toString() : field.defaultValue;
+      if (field.type == "text" || field.type == "password") {
+        textControllers[field.key] = TextEditingController(text: field.defaultValue);
+      }
+    }
+  }
+
+  @override
+  void dispose() {
+    for (var controller in textControllers.values) {
+      controller.dispose();
+    }
+    super.dispose();
+  }
+
+  void cancel() {
+    Navigator.of(context).pop(null);
+  }
+
+  void submit(WoxDialogButton button) {
+    if (button.isCancel) {
+      cancel();
+      return;
+    }
+
+    final missing = widget.spec.fields.where((field) => field.required && field.type != "checkbox" && (values[field.key] ?? "").isEmpty).map((field) => field.key).toSet();
+    if (missing.isNotEmpty) {
+      setState(() {
+        missingFields
+          ..clear()
+          ..addAll(missing);
+      });
+      return;
+    }
+
+    Navigator.of(context).pop({...values, woxDialogButtonResponseKey: button.key});
+  }
+
+  Widget buildField(WoxDialogField field) {
+    final errorText = missingFields.contains(field.key) ? "*" : null;
+    switch (field.type) {
+      case "checkbox":
+        return CheckboxListTile(
+          contentPadding: EdgeInsets.zero,
+          title: Text(field.label),
+          value: values[field.key] == "true",
+          onChanged: (checked) => setState(() => values[field.key] = (checked ?? false).toString()),
+        );
+      case "select":
+        final hasValue = field.options.any((option) => option.value == values[field.key]);
+        return DropdownButtonFormField<String>(
+          decoration: InputDecoration(labelText: field.label, hintText: field.placeholder, errorText: errorText),
+          value: hasValue ? values[field.key] : null,
+          items: field.options.map((option) => DropdownMenuItem(value: option.value, child: Text(option.label))).toList(),
+          onChanged: (value) => setState(() => values[field.key] = value ?? ""),
+        );
+      default:
+        return TextField(
+          controller: textControllers[field.key],
+          obscureText: field.type == "password",
+          decoration: InputDecoration(labelText: field.label, hintText: field.placeholder, errorText: errorText),
+          onChanged: (value) => values[field.key] = value,
+        );
+    }
+  }
+
+  @override
+  Widget build(BuildContext context) {
+    final submitButton = widget.spec.buttons.lastWhere((button) => !button.isCancel, orElse: () => widget.spec.buttons.last);
+    return CallbackShortcuts(
+      bindings: {
+        const SingleActivator(LogicalKeyboardKey.escape): cancel,
+        const SingleActivator(LogicalKeyboardKey.enter): () => submit(submitButton),
+      },
+      child: Focus(
+        autofocus: true,
+        child: AlertDialog(
+          title: Text(widget.spec.title),
+          content: SingleChildScrollView(
+            child: Column(
+              mainAxisSize: MainAxisSize.min,
+              crossAxisAlignment: CrossAxisAlignment.start,
+              children: [
+                if (widget.spec.description.isNotEmpty) Text(widget.spec.description),
+                ...widget.spec.fields.map((field) => Padding(padding: const EdgeInsets.only(top: 8), child: buildField(field))),
+              ],
+            ),
+          ),
+          actions: widget.spec.buttons.map((button) {
+            if (button.isCancel) {
+              return TextButton(onPressed: () => submit(button), child: Text(button.label));
+            }
+            return FilledButton(onPressed: () => submit(button), child: Text(button.label));
+          }).toList(),
+        ),
+      ),
+    );
+  }
+}
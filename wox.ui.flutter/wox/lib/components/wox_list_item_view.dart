@@ -3,19 +3,25 @@ import 'package:from_css_color/from_css_color.dart';
 import 'package:get/get.dart';
 import 'package:uuid/v4.dart';
 import 'package:wox/components/wox_image_view.dart';
+import 'package:wox/components/wox_truncated_text_view.dart';
 import 'package:wox/entity/wox_image.dart';
 import 'package:wox/entity/wox_query.dart';
 import 'package:wox/entity/wox_theme.dart';
 import 'package:wox/enums/wox_image_type_enum.dart';
 import 'package:wox/enums/wox_list_view_type_enum.dart';
 import 'package:wox/enums/wox_result_tail_type_enum.dart';
+import 'package:wox/enums/wox_result_truncation_enum.dart';
+import 'package:wox/modules/setting/wox_setting_controller.dart';
+import 'package:wox/utils/consts.dart';
 import 'package:wox/utils/log.dart';
+import 'package:wox/utils/strings.dart';
 import 'package:wox/utils/wox_setting_util.dart';
 
 import 'wox_hotkey_view.dart';
 
 class WoxListItemView extends StatelessWidget {
   final bool isActive;
+  final bool isSelected; // selected for a batch action, see WoxLauncherController.selectedResultIds
   final Rx<WoxImage> icon;
   final Rx<String> title;
   final Rx<String> subTitle;
@@ -23,6 +29,10 @@ class WoxListItemView extends StatelessWidget {
   final WoxTheme woxTheme;
   final WoxListViewType listViewType;
   final bool isGroup;
+  final double iconSize; // icon size hint of the result, see WoxQueryResult.iconSize
+  final WoxResultTruncation titleTruncation;
+  final WoxResultTruncation subTitleTruncation;
+  final int lastUpdated; // results with data updated at this time are stale, 0 means live, see WoxQueryResult.lastUpdated
 
   const WoxListItemView({
     super.key,
@@ -32,14 +42,43 @@ class WoxListItemView extends StatelessWidget {
     required this.subTitle,
     required this.tails,
     required this.isActive,
+    this.isSelected = false,
     required this.listViewType,
     required this.isGroup,
+    this.iconSize = RESULT_ITEM_DEFAULT_ICON_SIZE,
+    this.titleTruncation = "end",
+    this.subTitleTruncation = "end",
+    this.lastUpdated = 0,
   });
 
   bool isAction() {
     return listViewType == WoxListViewTypeEnum.WOX_LIST_VIEW_TYPE_ACTION.code;
   }
 
+  bool isStale() {
+    return lastUpdated > 0;
+  }
+
+  Widget buildLastUpdated() {
+    final settingController = Get.find<WoxSettingController>();
+    final relativeTime = Strings.formatRelativeTime(lastUpdated, settingController.tr);
+    return Padding(
+      padding: const EdgeInsets.only(left: 6.0),
+      child: Text(
+        Strings.format(settingController.tr("ui_result_updated"), [relativeTime]),
+        style: TextStyle(
+          color: fromCssColor(isActive ? woxTheme.resultItemActiveSubTitleColor : woxTheme.resultItemSubTitleColor),
+          fontSize: 12,
+          fontStyle: FontStyle.italic,
+        ),
+        maxLines: 1,
+        strutStyle: const StrutStyle(
+          forceStrutHeight: true,
+        ),
+      ),
+    );
+  }
+
   double getImageSize(WoxImage img, double defaultSize) {
     if (img.imageType == WoxImageTypeEnum.WOX_IMAGE_TYPE_EMOJI.code) {
       return defaultSize - 10;
@@ -106,7 +145,11 @@ class WoxListItemView extends StatelessWidget {
 
     return Container(
       decoration: BoxDecoration(
-        color: isActive ? fromCssColor(isAction() ? woxTheme.actionItemActiveBackgroundColor : woxTheme.resultItemActiveBackgroundColor) : Colors.transparent,
+        color: isActive
+            ? fromCssColor(isAction() ? woxTheme.actionItemActiveBackgroundColor : woxTheme.resultItemActiveBackgroundColor)
+            : isSelected
+                ? fromCssColor(woxTheme.resultItemActiveBackgroundColor).withOpacity(0.5)
+                : Colors.transparent,
         borderRadius: BorderRadius.circular(isAction() ? 0.0 : woxTheme.resultItemBorderRadius.toDouble()),
         border: Border(
             left: isAction()
@@ -135,52 +178,61 @@ class WoxListItemView extends StatelessWidget {
 
                     return WoxImageView(
                       woxImage: icon.value,
-                      width: getImageSize(icon.value, 30),
-                      height: getImageSize(icon.value, 30),
+                      width: getImageSize(icon.value, iconSize),
+                      height: getImageSize(icon.value, iconSize),
                     );
                   })),
           Expanded(
-            child: Column(crossAxisAlignment: CrossAxisAlignment.start, mainAxisAlignment: MainAxisAlignment.center, children: [
-              Obx(() {
-                if (LoggerSwitch.enablePaintLog) Logger.instance.info(const UuidV4().generate(), "repaint: list item view ${title.value} - title");
+            // stale results are dimmed, so user can tell cached data from live data at a glance
+            child: Opacity(
+              opacity: isStale() ? 0.7 : 1.0,
+              child: Column(crossAxisAlignment: CrossAxisAlignment.start, mainAxisAlignment: MainAxisAlignment.center, children: [
+                Obx(() {
+                  if (LoggerSwitch.enablePaintLog) Logger.instance.info(const UuidV4().generate(), "repaint: list item view ${title.value} - title");
 
-                return Text(
-                  title.value,
-                  style: TextStyle(
-                    fontSize: 16,
-                    color: isAction()
-                        ? fromCssColor(isActive ? woxTheme.actionItemActiveFontColor : woxTheme.actionItemFontColor)
-                        : fromCssColor(isActive ? woxTheme.resultItemActiveTitleColor : woxTheme.resultItemTitleColor),
-                  ),
-                  maxLines: 1,
-                  overflow: TextOverflow.ellipsis,
-                  strutStyle: const StrutStyle(
-                    forceStrutHeight: true,
-                  ),
-                );
-              }),
-              Obx(() {
-                if (LoggerSwitch.enablePaintLog) Logger.instance.info(const UuidV4().generate(), "repaint: list item view ${title.value} - subtitle");
+                  return WoxTruncatedTextView(
+                    text: title.value,
+                    style: TextStyle(
+                      fontSize: 16,
+                      color: isAction()
+                          ? fromCssColor(isActive ? woxTheme.actionItemActiveFontColor : woxTheme.actionItemFontColor)
+                          : fromCssColor(isActive ? woxTheme.resultItemActiveTitleColor : woxTheme.resultItemTitleColor),
+                    ),
+                    truncation: titleTruncation,
+                    strutStyle: const StrutStyle(
+                      forceStrutHeight: true,
+                    ),
+                  );
+                }),
+                Obx(() {
+                  if (LoggerSwitch.enablePaintLog) Logger.instance.info(const UuidV4().generate(), "repaint: list item view ${title.value} - subtitle");
 
-                return subTitle.isNotEmpty
-                    ? Padding(
-                        padding: const EdgeInsets.only(top: 2.0),
-                        child: Text(
-                          subTitle.value,
-                          style: TextStyle(
-                            color: fromCssColor(isActive ? woxTheme.resultItemActiveSubTitleColor : woxTheme.resultItemSubTitleColor),
-                            fontSize: 13,
-                          ),
-                          maxLines: 1,
-                          overflow: TextOverflow.ellipsis,
-                          strutStyle: const StrutStyle(
-                            forceStrutHeight: true,
+                  return subTitle.isNotEmpty || isStale()
+                      ? Padding(
+                          padding: const EdgeInsets.only(top: 2.0),
+                          child: Row(
+                            children: [
+                              Flexible(
+                                child: WoxTruncatedTextView(
+                                  text: subTitle.value,
+                                  style: TextStyle(
+                                    color: fromCssColor(isActive ? woxTheme.resultItemActiveSubTitleColor : woxTheme.resultItemSubTitleColor),
+                                    fontSize: 13,
+                                  ),
+                                  truncation: subTitleTruncation,
+                                  strutStyle: const StrutStyle(
+                                    forceStrutHeight: true,
+                                  ),
+                                ),
+                              ),
+                              if (isStale()) buildLastUpdated(),
+                            ],
                           ),
-                        ),
-                      )
-                    : const SizedBox();
-              }),
-            ]),
+                        )
+                      : const SizedBox();
+                }),
+              ]),
+            ),
           ),
           // Tails
           Obx(() {
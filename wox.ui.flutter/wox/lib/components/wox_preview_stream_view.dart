@@ -0,0 +1,144 @@
+import 'package:dio/dio.dart';
+import 'package:flutter/material.dart';
+import 'package:from_css_color/from_css_color.dart';
+import 'package:get/get.dart';
+import 'package:wox/entity/wox_preview.dart';
+import 'package:wox/entity/wox_theme.dart';
+import 'package:wox/modules/setting/wox_setting_controller.dart';
+import 'package:wox/utils/wox_http_util.dart';
+
+/// Preview of a large text file, starts from the end of the file and loads earlier windows on demand instead of loading the whole file
+class WoxPreviewStreamView extends StatefulWidget {
+  final String streamUrl;
+  final WoxTheme woxTheme;
+
+  const WoxPreviewStreamView({super.key, required this.streamUrl, required this.woxTheme});
+
+  @override
+  State<WoxPreviewStreamView> createState() => _WoxPreviewStreamViewState();
+}
+
+class _WoxPreviewStreamViewState extends State<WoxPreviewStreamView> {
+  // same as default window size of wox.core
+  static const windowSize = 64 * 1024;
+
+  final scrollController = ScrollController();
+  String text = "";
+  int startOffset = 0;
+  bool isLoading = false;
+  String? error;
+  // cancelled when the preview is closed or switched to another stream, so wox.core stops reading the file
+  CancelToken cancelToken = CancelToken();
+
+  @override
+  void initState() {
+    super.initState();
+    loadTail();
+  }
+
+  @override
+  void didUpdateWidget(covariant WoxPreviewStreamView oldWidget) {
+    super.didUpdateWidget(oldWidget);
+    if (oldWidget.streamUrl != widget.streamUrl) {
+      cancelToken.cancel();
+      cancelToken = CancelToken();
+      loadTail();
+    }
+  }
+
+  @override
+  void dispose() {
+    cancelToken.cancel();
+    scrollController.dispose();
+    super.dispose();
+  }
+
+  Future<void> loadTail() async {
+    final streamUrl = widget.streamUrl;
+    setState(() {
+      isLoading = true;
+      error = null;
+    });
+    try {
+      final window = await WoxHttpUtil.instance.getData<WoxPreviewStreamWindow>(streamUrl, cancelToken: cancelToken);
+      if (!mounted || streamUrl != widget.streamUrl) return;
+      setState(() {
+        text = window.data;
+        startOffset = window.offset;
+      });
+      WidgetsBinding.instance.addPostFrameCallback((_) {
+        if (scrollController.hasClients) {
+          scrollController.jumpTo(scrollController.position.maxScrollExtent);
+        }
+      });
+    } catch (e) {
+      if (mounted && !isCancelled(e)) setState(() => error = e.toString());
+    } finally {
+      if (mounted) setState(() => isLoading = false);
+    }
+  }
+
+  bool isCancelled(Object e) {
+    return e is DioException && CancelToken.isCancel(e);
+  }
+
+  Future<void> loadEarlier() async {
+    if (isLoading || startOffset <= 0) return;
+
+    final streamUrl = widget.streamUrl;
+    setState(() => isLoading = true);
+    try {
+      // only ask for the bytes before current text, so windows never overlap
+      final offset = startOffset > windowSize ? startOffset - windowSize : 0;
+      final window = await WoxHttpUtil.instance.getData<WoxPreviewStreamWindow>(streamUrl, params: {"offset": offset, "length": startOffset - offset}, cancelToken: cancelToken);
+      if (!mounted || streamUrl != widget.streamUrl) return;
+
+      // keep the visible text at the same position after prepending
+      final previousExtent = scrollController.hasClients ? scrollController.position.maxScrollExtent : 0.0;
+      setState(() {
+        text = window.data + text;
+        startOffset = window.offset;
+      });
+      WidgetsBinding.instance.addPostFrameCallback((_) {
+        if (scrollController.hasClients) {
+          scrollController.jumpTo(scrollController.offset + scrollController.position.maxScrollExtent - previousExtent);
+        }
+      });
+    } catch (e) {
+      if (mounted && !isCancelled(e)) setState(() => error = e.toString());
+    } finally {
+      if (mounted) setState(() => isLoading = false);
+    }
+  }
+
+  @override
+  Widget build(BuildContext context) {
+    if (error != null) {
+      return SelectableText(error!, style: const TextStyle(color: Colors.red));
+    }
+    if (isLoading && text.isEmpty) {
+      return const Center(child: CircularProgressIndicator());
+    }
+
+    return Scrollbar(
+      controller: scrollController,
+      child: SingleChildScrollView(
+        controller: scrollController,
+        child: Column(
+          crossAxisAlignment: CrossAxisAlignment.start,
+          children: [
+            if (startOffset > 0)
+              TextButton(
+                onPressed: isLoading ? null : loadEarlier,
+                child: Text(Get.find<WoxSettingController>().tr("ui_preview_stream_load_earlier"), style: TextStyle(color: fromCssColor(widget.woxTheme.previewPropertyTitleColor))),
+              ),
+            SelectableText(
+              text,
+              style: TextStyle(color: fromCssColor(widget.woxTheme.previewFontColor)),
+            ),
+          ],
+        ),
+      ),
+    );
+  }
+}
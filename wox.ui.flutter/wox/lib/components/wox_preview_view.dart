@@ -15,6 +15,7 @@ import 'package:highlight/languages/yaml.dart';
 import 'package:syncfusion_flutter_pdfviewer/pdfviewer.dart';
 import 'package:uuid/v4.dart';
 import 'package:wox/components/wox_image_view.dart';
+import 'package:wox/components/wox_preview_stream_view.dart';
 import 'package:wox/entity/wox_image.dart';
 import 'package:wox/entity/wox_preview.dart';
 import 'package:wox/entity/wox_theme.dart';
@@ -219,6 +220,8 @@ class _WoxPreviewViewState extends State<WoxPreviewView> {
           contentWidget = buildText("Unsupported file type preview: $fileExtension");
         }
       }
+    } else if (widget.woxPreview.previewType == WoxPreviewTypeEnum.WOX_PREVIEW_TYPE_STREAM.code) {
+      contentWidget = WoxPreviewStreamView(streamUrl: widget.woxPreview.previewData, woxTheme: widget.woxTheme);
     } else if (widget.woxPreview.previewType == WoxPreviewTypeEnum.WOX_PREVIEW_TYPE_IMAGE.code) {
       final parsedWoxImage = WoxImage.parse(widget.woxPreview.previewData);
       if (parsedWoxImage == null) {
@@ -0,0 +1,67 @@
+import 'package:flutter/material.dart';
+import 'package:wox/enums/wox_result_truncation_enum.dart';
+
+/// Single line text which puts the ellipsis at the start, middle or end when text doesn't fit, see [WoxResultTruncationEnum].
+/// Flutter only supports ellipsis at the end, so start and middle are done by measuring how many characters fit
+class WoxTruncatedTextView extends StatelessWidget {
+  static const ellipsis = "…";
+
+  final String text;
+  final TextStyle style;
+  final WoxResultTruncation truncation;
+  final StrutStyle? strutStyle;
+
+  const WoxTruncatedTextView({super.key, required this.text, required this.style, required this.truncation, this.strutStyle});
+
+  @override
+  Widget build(BuildContext context) {
+    if (truncation != WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_START.code && truncation != WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_MIDDLE.code) {
+      return Text(text, style: style, maxLines: 1, overflow: TextOverflow.ellipsis, strutStyle: strutStyle);
+    }
+
+    return LayoutBuilder(builder: (context, constraints) {
+      return Text(truncate(context, constraints.maxWidth), style: style, maxLines: 1, softWrap: false, overflow: TextOverflow.clip, strutStyle: strutStyle);
+    });
+  }
+
+  String truncate(BuildContext context, double maxWidth) {
+    if (!maxWidth.isFinite || fits(context, text, maxWidth)) {
+      return text;
+    }
+
+    // binary search the most characters to keep, characters are counted by runes so emoji won't be split
+    final runes = text.runes.toList();
+    var low = 0;
+    var high = runes.length;
+    while (low < high) {
+      final keep = (low + high + 1) ~/ 2;
+      if (fits(context, buildTruncatedText(runes, keep), maxWidth)) {
+        low = keep;
+      } else {
+        high = keep - 1;
+      }
+    }
+    return buildTruncatedText(runes, low);
+  }
+
+  String buildTruncatedText(List<int> runes, int keep) {
+    if (truncation == WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_START.code) {
+      return ellipsis + String.fromCharCodes(runes.sublist(runes.length - keep));
+    }
+
+    final head = (keep + 1) ~/ 2;
+    final tail = keep - head;
+    return String.fromCharCodes(runes.sublist(0, head)) + ellipsis + String.fromCharCodes(runes.sublist(runes.length - tail));
+  }
+
+  bool fits(BuildContext context, String value, double maxWidth) {
+    final painter = TextPainter(
+      text: TextSpan(text: value, style: DefaultTextStyle.of(context).style.merge(style)),
+      maxLines: 1,
+      textDirection: Directionality.of(context),
+      textScaler: MediaQuery.textScalerOf(context),
+      strutStyle: strutStyle,
+    )..layout();
+    return painter.width <= maxWidth;
+  }
+}
@@ -0,0 +1,58 @@
+// modal form shown by plugins, see DialogSpec in wox.core
+class WoxDialogSpec {
+  late String id;
+  late String title;
+  late String description;
+  late List<WoxDialogField> fields;
+  late List<WoxDialogButton> buttons;
+
+  WoxDialogSpec.fromJson(Map<String, dynamic> json) {
+    id = json['Id'];
+    title = json['Title'] ?? "";
+    description = json['Description'] ?? "";
+    fields = json['Fields'] == null ? [] : (json['Fields'] as List).map((e) => WoxDialogField.fromJson(e)).toList();
+    buttons = json['Buttons'] == null ? [] : (json['Buttons'] as List).map((e) => WoxDialogButton.fromJson(e)).toList();
+  }
+}
+
+class WoxDialogField {
+  late String key;
+  late String type; // text, password, select or checkbox
+  late String label;
+  late String placeholder;
+  late String defaultValue;
+  late List<WoxDialogFieldOption> options;
+  late bool required;
+
+  WoxDialogField.fromJson(Map<String, dynamic> json) {
+    key = json['Key'];
+    type = json['Type'];
+    label = json['Label'] ?? "";
+    placeholder = json['Placeholder'] ?? "";
+    defaultValue = json['DefaultValue'] ?? "";
+    options = json['Options'] == null ? [] : (json['Options'] as List).map((e) => WoxDialogFieldOption.fromJson(e)).toList();
+    required = json['Required'] ?? false;
+  }
+}
+
+class WoxDialogFieldOption {
+  late String label;
+  late String value;
+
+  WoxDialogFieldOption.fromJson(Map<String, dynamic> json) {
+    label = json['Label'];
+    value = json['Value'];
+  }
+}
+
+class WoxDialogButton {
+  late String key;
+  late String label;
+  late bool isCancel;
+
+  WoxDialogButton.fromJson(Map<String, dynamic> json) {
+    key = json['Key'];
+    label = json['Label'];
+    isCancel = json['IsCancel'] ?? false;
+  }
+}
@@ -229,6 +229,17 @@ class WoxHotkey {
     return modifiers;
   }
 
+  /// Names of pressed modifiers sent to Wox core (E.g. when executing an action), core converts them to platform specific names, E.g. cmd to win on Windows
+  static List<String> getPressedModifierNames() {
+    const names = {
+      HotKeyModifier.alt: "alt",
+      HotKeyModifier.control: "ctrl",
+      HotKeyModifier.shift: "shift",
+      HotKeyModifier.meta: "cmd",
+    };
+    return getPressedModifiers().map((modifier) => names[modifier]!).toList();
+  }
+
   static bool isAllowedKey(PhysicalKeyboardKey key) {
     var allowedKeys = [
       PhysicalKeyboardKey.keyA,
@@ -39,3 +39,18 @@ class WoxPreview {
     return WoxPreview(previewType: "", previewData: "", previewProperties: {}, scrollPosition: "");
   }
 }
+
+/// A range of the file of a stream preview, see PreviewStreamWindow in wox.core
+class WoxPreviewStreamWindow {
+  late int offset;
+  late int length;
+  late int size;
+  late String data;
+
+  WoxPreviewStreamWindow.fromJson(Map<String, dynamic> json) {
+    offset = json['Offset'];
+    length = json['Length'];
+    size = json['Size'];
+    data = json['Data'];
+  }
+}
@@ -3,11 +3,14 @@ import 'package:hotkey_manager/hotkey_manager.dart';
 import 'package:wox/entity/wox_hotkey.dart';
 import 'package:wox/entity/wox_image.dart';
 import 'package:wox/entity/wox_preview.dart';
+import 'package:wox/enums/wox_escape_mode_enum.dart';
 import 'package:wox/enums/wox_last_query_mode_enum.dart';
 import 'package:wox/enums/wox_position_type_enum.dart';
 import 'package:wox/enums/wox_query_type_enum.dart';
 import 'package:wox/enums/wox_result_tail_type_enum.dart';
+import 'package:wox/enums/wox_result_truncation_enum.dart';
 import 'package:wox/enums/wox_selection_type_enum.dart';
+import 'package:wox/utils/consts.dart';
 
 class PlainQuery {
   late String queryId;
@@ -101,9 +104,37 @@ class WoxQueryResult {
   late RxList<WoxQueryResultTail> tails;
   late String contextData;
 
+  // why this result matched, shown as tooltip of the result
+  late String matchReason;
+
+  // the plugin which returned this result, group headers of plugin groups carry it too
+  late String pluginId;
+
+  // true if the plugin group of this result is collapsed, see WoxLauncherController.toggleResultGroup
+  late bool groupCollapsed;
+
   late List<WoxResultAction> actions;
+
+  // where title and subtitle are cut when they don't fit, see WoxResultTruncationEnum
+  late WoxResultTruncation titleTruncation;
+  late WoxResultTruncation subTitleTruncation;
+
+  // unix timestamp in milliseconds when the data of result was last updated, 0 if result is live
+  late int lastUpdated;
+
+  // icon size hint in logical pixels, always set by wox.core and bounded so it won't break the layout
+  late double iconSize;
+
+  // shown in the context menu on right click, executed by id the same way as actions
+  late List<WoxResultAction> contextActions;
+
+  // named previews of the result, preview of a tab is fetched when user opens it, see WoxLauncherController.switchPreviewTab
+  late List<WoxPreviewTab> previewTabs;
   late int refreshInterval;
 
+  // icon is a placeholder, the real icon is fetched when result scrolls into view, see WoxLauncherController.loadLazyIcon
+  late bool hasLazyIcon;
+
   // Used by the frontend to determine if this result is a group
   late bool isGroup;
 
@@ -121,7 +152,17 @@ class WoxQueryResult {
       required this.contextData,
       required this.actions,
       required this.refreshInterval,
-      required this.isGroup});
+      required this.isGroup,
+      this.matchReason = "",
+      this.pluginId = "",
+      this.groupCollapsed = false,
+      this.titleTruncation = "end",
+      this.subTitleTruncation = "end",
+      this.iconSize = RESULT_ITEM_DEFAULT_ICON_SIZE,
+      this.lastUpdated = 0,
+      this.contextActions = const [],
+      this.previewTabs = const [],
+      this.hasLazyIcon = false});
 
   WoxQueryResult.empty() {
     queryId = "";
@@ -135,8 +176,18 @@ class WoxQueryResult {
     groupScore = 0;
     tails = RxList<WoxQueryResultTail>();
     contextData = "";
+    matchReason = "";
+    pluginId = "";
+    groupCollapsed = false;
     actions = RxList<WoxResultAction>();
+    titleTruncation = WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_END.code;
+    subTitleTruncation = WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_END.code;
+    iconSize = RESULT_ITEM_DEFAULT_ICON_SIZE;
+    lastUpdated = 0;
+    contextActions = <WoxResultAction>[];
+    previewTabs = <WoxPreviewTab>[];
     refreshInterval = 0;
+    hasLazyIcon = false;
     isGroup = false;
   }
 
@@ -151,6 +202,9 @@ class WoxQueryResult {
     group = json['Group'];
     groupScore = json['GroupScore'];
     contextData = json['ContextData'];
+    matchReason = json['MatchReason'] ?? "";
+    pluginId = json['PluginId'] ?? "";
+    groupCollapsed = json['GroupCollapsed'] ?? false;
 
     if (json['Tails'] != null) {
       tails = RxList();
@@ -170,7 +224,34 @@ class WoxQueryResult {
       actions = RxList<WoxResultAction>();
     }
 
+    titleTruncation = json['TitleTruncation'] ?? "";
+    if (titleTruncation == "") {
+      titleTruncation = WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_END.code;
+    }
+    subTitleTruncation = json['SubTitleTruncation'] ?? "";
+    if (subTitleTruncation == "") {
+      subTitleTruncation = WoxResultTruncationEnum.WOX_RESULT_TRUNCATION_END.code;
+    }
+    iconSize = ((json['IconSize'] ?? 0) as num) > 0 ? (json['IconSize'] as num).toDouble() : RESULT_ITEM_DEFAULT_ICON_SIZE;
+
+    lastUpdated = json['LastUpdated'] ?? 0;
+
+    contextActions = <WoxResultAction>[];
+    if (json['ContextActions'] != null) {
+      json['ContextActions'].forEach((v) {
+        contextActions.add(WoxResultAction.fromJson(v));
+      });
+    }
+
+    previewTabs = <WoxPreviewTab>[];
+    if (json['PreviewTabs'] != null) {
+      json['PreviewTabs'].forEach((v) {
+        previewTabs.add(WoxPreviewTab.fromJson(v));
+      });
+    }
+
     refreshInterval = json['RefreshInterval'];
+    hasLazyIcon = json['HasLazyIcon'] ?? false;
     isGroup = false;
   }
 
@@ -186,13 +267,45 @@ class WoxQueryResult {
     data['Group'] = group;
     data['GroupScore'] = groupScore;
     data['ContextData'] = contextData;
+    data['MatchReason'] = matchReason;
+    data['PluginId'] = pluginId;
+    data['GroupCollapsed'] = groupCollapsed;
     data['Actions'] = actions.map((v) => v.toJson()).toList();
+    data['TitleTruncation'] = titleTruncation;
+    data['SubTitleTruncation'] = subTitleTruncation;
+    data['IconSize'] = iconSize;
+    data['LastUpdated'] = lastUpdated;
+    data['ContextActions'] = contextActions.map((v) => v.toJson()).toList();
+    data['PreviewTabs'] = previewTabs.map((v) => v.toJson()).toList();
     data['RefreshInterval'] = refreshInterval;
+    data['HasLazyIcon'] = hasLazyIcon;
     data['Tails'] = tails.map((v) => v.toJson()).toList();
     return data;
   }
 }
 
+class WoxPreviewTab {
+  late String id;
+  late String name;
+  late bool isDefault;
+
+  WoxPreviewTab({required this.id, required this.name, required this.isDefault});
+
+  WoxPreviewTab.fromJson(Map<String, dynamic> json) {
+    id = json['Id'];
+    name = json['Name'];
+    isDefault = json['IsDefault'] ?? false;
+  }
+
+  Map<String, dynamic> toJson() {
+    final Map<String, dynamic> data = <String, dynamic>{};
+    data['Id'] = id;
+    data['Name'] = name;
+    data['IsDefault'] = isDefault;
+    return data;
+  }
+}
+
 class WoxQueryResultTail {
   late String type;
   late String? text;
@@ -259,6 +372,28 @@ class WoxQueryResultTail {
   }
 }
 
+class WoxResultActionInput {
+  late String prompt;
+  late String placeholder;
+  late String defaultValue;
+
+  WoxResultActionInput({required this.prompt, required this.placeholder, required this.defaultValue});
+
+  WoxResultActionInput.fromJson(Map<String, dynamic> json) {
+    prompt = json['Prompt'] ?? "";
+    placeholder = json['Placeholder'] ?? "";
+    defaultValue = json['DefaultValue'] ?? "";
+  }
+
+  Map<String, dynamic> toJson() {
+    final Map<String, dynamic> data = <String, dynamic>{};
+    data['Prompt'] = prompt;
+    data['Placeholder'] = placeholder;
+    data['DefaultValue'] = defaultValue;
+    return data;
+  }
+}
+
 class WoxResultAction {
   late String id;
   late Rx<String> name;
@@ -266,9 +401,30 @@ class WoxResultAction {
   late bool isDefault;
   late bool preventHideAfterAction;
   late String hotkey;
+  late String hotkeyHint;
   late bool isSystemAction;
 
-  WoxResultAction({required this.id, required this.name, required this.icon, required this.isDefault, required this.preventHideAfterAction, required this.hotkey, required this.isSystemAction});
+  // modifier which runs this action when held with Enter, platform specific name, E.g. win or option
+  late String activationModifier;
+
+  // null if action doesn't require input, otherwise user must enter a value before action runs
+  WoxResultActionInput? input;
+
+  // true if action can run on multiple selected results at once
+  late bool isBatch;
+
+  WoxResultAction(
+      {required this.id,
+      required this.name,
+      required this.icon,
+      required this.isDefault,
+      required this.preventHideAfterAction,
+      required this.hotkey,
+      this.hotkeyHint = "",
+      required this.isSystemAction,
+      this.activationModifier = "",
+      this.input,
+      this.isBatch = false});
 
   WoxResultAction.fromJson(Map<String, dynamic> json) {
     id = json['Id'];
@@ -279,7 +435,11 @@ class WoxResultAction {
     if (json['Hotkey'] != null) {
       hotkey = json['Hotkey'];
     }
+    hotkeyHint = json['HotkeyHint'] ?? "";
     isSystemAction = json['IsSystemAction'];
+    activationModifier = json['ActivationModifier'] ?? "";
+    input = json['Input'] != null ? WoxResultActionInput.fromJson(json['Input']) : null;
+    isBatch = json['IsBatch'] ?? false;
   }
 
   Map<String, dynamic> toJson() {
@@ -290,7 +450,11 @@ class WoxResultAction {
     data['IsDefault'] = isDefault;
     data['PreventHideAfterAction'] = preventHideAfterAction;
     data['Hotkey'] = hotkey;
+    data['HotkeyHint'] = hotkeyHint;
     data['IsSystemAction'] = isSystemAction;
+    data['ActivationModifier'] = activationModifier;
+    data['Input'] = input?.toJson();
+    data['IsBatch'] = isBatch;
     return data;
   }
 
@@ -305,7 +469,9 @@ class WoxResultAction {
         isDefault == other.isDefault &&
         preventHideAfterAction == other.preventHideAfterAction &&
         hotkey == other.hotkey &&
-        isSystemAction == other.isSystemAction;
+        hotkeyHint == other.hotkeyHint &&
+        isSystemAction == other.isSystemAction &&
+        activationModifier == other.activationModifier;
   }
 
   static bool listEquals(List<WoxResultAction> actions1, List<WoxResultAction> actions2) {
@@ -336,8 +502,9 @@ class ShowAppParams {
   late Position position;
   late List<QueryHistory> queryHistories;
   late WoxLastQueryMode lastQueryMode;
+  late WoxEscapeMode escapeMode;
 
-  ShowAppParams({required this.selectAll, required this.position, required this.queryHistories, required this.lastQueryMode});
+  ShowAppParams({required this.selectAll, required this.position, required this.queryHistories, required this.lastQueryMode, required this.escapeMode});
 
   ShowAppParams.fromJson(Map<String, dynamic> json) {
     selectAll = json['SelectAll'];
@@ -353,6 +520,7 @@ class ShowAppParams {
       queryHistories = <QueryHistory>[];
     }
     lastQueryMode = json['LastQueryMode'];
+    escapeMode = json['EscapeMode'] ?? WoxEscapeModeEnum.WOX_ESCAPE_MODE_HIDE.code;
   }
 }
 
@@ -18,6 +18,9 @@ class WoxSetting {
   late bool httpProxyEnabled;
   late String httpProxyUrl;
   late bool enableAutoBackup;
+  late bool enablePreviewPeek;
+  late String backgroundActionModifier;
+  late bool hideActionHotkeyHints;
 
   WoxSetting({
     required this.enableAutostart,
@@ -39,6 +42,9 @@ class WoxSetting {
     required this.httpProxyEnabled,
     required this.httpProxyUrl,
     required this.enableAutoBackup,
+    required this.enablePreviewPeek,
+    required this.backgroundActionModifier,
+    required this.hideActionHotkeyHints,
   });
 
   WoxSetting.fromJson(Map<String, dynamic> json) {
@@ -87,6 +93,9 @@ class WoxSetting {
     httpProxyEnabled = json['HttpProxyEnabled'] ?? false;
     httpProxyUrl = json['HttpProxyUrl'] ?? '';
     enableAutoBackup = json['EnableAutoBackup'] ?? false;
+    enablePreviewPeek = json['EnablePreviewPeek'] ?? false;
+    backgroundActionModifier = json['BackgroundActionModifier'] ?? 'none';
+    hideActionHotkeyHints = json['HideActionHotkeyHints'] ?? false;
   }
 
   Map<String, dynamic> toJson() {
@@ -110,6 +119,9 @@ class WoxSetting {
     data['HttpProxyEnabled'] = httpProxyEnabled;
     data['HttpProxyUrl'] = httpProxyUrl;
     data['EnableAutoBackup'] = enableAutoBackup;
+    data['EnablePreviewPeek'] = enablePreviewPeek;
+    data['BackgroundActionModifier'] = backgroundActionModifier;
+    data['HideActionHotkeyHints'] = hideActionHotkeyHints;
     return data;
   }
 }
@@ -121,12 +133,15 @@ class QueryHotkey {
 
   late bool isSilentExecution;
 
-  QueryHotkey({required this.hotkey, required this.query, required this.isSilentExecution});
+  late String headlessAction; // "<plugin id or name>:<action name>", executed instead of the query if not empty
+
+  QueryHotkey({required this.hotkey, required this.query, required this.isSilentExecution, this.headlessAction = ""});
 
   QueryHotkey.fromJson(Map<String, dynamic> json) {
     hotkey = json['Hotkey'];
     query = json['Query'];
     isSilentExecution = json['IsSilentExecution'] ?? false;
+    headlessAction = json['HeadlessAction'] ?? "";
   }
 
   Map<String, dynamic> toJson() {
@@ -134,6 +149,7 @@ class QueryHotkey {
     data['Hotkey'] = hotkey;
     data['Query'] = query;
     data['IsSilentExecution'] = isSilentExecution;
+    data['HeadlessAction'] = headlessAction;
     return data;
   }
 }
@@ -34,6 +34,63 @@ class ToolbarInfo {
   }
 }
 
+/// Persistent status of a plugin shown in the toolbar until it's cleared, E.g. "syncing 3/10".
+/// wox.core keeps a stack of statuses and always sends the latest one, see SetStatus in wox.core
+class StatusMsg {
+  final String pluginId;
+  final WoxImage? icon;
+  final String text;
+
+  StatusMsg({
+    this.pluginId = '',
+    this.icon,
+    this.text = '',
+  });
+
+  static StatusMsg fromJson(Map<String, dynamic> json) {
+    return StatusMsg(
+      pluginId: json['PluginId'] ?? '',
+      icon: WoxImage.parse(json['Icon'] ?? ''),
+      text: json['Text'] ?? '',
+    );
+  }
+}
+
+/// Progress of a plugin which is still querying, see QueryProgress in wox.core.
+/// Done with empty pluginId clears progress of all plugins of the query
+class QueryProgress {
+  final String queryId;
+  final String pluginId;
+  final int progress; // 0-100, negative means indeterminate
+  final String text;
+  final bool done;
+
+  QueryProgress({
+    required this.queryId,
+    required this.pluginId,
+    required this.progress,
+    required this.text,
+    required this.done,
+  });
+
+  static QueryProgress fromJson(Map<String, dynamic> json) {
+    return QueryProgress(
+      queryId: json['QueryId'] ?? '',
+      pluginId: json['PluginId'] ?? '',
+      progress: json['Progress'] ?? -1,
+      text: json['Text'] ?? '',
+      done: json['Done'] ?? false,
+    );
+  }
+
+  String toDisplayText() {
+    if (progress < 0) {
+      return text;
+    }
+    return text.isEmpty ? "$progress%" : "$text $progress%";
+  }
+}
+
 class ToolbarMsg {
   final WoxImage? icon;
   final String? text;
@@ -0,0 +1,16 @@
+typedef WoxEscapeMode = String;
+
+enum WoxEscapeModeEnum {
+  WOX_ESCAPE_MODE_HIDE("hide", "hide"),
+  // first escape clears the query box, next escape hides wox
+  WOX_ESCAPE_MODE_CLEAR_THEN_HIDE("clear_then_hide", "clear_then_hide"),
+  // first escape hides wox, escape after shown again with the same query clears it
+  WOX_ESCAPE_MODE_HIDE_THEN_CLEAR("hide_then_clear", "hide_then_clear");
+
+  final String code;
+  final String value;
+
+  const WoxEscapeModeEnum(this.code, this.value);
+
+  static String getValue(String code) => WoxEscapeModeEnum.values.firstWhere((activity) => activity.code == code).value;
+}
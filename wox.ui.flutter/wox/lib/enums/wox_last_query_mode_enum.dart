@@ -2,7 +2,9 @@ typedef WoxLastQueryMode = String;
 
 enum WoxLastQueryModeEnum {
   WOX_LAST_QUERY_MODE_PRESERVE("preserve", "preserve"),
-  WOX_LAST_QUERY_MODE_EMPTY("empty", "empty");
+  WOX_LAST_QUERY_MODE_EMPTY("empty", "empty"),
+  // query box is kept when hidden, wox restores the last query when shown (see LastQueryModeRestore in wox.core)
+  WOX_LAST_QUERY_MODE_RESTORE("restore", "restore");
 
   final String code;
   final String value;
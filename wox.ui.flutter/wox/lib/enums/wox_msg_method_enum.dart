@@ -4,8 +4,14 @@ enum WoxMsgMethodEnum {
   WOX_MSG_METHOD_Log("Log", "Log"),
   WOX_MSG_METHOD_QUERY("Query", "Query"),
   WOX_MSG_METHOD_ACTION("Action", "Action"),
+  WOX_MSG_METHOD_BATCH_ACTION("BatchAction", "Batch action"),
   WOX_MSG_METHOD_REFRESH("Refresh", "Refresh"),
-  WOX_MSG_METHOD_VISIBILITY_CHANGED("VisibilityChanged", "Visibility changed");
+  WOX_MSG_METHOD_ESCAPE("Escape", "Escape"),
+  WOX_MSG_METHOD_VISIBILITY_CHANGED("VisibilityChanged", "Visibility changed"),
+  WOX_MSG_METHOD_SET_RESULT_GROUP_COLLAPSED("SetResultGroupCollapsed", "Set result group collapsed"),
+  WOX_MSG_METHOD_VALIDATE_ACTION_INPUT("ValidateActionInput", "Validate action input"),
+  WOX_MSG_METHOD_CANCEL_ACTION_INPUT("CancelActionInput", "Cancel action input"),
+  WOX_MSG_METHOD_FOCUS_PREVIEW("FocusPreview", "Focus preview");
 
   final String code;
   final String value;
@@ -6,7 +6,8 @@ enum WoxPreviewTypeEnum {
   WOX_PREVIEW_TYPE_IMAGE("image", "image"),
   WOX_PREVIEW_TYPE_URL("url", "url"),
   WOX_PREVIEW_TYPE_FILE("file", "file"),
-  WOX_PREVIEW_TYPE_REMOTE("remote", "remote");
+  WOX_PREVIEW_TYPE_REMOTE("remote", "remote"),
+  WOX_PREVIEW_TYPE_STREAM("stream", "stream");
 
   final String code;
   final String value;
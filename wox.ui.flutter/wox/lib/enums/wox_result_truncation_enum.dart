@@ -0,0 +1,14 @@
+typedef WoxResultTruncation = String;
+
+enum WoxResultTruncationEnum {
+  WOX_RESULT_TRUNCATION_END("end", "end"),
+  WOX_RESULT_TRUNCATION_START("start", "start"),
+  WOX_RESULT_TRUNCATION_MIDDLE("middle", "middle");
+
+  final String code;
+  final String value;
+
+  const WoxResultTruncationEnum(this.code, this.value);
+
+  static String getValue(String code) => WoxResultTruncationEnum.values.firstWhere((activity) => activity.code == code).value;
+}
@@ -84,6 +84,7 @@ class MyApp extends StatelessWidget {
   @override
   Widget build(BuildContext context) {
     return MaterialApp(
+      navigatorKey: Get.find<WoxLauncherController>().navigatorKey,
       theme: ThemeData(
         useMaterial3: true,
         textTheme: SystemChineseFont.textTheme(Brightness.light),
@@ -37,7 +37,7 @@ class WoxLauncherView extends GetView<WoxLauncherController> {
                   ),
                 ),
               ),
-              if (controller.toolbar.value.isNotEmpty())
+              if (controller.isShowToolbar())
                 const SizedBox(
                   height: 40,
                   child: WoxQueryToolbarView(),
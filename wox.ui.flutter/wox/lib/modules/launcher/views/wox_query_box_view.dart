@@ -5,6 +5,7 @@ import 'package:get/get.dart';
 import 'package:uuid/v4.dart';
 import 'package:wox/components/wox_image_view.dart';
 import 'package:wox/entity/wox_hotkey.dart';
+import 'package:wox/enums/wox_direction_enum.dart';
 import 'package:wox/modules/launcher/wox_launcher_controller.dart';
 import 'package:wox/utils/log.dart';
 
@@ -26,7 +27,7 @@ class WoxQueryBoxView extends GetView<WoxLauncherController> {
                     if (event is KeyDownEvent) {
                       switch (event.logicalKey) {
                         case LogicalKeyboardKey.escape:
-                          controller.hideApp(const UuidV4().generate());
+                          controller.onEscape(const UuidV4().generate());
                           return KeyEventResult.handled;
                         case LogicalKeyboardKey.arrowDown:
                           controller.handleQueryBoxArrowDown();
@@ -61,6 +62,23 @@ class WoxQueryBoxView extends GetView<WoxLauncherController> {
                     }
                   }
 
+                  // shift+arrow selects multiple results, batch actions run on all of them, see WoxLauncherController.handleQueryBoxShiftArrow
+                  if ((event is KeyDownEvent || event is KeyRepeatEvent) && controller.isOnlyModifierPressed("shift")) {
+                    if (event.logicalKey == LogicalKeyboardKey.arrowDown) {
+                      controller.handleQueryBoxShiftArrow(const UuidV4().generate(), WoxDirectionEnum.WOX_DIRECTION_DOWN.code);
+                      return KeyEventResult.handled;
+                    }
+                    if (event.logicalKey == LogicalKeyboardKey.arrowUp) {
+                      controller.handleQueryBoxShiftArrow(const UuidV4().generate(), WoxDirectionEnum.WOX_DIRECTION_UP.code);
+                      return KeyEventResult.handled;
+                    }
+                  }
+
+                  // Enter with modifiers, E.g. open in background, see WoxLauncherController.onModifierEnter
+                  if (event is KeyDownEvent && event.logicalKey == LogicalKeyboardKey.enter && controller.onModifierEnter(const UuidV4().generate())) {
+                    return KeyEventResult.handled;
+                  }
+
                   var pressedHotkey = WoxHotkey.parseHotkeyFromEvent(event);
                   if (pressedHotkey == null) {
                     return KeyEventResult.ignored;
@@ -80,6 +98,12 @@ class WoxQueryBoxView extends GetView<WoxLauncherController> {
                     return KeyEventResult.handled;
                   }
 
+                  // modifier+Enter runs the default action with the held modifiers
+                  if (event is KeyDownEvent && event.logicalKey == LogicalKeyboardKey.enter) {
+                    controller.onEnterWithModifiers(const UuidV4().generate());
+                    return KeyEventResult.handled;
+                  }
+
                   return KeyEventResult.ignored;
                 },
                 child: SizedBox(
@@ -1,3 +1,4 @@
+import 'package:dio/dio.dart';
 import 'package:flutter/gestures.dart';
 import 'package:flutter/material.dart';
 import 'package:flutter/services.dart';
@@ -19,9 +20,10 @@ import '../wox_launcher_controller.dart';
 class WoxQueryResultView extends GetView<WoxLauncherController> {
   const WoxQueryResultView({super.key});
 
+  // hint is empty if user hides hotkey hints, hotkey still works in that case
   RxList<WoxQueryResultTail> getHotkeyTails(WoxResultAction action) {
     var tails = <WoxQueryResultTail>[];
-    if (action.hotkey != "") {
+    if (action.hotkey != "" && action.hotkeyHint != "") {
       var hotkey = WoxHotkey.parseHotkeyFromString(action.hotkey);
       if (hotkey != null) {
         tails.add(WoxQueryResultTail.hotkey(hotkey));
@@ -125,63 +127,93 @@ class WoxQueryResultView extends GetView<WoxLauncherController> {
                 ),
                 child: Scrollbar(
                   controller: controller.resultScrollerController,
-                  child: Listener(
-                    onPointerSignal: (event) {
-                      if (event is PointerScrollEvent) {
-                        controller.changeResultScrollPosition(
-                          const UuidV4().generate(),
-                          WoxEventDeviceTypeEnum.WOX_EVENT_DEVEICE_TYPE_MOUSE.code,
-                          event.scrollDelta.dy > 0 ? WoxDirectionEnum.WOX_DIRECTION_DOWN.code : WoxDirectionEnum.WOX_DIRECTION_UP.code,
-                        );
-                      }
-                    },
-                    child: ListView.builder(
-                      shrinkWrap: true,
-                      physics: const NeverScrollableScrollPhysics(),
-                      controller: controller.resultScrollerController,
-                      itemCount: controller.results.length,
-                      itemExtent: WoxThemeUtil.instance.getResultListViewHeightByCount(1),
-                      itemBuilder: (context, index) {
-                        WoxQueryResult woxQueryResult = controller.getQueryResultByIndex(index);
-                        return MouseRegion(
-                          onEnter: (_) {
-                            if (controller.isMouseMoved && !woxQueryResult.isGroup) {
-                              controller.setActiveResultIndex(index);
-                            }
-                          },
-                          onHover: (_) {
-                            if (!controller.isMouseMoved && !woxQueryResult.isGroup) {
-                              controller.isMouseMoved = true;
-                              controller.setActiveResultIndex(index);
-                            }
-                          },
-                          child: GestureDetector(
-                            onTap: () {
-                              if (!woxQueryResult.isGroup) {
-                                // request focus to action query box since it will lose focus when tap
-                                controller.queryBoxFocusNode.requestFocus();
+                  child: MouseRegion(
+                    onExit: (_) => controller.endPeek(),
+                    child: Listener(
+                      onPointerSignal: (event) {
+                        if (event is PointerScrollEvent) {
+                          controller.changeResultScrollPosition(
+                            const UuidV4().generate(),
+                            WoxEventDeviceTypeEnum.WOX_EVENT_DEVEICE_TYPE_MOUSE.code,
+                            event.scrollDelta.dy > 0 ? WoxDirectionEnum.WOX_DIRECTION_DOWN.code : WoxDirectionEnum.WOX_DIRECTION_UP.code,
+                          );
+                        }
+                      },
+                      child: ListView.builder(
+                        shrinkWrap: true,
+                        physics: const NeverScrollableScrollPhysics(),
+                        controller: controller.resultScrollerController,
+                        itemCount: controller.results.length,
+                        itemExtentBuilder: (index, _) => index < controller.results.length ? controller.getResultItemHeightByIndex(index) : null,
+                        itemBuilder: (context, index) {
+                          WoxQueryResult woxQueryResult = controller.getQueryResultByIndex(index);
+                          return MouseRegion(
+                            onEnter: (_) {
+                              if (controller.isMouseMoved && !woxQueryResult.isGroup) {
+                                hoverResult(index);
                               }
                             },
-                            onDoubleTap: () {
-                              if (!woxQueryResult.isGroup) {
-                                controller.onEnter(const UuidV4().generate());
-                                controller.queryBoxFocusNode.requestFocus();
+                            onHover: (_) {
+                              if (!controller.isMouseMoved && !woxQueryResult.isGroup) {
+                                controller.isMouseMoved = true;
+                                hoverResult(index);
                               }
                             },
-                            child: WoxListItemView(
-                              key: controller.getResultItemGlobalKeyByIndex(index),
-                              woxTheme: controller.woxTheme.value,
-                              icon: woxQueryResult.icon,
-                              title: woxQueryResult.title,
-                              tails: woxQueryResult.tails,
-                              subTitle: woxQueryResult.subTitle,
-                              isActive: controller.isResultActiveByIndex(index),
-                              listViewType: WoxListViewTypeEnum.WOX_LIST_VIEW_TYPE_RESULT.code,
-                              isGroup: woxQueryResult.isGroup,
+                            child: GestureDetector(
+                              onTap: () {
+                                if (!woxQueryResult.isGroup && (HardwareKeyboard.instance.isMetaPressed || HardwareKeyboard.instance.isControlPressed)) {
+                                  // cmd/ctrl+click selects multiple results, batch actions run on all of them
+                                  controller.toggleResultSelection(const UuidV4().generate(), index);
+                                  controller.queryBoxFocusNode.requestFocus();
+                                } else if (!woxQueryResult.isGroup) {
+                                  // hover only peeks the preview when preview peek is enabled, tap focuses the result
+                                  if (controller.isPreviewPeekEnabled()) {
+                                    controller.setActiveResultIndex(index);
+                                  }
+                                  // request focus to action query box since it will lose focus when tap
+                                  controller.queryBoxFocusNode.requestFocus();
+                                } else {
+                                  controller.toggleResultGroup(const UuidV4().generate(), woxQueryResult);
+                                  controller.queryBoxFocusNode.requestFocus();
+                                }
+                              },
+                              onDoubleTap: () {
+                                if (!woxQueryResult.isGroup) {
+                                  controller.onEnter(const UuidV4().generate());
+                                  controller.queryBoxFocusNode.requestFocus();
+                                }
+                              },
+                              onSecondaryTapDown: (details) {
+                                if (!woxQueryResult.isGroup && woxQueryResult.contextActions.isNotEmpty) {
+                                  showContextActionMenu(context, woxQueryResult, details.globalPosition);
+                                }
+                              },
+                              child: wrapMatchReasonTooltip(
+                                woxQueryResult,
+                                WoxLazyIconLoader(
+                                  result: woxQueryResult,
+                                  child: WoxListItemView(
+                                    key: controller.getResultItemGlobalKeyByIndex(index),
+                                    woxTheme: controller.woxTheme.value,
+                                    icon: woxQueryResult.icon,
+                                    title: woxQueryResult.title,
+                                    tails: woxQueryResult.tails,
+                                    subTitle: woxQueryResult.subTitle,
+                                    isActive: controller.isResultActiveByIndex(index),
+                                    isSelected: controller.isResultSelectedByIndex(index),
+                                    listViewType: WoxListViewTypeEnum.WOX_LIST_VIEW_TYPE_RESULT.code,
+                                    isGroup: woxQueryResult.isGroup,
+                                    iconSize: woxQueryResult.iconSize,
+                                    titleTruncation: woxQueryResult.titleTruncation,
+                                    subTitleTruncation: woxQueryResult.subTitleTruncation,
+                                    lastUpdated: woxQueryResult.lastUpdated,
+                                  ),
+                                ),
+                              ),
                             ),
-                          ),
-                        );
-                      },
+                          );
+                        },
+                      ),
                     ),
                   ),
                 ),
@@ -191,21 +223,111 @@ class WoxQueryResultView extends GetView<WoxLauncherController> {
     );
   }
 
+  /// Show context actions of the result at mouse position, the picked action is executed by id the same way as actions
+  Future<void> showContextActionMenu(BuildContext context, WoxQueryResult woxQueryResult, Offset position) async {
+    final overlay = Overlay.of(context).context.findRenderObject() as RenderBox;
+    final action = await showMenu<WoxResultAction>(
+      context: context,
+      position: RelativeRect.fromRect(position & const Size(1, 1), Offset.zero & overlay.size),
+      color: fromCssColor(controller.woxTheme.value.actionContainerBackgroundColor),
+      items: woxQueryResult.contextActions
+          .map((action) => PopupMenuItem<WoxResultAction>(
+                value: action,
+                height: 32,
+                child: Text(action.name.value, style: TextStyle(color: fromCssColor(controller.woxTheme.value.actionItemFontColor), fontSize: 14)),
+              ))
+          .toList(),
+    );
+
+    if (action != null) {
+      controller.executeAction(const UuidV4().generate(), woxQueryResult, action);
+    }
+    controller.queryBoxFocusNode.requestFocus();
+  }
+
+  /// Show why the result matched as tooltip when mouse rests on it, it's kept out of the result list itself
+  Widget wrapMatchReasonTooltip(WoxQueryResult woxQueryResult, Widget child) {
+    if (woxQueryResult.isGroup || woxQueryResult.matchReason.isEmpty) {
+      return child;
+    }
+
+    return Tooltip(
+      message: woxQueryResult.matchReason,
+      waitDuration: const Duration(milliseconds: 800),
+      child: child,
+    );
+  }
+
+  void hoverResult(int index) {
+    if (controller.isPreviewPeekEnabled()) {
+      controller.peekResult(index);
+    } else {
+      controller.setActiveResultIndex(index);
+    }
+  }
+
   Widget getPreviewView() {
     if (LoggerSwitch.enablePaintLog) Logger.instance.info(const UuidV4().generate(), "repaint: preview view container");
 
     return Obx(
-      () => controller.isShowPreviewPanel.value
+      () => controller.isShowPreviewPanel.value || controller.peekPreview.value.previewData != ""
           ? Expanded(
-              child: WoxPreviewView(
-                woxPreview: controller.currentPreview.value,
-                woxTheme: controller.woxTheme.value,
+              child: Column(
+                children: [
+                  if (controller.peekPreview.value.previewData == "") getPreviewTabBar(),
+                  Expanded(
+                    child: WoxPreviewView(
+                      woxPreview: controller.peekPreview.value.previewData != "" ? controller.peekPreview.value : controller.currentPreview.value,
+                      woxTheme: controller.woxTheme.value,
+                    ),
+                  ),
+                ],
               ),
             )
           : const SizedBox(),
     );
   }
 
+  /// Tabs of the focused result, only shown if the result has more than one preview tab
+  Widget getPreviewTabBar() {
+    if (controller.activeResultIndex.value >= controller.results.length) {
+      return const SizedBox();
+    }
+    final result = controller.results[controller.activeResultIndex.value];
+    if (result.previewTabs.length < 2) {
+      return const SizedBox();
+    }
+
+    return Padding(
+      padding: const EdgeInsets.only(bottom: 6.0),
+      child: SingleChildScrollView(
+        scrollDirection: Axis.horizontal,
+        child: Row(
+          children: result.previewTabs.map((tab) {
+            final isActive = controller.activePreviewTabId.value == tab.id;
+            return GestureDetector(
+              onTap: () => controller.switchPreviewTab(const UuidV4().generate(), result.id, tab),
+              child: Container(
+                padding: const EdgeInsets.symmetric(horizontal: 10.0, vertical: 4.0),
+                decoration: BoxDecoration(
+                  color: isActive ? fromCssColor(controller.woxTheme.value.resultItemActiveBackgroundColor) : Colors.transparent,
+                  borderRadius: BorderRadius.circular(4.0),
+                ),
+                child: Text(
+                  tab.name,
+                  style: TextStyle(
+                    color: fromCssColor(isActive ? controller.woxTheme.value.resultItemActiveTitleColor : controller.woxTheme.value.previewFontColor),
+                    fontSize: 13,
+                  ),
+                ),
+              ),
+            );
+          }).toList(),
+        ),
+      ),
+    );
+  }
+
 // Action Query Box
   Widget getActionQueryBox() {
     return Focus(
@@ -319,3 +441,55 @@ class WoxQueryResultView extends GetView<WoxLauncherController> {
     );
   }
 }
+
+/// Loads the real icon of a result with lazy icon while the result is in view, loading is cancelled when the result scrolls away
+class WoxLazyIconLoader extends StatefulWidget {
+  final WoxQueryResult result;
+  final Widget child;
+
+  const WoxLazyIconLoader({super.key, required this.result, required this.child});
+
+  @override
+  State<WoxLazyIconLoader> createState() => _WoxLazyIconLoaderState();
+}
+
+class _WoxLazyIconLoaderState extends State<WoxLazyIconLoader> {
+  CancelToken? cancelToken;
+
+  @override
+  void initState() {
+    super.initState();
+    load();
+  }
+
+  @override
+  void didUpdateWidget(covariant WoxLazyIconLoader oldWidget) {
+    super.didUpdateWidget(oldWidget);
+    // list items are reused when results change, icon of the previous result is not needed anymore
+    if (oldWidget.result.id != widget.result.id) {
+      cancelToken?.cancel();
+      load();
+    }
+  }
+
+  @override
+  void dispose() {
+    cancelToken?.cancel();
+    super.dispose();
+  }
+
+  void load() {
+    cancelToken = null;
+    if (!widget.result.hasLazyIcon) {
+      return;
+    }
+
+    cancelToken = CancelToken();
+    Get.find<WoxLauncherController>().loadLazyIcon(widget.result, cancelToken!);
+  }
+
+  @override
+  Widget build(BuildContext context) {
+    return widget.child;
+  }
+}
@@ -6,6 +6,7 @@ import 'package:uuid/v4.dart';
 import 'package:wox/components/wox_hotkey_view.dart';
 import 'package:wox/components/wox_image_view.dart';
 import 'package:wox/entity/wox_hotkey.dart';
+import 'package:wox/entity/wox_toolbar.dart';
 import 'package:wox/modules/launcher/wox_launcher_controller.dart';
 import 'package:wox/utils/log.dart';
 import 'package:wox/utils/wox_theme_util.dart';
@@ -17,7 +18,16 @@ class WoxQueryToolbarView extends GetView<WoxLauncherController> {
     if (LoggerSwitch.enablePaintLog) Logger.instance.debug(const UuidV4().generate(), "repaint: toolbar view - left part");
 
     return Obx(() {
-      final toolbarInfo = controller.toolbar.value;
+      var toolbarInfo = controller.toolbar.value;
+      final status = controller.status.value;
+      // toolbar message wins over query progress and status, they show again once the message is gone
+      if (toolbarInfo.text == null || toolbarInfo.text!.isEmpty) {
+        if (controller.results.isEmpty && controller.queryProgress.isNotEmpty) {
+          toolbarInfo = ToolbarInfo(text: controller.queryProgress.values.map((progress) => progress.toDisplayText()).join("  "));
+        } else if (status != null) {
+          toolbarInfo = ToolbarInfo(icon: status.icon, text: status.text);
+        }
+      }
       return SizedBox(
         width: 550,
         child: Row(
@@ -1,8 +1,10 @@
 import 'dart:async';
 import 'dart:io';
+import 'dart:math';
 import 'dart:ui';
 
 import 'package:desktop_drop/desktop_drop.dart';
+import 'package:dio/dio.dart';
 import 'package:flutter/material.dart';
 import 'package:get/get.dart';
 import 'package:hotkey_manager/hotkey_manager.dart';
@@ -10,6 +12,9 @@ import 'package:lpinyin/lpinyin.dart';
 import 'package:uuid/v4.dart';
 import 'package:wox/utils/windows/window_manager.dart';
 import 'package:wox/api/wox_api.dart';
+import 'package:wox/components/wox_action_input_view.dart';
+import 'package:wox/components/wox_dialog_view.dart';
+import 'package:wox/entity/wox_dialog.dart';
 import 'package:wox/entity/wox_hotkey.dart';
 import 'package:wox/entity/wox_image.dart';
 import 'package:wox/entity/wox_preview.dart';
@@ -19,6 +24,7 @@ import 'package:wox/entity/wox_theme.dart';
 import 'package:wox/entity/wox_toolbar.dart';
 import 'package:wox/entity/wox_websocket_msg.dart';
 import 'package:wox/enums/wox_direction_enum.dart';
+import 'package:wox/enums/wox_escape_mode_enum.dart';
 import 'package:wox/enums/wox_event_device_type_enum.dart';
 import 'package:wox/enums/wox_image_type_enum.dart';
 import 'package:wox/enums/wox_last_query_mode_enum.dart';
@@ -31,6 +37,7 @@ import 'package:wox/modules/setting/wox_setting_controller.dart';
 import 'package:wox/utils/consts.dart';
 import 'package:wox/utils/log.dart';
 import 'package:wox/utils/picker.dart';
+import 'package:wox/utils/wox_http_util.dart';
 import 'package:wox/utils/wox_setting_util.dart';
 import 'package:wox/utils/wox_theme_util.dart';
 import 'package:wox/utils/wox_websocket_msg_util.dart';
@@ -48,6 +55,10 @@ class WoxLauncherController extends GetxController {
   final currentPreview = WoxPreview.empty().obs;
   final isShowPreviewPanel = false.obs;
 
+  /// The preview of the result under mouse cursor, shown in place of [currentPreview] while hovering, see [peekResult].
+  final peekPreview = WoxPreview.empty().obs;
+  CancelToken? peekCancelToken;
+
   // result related variables
   /// The list of query results.
   final results = <WoxQueryResult>[].obs;
@@ -55,6 +66,12 @@ class WoxLauncherController extends GetxController {
   final resultGlobalKeys = <GlobalKey>[]; // the global keys for each result item, used to calculate the position of the result item
   final resultScrollerController = ScrollController(initialScrollOffset: 0.0);
   final originalResults = <WoxQueryResult>[]; // the original results, used to filter and restore selection results
+  final collapsedGroupResults = <WoxQueryResult>[]; // results of collapsed plugin groups, not shown until the group is expanded
+  final selectedResultIds = <String>[].obs; // results selected with shift+arrow or cmd/ctrl+click, in selection order, batch actions run on them
+
+  /// Estimated result count of the current query sent by wox.core before results arrive, 0 if unknown or query is done.
+  /// Window height is reserved for it so the window won't grow batch by batch, see [onQueryResultCountHint]
+  var expectedResultCount = 0;
 
   /// The timer to clear query results.
   /// On every query changed, it will reset the timer and will clear the query results after N ms.
@@ -79,6 +96,11 @@ class WoxLauncherController extends GetxController {
   final Rx<WoxTheme> woxTheme = WoxThemeUtil.instance.currentTheme.obs;
   var refreshCounter = 0;
   var lastQueryMode = WoxLastQueryModeEnum.WOX_LAST_QUERY_MODE_PRESERVE.code;
+  var escapeMode = WoxEscapeModeEnum.WOX_ESCAPE_MODE_HIDE.code;
+
+  // navigator of the launcher, used to show dialogs requested by wox.core
+  final navigatorKey = GlobalKey<NavigatorState>();
+  String? openDialogId;
   final isInSettingView = false.obs;
   var positionBeforeOpenSetting = const Offset(0, 0);
 
@@ -91,6 +113,10 @@ class WoxLauncherController extends GetxController {
   // toolbar related variables
   final toolbar = ToolbarInfo.empty().obs;
   final toolbarCopyText = 'Copy'.obs;
+  // status set by plugins, shown in the toolbar when there is no toolbar message
+  final status = Rx<StatusMsg?>(null);
+  // progress of plugins still querying the current query by plugin id, shown in the toolbar until results arrive
+  final queryProgress = <String, QueryProgress>{}.obs;
   // The timer to clean the toolbar when query changed
   // on every query changed, it will reset the timer and will clear the toolbar after N ms
   // If there is no this delay mechanism, the toolbar will flicker for fast typing
@@ -101,6 +127,124 @@ class WoxLauncherController extends GetxController {
   /// This is used to prevent the result item from being selected when the mouse is just hovering over the item in the result list.
   var isMouseMoved = false;
 
+  /// The result whose preview is visible, wox.core keeps updating its preview while it's focused, see [updatePreview].
+  var focusedPreviewResultId = "";
+  CancelToken? enrichPreviewCancelToken;
+
+  /// The opened preview tab of the focused result, empty if the result has no preview tabs, see [switchPreviewTab].
+  final activePreviewTabId = "".obs;
+  CancelToken? previewTabCancelToken;
+
+  @override
+  void onInit() {
+    super.onInit();
+    ever(currentPreview, (_) => focusPreview());
+    ever(isShowPreviewPanel, (_) => focusPreview());
+  }
+
+  /// Tell wox.core which result's preview is visible, empty if no preview is visible
+  void focusPreview() {
+    var resultId = "";
+    if (isShowPreviewPanel.value && activeResultIndex.value < results.length) {
+      resultId = results[activeResultIndex.value].id;
+    }
+    if (resultId == focusedPreviewResultId) {
+      return;
+    }
+
+    focusedPreviewResultId = resultId;
+    resetPreviewTab();
+    WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+      requestId: const UuidV4().generate(),
+      traceId: const UuidV4().generate(),
+      type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+      method: WoxMsgMethodEnum.WOX_MSG_METHOD_FOCUS_PREVIEW.code,
+      data: {"resultId": resultId},
+    ));
+    enrichPreview(resultId);
+  }
+
+  /// Fetch the preview of the focused result with contents of preview enrichers appended, the plain preview is shown meanwhile.
+  /// Enriching the previous result is cancelled, so wox.core stops enrichers of a result user has moved away from
+  Future<void> enrichPreview(String resultId) async {
+    enrichPreviewCancelToken?.cancel();
+    enrichPreviewCancelToken = null;
+    if (resultId == "") {
+      return;
+    }
+
+    final cancelToken = CancelToken();
+    enrichPreviewCancelToken = cancelToken;
+    try {
+      final preview = await WoxHttpUtil.instance.getData<WoxPreview>("/preview/enriched", params: {"id": resultId}, cancelToken: cancelToken);
+      if (enrichPreviewCancelToken == cancelToken && focusedPreviewResultId == resultId) {
+        currentPreview.value = preview;
+      }
+    } catch (e) {
+      // enriching is cancelled or failed, keep showing the plain preview
+    }
+  }
+
+  /// Result preview shows the default tab, other tabs of the previous result are not needed anymore
+  void resetPreviewTab() {
+    previewTabCancelToken?.cancel();
+    previewTabCancelToken = null;
+    activePreviewTabId.value = "";
+    if (focusedPreviewResultId == "" || activeResultIndex.value >= results.length) {
+      return;
+    }
+
+    final tabs = results[activeResultIndex.value].previewTabs;
+    if (tabs.isNotEmpty) {
+      activePreviewTabId.value = tabs.firstWhere((tab) => tab.isDefault, orElse: () => tabs.first).id;
+    }
+  }
+
+  /// Open a preview tab of the focused result, loading the previous tab is cancelled so wox.core stops computing a tab user has left
+  Future<void> switchPreviewTab(String traceId, String resultId, WoxPreviewTab tab) async {
+    if (focusedPreviewResultId != resultId || activePreviewTabId.value == tab.id) {
+      return;
+    }
+
+    Logger.instance.debug(traceId, "switch preview tab: ${tab.name}");
+    previewTabCancelToken?.cancel();
+    final cancelToken = CancelToken();
+    previewTabCancelToken = cancelToken;
+    activePreviewTabId.value = tab.id;
+    try {
+      final preview = await WoxHttpUtil.instance.getData<WoxPreview>("/preview/tab", params: {"id": resultId, "tabId": tab.id}, cancelToken: cancelToken);
+      if (previewTabCancelToken == cancelToken && focusedPreviewResultId == resultId) {
+        currentPreview.value = preview;
+      }
+    } catch (e) {
+      // switched to another tab or failed, failure is logged by WoxHttpUtil
+    }
+  }
+
+  /// Update the preview of a result pushed by wox.core while the preview is focused
+  void updatePreview(String traceId, String resultId, WoxPreview preview) {
+    final index = results.indexWhere((element) => element.id == resultId && !element.isGroup);
+    if (index == -1) {
+      return;
+    }
+
+    results[index].preview = preview;
+    if (isResultActiveByIndex(index)) {
+      currentPreview.value = preview;
+    }
+  }
+
+  /// Reserve window height for the estimated result count of the current query, the actual count may be less or more.
+  /// A zero estimate means the query is done, the window then fits the actual results
+  void onQueryResultCountHint(String traceId, String queryId, int estimatedCount) {
+    if (currentQuery.value.queryId != queryId || expectedResultCount == estimatedCount) {
+      return;
+    }
+
+    expectedResultCount = estimatedCount;
+    resizeHeight();
+  }
+
   /// Triggered when received query results from the server.
   void onReceivedQueryResults(String traceId, List<WoxQueryResult> receivedResults) {
     if (receivedResults.isEmpty) {
@@ -116,28 +260,13 @@ class WoxLauncherController extends GetxController {
     //cancel clear results timer
     clearQueryResultsTimer.cancel();
 
-    //merge results
-    final existingQueryResults = results.where((item) => item.queryId == currentQuery.value.queryId).toList();
+    //merge results, results hidden in collapsed groups are merged too
+    final existingQueryResults = results.where((item) => item.queryId == currentQuery.value.queryId).toList()
+      ..addAll(collapsedGroupResults.where((item) => item.queryId == currentQuery.value.queryId));
     final finalResults = List<WoxQueryResult>.from(existingQueryResults)..addAll(receivedResults);
 
-    //group results
-    var finalResultsSorted = <WoxQueryResult>[];
-    final groups = finalResults.map((e) => e.group).toSet().toList();
-    groups.sort((a, b) => finalResults.where((element) => element.group == b).first.groupScore.compareTo(finalResults.where((element) => element.group == a).first.groupScore));
-    for (var group in groups) {
-      final groupResults = finalResults.where((element) => element.group == group).toList();
-      final groupResultsSorted = groupResults..sort((a, b) => b.score.compareTo(a.score));
-      if (group != "") {
-        finalResultsSorted.add(WoxQueryResult.empty()
-          ..title.value = group
-          ..isGroup = true
-          ..score = groupResultsSorted.first.groupScore);
-      }
-      finalResultsSorted.addAll(groupResultsSorted);
-    }
-
     // move default action to the first for every result
-    for (var element in finalResultsSorted) {
+    for (var element in receivedResults) {
       final defaultActionIndex = element.actions.indexWhere((element) => element.isDefault);
       if (defaultActionIndex != -1) {
         final defaultAction = element.actions[defaultActionIndex];
@@ -146,7 +275,7 @@ class WoxLauncherController extends GetxController {
       }
     }
 
-    results.assignAll(finalResultsSorted);
+    results.assignAll(groupQueryResults(finalResults));
     originalResults.assignAll(results);
     for (var _ in results) {
       resultGlobalKeys.add(GlobalKey());
@@ -162,6 +291,142 @@ class WoxLauncherController extends GetxController {
     resizeHeight();
   }
 
+  /// Sort results into their groups, a header is added for every named group.
+  /// Results of collapsed plugin groups are moved to [collapsedGroupResults] and only their header is returned.
+  List<WoxQueryResult> groupQueryResults(List<WoxQueryResult> queryResults) {
+    collapsedGroupResults.clear();
+
+    var sorted = <WoxQueryResult>[];
+    final groups = queryResults.map((e) => e.group).toSet().toList();
+    groups.sort((a, b) => queryResults.where((element) => element.group == b).first.groupScore.compareTo(queryResults.where((element) => element.group == a).first.groupScore));
+    for (var group in groups) {
+      final groupResults = queryResults.where((element) => element.group == group).toList();
+      final groupResultsSorted = groupResults..sort((a, b) => b.score.compareTo(a.score));
+      final groupCollapsed = group != "" && groupResultsSorted.first.groupCollapsed;
+      if (group != "") {
+        final header = WoxQueryResult.empty()
+          ..title.value = group
+          ..isGroup = true
+          ..pluginId = groupResultsSorted.first.pluginId
+          ..groupCollapsed = groupCollapsed
+          ..score = groupResultsSorted.first.groupScore;
+        if (groupCollapsed) {
+          header.tails.add(WoxQueryResultTail.text("+${groupResultsSorted.length}"));
+        }
+        sorted.add(header);
+      }
+      if (groupCollapsed) {
+        collapsedGroupResults.addAll(groupResultsSorted);
+      } else {
+        sorted.addAll(groupResultsSorted);
+      }
+    }
+
+    return sorted;
+  }
+
+  /// Collapse or expand the plugin group of the given group header, wox.core doesn't refresh hidden results while the group is collapsed.
+  /// The state is kept by wox.core, so later queries return results of the plugin collapsed too.
+  void toggleResultGroup(String traceId, WoxQueryResult groupHeader) {
+    if (!groupHeader.isGroup || groupHeader.pluginId.isEmpty) {
+      return;
+    }
+
+    final collapsed = !groupHeader.groupCollapsed;
+    Logger.instance.info(traceId, "toggle result group: ${groupHeader.title.value}, collapsed: $collapsed");
+    WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+      requestId: const UuidV4().generate(),
+      traceId: traceId,
+      type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+      method: WoxMsgMethodEnum.WOX_MSG_METHOD_SET_RESULT_GROUP_COLLAPSED.code,
+      data: {
+        "pluginId": groupHeader.pluginId,
+        "collapsed": collapsed.toString(),
+      },
+    ));
+
+    final activeResultId = results.isNotEmpty ? results[activeResultIndex.value].id : "";
+    final allResults = results.where((element) => !element.isGroup).toList()..addAll(collapsedGroupResults);
+    for (var result in allResults) {
+      if (result.pluginId == groupHeader.pluginId) {
+        result.groupCollapsed = collapsed;
+      }
+    }
+
+    results.assignAll(groupQueryResults(allResults));
+    originalResults.assignAll(results);
+    resultGlobalKeys.clear();
+    for (var _ in results) {
+      resultGlobalKeys.add(GlobalKey());
+    }
+
+    final newActiveIndex = results.indexWhere((element) => element.id == activeResultId && !element.isGroup);
+    if (newActiveIndex != -1) {
+      activeResultIndex.value = newActiveIndex;
+    } else {
+      resetActiveResult();
+      resetActiveAction(traceId, "toggle result group: ${groupHeader.title.value}");
+    }
+
+    resizeHeight();
+  }
+
+  /// Update scores of shown results and re-sort them without rebuilding, scores are pushed by plugins after results are shown.
+  /// Results are only re-sorted inside their group, results with equal scores keep their order and the active result stays active.
+  void updateResultScores(String traceId, List<dynamic> scores) {
+    var changed = false;
+    for (var item in scores) {
+      // results may be filtered (see filterSelectionResults), results hidden by the filter are updated too so they are in place once restored
+      for (var list in [results, originalResults]) {
+        final index = list.indexWhere((element) => element.id == item['ResultId'] && !element.isGroup);
+        if (index != -1) {
+          list[index].score = item['Score'];
+          changed = true;
+        }
+      }
+    }
+    if (!changed) {
+      return;
+    }
+
+    Logger.instance.info(traceId, "update result scores, count: ${scores.length}");
+    final activeResultId = results.isNotEmpty ? results[activeResultIndex.value].id : "";
+    // results and originalResults are re-sorted separately, results may only hold a part of originalResults
+    results.assignAll(sortResultsByScore(results));
+    originalResults.assignAll(sortResultsByScore(originalResults));
+    final newActiveIndex = results.indexWhere((element) => element.id == activeResultId && !element.isGroup);
+    if (newActiveIndex != -1) {
+      activeResultIndex.value = newActiveIndex;
+    }
+  }
+
+  /// Sort results by score within each group, group headers stay in place
+  List<WoxQueryResult> sortResultsByScore(List<WoxQueryResult> list) {
+    final sorted = <WoxQueryResult>[];
+    var segment = <WoxQueryResult>[];
+    void flushSegment() {
+      // sort by score desc and by current position on tie, so the sort is stable
+      final positions = {for (var i = 0; i < segment.length; i++) segment[i]: i};
+      segment.sort((a, b) {
+        final byScore = b.score.compareTo(a.score);
+        return byScore != 0 ? byScore : positions[a]!.compareTo(positions[b]!);
+      });
+      sorted.addAll(segment);
+      segment = <WoxQueryResult>[];
+    }
+
+    for (var result in list) {
+      if (result.isGroup) {
+        flushSegment();
+        sorted.add(result);
+      } else {
+        segment.add(result);
+      }
+    }
+    flushSegment();
+    return sorted;
+  }
+
   Future<void> toggleApp(String traceId, ShowAppParams params) async {
     var isVisible = await windowManager.isVisible();
     if (isVisible) {
@@ -179,7 +444,7 @@ class WoxLauncherController extends GetxController {
   Future<void> showApp(String traceId, ShowAppParams params) async {
     if (currentQuery.value.queryType == WoxQueryTypeEnum.WOX_QUERY_TYPE_INPUT.code) {
       canArrowUpHistory = true;
-      if (lastQueryMode == WoxLastQueryModeEnum.WOX_LAST_QUERY_MODE_PRESERVE.code) {
+      if (lastQueryMode == WoxLastQueryModeEnum.WOX_LAST_QUERY_MODE_PRESERVE.code || lastQueryMode == WoxLastQueryModeEnum.WOX_LAST_QUERY_MODE_RESTORE.code) {
         //skip the first one, because it's the current query
         currentQueryHistoryIndex = 0;
       } else {
@@ -190,6 +455,7 @@ class WoxLauncherController extends GetxController {
     // update some properties to latest for later use
     latestQueryHistories.assignAll(params.queryHistories);
     lastQueryMode = params.lastQueryMode;
+    escapeMode = params.escapeMode;
 
     if (params.selectAll) {
       selectQueryBoxAllText(traceId);
@@ -212,7 +478,11 @@ class WoxLauncherController extends GetxController {
     await windowManager.focus();
     queryBoxFocusNode.requestFocus();
 
-    WoxApi.instance.onShow();
+    // query session is started by onShow, so preloaded results are requested after it
+    await WoxApi.instance.onShow();
+    if (currentQuery.value.queryType != WoxQueryTypeEnum.WOX_QUERY_TYPE_SELECTION.code && currentQuery.value.queryText.isEmpty) {
+      queryEmptyInput(traceId);
+    }
   }
 
   Future<void> hideApp(String traceId) async {
@@ -234,6 +504,115 @@ class WoxLauncherController extends GetxController {
     await WoxApi.instance.onHide(currentQuery.value);
   }
 
+  /// Escape in query box hides wox by default, other escape modes are decided by wox.core which may clear the query instead
+  Future<void> onEscape(String traceId) async {
+    if (escapeMode == WoxEscapeModeEnum.WOX_ESCAPE_MODE_HIDE.code) {
+      await hideApp(traceId);
+      return;
+    }
+
+    WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+      requestId: const UuidV4().generate(),
+      traceId: traceId,
+      type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+      method: WoxMsgMethodEnum.WOX_MSG_METHOD_ESCAPE.code,
+      data: {
+        "queryType": currentQuery.value.queryType,
+        "queryText": currentQuery.value.queryText,
+        "querySelection": currentQuery.value.querySelection.toJson(),
+      },
+    ));
+  }
+
+  /// Shows a modal form of a plugin, returns submitted values or null if user cancelled or wox.core closed it
+  Future<Map<String, String>?> showPluginDialog(String traceId, WoxDialogSpec spec) async {
+    final context = navigatorKey.currentContext;
+    if (context == null || openDialogId != null) {
+      Logger.instance.warn(traceId, "can't show dialog ${spec.title}, launcher is not ready or another dialog is open");
+      return null;
+    }
+
+    if (!await windowManager.isVisible()) {
+      await windowManager.show();
+      await windowManager.focus();
+    }
+    await windowManager.setSize(const Size(800, 600));
+
+    openDialogId = spec.id;
+    final values = await showDialog<Map<String, String>>(
+      context: context,
+      barrierDismissible: false,
+      builder: (context) => WoxDialogView(spec: spec),
+    );
+    openDialogId = null;
+
+    await resizeHeight();
+    queryBoxFocusNode.requestFocus();
+    return values;
+  }
+
+  /// Prompts for input of an action which requires it, returns entered value or null if user cancelled
+  Future<String?> showActionInput(String traceId, WoxQueryResult result, WoxResultAction action) async {
+    final context = navigatorKey.currentContext;
+    if (context == null || openDialogId != null) {
+      Logger.instance.warn(traceId, "can't prompt input of action ${action.name}, launcher is not ready or a dialog is open");
+      return null;
+    }
+
+    await windowManager.setSize(const Size(800, 600));
+
+    openDialogId = action.id;
+    final input = await showDialog<String>(
+      context: context,
+      barrierDismissible: false,
+      builder: (context) => WoxActionInputView(
+        input: action.input!,
+        validate: (value) => validateActionInput(traceId, result, action, value),
+      ),
+    );
+    openDialogId = null;
+
+    if (input == null) {
+      // plugin may need to know, see QueryResultActionInput.OnCancel in wox.core
+      await WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+        requestId: const UuidV4().generate(),
+        traceId: traceId,
+        type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+        method: WoxMsgMethodEnum.WOX_MSG_METHOD_CANCEL_ACTION_INPUT.code,
+        data: {"resultId": result.id, "actionId": action.id},
+      ));
+    }
+
+    await resizeHeight();
+    queryBoxFocusNode.requestFocus();
+    return input;
+  }
+
+  /// Returns error message of invalid action input, empty if input is valid
+  Future<String> validateActionInput(String traceId, WoxQueryResult result, WoxResultAction action, String input) async {
+    final response = await WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+      requestId: const UuidV4().generate(),
+      traceId: traceId,
+      type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+      method: WoxMsgMethodEnum.WOX_MSG_METHOD_VALIDATE_ACTION_INPUT.code,
+      data: {"resultId": result.id, "actionId": action.id, "input": input},
+    ));
+    if (response is Map && response["Valid"] == false) {
+      return response["Error"] ?? "";
+    }
+    return "";
+  }
+
+  /// Closes the dialog if it's still open, E.g. plugin stopped waiting for it
+  void closePluginDialog(String traceId, String dialogId) {
+    if (openDialogId != dialogId) {
+      return;
+    }
+
+    Logger.instance.info(traceId, "close dialog $dialogId");
+    navigatorKey.currentState?.pop(null);
+  }
+
   Future<void> toggleActionPanel(String traceId) async {
     if (results.isEmpty) {
       return;
@@ -310,6 +689,94 @@ class WoxLauncherController extends GetxController {
     toolbar.value.action?.call();
   }
 
+  /// Handle Enter pressed with modifiers, returns false if nothing is run for the held modifiers.
+  /// Holding only the background action modifier (see WoxSetting.backgroundActionModifier) runs the default action in background,
+  /// holding the activation modifier of an action runs that action. Otherwise hotkeys of actions are checked, see [onEnterWithModifiers].
+  bool onModifierEnter(String traceId) {
+    final result = getActiveResult();
+    if (result == null || result.isGroup) {
+      return false;
+    }
+
+    final backgroundModifier = WoxSettingUtil.instance.currentSetting.backgroundActionModifier;
+    if (backgroundModifier != "" && backgroundModifier != "none" && isOnlyModifierPressed(backgroundModifier)) {
+      final defaultAction = getDefaultAction(result);
+      if (defaultAction != null) {
+        executeActionInBackground(traceId, result, defaultAction);
+        return true;
+      }
+    }
+
+    final activatedActionIndex = result.actions.indexWhere((element) => element.activationModifier != "" && isOnlyModifierPressed(element.activationModifier));
+    if (activatedActionIndex != -1) {
+      executeAction(traceId, result, result.actions[activatedActionIndex]);
+      return true;
+    }
+
+    return false;
+  }
+
+  /// Enter with modifiers which are neither handled by [onModifierEnter] nor a hotkey of an action runs the default action,
+  /// held modifiers are sent along so the action can act differently, see [executeAction]
+  void onEnterWithModifiers(String traceId) {
+    final result = getActiveResult();
+    if (result == null || result.isGroup) {
+      return;
+    }
+
+    final defaultAction = getDefaultAction(result);
+    if (defaultAction != null) {
+      executeAction(traceId, result, defaultAction);
+    }
+  }
+
+  WoxResultAction? getDefaultAction(WoxQueryResult result) {
+    final defaultActionIndex = result.actions.indexWhere((element) => element.isDefault);
+    if (defaultActionIndex != -1) {
+      return result.actions[defaultActionIndex];
+    }
+    return result.actions.isNotEmpty ? result.actions.first : null;
+  }
+
+  /// Modifiers from wox.core use platform specific names (E.g. win or option), convert them to the names of [WoxHotkey.getPressedModifierNames]
+  String normalizeModifierName(String modifier) {
+    final name = modifier.toLowerCase();
+    if (name == "win") {
+      return "cmd";
+    }
+    if (name == "option") {
+      return "alt";
+    }
+    return name;
+  }
+
+  bool isOnlyModifierPressed(String modifier) {
+    final pressed = WoxHotkey.getPressedModifierNames();
+    return pressed.length == 1 && pressed.first == normalizeModifierName(modifier);
+  }
+
+  /// Execute the action without hiding Wox, query, results and the active result are kept so user can open the next result the same way
+  Future<void> executeActionInBackground(String traceId, WoxQueryResult result, WoxResultAction action) async {
+    Logger.instance.debug(traceId, "execute action in background: ${action.name}");
+
+    await WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+      requestId: const UuidV4().generate(),
+      traceId: traceId,
+      type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+      method: WoxMsgMethodEnum.WOX_MSG_METHOD_ACTION.code,
+      data: {
+        "resultId": result.id,
+        "actionId": action.id,
+        "background": "true",
+      },
+    ));
+
+    if (isShowActionPanel.value) {
+      hideActionPanel(traceId);
+    }
+    queryBoxFocusNode.requestFocus();
+  }
+
   Future<void> executeAction(String traceId, WoxQueryResult? result, WoxResultAction? action) async {
     Logger.instance.debug(traceId, "user execute result action: ${action?.name}");
 
@@ -325,23 +792,108 @@ class WoxLauncherController extends GetxController {
     var preventHideAfterAction = action.preventHideAfterAction;
     Logger.instance.debug(traceId, "execute action: ${action.name}, prevent hide after action: $preventHideAfterAction");
 
+    if (isMultiSelecting() && action.isBatch) {
+      await executeBatchAction(traceId, result, action);
+      return;
+    }
+
+    // read modifiers before prompting for input, user has released them by the time input is submitted
+    var data = <String, dynamic>{
+      "resultId": result.id,
+      "actionId": action.id,
+      // modifiers held when user pressed Enter or clicked, so one action can act differently, E.g. open in background with shift
+      "modifiers": WoxHotkey.getPressedModifierNames(),
+    };
+    if (action.input != null) {
+      final input = await showActionInput(traceId, result, action);
+      if (input == null) {
+        return;
+      }
+      data["input"] = input;
+    }
+
     await WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
       requestId: const UuidV4().generate(),
       traceId: traceId,
       type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
       method: WoxMsgMethodEnum.WOX_MSG_METHOD_ACTION.code,
+      data: data,
+    ));
+
+    if (!preventHideAfterAction) {
+      hideApp(traceId);
+    }
+    if (isShowActionPanel.value) {
+      hideActionPanel(traceId);
+    }
+  }
+
+  /// Run a batch action once on all selected results, result which user runs the action on goes first
+  Future<void> executeBatchAction(String traceId, WoxQueryResult result, WoxResultAction action) async {
+    final resultIds = [result.id, ...selectedResultIds.where((id) => id != result.id)];
+    Logger.instance.debug(traceId, "execute batch action: ${action.name}, results: ${resultIds.length}");
+
+    await WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
+      requestId: const UuidV4().generate(),
+      traceId: traceId,
+      type: WoxMsgTypeEnum.WOX_MSG_TYPE_REQUEST.code,
+      method: WoxMsgMethodEnum.WOX_MSG_METHOD_BATCH_ACTION.code,
       data: {
-        "resultId": result.id,
+        "resultIds": resultIds,
         "actionId": action.id,
       },
     ));
 
-    if (!preventHideAfterAction) {
+    selectedResultIds.clear();
+    if (!action.preventHideAfterAction) {
       hideApp(traceId);
     }
     if (isShowActionPanel.value) {
       hideActionPanel(traceId);
+    } else {
+      resetActiveAction(traceId, "batch action executed");
+    }
+  }
+
+  bool isMultiSelecting() {
+    return selectedResultIds.length > 1;
+  }
+
+  bool isResultSelectedByIndex(int index) {
+    return selectedResultIds.contains(results[index].id);
+  }
+
+  /// Select or unselect the result, only batch actions are listed while several results are selected
+  void toggleResultSelection(String traceId, int index) {
+    if (index < 0 || index >= results.length || results[index].isGroup) {
+      return;
+    }
+
+    final resultId = results[index].id;
+    if (!selectedResultIds.remove(resultId)) {
+      selectedResultIds.add(resultId);
     }
+    resetActiveAction(traceId, "result selection changed");
+  }
+
+  /// Shift+arrow selects the active result and moves to the next one, like selecting rows in a file manager
+  void handleQueryBoxShiftArrow(String traceId, WoxDirection direction) {
+    if (!selectedResultIds.contains(getActiveResult()?.id)) {
+      toggleResultSelection(traceId, activeResultIndex.value);
+    }
+    changeResultScrollPosition(traceId, WoxEventDeviceTypeEnum.WOX_EVENT_DEVEICE_TYPE_KEYBOARD.code, direction);
+    final activeResult = getActiveResult();
+    if (activeResult != null && !selectedResultIds.contains(activeResult.id)) {
+      toggleResultSelection(traceId, activeResultIndex.value);
+    }
+  }
+
+  /// Actions user can run on the active result, only batch actions if several results are selected
+  List<WoxResultAction> getAvailableActions(WoxQueryResult result) {
+    if (!isMultiSelecting()) {
+      return result.actions;
+    }
+    return result.actions.where((action) => action.isBatch).toList();
   }
 
   Future<void> autoCompleteQuery(String traceId) async {
@@ -411,6 +963,9 @@ class WoxLauncherController extends GetxController {
     }
 
     currentQuery.value = query;
+    expectedResultCount = 0;
+    queryProgress.clear();
+    selectedResultIds.clear();
     isShowActionPanel.value = false;
     if (query.queryType == WoxQueryTypeEnum.WOX_QUERY_TYPE_SELECTION.code) {
       canArrowUpHistory = false;
@@ -426,6 +981,10 @@ class WoxLauncherController extends GetxController {
     updateToolbarOnQueryChanged(traceId, query);
     if (query.isEmpty) {
       clearQueryResults();
+      // wox.core still answers empty input queries with preloaded results and results of plugins supporting empty queries
+      if (query.queryType == WoxQueryTypeEnum.WOX_QUERY_TYPE_INPUT.code) {
+        sendQuery(traceId, query);
+      }
       return;
     }
 
@@ -438,6 +997,10 @@ class WoxLauncherController extends GetxController {
         clearQueryResults();
       },
     );
+    sendQuery(traceId, query);
+  }
+
+  void sendQuery(String traceId, PlainQuery query) {
     WoxWebsocketMsgUtil.instance.sendMessage(WoxWebsocketMsg(
       requestId: const UuidV4().generate(),
       traceId: traceId,
@@ -448,10 +1011,31 @@ class WoxLauncherController extends GetxController {
         "queryType": query.queryType,
         "queryText": query.queryText,
         "querySelection": query.querySelection.toJson(),
+        // wox.core handles each message concurrently, it orders queries by this timestamp to find the latest one
+        "queryTimestamp": DateTime.now().microsecondsSinceEpoch,
+        "isComposing": isQueryComposing(query),
       },
     ));
   }
 
+  /// Whether the query text contains uncommitted IME composition, wox.core delays such queries as user is likely to keep typing
+  bool isQueryComposing(PlainQuery query) {
+    if (query.queryType != WoxQueryTypeEnum.WOX_QUERY_TYPE_INPUT.code || queryBoxTextFieldController.text != query.queryText) {
+      return false;
+    }
+
+    final composing = queryBoxTextFieldController.value.composing;
+    return composing.isValid && !composing.isCollapsed;
+  }
+
+  /// Send an empty input query, wox.core returns results preloaded when the window was shown if any plugin preloads them, otherwise nothing.
+  void queryEmptyInput(String traceId) {
+    final query = PlainQuery.emptyInput()..queryId = const UuidV4().generate();
+    currentQuery.value = query;
+    expectedResultCount = 0;
+    sendQuery(traceId, query);
+  }
+
   void onActionQueryBoxTextChanged(String traceId, String filteredActionName) {
     // restore all actions if query is empty
     var activeResult = getActiveResult();
@@ -460,12 +1044,12 @@ class WoxLauncherController extends GetxController {
     }
 
     if (filteredActionName.isEmpty) {
-      actions.assignAll(activeResult.actions);
+      actions.assignAll(getAvailableActions(activeResult));
       updateToolbarByActiveAction(traceId);
       return;
     }
 
-    var filteredActions = activeResult.actions.where((element) {
+    var filteredActions = getAvailableActions(activeResult).where((element) {
       return isFuzzyMatch(traceId, element.name.value, filteredActionName);
     }).toList();
 
@@ -495,7 +1079,7 @@ class WoxLauncherController extends GetxController {
   void changeResultScrollPosition(String traceId, WoxEventDeviceType deviceType, WoxDirection direction) {
     final prevResultIndex = activeResultIndex.value;
     updateActiveResultIndex(traceId, direction);
-    if (results.length < MAX_LIST_VIEW_ITEM_COUNT) {
+    if (getResultsHeightBetween(0, results.length) <= WoxThemeUtil.instance.getResultListViewHeightByCount(MAX_LIST_VIEW_ITEM_COUNT)) {
       results.refresh();
       return;
     }
@@ -508,17 +1092,17 @@ class WoxLauncherController extends GetxController {
             ? isResultItemAtBottom(activeResultIndex.value - 1)
             : !isResultItemAtBottom(results.length - 1);
         if (shouldJump) {
-          resultScrollerController.jumpTo(resultScrollerController.offset.ceil() + WoxThemeUtil.instance.getResultItemHeight() * (activeResultIndex.value - prevResultIndex).abs());
+          resultScrollerController.jumpTo(resultScrollerController.offset.ceil() + getResultsHeightBetween(prevResultIndex, activeResultIndex.value));
         }
       }
     }
     if (direction == WoxDirectionEnum.WOX_DIRECTION_UP.code) {
       if (activeResultIndex.value > prevResultIndex) {
-        resultScrollerController.jumpTo(WoxThemeUtil.instance.getResultListViewHeightByCount(results.length - MAX_LIST_VIEW_ITEM_COUNT));
+        resultScrollerController.jumpTo(max(0.0, getResultsHeightBetween(0, results.length) - WoxThemeUtil.instance.getResultListViewHeightByCount(MAX_LIST_VIEW_ITEM_COUNT)));
       } else {
         bool shouldJump = deviceType == WoxEventDeviceTypeEnum.WOX_EVENT_DEVEICE_TYPE_KEYBOARD.code ? isResultItemAtTop(activeResultIndex.value + 1) : !isResultItemAtTop(0);
         if (shouldJump) {
-          resultScrollerController.jumpTo(resultScrollerController.offset.ceil() - WoxThemeUtil.instance.getResultItemHeight() * (activeResultIndex.value - prevResultIndex).abs());
+          resultScrollerController.jumpTo(resultScrollerController.offset.ceil() - getResultsHeightBetween(activeResultIndex.value, prevResultIndex));
         }
       }
     }
@@ -564,12 +1148,42 @@ class WoxLauncherController extends GetxController {
       final pickFilesParams = FileSelectorParams.fromJson(msg.data);
       final files = await FileSelector.pick(msg.traceId, pickFilesParams);
       responseWoxWebsocketRequest(msg, true, files);
+    } else if (msg.method == "ShowDialog") {
+      final values = await showPluginDialog(msg.traceId, WoxDialogSpec.fromJson(msg.data));
+      responseWoxWebsocketRequest(msg, true, values);
+    } else if (msg.method == "CloseDialog") {
+      closePluginDialog(msg.traceId, msg.data['Id']);
+      responseWoxWebsocketRequest(msg, true, null);
     } else if (msg.method == "OpenSettingWindow") {
       openSettingWindow(msg.traceId, SettingWindowContext.fromJson(msg.data));
       responseWoxWebsocketRequest(msg, true, null);
     } else if (msg.method == "ShowToolbarMsg") {
       showToolbarMsg(msg.traceId, ToolbarMsg.fromJson(msg.data));
       responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "Batch") {
+      final failed = await executeUICommandBatch(msg.traceId, msg.data);
+      responseWoxWebsocketRequest(msg, failed == null, failed);
+    } else if (msg.method == "QueryResultCountHint") {
+      onQueryResultCountHint(msg.traceId, msg.data['QueryId'], msg.data['EstimatedCount']);
+      responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "QueryProgress") {
+      onQueryProgress(msg.traceId, QueryProgress.fromJson(msg.data));
+      responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "SetStatus") {
+      setStatus(msg.traceId, StatusMsg.fromJson(msg.data));
+      responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "ClearStatus") {
+      setStatus(msg.traceId, null);
+      responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "UpdatePreview") {
+      updatePreview(msg.traceId, msg.data['ResultId'], WoxPreview.fromJson(msg.data['Preview']));
+      responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "UpdateResults") {
+      updateResults(msg.traceId, msg.data);
+      responseWoxWebsocketRequest(msg, true, null);
+    } else if (msg.method == "UpdateResultScores") {
+      updateResultScores(msg.traceId, msg.data);
+      responseWoxWebsocketRequest(msg, true, null);
     } else if (msg.method == "GetCurrentQuery") {
       responseWoxWebsocketRequest(msg, true, currentQuery.value.toJson());
     } else if (msg.method == "IsVisible") {
@@ -590,6 +1204,32 @@ class WoxLauncherController extends GetxController {
     }
   }
 
+  /// Apply commands of a batch in order and stop at the first failed one, returns which command failed or null if all succeeded.
+  /// See UICommand in wox.core
+  Future<Map<String, dynamic>?> executeUICommandBatch(String traceId, List<dynamic> commands) async {
+    for (var i = 0; i < commands.length; i++) {
+      final method = commands[i]['Method'];
+      final data = commands[i]['Data'];
+      try {
+        if (method == "ChangeQuery") {
+          onQueryChanged(traceId, PlainQuery.fromJson(data), "receive change query from wox", moveCursorToEnd: true);
+        } else if (method == "HideApp") {
+          await hideApp(traceId);
+        } else if (method == "ShowApp") {
+          await showApp(traceId, ShowAppParams.fromJson(data));
+        } else if (method == "ShowToolbarMsg") {
+          showToolbarMsg(traceId, ToolbarMsg.fromJson(data));
+        } else {
+          throw Exception("unsupported ui command: $method");
+        }
+      } catch (e) {
+        Logger.instance.error(traceId, "failed to execute ui command $method in batch: $e");
+        return {"Index": i, "Method": method, "Error": e.toString()};
+      }
+    }
+    return null;
+  }
+
   void responseWoxWebsocketRequest(WoxWebsocketMsg request, bool success, dynamic data) {
     WoxWebsocketMsgUtil.instance.sendMessage(
       WoxWebsocketMsg(
@@ -603,12 +1243,26 @@ class WoxLauncherController extends GetxController {
     );
   }
 
+  double getResultItemHeightByIndex(int index) {
+    return WoxThemeUtil.instance.getResultItemHeight(iconSize: results[index].iconSize);
+  }
+
+  /// Total height of results in [from, to), order of the indexes doesn't matter
+  double getResultsHeightBetween(int from, int to) {
+    var height = 0.0;
+    for (var i = min(from, to); i < max(from, to) && i < results.length; i++) {
+      height += getResultItemHeightByIndex(i);
+    }
+    return height;
+  }
+
   bool isResultItemAtBottom(int index) {
     RenderBox? renderBox = resultGlobalKeys[index].currentContext?.findRenderObject() as RenderBox?;
     if (renderBox == null) return false;
 
-    if (renderBox.localToGlobal(Offset.zero).dy.ceil() >=
-        WoxThemeUtil.instance.getQueryBoxHeight() + WoxThemeUtil.instance.getResultListViewHeightByCount(MAX_LIST_VIEW_ITEM_COUNT - 1)) {
+    // results may have different heights (see WoxQueryResult.iconSize), compare bottom of the item with bottom of the list
+    if (renderBox.localToGlobal(Offset.zero).dy.ceil() + getResultItemHeightByIndex(index) >=
+        WoxThemeUtil.instance.getQueryBoxHeight() + WoxThemeUtil.instance.getResultListViewHeightByCount(MAX_LIST_VIEW_ITEM_COUNT)) {
       return true;
     }
     return false;
@@ -629,6 +1283,8 @@ class WoxLauncherController extends GetxController {
 
   Future<void> clearQueryResults() async {
     results.clear();
+    selectedResultIds.clear();
+    collapsedGroupResults.clear();
     actions.clear();
     toolbar.value = ToolbarInfo.empty();
     isShowPreviewPanel.value = false;
@@ -647,13 +1303,13 @@ class WoxLauncherController extends GetxController {
 
   /// reset and jump active result to top of the list
   void resetActiveResult() {
+    endPeek();
+
     // reset active result index
     if (results.isNotEmpty) {
-      if (results[0].isGroup) {
-        activeResultIndex.value = 1;
-      } else {
-        activeResultIndex.value = 0;
-      }
+      // first result may be a group header, or several if groups are collapsed
+      final firstResultIndex = results.indexWhere((element) => !element.isGroup);
+      activeResultIndex.value = firstResultIndex == -1 ? 0 : firstResultIndex;
       if (resultScrollerController.hasClients) {
         resultScrollerController.jumpTo(0);
       }
@@ -669,14 +1325,19 @@ class WoxLauncherController extends GetxController {
   }
 
   Future<void> resizeHeight() async {
-    double resultHeight = WoxThemeUtil.instance.getResultListViewHeightByCount(results.length > 10 ? 10 : results.length);
+    final resultCount = max(results.length, expectedResultCount);
+    // reserved results without data yet are counted at default height, the list never grows beyond 10 default results
+    final maxResultHeight = WoxThemeUtil.instance.getResultListViewHeightByCount(10);
+    final knownCount = min(results.length, 10);
+    double resultHeight = getResultsHeightBetween(0, knownCount) + WoxThemeUtil.instance.getResultListViewHeightByCount(max(0, min(resultCount, 10) - knownCount));
+    resultHeight = min(resultHeight, maxResultHeight);
     if (isShowActionPanel.value || isShowPreviewPanel.value) {
-      resultHeight = WoxThemeUtil.instance.getResultListViewHeightByCount(10);
+      resultHeight = maxResultHeight;
     }
-    if (results.isNotEmpty) {
+    if (resultCount > 0) {
       resultHeight += woxTheme.value.resultContainerPaddingTop + woxTheme.value.resultContainerPaddingBottom;
     }
-    if (toolbar.value.isNotEmpty()) {
+    if (isShowToolbar()) {
       resultHeight += WoxThemeUtil.instance.getToolbarHeight();
     }
     final totalHeight = WoxThemeUtil.instance.getQueryBoxHeight() + resultHeight;
@@ -717,6 +1378,7 @@ class WoxLauncherController extends GetxController {
         }
       }
     }
+    endPeek();
     currentPreview.value = results[activeResultIndex.value].preview;
     isShowPreviewPanel.value = currentPreview.value.previewData != "";
     resetActiveAction(traceId, "update active result index, direction: $woxDirection");
@@ -766,6 +1428,7 @@ class WoxLauncherController extends GetxController {
   }
 
   void setActiveResultIndex(int index) {
+    endPeek();
     activeResultIndex.value = index;
     currentPreview.value = results[index].preview;
     isShowPreviewPanel.value = currentPreview.value.previewData != "";
@@ -773,6 +1436,54 @@ class WoxLauncherController extends GetxController {
     results.refresh();
   }
 
+  /// Whether hovering a result peeks its preview instead of focusing it, see [peekResult]
+  bool isPreviewPeekEnabled() {
+    return WoxSettingUtil.instance.currentSetting.enablePreviewPeek;
+  }
+
+  /// Show preview of the hovered result without focusing it, focus and actions stay on the active result.
+  /// The previous peek is cancelled, so a lazy preview of a result user only passed over is not computed.
+  Future<void> peekResult(int index) async {
+    endPeek();
+
+    final result = results[index];
+    if (index == activeResultIndex.value || result.isGroup || result.preview.previewData == "") {
+      return;
+    }
+
+    final cancelToken = CancelToken();
+    peekCancelToken = cancelToken;
+    try {
+      final preview = await WoxHttpUtil.instance.getData<WoxPreview>("/preview/peek", params: {"id": result.id}, cancelToken: cancelToken);
+      if (peekCancelToken == cancelToken) {
+        peekPreview.value = preview;
+      }
+    } catch (e) {
+      // peek is cancelled or failed, keep showing preview of the active result
+    }
+  }
+
+  /// Fetch the real icon of a result whose icon is computed lazily by plugin, the placeholder icon is shown meanwhile.
+  /// [cancelToken] is cancelled if the result scrolls out of view before its icon is resolved, see WoxLazyIconLoader
+  Future<void> loadLazyIcon(WoxQueryResult result, CancelToken cancelToken) async {
+    try {
+      final icon = await WoxHttpUtil.instance.getData<WoxImage>("/result/icon", params: {"resultId": result.id}, cancelToken: cancelToken);
+      result.icon.value = icon;
+      result.hasLazyIcon = false;
+    } catch (e) {
+      // cancelled or failed, placeholder is kept and the icon is fetched again when the result shows up next time
+    }
+  }
+
+  /// End the peek when mouse leaves the result or focus changes, preview of the active result is shown again
+  void endPeek() {
+    peekCancelToken?.cancel();
+    peekCancelToken = null;
+    if (peekPreview.value.previewData != "") {
+      peekPreview.value = WoxPreview.empty();
+    }
+  }
+
   /// update active actions based on active result and reset active action index to 0
   void resetActiveAction(String traceId, String reason, {bool remainIndex = false}) {
     var activeQueryResult = getActiveResult();
@@ -794,13 +1505,13 @@ class WoxLauncherController extends GetxController {
     final filterText = actionTextFieldController.text;
     List<WoxResultAction> newActions;
     if (filterText.isNotEmpty) {
-      newActions = activeQueryResult.actions.where((element) {
+      newActions = getAvailableActions(activeQueryResult).where((element) {
         return isFuzzyMatch(traceId, element.name.value, filterText);
       }).toList();
       activeActionIndex.value = newActions.isEmpty ? -1 : 0;
       remainIndex = false;
     } else {
-      newActions = List.from(activeQueryResult.actions);
+      newActions = List.from(getAvailableActions(activeQueryResult));
     }
 
     // Only update actions if they have actually changed
@@ -875,29 +1586,7 @@ class WoxLauncherController extends GetxController {
               return;
             }
 
-            final refreshResult = WoxRefreshableResult.fromJson(resp);
-            result.title.value = refreshResult.title;
-            result.subTitle.value = refreshResult.subTitle;
-            result.icon.value = refreshResult.icon;
-            result.preview = refreshResult.preview;
-            result.tails.assignAll(refreshResult.tails);
-            result.actions.assignAll(refreshResult.actions);
-
-            // only update preview and toolbar when current result is active
-            final resultIndex = results.indexWhere((element) => element.id == result.id);
-            if (isResultActiveByIndex(resultIndex)) {
-              currentPreview.value = result.preview;
-              final oldShowPreview = isShowPreviewPanel.value;
-              isShowPreviewPanel.value = currentPreview.value.previewData != "";
-              if (oldShowPreview != isShowPreviewPanel.value) {
-                Logger.instance.debug(traceId, "preview panel visibility changed, resize height");
-                resizeHeight();
-              }
-              resetActiveAction(traceId, "refresh active result", remainIndex: true);
-            }
-
-            result.contextData = refreshResult.contextData;
-            result.refreshInterval = refreshResult.refreshInterval;
+            applyRefreshableResult(traceId, result, WoxRefreshableResult.fromJson(resp));
             isRequesting.remove(result.id);
           });
         }
@@ -905,6 +1594,44 @@ class WoxLauncherController extends GetxController {
     });
   }
 
+  void applyRefreshableResult(String traceId, WoxQueryResult result, WoxRefreshableResult refreshResult) {
+    result.title.value = refreshResult.title;
+    result.subTitle.value = refreshResult.subTitle;
+    result.icon.value = refreshResult.icon;
+    result.preview = refreshResult.preview;
+    result.tails.assignAll(refreshResult.tails);
+    result.actions.assignAll(refreshResult.actions);
+
+    // only update preview and toolbar when current result is active
+    final resultIndex = results.indexWhere((element) => element.id == result.id);
+    if (isResultActiveByIndex(resultIndex)) {
+      currentPreview.value = result.preview;
+      final oldShowPreview = isShowPreviewPanel.value;
+      isShowPreviewPanel.value = currentPreview.value.previewData != "";
+      if (oldShowPreview != isShowPreviewPanel.value) {
+        Logger.instance.debug(traceId, "preview panel visibility changed, resize height");
+        resizeHeight();
+      }
+      resetActiveAction(traceId, "refresh active result", remainIndex: true);
+    }
+
+    result.contextData = refreshResult.contextData;
+    result.refreshInterval = refreshResult.refreshInterval;
+  }
+
+  /// Update shown results pushed by plugins, results which are not shown anymore (E.g. query changed) are skipped
+  void updateResults(String traceId, List<dynamic> updates) {
+    for (var item in updates) {
+      final refreshResult = WoxRefreshableResult.fromJson(item);
+      final index = results.indexWhere((element) => element.id == refreshResult.resultId && !element.isGroup);
+      if (index == -1) {
+        continue;
+      }
+      applyRefreshableResult(traceId, results[index], refreshResult);
+    }
+    Logger.instance.info(traceId, "update results, count: ${updates.length}");
+  }
+
   startDoctorCheckSchedule() {
     Timer.periodic(const Duration(minutes: 1), (timer) async {
       doctorCheckPassed = await WoxApi.instance.doctorCheck();
@@ -942,6 +1669,7 @@ class WoxLauncherController extends GetxController {
           ShowAppParams(
             queryHistories: latestQueryHistories,
             lastQueryMode: lastQueryMode,
+            escapeMode: escapeMode,
             selectAll: true,
             position: Position(
               type: WoxPositionTypeEnum.POSITION_TYPE_LAST_LOCATION.code,
@@ -952,6 +1680,36 @@ class WoxLauncherController extends GetxController {
     }
   }
 
+  bool isShowToolbar() {
+    return toolbar.value.isNotEmpty() || status.value != null || (results.isEmpty && queryProgress.isNotEmpty);
+  }
+
+  void onQueryProgress(String traceId, QueryProgress progress) {
+    if (progress.queryId != currentQuery.value.queryId) {
+      return;
+    }
+
+    final wasShowToolbar = isShowToolbar();
+    if (!progress.done) {
+      queryProgress[progress.pluginId] = progress;
+    } else if (progress.pluginId.isEmpty) {
+      queryProgress.clear();
+    } else {
+      queryProgress.remove(progress.pluginId);
+    }
+    if (wasShowToolbar != isShowToolbar()) {
+      resizeHeight();
+    }
+  }
+
+  void setStatus(String traceId, StatusMsg? msg) {
+    final wasShowToolbar = isShowToolbar();
+    status.value = msg;
+    if (wasShowToolbar != isShowToolbar()) {
+      resizeHeight();
+    }
+  }
+
   void showToolbarMsg(String traceId, ToolbarMsg msg) {
     // cancel the timer if it is running
     cleanToolbarTimer.cancel();
@@ -1,4 +1,5 @@
 import 'dart:convert';
+import 'dart:io';
 
 import 'package:fluent_ui/fluent_ui.dart';
 import 'package:get/get.dart';
@@ -135,6 +136,54 @@ class WoxSettingGeneralView extends WoxSettingBaseView {
             );
           }),
         ),
+        formField(
+          label: controller.tr("ui_background_action_modifier"),
+          tips: controller.tr("ui_background_action_modifier_tips"),
+          child: Obx(() {
+            return ComboBox<String>(
+              items: [
+                ComboBoxItem(
+                  value: "none",
+                  child: Text(controller.tr("ui_background_action_modifier_none")),
+                ),
+                ComboBoxItem(
+                  value: "shift",
+                  child: const Text("Shift"),
+                ),
+                ComboBoxItem(
+                  value: "ctrl",
+                  child: const Text("Ctrl"),
+                ),
+                ComboBoxItem(
+                  value: "alt",
+                  child: Text(Platform.isMacOS ? "Option" : "Alt"),
+                ),
+                ComboBoxItem(
+                  value: "cmd",
+                  child: Text(Platform.isMacOS ? "Cmd" : "Win"),
+                ),
+              ],
+              value: controller.woxSetting.value.backgroundActionModifier,
+              onChanged: (v) {
+                if (v != null) {
+                  controller.updateConfig("BackgroundActionModifier", v);
+                }
+              },
+            );
+          }),
+        ),
+        formField(
+          label: controller.tr("ui_hide_action_hotkey_hints"),
+          tips: controller.tr("ui_hide_action_hotkey_hints_tips"),
+          child: Obx(() {
+            return ToggleSwitch(
+              checked: controller.woxSetting.value.hideActionHotkeyHints,
+              onChanged: (bool value) {
+                controller.updateConfig("HideActionHotkeyHints", value.toString());
+              },
+            );
+          }),
+        ),
         formField(
           label: controller.tr("ui_lang"),
           child: FutureBuilder(
@@ -187,9 +236,14 @@ class WoxSettingGeneralView extends WoxSettingBaseView {
                     "Tooltip": "i18n:ui_query_hotkeys_query_tooltip",
                     "Type": "text",
                     "TextMaxLines": 1,
-                    "Validators": [
-                      {"Type": "not_empty"}
-                    ],
+                  },
+                  {
+                    "Key": "HeadlessAction",
+                    "Label": "i18n:ui_query_hotkeys_headless_action",
+                    "Tooltip": "i18n:ui_query_hotkeys_headless_action_tooltip",
+                    "Width": 160,
+                    "Type": "text",
+                    "TextMaxLines": 1,
                   },
                   {
                     "Key": "IsSilentExecution",
@@ -204,6 +258,15 @@ class WoxSettingGeneralView extends WoxSettingBaseView {
               onUpdate: (key, value) {
                 controller.updateConfig("QueryHotkeys", value);
               },
+              onUpdateValidate: (rowValues) async {
+                // query is only optional for headless actions, it's passed to the action as context data
+                final headlessAction = (rowValues["HeadlessAction"] ?? "").toString().trim();
+                final query = (rowValues["Query"] ?? "").toString().trim();
+                if (headlessAction == "" && query == "") {
+                  return controller.tr("ui_query_hotkeys_query_required");
+                }
+                return null;
+              },
             );
           }),
         ),
@@ -1,6 +1,9 @@
 const int MAX_LIST_VIEW_ITEM_COUNT = 10;
 const double QUERY_BOX_BASE_HEIGHT = 55.0;
 const double RESULT_ITEM_BASE_HEIGHT = 50.0;
+
+// icon size of a result without size hint, results with larger icons are taller, see WoxThemeUtil.getResultItemHeight
+const double RESULT_ITEM_DEFAULT_ICON_SIZE = 30.0;
 const double TOOLBAR_HEIGHT = 40.0;
 
 const String QUERY_ICON_SELECTION_FILE =
@@ -16,6 +16,8 @@ class EntityFactory {
       return WoxSetting.fromJson(json) as T;
     } else if (T.toString() == "WoxPreview") {
       return WoxPreview.fromJson(json) as T;
+    } else if (T.toString() == "WoxPreviewStreamWindow") {
+      return WoxPreviewStreamWindow.fromJson(json) as T;
     } else if (T.toString() == "WoxImage") {
       return WoxImage.fromJson(json) as T;
     } else if (T.toString() == "WoxLang") {
@@ -2,23 +2,48 @@ import 'package:file_picker/file_picker.dart';
 
 class FileSelectorParams {
   late bool isDirectory;
+  late String title;
+  late String defaultDirectory;
+  late List<String> extensions;
+  late bool allowMultiple;
 
-  FileSelectorParams({required this.isDirectory});
+  FileSelectorParams({required this.isDirectory, this.title = "", this.defaultDirectory = "", this.extensions = const [], this.allowMultiple = false});
 
   FileSelectorParams.fromJson(Map<String, dynamic> json) {
     isDirectory = json['IsDirectory'];
+    title = json['Title'] ?? "";
+    defaultDirectory = json['DefaultDirectory'] ?? "";
+    extensions = json['Extensions'] == null ? [] : List<String>.from(json['Extensions']);
+    allowMultiple = json['AllowMultiple'] ?? false;
   }
 }
 
 class FileSelector {
+  // returns empty list if user cancelled
   static Future<List<String>> pick(String traceId, FileSelectorParams params) async {
+    final dialogTitle = params.title.isEmpty ? null : params.title;
+    final initialDirectory = params.defaultDirectory.isEmpty ? null : params.defaultDirectory;
+
     if (params.isDirectory) {
-      String? selectedDirectory = await FilePicker.platform.getDirectoryPath();
+      String? selectedDirectory = await FilePicker.platform.getDirectoryPath(dialogTitle: dialogTitle, initialDirectory: initialDirectory);
       if (selectedDirectory != null) {
         return [selectedDirectory];
       }
+      return [];
+    }
+
+    final result = await FilePicker.platform.pickFiles(
+      dialogTitle: dialogTitle,
+      initialDirectory: initialDirectory,
+      type: params.extensions.isEmpty ? FileType.any : FileType.custom,
+      allowedExtensions: params.extensions.isEmpty ? null : params.extensions,
+      allowMultiple: params.allowMultiple,
+      lockParentWindow: true,
+    );
+    if (result == null) {
+      return [];
     }
 
-    return [];
+    return result.paths.whereType<String>().toList();
   }
 }
@@ -15,4 +15,43 @@ class Strings {
 
     return str;
   }
+
+  /// Format a unix timestamp in milliseconds relative to now, E.g. "5m ago". Same rules as util.FormatRelativeTime of wox.core,
+  /// so times formatted by UI and by plugins look the same. [tr] translates the relative_time_* keys
+  static String formatRelativeTime(int timestamp, String Function(String key) tr) {
+    if (timestamp <= 0) {
+      return "";
+    }
+
+    var diff = DateTime.now().difference(DateTime.fromMillisecondsSinceEpoch(timestamp));
+    var direction = "ago";
+    if (diff.isNegative) {
+      direction = "in";
+      diff = -diff;
+    }
+    if (diff.inSeconds < 45) {
+      return tr("relative_time_just_now");
+    }
+
+    String unit;
+    int count;
+    if (diff.inHours < 1) {
+      unit = "minutes";
+      count = diff.inMinutes < 1 ? 1 : diff.inMinutes;
+    } else if (diff.inDays < 1) {
+      unit = "hours";
+      count = diff.inHours;
+    } else if (diff.inDays < 30) {
+      unit = "days";
+      count = diff.inDays;
+    } else if (diff.inDays < 365 && diff.inDays ~/ 30 < 12) {
+      unit = "months";
+      count = diff.inDays ~/ 30;
+    } else {
+      unit = "years";
+      count = diff.inDays < 365 ? 1 : diff.inDays ~/ 365;
+    }
+
+    return format(tr("relative_time_${unit}_$direction"), [count]);
+  }
 }
@@ -15,13 +15,15 @@ class WoxHttpUtil {
 
   static WoxHttpUtil get instance => _instance;
 
-  Future<T> getData<T>(String url, {Map<String, dynamic>? params}) async {
+  /// [cancelToken] aborts the request, server side work bound to the request is cancelled as well
+  Future<T> getData<T>(String url, {Map<String, dynamic>? params, CancelToken? cancelToken}) async {
     try {
-      final response = await _dio.get(_baseUrl + url, queryParameters: params);
+      final response = await _dio.get(_baseUrl + url, queryParameters: params, cancelToken: cancelToken);
       WoxResponse woxResponse = WoxResponse.fromJson(response.data);
       if (woxResponse.success == false) throw Exception(woxResponse.message);
       return EntityFactory.generateOBJ<T>(woxResponse.data);
     } catch (e) {
+      if (e is DioException && CancelToken.isCancel(e)) rethrow;
       Logger.instance.error(const UuidV4().generate(), 'Failed to fetch data: $e');
       rethrow;
     }
@@ -26,8 +26,10 @@ class WoxThemeUtil {
     return QUERY_BOX_BASE_HEIGHT + currentTheme.appPaddingTop + currentTheme.appPaddingBottom;
   }
 
-  double getResultItemHeight() {
-    return RESULT_ITEM_BASE_HEIGHT + currentTheme.resultItemPaddingTop + currentTheme.resultItemPaddingBottom;
+  /// [iconSize] is the icon size hint of the result, the item grows with icons larger than the default size
+  double getResultItemHeight({double iconSize = RESULT_ITEM_DEFAULT_ICON_SIZE}) {
+    final iconExtraHeight = iconSize > RESULT_ITEM_DEFAULT_ICON_SIZE ? iconSize - RESULT_ITEM_DEFAULT_ICON_SIZE : 0;
+    return RESULT_ITEM_BASE_HEIGHT + iconExtraHeight + currentTheme.resultItemPaddingTop + currentTheme.resultItemPaddingBottom;
   }
 
   double getToolbarHeight() {